@@ -0,0 +1,97 @@
+// File: backend/api/grpcserver/convert.go
+
+package grpcserver
+
+import (
+	"github.com/pranesh-j/subplexity/api/searchpb"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// fromProtoRequest converts a gRPC search request to the pipeline's form
+func fromProtoRequest(in *searchpb.SearchRequest) models.SearchRequest {
+	return models.SearchRequest{
+		Query:           in.GetQuery(),
+		SearchMode:      in.GetSearchMode(),
+		ModelName:       in.GetModelName(),
+		Limit:           int(in.GetLimit()),
+		Subreddits:      in.GetSubreddits(),
+		AnswerLanguage:  in.GetAnswerLanguage(),
+		AnswerFormat:    in.GetAnswerFormat(),
+		Verify:          in.GetVerify(),
+		SelfConsistency: in.GetSelfConsistency(),
+	}
+}
+
+// toProtoResponse converts a search response for gRPC clients
+func toProtoResponse(response *models.SearchResponse) *searchpb.SearchResponse {
+	out := &searchpb.SearchResponse{
+		Id:          response.ID,
+		Results:     toProtoResults(response.Results),
+		TotalCount:  int32(response.TotalCount),
+		Reasoning:   response.Reasoning,
+		Answer:      response.Answer,
+		Warnings:    response.Warnings,
+		ElapsedTime: response.ElapsedTime,
+		LastUpdated: response.LastUpdated,
+		Source:      response.Source,
+	}
+	for _, step := range response.ReasoningSteps {
+		out.ReasoningSteps = append(out.ReasoningSteps, &searchpb.ReasoningStep{
+			Title:   step.Title,
+			Content: step.Content,
+		})
+	}
+	for _, citation := range response.Citations {
+		out.Citations = append(out.Citations, &searchpb.Citation{
+			Index:     int32(citation.Index),
+			Text:      citation.Text,
+			Url:       citation.URL,
+			Title:     citation.Title,
+			Type:      citation.Type,
+			Subreddit: citation.Subreddit,
+			Archived:  citation.Archived,
+		})
+	}
+	if response.Consistency != nil {
+		out.Consistency = &searchpb.ConsistencyReport{
+			Samples:      int32(response.Consistency.Samples),
+			Agreement:    response.Consistency.Agreement,
+			Consensus:    response.Consistency.Consensus,
+			Alternatives: response.Consistency.Alternatives,
+		}
+	}
+	return out
+}
+
+// toProtoResults converts search results for gRPC clients
+func toProtoResults(results []models.SearchResult) []*searchpb.SearchResult {
+	out := make([]*searchpb.SearchResult, 0, len(results))
+	for _, result := range results {
+		out = append(out, &searchpb.SearchResult{
+			Id:            result.ID,
+			Type:          result.Type,
+			Title:         result.Title,
+			Subreddit:     result.Subreddit,
+			Author:        result.Author,
+			Content:       result.Content,
+			Url:           result.URL,
+			Permalink:     result.Permalink,
+			ExternalUrl:   result.ExternalURL,
+			CreatedUtc:    result.CreatedUTC,
+			EditedUtc:     result.EditedUTC,
+			Score:         int32(result.Score),
+			CommentCount:  int32(result.CommentCount),
+			UpvoteRatio:   result.UpvoteRatio,
+			Relevance:     result.Relevance,
+			Highlights:    result.Highlights,
+			Flair:         result.Flair,
+			AuthorFlair:   result.AuthorFlair,
+			Nsfw:          result.NSFW,
+			Distinguished: result.Distinguished,
+			Stickied:      result.Stickied,
+			Archived:      result.Archived,
+			Locked:        result.Locked,
+		})
+	}
+	return out
+}
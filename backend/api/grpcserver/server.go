@@ -0,0 +1,178 @@
+// File: backend/api/grpcserver/server.go
+
+// Package grpcserver serves the search pipeline over gRPC for other
+// services, as defined in api/searchpb/search.proto
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/api/searchpb"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// searchTimeout bounds each call, matching the HTTP API
+	searchTimeout = 60 * time.Second
+
+	// Comments given to the model when summarizing a thread
+	summaryComments = 30
+)
+
+// postIDPattern matches Reddit base-36 post IDs
+var postIDPattern = regexp.MustCompile(`^[a-z0-9]{1,12}$`)
+
+// Server implements searchpb.SearchServiceServer
+type Server struct {
+	searchpb.UnimplementedSearchServiceServer
+
+	pipeline      *services.SearchPipeline
+	redditService *services.RedditService
+	store         *store.Store
+}
+
+// New creates a gRPC search server. Responses are persisted to dataStore so
+// their IDs can be used with the HTTP export endpoints.
+func New(pipeline *services.SearchPipeline, redditService *services.RedditService, dataStore *store.Store) *Server {
+	return &Server{
+		pipeline:      pipeline,
+		redditService: redditService,
+		store:         dataStore,
+	}
+}
+
+// Register adds the search service to a gRPC server
+func (s *Server) Register(server *grpc.Server) {
+	searchpb.RegisterSearchServiceServer(server, s)
+}
+
+// Search runs a search and returns the answer with its sources
+func (s *Server) Search(ctx context.Context, in *searchpb.SearchRequest) (*searchpb.SearchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+
+	req := fromProtoRequest(in)
+	if err := s.pipeline.Validate(&req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := s.pipeline.Run(ctx, req)
+	if err != nil {
+		return nil, searchError(err)
+	}
+	s.saveSnapshot(ctx, response)
+	return toProtoResponse(response), nil
+}
+
+// StreamSearch runs a search, sending the results once they are retrieved
+// and the complete response once the answer is generated
+func (s *Server) StreamSearch(in *searchpb.SearchRequest, stream searchpb.SearchService_StreamSearchServer) error {
+	ctx, cancel := context.WithTimeout(stream.Context(), searchTimeout)
+	defer cancel()
+
+	req := fromProtoRequest(in)
+	if err := s.pipeline.Validate(&req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// A failed send means the client has gone; cancel so the answer isn't
+	// generated for nobody
+	var sendErr error
+	response, err := s.pipeline.RunStream(ctx, req, func(results []models.SearchResult, source string) {
+		sendErr = stream.Send(&searchpb.SearchEvent{
+			Event: &searchpb.SearchEvent_Results{
+				Results: &searchpb.SearchResults{Results: toProtoResults(results), Source: source},
+			},
+		})
+		if sendErr != nil {
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return searchError(err)
+	}
+
+	s.saveSnapshot(ctx, response)
+	return stream.Send(&searchpb.SearchEvent{
+		Event: &searchpb.SearchEvent_Response{Response: toProtoResponse(response)},
+	})
+}
+
+// Summarize summarizes a post and its top comments
+func (s *Server) Summarize(ctx context.Context, in *searchpb.SummarizeRequest) (*searchpb.SearchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, searchTimeout)
+	defer cancel()
+
+	postID := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(in.GetPostId())), "t3_")
+	if !postIDPattern.MatchString(postID) {
+		return nil, status.Error(codes.InvalidArgument, "invalid post ID")
+	}
+
+	thread, err := s.redditService.GetComments(ctx, postID, services.CommentOptions{
+		Sort:  "top",
+		Limit: 100,
+		Depth: 1,
+	})
+	if err != nil {
+		log.Printf("Failed to fetch comments for %s: %v", postID, err)
+		return nil, status.Errorf(codes.Unavailable, "failed to fetch the post from Reddit: %v", err)
+	}
+
+	results := services.ThreadResults(thread, summaryComments)
+	req := models.SearchRequest{
+		Query:          fmt.Sprintf("Summarize the discussion in the r/%s post \"%s\"", thread.Post.Subreddit, thread.Post.Title),
+		SearchMode:     "All",
+		ModelName:      in.GetModelName(),
+		Limit:          len(results),
+		AnswerLanguage: in.GetAnswerLanguage(),
+		AnswerFormat:   in.GetAnswerFormat(),
+	}
+	if err := s.pipeline.Validate(&req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	response, err := s.pipeline.RunWithResults(ctx, req, results, models.SourceReddit)
+	if err != nil {
+		return nil, searchError(err)
+	}
+	s.saveSnapshot(ctx, response)
+	return toProtoResponse(response), nil
+}
+
+// saveSnapshot persists a response, assigning its ID. Failures are logged
+// but don't fail the call.
+func (s *Server) saveSnapshot(ctx context.Context, response *models.SearchResponse) {
+	if _, err := s.store.SaveSnapshot(ctx, response); err != nil {
+		log.Printf("Failed to save search snapshot: %v", err)
+	}
+}
+
+// searchError maps a pipeline error to a gRPC status. Overload is
+// ResourceExhausted so clients back off and retry.
+func searchError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrOverloaded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		log.Printf("Search failed: %v", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,231 @@
+// File: backend/api/grpcserver/server_test.go
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/api/searchpb"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestServer returns a search server over a mock Reddit API with posts
+// about Go, answering with mock AI responses, and its snapshot store
+func newTestServer(t *testing.T) (*Server, *store.Store) {
+	t.Helper()
+	// Without provider keys every model answers with a mock response
+	for _, env := range []string{"ANTHROPIC_API_KEY", "GOOGLE_API_KEY", "OPENAI_API_KEY", "DEEPSEEK_API_KEY", "AI_MODE"} {
+		t.Setenv(env, "")
+	}
+
+	mock := redditmock.New()
+	t.Cleanup(mock.Close)
+	mock.AddPosts(redditmock.Post{ID: "go1", Subreddit: "golang", Title: "Go 1.22 released", Body: "Range over integers is here.", Author: "gopher", Score: 420})
+
+	reddit := services.NewRedditService("id", "secret")
+	reddit.SetHTTPClient(mock.Client())
+
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { dataStore.Close() })
+
+	return New(services.NewSearchPipeline(reddit, services.NewAIService()), reddit, dataStore), dataStore
+}
+
+// dialTestServer serves s over an in-memory connection and returns a client
+func dialTestServer(t *testing.T, s *Server) searchpb.SearchServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s.Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return searchpb.NewSearchServiceClient(conn)
+}
+
+func TestSearch(t *testing.T) {
+	s, dataStore := newTestServer(t)
+	client := dialTestServer(t, s)
+
+	response, err := client.Search(context.Background(), &searchpb.SearchRequest{
+		Query:          "go released",
+		SearchMode:     "Posts",
+		ModelName:      "Claude",
+		Limit:          5,
+		Subreddits:     []string{"golang"},
+		AnswerLanguage: "de",
+		AnswerFormat:   "bullets",
+		Verify:         true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Answer == "" || response.Source == "" || response.TotalCount != 1 || len(response.Results) != 1 {
+		t.Fatalf("Expected an answer and one result, got %+v", response)
+	}
+	if result := response.Results[0]; result.Id != "go1" || result.Subreddit != "golang" || result.Author != "gopher" || result.Score != 420 || result.Title != "Go 1.22 released" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	// The response was saved under its ID with the request as sent
+	if response.Id == "" {
+		t.Fatal("Expected a snapshot ID")
+	}
+	snapshot, err := dataStore.GetSnapshot(context.Background(), response.Id)
+	if err != nil {
+		t.Fatalf("Expected the snapshot to be saved: %v", err)
+	}
+	params := snapshot.RequestParams
+	if params.Query != "go released" || params.SearchMode != "Posts" || params.ModelName != "Claude" || params.Limit != 5 ||
+		strings.Join(params.Subreddits, ",") != "golang" || params.AnswerLanguage != "German" || params.AnswerFormat != "bullets" || !params.Verify {
+		t.Errorf("Expected the request's fields to reach the pipeline, got %+v", params)
+	}
+
+	if _, err := client.Search(context.Background(), &searchpb.SearchRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an empty query, got %v", err)
+	}
+}
+
+func TestStreamSearch(t *testing.T) {
+	s, _ := newTestServer(t)
+	client := dialTestServer(t, s)
+
+	stream, err := client.StreamSearch(context.Background(), &searchpb.SearchRequest{Query: "go released", SearchMode: "Posts"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var events []*searchpb.SearchEvent
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		events = append(events, event)
+	}
+
+	if len(events) != 2 || events[0].GetResults() == nil || events[1].GetResponse() == nil {
+		t.Fatalf("Expected the results, then the response, got %+v", events)
+	}
+	if results := events[0].GetResults().Results; len(results) != 1 || results[0].Id != "go1" {
+		t.Errorf("Unexpected streamed results %+v", results)
+	}
+	if response := events[1].GetResponse(); response.Answer == "" || response.Id == "" {
+		t.Errorf("Expected a saved answer, got %+v", response)
+	}
+}
+
+// failingStream is a StreamSearch stream whose client has gone away
+type failingStream struct {
+	grpc.ServerStream
+	sends int
+}
+
+func (s *failingStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *failingStream) Send(*searchpb.SearchEvent) error {
+	s.sends++
+	return io.ErrClosedPipe
+}
+
+func TestStreamSearchStopsAfterFailedSend(t *testing.T) {
+	s, _ := newTestServer(t)
+	stream := &failingStream{}
+
+	err := s.StreamSearch(&searchpb.SearchRequest{Query: "go released", SearchMode: "Posts"}, stream)
+	if !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Expected the send error, got %v", err)
+	}
+	if stream.sends != 1 {
+		t.Errorf("Expected nothing sent after the results failed, got %d sends", stream.sends)
+	}
+}
+
+func TestSummarizeRejectsMalformedIDs(t *testing.T) {
+	s, _ := newTestServer(t)
+	client := dialTestServer(t, s)
+
+	for _, id := range []string{"", "not a post!", "t3_", "../comments", strings.Repeat("a", 13)} {
+		_, err := client.Summarize(context.Background(), &searchpb.SummarizeRequest{PostId: id})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%q: expected InvalidArgument, got %v", id, err)
+		}
+	}
+}
+
+func TestSearchError(t *testing.T) {
+	testCases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{fmt.Errorf("search: %w", services.ErrOverloaded), codes.ResourceExhausted},
+		{fmt.Errorf("search: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{errors.New("boom"), codes.Internal},
+	}
+	for _, tc := range testCases {
+		if code := status.Code(searchError(tc.err)); code != tc.code {
+			t.Errorf("%v: expected %s, got %s", tc.err, tc.code, code)
+		}
+	}
+}
+
+func TestToProtoResponse(t *testing.T) {
+	response := toProtoResponse(&models.SearchResponse{
+		ID:             "snap1",
+		Answer:         "Use Go [1].",
+		ReasoningSteps: []models.ReasoningStep{{Title: "Compare", Content: "Go came up most."}},
+		Citations:      []models.Citation{{Index: 1, URL: "https://reddit.com/go1", Subreddit: "golang", Archived: true}},
+		Consistency:    &models.ConsistencyReport{Samples: 3, Agreement: 0.67, Alternatives: []string{"Use Rust."}},
+		Results:        []models.SearchResult{{ID: "go1", ExternalURL: "https://go.dev", EditedUTC: 1700000000, UpvoteRatio: 0.9, NSFW: true, Locked: true}},
+		TotalCount:     1,
+	})
+
+	if response.Id != "snap1" || response.TotalCount != 1 || len(response.ReasoningSteps) != 1 || response.ReasoningSteps[0].Content != "Go came up most." {
+		t.Errorf("Unexpected response %+v", response)
+	}
+	if citation := response.Citations[0]; citation.Index != 1 || citation.Url != "https://reddit.com/go1" || !citation.Archived {
+		t.Errorf("Unexpected citation %+v", citation)
+	}
+	if consistency := response.Consistency; consistency.Samples != 3 || consistency.Agreement != 0.67 || consistency.Alternatives[0] != "Use Rust." {
+		t.Errorf("Unexpected consistency report %+v", consistency)
+	}
+	if result := response.Results[0]; result.ExternalUrl != "https://go.dev" || result.EditedUtc != 1700000000 || result.UpvoteRatio != 0.9 || !result.Nsfw || !result.Locked {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	if empty := toProtoResponse(&models.SearchResponse{}); empty.Consistency != nil || empty.Results == nil {
+		t.Errorf("Expected no consistency report and an empty result list, got %+v", empty)
+	}
+}
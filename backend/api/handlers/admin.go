@@ -0,0 +1,176 @@
+// File: backend/api/handlers/admin.go
+
+package handlers
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// AdminHandler serves operational endpoints. Routes using it must be
+// protected by middleware.RequireAdmin.
+type AdminHandler struct {
+	AIService        *services.AIService
+	RedditService    *services.RedditService
+	CacheInvalidator *cache.Invalidator
+}
+
+// NewAdminHandler creates a new admin handler. invalidator flushes the Reddit
+// service's caches on every replica.
+func NewAdminHandler(aiService *services.AIService, redditService *services.RedditService, invalidator *cache.Invalidator) *AdminHandler {
+	return &AdminHandler{
+		AIService:        aiService,
+		RedditService:    redditService,
+		CacheInvalidator: invalidator,
+	}
+}
+
+// adminOperations documents the admin routes for /api/openapi.json
+var adminOperations = []openapi.Operation{
+	{
+		Method:    "POST",
+		Path:      "/api/admin/templates/reload",
+		Tag:       "Admin",
+		Summary:   "Reload prompt templates from disk",
+		Admin:     true,
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: map[string]string{}}},
+	},
+	{
+		Method:  "GET",
+		Path:    "/api/admin/cache/stats",
+		Tag:     "Admin",
+		Summary: "Report Reddit cache statistics",
+		Admin:   true,
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Caches map[string]cache.Stats `json:"caches"`
+			}{}},
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/admin/cache/flush",
+		Tag:         "Admin",
+		Summary:     "Flush Reddit caches on every replica",
+		Description: "Flushes the named caches, or all of them when none are named.",
+		Admin:       true,
+		Request:     flushCacheRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Flushed     []string `json:"flushed"`
+				Distributed bool     `json:"distributed"`
+			}{}},
+			errorResponse(http.StatusBadRequest, "Unknown cache"),
+			errorResponse(http.StatusBadGateway, "Flushed locally but failed to notify other replicas"),
+		},
+	},
+	{
+		Method:    "GET",
+		Path:      "/api/admin/reddit/concurrency",
+		Tag:       "Admin",
+		Summary:   "Report the adaptive Reddit concurrency limit",
+		Admin:     true,
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: models.ConcurrencyStatus{}}},
+	},
+	{
+		Method:  "PUT",
+		Path:    "/api/admin/reddit/concurrency",
+		Tag:     "Admin",
+		Summary: "Change the bounds of the Reddit concurrency limit until restart",
+		Admin:   true,
+		Request: models.ConcurrencySettings{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.ConcurrencyStatus{}},
+			errorResponse(http.StatusBadRequest, "Invalid settings"),
+		},
+	},
+}
+
+// HandleReloadTemplates re-reads prompt templates from disk
+func (h *AdminHandler) HandleReloadTemplates(c *gin.Context) {
+	if err := h.AIService.ReloadPromptTemplates(); err != nil {
+		log.Printf("Failed to reload prompt templates: %v", err)
+		writeError(c, http.StatusUnprocessableEntity, models.ErrorCodeInternal, "Failed to reload prompt templates; previous templates are still in use", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "reloaded"})
+}
+
+// HandleCacheStats reports hit, miss, eviction and expiry counts for the
+// Reddit caches, to guide TTL and size tuning
+func (h *AdminHandler) HandleCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"caches": h.RedditService.CacheStats(),
+	})
+}
+
+// flushCacheRequest optionally names the caches to flush
+type flushCacheRequest struct {
+	Caches []string `json:"caches"`
+}
+
+// HandleFlushCache clears the named caches, or all of them, on every replica
+func (h *AdminHandler) HandleFlushCache(c *gin.Context) {
+	var req flushCacheRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	known := h.RedditService.CacheStats()
+	for _, name := range req.Caches {
+		if _, ok := known[name]; !ok {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Unknown cache", name)
+			return
+		}
+	}
+
+	flushed, err := h.CacheInvalidator.Flush(c.Request.Context(), req.Caches)
+	if err != nil {
+		// This replica is already flushed; the others may still serve stale entries
+		log.Printf("Failed to broadcast cache flush: %v", err)
+		c.JSON(http.StatusBadGateway, struct {
+			models.ErrorResponse
+			Flushed []string `json:"flushed"`
+		}{
+			newErrorBody(c, models.ErrorCodeUpstream, "Flushed locally but failed to notify other replicas", err.Error()),
+			flushed,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flushed":     flushed,
+		"distributed": h.CacheInvalidator.Distributed(),
+	})
+}
+
+// HandleGetConcurrency reports the adaptive limit on concurrent Reddit requests
+func (h *AdminHandler) HandleGetConcurrency(c *gin.Context) {
+	c.JSON(http.StatusOK, h.RedditService.ConcurrencyStatus())
+}
+
+// HandleUpdateConcurrency changes the bounds of the Reddit concurrency limit.
+// The change lasts until restart.
+func (h *AdminHandler) HandleUpdateConcurrency(c *gin.Context) {
+	var settings models.ConcurrencySettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid request format", err.Error())
+		return
+	}
+
+	if err := h.RedditService.UpdateConcurrency(settings); err != nil {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid concurrency settings", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, h.RedditService.ConcurrencyStatus())
+}
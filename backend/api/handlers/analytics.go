@@ -0,0 +1,86 @@
+// File: backend/api/handlers/analytics.go
+
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	defaultAnalyticsDays = 7
+	maxAnalyticsDays     = 90
+	defaultAnalyticsTop  = 10
+	maxAnalyticsTop      = 100
+)
+
+// AnalyticsHandler reports search traffic across all clients. Routes using
+// it must be protected by middleware.RequireAdmin.
+type AnalyticsHandler struct {
+	Store *store.Store
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(dataStore *store.Store) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		Store: dataStore,
+	}
+}
+
+// analyticsOperations documents the analytics routes for /api/openapi.json
+var analyticsOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/admin/analytics",
+		Tag:         "Admin",
+		Summary:     "Report search traffic",
+		Description: "Summarizes searches by UTC day, from the search history of all clients: counts, latency percentiles, Reddit cache hit ratios, the models used, and the most searched queries and subreddits.",
+		Admin:       true,
+		Parameters: []openapi.Parameter{
+			{Name: "days", In: "query", Type: "integer", Description: fmt.Sprintf("Number of days to cover, including today, default %d, at most %d", defaultAnalyticsDays, maxAnalyticsDays)},
+			{Name: "top", In: "query", Type: "integer", Description: fmt.Sprintf("Number of top queries and subreddits, default %d, at most %d", defaultAnalyticsTop, maxAnalyticsTop)},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Analytics{}},
+			errorResponse(http.StatusBadRequest, "Invalid days or top"),
+		},
+	},
+}
+
+// HandleGet summarizes the searches of the last days, selected with the days
+// query parameter
+func (h *AnalyticsHandler) HandleGet(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(defaultAnalyticsDays)))
+	if err != nil || days <= 0 || days > maxAnalyticsDays {
+		invalidField(c, "days", fmt.Sprintf("must be an integer between 1 and %d", maxAnalyticsDays))
+		return
+	}
+
+	top, err := strconv.Atoi(c.DefaultQuery("top", strconv.Itoa(defaultAnalyticsTop)))
+	if err != nil || top <= 0 {
+		invalidField(c, "top", "must be a positive integer")
+		return
+	}
+	if top > maxAnalyticsTop {
+		top = maxAnalyticsTop
+	}
+
+	now := time.Now().UTC()
+	since := now.Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	analytics, err := h.Store.Analytics(c.Request.Context(), since, now, top)
+	if err != nil {
+		log.Printf("Failed to load analytics: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load analytics", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
@@ -0,0 +1,159 @@
+// File: backend/api/handlers/annotations.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// maxAnnotationLength caps notes on results, in characters
+const maxAnnotationLength = 2000
+
+// AnnotationHandler keeps the caller's private notes on search results
+type AnnotationHandler struct {
+	Store *store.Store
+}
+
+// NewAnnotationHandler creates a new annotation handler
+func NewAnnotationHandler(dataStore *store.Store) *AnnotationHandler {
+	return &AnnotationHandler{
+		Store: dataStore,
+	}
+}
+
+// annotationOperations documents the annotation routes for
+// /api/openapi.json. Notes belong to the caller and are included in their
+// Markdown and HTML exports.
+var annotationOperations = []openapi.Operation{
+	{
+		Method:     "GET",
+		Path:       "/api/search/{id}/annotations",
+		Tag:        "Annotations",
+		Summary:    "List your notes on a search's results",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path", Description: "Snapshot ID from SearchResponse.id"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Annotations []models.Annotation `json:"annotations"`
+			}{}},
+		},
+	},
+	{
+		Method:  "PUT",
+		Path:    "/api/search/{id}/annotations/{resultId}",
+		Tag:     "Annotations",
+		Summary: "Set your note on a result",
+		Parameters: []openapi.Parameter{
+			{Name: "id", In: "path", Description: "Snapshot ID from SearchResponse.id"},
+			{Name: "resultId", In: "path"},
+		},
+		Request: models.AnnotationRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Annotation{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Search or result not found"),
+		},
+	},
+	{
+		Method:  "DELETE",
+		Path:    "/api/search/{id}/annotations/{resultId}",
+		Tag:     "Annotations",
+		Summary: "Delete your note on a result",
+		Parameters: []openapi.Parameter{
+			{Name: "id", In: "path", Description: "Snapshot ID from SearchResponse.id"},
+			{Name: "resultId", In: "path"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			errorResponse(http.StatusNotFound, "Note not found"),
+		},
+	},
+}
+
+// HandleList returns the caller's notes on the results of the search :id
+func (h *AnnotationHandler) HandleList(c *gin.Context) {
+	id := c.Param("id")
+
+	annotations, err := h.Store.ListAnnotations(c.Request.Context(), clientKey(c), id)
+	if err != nil {
+		log.Printf("Failed to list annotations for %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to list notes", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"annotations": annotations})
+}
+
+// HandleSet creates or replaces the caller's note on the result :resultId
+// of the search :id
+func (h *AnnotationHandler) HandleSet(c *gin.Context) {
+	id := c.Param("id")
+	resultID := c.Param("resultId")
+
+	var req models.AnnotationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		invalidField(c, "note", "cannot be empty; delete the note instead")
+		return
+	}
+	if length := utf8.RuneCountInString(note); length > maxAnnotationLength {
+		invalidField(c, "note", fmt.Sprintf("must be at most %d characters, got %d", maxAnnotationLength, length))
+		return
+	}
+
+	snapshot, err := h.Store.GetSnapshot(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load snapshot %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search", err.Error())
+		return
+	}
+	if _, ok := findResult(snapshot.Results, resultID); !ok {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Result not found in this search", "")
+		return
+	}
+
+	annotation := &models.Annotation{SnapshotID: id, ResultID: resultID, Note: note}
+	if err := h.Store.SetAnnotation(c.Request.Context(), clientKey(c), annotation); err != nil {
+		log.Printf("Failed to save annotation on %s/%s: %v", id, resultID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to save note", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, annotation)
+}
+
+// HandleDelete removes the caller's note on the result :resultId of the
+// search :id
+func (h *AnnotationHandler) HandleDelete(c *gin.Context) {
+	id := c.Param("id")
+	resultID := c.Param("resultId")
+
+	err := h.Store.DeleteAnnotation(c.Request.Context(), clientKey(c), id, resultID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Note not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete annotation on %s/%s: %v", id, resultID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to delete note", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+// File: backend/api/handlers/answers.go
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	// deferredAnswerTimeout bounds a search whose answer is generated after
+	// its results were returned, matching the time HandleSearch allows
+	deferredAnswerTimeout = 60 * time.Second
+	// pendingAnswerRetention is how long a generated answer can be fetched
+	pendingAnswerRetention = 10 * time.Minute
+	// answerKeepAlive is how often an event stream waiting for an answer
+	// sends a comment, so proxies don't close it as idle
+	answerKeepAlive = 15 * time.Second
+)
+
+// answerOperations documents the deferred answer route for
+// /api/openapi.json
+var answerOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/search/answers/{id}",
+		Tag:         "Search",
+		Summary:     "Fetch the answer to a search that returned its results first",
+		Description: "Waits for the answer to a POST /api/search with deferAnswer and returns the full response, as saved to the snapshot. With \"Accept: text/event-stream\" the response is an event stream instead, sending an answer event with the response or an error event with the error body. Answers can be fetched for 10 minutes.",
+		Parameters:  []openapi.Parameter{{Name: "id", In: "path", Description: "pendingAnswerId from the search's results"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SearchResponse{}},
+			errorResponse(http.StatusNotFound, "Unknown or expired answer"),
+			errorResponse(http.StatusBadGateway, "The AI provider failed; see code"),
+			errorResponse(http.StatusServiceUnavailable, "Server is busy or rate limited upstream"),
+			errorResponse(http.StatusGatewayTimeout, "Search timed out"),
+			errorResponse(http.StatusInternalServerError, "Search failed"),
+		},
+	},
+}
+
+// deferAnswer runs a search in the background and responds with its results
+// as soon as they are retrieved, leaving the answer to HandleAnswer. A
+// search that fails before then responds with the error, and one that
+// finishes first with the full response. The answer outlives the request,
+// which is detached so the answer keeps the client's concurrency slot and
+// its tokens count against the client's quota.
+func (h *SearchHandler) deferAnswer(c *gin.Context, req models.SearchRequest) {
+	startTime := time.Now()
+	id := h.Answers.Start()
+	owner := clientKey(c)
+	first := make(chan *models.SearchResponse, 1)
+
+	// Keep the request's values, such as the signed-in user, but not its
+	// cancellation
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), deferredAnswerTimeout)
+	done := middleware.Detach(c)
+	go func() {
+		defer done()
+		defer cancel()
+		response, err := h.runSearchStream(ctx, owner, req, func(results []models.SearchResult, source string) {
+			first <- &models.SearchResponse{
+				Results:         append([]models.SearchResult(nil), results...),
+				TotalCount:      len(results),
+				ElapsedTime:     time.Since(startTime).Seconds(),
+				Source:          source,
+				PendingAnswerID: id,
+			}
+		})
+		if err != nil {
+			log.Printf("Deferred search for '%s' failed: %v", req.Query, err)
+		}
+		h.Answers.Finish(id, response, err)
+	}()
+
+	answer, _ := h.Answers.Get(id)
+	select {
+	case response := <-first:
+		c.JSON(http.StatusOK, response)
+	case <-answer.Done():
+		response, err := answer.Result()
+		if err != nil {
+			writeServiceError(c, "Search failed", err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// HandleAnswer waits for the answer to a search with deferAnswer, responding
+// with the full response as JSON or, when the client accepts it, as an
+// event stream
+func (h *SearchHandler) HandleAnswer(c *gin.Context) {
+	answer, ok := h.Answers.Get(c.Param("id"))
+	if !ok {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Answer not found", "The answer is unknown or has expired")
+		return
+	}
+
+	if !strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		select {
+		case <-answer.Done():
+		case <-c.Request.Context().Done():
+			return
+		}
+		response, err := answer.Result()
+		if err != nil {
+			writeServiceError(c, "Search failed", err)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	keepAlive := time.NewTicker(answerKeepAlive)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-answer.Done():
+		case <-keepAlive.C:
+			io.WriteString(w, ": waiting for the answer\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+		if response, err := answer.Result(); err != nil {
+			c.SSEvent("error", newErrorBody(c, services.ErrorCode(err), "Search failed", err.Error()))
+		} else {
+			c.SSEvent("answer", response)
+		}
+		return false
+	})
+}
@@ -0,0 +1,203 @@
+// File: backend/api/handlers/auth.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// maxEmailLength is the longest email address accepted
+const maxEmailLength = 254
+
+// AuthHandler registers accounts and signs them in
+type AuthHandler struct {
+	Store    *store.Store
+	Sessions *auth.Sessions
+	cfg      config.AccountsConfig
+}
+
+// NewAuthHandler creates an account handler. Without sessions, accounts are
+// disabled and its routes respond with 404.
+func NewAuthHandler(dataStore *store.Store, sessions *auth.Sessions, cfg config.AccountsConfig) *AuthHandler {
+	return &AuthHandler{
+		Store:    dataStore,
+		Sessions: sessions,
+		cfg:      cfg,
+	}
+}
+
+// authOperations documents the account routes for /api/openapi.json
+var authOperations = []openapi.Operation{
+	{
+		Method:      "POST",
+		Path:        "/api/auth/register",
+		Tag:         "Accounts",
+		Summary:     "Create an account",
+		Description: "Registers an account and signs it in. Send the returned token as \"Authorization: Bearer <token>\".",
+		Request:     models.Credentials{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.Session{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Accounts are disabled"),
+			errorResponse(http.StatusConflict, "An account with this email already exists"),
+		},
+	},
+	{
+		Method:  "POST",
+		Path:    "/api/auth/login",
+		Tag:     "Accounts",
+		Summary: "Sign in",
+		Request: models.Credentials{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Session{}},
+			errorResponse(http.StatusUnauthorized, "Wrong email or password"),
+			errorResponse(http.StatusNotFound, "Accounts are disabled"),
+		},
+	},
+	{
+		Method:  "GET",
+		Path:    "/api/auth/me",
+		Tag:     "Accounts",
+		Summary: "Get the signed-in account",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Account{}},
+			errorResponse(http.StatusUnauthorized, "Not signed in, or the session expired"),
+			errorResponse(http.StatusNotFound, "Accounts are disabled"),
+		},
+	},
+}
+
+// HandleRegister creates an account and signs it in
+func (h *AuthHandler) HandleRegister(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	var req models.Credentials
+	if !bindJSON(c, &req) {
+		return
+	}
+	email := strings.TrimSpace(req.Email)
+	if err := h.validateCredentials(email, req.Password); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create account", err.Error())
+		return
+	}
+	account := &models.Account{Email: email}
+	if err := h.Store.CreateAccount(c.Request.Context(), account, hash); err != nil {
+		if errors.Is(err, store.ErrExists) {
+			writeError(c, http.StatusConflict, models.ErrorCodeConflict, "An account with this email already exists", "")
+			return
+		}
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create account", err.Error())
+		return
+	}
+
+	h.writeSession(c, http.StatusCreated, account)
+}
+
+// HandleLogin signs in with an email and password
+func (h *AuthHandler) HandleLogin(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	var req models.Credentials
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	account, hash, err := h.Store.AccountByEmail(c.Request.Context(), strings.TrimSpace(req.Email))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to sign in", err.Error())
+		return
+	}
+	// Unknown emails take as long as wrong passwords and get the same
+	// response, so logins can't be used to find accounts
+	if !auth.CheckPassword(hash, req.Password) {
+		writeError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Wrong email or password", "")
+		return
+	}
+
+	h.writeSession(c, http.StatusOK, account)
+}
+
+// HandleMe returns the signed-in account
+func (h *AuthHandler) HandleMe(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	accountID := middleware.AccountID(c)
+	if accountID == "" {
+		writeError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Not signed in", "")
+		return
+	}
+
+	account, err := h.Store.Account(c.Request.Context(), accountID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Account no longer exists", "")
+		return
+	}
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load account", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// enabled responds with 404 when accounts are disabled
+func (h *AuthHandler) enabled(c *gin.Context) bool {
+	if h.Sessions == nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeDisabled, "Accounts are not enabled", "")
+		return false
+	}
+	return true
+}
+
+// validateCredentials checks a new account's email and password
+func (h *AuthHandler) validateCredentials(email, password string) error {
+	invalid := &services.ValidationError{}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email || len(email) > maxEmailLength {
+		invalid.Fields = append(invalid.Fields, models.FieldError{Field: "email", Message: "must be a valid email address"})
+	}
+	if len(password) < h.cfg.MinPasswordLength || len(password) > auth.MaxPasswordLength {
+		invalid.Fields = append(invalid.Fields, models.FieldError{
+			Field:   "password",
+			Message: fmt.Sprintf("must be between %d and %d characters", h.cfg.MinPasswordLength, auth.MaxPasswordLength),
+		})
+	}
+	if len(invalid.Fields) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// writeSession signs account in and responds with its session
+func (h *AuthHandler) writeSession(c *gin.Context, status int, account *models.Account) {
+	token, expires, err := h.Sessions.Issue(account.ID)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to sign in", err.Error())
+		return
+	}
+	c.JSON(status, models.Session{Token: token, ExpiresAt: expires.Unix(), Account: *account})
+}
@@ -0,0 +1,142 @@
+// File: backend/api/handlers/batch.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	// maxBatchQueries caps how many queries a single batch may contain
+	maxBatchQueries = 10
+	// batchConcurrency is how many pipelines run at the same time per batch
+	batchConcurrency = 3
+)
+
+// batchTimeBudget is the total time shared by all queries in a batch; a
+// variable so tests can shorten it
+var batchTimeBudget = 120 * time.Second
+
+// HandleBatchSearch runs several search pipelines concurrently under a shared
+// time budget and returns the outcome of each query. Each query counts
+// against the caller's quota, and each running query holds one of the
+// caller's concurrency slots, as a search of its own would.
+func (h *SearchHandler) HandleBatchSearch(c *gin.Context) {
+	var req models.BatchSearchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Validate request, reporting the invalid fields of every query
+	invalid := &services.ValidationError{}
+	if len(req.Queries) == 0 {
+		invalid.Fields = append(invalid.Fields, models.FieldError{Field: "queries", Message: "batch must contain at least one query"})
+	}
+	if len(req.Queries) > maxBatchQueries {
+		invalid.Fields = append(invalid.Fields, models.FieldError{
+			Field:   "queries",
+			Message: fmt.Sprintf("batch cannot contain more than %d queries", maxBatchQueries),
+		})
+	}
+	for i := range req.Queries {
+		var queryInvalid *services.ValidationError
+		if err := h.Pipeline.Validate(&req.Queries[i]); errors.As(err, &queryInvalid) {
+			for _, field := range queryInvalid.Fields {
+				field.Field = fmt.Sprintf("queries[%d].%s", i, field.Field)
+				invalid.Fields = append(invalid.Fields, field)
+			}
+		}
+		if req.Queries[i].Locale == "" {
+			req.Queries[i].Locale = requestLocale(c)
+		}
+	}
+	if len(invalid.Fields) > 0 {
+		writeValidationError(c, invalid)
+		return
+	}
+
+	// The request itself was counted as one search
+	if !middleware.ChargeRequests(c, int64(len(req.Queries)-1)) {
+		return
+	}
+
+	// The request's own slot runs one query; more run alongside it in
+	// whichever of the caller's slots are free
+	workers := batchConcurrency
+	if len(req.Queries) < workers {
+		workers = len(req.Queries)
+	}
+	extra, releaseSlots := middleware.TryClientSlots(c, workers-1)
+	defer releaseSlots()
+	workers = 1 + extra
+
+	log.Printf("Batch search request with %d queries, %d at a time", len(req.Queries), workers)
+
+	// All queries share a single deadline
+	ctx, cancel := context.WithTimeout(c.Request.Context(), batchTimeBudget)
+	defer cancel()
+
+	owner := clientKey(c)
+	startTime := time.Now()
+	items := make([]models.BatchSearchItem, len(req.Queries))
+	queue := make(chan int, len(req.Queries))
+	for i, query := range req.Queries {
+		items[i] = models.BatchSearchItem{Index: i, Query: query.Query}
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				// Give up on queued queries once the budget is spent
+				if ctx.Err() != nil {
+					items[i].Error = "batch time budget exhausted before query could run"
+					continue
+				}
+
+				response, err := h.runSearch(ctx, owner, req.Queries[i])
+				if err != nil {
+					log.Printf("Batch query %d failed: %v", i, err)
+					items[i].Error = err.Error()
+					continue
+				}
+				items[i].Response = response
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Summarize the batch outcome
+	response := models.BatchSearchResponse{
+		Results:     items,
+		ElapsedTime: time.Since(startTime).Seconds(),
+	}
+	for _, item := range items {
+		if item.Error != "" {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	log.Printf("Batch search completed in %.2f seconds: %d succeeded, %d failed",
+		response.ElapsedTime, response.Succeeded, response.Failed)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,165 @@
+// File: backend/api/handlers/batch_test.go
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/quota"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+// newTestSearchHandler returns a search handler without an AI service,
+// searching a mock Reddit API with posts about Go and Rust
+func newTestSearchHandler(t *testing.T) (*SearchHandler, *redditmock.Server) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mock := redditmock.New()
+	t.Cleanup(mock.Close)
+	mock.AddPosts(
+		redditmock.Post{ID: "go1", Subreddit: "golang", Title: "Go 1.22 released", Author: "gopher", Score: 420},
+		redditmock.Post{ID: "rs1", Subreddit: "rust", Title: "Rust 1.76 released", Author: "crab", Score: 310},
+	)
+
+	reddit := services.NewRedditService("id", "secret")
+	reddit.SetHTTPClient(mock.Client())
+
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { dataStore.Close() })
+
+	return NewSearchHandler(reddit, nil, dataStore), mock
+}
+
+// postBatch sends queries to r's batch route
+func postBatch(r http.Handler, queries ...models.SearchRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(models.BatchSearchRequest{Queries: queries})
+	req := httptest.NewRequest(http.MethodPost, "/api/search/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandleBatchSearch(t *testing.T) {
+	handler, _ := newTestSearchHandler(t)
+	r := gin.New()
+	r.POST("/api/search/batch", handler.HandleBatchSearch)
+
+	// A query that fails doesn't fail the others
+	rec := postBatch(r,
+		models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true},
+		models.SearchRequest{Query: "rust released", SearchMode: "Posts", SkipAI: true, RedditScope: models.RedditScopeSubscriptions},
+	)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var response models.BatchSearchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if response.Succeeded != 1 || response.Failed != 1 || len(response.Results) != 2 {
+		t.Fatalf("Expected one success and one failure, got %+v", response)
+	}
+	if first := response.Results[0]; first.Error != "" || first.Response == nil || len(first.Response.Results) == 0 || first.Response.Results[0].ID != "go1" {
+		t.Errorf("Expected the first query to find go1, got %+v", first)
+	}
+	if second := response.Results[1]; second.Index != 1 || second.Response != nil || second.Error == "" {
+		t.Errorf("Expected the second query to report its error, got %+v", second)
+	}
+}
+
+func TestHandleBatchSearchQueryCount(t *testing.T) {
+	handler, mock := newTestSearchHandler(t)
+	r := gin.New()
+	r.POST("/api/search/batch", handler.HandleBatchSearch)
+
+	tooMany := make([]models.SearchRequest, maxBatchQueries+1)
+	for i := range tooMany {
+		tooMany[i] = models.SearchRequest{Query: "go", SkipAI: true}
+	}
+	for _, queries := range [][]models.SearchRequest{nil, tooMany} {
+		rec := postBatch(r, queries...)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%d queries: expected 400, got %d", len(queries), rec.Code)
+			continue
+		}
+		var body models.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		if len(body.Fields) == 0 || body.Fields[0].Field != "queries" {
+			t.Errorf("%d queries: expected a queries field error, got %s", len(queries), rec.Body)
+		}
+	}
+	if requests := mock.Requests(); len(requests) != 0 {
+		t.Errorf("Expected refused batches not to search, got %d Reddit requests", len(requests))
+	}
+}
+
+func TestHandleBatchSearchTimeBudget(t *testing.T) {
+	budget := batchTimeBudget
+	batchTimeBudget = 50 * time.Millisecond
+	t.Cleanup(func() { batchTimeBudget = budget })
+
+	handler, mock := newTestSearchHandler(t)
+	mock.Fail(redditmock.Failure{Path: "/search.json", Delay: 300 * time.Millisecond})
+	r := gin.New()
+	r.POST("/api/search/batch", handler.HandleBatchSearch)
+
+	queries := make([]models.SearchRequest, batchConcurrency+1)
+	for i := range queries {
+		queries[i] = models.SearchRequest{Query: "slow query " + strings.Repeat("x", i+1), SearchMode: "Posts", SkipAI: true}
+	}
+	start := time.Now()
+	rec := postBatch(r, queries...)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the batch to stop at its time budget, took %s", elapsed)
+	}
+
+	var response models.BatchSearchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if response.Succeeded != 0 || response.Failed != len(queries) {
+		t.Fatalf("Expected every query to run out of time, got %+v", response)
+	}
+	if last := response.Results[len(queries)-1]; !strings.Contains(last.Error, "budget exhausted") {
+		t.Errorf("Expected the queued query not to run, got %q", last.Error)
+	}
+}
+
+func TestHandleBatchSearchQuota(t *testing.T) {
+	handler, _ := newTestSearchHandler(t)
+	limiter := quota.New(handler.Store, config.QuotasConfig{Enabled: true, DailyRequests: 3})
+	r := gin.New()
+	r.POST("/api/search/batch", middleware.Quotas(limiter), handler.HandleBatchSearch)
+
+	query := models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true}
+	if rec := postBatch(r, query, query, query, query); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected a 4-query batch to go over a quota of 3, got %d", rec.Code)
+	}
+
+	// Only the refused batch's own request was counted
+	rec := postBatch(r, query, query)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected a 2-query batch within quota, got %d: %s", rec.Code, rec.Body)
+	}
+	if remaining := rec.Header().Get("X-Quota-Remaining-Requests-Day"); remaining != "0" {
+		t.Errorf("Expected each query to be counted, got %q requests remaining", remaining)
+	}
+}
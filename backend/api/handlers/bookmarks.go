@@ -0,0 +1,381 @@
+// File: backend/api/handlers/bookmarks.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	defaultBookmarkPageSize = 20
+	maxBookmarkPageSize     = 100
+	maxBookmarkTags         = 10
+	maxBookmarkNoteLength   = 2000
+)
+
+// bookmarkTagPattern accepts short tags of letters, digits, spaces, dashes
+// and underscores, starting with a letter or digit
+var bookmarkTagPattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _-]{0,31}$`)
+
+// BookmarkHandler keeps the results and answers clients bookmark
+type BookmarkHandler struct {
+	Store *store.Store
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(dataStore *store.Store) *BookmarkHandler {
+	return &BookmarkHandler{
+		Store: dataStore,
+	}
+}
+
+// bookmarkOperations documents the bookmark routes for /api/openapi.json.
+// Bookmarks belong to the caller.
+var bookmarkOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/bookmarks",
+		Tag:         "Bookmarks",
+		Summary:     "List bookmarks, newest first",
+		Description: "Filters combine: bookmarks must have every tag given and match every word of q, as a prefix, in their content, note or tags.",
+		Parameters: []openapi.Parameter{
+			{Name: "tag", In: "query", Description: "Only bookmarks with this tag; repeat for several"},
+			{Name: "q", In: "query", Description: "Full-text filter"},
+			{Name: "kind", In: "query", Description: "\"result\" or \"answer\""},
+			{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Page size, default %d, at most %d", defaultBookmarkPageSize, maxBookmarkPageSize)},
+			{Name: "offset", In: "query", Type: "integer"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.BookmarkPage{}},
+			validationErrorResponse,
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/bookmarks",
+		Tag:         "Bookmarks",
+		Summary:     "Bookmark a result or answer",
+		Description: "Bookmarks one result of a search, or its whole answer when resultId is empty. The content is copied from the search, so the bookmark outlives it.",
+		Request:     models.BookmarkRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.Bookmark{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Search or result not found"),
+			errorResponse(http.StatusConflict, "Already bookmarked"),
+		},
+	},
+	{
+		Method:  "GET",
+		Path:    "/api/bookmarks/tags",
+		Tag:     "Bookmarks",
+		Summary: "List bookmark tags, most used first",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Tags []models.TagCount `json:"tags"`
+			}{}},
+		},
+	},
+	{
+		Method:     "GET",
+		Path:       "/api/bookmarks/{id}",
+		Tag:        "Bookmarks",
+		Summary:    "Get a bookmark",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Bookmark{}},
+			errorResponse(http.StatusNotFound, "Bookmark not found"),
+		},
+	},
+	{
+		Method:     "PUT",
+		Path:       "/api/bookmarks/{id}",
+		Tag:        "Bookmarks",
+		Summary:    "Update a bookmark's note and tags",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Request:    models.BookmarkUpdate{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Bookmark{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Bookmark not found"),
+		},
+	},
+	{
+		Method:     "DELETE",
+		Path:       "/api/bookmarks/{id}",
+		Tag:        "Bookmarks",
+		Summary:    "Delete a bookmark",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			errorResponse(http.StatusNotFound, "Bookmark not found"),
+		},
+	},
+}
+
+// HandleList returns one page of the caller's bookmarks matching the tag,
+// q and kind query parameters
+func (h *BookmarkHandler) HandleList(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultBookmarkPageSize)))
+	if err != nil || limit <= 0 {
+		invalidField(c, "limit", "must be a positive integer")
+		return
+	}
+	if limit > maxBookmarkPageSize {
+		limit = maxBookmarkPageSize
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		invalidField(c, "offset", "must be a non-negative integer")
+		return
+	}
+
+	filter := models.BookmarkFilter{
+		Kind: strings.ToLower(strings.TrimSpace(c.Query("kind"))),
+		Text: strings.TrimSpace(c.Query("q")),
+	}
+	if filter.Kind != "" && filter.Kind != models.BookmarkKindResult && filter.Kind != models.BookmarkKindAnswer {
+		invalidField(c, "kind", fmt.Sprintf("must be %s or %s", models.BookmarkKindResult, models.BookmarkKindAnswer))
+		return
+	}
+	if filter.Tags, err = normalizeTags(c.QueryArray("tag")); err != nil {
+		writeValidationError(c, err)
+		return
+	}
+
+	bookmarks, total, err := h.Store.ListBookmarks(c.Request.Context(), clientKey(c), filter, limit, offset)
+	if err != nil {
+		log.Printf("Failed to list bookmarks: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to list bookmarks", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.BookmarkPage{
+		Bookmarks: bookmarks,
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
+// HandleCreate bookmarks a result or answer from a search snapshot
+func (h *BookmarkHandler) HandleCreate(c *gin.Context) {
+	var req models.BookmarkRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	req.SnapshotID = strings.TrimSpace(req.SnapshotID)
+	invalid := &services.ValidationError{}
+	if req.SnapshotID == "" {
+		invalid.Fields = append(invalid.Fields, models.FieldError{Field: "snapshotId", Message: "is required"})
+	}
+	tags := validateBookmarkUpdate(req.Note, req.Tags, invalid)
+	if len(invalid.Fields) > 0 {
+		writeValidationError(c, invalid)
+		return
+	}
+
+	snapshot, err := h.Store.GetSnapshot(c.Request.Context(), req.SnapshotID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load snapshot %s: %v", req.SnapshotID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search", err.Error())
+		return
+	}
+
+	bookmark := &models.Bookmark{
+		SnapshotID: snapshot.ID,
+		Query:      snapshot.RequestParams.Query,
+		Note:       strings.TrimSpace(req.Note),
+		Tags:       tags,
+	}
+	if req.ResultID != "" {
+		bookmark.Kind = models.BookmarkKindResult
+		for i := range snapshot.Results {
+			if snapshot.Results[i].ID == req.ResultID {
+				bookmark.Result = &snapshot.Results[i]
+				break
+			}
+		}
+		if bookmark.Result == nil {
+			writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Result not found in this search", "")
+			return
+		}
+	} else {
+		if snapshot.Answer == "" {
+			invalidField(c, "resultId", "is required: this search has no answer to bookmark")
+			return
+		}
+		bookmark.Kind = models.BookmarkKindAnswer
+		bookmark.Answer = snapshot.Answer
+		bookmark.Citations = snapshot.Citations
+	}
+
+	err = h.Store.CreateBookmark(c.Request.Context(), clientKey(c), bookmark)
+	if errors.Is(err, store.ErrExists) {
+		writeError(c, http.StatusConflict, models.ErrorCodeConflict, "Already bookmarked", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to create bookmark: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create bookmark", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, bookmark)
+}
+
+// HandleTags lists the tags on the caller's bookmarks
+func (h *BookmarkHandler) HandleTags(c *gin.Context) {
+	tags, err := h.Store.BookmarkTags(c.Request.Context(), clientKey(c))
+	if err != nil {
+		log.Printf("Failed to list bookmark tags: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to list bookmark tags", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// HandleGet returns the bookmark :id
+func (h *BookmarkHandler) HandleGet(c *gin.Context) {
+	bookmark, ok := h.loadBookmark(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// HandleUpdate replaces the note and tags of the bookmark :id
+func (h *BookmarkHandler) HandleUpdate(c *gin.Context) {
+	bookmark, ok := h.loadBookmark(c)
+	if !ok {
+		return
+	}
+
+	var req models.BookmarkUpdate
+	if !bindJSON(c, &req) {
+		return
+	}
+	invalid := &services.ValidationError{}
+	tags := validateBookmarkUpdate(req.Note, req.Tags, invalid)
+	if len(invalid.Fields) > 0 {
+		writeValidationError(c, invalid)
+		return
+	}
+	bookmark.Note = strings.TrimSpace(req.Note)
+	bookmark.Tags = tags
+
+	err := h.Store.UpdateBookmark(c.Request.Context(), clientKey(c), bookmark)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Bookmark not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update bookmark %s: %v", bookmark.ID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to update bookmark", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, bookmark)
+}
+
+// HandleDelete removes the bookmark :id
+func (h *BookmarkHandler) HandleDelete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.Store.DeleteBookmark(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Bookmark not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete bookmark %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to delete bookmark", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// loadBookmark fetches the caller's bookmark :id, writing an error response
+// and returning false if it can't be loaded
+func (h *BookmarkHandler) loadBookmark(c *gin.Context) (*models.Bookmark, bool) {
+	id := c.Param("id")
+
+	bookmark, err := h.Store.GetBookmark(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Bookmark not found", "")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load bookmark %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load bookmark", err.Error())
+		return nil, false
+	}
+
+	return bookmark, true
+}
+
+// validateBookmarkUpdate checks a bookmark's note and tags, adding any
+// failures to invalid, and returns the tags normalized
+func validateBookmarkUpdate(note string, tags []string, invalid *services.ValidationError) []string {
+	if length := utf8.RuneCountInString(strings.TrimSpace(note)); length > maxBookmarkNoteLength {
+		invalid.Fields = append(invalid.Fields, models.FieldError{
+			Field:   "note",
+			Message: fmt.Sprintf("must be at most %d characters, got %d", maxBookmarkNoteLength, length),
+		})
+	}
+	normalized, err := normalizeTags(tags)
+	var tagErr *services.ValidationError
+	if errors.As(err, &tagErr) {
+		invalid.Fields = append(invalid.Fields, tagErr.Fields...)
+	}
+	return normalized
+}
+
+// normalizeTags trims, lowercases and deduplicates tags, reporting invalid
+// ones as a *services.ValidationError
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if seen[tag] {
+			continue
+		}
+		if !bookmarkTagPattern.MatchString(tag) {
+			return nil, &services.ValidationError{Fields: []models.FieldError{{
+				Field:   "tags",
+				Message: fmt.Sprintf("invalid tag '%s': use up to 32 letters, digits, spaces, dashes or underscores", tag),
+			}}}
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxBookmarkTags {
+		return nil, &services.ValidationError{Fields: []models.FieldError{{
+			Field:   "tags",
+			Message: fmt.Sprintf("at most %d tags, got %d", maxBookmarkTags, len(normalized)),
+		}}}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,21 @@
+// File: backend/api/handlers/client.go
+
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// clientKey identifies the caller for per-client data such as saved
+// searches, the same way the per-client limits do
+func clientKey(c *gin.Context) string {
+	return middleware.ClientKey(c)
+}
+
+// requestLocale is the supported locale the caller's Accept-Language header
+// prefers, for requests that don't name one
+func requestLocale(c *gin.Context) string {
+	return utils.MatchLocale(c.GetHeader("Accept-Language"))
+}
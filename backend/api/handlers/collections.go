@@ -0,0 +1,494 @@
+// File: backend/api/handlers/collections.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	maxCollectionNameLength        = 100
+	maxCollectionDescriptionLength = 2000
+	// maxCollectionSources caps the results a report is written from;
+	// picked results come first, then the results of whole searches
+	maxCollectionSources = 50
+)
+
+// CollectionHandler manages research collections and synthesizes reports
+// across their sources
+type CollectionHandler struct {
+	Store  *store.Store
+	Search *SearchHandler
+}
+
+// NewCollectionHandler creates a new collection handler
+func NewCollectionHandler(dataStore *store.Store, searchHandler *SearchHandler) *CollectionHandler {
+	return &CollectionHandler{
+		Store:  dataStore,
+		Search: searchHandler,
+	}
+}
+
+// collectionOperations documents the collection routes for
+// /api/openapi.json. Collections belong to the caller.
+var collectionOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/collections",
+		Tag:     "Collections",
+		Summary: "List collections, most recently changed first",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Collections []models.CollectionSummary `json:"collections"`
+			}{}},
+		},
+	},
+	{
+		Method:  "POST",
+		Path:    "/api/collections",
+		Tag:     "Collections",
+		Summary: "Create a collection",
+		Request: models.CollectionRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.Collection{}},
+			validationErrorResponse,
+		},
+	},
+	{
+		Method:     "GET",
+		Path:       "/api/collections/{id}",
+		Tag:        "Collections",
+		Summary:    "Get a collection with its items and latest report",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Collection{}},
+			errorResponse(http.StatusNotFound, "Collection not found"),
+		},
+	},
+	{
+		Method:     "PUT",
+		Path:       "/api/collections/{id}",
+		Tag:        "Collections",
+		Summary:    "Rename a collection",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Request:    models.CollectionRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Collection{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Collection not found"),
+		},
+	},
+	{
+		Method:     "DELETE",
+		Path:       "/api/collections/{id}",
+		Tag:        "Collections",
+		Summary:    "Delete a collection",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			errorResponse(http.StatusNotFound, "Collection not found"),
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/collections/{id}/items",
+		Tag:         "Collections",
+		Summary:     "Add a search or some of its results",
+		Description: "Adds the whole search, or only the results listed in resultIds. Picked results are copied, so they outlive the search. Items already in the collection are left as they are.",
+		Parameters:  []openapi.Parameter{{Name: "id", In: "path"}},
+		Request:     models.CollectionItemsRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Collection{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Collection, search or result not found"),
+		},
+	},
+	{
+		Method:  "DELETE",
+		Path:    "/api/collections/{id}/items",
+		Tag:     "Collections",
+		Summary: "Remove a search or result",
+		Parameters: []openapi.Parameter{
+			{Name: "id", In: "path"},
+			{Name: "snapshotId", In: "query", Description: "The search"},
+			{Name: "resultId", In: "query", Description: "The result to remove; empty removes the whole search"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Collection or item not found"),
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/collections/{id}/synthesize",
+		Tag:         "Collections",
+		Summary:     "Synthesize a report across the collection",
+		Description: fmt.Sprintf("Writes an AI report from the picked results and the results of every whole search, up to %d sources, and keeps it as the collection's latest report. The body may be empty.", maxCollectionSources),
+		Parameters:  []openapi.Parameter{{Name: "id", In: "path"}},
+		Request:     models.SynthesizeRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.CollectionReport{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Collection not found"),
+			clientBusyResponse,
+			errorResponse(http.StatusServiceUnavailable, "Server is busy or rate limited upstream; retry after the Retry-After header"),
+		},
+	},
+}
+
+// HandleList summarizes the caller's collections
+func (h *CollectionHandler) HandleList(c *gin.Context) {
+	collections, err := h.Store.ListCollections(c.Request.Context(), clientKey(c))
+	if err != nil {
+		log.Printf("Failed to list collections: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to list collections", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"collections": collections})
+}
+
+// HandleCreate stores a new, empty collection
+func (h *CollectionHandler) HandleCreate(c *gin.Context) {
+	collection, ok := bindCollection(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.CreateCollection(c.Request.Context(), clientKey(c), collection); err != nil {
+		log.Printf("Failed to create collection: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create collection", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// HandleGet returns the collection :id
+func (h *CollectionHandler) HandleGet(c *gin.Context) {
+	collection, ok := h.loadCollection(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// HandleUpdate replaces the name and description of the collection :id
+func (h *CollectionHandler) HandleUpdate(c *gin.Context) {
+	collection, ok := h.loadCollection(c)
+	if !ok {
+		return
+	}
+
+	update, ok := bindCollection(c)
+	if !ok {
+		return
+	}
+	collection.Name = update.Name
+	collection.Description = update.Description
+
+	err := h.Store.UpdateCollection(c.Request.Context(), clientKey(c), collection)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update collection %s: %v", collection.ID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to update collection", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// HandleDelete removes the collection :id
+func (h *CollectionHandler) HandleDelete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.Store.DeleteCollection(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete collection %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to delete collection", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleAddItems adds a search, or some of its results, to the collection
+// :id and returns the updated collection
+func (h *CollectionHandler) HandleAddItems(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.CollectionItemsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	req.SnapshotID = strings.TrimSpace(req.SnapshotID)
+	if req.SnapshotID == "" {
+		invalidField(c, "snapshotId", "is required")
+		return
+	}
+
+	snapshot, err := h.Store.GetSnapshot(c.Request.Context(), req.SnapshotID)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load snapshot %s: %v", req.SnapshotID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search", err.Error())
+		return
+	}
+
+	var searches []models.CollectionSearch
+	var results []models.CollectionResult
+	if len(req.ResultIDs) == 0 {
+		searches = append(searches, models.CollectionSearch{SnapshotID: snapshot.ID, Query: snapshot.RequestParams.Query})
+	}
+	for _, resultID := range req.ResultIDs {
+		result, ok := findResult(snapshot.Results, resultID)
+		if !ok {
+			writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Result not found in this search", resultID)
+			return
+		}
+		results = append(results, models.CollectionResult{SnapshotID: snapshot.ID, Query: snapshot.RequestParams.Query, Result: result})
+	}
+
+	err = h.Store.AddCollectionItems(c.Request.Context(), clientKey(c), id, searches, results)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to add to collection %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to add to collection", err.Error())
+		return
+	}
+
+	h.HandleGet(c)
+}
+
+// HandleRemoveItem removes the search ?snapshotId, or its result ?resultId,
+// from the collection :id
+func (h *CollectionHandler) HandleRemoveItem(c *gin.Context) {
+	id := c.Param("id")
+	snapshotID := strings.TrimSpace(c.Query("snapshotId"))
+	if snapshotID == "" {
+		invalidField(c, "snapshotId", "is required")
+		return
+	}
+
+	err := h.Store.RemoveCollectionItem(c.Request.Context(), clientKey(c), id, snapshotID, c.Query("resultId"))
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection or item not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to remove from collection %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to remove from collection", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleSynthesize writes a report across the sources of the collection :id
+// and keeps it as the collection's latest
+func (h *CollectionHandler) HandleSynthesize(c *gin.Context) {
+	collection, ok := h.loadCollection(c)
+	if !ok {
+		return
+	}
+
+	var req models.SynthesizeRequest
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	sources, err := h.collectSources(ctx, collection)
+	if err != nil {
+		log.Printf("Failed to load sources of collection %s: %v", collection.ID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load collection sources", err.Error())
+		return
+	}
+	if len(sources) == 0 {
+		invalidField(c, "id", "the collection has no results to synthesize; add searches or results first")
+		return
+	}
+
+	prompt := strings.TrimSpace(req.Prompt)
+	if prompt == "" {
+		prompt = fmt.Sprintf("Write a research report on %q that combines what these sources say, noting where they agree and disagree.", collection.Name)
+	}
+	if req.AnswerFormat == "" {
+		req.AnswerFormat = models.AnswerFormatEssay
+	}
+	searchReq := models.SearchRequest{
+		Query:          prompt,
+		ModelName:      req.ModelName,
+		Limit:          len(sources),
+		AnswerFormat:   req.AnswerFormat,
+		AnswerLanguage: req.AnswerLanguage,
+	}
+
+	log.Printf("Synthesizing collection %s from %d sources", collection.ID, len(sources))
+
+	response, err := h.Search.Pipeline.RunWithResults(ctx, searchReq, sources, models.SourceReddit)
+	var invalid *services.ValidationError
+	if errors.As(err, &invalid) {
+		// The prompt is validated as the query
+		for i := range invalid.Fields {
+			if invalid.Fields[i].Field == "query" {
+				invalid.Fields[i].Field = "prompt"
+			}
+		}
+	}
+	if err != nil {
+		writeServiceError(c, "Collection synthesis failed", err)
+		return
+	}
+	if response.AnswerError != nil {
+		writeCodedError(c, response.AnswerError.Code, "Collection synthesis failed", response.AnswerError.Details)
+		return
+	}
+
+	report := &models.CollectionReport{
+		Prompt:      prompt,
+		Answer:      response.Answer,
+		Citations:   response.Citations,
+		Sources:     response.Results,
+		Warnings:    response.Warnings,
+		ModelName:   response.RequestParams.ModelName,
+		GeneratedAt: time.Now().Unix(),
+	}
+	// Keep the report even if the client went away while it was written
+	err = h.Store.SaveCollectionReport(context.WithoutCancel(ctx), clientKey(c), collection.ID, report)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save report of collection %s: %v", collection.ID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to save collection report", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// collectSources gathers the results a collection's report is written
+// from: picked results first, then those of whole searches, without
+// duplicates and up to maxCollectionSources. Searches whose snapshots are
+// gone are skipped.
+func (h *CollectionHandler) collectSources(ctx context.Context, collection *models.Collection) ([]models.SearchResult, error) {
+	var sources []models.SearchResult
+	seen := make(map[string]bool)
+	add := func(result models.SearchResult) {
+		if len(sources) < maxCollectionSources && !seen[result.ID] {
+			seen[result.ID] = true
+			sources = append(sources, result)
+		}
+	}
+
+	for _, item := range collection.Results {
+		add(item.Result)
+	}
+	for _, search := range collection.Searches {
+		snapshot, err := h.Store.GetSnapshot(ctx, search.SnapshotID)
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range snapshot.Results {
+			add(result)
+		}
+	}
+	return sources, nil
+}
+
+// loadCollection fetches the caller's collection :id, writing an error
+// response and returning false if it can't be loaded
+func (h *CollectionHandler) loadCollection(c *gin.Context) (*models.Collection, bool) {
+	id := c.Param("id")
+
+	collection, err := h.Store.GetCollection(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Collection not found", "")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load collection %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load collection", err.Error())
+		return nil, false
+	}
+
+	return collection, true
+}
+
+// bindCollection parses and validates a collection payload, writing an
+// error response and returning false if it is invalid
+func bindCollection(c *gin.Context) (*models.Collection, bool) {
+	var req models.CollectionRequest
+	if !bindJSON(c, &req) {
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	req.Description = strings.TrimSpace(req.Description)
+	invalid := &services.ValidationError{}
+	if req.Name == "" {
+		invalid.Fields = append(invalid.Fields, models.FieldError{Field: "name", Message: "collection name cannot be empty"})
+	} else if length := utf8.RuneCountInString(req.Name); length > maxCollectionNameLength {
+		invalid.Fields = append(invalid.Fields, models.FieldError{
+			Field:   "name",
+			Message: fmt.Sprintf("must be at most %d characters, got %d", maxCollectionNameLength, length),
+		})
+	}
+	if length := utf8.RuneCountInString(req.Description); length > maxCollectionDescriptionLength {
+		invalid.Fields = append(invalid.Fields, models.FieldError{
+			Field:   "description",
+			Message: fmt.Sprintf("must be at most %d characters, got %d", maxCollectionDescriptionLength, length),
+		})
+	}
+	if len(invalid.Fields) > 0 {
+		writeValidationError(c, invalid)
+		return nil, false
+	}
+
+	return &models.Collection{Name: req.Name, Description: req.Description}, true
+}
+
+// findResult returns the result with id among results
+func findResult(results []models.SearchResult, id string) (models.SearchResult, bool) {
+	for _, result := range results {
+		if result.ID == id {
+			return result, true
+		}
+	}
+	return models.SearchResult{}, false
+}
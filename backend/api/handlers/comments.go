@@ -0,0 +1,124 @@
+// File: backend/api/handlers/comments.go
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	defaultCommentLimit = 50
+	maxCommentLimit     = 500
+	defaultCommentDepth = 5
+	maxCommentDepth     = 10
+)
+
+// postIDPattern matches Reddit base-36 post IDs
+var postIDPattern = regexp.MustCompile(`^[a-z0-9]{1,12}$`)
+
+// commentSorts are the sort orders Reddit accepts for comments
+var commentSorts = map[string]bool{
+	"confidence": true, "top": true, "new": true, "controversial": true, "old": true, "qa": true,
+}
+
+// CommentsHandler serves comment trees for posts
+type CommentsHandler struct {
+	RedditService *services.RedditService
+}
+
+// NewCommentsHandler creates a new comments handler
+func NewCommentsHandler(redditService *services.RedditService) *CommentsHandler {
+	return &CommentsHandler{
+		RedditService: redditService,
+	}
+}
+
+// commentsOperations documents the comments route for /api/openapi.json
+var commentsOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/comments/{postId}",
+		Tag:     "Reddit",
+		Summary: "Get a post and its comment tree",
+		Parameters: []openapi.Parameter{
+			{Name: "postId", In: "path", Description: "Reddit post ID, with or without the t3_ prefix"},
+			{Name: "sort", In: "query", Description: "confidence (default), top, new, controversial, old or qa"},
+			{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Comments in the tree, default %d, at most %d", defaultCommentLimit, maxCommentLimit)},
+			{Name: "depth", In: "query", Type: "integer", Description: fmt.Sprintf("Reply depth, default %d, at most %d", defaultCommentDepth, maxCommentDepth)},
+			{Name: "expand", In: "query", Type: "boolean", Description: "Load some of the comments Reddit left out"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.CommentThread{}},
+			errorResponse(http.StatusBadRequest, "Invalid parameters"),
+			errorResponse(http.StatusBadGateway, "Reddit request failed"),
+			errorResponse(http.StatusServiceUnavailable, "Reddit is rate limiting the server"),
+		},
+	},
+}
+
+// HandleComments returns a post and its comment tree. The sort, limit and
+// depth query parameters shape the tree; comments beyond it are summarized
+// as "more" stubs, a few of which are loaded when expand=true.
+func (h *CommentsHandler) HandleComments(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	postID := strings.TrimPrefix(strings.ToLower(c.Param("postId")), "t3_")
+	if !postIDPattern.MatchString(postID) {
+		invalidField(c, "postId", "invalid post ID")
+		return
+	}
+
+	sort := c.DefaultQuery("sort", "confidence")
+	if !commentSorts[sort] {
+		invalidField(c, "sort", "must be one of confidence, top, new, controversial, old or qa")
+		return
+	}
+
+	limit, ok := boundedIntQuery(c, "limit", defaultCommentLimit, maxCommentLimit)
+	if !ok {
+		return
+	}
+	depth, ok := boundedIntQuery(c, "depth", defaultCommentDepth, maxCommentDepth)
+	if !ok {
+		return
+	}
+
+	thread, err := h.RedditService.GetComments(ctx, postID, services.CommentOptions{
+		Sort:   sort,
+		Limit:  limit,
+		Depth:  depth,
+		Expand: c.Query("expand") == "true",
+	})
+	if err != nil {
+		writeUpstreamError(c, "Failed to fetch comments from Reddit", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+// boundedIntQuery reads a positive integer query parameter, capped at max.
+// It writes a 400 response and returns false when the value is invalid.
+func boundedIntQuery(c *gin.Context, name string, defaultValue, max int) (int, bool) {
+	value, err := strconv.Atoi(c.DefaultQuery(name, strconv.Itoa(defaultValue)))
+	if err != nil || value <= 0 {
+		invalidField(c, name, "must be a positive integer")
+		return 0, false
+	}
+	if value > max {
+		value = max
+	}
+	return value, true
+}
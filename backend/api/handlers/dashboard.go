@@ -0,0 +1,77 @@
+// File: backend/api/handlers/dashboard.go
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// DashboardHandler gathers the state operators watch into one response, to
+// back an ops dashboard. Routes using it must be protected by
+// middleware.RequireAdmin.
+type DashboardHandler struct {
+	Health   *HealthHandler
+	Pipeline *services.SearchPipeline
+	Errors   *middleware.ErrorLog
+}
+
+// NewDashboardHandler creates a new dashboard handler. errors holds the
+// server errors recorded by middleware.RecordErrors.
+func NewDashboardHandler(health *HealthHandler, pipeline *services.SearchPipeline, errors *middleware.ErrorLog) *DashboardHandler {
+	return &DashboardHandler{
+		Health:   health,
+		Pipeline: pipeline,
+		Errors:   errors,
+	}
+}
+
+// adminDashboard is the response of the dashboard route
+type adminDashboard struct {
+	Health       models.HealthReport    `json:"health"`
+	RateLimits   dashboardRateLimits    `json:"rateLimits"`
+	Caches       map[string]cache.Stats `json:"caches"`
+	Searches     models.SearchLoad      `json:"searches"`
+	RecentErrors []models.RecentError   `json:"recentErrors"` // Newest first
+}
+
+// dashboardRateLimits reports how much of Reddit's budget is left, and the
+// adaptive limit keeping the server within it
+type dashboardRateLimits struct {
+	Reddit            models.RateLimitBudget   `json:"reddit"`
+	RedditConcurrency models.ConcurrencyStatus `json:"redditConcurrency"`
+}
+
+// dashboardOperations documents the dashboard route for /api/openapi.json
+var dashboardOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/admin/dashboard",
+		Tag:         "Admin",
+		Summary:     "Report the state of the server for an ops dashboard",
+		Description: "Combines dependency health, Reddit's rate-limit budget and the adaptive concurrency limit, cache statistics, running and queued searches, and the most recent server errors. It responds 200 even when dependencies are down; see health.status.",
+		Admin:       true,
+		Responses:   []openapi.Response{{Status: http.StatusOK, Body: adminDashboard{}}},
+	},
+}
+
+// HandleDashboard reports the state of this replica and its dependencies
+func (h *DashboardHandler) HandleDashboard(c *gin.Context) {
+	reddit := h.Health.RedditService
+	c.JSON(http.StatusOK, adminDashboard{
+		Health: h.Health.report(c.Request.Context()),
+		RateLimits: dashboardRateLimits{
+			Reddit:            reddit.RateLimitBudget(),
+			RedditConcurrency: reddit.ConcurrencyStatus(),
+		},
+		Caches:       reddit.CacheStats(),
+		Searches:     h.Pipeline.Load(),
+		RecentErrors: h.Errors.Recent(),
+	})
+}
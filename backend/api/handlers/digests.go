@@ -0,0 +1,66 @@
+// File: backend/api/handlers/digests.go
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/digest"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// DigestHandler serves scheduled topic digests
+type DigestHandler struct {
+	Scheduler *digest.Scheduler // nil when digests are disabled
+}
+
+// NewDigestHandler creates a new digest handler
+func NewDigestHandler(scheduler *digest.Scheduler) *DigestHandler {
+	return &DigestHandler{
+		Scheduler: scheduler,
+	}
+}
+
+// digestOperations documents the digest route for /api/openapi.json
+var digestOperations = []openapi.Operation{
+	{
+		Method:     "GET",
+		Path:       "/api/digests/{topic}",
+		Tag:        "Digests",
+		Summary:    "Get the latest digest for a topic",
+		Parameters: []openapi.Parameter{{Name: "topic", In: "path", Description: "Topic name from the digests configuration"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Digest{}},
+			errorResponse(http.StatusNotFound, "Unknown topic, or digests are disabled"),
+			errorResponse(http.StatusServiceUnavailable, "Digest has not been generated yet"),
+		},
+	},
+}
+
+// HandleGet returns the latest digest for the topic in the URL
+func (h *DigestHandler) HandleGet(c *gin.Context) {
+	if h.Scheduler == nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeDisabled, "Digests are not enabled", "")
+		return
+	}
+
+	result, err := h.Scheduler.Get(c.Request.Context(), c.Param("topic"))
+	switch {
+	case errors.Is(err, digest.ErrUnknownTopic):
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Unknown digest topic", "")
+	case errors.Is(err, store.ErrNotFound):
+		// Configured, but the first run hasn't finished yet
+		c.Header("Retry-After", "60")
+		writeError(c, http.StatusServiceUnavailable, models.ErrorCodeNotReady, "Digest has not been generated yet", "")
+	case err != nil:
+		log.Printf("Failed to load digest: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load digest", err.Error())
+	default:
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,91 @@
+// File: backend/api/handlers/errors.go
+
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// serviceErrorStatus is the HTTP status for each error code services report,
+// and the Retry-After delay in seconds for the ones worth retrying soon
+var serviceErrorStatus = map[string]struct {
+	status     int
+	retryAfter string
+}{
+	models.ErrorCodeInvalidRequest:      {http.StatusBadRequest, ""},
+	models.ErrorCodeRedditLoginRequired: {http.StatusForbidden, ""},
+	models.ErrorCodeOverloaded:          {http.StatusServiceUnavailable, "5"},
+	models.ErrorCodeTimeout:             {http.StatusGatewayTimeout, ""},
+	models.ErrorCodeRedditRateLimited:   {http.StatusServiceUnavailable, "30"},
+	models.ErrorCodeRedditUnavailable:   {http.StatusBadGateway, ""},
+	models.ErrorCodeAIRateLimited:       {http.StatusServiceUnavailable, "30"},
+	models.ErrorCodeAIUnavailable:       {http.StatusBadGateway, ""},
+	models.ErrorCodeInternal:            {http.StatusInternalServerError, ""},
+}
+
+// errorResponse documents an error response for /api/openapi.json
+func errorResponse(status int, description string) openapi.Response {
+	return openapi.Response{Status: status, Description: description, Body: models.ErrorResponse{}}
+}
+
+// clientBusyResponse documents the 429 response for routes behind the
+// per-client concurrency cap and quotas
+var clientBusyResponse = errorResponse(http.StatusTooManyRequests, "Too many searches in flight for this client (too_many_requests), or a quota is used up (quota_exceeded); retry after the Retry-After header")
+
+// writeError responds with the standard error body
+func writeError(c *gin.Context, status int, code, message, details string) {
+	c.JSON(status, newErrorBody(c, code, message, details))
+}
+
+// writeServiceError responds to an error from the services, choosing the
+// status and code from what went wrong, e.g. 503 reddit_rate_limited when
+// Reddit is throttling the server. message describes the failed operation.
+func writeServiceError(c *gin.Context, message string, err error) {
+	code := services.ErrorCode(err)
+	if code == models.ErrorCodeInvalidRequest {
+		writeValidationError(c, err)
+		return
+	}
+
+	writeCodedError(c, code, message, err.Error())
+}
+
+// writeCodedError responds with the status serviceErrorStatus gives code,
+// for failures the services reported as an error code rather than an error
+func writeCodedError(c *gin.Context, code, message, details string) {
+	mapping, ok := serviceErrorStatus[code]
+	if !ok {
+		mapping = serviceErrorStatus[models.ErrorCodeInternal]
+	}
+	if mapping.retryAfter != "" {
+		c.Header("Retry-After", mapping.retryAfter)
+	}
+	log.Printf("%s (%s): %s", message, code, details)
+	writeError(c, mapping.status, code, message, details)
+}
+
+// writeUpstreamError responds to a failed call to Reddit. Failures the
+// services recognize get their own code, as in writeServiceError; anything
+// else is reported as 502 upstream_error.
+func writeUpstreamError(c *gin.Context, message string, err error) {
+	if services.ErrorCode(err) != models.ErrorCodeInternal {
+		writeServiceError(c, message, err)
+		return
+	}
+	log.Printf("%s: %v", message, err)
+	writeError(c, http.StatusBadGateway, models.ErrorCodeUpstream, message, err.Error())
+}
+
+func newErrorBody(c *gin.Context, code, message, details string) models.ErrorResponse {
+	body := models.NewErrorResponse(code, message, details)
+	body.RequestID = middleware.RequestIDFrom(c)
+	middleware.NoteError(c, body)
+	return body
+}
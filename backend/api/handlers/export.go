@@ -0,0 +1,195 @@
+// File: backend/api/handlers/export.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/export"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// ExportHandler serves persisted search snapshots in downloadable formats
+type ExportHandler struct {
+	Store *store.Store
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(dataStore *store.Store) *ExportHandler {
+	return &ExportHandler{
+		Store: dataStore,
+	}
+}
+
+// embedCitations is how many citations the answer widget shows
+const embedCitations = 3
+
+// exportOperations documents the export routes for /api/openapi.json
+var exportOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/search/{id}/export",
+		Tag:     "Snapshots",
+		Summary: "Export a search snapshot",
+		Parameters: []openapi.Parameter{
+			{Name: "id", In: "path", Description: "Snapshot ID from SearchResponse.id"},
+			{Name: "format", In: "query", Description: "csv (default), markdown or html; markdown and html include the caller's notes on results"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Description: "The snapshot in the requested format", Body: "", ContentType: "text/csv"},
+			errorResponse(http.StatusBadRequest, "Unsupported format"),
+			errorResponse(http.StatusNotFound, "Snapshot not found"),
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/embed/{snapshotId}",
+		Tag:         "Snapshots",
+		Summary:     "Get an embeddable answer widget",
+		Description: fmt.Sprintf("Returns the answer and its first %d citations, as JSON or as an HTML card for an iframe. Snapshots never change, so responses may be cached.", embedCitations),
+		Parameters: []openapi.Parameter{
+			{Name: "snapshotId", In: "path", Description: "Snapshot ID from SearchResponse.id"},
+			{Name: "format", In: "query", Description: "json (default) or html"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Embed{}},
+			{Status: http.StatusNotModified, Description: "The cached copy matching If-None-Match is current"},
+			errorResponse(http.StatusBadRequest, "Unsupported format"),
+			errorResponse(http.StatusNotFound, "Snapshot not found"),
+		},
+	},
+	{
+		Method:     "GET",
+		Path:       "/api/reports/{id}",
+		Tag:        "Snapshots",
+		Summary:    "View a search snapshot as an HTML report, with the caller's notes on results",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path", Description: "Snapshot ID from SearchResponse.id"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: "", ContentType: "text/html"},
+			errorResponse(http.StatusNotFound, "Snapshot not found"),
+		},
+	},
+}
+
+// HandleExport renders the snapshot identified by :id in the requested format
+func (h *ExportHandler) HandleExport(c *gin.Context) {
+	id := c.Param("id")
+	format := c.DefaultQuery("format", "csv")
+
+	snapshot, ok := h.loadSnapshot(c, id)
+	if !ok {
+		return
+	}
+
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="subplexity-%s.csv"`, id))
+		c.Status(http.StatusOK)
+		if err := export.WriteCSV(c.Writer, snapshot.Results); err != nil {
+			log.Printf("Failed to write CSV export for %s: %v", id, err)
+		}
+	case "markdown", "md":
+		c.Header("Content-Type", "text/markdown; charset=utf-8")
+		c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="subplexity-%s.md"`, id))
+		c.Status(http.StatusOK)
+		if err := export.WriteMarkdown(c.Writer, snapshot, h.annotations(c, id)); err != nil {
+			log.Printf("Failed to write Markdown export for %s: %v", id, err)
+		}
+	case "html":
+		h.writeHTMLReport(c, id, snapshot)
+	default:
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, fmt.Sprintf("Unsupported export format '%s'", format), "")
+	}
+}
+
+// HandleReport serves the self-contained HTML report for the snapshot :id
+func (h *ExportHandler) HandleReport(c *gin.Context) {
+	id := c.Param("id")
+
+	snapshot, ok := h.loadSnapshot(c, id)
+	if !ok {
+		return
+	}
+
+	h.writeHTMLReport(c, id, snapshot)
+}
+
+// HandleEmbed serves the answer widget for the snapshot :snapshotId.
+// Snapshots are immutable, so responses carry a long max-age and an ETag.
+func (h *ExportHandler) HandleEmbed(c *gin.Context) {
+	id := c.Param("snapshotId")
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "html" {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, fmt.Sprintf("Unsupported embed format '%s'", format), "")
+		return
+	}
+
+	etag := fmt.Sprintf(`"%s-%s"`, id, format)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	snapshot, ok := h.loadSnapshot(c, id)
+	if !ok {
+		return
+	}
+	embed := export.NewEmbed(snapshot, embedCitations)
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Header("ETag", etag)
+	if format == "json" {
+		c.JSON(http.StatusOK, embed)
+		return
+	}
+
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := export.WriteEmbedHTML(c.Writer, embed); err != nil {
+		log.Printf("Failed to write embed for %s: %v", id, err)
+	}
+}
+
+// loadSnapshot loads the snapshot id, responding with an error and
+// returning false when it can't
+func (h *ExportHandler) loadSnapshot(c *gin.Context, id string) (*models.SearchResponse, bool) {
+	snapshot, err := h.Store.GetSnapshot(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search snapshot not found", "")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load snapshot %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search snapshot", err.Error())
+		return nil, false
+	}
+	return snapshot, true
+}
+
+// annotations loads the caller's notes on the snapshot id's results. The
+// export goes ahead without them if they can't be loaded.
+func (h *ExportHandler) annotations(c *gin.Context, id string) []models.Annotation {
+	annotations, err := h.Store.ListAnnotations(c.Request.Context(), clientKey(c), id)
+	if err != nil {
+		log.Printf("Failed to load annotations for %s: %v", id, err)
+		return nil
+	}
+	return annotations
+}
+
+// writeHTMLReport renders a snapshot as an HTML report into the response
+func (h *ExportHandler) writeHTMLReport(c *gin.Context, id string, snapshot *models.SearchResponse) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="subplexity-%s.html"`, id))
+	c.Status(http.StatusOK)
+	if err := export.WriteHTML(c.Writer, snapshot, h.annotations(c, id)); err != nil {
+		log.Printf("Failed to write HTML report for %s: %v", id, err)
+	}
+}
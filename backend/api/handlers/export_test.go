@@ -0,0 +1,89 @@
+// File: backend/api/handlers/export_test.go
+
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// getEmbed fetches path from r's embed route
+func getEmbed(r http.Handler, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestHandleEmbed(t *testing.T) {
+	search, _ := newTestSearchHandler(t)
+	h := NewExportHandler(search.Store)
+	r := gin.New()
+	r.GET("/api/embed/:snapshotId", h.HandleEmbed)
+
+	const script = `<script>alert(1)</script>`
+	id, err := search.Store.SaveSnapshot(context.Background(), &models.SearchResponse{
+		Answer: "Use Go [1]. " + script,
+		Citations: []models.Citation{
+			{Index: 1, Title: "Post " + script, URL: "https://reddit.com/r/golang/comments/go1", Subreddit: "golang"},
+			{Index: 2, Title: "Bad link", URL: "javascript:alert(1)", Subreddit: "golang"},
+		},
+		TotalCount:    2,
+		RequestParams: models.RequestParams{Query: "</title>" + script},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	rec := getEmbed(r, "/api/embed/"+id+"?format=html")
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected an HTML embed, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	html := rec.Body.String()
+	for _, unsafe := range []string{"<script>", "javascript:", "</title><"} {
+		if strings.Contains(html, unsafe) {
+			t.Errorf("Expected %q to be escaped in:\n%s", unsafe, html)
+		}
+	}
+	if !strings.Contains(html, `<a class="cite" href="https://reddit.com/r/golang/comments/go1" rel="noopener">[1]</a>`) {
+		t.Errorf("Expected [1] to link to its source in:\n%s", html)
+	}
+
+	rec = getEmbed(r, "/api/embed/"+id)
+	var embed models.Embed
+	if err := json.Unmarshal(rec.Body.Bytes(), &embed); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected a JSON embed, got %d: %s", rec.Code, rec.Body)
+	}
+	if embed.ID != id || len(embed.Citations) != 2 || rec.Header().Get("ETag") == "" {
+		t.Errorf("Unexpected embed %+v", embed)
+	}
+
+	// A cached copy is still current
+	req := httptest.NewRequest(http.MethodGet, "/api/embed/"+id, nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for a matching ETag, got %d", rec.Code)
+	}
+
+	for path, status := range map[string]int{
+		"/api/embed/unknown":               http.StatusNotFound,
+		"/api/embed/unknown?format=html":   http.StatusNotFound,
+		"/api/embed/" + id + "?format=pdf": http.StatusBadRequest,
+	} {
+		rec := getEmbed(r, path)
+		if rec.Code != status {
+			t.Errorf("%s: expected %d, got %d", path, status, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "<html") {
+			t.Errorf("%s: expected an error, not a widget", path)
+		}
+	}
+}
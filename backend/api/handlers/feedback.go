@@ -0,0 +1,92 @@
+// File: backend/api/handlers/feedback.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// maxFeedbackCommentLength caps free-text comments on feedback
+const maxFeedbackCommentLength = 2000
+
+// FeedbackHandler collects user judgments of answers
+type FeedbackHandler struct {
+	Store *store.Store
+}
+
+// NewFeedbackHandler creates a new feedback handler
+func NewFeedbackHandler(dataStore *store.Store) *FeedbackHandler {
+	return &FeedbackHandler{
+		Store: dataStore,
+	}
+}
+
+// feedbackOperations documents the feedback route for /api/openapi.json
+var feedbackOperations = []openapi.Operation{
+	{
+		Method:  "POST",
+		Path:    "/api/feedback",
+		Tag:     "Feedback",
+		Summary: "Rate an answer",
+		Request: models.FeedbackRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.Feedback{}},
+			errorResponse(http.StatusBadRequest, "Invalid feedback"),
+			errorResponse(http.StatusNotFound, "Search not found"),
+		},
+	},
+}
+
+// HandleFeedback records a thumbs up/down, with an optional comment, for a
+// previously returned search
+func (h *FeedbackHandler) HandleFeedback(c *gin.Context) {
+	var req models.FeedbackRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	// Validate request
+	if req.SearchID == "" {
+		invalidField(c, "searchId", "is required")
+		return
+	}
+	rating := strings.ToLower(strings.TrimSpace(req.Rating))
+	if rating != models.RatingUp && rating != models.RatingDown {
+		invalidField(c, "rating", "must be 'up' or 'down'")
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if len(comment) > maxFeedbackCommentLength {
+		invalidField(c, "comment", fmt.Sprintf("cannot be longer than %d characters", maxFeedbackCommentLength))
+		return
+	}
+
+	feedback := &models.Feedback{
+		SearchID: req.SearchID,
+		Rating:   rating,
+		Comment:  comment,
+	}
+
+	err := h.Store.SaveFeedback(c.Request.Context(), clientKey(c), feedback)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save feedback for %s: %v", req.SearchID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to save feedback", err.Error())
+		return
+	}
+
+	log.Printf("Feedback recorded for search %s: %s", feedback.SearchID, feedback.Rating)
+	c.JSON(http.StatusCreated, feedback)
+}
@@ -0,0 +1,97 @@
+// File: backend/api/handlers/graphql.go
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// Bounds on GraphQL queries, so one request can't fan out into an unbounded
+// number of Reddit calls
+const (
+	maxGraphQLDepth       = 12
+	maxGraphQLParallelism = 4
+)
+
+// ownerKey carries the caller's clientKey to resolvers
+type ownerKey struct{}
+
+// localeKey carries the caller's Accept-Language locale to resolvers
+type localeKey struct{}
+
+// GraphQLHandler serves the GraphQL API, which exposes search, posts,
+// subreddits and users so clients can fetch exactly the fields they need in
+// one request
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQL handler. Searches run through
+// searchHandler so they are saved and recorded in history like REST ones.
+func NewGraphQLHandler(searchHandler *SearchHandler, redditService *services.RedditService) *GraphQLHandler {
+	resolver := &queryResolver{
+		searchHandler: searchHandler,
+		redditService: redditService,
+	}
+	return &GraphQLHandler{
+		schema: graphql.MustParseSchema(graphQLSchema, resolver,
+			graphql.MaxDepth(maxGraphQLDepth),
+			graphql.MaxParallelism(maxGraphQLParallelism),
+		),
+	}
+}
+
+// graphQLRequest is a GraphQL request in the usual JSON-over-HTTP form
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLOperations documents the GraphQL route for /api/openapi.json
+var graphQLOperations = []openapi.Operation{
+	{
+		Method:      "POST",
+		Path:        "/api/graphql",
+		Tag:         "GraphQL",
+		Summary:     "Run a GraphQL query",
+		Description: "Queries search, posts, subreddits and users. Field errors are reported in the errors list with a 200 status.",
+		Request:     graphQLRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Data   map[string]interface{}   `json:"data"`
+				Errors []map[string]interface{} `json:"errors,omitempty"`
+			}{}},
+			errorResponse(http.StatusBadRequest, "Malformed request"),
+			clientBusyResponse,
+		},
+	},
+}
+
+// HandleGraphQL executes a GraphQL query. Field errors are reported in the
+// response's errors list alongside any data that did resolve, as GraphQL
+// clients expect, so only malformed requests get a non-200 status.
+func (h *GraphQLHandler) HandleGraphQL(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	var req graphQLRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if req.Query == "" {
+		invalidField(c, "query", "cannot be empty")
+		return
+	}
+
+	ctx = context.WithValue(ctx, ownerKey{}, clientKey(c))
+	ctx = context.WithValue(ctx, localeKey{}, requestLocale(c))
+	c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
@@ -0,0 +1,459 @@
+// File: backend/api/handlers/graphql_resolvers.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	defaultGraphQLListLimit = 25
+	maxGraphQLListLimit     = 100
+)
+
+// usernamePattern matches valid Reddit usernames
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,20}$`)
+
+// subredditListings are the listings Subreddit.posts accepts
+var subredditListings = map[string]bool{"hot": true, "new": true, "top": true}
+
+// queryResolver resolves the root Query type
+type queryResolver struct {
+	searchHandler *SearchHandler
+	redditService *services.RedditService
+}
+
+type searchArgs struct {
+	Query             string
+	Mode              *string
+	Model             *string
+	Limit             *int32
+	Subreddits        *[]string
+	ExcludeSubreddits *[]string
+	ExcludeAuthors    *[]string
+	AnswerLanguage    *string
+	AnswerFormat      *string
+	Locale            *string
+	Timezone          *string
+	Verify            bool
+	SelfConsistency   bool
+	CleanLanguage     bool
+	SafeSearch        bool
+	SkipAI            bool
+	MaxContentLength  *int32
+	Highlights        *int32
+	MinSubreddits     *int32
+	RecencyBias       *string
+	Region            *string
+	MinScore          *int32
+	MinComments       *int32
+}
+
+// Search runs the search pipeline for the caller
+func (r *queryResolver) Search(ctx context.Context, args searchArgs) (*searchResponseResolver, error) {
+	req := models.SearchRequest{
+		Query:           args.Query,
+		SearchMode:      stringArg(args.Mode),
+		ModelName:       stringArg(args.Model),
+		AnswerLanguage:  stringArg(args.AnswerLanguage),
+		AnswerFormat:    stringArg(args.AnswerFormat),
+		Locale:          stringArg(args.Locale),
+		Timezone:        stringArg(args.Timezone),
+		Verify:          args.Verify,
+		SelfConsistency: args.SelfConsistency,
+		CleanLanguage:   args.CleanLanguage,
+		SafeSearch:      args.SafeSearch,
+		SkipAI:          args.SkipAI,
+		RecencyBias:     stringArg(args.RecencyBias),
+		Region:          stringArg(args.Region),
+	}
+	if args.Limit != nil {
+		req.Limit = int(*args.Limit)
+	}
+	if args.Subreddits != nil {
+		req.Subreddits = *args.Subreddits
+	}
+	if args.ExcludeSubreddits != nil {
+		req.ExcludeSubreddits = *args.ExcludeSubreddits
+	}
+	if args.ExcludeAuthors != nil {
+		req.ExcludeAuthors = append([]string{}, *args.ExcludeAuthors...)
+	}
+	if args.MaxContentLength != nil {
+		req.MaxContentLength = int(*args.MaxContentLength)
+	}
+	if args.Highlights != nil {
+		req.Highlights = int(*args.Highlights)
+	}
+	if args.MinSubreddits != nil {
+		req.MinSubreddits = int(*args.MinSubreddits)
+	}
+	if args.MinScore != nil {
+		req.MinScore = int(*args.MinScore)
+	}
+	if args.MinComments != nil {
+		req.MinComments = int(*args.MinComments)
+	}
+	if err := r.searchHandler.Pipeline.Validate(&req); err != nil {
+		return nil, err
+	}
+	if req.Locale == "" {
+		req.Locale, _ = ctx.Value(localeKey{}).(string)
+	}
+
+	owner, _ := ctx.Value(ownerKey{}).(string)
+	response, err := r.searchHandler.runSearch(ctx, owner, req)
+	if err != nil {
+		return nil, err
+	}
+	return &searchResponseResolver{response: response}, nil
+}
+
+// Post returns a post and its comment tree
+func (r *queryResolver) Post(ctx context.Context, args struct {
+	ID           graphql.ID
+	CommentSort  string
+	CommentLimit int32
+	CommentDepth int32
+}) (*postResolver, error) {
+	postID := strings.TrimPrefix(strings.ToLower(string(args.ID)), "t3_")
+	if !postIDPattern.MatchString(postID) {
+		return nil, errors.New("invalid post ID")
+	}
+	sort := args.CommentSort
+	if !commentSorts[sort] {
+		return nil, errors.New("commentSort must be one of confidence, top, new, controversial, old or qa")
+	}
+
+	thread, err := r.redditService.GetComments(ctx, postID, services.CommentOptions{
+		Sort:  sort,
+		Limit: boundedIntArg(args.CommentLimit, defaultCommentLimit, maxCommentLimit),
+		Depth: boundedIntArg(args.CommentDepth, defaultCommentDepth, maxCommentDepth),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &postResolver{thread: thread}, nil
+}
+
+// Subreddit returns a community's details
+func (r *queryResolver) Subreddit(ctx context.Context, args struct{ Name string }) (*subredditResolver, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(args.Name), "r/")
+	if !subredditNamePattern.MatchString(name) {
+		return nil, errors.New("invalid subreddit name")
+	}
+
+	subreddit, err := r.redditService.GetSubreddit(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &subredditResolver{subreddit: subreddit, redditService: r.redditService}, nil
+}
+
+// User returns a user's public profile
+func (r *queryResolver) User(ctx context.Context, args struct{ Name string }) (*userResolver, error) {
+	name := strings.TrimPrefix(strings.TrimSpace(args.Name), "u/")
+	if !usernamePattern.MatchString(name) {
+		return nil, errors.New("invalid username")
+	}
+
+	user, err := r.redditService.GetUser(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{user: user, redditService: r.redditService}, nil
+}
+
+// searchResponseResolver resolves SearchResponse
+type searchResponseResolver struct {
+	response *models.SearchResponse
+}
+
+func (r *searchResponseResolver) ID() *graphql.ID {
+	if r.response.ID == "" {
+		return nil
+	}
+	id := graphql.ID(r.response.ID)
+	return &id
+}
+
+func (r *searchResponseResolver) Answer() string       { return r.response.Answer }
+func (r *searchResponseResolver) Reasoning() string    { return r.response.Reasoning }
+func (r *searchResponseResolver) TotalCount() int32    { return int32(r.response.TotalCount) }
+func (r *searchResponseResolver) Warnings() []string   { return nonNilStrings(r.response.Warnings) }
+func (r *searchResponseResolver) Source() string       { return r.response.Source }
+func (r *searchResponseResolver) ElapsedTime() float64 { return r.response.ElapsedTime }
+func (r *searchResponseResolver) LastUpdated() float64 { return float64(r.response.LastUpdated) }
+func (r *searchResponseResolver) LastUpdatedLocal() *string {
+	return optionalString(r.response.LastUpdatedLocal)
+}
+
+func (r *searchResponseResolver) Results() []*resultResolver {
+	return resultResolvers(r.response.Results)
+}
+
+func (r *searchResponseResolver) Citations() []*citationResolver {
+	citations := make([]*citationResolver, len(r.response.Citations))
+	for i := range r.response.Citations {
+		citations[i] = &citationResolver{citation: &r.response.Citations[i]}
+	}
+	return citations
+}
+
+func (r *searchResponseResolver) Consistency() *consistencyResolver {
+	if r.response.Consistency == nil {
+		return nil
+	}
+	return &consistencyResolver{report: r.response.Consistency}
+}
+
+func (r *searchResponseResolver) AnswerError() *answerErrorResolver {
+	if r.response.AnswerError == nil {
+		return nil
+	}
+	return &answerErrorResolver{failure: r.response.AnswerError}
+}
+
+// resultResolver resolves Result
+type resultResolver struct {
+	result *models.SearchResult
+}
+
+func resultResolvers(results []models.SearchResult) []*resultResolver {
+	resolvers := make([]*resultResolver, len(results))
+	for i := range results {
+		resolvers[i] = &resultResolver{result: &results[i]}
+	}
+	return resolvers
+}
+
+func (r *resultResolver) ID() graphql.ID        { return graphql.ID(r.result.ID) }
+func (r *resultResolver) Type() string          { return r.result.Type }
+func (r *resultResolver) Title() string         { return r.result.Title }
+func (r *resultResolver) Subreddit() string     { return r.result.Subreddit }
+func (r *resultResolver) Author() string        { return r.result.Author }
+func (r *resultResolver) Content() string       { return r.result.Content }
+func (r *resultResolver) URL() string           { return r.result.URL }
+func (r *resultResolver) Permalink() string     { return r.result.Permalink }
+func (r *resultResolver) ExternalURL() *string  { return optionalString(r.result.ExternalURL) }
+func (r *resultResolver) CreatedUTC() float64   { return float64(r.result.CreatedUTC) }
+func (r *resultResolver) CreatedLocal() *string { return optionalString(r.result.CreatedLocal) }
+func (r *resultResolver) CreatedAgo() *string   { return optionalString(r.result.CreatedAgo) }
+func (r *resultResolver) Score() int32          { return int32(r.result.Score) }
+func (r *resultResolver) CommentCount() int32   { return int32(r.result.CommentCount) }
+func (r *resultResolver) Highlights() []string  { return nonNilStrings(r.result.Highlights) }
+func (r *resultResolver) Flair() *string        { return optionalString(r.result.Flair) }
+func (r *resultResolver) AuthorFlair() *string  { return optionalString(r.result.AuthorFlair) }
+func (r *resultResolver) NSFW() bool            { return r.result.NSFW }
+func (r *resultResolver) Distinguished() bool   { return r.result.Distinguished }
+func (r *resultResolver) Stickied() bool        { return r.result.Stickied }
+func (r *resultResolver) Archived() bool        { return r.result.Archived }
+func (r *resultResolver) Locked() bool          { return r.result.Locked }
+func (r *resultResolver) ThumbnailURL() *string { return optionalString(r.result.ThumbnailURL) }
+func (r *resultResolver) PreviewURL() *string   { return optionalString(r.result.PreviewURL) }
+func (r *resultResolver) EditedUTC() *float64   { return optionalFloat(float64(r.result.EditedUTC)) }
+func (r *resultResolver) UpvoteRatio() *float64 { return optionalFloat(r.result.UpvoteRatio) }
+func (r *resultResolver) Relevance() *float64   { return optionalFloat(r.result.Relevance) }
+
+// citationResolver resolves Citation
+type citationResolver struct {
+	citation *models.Citation
+}
+
+func (r *citationResolver) Index() int32      { return int32(r.citation.Index) }
+func (r *citationResolver) Text() string      { return r.citation.Text }
+func (r *citationResolver) URL() string       { return r.citation.URL }
+func (r *citationResolver) Title() string     { return r.citation.Title }
+func (r *citationResolver) Type() string      { return r.citation.Type }
+func (r *citationResolver) Subreddit() string { return r.citation.Subreddit }
+func (r *citationResolver) Archived() bool    { return r.citation.Archived }
+
+// consistencyResolver resolves Consistency
+type consistencyResolver struct {
+	report *models.ConsistencyReport
+}
+
+func (r *consistencyResolver) Samples() int32         { return int32(r.report.Samples) }
+func (r *consistencyResolver) Agreement() float64     { return r.report.Agreement }
+func (r *consistencyResolver) Consensus() bool        { return r.report.Consensus }
+func (r *consistencyResolver) Alternatives() []string { return nonNilStrings(r.report.Alternatives) }
+
+// answerErrorResolver resolves AnswerError
+type answerErrorResolver struct {
+	failure *models.ErrorResponse
+}
+
+func (r *answerErrorResolver) Code() string    { return r.failure.Code }
+func (r *answerErrorResolver) Message() string { return r.failure.Error }
+func (r *answerErrorResolver) Retryable() bool { return r.failure.Retryable }
+
+// postResolver resolves Post
+type postResolver struct {
+	thread *models.CommentThread
+}
+
+func (r *postResolver) Post() *resultResolver { return &resultResolver{result: &r.thread.Post} }
+
+func (r *postResolver) Comments() []*commentResolver {
+	return commentResolvers(r.thread.Comments)
+}
+
+func (r *postResolver) MoreComments() int32 {
+	if r.thread.More == nil {
+		return 0
+	}
+	return int32(r.thread.More.Count)
+}
+
+// commentResolver resolves Comment
+type commentResolver struct {
+	comment *models.Comment
+}
+
+func commentResolvers(comments []models.Comment) []*commentResolver {
+	resolvers := make([]*commentResolver, len(comments))
+	for i := range comments {
+		resolvers[i] = &commentResolver{comment: &comments[i]}
+	}
+	return resolvers
+}
+
+func (r *commentResolver) ID() graphql.ID         { return graphql.ID(r.comment.ID) }
+func (r *commentResolver) ParentID() string       { return r.comment.ParentID }
+func (r *commentResolver) Author() string         { return r.comment.Author }
+func (r *commentResolver) Body() string           { return r.comment.Body }
+func (r *commentResolver) Score() int32           { return int32(r.comment.Score) }
+func (r *commentResolver) CreatedUTC() float64    { return float64(r.comment.CreatedUTC) }
+func (r *commentResolver) URL() string            { return r.comment.URL }
+func (r *commentResolver) Depth() int32           { return int32(r.comment.Depth) }
+func (r *commentResolver) IsSubmitter() bool      { return r.comment.IsSubmitter }
+func (r *commentResolver) Distinguished() *string { return optionalString(r.comment.Distinguished) }
+func (r *commentResolver) Stickied() bool         { return r.comment.Stickied }
+
+func (r *commentResolver) Replies() []*commentResolver {
+	return commentResolvers(r.comment.Replies)
+}
+
+func (r *commentResolver) MoreReplies() int32 {
+	if r.comment.More == nil {
+		return 0
+	}
+	return int32(r.comment.More.Count)
+}
+
+// subredditResolver resolves Subreddit from its subreddit result
+type subredditResolver struct {
+	subreddit     *models.SearchResult
+	redditService *services.RedditService
+}
+
+func (r *subredditResolver) Name() string        { return r.subreddit.Subreddit }
+func (r *subredditResolver) Title() string       { return r.subreddit.Title }
+func (r *subredditResolver) Description() string { return r.subreddit.Content }
+func (r *subredditResolver) Subscribers() int32  { return int32(r.subreddit.Score) }
+func (r *subredditResolver) CreatedUTC() float64 { return float64(r.subreddit.CreatedUTC) }
+func (r *subredditResolver) NSFW() bool          { return r.subreddit.NSFW }
+func (r *subredditResolver) URL() string         { return r.subreddit.URL }
+
+// Posts returns one of the community's listings
+func (r *subredditResolver) Posts(ctx context.Context, args struct {
+	Listing string
+	Limit   int32
+}) ([]*resultResolver, error) {
+	listing := args.Listing
+	if !subredditListings[listing] {
+		return nil, fmt.Errorf("listing must be one of hot, new or top")
+	}
+
+	results, err := r.redditService.FetchListing(ctx, r.subreddit.Subreddit, listing,
+		boundedIntArg(args.Limit, defaultGraphQLListLimit, maxGraphQLListLimit))
+	if err != nil {
+		return nil, err
+	}
+	return resultResolvers(results), nil
+}
+
+// userResolver resolves User
+type userResolver struct {
+	user          *models.RedditUser
+	redditService *services.RedditService
+}
+
+func (r *userResolver) Name() string        { return r.user.Name }
+func (r *userResolver) CreatedUTC() float64 { return float64(r.user.CreatedUTC) }
+func (r *userResolver) LinkKarma() int32    { return int32(r.user.LinkKarma) }
+func (r *userResolver) CommentKarma() int32 { return int32(r.user.CommentKarma) }
+func (r *userResolver) Verified() bool      { return r.user.Verified }
+func (r *userResolver) IsModerator() bool   { return r.user.IsModerator }
+func (r *userResolver) Suspended() bool     { return r.user.Suspended }
+
+// Posts returns the user's newest posts
+func (r *userResolver) Posts(ctx context.Context, args struct{ Limit int32 }) ([]*resultResolver, error) {
+	return r.listing(ctx, "submitted", args.Limit)
+}
+
+// Comments returns the user's newest comments
+func (r *userResolver) Comments(ctx context.Context, args struct{ Limit int32 }) ([]*resultResolver, error) {
+	return r.listing(ctx, "comments", args.Limit)
+}
+
+func (r *userResolver) listing(ctx context.Context, listing string, limit int32) ([]*resultResolver, error) {
+	results, err := r.redditService.FetchUserListing(ctx, r.user.Name, listing,
+		boundedIntArg(limit, defaultGraphQLListLimit, maxGraphQLListLimit))
+	if err != nil {
+		return nil, err
+	}
+	return resultResolvers(results), nil
+}
+
+// stringArg returns an optional string argument, or "" when it was omitted
+func stringArg(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// boundedIntArg returns an Int argument, falling back to defaultValue when
+// it is not positive and capping it at max
+func boundedIntArg(value int32, defaultValue, max int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	if int(value) > max {
+		return max
+	}
+	return int(value)
+}
+
+// optionalString maps "" to null
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// optionalFloat maps 0 to null
+func optionalFloat(value float64) *float64 {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
+// nonNilStrings returns an empty list instead of nil for non-null lists
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
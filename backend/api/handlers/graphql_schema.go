@@ -0,0 +1,179 @@
+// File: backend/api/handlers/graphql_schema.go
+
+package handlers
+
+// graphQLSchema describes the GraphQL API. Timestamps are Unix seconds as
+// Float, since GraphQL's Int is only 32 bits.
+const graphQLSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	# Runs a search like POST /api/search
+	search(
+		query: String!
+		mode: String
+		model: String
+		limit: Int
+		subreddits: [String!]
+		# Communities to leave out
+		excludeSubreddits: [String!]
+		# Users to leave out; defaults to bots such as AutoModerator, [] for none
+		excludeAuthors: [String!]
+		answerLanguage: String
+		answerFormat: String
+		# Language tag for relative times, e.g. "de"; defaults to Accept-Language
+		locale: String
+		# IANA timezone for local times, e.g. "America/New_York"
+		timezone: String
+		verify: Boolean = false
+		selfConsistency: Boolean = false
+		# Paraphrase and mask profanity in the answer
+		cleanLanguage: Boolean = false
+		# Drop NSFW results and unsuitable communities, and moderate the answer
+		safeSearch: Boolean = false
+		# Return ranked results without an answer
+		skipAI: Boolean = false
+		# Characters of each result's content the model sees
+		maxContentLength: Int
+		# Key excerpts per result, at most 10
+		highlights: Int
+		# Communities the answer should draw on, at most 5; broadens one-sided results
+		minSubreddits: Int
+		# Where the user is, e.g. "uk" or "nyc"; favors local communities
+		region: String
+		# How strongly to favor newer results: none, low or high
+		recencyBias: String
+		# Drop posts and comments with fewer net upvotes
+		minScore: Int
+		# Drop posts with fewer comments
+		minComments: Int
+	): SearchResponse!
+	# A post and its comment tree
+	post(id: ID!, commentSort: String = "confidence", commentLimit: Int = 50, commentDepth: Int = 5): Post
+	# A community's details and listings
+	subreddit(name: String!): Subreddit
+	# A user's public profile and recent activity
+	user(name: String!): User
+}
+
+type SearchResponse {
+	id: ID
+	answer: String!
+	reasoning: String!
+	results: [Result!]!
+	totalCount: Int!
+	citations: [Citation!]!
+	warnings: [String!]!
+	consistency: Consistency
+	# Set when results were found but the AI answer failed
+	answerError: AnswerError
+	source: String!
+	elapsedTime: Float!
+	lastUpdated: Float!
+	lastUpdatedLocal: String
+}
+
+type Result {
+	id: ID!
+	type: String!
+	title: String!
+	subreddit: String!
+	author: String!
+	content: String!
+	url: String!
+	permalink: String!
+	externalUrl: String
+	createdUtc: Float!
+	createdLocal: String
+	createdAgo: String
+	editedUtc: Float
+	score: Int!
+	commentCount: Int!
+	upvoteRatio: Float
+	relevance: Float
+	highlights: [String!]!
+	flair: String
+	authorFlair: String
+	nsfw: Boolean!
+	distinguished: Boolean!
+	stickied: Boolean!
+	archived: Boolean!
+	locked: Boolean!
+	thumbnailUrl: String
+	previewUrl: String
+}
+
+type Citation {
+	index: Int!
+	text: String!
+	url: String!
+	title: String!
+	type: String!
+	subreddit: String!
+	archived: Boolean!
+}
+
+type Consistency {
+	samples: Int!
+	agreement: Float!
+	consensus: Boolean!
+	alternatives: [String!]!
+}
+
+type AnswerError {
+	# e.g. "ai_rate_limited" or "ai_unavailable"
+	code: String!
+	message: String!
+	retryable: Boolean!
+}
+
+type Post {
+	post: Result!
+	comments: [Comment!]!
+	# Top-level comments Reddit left out
+	moreComments: Int!
+}
+
+type Comment {
+	id: ID!
+	parentId: String!
+	author: String!
+	body: String!
+	score: Int!
+	createdUtc: Float!
+	url: String!
+	depth: Int!
+	isSubmitter: Boolean!
+	distinguished: String
+	stickied: Boolean!
+	replies: [Comment!]!
+	# Replies Reddit left out
+	moreReplies: Int!
+}
+
+type Subreddit {
+	name: String!
+	title: String!
+	description: String!
+	subscribers: Int!
+	createdUtc: Float!
+	nsfw: Boolean!
+	url: String!
+	# listing is hot, new or top (past day)
+	posts(listing: String = "hot", limit: Int = 25): [Result!]!
+}
+
+type User {
+	name: String!
+	createdUtc: Float!
+	linkKarma: Int!
+	commentKarma: Int!
+	verified: Boolean!
+	isModerator: Boolean!
+	suspended: Boolean!
+	posts(limit: Int = 25): [Result!]!
+	comments(limit: Int = 25): [Result!]!
+}
+`
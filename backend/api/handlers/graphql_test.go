@@ -0,0 +1,203 @@
+// File: backend/api/handlers/graphql_test.go
+
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+// graphQLResult is a GraphQL response
+type graphQLResult struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// newTestGraphQL returns a router serving GraphQL over a mock Reddit API
+func newTestGraphQL(t *testing.T) (http.Handler, *redditmock.Server) {
+	t.Helper()
+	search, mock := newTestSearchHandler(t)
+	h := NewGraphQLHandler(search, search.RedditService)
+
+	r := gin.New()
+	r.POST("/api/graphql", h.HandleGraphQL)
+	return r, mock
+}
+
+// queryGraphQL runs query and decodes its data into data
+func queryGraphQL(t *testing.T, r http.Handler, query string, data interface{}) graphQLResult {
+	t.Helper()
+	body, _ := json.Marshal(graphQLRequest{Query: query})
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var result graphQLResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Invalid GraphQL response: %v", err)
+	}
+	if data != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			t.Fatalf("Unexpected data %s: %v", result.Data, err)
+		}
+	}
+	return result
+}
+
+func TestGraphQLSearch(t *testing.T) {
+	r, _ := newTestGraphQL(t)
+
+	var data struct {
+		Search struct {
+			TotalCount int
+			Results    []struct {
+				ID        string
+				Title     string
+				Subreddit string
+				Score     int
+			}
+		}
+	}
+	result := queryGraphQL(t, r, `{ search(query: "go released", mode: "Posts", skipAI: true) { totalCount results { id title subreddit score } } }`, &data)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	if data.Search.TotalCount == 0 || data.Search.Results[0].ID != "go1" || data.Search.Results[0].Subreddit != "golang" {
+		t.Errorf("Expected go1 from r/golang, got %+v", data.Search)
+	}
+
+	// Invalid requests are field errors
+	result = queryGraphQL(t, r, `{ search(query: "") { totalCount } }`, nil)
+	if len(result.Errors) == 0 {
+		t.Error("Expected an error for an empty query")
+	}
+}
+
+func TestGraphQLPost(t *testing.T) {
+	r, mock := newTestGraphQL(t)
+	mock.SetResponse("/comments/go1.json", `[
+	  {"kind": "Listing", "data": {"children": [
+	    {"kind": "t3", "data": {"id": "go1", "title": "Go 1.22 released", "subreddit": "golang", "author": "gopher", "permalink": "/r/golang/comments/go1/go_122_released/"}}
+	  ]}},
+	  {"kind": "Listing", "data": {"children": [
+	    {"kind": "t1", "data": {"id": "c1", "parent_id": "t3_go1", "body": "Range over int!", "author": "fan", "score": 12,
+	      "replies": {"kind": "Listing", "data": {"children": [
+	        {"kind": "t1", "data": {"id": "c2", "parent_id": "t1_c1", "body": "Finally", "depth": 1, "replies": ""}}
+	      ]}}}},
+	    {"kind": "more", "data": {"count": 7, "parent_id": "t3_go1", "children": ["c3"]}}
+	  ]}}
+	]`)
+
+	var data struct {
+		Post struct {
+			Post         struct{ Title string }
+			MoreComments int
+			Comments     []struct {
+				ID      string
+				Body    string
+				Replies []struct{ Body string }
+			}
+		}
+	}
+	result := queryGraphQL(t, r, `{ post(id: "t3_go1") { post { title } moreComments comments { id body replies { body } } } }`, &data)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	post := data.Post
+	if post.Post.Title != "Go 1.22 released" || post.MoreComments != 7 || len(post.Comments) != 1 {
+		t.Fatalf("Unexpected post %+v", post)
+	}
+	if comment := post.Comments[0]; comment.ID != "c1" || len(comment.Replies) != 1 || comment.Replies[0].Body != "Finally" {
+		t.Errorf("Unexpected comment tree %+v", comment)
+	}
+
+	for _, query := range []string{
+		`{ post(id: "not a post!") { moreComments } }`,
+		`{ post(id: "go1", commentSort: "random") { moreComments } }`,
+	} {
+		if result := queryGraphQL(t, r, query, nil); len(result.Errors) == 0 {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
+
+func TestGraphQLSubreddit(t *testing.T) {
+	r, mock := newTestGraphQL(t)
+	mock.SetResponse("/r/golang/about.json", `{"kind": "t5", "data": {"id": "2rc7j", "display_name": "golang", "title": "The Go Programming Language", "public_description": "Ask questions and post articles about Go", "subscribers": 250000}}`)
+	mock.SetResponse("/r/nowhere/about.json", `{"kind": "Listing", "data": {"children": []}}`)
+
+	var data struct {
+		Subreddit struct {
+			Name        string
+			Description string
+			Subscribers int
+			Posts       []struct{ ID string }
+		}
+	}
+	result := queryGraphQL(t, r, `{ subreddit(name: "r/golang") { name description subscribers posts(listing: "top", limit: 5) { id } } }`, &data)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	subreddit := data.Subreddit
+	if subreddit.Name != "golang" || subreddit.Subscribers != 250000 || subreddit.Description == "" {
+		t.Errorf("Unexpected subreddit %+v", subreddit)
+	}
+	if len(subreddit.Posts) != 1 || subreddit.Posts[0].ID != "go1" {
+		t.Errorf("Expected the r/golang listing, got %+v", subreddit.Posts)
+	}
+
+	for _, query := range []string{
+		`{ subreddit(name: "nowhere") { name } }`,
+		`{ subreddit(name: "bad name!") { name } }`,
+		`{ subreddit(name: "golang") { posts(listing: "rising") { id } } }`,
+	} {
+		if result := queryGraphQL(t, r, query, nil); len(result.Errors) == 0 {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
+
+func TestGraphQLUser(t *testing.T) {
+	r, mock := newTestGraphQL(t)
+	mock.SetResponse("/user/gopher/about.json", `{"kind": "t2", "data": {"name": "gopher", "link_karma": 1200, "comment_karma": 3400, "has_verified_email": true}}`)
+	mock.SetResponse("/user/gopher/submitted.json", `{"kind": "Listing", "data": {"children": [
+	  {"kind": "t3", "data": {"id": "go1", "title": "Go 1.22 released", "subreddit": "golang", "author": "gopher", "score": 420}}
+	]}}`)
+
+	var data struct {
+		User struct {
+			Name         string
+			LinkKarma    int
+			CommentKarma int
+			Verified     bool
+			Posts        []struct{ ID, Author string }
+		}
+	}
+	result := queryGraphQL(t, r, `{ user(name: "u/gopher") { name linkKarma commentKarma verified posts(limit: 5) { id author } } }`, &data)
+	if len(result.Errors) > 0 {
+		t.Fatalf("Unexpected errors: %+v", result.Errors)
+	}
+	user := data.User
+	if user.Name != "gopher" || user.LinkKarma != 1200 || user.CommentKarma != 3400 || !user.Verified {
+		t.Errorf("Unexpected user %+v", user)
+	}
+	if len(user.Posts) != 1 || user.Posts[0].ID != "go1" || user.Posts[0].Author != "gopher" {
+		t.Errorf("Expected gopher's post, got %+v", user.Posts)
+	}
+
+	if result := queryGraphQL(t, r, `{ user(name: "no spaces allowed") { name } }`, nil); len(result.Errors) == 0 {
+		t.Error("Expected an error for an invalid username")
+	}
+}
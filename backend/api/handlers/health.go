@@ -0,0 +1,107 @@
+// File: backend/api/handlers/health.go
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// healthProbeTimeout bounds how long dependency probes may take
+const healthProbeTimeout = 5 * time.Second
+
+// HealthHandler reports the status of the service and its dependencies
+type HealthHandler struct {
+	RedditService *services.RedditService
+	AIService     *services.AIService
+	Store         *store.Store
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(redditService *services.RedditService, aiService *services.AIService, dataStore *store.Store) *HealthHandler {
+	return &HealthHandler{
+		RedditService: redditService,
+		AIService:     aiService,
+		Store:         dataStore,
+	}
+}
+
+// healthOperations documents the health route for /api/openapi.json
+var healthOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/health",
+		Tag:     "Operations",
+		Summary: "Check the service and its dependencies",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Description: "Up, or degraded with the failing dependencies listed", Body: models.HealthReport{}},
+			{Status: http.StatusServiceUnavailable, Description: "A critical dependency is down", Body: models.HealthReport{}},
+		},
+	},
+}
+
+// HandleHealth probes Reddit auth, the database, each AI provider and the
+// cache. It responds 503 when a critical dependency is down and 200
+// otherwise, with degraded dependencies listed in the body.
+func (h *HealthHandler) HandleHealth(c *gin.Context) {
+	report := h.report(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == models.HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// report probes the dependencies, within healthProbeTimeout
+func (h *HealthHandler) report(ctx context.Context) models.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	checks := map[string]models.HealthCheck{
+		"reddit_auth": h.RedditService.CheckAuth(ctx),
+		"database":    h.checkDatabase(ctx),
+		"cache":       h.RedditService.CacheHealth(),
+	}
+	for provider, check := range h.AIService.ProviderHealth() {
+		checks["ai_"+provider] = check
+	}
+
+	return models.HealthReport{
+		Status: overallHealth(checks),
+		Time:   time.Now().Format(time.RFC3339),
+		Checks: checks,
+	}
+}
+
+// checkDatabase pings the persistent store
+func (h *HealthHandler) checkDatabase(ctx context.Context) models.HealthCheck {
+	check := models.HealthCheck{Status: models.HealthOK, Critical: true}
+	if err := h.Store.Ping(ctx); err != nil {
+		check.Status = models.HealthDown
+		check.Message = err.Error()
+	}
+	return check
+}
+
+// overallHealth is down when a critical check is down, degraded when any
+// check is not ok, and ok otherwise
+func overallHealth(checks map[string]models.HealthCheck) string {
+	status := models.HealthOK
+	for _, check := range checks {
+		switch {
+		case check.Status == models.HealthDown && check.Critical:
+			return models.HealthDown
+		case check.Status != models.HealthOK:
+			status = models.HealthDegraded
+		}
+	}
+	return status
+}
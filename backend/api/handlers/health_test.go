@@ -0,0 +1,68 @@
+// File: backend/api/handlers/health_test.go
+
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+// getHealth fetches the health report from h
+func getHealth(t *testing.T, h *HealthHandler) (int, models.HealthReport) {
+	t.Helper()
+	r := gin.New()
+	r.GET("/api/health", h.HandleHealth)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	var report models.HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Invalid health report: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestHandleHealth(t *testing.T) {
+	search, mock := newTestSearchHandler(t)
+	h := NewHealthHandler(search.RedditService, services.NewAIService(), search.Store)
+
+	status, report := getHealth(t, h)
+	if status != http.StatusOK || report.Status == models.HealthDown {
+		t.Fatalf("Expected 200 with every critical dependency up, got %d %+v", status, report)
+	}
+	if check := report.Checks["database"]; check.Status != models.HealthOK || !check.Critical {
+		t.Errorf("Expected a critical, ok database check, got %+v", check)
+	}
+
+	// Reddit auth is critical, so failing it takes the service down
+	mock.Fail(redditmock.Failure{Path: "/api/v1/access_token", Status: http.StatusInternalServerError})
+	search.RedditService.SetHTTPClient(mock.Client())
+	status, report = getHealth(t, h)
+	if status != http.StatusServiceUnavailable || report.Status != models.HealthDown {
+		t.Errorf("Expected 503 with Reddit auth down, got %d %+v", status, report)
+	}
+	if check := report.Checks["reddit_auth"]; check.Status != models.HealthDown || check.Message == "" {
+		t.Errorf("Expected the Reddit auth check down with a message, got %+v", check)
+	}
+}
+
+func TestHandleHealthDatabaseDown(t *testing.T) {
+	search, _ := newTestSearchHandler(t)
+	h := NewHealthHandler(search.RedditService, services.NewAIService(), search.Store)
+	search.Store.Close()
+
+	status, report := getHealth(t, h)
+	if status != http.StatusServiceUnavailable || report.Status != models.HealthDown {
+		t.Errorf("Expected 503 with the database down, got %d %+v", status, report)
+	}
+	if check := report.Checks["database"]; check.Status != models.HealthDown {
+		t.Errorf("Expected the database check down, got %+v", check)
+	}
+}
@@ -0,0 +1,137 @@
+// File: backend/api/handlers/history.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	defaultHistoryPageSize = 20
+	maxHistoryPageSize     = 100
+)
+
+// HistoryHandler exposes the caller's search history
+type HistoryHandler struct {
+	Store *store.Store
+}
+
+// NewHistoryHandler creates a new history handler
+func NewHistoryHandler(dataStore *store.Store) *HistoryHandler {
+	return &HistoryHandler{
+		Store: dataStore,
+	}
+}
+
+// historyOperations documents the history routes for /api/openapi.json.
+// History belongs to the caller.
+var historyOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/history",
+		Tag:     "History",
+		Summary: "List search history, newest first",
+		Parameters: []openapi.Parameter{
+			{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Page size, default %d, at most %d", defaultHistoryPageSize, maxHistoryPageSize)},
+			{Name: "offset", In: "query", Type: "integer"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.HistoryPage{}},
+			errorResponse(http.StatusBadRequest, "Invalid limit or offset"),
+		},
+	},
+	{
+		Method:  "DELETE",
+		Path:    "/api/history",
+		Tag:     "History",
+		Summary: "Clear search history",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				Deleted int64 `json:"deleted"`
+			}{}},
+		},
+	},
+	{
+		Method:     "DELETE",
+		Path:       "/api/history/{id}",
+		Tag:        "History",
+		Summary:    "Delete a history entry",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			errorResponse(http.StatusNotFound, "History entry not found"),
+		},
+	},
+}
+
+// HandleList returns one page of the caller's search history, newest first.
+// Pages are selected with the limit and offset query parameters.
+func (h *HistoryHandler) HandleList(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultHistoryPageSize)))
+	if err != nil || limit <= 0 {
+		invalidField(c, "limit", "must be a positive integer")
+		return
+	}
+	if limit > maxHistoryPageSize {
+		limit = maxHistoryPageSize
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		invalidField(c, "offset", "must be a non-negative integer")
+		return
+	}
+
+	entries, total, err := h.Store.ListHistory(c.Request.Context(), clientKey(c), limit, offset)
+	if err != nil {
+		log.Printf("Failed to list search history: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search history", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, models.HistoryPage{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
+
+// HandleDelete removes the history entry :id
+func (h *HistoryHandler) HandleDelete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.Store.DeleteHistoryEntry(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "History entry not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete history entry %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to delete history entry", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleClear removes the caller's entire search history
+func (h *HistoryHandler) HandleClear(c *gin.Context) {
+	deleted, err := h.Store.ClearHistory(c.Request.Context(), clientKey(c))
+	if err != nil {
+		log.Printf("Failed to clear search history: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to clear search history", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
@@ -0,0 +1,40 @@
+// File: backend/api/handlers/models.go
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// ModelsHandler describes the AI models clients can select
+type ModelsHandler struct {
+	AIService *services.AIService
+}
+
+// NewModelsHandler creates a new models handler
+func NewModelsHandler(aiService *services.AIService) *ModelsHandler {
+	return &ModelsHandler{
+		AIService: aiService,
+	}
+}
+
+// modelsOperations documents the model catalog route for /api/openapi.json
+var modelsOperations = []openapi.Operation{
+	{
+		Method:    "GET",
+		Path:      "/api/models",
+		Tag:       "Models",
+		Summary:   "List the AI models clients can select",
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: models.ModelCatalog{}}},
+	},
+}
+
+// HandleList returns the model catalog
+func (h *ModelsHandler) HandleList(c *gin.Context) {
+	c.JSON(http.StatusOK, h.AIService.Catalog())
+}
@@ -0,0 +1,125 @@
+// File: backend/api/handlers/openapi.go
+
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+)
+
+// APIVersion is the current API version. Its routes are served under
+// /api/v1, and the unversioned /api paths are aliases of them.
+const APIVersion = "v1"
+
+// apiInfo describes the API in the OpenAPI document
+var apiInfo = openapi.Info{
+	Title:       "Subplexity API",
+	Version:     "1.0.0",
+	Description: "Search Reddit and get AI answers with citations. Callers may send an X-API-Key header to keep their history and saved searches separate from others on the same IP address, or sign in to an account and send \"Authorization: Bearer <token>\" to keep them with the account. Routes are versioned under /api/v1; the unversioned /api paths are aliases kept for existing clients. Within a version, responses only gain optional fields, so clients should ignore fields they don't know. Timestamps are Unix seconds; add ?timestamps=rfc3339 or an \"Accept-Profile: rfc3339\" header to get RFC3339 strings instead. Errors have a machine-readable code, such as reddit_rate_limited or ai_unavailable, a retryable flag and the request's X-Request-ID. When quotas are enabled, search responses report the caller's remaining daily and monthly quota in X-Quota-Remaining-* headers. Quotas and concurrency limits follow the account, or an API key the operator issued; other callers are limited by IP address.",
+}
+
+// docsOperations documents the documentation routes themselves
+var docsOperations = []openapi.Operation{
+	{
+		Method:    "GET",
+		Path:      "/api/openapi.json",
+		Tag:       "Operations",
+		Summary:   "This OpenAPI document",
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: map[string]interface{}{}}},
+	},
+	{
+		Method:    "GET",
+		Path:      "/api/docs",
+		Tag:       "Operations",
+		Summary:   "Interactive API documentation",
+		Responses: []openapi.Response{{Status: http.StatusOK, Body: "", ContentType: "text/html"}},
+	},
+}
+
+// apiOperations lists every documented route, under the versioned paths.
+// Add a handler's operations here when registering its routes.
+func apiOperations() []openapi.Operation {
+	groups := [][]openapi.Operation{
+		searchOperations,
+		answerOperations,
+		suggestOperations,
+		exportOperations,
+		shareOperations,
+		savedSearchOperations,
+		bookmarkOperations,
+		collectionOperations,
+		authOperations,
+		redditLoginOperations,
+		historyOperations,
+		annotationOperations,
+		feedbackOperations,
+		modelsOperations,
+		trendingOperations,
+		commentsOperations,
+		graphQLOperations,
+		digestOperations,
+		telegramOperations,
+		adminOperations,
+		analyticsOperations,
+		dashboardOperations,
+		healthOperations,
+		docsOperations,
+	}
+
+	var operations []openapi.Operation
+	for _, group := range groups {
+		for _, op := range group {
+			op.Path = "/api/" + APIVersion + strings.TrimPrefix(op.Path, "/api")
+			operations = append(operations, op)
+		}
+	}
+	return operations
+}
+
+// swaggerUIPage renders the OpenAPI document with Swagger UI from a CDN
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Subplexity API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+<script>
+window.onload = function () {
+  window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+};
+</script>
+</body>
+</html>
+`
+
+// OpenAPIHandler serves the OpenAPI document and Swagger UI
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler builds the OpenAPI document from the handlers'
+// operation annotations
+func NewOpenAPIHandler() (*OpenAPIHandler, error) {
+	spec, err := openapi.Build(apiInfo, apiOperations())
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAPIHandler{spec: spec}, nil
+}
+
+// HandleSpec returns the OpenAPI document
+func (h *OpenAPIHandler) HandleSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// HandleDocs returns Swagger UI for the OpenAPI document
+func (h *OpenAPIHandler) HandleDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
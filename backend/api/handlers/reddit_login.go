@@ -0,0 +1,170 @@
+// File: backend/api/handlers/reddit_login.go
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/redditlogin"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// redditStateCookie ties a Reddit login to the browser that started it
+const redditStateCookie = "reddit_login_state"
+
+// RedditLoginHandler signs people in with their Reddit accounts
+type RedditLoginHandler struct {
+	Login    *redditlogin.Service
+	Sessions *auth.Sessions
+	cfg      config.RedditLoginConfig
+}
+
+// NewRedditLoginHandler creates a Reddit login handler. Without a login
+// service, signing in with Reddit is disabled and its routes respond with 404.
+func NewRedditLoginHandler(login *redditlogin.Service, sessions *auth.Sessions, cfg config.RedditLoginConfig) *RedditLoginHandler {
+	return &RedditLoginHandler{
+		Login:    login,
+		Sessions: sessions,
+		cfg:      cfg,
+	}
+}
+
+// redditLoginOperations documents the Reddit login routes for /api/openapi.json
+var redditLoginOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/auth/reddit/login",
+		Tag:         "Accounts",
+		Summary:     "Sign in with Reddit",
+		Description: "Redirects the browser to Reddit to approve the login, which returns to /api/auth/reddit/callback. Signed-in Reddit users can search their subscriptions, saved posts and multireddits with redditScope.",
+		Responses: []openapi.Response{
+			{Status: http.StatusFound, Description: "Redirect to Reddit"},
+			errorResponse(http.StatusNotFound, "Signing in with Reddit is disabled"),
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/auth/reddit/callback",
+		Tag:         "Accounts",
+		Summary:     "Finish signing in with Reddit",
+		Description: "Reddit redirects here once the user decides. The first login creates an account. When the server has an after-login page, the browser is redirected there with \"#token=...&expiresAt=...\", or \"#error=<code>\" if the login failed; otherwise the session is the response.",
+		Parameters: []openapi.Parameter{
+			{Name: "code", In: "query", Description: "Authorization code from Reddit"},
+			{Name: "state", In: "query", Description: "State issued by /api/auth/reddit/login"},
+			{Name: "error", In: "query", Description: "Set by Reddit when the user declined"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.Session{}},
+			{Status: http.StatusFound, Description: "Redirect to the after-login page"},
+			errorResponse(http.StatusBadRequest, "The login expired or was started in another browser"),
+			errorResponse(http.StatusForbidden, "The user declined, or Reddit refused the code"),
+			errorResponse(http.StatusNotFound, "Signing in with Reddit is disabled"),
+			errorResponse(http.StatusBadGateway, "Reddit failed; see code"),
+		},
+	},
+}
+
+// HandleLogin starts a Reddit login
+func (h *RedditLoginHandler) HandleLogin(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	state, nonce, err := h.Login.State()
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to start Reddit login", err.Error())
+		return
+	}
+	h.setStateCookie(c, nonce, int(redditlogin.StateTTL.Seconds()))
+	c.Redirect(http.StatusFound, h.Login.AuthorizeURL(state))
+}
+
+// HandleCallback finishes a Reddit login and signs the account in
+func (h *RedditLoginHandler) HandleCallback(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	nonce, _ := c.Cookie(redditStateCookie)
+	h.setStateCookie(c, "", -1)
+	if err := h.Login.CheckState(c.Query("state"), nonce); err != nil {
+		h.fail(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Reddit login expired or was started in another browser; sign in again", err.Error())
+		return
+	}
+	if reason := c.Query("error"); reason != "" {
+		h.fail(c, http.StatusForbidden, models.ErrorCodeUnauthorized, "Reddit login was not approved", reason)
+		return
+	}
+
+	account, err := h.Login.SignIn(c.Request.Context(), c.Query("code"))
+	if err != nil {
+		code := services.ErrorCode(err)
+		if errors.Is(err, services.ErrRedditLoginRequired) {
+			h.fail(c, http.StatusForbidden, models.ErrorCodeUnauthorized, "Reddit refused the login", err.Error())
+			return
+		}
+		if h.cfg.AfterLoginURL != "" {
+			log.Printf("Reddit login failed (%s): %v", code, err)
+			h.redirectAfterLogin(c, url.Values{"error": {code}})
+			return
+		}
+		writeUpstreamError(c, "Reddit login failed", err)
+		return
+	}
+
+	token, expires, err := h.Sessions.Issue(account.ID)
+	if err != nil {
+		h.fail(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to sign in", err.Error())
+		return
+	}
+	if h.cfg.AfterLoginURL != "" {
+		h.redirectAfterLogin(c, url.Values{"token": {token}, "expiresAt": {strconv.FormatInt(expires.Unix(), 10)}})
+		return
+	}
+	c.JSON(http.StatusOK, models.Session{Token: token, ExpiresAt: expires.Unix(), Account: *account})
+}
+
+// enabled responds with 404 when signing in with Reddit is disabled
+func (h *RedditLoginHandler) enabled(c *gin.Context) bool {
+	if h.Login == nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeDisabled, "Signing in with Reddit is not enabled", "")
+		return false
+	}
+	return true
+}
+
+// fail reports a failed login on the after-login page when there is one,
+// and as an error response otherwise
+func (h *RedditLoginHandler) fail(c *gin.Context, status int, code, message, details string) {
+	if h.cfg.AfterLoginURL == "" {
+		writeError(c, status, code, message, details)
+		return
+	}
+	log.Printf("%s (%s): %s", message, code, details)
+	h.redirectAfterLogin(c, url.Values{"error": {code}})
+}
+
+// redirectAfterLogin sends the browser to the after-login page with values
+// in the fragment, which browsers don't send to servers or log
+func (h *RedditLoginHandler) redirectAfterLogin(c *gin.Context, values url.Values) {
+	page, _, _ := strings.Cut(h.cfg.AfterLoginURL, "#")
+	c.Redirect(http.StatusFound, page+"#"+values.Encode())
+}
+
+// setStateCookie sets the login nonce cookie, or clears it with maxAge -1
+func (h *RedditLoginHandler) setStateCookie(c *gin.Context, nonce string, maxAge int) {
+	// Lax cookies are sent on the top-level redirect back from Reddit
+	c.SetSameSite(http.SameSiteLaxMode)
+	secure := strings.HasPrefix(h.cfg.RedirectURL, "https://")
+	c.SetCookie(redditStateCookie, nonce, maxAge, "/api", "", secure, true)
+}
@@ -0,0 +1,271 @@
+// File: backend/api/handlers/saved_searches.go
+
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// SavedSearchHandler manages named queries and re-runs them on demand
+type SavedSearchHandler struct {
+	Store  *store.Store
+	Search *SearchHandler
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(dataStore *store.Store, searchHandler *SearchHandler) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		Store:  dataStore,
+		Search: searchHandler,
+	}
+}
+
+// savedSearchOperations documents the saved search routes for
+// /api/openapi.json. Saved searches belong to the caller.
+var savedSearchOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/saved-searches",
+		Tag:     "Saved searches",
+		Summary: "List saved searches",
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: struct {
+				SavedSearches []models.SavedSearch `json:"savedSearches"`
+			}{}},
+		},
+	},
+	{
+		Method:  "POST",
+		Path:    "/api/saved-searches",
+		Tag:     "Saved searches",
+		Summary: "Save a search",
+		Request: models.SavedSearchRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.SavedSearch{}},
+			validationErrorResponse,
+		},
+	},
+	{
+		Method:     "GET",
+		Path:       "/api/saved-searches/{id}",
+		Tag:        "Saved searches",
+		Summary:    "Get a saved search",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SavedSearch{}},
+			errorResponse(http.StatusNotFound, "Saved search not found"),
+		},
+	},
+	{
+		Method:     "PUT",
+		Path:       "/api/saved-searches/{id}",
+		Tag:        "Saved searches",
+		Summary:    "Update a saved search",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Request:    models.SavedSearchRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SavedSearch{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Saved search not found"),
+		},
+	},
+	{
+		Method:     "DELETE",
+		Path:       "/api/saved-searches/{id}",
+		Tag:        "Saved searches",
+		Summary:    "Delete a saved search",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusNoContent},
+			errorResponse(http.StatusNotFound, "Saved search not found"),
+		},
+	},
+	{
+		Method:     "POST",
+		Path:       "/api/saved-searches/{id}/run",
+		Tag:        "Saved searches",
+		Summary:    "Run a saved search",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SearchResponse{}},
+			errorResponse(http.StatusNotFound, "Saved search not found"),
+			clientBusyResponse,
+			errorResponse(http.StatusServiceUnavailable, "Server is busy or rate limited upstream; retry after the Retry-After header"),
+		},
+	},
+}
+
+// HandleList returns the caller's saved searches
+func (h *SavedSearchHandler) HandleList(c *gin.Context) {
+	searches, err := h.Store.ListSavedSearches(c.Request.Context(), clientKey(c))
+	if err != nil {
+		log.Printf("Failed to list saved searches: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to list saved searches", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"savedSearches": searches})
+}
+
+// HandleCreate stores a new saved search
+func (h *SavedSearchHandler) HandleCreate(c *gin.Context) {
+	search, ok := h.bindSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.CreateSavedSearch(c.Request.Context(), clientKey(c), search); err != nil {
+		log.Printf("Failed to create saved search: %v", err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create saved search", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// HandleGet returns the saved search :id
+func (h *SavedSearchHandler) HandleGet(c *gin.Context) {
+	search, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// HandleUpdate replaces the name and parameters of the saved search :id
+func (h *SavedSearchHandler) HandleUpdate(c *gin.Context) {
+	existing, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	search, ok := h.bindSavedSearch(c)
+	if !ok {
+		return
+	}
+	search.ID = existing.ID
+	search.CreatedAt = existing.CreatedAt
+
+	err := h.Store.UpdateSavedSearch(c.Request.Context(), clientKey(c), search)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Saved search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to update saved search %s: %v", search.ID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to update saved search", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, search)
+}
+
+// HandleDelete removes the saved search :id
+func (h *SavedSearchHandler) HandleDelete(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.Store.DeleteSavedSearch(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Saved search not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to delete saved search %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to delete saved search", err.Error())
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleRun runs the saved search :id through the search pipeline
+func (h *SavedSearchHandler) HandleRun(c *gin.Context) {
+	search, ok := h.loadSavedSearch(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	log.Printf("Running saved search %s: Query='%s'", search.ID, search.Query)
+
+	response, err := h.Search.runSearch(ctx, clientKey(c), search.SearchRequest())
+	if err != nil {
+		writeServiceError(c, "Saved search failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// loadSavedSearch fetches the caller's saved search :id, writing an error
+// response and returning false if it can't be loaded
+func (h *SavedSearchHandler) loadSavedSearch(c *gin.Context) (*models.SavedSearch, bool) {
+	id := c.Param("id")
+
+	search, err := h.Store.GetSavedSearch(c.Request.Context(), clientKey(c), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Saved search not found", "")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load saved search %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load saved search", err.Error())
+		return nil, false
+	}
+
+	return search, true
+}
+
+// bindSavedSearch parses and validates a saved search payload, writing an
+// error response and returning false if it is invalid
+func (h *SavedSearchHandler) bindSavedSearch(c *gin.Context) (*models.SavedSearch, bool) {
+	var req models.SavedSearchRequest
+	if !bindJSON(c, &req) {
+		return nil, false
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		writeValidationError(c, &services.ValidationError{Fields: []models.FieldError{
+			{Field: "name", Message: "saved search name cannot be empty"},
+		}})
+		return nil, false
+	}
+
+	// Store the parameters exactly as they will run
+	searchReq := models.SearchRequest{
+		Query:      req.Query,
+		SearchMode: req.SearchMode,
+		ModelName:  req.ModelName,
+		Limit:      req.Limit,
+		Subreddits: req.Subreddits,
+	}
+	if err := h.Search.Pipeline.Validate(&searchReq); err != nil {
+		writeValidationError(c, err)
+		return nil, false
+	}
+	services.NormalizeRequest(&searchReq)
+
+	return &models.SavedSearch{
+		Name:       req.Name,
+		Query:      searchReq.Query,
+		SearchMode: searchReq.SearchMode,
+		ModelName:  searchReq.ModelName,
+		Limit:      searchReq.Limit,
+		Subreddits: searchReq.Subreddits,
+	}, true
+}
@@ -4,29 +4,117 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/api/middleware"
 	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/pending"
+	"github.com/pranesh-j/subplexity/internal/redditlogin"
 	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
 )
 
 type SearchHandler struct {
 	RedditService *services.RedditService
 	AIService     *services.AIService
-	initialized   bool
+	Pipeline      *services.SearchPipeline
+	Store         *store.Store
+	// Answers holds the answers to searches that returned their results
+	// first
+	Answers *pending.Answers
+	// RedditLogin provides the Reddit users searches with a RedditScope run
+	// as; nil when signing in with Reddit is disabled
+	RedditLogin *redditlogin.Service
+	initialized bool
 }
 
-func NewSearchHandler(redditService *services.RedditService, aiService *services.AIService) *SearchHandler {
+func NewSearchHandler(redditService *services.RedditService, aiService *services.AIService, dataStore *store.Store) *SearchHandler {
 	return &SearchHandler{
 		RedditService: redditService,
 		AIService:     aiService,
+		Pipeline:      services.NewSearchPipeline(redditService, aiService),
+		Store:         dataStore,
+		Answers:       pending.New(pendingAnswerRetention),
 	}
 }
 
+// searchOperations documents the search routes for /api/openapi.json
+var searchOperations = []openapi.Operation{
+	{
+		Method:      "POST",
+		Path:        "/api/search",
+		Tag:         "Search",
+		Summary:     "Search Reddit and answer the query",
+		Description: "Retrieves posts, comments and communities for the query, then generates an answer with citations. The response is saved as a snapshot whose ID can be exported, and recorded in the caller's history. With deferAnswer, the results are returned as soon as they are retrieved, with a pendingAnswerId for fetching the answer from GET /api/search/answers/{id}.",
+		Request:     models.SearchRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SearchResponse{}},
+			validationErrorResponse,
+			errorResponse(http.StatusRequestEntityTooLarge, "Request body too large"),
+			errorResponse(http.StatusForbidden, "redditScope needs an account signed in with Reddit (reddit_login_required)"),
+			clientBusyResponse,
+			errorResponse(http.StatusBadGateway, "Reddit or the AI provider failed; see code"),
+			errorResponse(http.StatusServiceUnavailable, "Server is busy or rate limited upstream; retry after the Retry-After header"),
+			errorResponse(http.StatusGatewayTimeout, "Search timed out"),
+			errorResponse(http.StatusInternalServerError, "Search failed"),
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/search",
+		Tag:         "Search",
+		Summary:     "Search Reddit without an answer",
+		Description: "Returns the ranked, highlighted results for the query without calling an AI model, like POST /api/search with skipAI. Cheaper and faster for clients that only want retrieval.",
+		Parameters: []openapi.Parameter{
+			{Name: "q", In: "query", Required: true, Description: "The search query"},
+			{Name: "mode", In: "query", Description: "Search mode, e.g. Posts or Comments; default All"},
+			{Name: "limit", In: "query", Type: "integer", Description: "Maximum number of results"},
+			{Name: "subreddits", In: "query", Description: "Comma-separated communities to restrict the search to"},
+			{Name: "excludeSubreddits", In: "query", Description: "Comma-separated communities to leave out"},
+			{Name: "region", In: "query", Description: "Where the user is, e.g. uk or nyc; also searches and favors local communities"},
+			{Name: "excludeAuthors", In: "query", Description: "Comma-separated users to leave out; default AutoModerator, empty for none"},
+			{Name: "timezone", In: "query", Description: "IANA timezone for local times, e.g. America/New_York"},
+			{Name: "highlights", In: "query", Type: "integer", Description: "Key excerpts per result, at most 10"},
+			{Name: "minSubreddits", In: "query", Type: "integer", Description: "Communities the top results should come from, at most 5; broadens one-sided results"},
+			{Name: "recencyBias", In: "query", Description: "How strongly to favor newer results: none, low or high; default favors them for time-sensitive queries"},
+			{Name: "minScore", In: "query", Type: "integer", Description: "Drop posts and comments with fewer net upvotes"},
+			{Name: "minComments", In: "query", Type: "integer", Description: "Drop posts with fewer comments"},
+			{Name: "includeRaw", In: "query", Type: "boolean", Description: "Attach the JSON Reddit returned for each result; ignored with safeSearch or a content policy that rewrites results"},
+			{Name: "safeSearch", In: "query", Type: "boolean", Description: "Drop NSFW results and unsuitable communities, and moderate results"},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SearchResponse{}},
+			validationErrorResponse,
+			clientBusyResponse,
+			errorResponse(http.StatusBadGateway, "Reddit request failed; see code"),
+			errorResponse(http.StatusServiceUnavailable, "Server is busy or rate limited upstream; retry after the Retry-After header"),
+			errorResponse(http.StatusGatewayTimeout, "Search timed out"),
+			errorResponse(http.StatusInternalServerError, "Search failed"),
+		},
+	},
+	{
+		Method:      "POST",
+		Path:        "/api/search/batch",
+		Tag:         "Search",
+		Summary:     "Run several searches at once",
+		Description: fmt.Sprintf("Runs up to %d searches under a shared time budget and reports the outcome of each. Each query counts against the caller's quota, and at most %d run at once, fewer when the caller's other searches hold its concurrency slots.", maxBatchQueries, batchConcurrency),
+		Request:     models.BatchSearchRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.BatchSearchResponse{}},
+			validationErrorResponse,
+			errorResponse(http.StatusRequestEntityTooLarge, "Request body too large"),
+			clientBusyResponse,
+		},
+	},
+}
+
 // Init initializes the handler (like warming up connections)
 func (h *SearchHandler) Init(ctx context.Context) error {
 	if h.initialized {
@@ -44,148 +132,186 @@ func (h *SearchHandler) HandleSearch(c *gin.Context) {
 	defer cancel()
 
 	var req models.SearchRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("Invalid request payload: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid request payload", 
-			"details": err.Error(),
-		})
+	if !bindJSON(c, &req) {
 		return
 	}
-
-	// Validate request
-	if req.Query == "" {
-		log.Println("Search query cannot be empty")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query cannot be empty"})
+	if err := h.Pipeline.Validate(&req); err != nil {
+		writeValidationError(c, err)
 		return
 	}
+	if req.Locale == "" {
+		req.Locale = requestLocale(c)
+	}
 
 	// Log the incoming request
 	log.Printf("Search request: Query='%s', Mode='%s', Model='%s', Limit=%d", 
 		req.Query, req.SearchMode, req.ModelName, req.Limit)
 
-	// Set default limit and search mode if not provided
-	if req.Limit <= 0 {
-		req.Limit = 25
-	}
-	if req.Limit > 100 {
-		req.Limit = 100 // Cap the maximum limit
-	}
-	if req.SearchMode == "" {
-		req.SearchMode = "All" // Default search mode
-	}
-	if req.ModelName == "" {
-		req.ModelName = "Claude" // Default AI model
+	// Return the results first, leaving the answer to a follow-up request
+	if req.DeferAnswer && !req.SkipAI {
+		h.deferAnswer(c, req)
+		return
 	}
 
-	// Measure execution time
-	startTime := time.Now()
-
-	// Search Reddit with timeout
-	results, err := h.RedditService.SearchReddit(ctx, req.Query, req.SearchMode, req.Limit)
+	// Run the search pipeline
+	response, err := h.runSearch(ctx, clientKey(c), req)
 	if err != nil {
-		log.Printf("Failed to search Reddit: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to search Reddit", 
-			"details": err.Error(),
-		})
+		writeServiceError(c, "Search failed", err)
 		return
 	}
 
-	// If no results were found, return an empty response with explanation
-	if len(results) == 0 {
-		log.Println("No search results found")
-		c.JSON(http.StatusOK, models.SearchResponse{
-			Results:     []models.SearchResult{},
-			TotalCount:  0,
-			Reasoning:   "No results found for your query.",
-			Answer:      "There are no Reddit results matching your search criteria. Please try a different query or search mode.",
-			ElapsedTime: time.Since(startTime).Seconds(),
-			LastUpdated: time.Now().Unix(),
-			RequestParams: models.RequestParams{
-				Query:      req.Query,
-				SearchMode: req.SearchMode,
-				ModelName:  req.ModelName,
-				Limit:      req.Limit,
-			},
-		})
-		return
-	}
+	c.JSON(http.StatusOK, response)
+}
 
-	// Filter out completely irrelevant results based on the query terms
-	var relevantResults []models.SearchResult
-	
-	// Extract main keywords from the query
-	queryWords := strings.Fields(strings.ToLower(req.Query))
-	
-	// Filter out common stop words
-	var queryKeywords []string
-	for _, word := range queryWords {
-		// Only keep meaningful words (longer than 2 chars)
-		if len(word) > 2 {
-			queryKeywords = append(queryKeywords, word)
+// HandleSearchResults runs a retrieval-only search from query parameters,
+// returning ranked results without an answer
+func (h *SearchHandler) HandleSearchResults(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	req := models.SearchRequest{
+		Query:      c.Query("q"),
+		SearchMode: c.Query("mode"),
+		Timezone:    c.Query("timezone"),
+		Locale:      requestLocale(c),
+		RecencyBias: c.Query("recencyBias"),
+		Region:      c.Query("region"),
+		SkipAI:      true,
+		IncludeRaw:  c.Query("includeRaw") == "true",
+		SafeSearch:  c.Query("safeSearch") == "true",
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "limit must be a positive integer", "")
+			return
 		}
+		req.Limit = n
 	}
-	
-	// Only perform filtering if we have meaningful keywords
-	if len(queryKeywords) > 0 {
-		for _, result := range results {
-			resultText := strings.ToLower(result.Title + " " + result.Content)
-			
-			// Check if any main query terms are present
-			for _, term := range queryKeywords {
-				if strings.Contains(resultText, term) {
-					relevantResults = append(relevantResults, result)
-					break
-				}
-			}
+	if highlights := c.Query("highlights"); highlights != "" {
+		n, err := strconv.Atoi(highlights)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "highlights must be an integer", "")
+			return
 		}
-		
-		// Only use filtered results if we found some
-		if len(relevantResults) > 0 {
-			log.Printf("Filtered results from %d to %d relevant items based on query keywords", 
-				len(results), len(relevantResults))
-			results = relevantResults
+		req.Highlights = n
+	}
+	if minSubreddits := c.Query("minSubreddits"); minSubreddits != "" {
+		n, err := strconv.Atoi(minSubreddits)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "minSubreddits must be an integer", "")
+			return
 		}
+		req.MinSubreddits = n
 	}
-
-	// Process results with AI (with error handling)
-	var reasoning, answer string
-	var reasoningSteps []models.ReasoningStep
-	var citations []models.Citation
-	aiErr := error(nil)
-	
-	// Only attempt AI processing if we have at least one result
-	if len(results) > 0 {
-		reasoning, answer, reasoningSteps, citations, aiErr = h.AIService.ProcessResults(ctx, req.Query, results, req.ModelName)
-		if aiErr != nil {
-			log.Printf("AI processing error: %v", aiErr)
-			// Still continue - we'll return the raw results
-			reasoning = "AI processing failed: " + aiErr.Error()
-			answer = "The search found results, but AI analysis couldn't be completed. The raw results are still available."
+	if minScore := c.Query("minScore"); minScore != "" {
+		n, err := strconv.Atoi(minScore)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "minScore must be an integer", "")
+			return
+		}
+		req.MinScore = n
+	}
+	if minComments := c.Query("minComments"); minComments != "" {
+		n, err := strconv.Atoi(minComments)
+		if err != nil {
+			writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "minComments must be an integer", "")
+			return
 		}
+		req.MinComments = n
+	}
+	if subreddits := c.Query("subreddits"); subreddits != "" {
+		req.Subreddits = strings.Split(subreddits, ",")
+	}
+	if excluded := c.Query("excludeSubreddits"); excluded != "" {
+		req.ExcludeSubreddits = strings.Split(excluded, ",")
+	}
+	// An empty excludeAuthors leaves out no one, not even the default bots
+	if authors, ok := c.GetQuery("excludeAuthors"); ok {
+		req.ExcludeAuthors = []string{}
+		if authors != "" {
+			req.ExcludeAuthors = strings.Split(authors, ",")
+		}
+	}
+	if err := h.Pipeline.Validate(&req); err != nil {
+		writeValidationError(c, err)
+		return
 	}
 
-	elapsedTime := time.Since(startTime).Seconds()
-	log.Printf("Search completed in %.2f seconds, found %d results", elapsedTime, len(results))
-
-	// Prepare response
-	response := models.SearchResponse{
-		Results:        results,
-		TotalCount:     len(results),
-		Reasoning:      reasoning,
-		ReasoningSteps: reasoningSteps,
-		Answer:         answer,
-		Citations:      citations,
-		ElapsedTime:    elapsedTime,
-		LastUpdated:    time.Now().Unix(),
-		RequestParams: models.RequestParams{
-			Query:      req.Query,
-			SearchMode: req.SearchMode,
-			ModelName:  req.ModelName,
-			Limit:      req.Limit,
-		},
+	response, err := h.runSearch(ctx, clientKey(c), req)
+	if err != nil {
+		writeServiceError(c, "Search failed", err)
+		return
 	}
 
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// runSearch runs the search pipeline, persists the response so it can be
+// exported and referenced later, and records it in owner's history
+func (h *SearchHandler) runSearch(ctx context.Context, owner string, req models.SearchRequest) (*models.SearchResponse, error) {
+	return h.runSearchStream(ctx, owner, req, nil)
+}
+
+// runSearchStream is runSearch, calling onResults with the results before
+// the answer is generated
+func (h *SearchHandler) runSearchStream(ctx context.Context, owner string, req models.SearchRequest, onResults services.ResultsFunc) (*models.SearchResponse, error) {
+	if req.RedditScope != "" {
+		var err error
+		if ctx, err = h.withRedditUser(ctx, owner); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cacheTracker := services.WithCacheTracker(ctx)
+	response, err := h.Pipeline.RunStream(ctx, req, onResults)
+	if err != nil {
+		return nil, err
+	}
+
+	h.saveSnapshot(ctx, response)
+	h.recordHistory(ctx, owner, response, cacheTracker)
+	return response, nil
+}
+
+// withRedditUser adds the Reddit user owner signed in as to ctx, for
+// searches of their own content. Other owners get
+// services.ErrRedditLoginRequired.
+func (h *SearchHandler) withRedditUser(ctx context.Context, owner string) (context.Context, error) {
+	accountID, ok := strings.CutPrefix(owner, middleware.AccountKeyPrefix)
+	if !ok || h.RedditLogin == nil {
+		return nil, services.ErrRedditLoginRequired
+	}
+	user, err := h.RedditLogin.User(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return services.WithRedditUser(ctx, user), nil
+}
+
+// saveSnapshot persists a search response, assigning its ID. Failures are
+// logged but don't fail the search itself.
+func (h *SearchHandler) saveSnapshot(ctx context.Context, response *models.SearchResponse) {
+	if _, err := h.Store.SaveSnapshot(ctx, response); err != nil {
+		log.Printf("Failed to save search snapshot: %v", err)
+	}
+}
+
+// recordHistory adds a completed search to owner's history, noting whether
+// cacheTracker saw its Reddit results served from cache. Failures are
+// logged but don't fail the search itself.
+func (h *SearchHandler) recordHistory(ctx context.Context, owner string, response *models.SearchResponse, cacheTracker *services.CacheTracker) {
+	entry := &models.HistoryEntry{
+		Query:       response.RequestParams.Query,
+		Params:      response.RequestParams,
+		ElapsedTime: response.ElapsedTime,
+		ResultCount: response.TotalCount,
+		SnapshotID:  response.ID,
+	}
+	if hit, ok := cacheTracker.Hit(); ok {
+		entry.CacheHit = &hit
+	}
+	if err := h.Store.AddHistoryEntry(ctx, owner, entry); err != nil {
+		log.Printf("Failed to record search history: %v", err)
+	}
+}
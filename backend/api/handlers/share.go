@@ -0,0 +1,176 @@
+// File: backend/api/handlers/share.go
+
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// ShareHandler hands out signed, expiring public links to answers
+type ShareHandler struct {
+	Store *store.Store
+	Links *auth.Links
+	cfg   config.SharingConfig
+}
+
+// NewShareHandler creates a share handler. Without a link signer, sharing
+// is disabled and its routes respond with 404.
+func NewShareHandler(dataStore *store.Store, links *auth.Links, cfg config.SharingConfig) *ShareHandler {
+	return &ShareHandler{
+		Store: dataStore,
+		Links: links,
+		cfg:   cfg,
+	}
+}
+
+// shareOperations documents the share routes for /api/openapi.json
+var shareOperations = []openapi.Operation{
+	{
+		Method:      "POST",
+		Path:        "/api/share",
+		Tag:         "Snapshots",
+		Summary:     "Create a public link to an answer",
+		Description: "Returns a signed link to the search's snapshot that anyone can open until it expires. Links can't be revoked individually.",
+		Request:     models.ShareRequest{},
+		Responses: []openapi.Response{
+			{Status: http.StatusCreated, Body: models.Share{}},
+			validationErrorResponse,
+			errorResponse(http.StatusNotFound, "Snapshot not found, or sharing is disabled"),
+		},
+	},
+	{
+		Method:      "GET",
+		Path:        "/api/share/{token}",
+		Tag:         "Snapshots",
+		Summary:     "Open a shared answer",
+		Description: "Returns the snapshot without its ID, so the link's expiry can't be sidestepped through the snapshot routes.",
+		Parameters:  []openapi.Parameter{{Name: "token", In: "path", Description: "Token from Share.token"}},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SearchResponse{}},
+			errorResponse(http.StatusNotFound, "Invalid link, or sharing is disabled"),
+			errorResponse(http.StatusGone, "The link has expired"),
+		},
+	},
+}
+
+// HandleCreate signs a link to a snapshot
+func (h *ShareHandler) HandleCreate(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	var req models.ShareRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	req.SnapshotID = strings.TrimSpace(req.SnapshotID)
+	if req.SnapshotID == "" {
+		invalidField(c, "snapshotId", "is required")
+		return
+	}
+	ttl := h.cfg.DefaultTTL
+	if req.ExpiresIn != 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+		if ttl < time.Minute || ttl > h.cfg.MaxTTL {
+			invalidField(c, "expiresIn", fmt.Sprintf("must be between 60 and %d seconds, got %d", int64(h.cfg.MaxTTL.Seconds()), req.ExpiresIn))
+			return
+		}
+	}
+
+	if _, err := h.Store.GetSnapshot(c.Request.Context(), req.SnapshotID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search snapshot not found", "")
+			return
+		}
+		log.Printf("Failed to load snapshot %s: %v", req.SnapshotID, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search snapshot", err.Error())
+		return
+	}
+
+	expires := time.Now().Add(ttl)
+	token, err := h.Links.Sign(req.SnapshotID, expires)
+	if err != nil {
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to create link", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.Share{
+		Token:      token,
+		URL:        h.linkURL(c, token),
+		SnapshotID: req.SnapshotID,
+		ExpiresAt:  expires.Unix(),
+	})
+}
+
+// HandleGet serves the snapshot the link :token points to
+func (h *ShareHandler) HandleGet(c *gin.Context) {
+	if !h.enabled(c) {
+		return
+	}
+
+	id, err := h.Links.Verify(c.Param("token"))
+	if errors.Is(err, auth.ErrExpiredLink) {
+		writeError(c, http.StatusGone, models.ErrorCodeExpired, "This link has expired", "")
+		return
+	}
+	if err != nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Invalid link", "")
+		return
+	}
+
+	snapshot, err := h.Store.GetSnapshot(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		writeError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Search snapshot not found", "")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load snapshot %s: %v", id, err)
+		writeError(c, http.StatusInternalServerError, models.ErrorCodeInternal, "Failed to load search snapshot", err.Error())
+		return
+	}
+
+	// The snapshot ID opens it for good on the export routes, outliving
+	// the link, so it stays with whoever shared it
+	snapshot.ID = ""
+
+	// Shared links are for the people they're sent to, not search engines
+	c.Header("X-Robots-Tag", "noindex")
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// enabled responds with 404 when sharing is disabled
+func (h *ShareHandler) enabled(c *gin.Context) bool {
+	if h.Links == nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeDisabled, "Sharing is not enabled", "")
+		return false
+	}
+	return true
+}
+
+// linkURL is the public URL of the link token, under the API version the
+// link was created with
+func (h *ShareHandler) linkURL(c *gin.Context, token string) string {
+	base := h.cfg.PublicURL
+	if base == "" {
+		scheme := "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		} else if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+			scheme = proto
+		}
+		base = scheme + "://" + c.Request.Host
+	}
+	return base + c.FullPath() + "/" + token
+}
@@ -0,0 +1,143 @@
+// File: backend/api/handlers/suggest.go
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 10
+	maxSuggestQuery     = 100
+	// suggestLookback is how far back past searches are suggested from
+	suggestLookback = 30 * 24 * time.Hour
+	// minSuggestOwners is how many clients must have run a query before it
+	// is suggested to others
+	minSuggestOwners = 2
+)
+
+// subredditPrefixPattern matches what could be the start of a subreddit name
+var subredditPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,21}$`)
+
+// SuggestHandler serves typeahead suggestions for the search box
+type SuggestHandler struct {
+	Store         *store.Store
+	RedditService *services.RedditService
+}
+
+// NewSuggestHandler creates a new suggest handler
+func NewSuggestHandler(dataStore *store.Store, redditService *services.RedditService) *SuggestHandler {
+	return &SuggestHandler{
+		Store:         dataStore,
+		RedditService: redditService,
+	}
+}
+
+// suggestOperations documents the suggest route for /api/openapi.json
+var suggestOperations = []openapi.Operation{
+	{
+		Method:      "GET",
+		Path:        "/api/suggest",
+		Tag:         "Search",
+		Summary:     "Suggest queries and communities as the user types",
+		Description: fmt.Sprintf("Combines popular past searches starting with q, run by at least %d clients or by the caller, with Reddit's subreddit autocomplete. Either list may be empty if its source fails.", minSuggestOwners),
+		Parameters: []openapi.Parameter{
+			{Name: "q", In: "query", Required: true, Description: "The partly typed query"},
+			{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Suggestions per list, default %d, at most %d", defaultSuggestLimit, maxSuggestLimit)},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.SuggestResponse{}},
+			validationErrorResponse,
+			errorResponse(http.StatusBadGateway, "Neither past searches nor Reddit could be reached"),
+		},
+	},
+}
+
+// HandleSuggest returns past queries and communities starting with q
+func (h *SuggestHandler) HandleSuggest(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		invalidField(c, "q", "is required")
+		return
+	}
+	if len(query) > maxSuggestQuery {
+		invalidField(c, "q", fmt.Sprintf("must be at most %d characters", maxSuggestQuery))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSuggestLimit)))
+	if err != nil || limit <= 0 {
+		invalidField(c, "limit", "must be a positive integer")
+		return
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	// Past searches and communities are independent, so look them up
+	// concurrently
+	owner := clientKey(c)
+	var (
+		wg                    sync.WaitGroup
+		queries               []string
+		subreddits            []models.SearchResult
+		queriesErr, redditErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		since := time.Now().Add(-suggestLookback)
+		queries, queriesErr = h.Store.SuggestQueries(ctx, owner, query, since, minSuggestOwners, limit)
+	}()
+	// Multi-word queries can't be the start of a community name
+	if prefix := strings.TrimPrefix(query, "r/"); subredditPrefixPattern.MatchString(prefix) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			subreddits, redditErr = h.RedditService.SuggestSubreddits(ctx, prefix, limit)
+		}()
+	}
+	wg.Wait()
+
+	if queriesErr != nil && redditErr != nil {
+		writeUpstreamError(c, "Failed to load suggestions", redditErr)
+		return
+	}
+	if queriesErr != nil {
+		log.Printf("Failed to suggest past queries: %v", queriesErr)
+	}
+	if redditErr != nil {
+		log.Printf("Failed to suggest subreddits: %v", redditErr)
+	}
+
+	// Clients get [] rather than null for empty lists
+	if queries == nil {
+		queries = []string{}
+	}
+	if subreddits == nil {
+		subreddits = []models.SearchResult{}
+	}
+	c.JSON(http.StatusOK, models.SuggestResponse{
+		Query:      query,
+		Queries:    queries,
+		Subreddits: subreddits,
+	})
+}
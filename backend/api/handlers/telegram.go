@@ -0,0 +1,169 @@
+// File: backend/api/handlers/telegram.go
+
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/telegram"
+)
+
+// telegramSecretHeader carries the secret_token given to setWebhook
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// telegramReplyTimeout bounds searching and replying to one message
+const telegramReplyTimeout = 90 * time.Second
+
+// telegramHelp is sent for /start, /help and unknown commands
+const telegramHelp = "Send me a question and I'll answer it from Reddit discussions, with links to the sources.\n\n" +
+	"In groups, use /ask followed by your question."
+
+// TelegramHandler answers messages sent to the Telegram bot
+type TelegramHandler struct {
+	searchHandler *SearchHandler
+	bot           *telegram.Bot // nil when the bot is disabled
+	secret        string
+	limiter       *telegram.ChatLimiter
+	cfg           config.TelegramConfig
+}
+
+// NewTelegramHandler creates a new Telegram handler. Pass a nil bot to
+// disable the webhook. Searches run through searchHandler so they are saved
+// and recorded in each chat's history.
+func NewTelegramHandler(searchHandler *SearchHandler, bot *telegram.Bot, secret string, cfg config.TelegramConfig) *TelegramHandler {
+	return &TelegramHandler{
+		searchHandler: searchHandler,
+		bot:           bot,
+		secret:        secret,
+		limiter:       telegram.NewChatLimiter(cfg.MessagesPerWindow, cfg.Window),
+		cfg:           cfg,
+	}
+}
+
+// telegramOperations documents the webhook route for /api/openapi.json
+var telegramOperations = []openapi.Operation{
+	{
+		Method:      "POST",
+		Path:        "/api/telegram/webhook",
+		Tag:         "Integrations",
+		Summary:     "Receive Telegram bot updates",
+		Description: "Called by Telegram for each message sent to the bot. Messages are answered asynchronously with a reply in the chat.",
+		Parameters: []openapi.Parameter{
+			{Name: telegramSecretHeader, In: "header", Required: true, Description: "The secret_token registered with setWebhook"},
+		},
+		Request: telegram.Update{},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Description: "Update accepted"},
+			errorResponse(http.StatusBadRequest, "Invalid update"),
+			errorResponse(http.StatusUnauthorized, "Invalid secret token"),
+			errorResponse(http.StatusNotFound, "Telegram bot is not enabled"),
+		},
+	},
+}
+
+// HandleWebhook accepts an update from Telegram. The reply is sent in the
+// background: Telegram redelivers updates that aren't acknowledged quickly,
+// and a search can take longer than it waits.
+func (h *TelegramHandler) HandleWebhook(c *gin.Context) {
+	if h.bot == nil {
+		writeError(c, http.StatusNotFound, models.ErrorCodeDisabled, "Telegram bot is not enabled", "")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader(telegramSecretHeader)), []byte(h.secret)) != 1 {
+		writeError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid secret token", "")
+		return
+	}
+
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid update", err.Error())
+		return
+	}
+
+	// Edits, joins, stickers and the like need no reply
+	if update.Message != nil && strings.TrimSpace(update.Message.Text) != "" {
+		go h.reply(*update.Message)
+	}
+	c.Status(http.StatusOK)
+}
+
+// reply answers one message
+func (h *TelegramHandler) reply(msg telegram.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), telegramReplyTimeout)
+	defer cancel()
+
+	query, ok := parseTelegramQuery(msg.Text)
+	if !ok {
+		h.send(ctx, msg, telegram.Escape(telegramHelp))
+		return
+	}
+	if !h.limiter.Allow(msg.Chat.ID) {
+		h.send(ctx, msg, telegram.Escape(fmt.Sprintf(
+			"You're sending questions too quickly. This chat can ask %d per %v; please try again shortly.",
+			h.cfg.MessagesPerWindow, h.cfg.Window)))
+		return
+	}
+
+	if err := h.bot.SendTyping(ctx, msg.Chat.ID); err != nil {
+		log.Printf("Failed to send Telegram typing action: %v", err)
+	}
+
+	req := models.SearchRequest{Query: query, ModelName: h.cfg.ModelName}
+	owner := fmt.Sprintf("telegram:%d", msg.Chat.ID)
+	response, err := h.searchHandler.runSearch(ctx, owner, req)
+	if err != nil {
+		log.Printf("Telegram search failed: %v", err)
+		text := "Sorry, that search failed. Please try again later."
+		var invalid *services.ValidationError
+		if errors.As(err, &invalid) {
+			text = "I can't search for that: " + invalid.Error()
+		}
+		if errors.Is(err, services.ErrOverloaded) {
+			text = "I'm busy right now. Please try again in a few seconds."
+		}
+		h.send(ctx, msg, telegram.Escape(text))
+		return
+	}
+
+	h.send(ctx, msg, telegram.FormatAnswer(response, h.cfg.MaxCitations))
+}
+
+// send replies to msg, logging failures since there is no caller to
+// report them to
+func (h *TelegramHandler) send(ctx context.Context, msg telegram.Message, html string) {
+	if err := h.bot.SendMessage(ctx, msg.Chat.ID, html, msg.MessageID); err != nil {
+		log.Printf("Failed to send Telegram reply: %v", err)
+	}
+}
+
+// parseTelegramQuery extracts the question from a message. Commands may be
+// addressed to the bot as /ask@botname; /start, /help and unknown commands
+// report false so the caller sends help instead.
+func parseTelegramQuery(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return text, true
+	}
+
+	command, rest, _ := strings.Cut(text, " ")
+	command, _, _ = strings.Cut(command, "@")
+	switch strings.ToLower(command) {
+	case "/ask", "/search":
+		rest = strings.TrimSpace(rest)
+		return rest, rest != ""
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,101 @@
+// File: backend/api/handlers/trending.go
+
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/openapi"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit     = 50
+	maxTrendingQuery     = 100
+)
+
+// subredditNamePattern matches valid subreddit names
+var subredditNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{2,21}$`)
+
+// TrendingHandler serves what is currently popular on Reddit
+type TrendingHandler struct {
+	RedditService *services.RedditService
+}
+
+// NewTrendingHandler creates a new trending handler
+func NewTrendingHandler(redditService *services.RedditService) *TrendingHandler {
+	return &TrendingHandler{
+		RedditService: redditService,
+	}
+}
+
+// trendingOperations documents the trending route for /api/openapi.json
+var trendingOperations = []openapi.Operation{
+	{
+		Method:  "GET",
+		Path:    "/api/trending",
+		Tag:     "Reddit",
+		Summary: "List hot posts and popular communities",
+		Parameters: []openapi.Parameter{
+			{Name: "subreddit", In: "query", Description: "Scope posts to one community"},
+			{Name: "q", In: "query", Description: "Search communities by name or description"},
+			{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Items per list, default %d, at most %d", defaultTrendingLimit, maxTrendingLimit)},
+		},
+		Responses: []openapi.Response{
+			{Status: http.StatusOK, Body: models.TrendingResponse{}},
+			errorResponse(http.StatusBadRequest, "Invalid parameters"),
+			errorResponse(http.StatusBadGateway, "Reddit request failed"),
+			errorResponse(http.StatusServiceUnavailable, "Reddit is rate limiting the server"),
+		},
+	},
+}
+
+// HandleTrending returns hot posts and popular communities. The optional
+// subreddit parameter scopes posts to one community, q searches communities,
+// and limit caps each list.
+func (h *TrendingHandler) HandleTrending(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTrendingLimit)))
+	if err != nil || limit <= 0 {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "limit must be a positive integer", "")
+		return
+	}
+	if limit > maxTrendingLimit {
+		limit = maxTrendingLimit
+	}
+
+	subreddit := strings.TrimPrefix(strings.TrimSpace(c.Query("subreddit")), "r/")
+	if subreddit != "" && !subredditNamePattern.MatchString(subreddit) {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid subreddit name", "")
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if len(query) > maxTrendingQuery {
+		writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "q is too long", "")
+		return
+	}
+
+	response, err := h.RedditService.GetTrending(ctx, services.TrendingOptions{
+		Subreddit: subreddit,
+		Query:     query,
+		Limit:     limit,
+	})
+	if err != nil {
+		writeUpstreamError(c, "Failed to fetch trending content from Reddit", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+// File: backend/api/handlers/trending_test.go
+
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandleTrending(t *testing.T) {
+	search, mock := newTestSearchHandler(t)
+	mock.SetResponse("/subreddits/popular.json", `{"kind": "Listing", "data": {"children": []}}`)
+	h := NewTrendingHandler(search.RedditService)
+	r := gin.New()
+	r.GET("/api/trending", h.HandleTrending)
+
+	for query, status := range map[string]int{
+		"?subreddit=r/golang&limit=500": http.StatusOK,
+		"?limit=abc":                    http.StatusBadRequest,
+		"?limit=0":                      http.StatusBadRequest,
+		"?limit=-5":                     http.StatusBadRequest,
+		"?subreddit=bad%20name!":        http.StatusBadRequest,
+		"?subreddit=a":                  http.StatusBadRequest,
+		"?subreddit=golang/../admin":    http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/trending"+query, nil))
+		if rec.Code != status {
+			t.Errorf("%s: expected %d, got %d: %s", query, status, rec.Code, rec.Body)
+		}
+	}
+
+	// Invalid parameters never reach Reddit, and limits are capped
+	hot := 0
+	for _, req := range mock.Requests() {
+		if req.Path == "/r/golang/hot.json" {
+			hot++
+			if req.Query.Get("limit") != "50" {
+				t.Errorf("Expected the limit capped at 50, got %s", req.Query.Get("limit"))
+			}
+		}
+		if req.Path != "/r/golang/hot.json" && req.Path != "/subreddits/popular.json" && req.Path != "/api/v1/access_token" {
+			t.Errorf("Unexpected request to %s", req.Path)
+		}
+	}
+	if hot != 1 {
+		t.Errorf("Expected one request for r/golang's hot posts, got %d", hot)
+	}
+}
@@ -0,0 +1,54 @@
+// File: backend/api/handlers/validation.go
+
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// validationErrorResponse documents the 400 response for requests that fail
+// validation
+var validationErrorResponse = errorResponse(http.StatusBadRequest, "Invalid request; fields lists each problem")
+
+// bindJSON decodes the request body into obj. It responds with 413 when
+// the body is over the size limit and 400 when it isn't valid JSON, and
+// reports whether decoding succeeded.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(c, http.StatusRequestEntityTooLarge, models.ErrorCodePayloadTooLarge, "Request body too large", err.Error())
+		return false
+	}
+
+	log.Printf("Invalid request payload: %v", err)
+	writeError(c, http.StatusBadRequest, models.ErrorCodeInvalidRequest, "Invalid request payload", err.Error())
+	return false
+}
+
+// writeValidationError responds with 400, listing the invalid fields when
+// err is a *services.ValidationError
+func writeValidationError(c *gin.Context, err error) {
+	body := newErrorBody(c, models.ErrorCodeInvalidRequest, "Invalid request", err.Error())
+	var invalid *services.ValidationError
+	if errors.As(err, &invalid) {
+		body.Fields = invalid.Fields
+	}
+	c.JSON(http.StatusBadRequest, body)
+}
+
+// invalidField responds with 400 for a single invalid field, such as a path
+// or query parameter
+func invalidField(c *gin.Context, field, message string) {
+	writeValidationError(c, &services.ValidationError{Fields: []models.FieldError{{Field: field, Message: message}}})
+}
@@ -0,0 +1,32 @@
+// File: backend/api/middleware/admin.go
+
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// RequireAdmin only lets through requests carrying "Authorization: Bearer
+// <adminKey>". When adminKey is empty the admin API is disabled entirely.
+func RequireAdmin(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if adminKey == "" {
+			abortWithError(c, http.StatusServiceUnavailable, models.ErrorCodeDisabled,
+				"Admin API is disabled. Set ADMIN_API_KEY to enable it", "")
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminKey)) != 1 {
+			abortWithError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, "Invalid admin credentials", "")
+			return
+		}
+
+		c.Next()
+	}
+}
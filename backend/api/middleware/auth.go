@@ -0,0 +1,54 @@
+// File: backend/api/middleware/auth.go
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// accountIDKey stores the signed-in account's ID in the gin context
+const accountIDKey = "accountID"
+
+// Authenticate signs in requests carrying a session token as "Authorization:
+// Bearer <token>". Requests with an invalid or expired token get 401, so
+// clients know to sign in again; requests without one carry on anonymously.
+// Other bearer credentials, such as the admin key, are left alone. nil
+// sessions disables accounts.
+func Authenticate(sessions *auth.Sessions) gin.HandlerFunc {
+	if sessions == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !auth.LooksLikeToken(token) {
+			c.Next()
+			return
+		}
+
+		accountID, err := sessions.Verify(token)
+		if err != nil {
+			message := "Invalid session token"
+			if errors.Is(err, auth.ErrExpiredToken) {
+				message = "Session expired; sign in again"
+			}
+			abortWithError(c, http.StatusUnauthorized, models.ErrorCodeUnauthorized, message, "")
+			return
+		}
+
+		c.Set(accountIDKey, accountID)
+		c.Next()
+	}
+}
+
+// AccountID returns the ID of the account Authenticate signed the request in
+// as, or "" for anonymous requests
+func AccountID(c *gin.Context) string {
+	return c.GetString(accountIDKey)
+}
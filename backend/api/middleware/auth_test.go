@@ -0,0 +1,56 @@
+// File: backend/api/middleware/auth_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/auth"
+)
+
+func TestAuthenticate(t *testing.T) {
+	sessions, err := auth.NewSessions([]byte(strings.Repeat("s", auth.MinSecretLength)), time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	token, _, err := sessions.Issue("account-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Authenticate(sessions))
+	r.GET("/api/history", func(c *gin.Context) { c.String(http.StatusOK, ClientKey(c)) })
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantKey       string
+	}{
+		{"Anonymous", "", http.StatusOK, "ip:192.0.2.1"},
+		{"Signed in", "Bearer " + token, http.StatusOK, "account:account-1"},
+		{"Other bearer key", "Bearer admin-key", http.StatusOK, "ip:192.0.2.1"},
+		{"Bad token", "Bearer " + token + "x", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/history", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.wantStatus)
+		}
+		if tt.wantKey != "" && rec.Body.String() != tt.wantKey {
+			t.Errorf("%s: client key %q, want %q", tt.name, rec.Body.String(), tt.wantKey)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// File: backend/api/middleware/body.go
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// MaxBodySize rejects requests whose body is larger than limit bytes with
+// 413. Bodies without a declared length are cut off at the limit, failing
+// when the handler reads past it.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > limit {
+			abortWithError(c, http.StatusRequestEntityTooLarge, models.ErrorCodePayloadTooLarge,
+				"Request body too large", fmt.Sprintf("the limit is %d bytes", limit))
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
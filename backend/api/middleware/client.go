@@ -0,0 +1,75 @@
+// File: backend/api/middleware/client.go
+
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountKeyPrefix starts the client keys of signed-in callers, followed by
+// their account ID
+const AccountKeyPrefix = "account:"
+
+// issuedAPIKey marks, in the gin context, requests whose X-API-Key is one of
+// the keys APIKeys was given
+const issuedAPIKey = "issuedAPIKey"
+
+// ClientKey identifies the caller for per-client data such as history and
+// saved searches. Signed-in callers are keyed by their account. Callers
+// presenting an X-API-Key are keyed by a hash of it so the raw key is never
+// stored; everyone else is keyed by IP address.
+//
+// Any X-API-Key is accepted here, since it only separates a caller's own
+// data. Limits use LimitKey instead.
+func ClientKey(c *gin.Context) string {
+	if accountID := AccountID(c); accountID != "" {
+		return AccountKeyPrefix + accountID
+	}
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKeyClient(apiKey)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// LimitKey identifies the caller for quotas and concurrency caps. It is
+// ClientKey, except that an X-API-Key only counts once APIKeys has found it
+// among the issued keys; other callers are limited by IP address, so
+// sending a fresh key doesn't buy a fresh allowance.
+func LimitKey(c *gin.Context) string {
+	if accountID := AccountID(c); accountID != "" {
+		return AccountKeyPrefix + accountID
+	}
+	if c.GetBool(issuedAPIKey) {
+		return apiKeyClient(c.GetHeader("X-API-Key"))
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// APIKeys recognizes requests whose X-API-Key is one of keys, so LimitKey
+// gives them their own allowance. Requests with other keys carry on as
+// before and are limited by IP address.
+func APIKeys(keys []string) gin.HandlerFunc {
+	issued := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			issued[apiKeyClient(key)] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" && issued[apiKeyClient(apiKey)] {
+			c.Set(issuedAPIKey, true)
+		}
+		c.Next()
+	}
+}
+
+// apiKeyClient is the client key of callers presenting apiKey
+func apiKeyClient(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key:" + hex.EncodeToString(sum[:])
+}
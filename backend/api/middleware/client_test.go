@@ -0,0 +1,42 @@
+// File: backend/api/middleware/client_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLimitKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(APIKeys([]string{"issued-key", " "}))
+	r.GET("/api/search", func(c *gin.Context) {
+		c.String(http.StatusOK, ClientKey(c)+" "+LimitKey(c))
+	})
+
+	tests := []struct {
+		name      string
+		apiKey    string
+		wantData  string
+		wantLimit string
+	}{
+		{"No key", "", "ip:192.0.2.1", "ip:192.0.2.1"},
+		{"Issued key", "issued-key", apiKeyClient("issued-key"), apiKeyClient("issued-key")},
+		{"Unknown key", "made-up-key", apiKeyClient("made-up-key"), "ip:192.0.2.1"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/search", nil)
+		if tt.apiKey != "" {
+			req.Header.Set("X-API-Key", tt.apiKey)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if want := tt.wantData + " " + tt.wantLimit; rec.Body.String() != want {
+			t.Errorf("%s: keys %q, want %q", tt.name, rec.Body.String(), want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// File: backend/api/middleware/concurrency.go
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// clientSlotsKey stores, in the gin context, the slots a request holds, for
+// TryClientSlots to take more of
+const clientSlotsKey = "clientSlots"
+
+// clientRetryAfter is the Retry-After delay, in seconds, sent to clients
+// over their concurrency cap
+const clientRetryAfter = "2"
+
+// ClientConcurrency lets each client, as identified by LimitKey, have at
+// most limit requests in flight through the routes it guards. Extra requests
+// wait up to wait for one of the client's requests to finish, then fail with
+// 429. This keeps one misbehaving client from monopolizing the shared
+// Reddit token and AI spend. A limit of 0 or less disables the cap.
+//
+// Apply the same handler to every route that should share a client's
+// allowance.
+func ClientConcurrency(limit int, wait time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	slots := &clientSlots{limit: limit, clients: make(map[string]*clientSlot)}
+	return func(c *gin.Context) {
+		key := LimitKey(c)
+		if !slots.acquire(c, key, wait) {
+			if c.Request.Context().Err() != nil {
+				c.Abort()
+				return
+			}
+			c.Header("Retry-After", clientRetryAfter)
+			abortWithError(c, http.StatusTooManyRequests, models.ErrorCodeTooManyRequests,
+				"Too many searches in progress", fmt.Sprintf("at most %d searches may run at once per client", limit))
+			return
+		}
+		// A detached request keeps its slot until its background work finishes
+		defer afterRequest(c, func() { slots.release(key) })
+
+		c.Set(clientSlotsKey, &heldSlots{slots: slots, key: key})
+		c.Next()
+	}
+}
+
+// TryClientSlots takes up to n more of the caller's concurrency slots
+// without waiting, for a request that runs several searches at once, such
+// as a batch, on top of the one ClientConcurrency took. It returns how many
+// it took and a function releasing them, to call once the searches are
+// done. Without a cap every slot asked for is granted.
+func TryClientSlots(c *gin.Context, n int) (int, func()) {
+	value, _ := c.Get(clientSlotsKey)
+	held, ok := value.(*heldSlots)
+	if n <= 0 {
+		return 0, func() {}
+	}
+	if !ok {
+		return n, func() {}
+	}
+
+	taken := 0
+	for taken < n && held.slots.tryAcquire(held.key) {
+		taken++
+	}
+	return taken, func() {
+		for i := 0; i < taken; i++ {
+			held.slots.release(held.key)
+		}
+	}
+}
+
+// heldSlots identifies the client whose slot a request holds
+type heldSlots struct {
+	slots *clientSlots
+	key   string
+}
+
+// clientSlots tracks the requests each client has in flight
+type clientSlots struct {
+	limit   int
+	mu      sync.Mutex
+	clients map[string]*clientSlot
+}
+
+// clientSlot is one client's semaphore. users counts the requests holding
+// or waiting for it, so idle clients can be forgotten.
+type clientSlot struct {
+	slots chan struct{}
+	users int
+}
+
+// acquire takes one of key's slots, waiting up to wait. It reports whether
+// a slot was taken.
+func (s *clientSlots) acquire(c *gin.Context, key string, wait time.Duration) bool {
+	slot := s.join(key)
+
+	// Take a free slot without racing the timer
+	select {
+	case slot.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case slot.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+	case <-c.Request.Context().Done():
+	}
+	s.leave(key, slot)
+	return false
+}
+
+// tryAcquire takes one of key's slots if one is free right away. It reports
+// whether a slot was taken.
+func (s *clientSlots) tryAcquire(key string) bool {
+	slot := s.join(key)
+
+	select {
+	case slot.slots <- struct{}{}:
+		return true
+	default:
+		s.leave(key, slot)
+		return false
+	}
+}
+
+// join counts a request holding or waiting for one of key's slots and
+// returns the client's semaphore
+func (s *clientSlots) join(key string) *clientSlot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot, ok := s.clients[key]
+	if !ok {
+		slot = &clientSlot{slots: make(chan struct{}, s.limit)}
+		s.clients[key] = slot
+	}
+	slot.users++
+	return slot
+}
+
+// release frees a slot taken by acquire or tryAcquire
+func (s *clientSlots) release(key string) {
+	s.mu.Lock()
+	slot := s.clients[key]
+	s.mu.Unlock()
+
+	<-slot.slots
+	s.leave(key, slot)
+}
+
+func (s *clientSlots) leave(key string, slot *clientSlot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot.users--
+	if slot.users == 0 {
+		delete(s.clients, key)
+	}
+}
@@ -0,0 +1,161 @@
+// File: backend/api/middleware/concurrency_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientConcurrency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	r := gin.New()
+	r.Use(APIKeys([]string{"alice", "bob"}))
+	r.Use(ClientConcurrency(1, 20*time.Millisecond))
+	r.POST("/api/search", func(c *gin.Context) {
+		if c.Query("block") == "true" {
+			started <- struct{}{}
+			<-finish
+		}
+		c.Status(http.StatusOK)
+	})
+
+	search := func(apiKey, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/search"+query, nil)
+		req.Header.Set("X-API-Key", apiKey)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Hold the first client's only slot
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		search("alice", "?block=true")
+	}()
+	<-started
+
+	rec := search("alice", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 over the cap, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+	if rec := search("bob", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected other clients unaffected, got %d", rec.Code)
+	}
+
+	// Keys that weren't issued share the IP address's allowance
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		search("made-up-1", "?block=true")
+	}()
+	<-started
+	if rec := search("made-up-2", ""); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a made-up key not to get its own slot, got %d", rec.Code)
+	}
+
+	close(finish)
+	wg.Wait()
+	if rec := search("alice", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected the slot to be released, got %d", rec.Code)
+	}
+}
+
+func TestClientConcurrencyQueues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := make(chan struct{})
+	r := gin.New()
+	r.Use(ClientConcurrency(1, time.Second))
+	r.POST("/api/search", func(c *gin.Context) {
+		if c.Query("block") == "true" {
+			close(started)
+			time.Sleep(20 * time.Millisecond)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	go r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/search?block=true", nil))
+	<-started
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/search", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the queued request to run once the slot was free, got %d", rec.Code)
+	}
+}
+
+func TestTryClientSlots(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	taken := make(chan int, 2)
+	r := gin.New()
+	r.Use(ClientConcurrency(3, 20*time.Millisecond))
+	r.POST("/api/search/batch", func(c *gin.Context) {
+		n, release := TryClientSlots(c, 5)
+		taken <- n
+		if c.Query("nested") == "true" {
+			// The client's slots are all held, so requests wait and fail
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/search/batch", nil))
+			if rec.Code != http.StatusTooManyRequests {
+				t.Errorf("Expected 429 with every slot held, got %d", rec.Code)
+			}
+		}
+		release()
+		c.Status(http.StatusOK)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/search/batch?nested=true", nil))
+	if n := <-taken; n != 2 {
+		t.Errorf("Expected the 2 slots left over, got %d", n)
+	}
+
+	// The slots are free again once released
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/search/batch", nil))
+	if n := <-taken; n != 2 {
+		t.Errorf("Expected the released slots to be taken again, got %d", n)
+	}
+}
+
+func TestClientConcurrencyDetached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var done func()
+	r := gin.New()
+	r.Use(ClientConcurrency(1, 20*time.Millisecond))
+	r.POST("/api/search", func(c *gin.Context) {
+		if c.Query("detach") == "true" {
+			done = Detach(c)
+		}
+		c.Status(http.StatusOK)
+	})
+
+	search := func(query string) int {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/search"+query, nil))
+		return rec.Code
+	}
+
+	if code := search("?detach=true"); code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	// Work left running keeps the slot after the response
+	if code := search(""); code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 while detached work runs, got %d", code)
+	}
+	done()
+	done()
+	if code := search(""); code != http.StatusOK {
+		t.Errorf("Expected the slot back once the work is done, got %d", code)
+	}
+}
@@ -0,0 +1,100 @@
+// File: backend/api/middleware/cors.go
+
+package middleware
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/config"
+)
+
+// CORS applies the configured cross-origin policy. Route overrides apply to
+// paths under their prefix, the longest matching prefix winning; other
+// paths use cfg.AllowOrigins. Prefixes are unversioned, so "/api/embed" also
+// covers "/api/v1/embed". Cross-origin requests from other origins are
+// rejected with 403.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	type route struct {
+		prefix  string
+		handler gin.HandlerFunc
+	}
+	routes := make([]route, 0, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		routes = append(routes, route{prefix: r.PathPrefix, handler: corsPolicy(r.AllowOrigins)})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return len(routes[i].prefix) > len(routes[j].prefix)
+	})
+	defaultPolicy := corsPolicy(cfg.AllowOrigins)
+
+	// Runs for every request, including preflights for unknown routes, so
+	// it must be registered with Engine.Use rather than on a group
+	return func(c *gin.Context) {
+		path := unversionedPath(c.Request.URL.Path)
+		for _, r := range routes {
+			if strings.HasPrefix(path, r.prefix) {
+				r.handler(c)
+				return
+			}
+		}
+		defaultPolicy(c)
+	}
+}
+
+// corsPolicy allows requests from origins, which may contain * wildcards
+func corsPolicy(origins []string) gin.HandlerFunc {
+	settings := cors.Config{
+		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "X-API-Key", RequestIDHeader},
+		ExposeHeaders: append([]string{"Content-Length", "Retry-After", RequestIDHeader}, quotaHeaders...),
+		MaxAge:        12 * time.Hour,
+	}
+
+	for _, origin := range origins {
+		if origin == "*" {
+			// Browsers refuse credentials with a wildcard origin
+			settings.AllowAllOrigins = true
+			return cors.New(settings)
+		}
+	}
+
+	settings.AllowCredentials = true
+	settings.AllowOriginFunc = originMatcher(origins)
+	return cors.New(settings)
+}
+
+// originMatcher reports whether an origin matches one of patterns, where *
+// matches any run of characters other than "/"
+func originMatcher(patterns []string) func(origin string) bool {
+	exact := make(map[string]bool, len(patterns))
+	var wildcards []*regexp.Regexp
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "*") {
+			exact[pattern] = true
+			continue
+		}
+		parts := strings.Split(pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		wildcards = append(wildcards, regexp.MustCompile("^"+strings.Join(parts, "[^/]*")+"$"))
+	}
+
+	return func(origin string) bool {
+		origin = strings.ToLower(origin)
+		if exact[origin] {
+			return true
+		}
+		for _, wildcard := range wildcards {
+			if wildcard.MatchString(origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
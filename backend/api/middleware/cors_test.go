@@ -0,0 +1,62 @@
+// File: backend/api/middleware/cors_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/config"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(config.CORSConfig{
+		AllowOrigins: []string{"https://app.example.com", "https://*.preview.example.com", "http://localhost:*"},
+		Routes: []config.CORSRoute{
+			{PathPrefix: "/api/admin", AllowOrigins: nil},
+			{PathPrefix: "/api/public", AllowOrigins: []string{"*"}},
+		},
+	}))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/search", ok)
+	r.GET("/api/admin/stats", ok)
+	r.GET("/api/public/docs", ok)
+	r.GET("/api/v1/admin/stats", ok)
+	r.GET("/api/v1/public/docs", ok)
+
+	tests := []struct {
+		path, origin string
+		wantStatus   int
+		wantAllow    string
+	}{
+		{"/api/search", "https://app.example.com", http.StatusOK, "https://app.example.com"},
+		{"/api/search", "https://pr-12.preview.example.com", http.StatusOK, "https://pr-12.preview.example.com"},
+		{"/api/search", "http://localhost:5173", http.StatusOK, "http://localhost:5173"},
+		{"/api/search", "https://preview.example.com.evil.com", http.StatusForbidden, ""},
+		{"/api/search", "https://evil.com", http.StatusForbidden, ""},
+		{"/api/admin/stats", "https://app.example.com", http.StatusForbidden, ""},
+		{"/api/public/docs", "https://evil.com", http.StatusOK, "*"},
+		{"/api/v1/public/docs", "https://evil.com", http.StatusOK, "*"},
+		{"/api/v1/admin/stats", "https://app.example.com", http.StatusForbidden, ""},
+		{"/api/search", "", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s from %q: status %d, want %d", tt.path, tt.origin, rec.Code, tt.wantStatus)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllow {
+			t.Errorf("%s from %q: Access-Control-Allow-Origin %q, want %q", tt.path, tt.origin, got, tt.wantAllow)
+		}
+	}
+}
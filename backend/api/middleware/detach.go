@@ -0,0 +1,66 @@
+// File: backend/api/middleware/detach.go
+
+package middleware
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// detachKey stores, in the gin context, the work a request leaves running
+// once it has responded
+const detachKey = "detached"
+
+// detached holds what middleware puts off until a request's detached work
+// is done
+type detached struct {
+	mu       sync.Mutex
+	done     bool
+	deferred []func()
+}
+
+// Detach marks the request as leaving work running after the handler
+// returns, such as an answer generated in the background. Until the
+// returned function is called, the request keeps its ClientConcurrency slot
+// and Quotas waits to charge its AI tokens, so the work counts against the
+// client like any other. Call it exactly once, when the work is done.
+func Detach(c *gin.Context) func() {
+	d := &detached{}
+	c.Set(detachKey, d)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			d.done = true
+			deferred := d.deferred
+			d.deferred = nil
+			d.mu.Unlock()
+
+			for _, f := range deferred {
+				f()
+			}
+		})
+	}
+}
+
+// afterRequest runs f, which must not use c, once the request and any work
+// it detached are done. Middleware calls it after c.Next.
+func afterRequest(c *gin.Context, f func()) {
+	value, _ := c.Get(detachKey)
+	d, ok := value.(*detached)
+	if !ok {
+		f()
+		return
+	}
+
+	d.mu.Lock()
+	if !d.done {
+		d.deferred = append(d.deferred, f)
+		d.mu.Unlock()
+		return
+	}
+	d.mu.Unlock()
+	f()
+}
@@ -0,0 +1,89 @@
+// File: backend/api/middleware/errorlog.go
+
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// errorBodyKey stores the error body a handler responded with in the gin
+// context, for RecordErrors
+const errorBodyKey = "errorBody"
+
+// ErrorLog keeps the most recent server error responses, for the admin
+// dashboard
+type ErrorLog struct {
+	mu     sync.Mutex
+	errors []models.RecentError // Ring buffer, next holds the oldest when full
+	next   int
+	size   int
+}
+
+// NewErrorLog creates a log of the last size errors
+func NewErrorLog(size int) *ErrorLog {
+	return &ErrorLog{errors: make([]models.RecentError, 0, size), size: size}
+}
+
+// add records an error, dropping the oldest when the log is full
+func (l *ErrorLog) add(entry models.RecentError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.errors) < l.size {
+		l.errors = append(l.errors, entry)
+		return
+	}
+	l.errors[l.next] = entry
+	l.next = (l.next + 1) % l.size
+}
+
+// Recent returns the logged errors, newest first
+func (l *ErrorLog) Recent() []models.RecentError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	recent := make([]models.RecentError, 0, len(l.errors))
+	for i := len(l.errors) - 1; i >= 0; i-- {
+		recent = append(recent, l.errors[(l.next+i)%len(l.errors)])
+	}
+	return recent
+}
+
+// RecordErrors logs responses with a 5xx status to errors. It should be
+// installed before gin.Recovery so panics are logged too.
+func RecordErrors(errors *ErrorLog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError {
+			return
+		}
+		entry := models.RecentError{
+			OccurredAt: time.Now().Unix(),
+			Method:     c.Request.Method,
+			Path:       c.FullPath(),
+			Status:     status,
+			RequestID:  RequestIDFrom(c),
+		}
+		if entry.Path == "" {
+			entry.Path = c.Request.URL.Path
+		}
+		if body, ok := c.Value(errorBodyKey).(models.ErrorResponse); ok {
+			entry.Code = body.Code
+			entry.Message = body.Error
+		}
+		errors.add(entry)
+	}
+}
+
+// NoteError remembers the error body a handler responds with, so
+// RecordErrors can log its code and message
+func NoteError(c *gin.Context, body models.ErrorResponse) {
+	c.Set(errorBodyKey, body)
+}
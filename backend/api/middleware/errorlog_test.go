@@ -0,0 +1,46 @@
+// File: backend/api/middleware/errorlog_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestRecordErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	errorLog := NewErrorLog(2)
+	r := gin.New()
+	r.Use(RecordErrors(errorLog))
+	r.Use(gin.Recovery())
+	r.Use(RequestID())
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/missing/:id", func(c *gin.Context) {
+		abortWithError(c, http.StatusNotFound, models.ErrorCodeNotFound, "Not found", "")
+	})
+	r.GET("/fail/:id", func(c *gin.Context) {
+		abortWithError(c, http.StatusBadGateway, models.ErrorCodeUpstream, "Reddit failed", "")
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	for _, path := range []string{"/ok", "/missing/1", "/fail/1", "/fail/2", "/panic"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Only the last two server errors are kept, newest first
+	recent := errorLog.Recent()
+	if len(recent) != 2 {
+		t.Fatalf("Expected 2 recent errors, got %+v", recent)
+	}
+	if recent[0].Path != "/panic" || recent[0].Status != http.StatusInternalServerError || recent[0].RequestID == "" {
+		t.Errorf("Expected the panic first, got %+v", recent[0])
+	}
+	if got := recent[1]; got.Path != "/fail/:id" || got.Status != http.StatusBadGateway ||
+		got.Code != models.ErrorCodeUpstream || got.Message != "Reddit failed" {
+		t.Errorf("Expected the upstream failure second, got %+v", got)
+	}
+}
@@ -0,0 +1,16 @@
+// File: backend/api/middleware/errors.go
+
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// abortWithError stops the request with the standard error body
+func abortWithError(c *gin.Context, status int, code, message, details string) {
+	body := models.NewErrorResponse(code, message, details)
+	body.RequestID = RequestIDFrom(c)
+	NoteError(c, body)
+	c.AbortWithStatusJSON(status, body)
+}
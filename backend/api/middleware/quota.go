@@ -0,0 +1,133 @@
+// File: backend/api/middleware/quota.go
+
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/quota"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// quotaChargeKey stores, in the gin context, the function ChargeRequests
+// counts more requests with
+const quotaChargeKey = "quotaCharge"
+
+// quotaHeaders are the response headers Quotas sets, exposed to browsers
+var quotaHeaders = []string{
+	"X-Quota-Remaining-Requests-Day",
+	"X-Quota-Remaining-Requests-Month",
+	"X-Quota-Remaining-Tokens-Day",
+	"X-Quota-Remaining-Tokens-Month",
+	"X-Quota-Reset-Day",
+	"X-Quota-Reset-Month",
+}
+
+// Quotas counts each request, and the AI tokens it uses, against the daily
+// and monthly quotas of the client, as identified by LimitKey, refusing
+// requests with 429 once a quota is used up. Responses report what is left
+// in X-Quota-Remaining-* headers and when the periods reset, as Unix
+// seconds, in X-Quota-Reset-*. A nil limiter disables quotas.
+//
+// If the usage can't be read the request is let through, so a database
+// problem doesn't take searches down with it.
+func Quotas(limiter *quota.Limiter) gin.HandlerFunc {
+	if limiter == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		owner := LimitKey(c)
+		status, err := limiter.Begin(c.Request.Context(), owner)
+		if err != nil {
+			log.Printf("Quota check failed, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		setQuotaHeaders(c, status)
+		if status.Exceeded != "" {
+			refuseOverQuota(c, status)
+			return
+		}
+		c.Set(quotaChargeKey, func(n int64) bool {
+			more, err := limiter.BeginN(c.Request.Context(), owner, n)
+			if err != nil {
+				log.Printf("Quota check failed, allowing request: %v", err)
+				return true
+			}
+			setQuotaHeaders(c, more)
+			if more.Exceeded != "" {
+				refuseOverQuota(c, more)
+				return false
+			}
+			return true
+		})
+
+		ctx, tokens := services.WithTokenCounter(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		// Charge the tokens even if the client went away mid-answer, and
+		// those of any answer still being generated once it is done
+		afterRequest(c, func() {
+			if err := limiter.Finish(context.WithoutCancel(ctx), owner, status, tokens.Tokens()); err != nil {
+				log.Printf("Failed to record quota usage: %v", err)
+			}
+		})
+	}
+}
+
+// ChargeRequests counts n more requests against the caller's quota, for a
+// request that runs several searches, such as a batch, on top of the one
+// Quotas counted. Over quota it responds with 429 and returns false; the
+// handler should return without running any of them. Without quotas it
+// returns true.
+func ChargeRequests(c *gin.Context, n int64) bool {
+	value, _ := c.Get(quotaChargeKey)
+	charge, ok := value.(func(int64) bool)
+	if !ok || n <= 0 {
+		return true
+	}
+	return charge(n)
+}
+
+// refuseOverQuota responds with 429 for status's exceeded quota
+func refuseOverQuota(c *gin.Context, status *quota.Status) {
+	retryAfter := time.Until(status.ResetAt()).Round(time.Second)
+	c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+	abortWithError(c, http.StatusTooManyRequests, models.ErrorCodeQuotaExceeded,
+		"Quota exceeded", "the "+status.Exceeded+" quota is used up")
+}
+
+// setQuotaHeaders reports the configured quotas' remaining amounts. Token
+// counts don't include the current request, whose usage isn't known yet.
+func setQuotaHeaders(c *gin.Context, status *quota.Status) {
+	for _, q := range []struct {
+		header string
+		used   int64
+		limit  int64
+	}{
+		{quotaHeaders[0], status.Daily.Requests, status.Limits.DailyRequests},
+		{quotaHeaders[1], status.Monthly.Requests, status.Limits.MonthlyRequests},
+		{quotaHeaders[2], status.Daily.Tokens, status.Limits.DailyTokens},
+		{quotaHeaders[3], status.Monthly.Tokens, status.Limits.MonthlyTokens},
+	} {
+		if q.limit <= 0 {
+			continue
+		}
+		remaining := q.limit - q.used
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header(q.header, strconv.FormatInt(remaining, 10))
+	}
+	c.Header(quotaHeaders[4], strconv.FormatInt(status.DailyReset.Unix(), 10))
+	c.Header(quotaHeaders[5], strconv.FormatInt(status.MonthlyReset.Unix(), 10))
+}
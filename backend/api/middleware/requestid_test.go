@@ -0,0 +1,55 @@
+// File: backend/api/middleware/requestid_test.go
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	r.Use(RequireAdmin("secret"))
+	r.GET("/api/admin/stats", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"Generated", "", false},
+		{"Kept", "req-123.abc", true},
+		{"Unsafe replaced", "bad id\n", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/stats", nil)
+		if tt.incoming != "" {
+			req.Header.Set(RequestIDHeader, tt.incoming)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(RequestIDHeader)
+		if tt.keep && id != tt.incoming {
+			t.Errorf("%s: request ID %q, want %q", tt.name, id, tt.incoming)
+		}
+		if !tt.keep && (id == "" || id == tt.incoming) {
+			t.Errorf("%s: expected a generated request ID, got %q", tt.name, id)
+		}
+
+		var body models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid error body: %v", tt.name, err)
+		}
+		if body.Code != models.ErrorCodeUnauthorized || body.RequestID != id || body.Retryable {
+			t.Errorf("%s: unexpected error body %+v", tt.name, body)
+		}
+	}
+}
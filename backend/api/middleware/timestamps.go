@@ -0,0 +1,138 @@
+// File: backend/api/middleware/timestamps.go
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimestampsRFC3339 is the value of the timestamps query parameter, or the
+// Accept-Profile header, that asks for RFC3339 timestamps
+const TimestampsRFC3339 = "rfc3339"
+
+// timestampFields are the JSON fields that hold Unix timestamps in seconds
+var timestampFields = map[string]bool{
+	"createdUtc":  true,
+	"editedUtc":   true,
+	"createdAt":   true,
+	"updatedAt":   true,
+	"lastUpdated": true,
+	"generatedAt": true,
+	"nextUpdate":  true,
+	"lastSuccess": true,
+	"lastFailure": true,
+	"occurredAt":  true,
+	"observedAt":  true,
+	"resetAt":     true,
+}
+
+// Timestamps rewrites the Unix timestamps in JSON responses as RFC3339
+// strings in UTC, e.g. "2024-03-09T21:04:00Z", when the client asks for them
+// with ?timestamps=rfc3339 or "Accept-Profile: rfc3339". Unknown times
+// (zero) become null. Other responses, such as event streams, pass through.
+func Timestamps() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !wantsRFC3339(c) {
+			c.Next()
+			return
+		}
+
+		writer := &timestampWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.buffered {
+			body := writer.body.Bytes()
+			if converted, err := convertTimestamps(body); err == nil {
+				body = converted
+				c.Writer.Header().Del("Content-Length")
+			}
+			c.Writer.Write(body)
+		}
+	}
+}
+
+func wantsRFC3339(c *gin.Context) bool {
+	if strings.EqualFold(c.Query("timestamps"), TimestampsRFC3339) {
+		return true
+	}
+	for _, profile := range strings.Split(c.GetHeader("Accept-Profile"), ",") {
+		if strings.EqualFold(strings.Trim(strings.TrimSpace(profile), `"`), TimestampsRFC3339) {
+			return true
+		}
+	}
+	return false
+}
+
+// timestampWriter holds back JSON bodies so their timestamps can be
+// rewritten once the handler is done
+type timestampWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	buffered bool
+	decided  bool
+}
+
+func (w *timestampWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.buffered = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	if !w.buffered {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *timestampWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// convertTimestamps rewrites the timestamp fields anywhere in a JSON
+// document
+func convertTimestamps(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	return json.Marshal(rewriteTimestamps(document))
+}
+
+func rewriteTimestamps(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if number, ok := field.(json.Number); ok && timestampFields[key] {
+				v[key] = formatTimestamp(number)
+				continue
+			}
+			v[key] = rewriteTimestamps(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = rewriteTimestamps(item)
+		}
+	}
+	return value
+}
+
+// formatTimestamp formats Unix seconds as RFC3339, leaving values that
+// aren't whole seconds as they are
+func formatTimestamp(number json.Number) interface{} {
+	seconds, err := number.Int64()
+	if err != nil {
+		return number
+	}
+	if seconds == 0 {
+		return nil
+	}
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
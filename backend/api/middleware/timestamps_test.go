@@ -0,0 +1,81 @@
+// File: backend/api/middleware/timestamps_test.go
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimestamps(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Timestamps())
+	r.GET("/api/search", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"lastUpdated": 1710018240,
+			"totalCount":  2,
+			"results": []gin.H{
+				{"id": "a", "createdUtc": 1710018240, "score": 1710018240},
+				{"id": "b", "createdUtc": 0},
+			},
+		})
+	})
+	r.GET("/api/stream", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/event-stream", []byte("data: {\"createdUtc\": 1}\n\n"))
+	})
+
+	get := func(path, profile string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if profile != "" {
+			req.Header.Set("Accept-Profile", profile)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var unix struct {
+		LastUpdated int64 `json:"lastUpdated"`
+	}
+	if err := json.Unmarshal(get("/api/search", "").Body.Bytes(), &unix); err != nil || unix.LastUpdated != 1710018240 {
+		t.Errorf("Expected Unix timestamps by default, got %+v (%v)", unix, err)
+	}
+
+	for _, rec := range []*httptest.ResponseRecorder{
+		get("/api/search?timestamps=rfc3339", ""),
+		get("/api/search", `"rfc3339"`),
+	} {
+		var body struct {
+			LastUpdated string `json:"lastUpdated"`
+			TotalCount  int    `json:"totalCount"`
+			Results     []struct {
+				CreatedUTC *string `json:"createdUtc"`
+				Score      int64   `json:"score"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode %s: %v", rec.Body.String(), err)
+		}
+		if rec.Code != http.StatusOK || body.LastUpdated != "2024-03-09T21:04:00Z" || body.TotalCount != 2 {
+			t.Errorf("Unexpected response %d %+v", rec.Code, body)
+		}
+		if len(body.Results) != 2 || body.Results[0].CreatedUTC == nil || *body.Results[0].CreatedUTC != "2024-03-09T21:04:00Z" {
+			t.Errorf("Expected nested createdUtc as RFC3339, got %s", rec.Body.String())
+		}
+		if body.Results[0].Score != 1710018240 {
+			t.Errorf("Expected other numbers unchanged, got %d", body.Results[0].Score)
+		}
+		if body.Results[1].CreatedUTC != nil {
+			t.Errorf("Expected a zero timestamp as null, got %q", *body.Results[1].CreatedUTC)
+		}
+	}
+
+	if rec := get("/api/stream?timestamps=rfc3339", ""); rec.Body.String() != "data: {\"createdUtc\": 1}\n\n" {
+		t.Errorf("Expected event streams to pass through, got %q", rec.Body.String())
+	}
+}
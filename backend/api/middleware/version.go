@@ -0,0 +1,48 @@
+// File: backend/api/middleware/version.go
+
+package middleware
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader reports which API version served a response
+const APIVersionHeader = "API-Version"
+
+// versionPrefixPattern matches the version segment of a versioned API path,
+// e.g. "/api/v1" in "/api/v1/search"
+var versionPrefixPattern = regexp.MustCompile(`^/api/v[0-9]+(/|$)`)
+
+// APIVersion marks responses from a versioned route group, e.g. "v1"
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// UnversionedAPI serves the paths from before API versioning as aliases of
+// version, so existing clients keep working. Responses name the version
+// that served them and link to its path, e.g. "/api/v1/search" for
+// "/api/search".
+func UnversionedAPI(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := "/api/" + version + strings.TrimPrefix(c.Request.URL.Path, "/api")
+		c.Header(APIVersionHeader, version)
+		c.Header("Link", "<"+path+`>; rel="successor-version"`)
+		c.Next()
+	}
+}
+
+// unversionedPath strips the version from an API path, so "/api/v1/embed/x"
+// becomes "/api/embed/x". Other paths are returned as they are.
+func unversionedPath(path string) string {
+	match := versionPrefixPattern.FindStringSubmatch(path)
+	if match == nil {
+		return path
+	}
+	return "/api" + match[1] + path[len(match[0]):]
+}
@@ -0,0 +1,53 @@
+// File: backend/api/middleware/version_test.go
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIVersionRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	routes := func(api *gin.RouterGroup) {
+		api.GET("/search/:id", func(c *gin.Context) { c.String(http.StatusOK, c.Param("id")) })
+	}
+	routes(r.Group("/api/v1", APIVersion("v1")))
+	routes(r.Group("/api", UnversionedAPI("v1")))
+
+	for path, wantLink := range map[string]string{
+		"/api/v1/search/abc": "",
+		"/api/search/abc":    `</api/v1/search/abc>; rel="successor-version"`,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != "abc" {
+			t.Errorf("%s: got %d %q", path, rec.Code, rec.Body.String())
+		}
+		if got := rec.Header().Get(APIVersionHeader); got != "v1" {
+			t.Errorf("%s: %s %q, want v1", path, APIVersionHeader, got)
+		}
+		if got := rec.Header().Get("Link"); got != wantLink {
+			t.Errorf("%s: Link %q, want %q", path, got, wantLink)
+		}
+	}
+}
+
+func TestUnversionedPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"/api/v1/embed/abc": "/api/embed/abc",
+		"/api/v12/search":   "/api/search",
+		"/api/v1":           "/api",
+		"/api/embed/abc":    "/api/embed/abc",
+		"/api/vintage":      "/api/vintage",
+		"/health":           "/health",
+	} {
+		if got := unversionedPath(path); got != want {
+			t.Errorf("unversionedPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// File: backend/cmd/mcp/main.go
+
+// Command mcp serves Subplexity's Reddit tools over the Model Context
+// Protocol on stdin and stdout. Configure it in an MCP client such as
+// Claude Desktop as a stdio server whose command is this binary.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/mcp"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+// serverVersion is reported to MCP clients during initialization
+const serverVersion = "1.0.0"
+
+func main() {
+	// stdout carries protocol messages, so logs go to stderr only
+	log.SetOutput(os.Stderr)
+
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: No .env file found, using environment variables")
+	}
+
+	configPath := os.Getenv("CONFIG_FILE")
+	if configPath == "" {
+		configPath = "config.yaml"
+	}
+	redditClientID := os.Getenv("REDDIT_API_CLIENT_ID")
+	redditClientSecret := os.Getenv("REDDIT_API_CLIENT_SECRET")
+	if redditClientID == "" || redditClientSecret == "" {
+		log.Println("Warning: Reddit API credentials not set. Set REDDIT_API_CLIENT_ID and REDDIT_API_CLIENT_SECRET environment variables")
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	redditService := services.NewRedditService(redditClientID, redditClientSecret)
+	redditService.SetConcurrencyConfig(cfg.RedditConcurrency)
+
+	server := mcp.NewServer("subplexity", serverVersion, mcp.RedditTools(redditService))
+	// The client ends the session by closing stdin
+	log.Println("Serving MCP on stdio")
+	if err := server.Serve(context.Background(), os.Stdin, os.Stdout); err != nil {
+		log.Fatalf("MCP server failed: %v", err)
+	}
+}
@@ -4,20 +4,46 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net"
 	"net/http"  // Add this import
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/pranesh-j/subplexity/api/grpcserver"
 	"github.com/pranesh-j/subplexity/api/handlers"
+	"github.com/pranesh-j/subplexity/api/middleware"
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/budget"
+	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/contentpolicy"
+	"github.com/pranesh-j/subplexity/internal/digest"
+	"github.com/pranesh-j/subplexity/internal/embeddings"
+	"github.com/pranesh-j/subplexity/internal/indexer"
+	"github.com/pranesh-j/subplexity/internal/linkfetch"
+	"github.com/pranesh-j/subplexity/internal/moderation"
+	"github.com/pranesh-j/subplexity/internal/quota"
+	"github.com/pranesh-j/subplexity/internal/redditlogin"
 	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+	"github.com/pranesh-j/subplexity/internal/telegram"
+	"github.com/pranesh-j/subplexity/internal/transcripts"
+	"google.golang.org/grpc"
 )
 
+// cacheInvalidationChannel is the Redis pub/sub channel replicas share
+const cacheInvalidationChannel = "subplexity:cache-invalidation"
+
+// recentErrorLogSize is how many server errors the admin dashboard shows
+const recentErrorLogSize = 100
+
 func main() {
 	// Set up context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -39,6 +65,10 @@ func main() {
 
 	// Get required environment variables
 	port := getEnvWithDefault("PORT", "8080")
+	databasePath := getEnvWithDefault("DATABASE_PATH", "subplexity.db")
+	adminAPIKey := os.Getenv("ADMIN_API_KEY")
+	clientAPIKeys := strings.Split(os.Getenv("CLIENT_API_KEYS"), ",")
+	configPath := getEnvWithDefault("CONFIG_FILE", "config.yaml")
 	redditClientID := os.Getenv("REDDIT_API_CLIENT_ID")
 	redditClientSecret := os.Getenv("REDDIT_API_CLIENT_SECRET")
 
@@ -50,33 +80,167 @@ func main() {
 	// Check for AI model API keys
 	checkAICredentials()
 
+	// Load optional runtime configuration
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if origins := os.Getenv("CORS_ALLOW_ORIGINS"); origins != "" {
+		if err := cfg.CORS.OverrideOrigins(origins); err != nil {
+			log.Fatalf("Invalid CORS configuration: %v", err)
+		}
+	}
+
+	// Open persistent storage
+	dataStore, err := store.Open(databasePath)
+	if err != nil {
+		log.Fatalf("Failed to open database at %s: %v", databasePath, err)
+	}
+	defer dataStore.Close()
+
+	// Set up embeddings shared by semantic features
+	embeddingService, err := newEmbeddings(cfg.Embeddings)
+	if err != nil {
+		log.Fatalf("Failed to set up embeddings: %v", err)
+	}
+	defer embeddingService.Close()
+
 	// Initialize services
 	redditService := services.NewRedditService(redditClientID, redditClientSecret)
+	redditService.SetConcurrencyConfig(cfg.RedditConcurrency)
+	redditService.SetRegions(cfg.Regions)
 	aiService := services.NewAIService()
+	aiService.SetPromptConfig(cfg.Prompts)
+	aiService.SetVisionConfig(cfg.Vision)
+	if budgetTracker := newBudgetTracker(cfg.Budgets, dataStore); budgetTracker != nil {
+		aiService.SetBudget(budgetTracker)
+	}
 
 	// Initialize handlers
-	searchHandler := handlers.NewSearchHandler(redditService, aiService)
+	searchHandler := handlers.NewSearchHandler(redditService, aiService, dataStore)
+	moderator := newModerator(cfg.Moderation)
+	searchHandler.Pipeline.SetModerator(moderator)
+	searchHandler.Pipeline.SetSafeSearch(cfg.SafeSearch)
+	if cfg.SafeSearch.Enabled && moderator == nil {
+		log.Println("Warning: safe search is enabled without content moderation. NSFW results and excluded communities are still dropped.")
+	}
+	searchHandler.Pipeline.SetContentPolicy(contentpolicy.New(cfg.ContentPolicy))
+	searchHandler.Pipeline.SetLimits(cfg.Limits)
+	searchHandler.Pipeline.SetRequestRules(cfg.Requests)
+	if cfg.LinkFetching.Enabled {
+		searchHandler.Pipeline.SetLinkEnricher(linkfetch.New(cfg.LinkFetching))
+	}
+	if cfg.Transcripts.Enabled {
+		searchHandler.Pipeline.SetTranscriptEnricher(transcripts.New(cfg.Transcripts))
+	}
+	exportHandler := handlers.NewExportHandler(dataStore)
+	savedSearchHandler := handlers.NewSavedSearchHandler(dataStore, searchHandler)
+	historyHandler := handlers.NewHistoryHandler(dataStore)
+	annotationHandler := handlers.NewAnnotationHandler(dataStore)
+	bookmarkHandler := handlers.NewBookmarkHandler(dataStore)
+	collectionHandler := handlers.NewCollectionHandler(dataStore, searchHandler)
+	feedbackHandler := handlers.NewFeedbackHandler(dataStore)
+
+	// Share cache flushes with other replicas when Redis is configured
+	cacheBus, err := newCacheBus(ctx)
+	if err != nil {
+		log.Fatalf("Failed to set up cache invalidation: %v", err)
+	}
+	if cacheBus != nil {
+		defer cacheBus.Close()
+	}
+	cacheInvalidator := cache.NewInvalidator(cacheBus, redditService.FlushCaches)
+	go cacheInvalidator.Start(ctx)
+	adminHandler := handlers.NewAdminHandler(aiService, redditService, cacheInvalidator)
+	analyticsHandler := handlers.NewAnalyticsHandler(dataStore)
+
+	// Crawl configured subreddits in the background and answer matching
+	// searches from the local index
+	if cfg.Indexer.Enabled {
+		subredditIndexer := indexer.New(redditService, dataStore, embeddingService, cfg.Indexer)
+		searchHandler.Pipeline.SetLocalIndex(subredditIndexer)
+		go subredditIndexer.Start(ctx)
+	}
+
+	// Generate scheduled topic digests in the background
+	var digestScheduler *digest.Scheduler
+	if cfg.Digests.Enabled {
+		digestScheduler = digest.New(redditService, searchHandler.Pipeline, dataStore, cfg.Digests)
+		go digestScheduler.Start(ctx)
+	}
+	// Delete snapshots past their retention
+	if cfg.Snapshots.Retention > 0 {
+		go pruneSnapshots(ctx, dataStore, cfg.Snapshots)
+	}
+	// Pre-populate the search cache with recently popular searches
+	if cfg.CacheWarmup.Enabled {
+		go warmSearchCache(ctx, dataStore, searchHandler.Pipeline, cfg.CacheWarmup)
+	}
+	// Check AI models in the background so failing ones are left out of
+	// model selection
+	if cfg.AIProbes.Enabled {
+		go aiService.RunProbes(ctx, cfg.AIProbes)
+	}
+
+	digestHandler := handlers.NewDigestHandler(digestScheduler)
+	trendingHandler := handlers.NewTrendingHandler(redditService)
+	suggestHandler := handlers.NewSuggestHandler(dataStore, redditService)
+	commentsHandler := handlers.NewCommentsHandler(redditService)
+	modelsHandler := handlers.NewModelsHandler(aiService)
+	healthHandler := handlers.NewHealthHandler(redditService, aiService, dataStore)
+	errorLog := middleware.NewErrorLog(recentErrorLogSize)
+	dashboardHandler := handlers.NewDashboardHandler(healthHandler, searchHandler.Pipeline, errorLog)
+	graphqlHandler := handlers.NewGraphQLHandler(searchHandler, redditService)
+	sessions := newSessions(cfg.Accounts)
+	authHandler := handlers.NewAuthHandler(dataStore, sessions, cfg.Accounts)
+	redditLogin := newRedditLogin(cfg.Accounts.Reddit, sessions, dataStore, redditService)
+	searchHandler.RedditLogin = redditLogin
+	redditLoginHandler := handlers.NewRedditLoginHandler(redditLogin, sessions, cfg.Accounts.Reddit)
+	shareHandler := handlers.NewShareHandler(dataStore, newShareLinks(cfg.Sharing), cfg.Sharing)
+	telegramHandler := handlers.NewTelegramHandler(searchHandler, newTelegramBot(cfg.Telegram), os.Getenv("TELEGRAM_WEBHOOK_SECRET"), cfg.Telegram)
+	openapiHandler, err := handlers.NewOpenAPIHandler()
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
 
 	// Set up router - using production mode
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
+
+	// Keep recent server errors for the admin dashboard, including panics
+	// recovered below
+	r.Use(middleware.RecordErrors(errorLog))
 	r.Use(gin.Recovery())
 	r.Use(gin.Logger())
 
-	// Configure CORS for development and production
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "https://subplexity.vercel.app"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// API routes
-	api := r.Group("/api")
-	{
-		api.POST("/search", func(c *gin.Context) {
+	// Tag every request with an ID that error responses report back
+	r.Use(middleware.RequestID())
+
+	// Allow the configured frontends to call the API from the browser
+	r.Use(middleware.CORS(cfg.CORS))
+
+	// Refuse oversized request bodies before handlers read them
+	r.Use(middleware.MaxBodySize(int64(cfg.Requests.MaxBodyKB) * 1024))
+
+	// Let scripts and spreadsheets ask for RFC3339 instead of Unix timestamps
+	r.Use(middleware.Timestamps())
+
+	// Sign in requests carrying a session token, so per-client data and
+	// limits follow the account
+	r.Use(middleware.Authenticate(sessions))
+
+	// Give callers with an issued API key, listed comma-separated in
+	// CLIENT_API_KEYS, their own limits instead of their IP address's
+	r.Use(middleware.APIKeys(clientAPIKeys))
+
+	// Searches count against the caller's concurrency cap and quotas, shared
+	// by every route and version prefix
+	clientLimit := middleware.ClientConcurrency(cfg.Limits.MaxSearchesPerClient, cfg.Limits.ClientQueueTimeout)
+	quotaLimit := middleware.Quotas(newQuotaLimiter(cfg.Quotas, dataStore))
+
+	// API routes, registered once per version prefix
+	registerRoutes := func(api *gin.RouterGroup) {
+		api.POST("/search", clientLimit, quotaLimit, func(c *gin.Context) {
 			// Create a request-specific context with timeout
 			reqCtx, reqCancel := context.WithTimeout(ctx, 30*time.Second)
 			defer reqCancel()
@@ -86,18 +250,122 @@ func main() {
 			c.Set("requestContext", reqCtx)
 			searchHandler.HandleSearch(c)
 		})
-		
-		// Add health check endpoint
-		api.GET("/health", func(c *gin.Context) {
-			// Fixed: Using a simple static response instead of calling a non-existent method
-			c.JSON(200, gin.H{
-				"status":      "ok",
-				"time":        time.Now().Format(time.RFC3339),
-				"reddit_auth": "initialized", // Simplified status
-			})
-		})
+
+		// Ranked results without an answer, for clients that only want
+		// retrieval
+		api.GET("/search", clientLimit, quotaLimit, searchHandler.HandleSearchResults)
+
+		// The answer to a search that returned its results first
+		api.GET("/search/answers/:id", searchHandler.HandleAnswer)
+
+		// Run several searches concurrently under a shared time budget
+		api.POST("/search/batch", clientLimit, quotaLimit, searchHandler.HandleBatchSearch)
+
+		// Export persisted search snapshots
+		api.GET("/search/:id/export", exportHandler.HandleExport)
+		api.GET("/reports/:id", exportHandler.HandleReport)
+
+		// The caller's private notes on results, included in their exports
+		api.GET("/search/:id/annotations", annotationHandler.HandleList)
+		api.PUT("/search/:id/annotations/:resultId", annotationHandler.HandleSet)
+		api.DELETE("/search/:id/annotations/:resultId", annotationHandler.HandleDelete)
+
+		// Signed, expiring public links to answers
+		api.POST("/share", shareHandler.HandleCreate)
+		api.GET("/share/:token", shareHandler.HandleGet)
+
+		// Compact answer widgets for embedding in other sites
+		api.GET("/embed/:snapshotId", exportHandler.HandleEmbed)
+
+		// Saved searches: named queries that can be re-run later
+		api.GET("/saved-searches", savedSearchHandler.HandleList)
+		api.POST("/saved-searches", savedSearchHandler.HandleCreate)
+		api.GET("/saved-searches/:id", savedSearchHandler.HandleGet)
+		api.PUT("/saved-searches/:id", savedSearchHandler.HandleUpdate)
+		api.DELETE("/saved-searches/:id", savedSearchHandler.HandleDelete)
+		api.POST("/saved-searches/:id/run", clientLimit, quotaLimit, savedSearchHandler.HandleRun)
+
+		// Bookmarked results and answers of the calling client
+		api.GET("/bookmarks", bookmarkHandler.HandleList)
+		api.POST("/bookmarks", bookmarkHandler.HandleCreate)
+		api.GET("/bookmarks/tags", bookmarkHandler.HandleTags)
+		api.GET("/bookmarks/:id", bookmarkHandler.HandleGet)
+		api.PUT("/bookmarks/:id", bookmarkHandler.HandleUpdate)
+		api.DELETE("/bookmarks/:id", bookmarkHandler.HandleDelete)
+
+		// Research collections of the calling client
+		api.GET("/collections", collectionHandler.HandleList)
+		api.POST("/collections", collectionHandler.HandleCreate)
+		api.GET("/collections/:id", collectionHandler.HandleGet)
+		api.PUT("/collections/:id", collectionHandler.HandleUpdate)
+		api.DELETE("/collections/:id", collectionHandler.HandleDelete)
+		api.POST("/collections/:id/items", collectionHandler.HandleAddItems)
+		api.DELETE("/collections/:id/items", collectionHandler.HandleRemoveItem)
+		api.POST("/collections/:id/synthesize", clientLimit, quotaLimit, collectionHandler.HandleSynthesize)
+
+		// Search history for the calling client
+		api.GET("/history", historyHandler.HandleList)
+		api.DELETE("/history", historyHandler.HandleClear)
+		api.DELETE("/history/:id", historyHandler.HandleDelete)
+
+		// Thumbs up/down on answers
+		api.POST("/feedback", feedbackHandler.HandleFeedback)
+
+		// Selectable AI models with credential and health status
+		api.GET("/models", modelsHandler.HandleList)
+
+		// Hot posts and popular communities
+		api.GET("/trending", trendingHandler.HandleTrending)
+
+		// Typeahead suggestions from past searches and subreddit names
+		api.GET("/suggest", suggestHandler.HandleSuggest)
+
+		// Comment trees for posts
+		api.GET("/comments/:postId", commentsHandler.HandleComments)
+
+		// Search, posts, subreddits and users over GraphQL
+		api.POST("/graphql", clientLimit, quotaLimit, graphqlHandler.HandleGraphQL)
+
+		// User accounts and sessions
+		api.POST("/auth/register", authHandler.HandleRegister)
+		api.POST("/auth/login", authHandler.HandleLogin)
+		api.GET("/auth/me", authHandler.HandleMe)
+		api.GET("/auth/reddit/login", redditLoginHandler.HandleLogin)
+		api.GET("/auth/reddit/callback", redditLoginHandler.HandleCallback)
+
+		// Scheduled AI digests of subreddit activity
+		api.GET("/digests/:topic", digestHandler.HandleGet)
+
+		// Telegram bot updates, authenticated by TELEGRAM_WEBHOOK_SECRET
+		api.POST("/telegram/webhook", telegramHandler.HandleWebhook)
+
+		// Operational endpoints, guarded by ADMIN_API_KEY
+		admin := api.Group("/admin", middleware.RequireAdmin(adminAPIKey))
+		{
+			admin.POST("/templates/reload", adminHandler.HandleReloadTemplates)
+			admin.GET("/cache/stats", adminHandler.HandleCacheStats)
+			admin.POST("/cache/flush", adminHandler.HandleFlushCache)
+			admin.GET("/reddit/concurrency", adminHandler.HandleGetConcurrency)
+			admin.PUT("/reddit/concurrency", adminHandler.HandleUpdateConcurrency)
+			admin.GET("/analytics", analyticsHandler.HandleGet)
+			admin.GET("/dashboard", dashboardHandler.HandleDashboard)
+		}
+
+		// Dependency health; 503 when a critical dependency is down
+		api.GET("/health", healthHandler.HandleHealth)
+
+		// OpenAPI document and Swagger UI for integrators
+		api.GET("/openapi.json", openapiHandler.HandleSpec)
+		api.GET("/docs", openapiHandler.HandleDocs)
 	}
 
+	// Versioned API. Responses only gain fields within a version; see
+	// models.SearchResponse for the compatibility policy.
+	registerRoutes(r.Group("/api/"+handlers.APIVersion, middleware.APIVersion(handlers.APIVersion)))
+
+	// The unversioned paths predate /api/v1 and remain aliases of it
+	registerRoutes(r.Group("/api", middleware.UnversionedAPI(handlers.APIVersion)))
+
 	// Start server with graceful shutdown
 	log.Printf("Server starting on port %s\n", port)
 	
@@ -121,6 +389,23 @@ func main() {
 		}
 	}()
 	
+	// Serve the gRPC SearchService alongside HTTP for other services
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		listener, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on %s: %v", cfg.GRPC.Address, err)
+		}
+		grpcServer = grpc.NewServer()
+		grpcserver.New(searchHandler.Pipeline, redditService, dataStore).Register(grpcServer)
+		go func() {
+			log.Printf("gRPC server starting on %s", cfg.GRPC.Address)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for context cancellation (from signal or other shutdown trigger)
 	<-ctx.Done()
 	
@@ -133,10 +418,28 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server shutdown error: %v", err)
 	}
+	if grpcServer != nil {
+		stopGRPC(shutdownCtx, grpcServer)
+	}
 	
 	log.Println("Server gracefully stopped")
 }
 
+// stopGRPC lets in-flight gRPC calls finish, cutting off any still running
+// when ctx expires
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
 // getEnvWithDefault returns the value of an environment variable or a default value
 func getEnvWithDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -167,4 +470,217 @@ func checkAICredentials() {
 	if os.Getenv("DEEPSEEK_API_KEY") == "" {
 		log.Println("Warning: DEEPSEEK_API_KEY not set. DeepSeek models will use mock responses.")
 	}
-}
\ No newline at end of file
+}
+
+// newModerator creates the content moderation service, or returns nil when
+// moderation is disabled or the provider has no credentials
+func newModerator(cfg config.ModerationConfig) *moderation.Service {
+	if !cfg.Enabled {
+		log.Println("Content moderation disabled by configuration")
+		return nil
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		log.Println("Warning: OPENAI_API_KEY not set. Content moderation is disabled.")
+		return nil
+	}
+
+	return moderation.NewService(moderation.NewOpenAIModerator(apiKey, cfg.Model), cfg)
+}
+
+// newSessions creates the session signer for user accounts, or returns nil
+// when accounts are disabled or JWT_SECRET is missing or too short
+func newSessions(cfg config.AccountsConfig) *auth.Sessions {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	sessions, err := auth.NewSessions([]byte(os.Getenv("JWT_SECRET")), cfg.SessionTTL)
+	if err != nil {
+		log.Printf("Warning: JWT_SECRET is not usable (%v). User accounts are disabled.", err)
+		return nil
+	}
+	return sessions
+}
+
+// newRedditLogin creates the Reddit login service, or returns nil when it's
+// disabled or accounts, the Reddit app credentials or REDDIT_TOKEN_KEY are
+// missing
+func newRedditLogin(cfg config.RedditLoginConfig, sessions *auth.Sessions, dataStore *store.Store, redditService *services.RedditService) *redditlogin.Service {
+	if !cfg.Enabled {
+		return nil
+	}
+	if sessions == nil {
+		log.Println("Warning: accounts are disabled. Signing in with Reddit is disabled.")
+		return nil
+	}
+	if !redditService.Auth().HasCredentials() {
+		log.Println("Warning: REDDIT_API_CLIENT_ID or REDDIT_API_CLIENT_SECRET not set. Signing in with Reddit is disabled.")
+		return nil
+	}
+
+	sealer, err := auth.NewSealer([]byte(os.Getenv("REDDIT_TOKEN_KEY")))
+	if err != nil {
+		log.Printf("Warning: REDDIT_TOKEN_KEY is not usable (%v). Signing in with Reddit is disabled.", err)
+		return nil
+	}
+	return redditlogin.New(dataStore, redditService, sealer, cfg.RedirectURL)
+}
+
+// newShareLinks creates the signer for shared answer links, or returns nil
+// when sharing is disabled or SHARE_SECRET is missing or too short
+func newShareLinks(cfg config.SharingConfig) *auth.Links {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	links, err := auth.NewLinks([]byte(os.Getenv("SHARE_SECRET")))
+	if err != nil {
+		log.Printf("Warning: SHARE_SECRET is not usable (%v). Sharing is disabled.", err)
+		return nil
+	}
+	return links
+}
+
+// newQuotaLimiter creates the per-client quota limiter, or returns nil when
+// quotas are disabled
+func newQuotaLimiter(cfg config.QuotasConfig, dataStore *store.Store) *quota.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	log.Printf("Quotas: %d requests and %d tokens per day, %d requests and %d tokens per month (0 is unlimited)",
+		cfg.DailyRequests, cfg.DailyTokens, cfg.MonthlyRequests, cfg.MonthlyTokens)
+	return quota.New(dataStore, cfg)
+}
+
+// newBudgetTracker creates the AI spend budget tracker, or returns nil when
+// budgets are disabled
+func newBudgetTracker(cfg config.BudgetsConfig, dataStore *store.Store) *budget.Tracker {
+	if !cfg.Enabled {
+		return nil
+	}
+	for provider, limits := range cfg.Providers {
+		log.Printf("AI budget for %s: $%.2f per day, $%.2f per month (0 is unlimited)", provider, limits.DailyUSD, limits.MonthlyUSD)
+	}
+	return budget.New(dataStore, cfg)
+}
+
+// newTelegramBot creates the Telegram bot client, or returns nil when the
+// bot is disabled or its token or webhook secret is missing
+func newTelegramBot(cfg config.TelegramConfig) *telegram.Bot {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" || os.Getenv("TELEGRAM_WEBHOOK_SECRET") == "" {
+		log.Println("Warning: TELEGRAM_BOT_TOKEN or TELEGRAM_WEBHOOK_SECRET not set. Telegram bot is disabled.")
+		return nil
+	}
+
+	return telegram.NewBot(token)
+}
+
+// warmSearchCache replays the most frequent recent searches from history so
+// their results are cached before users ask for them
+func warmSearchCache(ctx context.Context, dataStore *store.Store, pipeline *services.SearchPipeline, cfg config.CacheWarmupConfig) {
+	searches, err := dataStore.PopularSearches(ctx, time.Now().Add(-cfg.Lookback), cfg.Queries)
+	if err != nil {
+		log.Printf("Cache warm-up skipped: %v", err)
+		return
+	}
+	if len(searches) == 0 {
+		return
+	}
+
+	start := time.Now()
+	warmed := pipeline.Warm(ctx, searches)
+	log.Printf("Cache warm-up: %d of %d popular searches cached in %s", warmed, len(searches), time.Since(start).Round(time.Millisecond))
+}
+
+// pruneSnapshots deletes snapshots older than the retention now and then
+// every prune interval, until ctx is cancelled
+func pruneSnapshots(ctx context.Context, dataStore *store.Store, cfg config.SnapshotsConfig) {
+	ticker := time.NewTicker(cfg.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		removed, err := dataStore.PruneSnapshots(ctx, time.Now().Add(-cfg.Retention))
+		if err != nil {
+			log.Printf("Failed to prune snapshots: %v", err)
+		} else if removed > 0 {
+			log.Printf("Pruned %d snapshots older than %s", removed, cfg.Retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newCacheBus connects to Redis at REDIS_URL for cache invalidation across
+// replicas, or returns nil when it is not set
+func newCacheBus(ctx context.Context) (cache.Bus, error) {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	bus, err := cache.NewRedisBus(ctx, redisURL, cacheInvalidationChannel)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Cache invalidation shared via Redis")
+	return bus, nil
+}
+
+// newEmbeddings creates the embedding service from configuration, or returns
+// nil when embeddings are disabled. The OpenAI provider falls back to local
+// hashing when OPENAI_API_KEY is missing.
+func newEmbeddings(cfg config.EmbeddingsConfig) (*embeddings.Service, error) {
+	if !cfg.Enabled {
+		log.Println("Embeddings disabled by configuration")
+		return nil, nil
+	}
+
+	var provider embeddings.Provider = embeddings.NewLocalProvider(cfg.Dimensions)
+	if cfg.Provider == config.EmbeddingProviderOpenAI {
+		if apiKey := os.Getenv("OPENAI_API_KEY"); apiKey != "" {
+			provider = embeddings.NewOpenAIProvider(apiKey, cfg.Model, cfg.Dimensions)
+		} else {
+			log.Println("Warning: OPENAI_API_KEY not set. Using local embeddings instead.")
+		}
+	}
+
+	var vectorStore embeddings.VectorStore
+	switch cfg.Store {
+	case config.VectorStoreSQLite:
+		sqliteStore, err := embeddings.OpenSQLiteStore(cfg.SQLitePath, cfg.Dimensions)
+		if err != nil {
+			return nil, err
+		}
+		vectorStore = sqliteStore
+	case config.VectorStorePgvector:
+		dsn := os.Getenv("EMBEDDINGS_DATABASE_URL")
+		if dsn == "" {
+			return nil, fmt.Errorf("the pgvector store needs EMBEDDINGS_DATABASE_URL")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		pgStore, err := embeddings.OpenPgvectorStore(ctx, dsn, cfg.Dimensions)
+		if err != nil {
+			return nil, err
+		}
+		vectorStore = pgStore
+	default:
+		vectorStore = embeddings.NewMemoryStore(cfg.Dimensions)
+	}
+
+	log.Printf("Embeddings: %s provider, %s store", provider.Name(), cfg.Store)
+	return embeddings.NewService(provider, vectorStore), nil
+}
@@ -0,0 +1,150 @@
+// File: backend/internal/auth/auth_test.go
+
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte(strings.Repeat("s", MinSecretLength))
+
+func TestSessions(t *testing.T) {
+	if _, err := NewSessions([]byte("short"), time.Hour); err == nil {
+		t.Error("Expected a short secret to be rejected")
+	}
+
+	sessions, err := NewSessions(testSecret, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	sessions.now = func() time.Time { return now }
+
+	token, expires, err := sessions.Issue("account-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !LooksLikeToken(token) || !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected token %q expiring %s", token, expires)
+	}
+	if id, err := sessions.Verify(token); err != nil || id != "account-1" {
+		t.Errorf("Verify() = %q, %v", id, err)
+	}
+
+	other, _ := NewSessions([]byte(strings.Repeat("o", MinSecretLength)), time.Hour)
+	parts := strings.Split(token, ".")
+	for name, invalid := range map[string]string{
+		"Garbage":      "not.a.token",
+		"Tampered":     parts[0] + "." + parts[1] + "x." + parts[2],
+		"Other secret": mustIssue(t, other),
+	} {
+		if _, err := sessions.Verify(invalid); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := sessions.Verify(token); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("Expected ErrExpiredToken, got %v", err)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !CheckPassword(hash, "correct horse") {
+		t.Error("Expected the password to match")
+	}
+	if CheckPassword(hash, "wrong horse") || CheckPassword("", "correct horse") {
+		t.Error("Expected wrong passwords and missing accounts not to match")
+	}
+}
+
+func mustIssue(t *testing.T, sessions *Sessions) string {
+	t.Helper()
+	token, _, err := sessions.Issue("account-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return token
+}
+
+func TestSealer(t *testing.T) {
+	if _, err := NewSealer([]byte("short")); err == nil {
+		t.Error("Expected a short secret to be rejected")
+	}
+
+	sealer, err := NewSealer(testSecret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sealed, err := sealer.Seal([]byte("refresh-token"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(sealed, "refresh-token") {
+		t.Error("Expected the sealed value not to contain the plaintext")
+	}
+	if again, _ := sealer.Seal([]byte("refresh-token")); again == sealed {
+		t.Error("Expected each seal to use a fresh nonce")
+	}
+
+	opened, err := sealer.Open(sealed)
+	if err != nil || string(opened) != "refresh-token" {
+		t.Errorf("Open() = %q, %v", opened, err)
+	}
+
+	other, _ := NewSealer([]byte(strings.Repeat("o", MinSecretLength)))
+	if _, err := other.Open(sealed); !errors.Is(err, ErrUnsealable) {
+		t.Errorf("Expected another key to fail with ErrUnsealable, got %v", err)
+	}
+	tampered := []byte(sealed)
+	tampered[len(tampered)/2] ^= 1
+	if _, err := sealer.Open(string(tampered)); !errors.Is(err, ErrUnsealable) {
+		t.Errorf("Expected a tampered value to fail with ErrUnsealable, got %v", err)
+	}
+}
+
+func TestLinks(t *testing.T) {
+	if _, err := NewLinks([]byte("short")); err == nil {
+		t.Error("Expected a short secret to be rejected")
+	}
+
+	links, err := NewLinks(testSecret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	links.now = func() time.Time { return now }
+
+	token, err := links.Sign("snapshot-1", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if subject, err := links.Verify(token); err != nil || subject != "snapshot-1" {
+		t.Errorf("Verify() = %q, %v", subject, err)
+	}
+
+	other, _ := NewLinks([]byte(strings.Repeat("o", MinSecretLength)))
+	otherToken, _ := other.Sign("snapshot-1", now.Add(time.Hour))
+	payload, signature, _ := strings.Cut(token, ".")
+	for name, invalid := range map[string]string{
+		"Garbage":      "garbage",
+		"Tampered":     payload + "x." + signature,
+		"Other secret": otherToken,
+	} {
+		if _, err := links.Verify(invalid); !errors.Is(err, ErrInvalidLink) {
+			t.Errorf("%s: expected ErrInvalidLink, got %v", name, err)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := links.Verify(token); !errors.Is(err, ErrExpiredLink) {
+		t.Errorf("Expected ErrExpiredLink, got %v", err)
+	}
+}
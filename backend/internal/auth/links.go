@@ -0,0 +1,81 @@
+// File: backend/internal/auth/links.go
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidLink is returned for link tokens that are malformed or
+	// weren't signed with this server's secret
+	ErrInvalidLink = errors.New("invalid link")
+	// ErrExpiredLink is returned for link tokens past their expiry
+	ErrExpiredLink = errors.New("link has expired")
+)
+
+// linkClaims are what a link token vouches for
+type linkClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Links signs and verifies expiring public links to a resource, such as a
+// shared answer. Links can't be revoked individually; changing the secret
+// invalidates every link.
+type Links struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewLinks creates a link signer using secret
+func NewLinks(secret []byte) (*Links, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("link secret must be at least %d bytes, got %d", MinSecretLength, len(secret))
+	}
+	return &Links{secret: secret, now: time.Now}, nil
+}
+
+// Sign returns a URL-safe token for subject that is valid until expires
+func (l *Links) Sign(subject string, expires time.Time) (string, error) {
+	payload, err := json.Marshal(linkClaims{Subject: subject, ExpiresAt: expires.Unix()})
+	if err != nil {
+		return "", fmt.Errorf("error encoding link claims: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + l.sign(unsigned), nil
+}
+
+// Verify checks a token's signature and expiry and returns its subject
+func (l *Links) Verify(token string) (string, error) {
+	unsigned, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(l.sign(unsigned))) {
+		return "", ErrInvalidLink
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(unsigned)
+	if err != nil {
+		return "", ErrInvalidLink
+	}
+	var c linkClaims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return "", ErrInvalidLink
+	}
+	if l.now().Unix() >= c.ExpiresAt {
+		return "", ErrExpiredLink
+	}
+	return c.Subject, nil
+}
+
+func (l *Links) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
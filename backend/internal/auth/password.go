@@ -0,0 +1,36 @@
+// File: backend/internal/auth/password.go
+
+package auth
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength is the longest password accepted, in bytes; bcrypt
+// ignores anything beyond it
+const MaxPasswordLength = 72
+
+// dummyHash is compared against when a login names an unknown account, so
+// the response takes as long as for a wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("subplexity"), bcrypt.DefaultCost)
+
+// HashPassword returns a salted bcrypt hash of password for storage
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. An empty hash, for an
+// account that doesn't exist, never matches but takes as long to check.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
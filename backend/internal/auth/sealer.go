@@ -0,0 +1,65 @@
+// File: backend/internal/auth/sealer.go
+
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrUnsealable is returned for sealed values that were tampered with or
+// sealed with another key
+var ErrUnsealable = errors.New("sealed value can't be opened")
+
+// Sealer encrypts and authenticates small values, such as OAuth tokens kept
+// in the database, with AES-256-GCM
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer creates a sealer whose key is derived from secret. Values sealed
+// with one secret can't be opened after it changes.
+func NewSealer(secret []byte) (*Sealer, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("encryption secret must be at least %d bytes, got %d", MinSecretLength, len(secret))
+	}
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// Seal encrypts plaintext, returning it with a random nonce as URL-safe text
+func (s *Sealer) Seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value from Seal
+func (s *Sealer) Open(sealed string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return nil, ErrUnsealable
+	}
+	nonce, ciphertext := raw[:s.aead.NonceSize()], raw[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrUnsealable
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,167 @@
+// File: backend/internal/budget/budget.go
+
+// Package budget caps the estimated cost of each AI provider's calls per UTC
+// day and month, with spend kept in the store so it survives restarts and is
+// shared by replicas using the same database. Exceeding a budget logs an
+// alert and sends it to a webhook, once per provider and period.
+package budget
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// webhookTimeout bounds delivering an alert
+const webhookTimeout = 10 * time.Second
+
+// Alert is sent to the webhook when a provider exceeds a budget
+type Alert struct {
+	Provider  string  `json:"provider"`
+	Period    string  `json:"period"` // "daily" or "monthly"
+	BudgetUSD float64 `json:"budgetUsd"`
+	SpentUSD  float64 `json:"spentUsd"`
+	// Fallback is the model answering the provider's calls until the period
+	// ends, or "mock" for mock responses
+	Fallback string `json:"fallback"`
+	Time     string `json:"time"` // RFC 3339
+}
+
+// Tracker checks and records AI providers' spend. It implements
+// services.SpendBudget.
+type Tracker struct {
+	store  *store.Store
+	cfg    config.BudgetsConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu sync.Mutex
+	// alerted holds the provider periods already alerted, e.g.
+	// "Anthropic daily 2024-03-02"
+	alerted map[string]bool
+}
+
+// New creates a tracker storing spend in dataStore
+func New(dataStore *store.Store, cfg config.BudgetsConfig) *Tracker {
+	return &Tracker{
+		store:   dataStore,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: webhookTimeout},
+		now:     time.Now,
+		alerted: make(map[string]bool),
+	}
+}
+
+// Exceeded reports whether provider has spent its daily or monthly budget,
+// and the model to answer its calls instead; an empty fallback means mock
+// responses. Spend that can't be read is logged and treated as within
+// budget, so a database problem doesn't stop answers.
+func (t *Tracker) Exceeded(ctx context.Context, provider string) (string, bool) {
+	budget, ok := t.cfg.Providers[provider]
+	if !ok || (budget.DailyUSD <= 0 && budget.MonthlyUSD <= 0) {
+		return "", false
+	}
+
+	day := t.now().UTC().Format(store.QuotaDayLayout)
+	daily, monthly, err := t.store.AISpend(ctx, provider, day)
+	if err != nil {
+		log.Printf("Failed to check %s AI budget: %v", provider, err)
+		return "", false
+	}
+
+	for _, period := range []struct {
+		name   string
+		key    string
+		spent  int64
+		budget float64
+	}{
+		{"daily", day, daily, budget.DailyUSD},
+		{"monthly", day[:len("2006-01")], monthly, budget.MonthlyUSD},
+	} {
+		if period.budget > 0 && float64(period.spent) >= period.budget*1e6 {
+			t.alert(provider, period.name, period.key, float64(period.spent)/1e6, period.budget, budget.FallbackModel)
+			return budget.FallbackModel, true
+		}
+	}
+	return "", false
+}
+
+// Charge records the estimated cost of a call to provider. Providers without
+// prices cost nothing.
+func (t *Tracker) Charge(ctx context.Context, provider string, promptTokens, responseTokens int64) {
+	budget, ok := t.cfg.Providers[provider]
+	if !ok {
+		return
+	}
+
+	// Prices per million tokens are millionths of a dollar per token
+	cost := int64(math.Round(float64(promptTokens)*budget.InputPerMillion + float64(responseTokens)*budget.OutputPerMillion))
+	if cost <= 0 {
+		return
+	}
+	day := t.now().UTC().Format(store.QuotaDayLayout)
+	if err := t.store.AddAISpend(ctx, provider, day, cost); err != nil {
+		log.Printf("Failed to record %s AI spend: %v", provider, err)
+	}
+}
+
+// alert logs and sends the first alert for a provider's period
+func (t *Tracker) alert(provider, period, key string, spent, budget float64, fallback string) {
+	t.mu.Lock()
+	alertKey := fmt.Sprintf("%s %s %s", provider, period, key)
+	if t.alerted[alertKey] {
+		t.mu.Unlock()
+		return
+	}
+	t.alerted[alertKey] = true
+	t.mu.Unlock()
+
+	if fallback == "" {
+		fallback = "mock"
+	}
+	log.Printf("ALERT: %s exceeded its %s AI budget ($%.2f of $%.2f); answering with %s until it resets",
+		provider, period, spent, budget, fallback)
+
+	if t.cfg.WebhookURL == "" {
+		return
+	}
+	alert := Alert{
+		Provider:  provider,
+		Period:    period,
+		BudgetUSD: budget,
+		SpentUSD:  spent,
+		Fallback:  fallback,
+		Time:      t.now().UTC().Format(time.RFC3339),
+	}
+	go func() {
+		if err := t.send(alert); err != nil {
+			log.Printf("Failed to send AI budget alert: %v", err)
+		}
+	}()
+}
+
+// send posts an alert to the webhook
+func (t *Tracker) send(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("error encoding alert: %w", err)
+	}
+	resp, err := t.client.Post(t.cfg.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error posting alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// File: backend/internal/budget/budget_test.go
+
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+func TestTracker(t *testing.T) {
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer dataStore.Close()
+
+	alerts := make(chan Alert, 4)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("Failed to decode alert: %v", err)
+		}
+		alerts <- alert
+	}))
+	defer webhook.Close()
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC)
+	tracker := New(dataStore, config.BudgetsConfig{
+		Enabled:    true,
+		WebhookURL: webhook.URL,
+		Providers: map[string]config.ProviderBudget{
+			"Anthropic": {DailyUSD: 1, MonthlyUSD: 10, InputPerMillion: 3, OutputPerMillion: 15, FallbackModel: "Google Gemini"},
+			"Google":    {InputPerMillion: 1},
+		},
+	})
+	tracker.now = func() time.Time { return now }
+
+	// 100k prompt and 20k response tokens cost $0.30 + $0.30
+	tracker.Charge(ctx, "Anthropic", 100000, 20000)
+	if _, exceeded := tracker.Exceeded(ctx, "Anthropic"); exceeded {
+		t.Fatalf("Expected $0.60 to be within the $1 daily budget")
+	}
+	tracker.Charge(ctx, "Anthropic", 100000, 20000)
+	fallback, exceeded := tracker.Exceeded(ctx, "Anthropic")
+	if !exceeded || fallback != "Google Gemini" {
+		t.Fatalf("Expected $1.20 to exceed the daily budget with a fallback, got %q, %v", fallback, exceeded)
+	}
+
+	// The alert is sent once per period
+	tracker.Exceeded(ctx, "Anthropic")
+	select {
+	case alert := <-alerts:
+		if alert.Provider != "Anthropic" || alert.Period != "daily" || alert.SpentUSD != 1.2 || alert.Fallback != "Google Gemini" {
+			t.Errorf("Unexpected alert %+v", alert)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected an alert on the webhook")
+	}
+	select {
+	case alert := <-alerts:
+		t.Errorf("Expected a single alert, got another %+v", alert)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Providers without budgets are never exceeded, and the budget resets
+	// with the day
+	tracker.Charge(ctx, "Google", 5000000, 0)
+	if _, exceeded := tracker.Exceeded(ctx, "Google"); exceeded {
+		t.Errorf("Expected Google without a budget never to be exceeded")
+	}
+	now = now.Add(2 * time.Hour)
+	if _, exceeded := tracker.Exceeded(ctx, "Anthropic"); exceeded {
+		t.Errorf("Expected the daily budget to reset at midnight")
+	}
+}
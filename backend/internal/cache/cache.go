@@ -5,18 +5,24 @@ package cache
 import (
 	"container/list"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Item represents a cached item with expiration
-type Item struct {
+type Item[V any] struct {
 	Key        string
-	Value      interface{}
+	Value      V
 	Expiration int64
+	Created    int64 // UnixNano time the item was stored
+
+	packed []byte // Compressed encoding of Value, which is then left zero
+	size   int64  // Size charged against the cache budget
 }
 
-// Cache provides a thread-safe LRU cache implementation with TTL
-type Cache struct {
+// Cache provides a thread-safe LRU cache implementation with TTL. V is the
+// type of the cached values, so lookups need no type assertions.
+type Cache[V any] struct {
 	maxItems    int
 	items       map[string]*list.Element
 	evictList   *list.List
@@ -26,6 +32,27 @@ type Cache struct {
 	onEvict     func(key string, value interface{})
 	sizeBytes   int64
 	maxSizeBytes int64
+	compressThreshold int
+
+	// Counters reported by Stats
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	expired   atomic.Uint64
+}
+
+// Stats is a snapshot of cache usage since it was created
+type Stats struct {
+	Hits         uint64  `json:"hits"`
+	Misses       uint64  `json:"misses"`
+	HitRate      float64 `json:"hitRate"`   // Hits / (hits + misses), 0 before any lookup
+	Evictions    uint64  `json:"evictions"` // Entries dropped to make room
+	Expired      uint64  `json:"expired"`   // Entries dropped because their TTL passed
+	Entries      int     `json:"entries"`
+	MaxEntries   int     `json:"maxEntries"`
+	Compressed   int     `json:"compressed"` // Entries currently stored compressed
+	SizeBytes    int64   `json:"sizeBytes"`
+	MaxSizeBytes int64   `json:"maxSizeBytes"`
 }
 
 // Config holds cache configuration options
@@ -35,6 +62,10 @@ type Config struct {
 	MaxSizeBytes int64
 	CleanupInterval time.Duration
 	OnEvict     func(key string, value interface{})
+	// CompressThreshold enables compression: values are sized by their JSON
+	// encoding, and those larger than this many bytes are stored compressed
+	// and decoded on read. 0 disables it.
+	CompressThreshold int
 }
 
 // DefaultConfig creates a default cache configuration
@@ -45,11 +76,12 @@ func DefaultConfig() Config {
 		MaxSizeBytes:   50 * 1024 * 1024, // 50MB
 		CleanupInterval: time.Minute,
 		OnEvict:        nil,
+		CompressThreshold: 4 * 1024, // 4KB
 	}
 }
 
-// NewCache creates a new cache with the given configuration
-func NewCache(config Config) *Cache {
+// NewCache creates a new cache of V values with the given configuration
+func NewCache[V any](config Config) *Cache[V] {
 	if config.MaxItems <= 0 {
 		config.MaxItems = DefaultConfig().MaxItems
 	}
@@ -63,13 +95,14 @@ func NewCache(config Config) *Cache {
 		config.CleanupInterval = DefaultConfig().CleanupInterval
 	}
 
-	cache := &Cache{
+	cache := &Cache[V]{
 		maxItems:     config.MaxItems,
 		items:        make(map[string]*list.Element, config.MaxItems),
 		evictList:    list.New(),
 		defaultTTL:   config.DefaultTTL,
 		onEvict:      config.OnEvict,
 		maxSizeBytes: config.MaxSizeBytes,
+		compressThreshold: config.CompressThreshold,
 	}
 	
 	// Start the background cleanup process
@@ -78,33 +111,45 @@ func NewCache(config Config) *Cache {
 		stop:     make(chan struct{}),
 	}
 	
-	go cache.janitor.run(cache)
+	go cache.janitor.run(cache.deleteExpired)
 	
 	return cache
 }
 
 // Set adds an item to the cache with the default TTL
-func (c *Cache) Set(key string, value interface{}) {
+func (c *Cache[V]) Set(key string, value V) {
 	c.SetWithTTL(key, value, c.defaultTTL)
 }
 
 // SetWithTTL adds an item to the cache with a specific TTL
-func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
-	// Estimate size (rough approximation)
-	size := estimateSize(value)
+func (c *Cache[V]) SetWithTTL(key string, value V, ttl time.Duration) {
+	// Create cache entry, compressing large values if enabled
+	item := &Item[V]{Key: key}
+	if c.compressThreshold > 0 {
+		packed, size, err := pack(value, c.compressThreshold)
+		if err != nil {
+			// Values that can't be encoded are not cached
+			return
+		}
+		if packed != nil {
+			item.packed = packed
+		} else {
+			item.Value = value
+		}
+		item.size = size
+	} else {
+		// Estimate size (rough approximation)
+		item.Value = value
+		item.size = estimateSize(value)
+	}
+	size := item.size
 	
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
 	// Check if item is already in cache and remove it
 	if element, exists := c.items[key]; exists {
-		c.evictList.Remove(element)
-		delete(c.items, key)
-		item := element.Value.(*Item)
-		c.sizeBytes -= estimateSize(item.Value)
-		if c.onEvict != nil {
-			c.onEvict(key, item.Value)
-		}
+		c.removeElement(element)
 	}
 	
 	// Check if adding this would exceed size limit
@@ -117,15 +162,10 @@ func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
 		c.evictOldest()
 	}
 	
-	// Create expiration time
-	expiration := time.Now().Add(ttl).UnixNano()
-	
-	// Create cache entry
-	item := &Item{
-		Key:        key,
-		Value:      value,
-		Expiration: expiration,
-	}
+	// Set expiration time
+	now := time.Now()
+	item.Expiration = now.Add(ttl).UnixNano()
+	item.Created = now.UnixNano()
 	
 	// Add to cache
 	element := c.evictList.PushFront(item)
@@ -134,31 +174,45 @@ func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
 }
 
 // Get retrieves an item from the cache
-func (c *Cache) Get(key string) (interface{}, bool) {
+func (c *Cache[V]) Get(key string) (V, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
 	element, found := c.items[key]
 	if !found {
-		return nil, false
+		c.misses.Add(1)
+		var zero V
+		return zero, false
 	}
 	
-	item := element.Value.(*Item)
+	item := element.Value.(*Item[V])
 	
 	// Check if item has expired
 	if item.Expiration < time.Now().UnixNano() {
 		c.removeElement(element)
-		return nil, false
+		c.expired.Add(1)
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	
+	value, err := item.value()
+	if err != nil {
+		// A corrupt entry is useless; drop it
+		c.removeElement(element)
+		c.misses.Add(1)
+		return value, false
 	}
 	
 	// Move to front (recently used)
 	c.evictList.MoveToFront(element)
 	
-	return item.Value, true
+	c.hits.Add(1)
+	return value, true
 }
 
 // Delete item from cache
-func (c *Cache) Delete(key string) bool {
+func (c *Cache[V]) Delete(key string) bool {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
@@ -172,47 +226,84 @@ func (c *Cache) Delete(key string) bool {
 }
 
 // removeElement is an internal function to remove an element from the cache
-func (c *Cache) removeElement(e *list.Element) {
+func (c *Cache[V]) removeElement(e *list.Element) {
 	c.evictList.Remove(e)
-	item := e.Value.(*Item)
+	item := e.Value.(*Item[V])
 	delete(c.items, item.Key)
-	c.sizeBytes -= estimateSize(item.Value)
+	c.sizeBytes -= item.size
 	if c.onEvict != nil {
-		c.onEvict(item.Key, item.Value)
+		value, _ := item.value()
+		c.onEvict(item.Key, value)
 	}
 }
 
 // evictOldest removes the oldest item from the cache 
-func (c *Cache) evictOldest() {
+func (c *Cache[V]) evictOldest() {
 	element := c.evictList.Back()
 	if element != nil {
 		c.removeElement(element)
+		c.evictions.Add(1)
 	}
 }
 
 // Len returns the number of items in the cache
-func (c *Cache) Len() int {
+func (c *Cache[V]) Len() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.evictList.Len()
 }
 
 // Size returns the estimated memory size of the cache in bytes
-func (c *Cache) Size() int64 {
+func (c *Cache[V]) Size() int64 {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.sizeBytes
 }
 
+// MaxSize returns the configured size budget of the cache in bytes
+func (c *Cache[V]) MaxSize() int64 {
+	return c.maxSizeBytes
+}
+
+// Stats returns hit, miss, eviction and expiry counts along with current usage
+func (c *Cache[V]) Stats() Stats {
+	c.mu.RLock()
+	entries, size := c.evictList.Len(), c.sizeBytes
+	compressed := 0
+	for _, element := range c.items {
+		if element.Value.(*Item[V]).packed != nil {
+			compressed++
+		}
+	}
+	c.mu.RUnlock()
+
+	stats := Stats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		Evictions:    c.evictions.Load(),
+		Expired:      c.expired.Load(),
+		Entries:      entries,
+		MaxEntries:   c.maxItems,
+		Compressed:   compressed,
+		SizeBytes:    size,
+		MaxSizeBytes: c.maxSizeBytes,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
 // Clear removes all items from the cache
-func (c *Cache) Clear() {
+func (c *Cache[V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
 	if c.onEvict != nil {
 		for _, element := range c.items {
-			item := element.Value.(*Item)
-			c.onEvict(item.Key, item.Value)
+			item := element.Value.(*Item[V])
+			value, _ := item.value()
+			c.onEvict(item.Key, value)
 		}
 	}
 	
@@ -222,22 +313,23 @@ func (c *Cache) Clear() {
 }
 
 // deleteExpired deletes expired items from the cache
-func (c *Cache) deleteExpired() {
+func (c *Cache[V]) deleteExpired() {
 	now := time.Now().UnixNano()
 	
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	
 	for _, element := range c.items {
-		item := element.Value.(*Item)
+		item := element.Value.(*Item[V])
 		if item.Expiration < now {
 			c.removeElement(element)
+			c.expired.Add(1)
 		}
 	}
 }
 
 // Close stops the janitor
-func (c *Cache) Close() {
+func (c *Cache[V]) Close() {
 	close(c.janitor.stop)
 }
 
@@ -247,15 +339,15 @@ type janitor struct {
 	stop     chan struct{}
 }
 
-// run runs the janitor process
-func (j *janitor) run(c *Cache) {
+// run calls deleteExpired every interval until the janitor is stopped
+func (j *janitor) run(deleteExpired func()) {
 	ticker := time.NewTicker(j.interval)
 	defer ticker.Stop()
 	
 	for {
 		select {
 		case <-ticker.C:
-			c.deleteExpired()
+			deleteExpired()
 		case <-j.stop:
 			return
 		}
@@ -290,29 +382,46 @@ func estimateSize(v interface{}) int64 {
 
 
 // GetWithTTL retrieves an item from the cache if it exists and is within a custom TTL
-func (c *Cache) GetWithTTL(key string, maxAge time.Duration) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache[V]) GetWithTTL(key string, maxAge time.Duration) (V, bool) {
+	// Write lock: a hit reorders the eviction list
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	
 	element, found := c.items[key]
 	if !found {
-		return nil, false
+		c.misses.Add(1)
+		var zero V
+		return zero, false
 	}
 	
-	item := element.Value.(*Item)
+	item := element.Value.(*Item[V])
 	
 	// Check if item has expired according to its original expiration
-	if item.Expiration < time.Now().UnixNano() {
-		return nil, false
+	now := time.Now().UnixNano()
+	if item.Expiration < now {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
 	}
 	
 	// Check if item is too old based on the custom maxAge
-	if time.Now().UnixNano() - (item.Expiration - int64(c.defaultTTL)) > int64(maxAge) {
-		return nil, false
+	if now-item.Created > int64(maxAge) {
+		c.misses.Add(1)
+		var zero V
+		return zero, false
+	}
+	
+	value, err := item.value()
+	if err != nil {
+		// A corrupt entry is useless; drop it
+		c.removeElement(element)
+		c.misses.Add(1)
+		return value, false
 	}
 	
 	// Move to front (recently used)
 	c.evictList.MoveToFront(element)
 	
-	return item.Value, true
+	c.hits.Add(1)
+	return value, true
 }
\ No newline at end of file
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	c := NewCache[string](Config{MaxItems: 2, DefaultTTL: time.Minute, CleanupInterval: time.Hour})
+	defer c.Close()
+
+	c.Set("a", "1")
+	c.Set("b", "2")
+	c.Get("a")       // hit
+	c.Get("missing") // miss
+	c.Set("c", "3")  // evicts b, the least recently used
+
+	c.SetWithTTL("d", "4", time.Nanosecond) // evicts a
+	time.Sleep(time.Millisecond)
+	c.Get("d") // expired, counts as a miss
+
+	stats := c.Stats()
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("hits/misses = %d/%d, want 1/2", stats.Hits, stats.Misses)
+	}
+	if stats.Evictions != 2 {
+		t.Errorf("evictions = %d, want 2", stats.Evictions)
+	}
+	if stats.Expired != 1 {
+		t.Errorf("expired = %d, want 1", stats.Expired)
+	}
+	if stats.Entries != 1 || stats.MaxEntries != 2 {
+		t.Errorf("entries = %d/%d, want 1/2", stats.Entries, stats.MaxEntries)
+	}
+	if want := 1.0 / 3; stats.HitRate != want {
+		t.Errorf("hit rate = %v, want %v", stats.HitRate, want)
+	}
+}
+
+func TestGetWithTTLUsesStoredTime(t *testing.T) {
+	c := NewCache[string](Config{DefaultTTL: time.Hour, CleanupInterval: time.Hour})
+	defer c.Close()
+
+	// An entry with a shorter TTL than the default must still be fresh
+	c.SetWithTTL("k", "v", 5*time.Minute)
+	if _, found := c.GetWithTTL("k", 5*time.Minute); !found {
+		t.Fatal("fresh entry was treated as too old")
+	}
+	if _, found := c.GetWithTTL("k", 0); found {
+		t.Error("entry older than maxAge was returned")
+	}
+}
+
+func TestCompression(t *testing.T) {
+	c := NewCache[[]string](Config{CompressThreshold: 64, CleanupInterval: time.Hour})
+	defer c.Close()
+
+	large := make([]string, 100)
+	for i := range large {
+		large[i] = "a repetitive value that compresses well"
+	}
+	c.Set("large", large)
+	c.Set("small", []string{"x"})
+
+	if stats := c.Stats(); stats.Compressed != 1 {
+		t.Errorf("compressed entries = %d, want 1", stats.Compressed)
+	}
+	if size := c.Size(); size >= 1000 {
+		t.Errorf("size = %d, want the large value charged compressed", size)
+	}
+
+	got, found := c.Get("large")
+	if !found || len(got) != len(large) || got[0] != large[0] {
+		t.Errorf("Get(large) = %d items, %v; want the original value", len(got), found)
+	}
+	if got, found := c.Get("small"); !found || len(got) != 1 {
+		t.Errorf("Get(small) = %v, %v", got, found)
+	}
+}
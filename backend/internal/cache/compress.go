@@ -0,0 +1,50 @@
+// File: backend/internal/cache/compress.go
+
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// EncodeAll and DecodeAll are safe for concurrent use, so one encoder and
+// decoder serve every cache
+var (
+	encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	decoder, _ = zstd.NewReader(nil)
+)
+
+// pack sizes a value by its JSON encoding and, when that is larger than
+// threshold bytes, returns the compressed encoding to store instead. packed
+// is nil for values small enough to keep as they are.
+func pack(value interface{}, threshold int) (packed []byte, size int64, err error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error encoding cache value: %w", err)
+	}
+	if len(encoded) <= threshold {
+		return nil, int64(len(encoded)), nil
+	}
+
+	packed = encoder.EncodeAll(encoded, make([]byte, 0, len(encoded)/4))
+	return packed, int64(len(packed)), nil
+}
+
+// value returns the item's value, decompressing it if it was packed
+func (item *Item[V]) value() (V, error) {
+	if item.packed == nil {
+		return item.Value, nil
+	}
+
+	var value V
+	encoded, err := decoder.DecodeAll(item.packed, nil)
+	if err != nil {
+		return value, fmt.Errorf("error decompressing cache value: %w", err)
+	}
+	if err := json.Unmarshal(encoded, &value); err != nil {
+		return value, fmt.Errorf("error decoding cache value: %w", err)
+	}
+	return value, nil
+}
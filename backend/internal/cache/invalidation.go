@@ -0,0 +1,98 @@
+// File: backend/internal/cache/invalidation.go
+
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+)
+
+// Invalidation asks every replica to clear some caches
+type Invalidation struct {
+	Caches []string `json:"caches,omitempty"` // Cache names; empty means all
+	Origin string   `json:"origin"`           // Node that sent it
+}
+
+// Bus carries invalidations between replicas
+type Bus interface {
+	Publish(ctx context.Context, msg Invalidation) error
+	// Subscribe calls handle for each message until ctx is done or the
+	// subscription fails
+	Subscribe(ctx context.Context, handle func(Invalidation)) error
+	Close() error
+}
+
+// Invalidator clears caches on this node and, when a bus is configured, on
+// every other replica
+type Invalidator struct {
+	bus   Bus
+	node  string
+	flush func(caches []string) []string
+}
+
+// NewInvalidator creates an invalidator. flush clears the named local caches
+// (all of them when none are named) and returns the names it cleared. bus may
+// be nil for a single replica.
+func NewInvalidator(bus Bus, flush func(caches []string) []string) *Invalidator {
+	return &Invalidator{
+		bus:   bus,
+		node:  newNodeID(),
+		flush: flush,
+	}
+}
+
+// Distributed reports whether invalidations reach other replicas
+func (i *Invalidator) Distributed() bool {
+	return i.bus != nil
+}
+
+// Flush clears the named caches here and publishes the invalidation. The
+// local flush happens even if publishing fails.
+func (i *Invalidator) Flush(ctx context.Context, caches []string) ([]string, error) {
+	flushed := i.flush(caches)
+	if i.bus == nil {
+		return flushed, nil
+	}
+	return flushed, i.bus.Publish(ctx, Invalidation{Caches: caches, Origin: i.node})
+}
+
+// Start applies invalidations published by other replicas until ctx is
+// cancelled, resubscribing after failures. It returns immediately without a
+// bus.
+func (i *Invalidator) Start(ctx context.Context) {
+	if i.bus == nil {
+		return
+	}
+
+	for {
+		err := i.bus.Subscribe(ctx, func(msg Invalidation) {
+			if msg.Origin == i.node {
+				return
+			}
+			flushed := i.flush(msg.Caches)
+			log.Printf("Flushed caches %v on request from replica %s", flushed, msg.Origin)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Cache invalidation subscription failed, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// newNodeID returns a random ID that tells this process's messages apart
+func newNodeID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryBus delivers published invalidations to every subscriber
+type memoryBus struct {
+	mu       sync.Mutex
+	handlers []func(Invalidation)
+}
+
+func (b *memoryBus) Publish(ctx context.Context, msg Invalidation) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, handle := range b.handlers {
+		handle(msg)
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(ctx context.Context, handle func(Invalidation)) error {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, handle)
+	b.mu.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (b *memoryBus) Close() error { return nil }
+
+func TestInvalidatorFlushesReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := &memoryBus{}
+	flushes := make(chan []string, 2)
+	record := func(replica string) func([]string) []string {
+		return func(caches []string) []string {
+			flushes <- append([]string{replica}, caches...)
+			return caches
+		}
+	}
+
+	sender := NewInvalidator(bus, record("sender"))
+	receiver := NewInvalidator(bus, record("receiver"))
+	go sender.Start(ctx)
+	go receiver.Start(ctx)
+
+	// Wait for both subscriptions
+	for deadline := time.Now().Add(time.Second); ; {
+		bus.mu.Lock()
+		subscribed := len(bus.handlers)
+		bus.mu.Unlock()
+		if subscribed == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("replicas did not subscribe")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, err := sender.Flush(ctx, []string{"search"}); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// The sender flushes once, locally; its own broadcast is ignored
+	got := map[string]int{}
+	for i := 0; i < 2; i++ {
+		select {
+		case flush := <-flushes:
+			if len(flush) != 2 || flush[1] != "search" {
+				t.Errorf("flush = %v, want the search cache", flush)
+			}
+			got[flush[0]]++
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for flushes")
+		}
+	}
+	if got["sender"] != 1 || got["receiver"] != 1 {
+		t.Errorf("flushes per replica = %v, want one each", got)
+	}
+}
@@ -0,0 +1,81 @@
+// File: backend/internal/cache/redis.go
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisBus carries invalidations over a Redis pub/sub channel
+type RedisBus struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisBus connects to the Redis server at url (redis://host:port/db)
+func NewRedisBus(ctx context.Context, url, channel string) (*RedisBus, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to Redis: %w", err)
+	}
+
+	return &RedisBus{client: client, channel: channel}, nil
+}
+
+// Publish sends an invalidation to every subscribed replica
+func (b *RedisBus) Publish(ctx context.Context, msg Invalidation) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error encoding invalidation: %w", err)
+	}
+	if err := b.client.Publish(ctx, b.channel, payload).Err(); err != nil {
+		return fmt.Errorf("error publishing invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls handle for each invalidation until ctx is done or the
+// connection is lost
+func (b *RedisBus) Subscribe(ctx context.Context, handle func(Invalidation)) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	defer sub.Close()
+
+	// Wait for confirmation so failures surface here rather than silently
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("error subscribing to %s: %w", b.channel, err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case message, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("subscription to %s closed", b.channel)
+			}
+			var msg Invalidation
+			if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+				log.Printf("Ignoring malformed cache invalidation: %v", err)
+				continue
+			}
+			handle(msg)
+		}
+	}
+}
+
+// Close closes the Redis connection
+func (b *RedisBus) Close() error {
+	return b.client.Close()
+}
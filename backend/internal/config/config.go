@@ -0,0 +1,1160 @@
+// File: backend/internal/config/config.go
+
+// Package config loads optional runtime configuration from a YAML file.
+// Everything in it has a sensible default, so the server runs without one.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the root of the configuration file
+type Config struct {
+	Prompts    PromptConfig     `yaml:"prompts"`
+	Moderation ModerationConfig `yaml:"moderation"`
+	// ContentPolicy is applied to search results and generated answers
+	ContentPolicy ContentPolicyConfig `yaml:"content_policy"`
+	Embeddings    EmbeddingsConfig    `yaml:"embeddings"`
+	Indexer       IndexerConfig       `yaml:"indexer"`
+	Digests       DigestConfig        `yaml:"digests"`
+	CacheWarmup   CacheWarmupConfig   `yaml:"cache_warmup"`
+	// RedditConcurrency bounds concurrent requests to the Reddit API
+	RedditConcurrency RedditConcurrencyConfig `yaml:"reddit_concurrency"`
+	Limits            LimitsConfig            `yaml:"limits"`
+	// LinkFetching reads the pages link posts point to
+	LinkFetching LinkFetchConfig `yaml:"link_fetching"`
+	// Vision attaches images from image posts for models that accept them
+	Vision VisionConfig `yaml:"vision"`
+	// Transcripts retrieves captions for video posts
+	Transcripts TranscriptConfig `yaml:"transcripts"`
+	// GRPC serves the search API over gRPC alongside HTTP
+	GRPC GRPCConfig `yaml:"grpc"`
+	// Telegram answers messages sent to a Telegram bot
+	Telegram TelegramConfig `yaml:"telegram"`
+	// CORS lists the browser origins allowed to call the API
+	CORS CORSConfig `yaml:"cors"`
+	// Requests bounds what clients may send and ask for
+	Requests RequestsConfig `yaml:"requests"`
+	// Quotas caps how much each client may search per day and month
+	Quotas QuotasConfig `yaml:"quotas"`
+	// Accounts lets people register and sign in
+	Accounts AccountsConfig `yaml:"accounts"`
+	// Sharing hands out public links to answers
+	Sharing SharingConfig `yaml:"sharing"`
+	// Snapshots controls how long search responses are kept
+	Snapshots SnapshotsConfig `yaml:"snapshots"`
+	// Budgets caps what each AI provider may cost per day and month
+	Budgets BudgetsConfig `yaml:"budgets"`
+	// AIProbes checks AI providers in the background
+	AIProbes AIProbesConfig `yaml:"ai_probes"`
+	// Regions maps the region hints clients may send to local communities
+	Regions RegionsConfig `yaml:"regions"`
+	// SafeSearch filters searches for education-facing deployments
+	SafeSearch SafeSearchConfig `yaml:"safe_search"`
+}
+
+// PromptConfig tunes how prompts are built
+type PromptConfig struct {
+	// SubredditInstructions are extra instructions keyed by subreddit name,
+	// added to the prompt when results come predominantly from that community
+	SubredditInstructions map[string]string `yaml:"subreddit_instructions"`
+	// SubredditShare is the fraction of results (0-1) that must come from a
+	// subreddit for its instructions to apply
+	SubredditShare float64 `yaml:"subreddit_share"`
+	// MaxContentLength caps each result's content in the prompt, in
+	// characters; 0 keeps each model's own cap
+	MaxContentLength int `yaml:"max_content_length"`
+	// SubredditContentLength overrides MaxContentLength for results from
+	// these subreddits, e.g. more for long-form communities such as
+	// AskHistorians
+	SubredditContentLength map[string]int `yaml:"subreddit_content_length"`
+}
+
+// MaxResultContentLength and MaxResultHighlights bound the per-result content
+// length and highlight count, whether configured or requested
+const (
+	MaxResultContentLength = 10000
+	MaxResultHighlights    = 10
+)
+
+// ModerationConfig controls provider moderation of retrieved content and
+// generated answers
+type ModerationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Provider selects the moderation API; only "openai" is supported
+	Provider string `yaml:"provider"`
+	// Model is the provider's moderation model
+	Model string `yaml:"model"`
+	// Categories limits enforcement to these categories; empty enforces all
+	Categories []string `yaml:"categories"`
+	// Thresholds overrides DefaultThreshold for individual categories
+	Thresholds map[string]float64 `yaml:"thresholds"`
+	// DefaultThreshold is the category score (0-1) at or above which
+	// content is flagged
+	DefaultThreshold float64 `yaml:"default_threshold"`
+	// CheckResults drops flagged search results before they reach the model
+	CheckResults bool `yaml:"check_results"`
+	// CheckAnswers withholds flagged answers
+	CheckAnswers bool `yaml:"check_answers"`
+}
+
+// NSFW handling modes for ContentPolicyConfig.NSFW
+const (
+	NSFWAllow = "allow" // Keep NSFW results unchanged
+	NSFWLabel = "label" // Keep NSFW results, prefixing their titles with [NSFW]
+	NSFWBlock = "block" // Drop NSFW results
+)
+
+// ContentPolicyConfig describes which content is removed or rewritten
+type ContentPolicyConfig struct {
+	// NSFW is one of NSFWAllow, NSFWLabel or NSFWBlock
+	NSFW string `yaml:"nsfw"`
+	// Categories are named term lists. A trailing * matches any suffix,
+	// e.g. "gambl*". Terms match whole words, case-insensitively.
+	Categories map[string][]string `yaml:"categories"`
+	// BlockedCategories drops results matching these categories and masks
+	// their terms in answers
+	BlockedCategories []string `yaml:"blocked_categories"`
+	// MaskProfanity replaces profanity in results and answers with asterisks
+	MaskProfanity bool `yaml:"mask_profanity"`
+	// ProfanityWords extends the built-in profanity list
+	ProfanityWords []string `yaml:"profanity_words"`
+}
+
+// Embedding providers for EmbeddingsConfig.Provider
+const (
+	EmbeddingProviderLocal  = "local"  // Word hashing, no API needed
+	EmbeddingProviderOpenAI = "openai" // OpenAI embeddings API, needs OPENAI_API_KEY
+)
+
+// Vector stores for EmbeddingsConfig.Store
+const (
+	VectorStoreMemory   = "memory"   // In process, lost on restart
+	VectorStoreSQLite   = "sqlite"   // Local file at SQLitePath
+	VectorStorePgvector = "pgvector" // Postgres with pgvector, DSN from EMBEDDINGS_DATABASE_URL
+)
+
+// EmbeddingsConfig selects the embedding provider and vector store shared by
+// semantic features
+type EmbeddingsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Provider is EmbeddingProviderLocal or EmbeddingProviderOpenAI
+	Provider string `yaml:"provider"`
+	// Model is the provider's embedding model; ignored by the local provider
+	Model string `yaml:"model"`
+	// Dimensions is the vector size. Changing it requires re-indexing.
+	Dimensions int `yaml:"dimensions"`
+	// Store is VectorStoreMemory, VectorStoreSQLite or VectorStorePgvector
+	Store string `yaml:"store"`
+	// SQLitePath is the database file used by the sqlite store
+	SQLitePath string `yaml:"sqlite_path"`
+}
+
+// IndexerConfig controls the background subreddit indexer, which keeps
+// recent posts from popular communities locally so searches on those topics
+// can be answered without querying Reddit
+type IndexerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Subreddits to crawl
+	Subreddits []string `yaml:"subreddits"`
+	// Listings fetched from each subreddit, e.g. "new", "hot", "top"
+	Listings []string `yaml:"listings"`
+	// PostsPerListing is how many posts each listing request returns (max 100)
+	PostsPerListing int `yaml:"posts_per_listing"`
+	// Interval between crawls
+	Interval time.Duration `yaml:"interval"`
+	// Retention is how long posts stay in the index after they were created
+	Retention time.Duration `yaml:"retention"`
+	// MinSimilarity is the embedding similarity (0-1) a post needs to count
+	// as relevant to a query
+	MinSimilarity float64 `yaml:"min_similarity"`
+	// MinResults is how many relevant posts the index must hold for a query
+	// to be answered from it; otherwise the search goes to Reddit
+	MinResults int `yaml:"min_results"`
+}
+
+// DigestConfig controls scheduled AI digests of subreddit activity
+type DigestConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval between digest runs for each topic
+	Interval time.Duration `yaml:"interval"`
+	// ModelName is the model used to write digests, e.g. "Claude"
+	ModelName string `yaml:"model_name"`
+	// PostsPerDigest is how many of the highest-scoring posts are summarized
+	PostsPerDigest int           `yaml:"posts_per_digest"`
+	Topics         []DigestTopic `yaml:"topics"`
+}
+
+// CacheWarmupConfig controls pre-populating the search cache on startup with
+// the most frequent recent searches, so the first users after a deploy don't
+// wait on Reddit
+type CacheWarmupConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Queries is how many of the most frequent searches are replayed
+	Queries int `yaml:"queries"`
+	// Lookback is how far back search history is considered
+	Lookback time.Duration `yaml:"lookback"`
+}
+
+// MaxRedditConcurrency caps RedditConcurrencyConfig.Max
+const MaxRedditConcurrency = 100
+
+// RedditConcurrencyConfig sets the bounds of the adaptive limit on concurrent
+// Reddit requests. The limit rises while responses are fast and falls on
+// rate limiting (429) or responses slower than TargetLatency.
+type RedditConcurrencyConfig struct {
+	// Initial is the limit at startup
+	Initial int `yaml:"initial"`
+	Min     int `yaml:"min"`
+	Max     int `yaml:"max"`
+	// TargetLatency is the response time above which the limit is lowered
+	TargetLatency time.Duration `yaml:"target_latency"`
+}
+
+// LimitsConfig protects the server from bursts of large searches
+type LimitsConfig struct {
+	// MaxConcurrentSearches is how many searches run at once. Further
+	// searches wait up to QueueTimeout, then fail with 503.
+	MaxConcurrentSearches int           `yaml:"max_concurrent_searches"`
+	QueueTimeout          time.Duration `yaml:"queue_timeout"`
+	// MaxResultMemoryMB caps the estimated size of the results held by all
+	// running searches; searches beyond it fail with 503
+	MaxResultMemoryMB int `yaml:"max_result_memory_mb"`
+	// MaxResponseKB caps the size of a search response; the lowest-ranked
+	// results are dropped, with a warning, to fit
+	MaxResponseKB int `yaml:"max_response_kb"`
+	// MaxSearchesPerClient is how many search requests one API key or IP
+	// address may have in flight; 0 removes the cap. Further requests wait
+	// up to ClientQueueTimeout, then fail with 429.
+	MaxSearchesPerClient int           `yaml:"max_searches_per_client"`
+	ClientQueueTimeout   time.Duration `yaml:"client_queue_timeout"`
+}
+
+// LinkFetchConfig controls fetching the articles that link posts without
+// text point to, so the model can see what the discussion is about
+type LinkFetchConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxLinks is how many link posts are fetched per search, in rank order
+	MaxLinks int `yaml:"max_links"`
+	// Timeout bounds each page fetch
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxPageKB is how much of each page is read
+	MaxPageKB int `yaml:"max_page_kb"`
+	// SummaryChars caps the extracted summary added to the prompt
+	SummaryChars int `yaml:"summary_chars"`
+}
+
+// VisionConfig controls attaching post images to prompts for models that
+// support image input
+type VisionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxImages is how many images are attached per answer, from the
+	// highest-ranked image posts in the prompt
+	MaxImages int `yaml:"max_images"`
+	// MaxImageKB skips larger images in favour of the post's thumbnail
+	MaxImageKB int `yaml:"max_image_kb"`
+	// Timeout bounds each image download
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// TranscriptConfig controls retrieving captions for Reddit-hosted and
+// YouTube videos in top results
+type TranscriptConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxVideos is how many video posts are looked up per search, in rank order
+	MaxVideos int `yaml:"max_videos"`
+	// Timeout bounds each request made while retrieving a transcript
+	Timeout time.Duration `yaml:"timeout"`
+	// Language is the preferred caption language (ISO 639-1); other
+	// languages are used when it isn't available
+	Language string `yaml:"language"`
+	// ExcerptChars caps the transcript excerpt added to the prompt
+	ExcerptChars int `yaml:"excerpt_chars"`
+}
+
+// GRPCConfig controls the gRPC SearchService, for services that want typed
+// clients or streamed results
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Address is the listen address, e.g. ":9090"
+	Address string `yaml:"address"`
+}
+
+// TelegramConfig controls the Telegram bot webhook, which answers messages
+// sent to the bot as search queries. The bot token comes from
+// TELEGRAM_BOT_TOKEN and the webhook secret from TELEGRAM_WEBHOOK_SECRET.
+type TelegramConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// ModelName is the model used to answer, e.g. "Claude"
+	ModelName string `yaml:"model_name"`
+	// MessagesPerWindow is how many queries each chat may send per Window;
+	// further messages get a "slow down" reply until the window moves on
+	MessagesPerWindow int           `yaml:"messages_per_window"`
+	Window            time.Duration `yaml:"window"`
+	// MaxCitations caps the sources listed under each answer
+	MaxCitations int `yaml:"max_citations"`
+}
+
+// CORSConfig lists the origins browsers may call the API from. Origins may
+// contain * wildcards, e.g. "https://*.example.com" or "http://localhost:*";
+// a lone "*" allows any origin, without credentials.
+type CORSConfig struct {
+	// AllowOrigins applies to routes without an override. The
+	// CORS_ALLOW_ORIGINS environment variable, a comma-separated list,
+	// replaces it.
+	AllowOrigins []string `yaml:"allow_origins"`
+	// Routes override AllowOrigins for paths under a prefix; the longest
+	// matching prefix wins
+	Routes []CORSRoute `yaml:"routes"`
+}
+
+// CORSRoute overrides the allowed origins for part of the API
+type CORSRoute struct {
+	// PathPrefix selects the routes, e.g. "/api/admin"
+	PathPrefix string `yaml:"path_prefix"`
+	// AllowOrigins replaces CORSConfig.AllowOrigins for these routes; empty
+	// blocks cross-origin requests
+	AllowOrigins []string `yaml:"allow_origins"`
+}
+
+// SearchModes are the search modes the server supports
+var SearchModes = []string{"All", "Posts", "Comments", "Communities"}
+
+// RequestsConfig bounds search requests. Requests outside the bounds are
+// rejected with 400, or 413 for oversized bodies.
+type RequestsConfig struct {
+	// MaxQueryLength caps search queries, in characters
+	MaxQueryLength int `yaml:"max_query_length"`
+	// MaxBodyKB caps the size of request bodies
+	MaxBodyKB int `yaml:"max_body_kb"`
+	// SearchModes are the modes clients may request, from SearchModes
+	SearchModes []string `yaml:"search_modes"`
+	// Models are the model names clients may request; empty allows every
+	// configured model
+	Models []string `yaml:"models"`
+	// Highlights is the number of key excerpts kept per result for requests
+	// that don't ask for a number
+	Highlights int `yaml:"highlights"`
+}
+
+// QuotasConfig caps the search requests and AI tokens each client, keyed by
+// account, issued API key or IP address, may use per UTC day and month.
+// Usage is kept in the database, so it survives restarts. A limit of 0 means
+// no limit.
+type QuotasConfig struct {
+	Enabled         bool  `yaml:"enabled"`
+	DailyRequests   int64 `yaml:"daily_requests"`
+	MonthlyRequests int64 `yaml:"monthly_requests"`
+	// Tokens are estimated from the length of prompts and answers
+	DailyTokens   int64 `yaml:"daily_tokens"`
+	MonthlyTokens int64 `yaml:"monthly_tokens"`
+}
+
+// BudgetsConfig caps the estimated cost of each AI provider's calls per UTC
+// day and month. Spend is kept in the database, so it survives restarts and
+// is shared by replicas. Once a budget is spent, the provider's models are
+// answered by its fallback model, or with mock responses, until the period
+// ends, and an alert is logged and sent to WebhookURL.
+type BudgetsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// WebhookURL receives a JSON POST when a budget is exceeded; empty only
+	// logs the alert
+	WebhookURL string `yaml:"webhook_url"`
+	// Providers holds the budget of each provider, keyed by provider name as
+	// in the model list, e.g. "Anthropic" or "OpenAI". Providers without
+	// one are not limited.
+	Providers map[string]ProviderBudget `yaml:"providers"`
+}
+
+// ProviderBudget is one AI provider's budget and prices. Costs are
+// estimated from the length of prompts and answers, so budgets should
+// leave some headroom.
+type ProviderBudget struct {
+	// DailyUSD and MonthlyUSD are the budgets; 0 means no limit
+	DailyUSD   float64 `yaml:"daily_usd"`
+	MonthlyUSD float64 `yaml:"monthly_usd"`
+	// InputPerMillion and OutputPerMillion are the prices in USD per
+	// million prompt and response tokens
+	InputPerMillion  float64 `yaml:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million"`
+	// FallbackModel answers instead once the budget is spent, e.g. a
+	// cheaper model from another provider; empty returns mock responses
+	FallbackModel string `yaml:"fallback_model"`
+}
+
+// AIProbesConfig controls background probes of the configured AI models.
+// Each probe is a tiny request to every model whose provider has an API
+// key. Models failing repeatedly are reported down and left out of
+// automatic model selection until a call to them succeeds again.
+type AIProbesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often models are probed
+	Interval time.Duration `yaml:"interval"`
+	// Timeout bounds each probe
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RegionsConfig maps region hints to country and city communities. Searches
+// with a region also look in its communities and rank their results higher,
+// so questions such as "best ISP right now" get local answers.
+type RegionsConfig struct {
+	// Subreddits are the communities of each region, keyed by the hint
+	// clients send, e.g. "uk" or "nyc". Configured regions replace the
+	// default ones of the same name.
+	Subreddits map[string][]string `yaml:"subreddits"`
+}
+
+// SafeSearchConfig controls safe search, which drops NSFW results, moderates
+// results and answers whenever moderation is configured, and leaves out
+// unsuitable communities. Requests opt in with safeSearch.
+type SafeSearchConfig struct {
+	// Enabled applies safe search to every request
+	Enabled bool `yaml:"enabled"`
+	// ExcludedSubreddits are left out of safe searches, on top of the
+	// communities Reddit marks NSFW
+	ExcludedSubreddits []string `yaml:"excluded_subreddits"`
+}
+
+// AccountsConfig enables user accounts. Signed-in users' history, saved
+// searches and quotas follow the account instead of the API key or IP
+// address. Sessions are signed with the JWT_SECRET environment variable.
+type AccountsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// SessionTTL is how long a login lasts before signing in again
+	SessionTTL        time.Duration `yaml:"session_ttl"`
+	MinPasswordLength int           `yaml:"min_password_length"`
+	// Reddit lets people sign in with their Reddit accounts
+	Reddit RedditLoginConfig `yaml:"reddit"`
+}
+
+// RedditLoginConfig enables signing in with Reddit, so searches can cover a
+// user's own subscriptions, saved posts and multireddits. It uses the Reddit
+// app from REDDIT_API_CLIENT_ID and REDDIT_API_CLIENT_SECRET, which must be
+// a "web app" with RedirectURL as its redirect URI. Reddit tokens are encrypted
+// with the REDDIT_TOKEN_KEY environment variable.
+type RedditLoginConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// RedirectURL is this server's /api/auth/reddit/callback as Reddit
+	// reaches it, e.g. "https://api.example.com/api/auth/reddit/callback"
+	RedirectURL string `yaml:"redirect_url"`
+	// AfterLoginURL is the frontend page the callback redirects to, with the
+	// session in the URL fragment. Empty responds with the session as JSON.
+	AfterLoginURL string `yaml:"after_login_url"`
+}
+
+// SharingConfig enables public, expiring links to answers at
+// /api/share/:token. Links are signed with the SHARE_SECRET environment
+// variable; changing it invalidates every link.
+type SharingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PublicURL is this server's base URL as link recipients reach it, e.g.
+	// "https://api.example.com". Empty uses the host the link was
+	// requested from.
+	PublicURL string `yaml:"public_url"`
+	// DefaultTTL is how long links last when the request doesn't say
+	DefaultTTL time.Duration `yaml:"default_ttl"`
+	// MaxTTL caps how long links may last
+	MaxTTL time.Duration `yaml:"max_ttl"`
+}
+
+// SnapshotsConfig controls how long search response snapshots are kept.
+// Exports, shared links, feedback, notes and history entries refer to a
+// snapshot and stop working once it's pruned. Snapshots of searches in a
+// research collection are kept.
+type SnapshotsConfig struct {
+	// Retention is how long snapshots are kept; 0 keeps them forever
+	Retention time.Duration `yaml:"retention"`
+	// PruneInterval is how often snapshots past the retention are deleted
+	PruneInterval time.Duration `yaml:"prune_interval"`
+}
+
+// DigestTopic is a digest served at /api/digests/:name
+type DigestTopic struct {
+	// Name is the URL slug: lowercase letters, digits and dashes
+	Name  string `yaml:"name"`
+	Title string `yaml:"title"`
+	// Subreddits whose listings are summarized
+	Subreddits []string `yaml:"subreddits"`
+	// Listing is the subreddit listing to read: "top" (past day, default),
+	// "hot" or "new"
+	Listing string `yaml:"listing"`
+	// Prompt is the question put to the model; a generic "what happened
+	// today" question is used when empty
+	Prompt string `yaml:"prompt"`
+}
+
+// digestNamePattern restricts digest topic names to URL-safe slugs
+var digestNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// Default returns the configuration used when no file is present
+func Default() *Config {
+	return &Config{
+		Prompts: PromptConfig{
+			SubredditInstructions:  map[string]string{},
+			SubredditShare:         0.5,
+			SubredditContentLength: map[string]int{},
+		},
+		Moderation: ModerationConfig{
+			Enabled:          true,
+			Provider:         "openai",
+			Model:            "omni-moderation-latest",
+			Thresholds:       map[string]float64{},
+			DefaultThreshold: 0.5,
+			CheckResults:     true,
+			CheckAnswers:     true,
+		},
+		ContentPolicy: ContentPolicyConfig{
+			NSFW: NSFWAllow,
+			Categories: map[string][]string{
+				"adult": {"porn*", "nsfw", "explicit", "graphically violent"},
+			},
+		},
+		Embeddings: EmbeddingsConfig{
+			Enabled:    true,
+			Provider:   EmbeddingProviderLocal,
+			Model:      "text-embedding-3-small",
+			Dimensions: 256,
+			Store:      VectorStoreMemory,
+			SQLitePath: "embeddings.db",
+		},
+		Indexer: IndexerConfig{
+			Listings:        []string{"new", "top"},
+			PostsPerListing: 50,
+			Interval:        30 * time.Minute,
+			Retention:       7 * 24 * time.Hour,
+			MinSimilarity:   0.35,
+			MinResults:      5,
+		},
+		Digests: DigestConfig{
+			Interval:       24 * time.Hour,
+			ModelName:      "Claude",
+			PostsPerDigest: 25,
+		},
+		CacheWarmup: CacheWarmupConfig{
+			Enabled:  true,
+			Queries:  20,
+			Lookback: 24 * time.Hour,
+		},
+		RedditConcurrency: RedditConcurrencyConfig{
+			Initial:       5,
+			Min:           1,
+			Max:           20,
+			TargetLatency: 3 * time.Second,
+		},
+		Limits: LimitsConfig{
+			MaxConcurrentSearches: 32,
+			QueueTimeout:          5 * time.Second,
+			MaxResultMemoryMB:     256,
+			MaxResponseKB:         2048,
+			MaxSearchesPerClient:  2,
+			ClientQueueTimeout:    2 * time.Second,
+		},
+		LinkFetching: LinkFetchConfig{
+			MaxLinks:     5,
+			Timeout:      5 * time.Second,
+			MaxPageKB:    1024,
+			SummaryChars: 1500,
+		},
+		Vision: VisionConfig{
+			MaxImages:  4,
+			MaxImageKB: 2048,
+			Timeout:    5 * time.Second,
+		},
+		Transcripts: TranscriptConfig{
+			MaxVideos:    3,
+			Timeout:      8 * time.Second,
+			Language:     "en",
+			ExcerptChars: 2000,
+		},
+		GRPC: GRPCConfig{
+			Address: ":9090",
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"http://localhost:3000", "https://subplexity.vercel.app"},
+			Routes: []CORSRoute{
+				// Answer widgets are meant to be embedded anywhere
+				{PathPrefix: "/api/embed", AllowOrigins: []string{"*"}},
+			},
+		},
+		Telegram: TelegramConfig{
+			ModelName:         "Claude",
+			MessagesPerWindow: 5,
+			Window:            time.Minute,
+			MaxCitations:      10,
+		},
+		Requests: RequestsConfig{
+			MaxQueryLength: 500,
+			MaxBodyKB:      64,
+			SearchModes:    append([]string(nil), SearchModes...),
+			Highlights:     3,
+		},
+		Quotas: QuotasConfig{
+			DailyRequests:   200,
+			MonthlyRequests: 3000,
+			DailyTokens:     2000000,
+			MonthlyTokens:   30000000,
+		},
+		Accounts: AccountsConfig{
+			SessionTTL:        30 * 24 * time.Hour,
+			MinPasswordLength: 8,
+		},
+		Sharing: SharingConfig{
+			DefaultTTL: 7 * 24 * time.Hour,
+			MaxTTL:     30 * 24 * time.Hour,
+		},
+		Snapshots: SnapshotsConfig{
+			PruneInterval: time.Hour,
+		},
+		AIProbes: AIProbesConfig{
+			Interval: 5 * time.Minute,
+			Timeout:  15 * time.Second,
+		},
+		SafeSearch: SafeSearchConfig{
+			ExcludedSubreddits: []string{"watchpeopledie", "morbidreality", "gore", "drugs", "darknet", "fightporn"},
+		},
+		Regions: RegionsConfig{
+			Subreddits: map[string][]string{
+				"us":        {"askanamerican", "usa"},
+				"uk":        {"unitedkingdom", "askuk", "casualuk"},
+				"ireland":   {"ireland", "askireland"},
+				"canada":    {"canada", "askacanadian", "personalfinancecanada"},
+				"australia": {"australia", "askaus", "ausfinance"},
+				"india":     {"india", "askindia", "indiaspeaks"},
+				"germany":   {"germany", "de", "fragreddit"},
+				"nyc":       {"nyc", "asknyc"},
+				"la":        {"losangeles", "askla"},
+				"sf":        {"sanfrancisco", "bayarea"},
+				"chicago":   {"chicago", "askchicago"},
+				"london":    {"london", "askuk"},
+				"toronto":   {"toronto", "askto"},
+				"sydney":    {"sydney"},
+				"bangalore": {"bangalore", "bengaluru"},
+			},
+		},
+	}
+}
+
+// Load reads the configuration file at path on top of the defaults. A
+// missing file is not an error; the defaults are returned instead.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	// Decode maps with default keys into empty maps; strict decoding rejects
+	// keys that are already set, so defaults are merged back afterwards
+	defaultCategories := cfg.ContentPolicy.Categories
+	cfg.ContentPolicy.Categories = nil
+	defaultRegions := cfg.Regions.Subreddits
+	cfg.Regions.Subreddits = nil
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	if cfg.ContentPolicy.Categories == nil {
+		cfg.ContentPolicy.Categories = make(map[string][]string, len(defaultCategories))
+	}
+	for name, terms := range defaultCategories {
+		if _, ok := cfg.ContentPolicy.Categories[name]; !ok {
+			cfg.ContentPolicy.Categories[name] = terms
+		}
+	}
+	regions := make(map[string][]string, len(defaultRegions)+len(cfg.Regions.Subreddits))
+	for region, subreddits := range defaultRegions {
+		regions[region] = subreddits
+	}
+	// Regions are matched case-insensitively, so "UK" replaces "uk"
+	for region, subreddits := range cfg.Regions.Subreddits {
+		regions[strings.ToLower(strings.TrimSpace(region))] = subreddits
+	}
+	cfg.Regions.Subreddits = regions
+
+	if err := cfg.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// normalize validates values and canonicalizes keys
+func (c *Config) normalize() error {
+	if c.Prompts.SubredditShare <= 0 || c.Prompts.SubredditShare > 1 {
+		return fmt.Errorf("prompts.subreddit_share must be between 0 and 1, got %v", c.Prompts.SubredditShare)
+	}
+	if c.Prompts.MaxContentLength < 0 || c.Prompts.MaxContentLength > MaxResultContentLength {
+		return fmt.Errorf("prompts.max_content_length must be between 0 and %d, got %d", MaxResultContentLength, c.Prompts.MaxContentLength)
+	}
+
+	if c.Moderation.Provider != "openai" {
+		return fmt.Errorf("moderation.provider '%s' is not supported", c.Moderation.Provider)
+	}
+	if c.Moderation.DefaultThreshold <= 0 || c.Moderation.DefaultThreshold > 1 {
+		return fmt.Errorf("moderation.default_threshold must be between 0 and 1, got %v", c.Moderation.DefaultThreshold)
+	}
+	for category, threshold := range c.Moderation.Thresholds {
+		if threshold <= 0 || threshold > 1 {
+			return fmt.Errorf("moderation.thresholds.%s must be between 0 and 1, got %v", category, threshold)
+		}
+	}
+
+	switch c.ContentPolicy.NSFW {
+	case NSFWAllow, NSFWLabel, NSFWBlock:
+	default:
+		return fmt.Errorf("content_policy.nsfw must be allow, label or block, got '%s'", c.ContentPolicy.NSFW)
+	}
+	for _, category := range c.ContentPolicy.BlockedCategories {
+		if _, ok := c.ContentPolicy.Categories[category]; !ok {
+			return fmt.Errorf("content_policy.blocked_categories: unknown category '%s'", category)
+		}
+	}
+
+	switch c.Embeddings.Provider {
+	case EmbeddingProviderLocal, EmbeddingProviderOpenAI:
+	default:
+		return fmt.Errorf("embeddings.provider must be local or openai, got '%s'", c.Embeddings.Provider)
+	}
+	switch c.Embeddings.Store {
+	case VectorStoreMemory, VectorStoreSQLite, VectorStorePgvector:
+	default:
+		return fmt.Errorf("embeddings.store must be memory, sqlite or pgvector, got '%s'", c.Embeddings.Store)
+	}
+	if c.Embeddings.Dimensions <= 0 || c.Embeddings.Dimensions > 4096 {
+		return fmt.Errorf("embeddings.dimensions must be between 1 and 4096, got %d", c.Embeddings.Dimensions)
+	}
+
+	if c.Indexer.Enabled {
+		if !c.Embeddings.Enabled {
+			return fmt.Errorf("indexer requires embeddings to be enabled")
+		}
+		if len(c.Indexer.Subreddits) == 0 {
+			return fmt.Errorf("indexer.subreddits must list at least one subreddit")
+		}
+	}
+	for _, listing := range c.Indexer.Listings {
+		switch listing {
+		case "new", "hot", "top", "rising":
+		default:
+			return fmt.Errorf("indexer.listings: unsupported listing '%s'", listing)
+		}
+	}
+	if c.Indexer.PostsPerListing <= 0 || c.Indexer.PostsPerListing > 100 {
+		return fmt.Errorf("indexer.posts_per_listing must be between 1 and 100, got %d", c.Indexer.PostsPerListing)
+	}
+	if c.Indexer.Interval < time.Minute {
+		return fmt.Errorf("indexer.interval must be at least 1m, got %s", c.Indexer.Interval)
+	}
+	if c.Indexer.MinSimilarity <= 0 || c.Indexer.MinSimilarity > 1 {
+		return fmt.Errorf("indexer.min_similarity must be between 0 and 1, got %v", c.Indexer.MinSimilarity)
+	}
+	if c.Indexer.MinResults <= 0 {
+		return fmt.Errorf("indexer.min_results must be positive, got %d", c.Indexer.MinResults)
+	}
+	subreddits := make([]string, 0, len(c.Indexer.Subreddits))
+	for _, name := range c.Indexer.Subreddits {
+		if name = NormalizeSubreddit(name); name != "" {
+			subreddits = append(subreddits, name)
+		}
+	}
+	c.Indexer.Subreddits = subreddits
+
+	if err := c.Digests.normalize(); err != nil {
+		return err
+	}
+
+	if err := c.CORS.normalize(); err != nil {
+		return err
+	}
+
+	if err := c.Requests.normalize(); err != nil {
+		return err
+	}
+
+	quotas := c.Quotas
+	if quotas.DailyRequests < 0 || quotas.MonthlyRequests < 0 || quotas.DailyTokens < 0 || quotas.MonthlyTokens < 0 {
+		return fmt.Errorf("quotas must not be negative, got %+v", quotas)
+	}
+
+	if c.Accounts.SessionTTL < time.Minute {
+		return fmt.Errorf("accounts.session_ttl must be at least 1m, got %s", c.Accounts.SessionTTL)
+	}
+	if c.Accounts.MinPasswordLength < 6 || c.Accounts.MinPasswordLength > 72 {
+		return fmt.Errorf("accounts.min_password_length must be between 6 and 72, got %d", c.Accounts.MinPasswordLength)
+	}
+	if err := c.Accounts.Reddit.normalize(); err != nil {
+		return err
+	}
+	if err := c.Sharing.normalize(); err != nil {
+		return err
+	}
+	if c.Snapshots.Retention != 0 && c.Snapshots.Retention < time.Hour {
+		return fmt.Errorf("snapshots.retention must be 0 (keep forever) or at least 1h, got %s", c.Snapshots.Retention)
+	}
+	if c.Snapshots.PruneInterval < time.Minute {
+		return fmt.Errorf("snapshots.prune_interval must be at least 1m, got %s", c.Snapshots.PruneInterval)
+	}
+
+	if err := c.Budgets.normalize(); err != nil {
+		return err
+	}
+	if c.AIProbes.Interval < 30*time.Second {
+		return fmt.Errorf("ai_probes.interval must be at least 30s, got %s", c.AIProbes.Interval)
+	}
+	if c.AIProbes.Timeout <= 0 || c.AIProbes.Timeout > c.AIProbes.Interval {
+		return fmt.Errorf("ai_probes.timeout must be positive and at most the interval, got %s", c.AIProbes.Timeout)
+	}
+
+	if c.CacheWarmup.Queries < 0 || c.CacheWarmup.Queries > 200 {
+		return fmt.Errorf("cache_warmup.queries must be between 0 and 200, got %d", c.CacheWarmup.Queries)
+	}
+	if c.CacheWarmup.Lookback <= 0 {
+		return fmt.Errorf("cache_warmup.lookback must be positive, got %s", c.CacheWarmup.Lookback)
+	}
+
+	concurrency := c.RedditConcurrency
+	if concurrency.Min < 1 || concurrency.Max < concurrency.Min || concurrency.Max > MaxRedditConcurrency {
+		return fmt.Errorf("reddit_concurrency: need 1 <= min <= max <= %d, got min %d, max %d", MaxRedditConcurrency, concurrency.Min, concurrency.Max)
+	}
+	if concurrency.Initial < concurrency.Min || concurrency.Initial > concurrency.Max {
+		return fmt.Errorf("reddit_concurrency.initial must be between min and max, got %d", concurrency.Initial)
+	}
+	if concurrency.TargetLatency <= 0 {
+		return fmt.Errorf("reddit_concurrency.target_latency must be positive, got %s", concurrency.TargetLatency)
+	}
+
+	if c.Limits.MaxConcurrentSearches < 1 {
+		return fmt.Errorf("limits.max_concurrent_searches must be at least 1, got %d", c.Limits.MaxConcurrentSearches)
+	}
+	if c.Limits.QueueTimeout < 0 {
+		return fmt.Errorf("limits.queue_timeout must not be negative, got %s", c.Limits.QueueTimeout)
+	}
+	if c.Limits.MaxResultMemoryMB < 1 {
+		return fmt.Errorf("limits.max_result_memory_mb must be at least 1, got %d", c.Limits.MaxResultMemoryMB)
+	}
+	if c.Limits.MaxResponseKB < 64 {
+		return fmt.Errorf("limits.max_response_kb must be at least 64, got %d", c.Limits.MaxResponseKB)
+	}
+	if c.Limits.MaxSearchesPerClient < 0 {
+		return fmt.Errorf("limits.max_searches_per_client must not be negative, got %d", c.Limits.MaxSearchesPerClient)
+	}
+	if c.Limits.ClientQueueTimeout < 0 {
+		return fmt.Errorf("limits.client_queue_timeout must not be negative, got %s", c.Limits.ClientQueueTimeout)
+	}
+
+	if c.LinkFetching.MaxLinks < 0 || c.LinkFetching.MaxLinks > 25 {
+		return fmt.Errorf("link_fetching.max_links must be between 0 and 25, got %d", c.LinkFetching.MaxLinks)
+	}
+	if c.LinkFetching.Timeout <= 0 {
+		return fmt.Errorf("link_fetching.timeout must be positive, got %s", c.LinkFetching.Timeout)
+	}
+	if c.LinkFetching.MaxPageKB < 16 || c.LinkFetching.MaxPageKB > 10240 {
+		return fmt.Errorf("link_fetching.max_page_kb must be between 16 and 10240, got %d", c.LinkFetching.MaxPageKB)
+	}
+	if c.LinkFetching.SummaryChars < 100 {
+		return fmt.Errorf("link_fetching.summary_chars must be at least 100, got %d", c.LinkFetching.SummaryChars)
+	}
+
+	if c.Vision.MaxImages < 1 || c.Vision.MaxImages > 10 {
+		return fmt.Errorf("vision.max_images must be between 1 and 10, got %d", c.Vision.MaxImages)
+	}
+	// Providers reject images over 5MB once base64-encoded
+	if c.Vision.MaxImageKB < 16 || c.Vision.MaxImageKB > 3072 {
+		return fmt.Errorf("vision.max_image_kb must be between 16 and 3072, got %d", c.Vision.MaxImageKB)
+	}
+	if c.Vision.Timeout <= 0 {
+		return fmt.Errorf("vision.timeout must be positive, got %s", c.Vision.Timeout)
+	}
+
+	if c.Transcripts.MaxVideos < 0 || c.Transcripts.MaxVideos > 10 {
+		return fmt.Errorf("transcripts.max_videos must be between 0 and 10, got %d", c.Transcripts.MaxVideos)
+	}
+	if c.Transcripts.Timeout <= 0 {
+		return fmt.Errorf("transcripts.timeout must be positive, got %s", c.Transcripts.Timeout)
+	}
+	c.Transcripts.Language = strings.ToLower(strings.TrimSpace(c.Transcripts.Language))
+	if c.Transcripts.Language == "" {
+		c.Transcripts.Language = "en"
+	}
+	if c.Transcripts.ExcerptChars < 100 {
+		return fmt.Errorf("transcripts.excerpt_chars must be at least 100, got %d", c.Transcripts.ExcerptChars)
+	}
+
+	c.GRPC.Address = strings.TrimSpace(c.GRPC.Address)
+	if c.GRPC.Enabled && c.GRPC.Address == "" {
+		return fmt.Errorf("grpc.address is required when grpc is enabled")
+	}
+
+	c.Telegram.ModelName = strings.TrimSpace(c.Telegram.ModelName)
+	if c.Telegram.ModelName == "" {
+		return fmt.Errorf("telegram.model_name is required")
+	}
+	if c.Telegram.MessagesPerWindow < 1 {
+		return fmt.Errorf("telegram.messages_per_window must be at least 1, got %d", c.Telegram.MessagesPerWindow)
+	}
+	if c.Telegram.Window <= 0 {
+		return fmt.Errorf("telegram.window must be positive, got %v", c.Telegram.Window)
+	}
+	if c.Telegram.MaxCitations < 0 {
+		return fmt.Errorf("telegram.max_citations must not be negative, got %d", c.Telegram.MaxCitations)
+	}
+
+	// Subreddit names are case-insensitive and may be written as "r/name"
+	instructions := make(map[string]string, len(c.Prompts.SubredditInstructions))
+	for name, text := range c.Prompts.SubredditInstructions {
+		instructions[NormalizeSubreddit(name)] = strings.TrimSpace(text)
+	}
+	c.Prompts.SubredditInstructions = instructions
+
+	regions := make(map[string][]string, len(c.Regions.Subreddits))
+	for region, subreddits := range c.Regions.Subreddits {
+		var names []string
+		for _, name := range subreddits {
+			if name = NormalizeSubreddit(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("regions.subreddits.%s must list at least one subreddit", region)
+		}
+		regions[strings.ToLower(strings.TrimSpace(region))] = names
+	}
+	c.Regions.Subreddits = regions
+
+	var safeExcluded []string
+	for _, name := range c.SafeSearch.ExcludedSubreddits {
+		if name = NormalizeSubreddit(name); name != "" {
+			safeExcluded = append(safeExcluded, name)
+		}
+	}
+	c.SafeSearch.ExcludedSubreddits = safeExcluded
+
+	contentLengths := make(map[string]int, len(c.Prompts.SubredditContentLength))
+	for name, length := range c.Prompts.SubredditContentLength {
+		if length < 1 || length > MaxResultContentLength {
+			return fmt.Errorf("prompts.subreddit_content_length.%s must be between 1 and %d, got %d", name, MaxResultContentLength, length)
+		}
+		contentLengths[NormalizeSubreddit(name)] = length
+	}
+	c.Prompts.SubredditContentLength = contentLengths
+
+	return nil
+}
+
+// normalize validates digest settings and fills in per-topic defaults
+func (d *DigestConfig) normalize() error {
+	if d.Interval < time.Minute {
+		return fmt.Errorf("digests.interval must be at least 1m, got %s", d.Interval)
+	}
+	if d.PostsPerDigest <= 0 || d.PostsPerDigest > 100 {
+		return fmt.Errorf("digests.posts_per_digest must be between 1 and 100, got %d", d.PostsPerDigest)
+	}
+
+	seen := make(map[string]bool, len(d.Topics))
+	for i := range d.Topics {
+		topic := &d.Topics[i]
+		if !digestNamePattern.MatchString(topic.Name) {
+			return fmt.Errorf("digests.topics: invalid name '%s' (use lowercase letters, digits and dashes)", topic.Name)
+		}
+		if seen[topic.Name] {
+			return fmt.Errorf("digests.topics: duplicate name '%s'", topic.Name)
+		}
+		seen[topic.Name] = true
+
+		var subreddits []string
+		for _, name := range topic.Subreddits {
+			if name = NormalizeSubreddit(name); name != "" {
+				subreddits = append(subreddits, name)
+			}
+		}
+		if len(subreddits) == 0 {
+			return fmt.Errorf("digests.topics.%s: at least one subreddit is required", topic.Name)
+		}
+		topic.Subreddits = subreddits
+
+		switch topic.Listing {
+		case "":
+			topic.Listing = "top"
+		case "top", "hot", "new":
+		default:
+			return fmt.Errorf("digests.topics.%s: unsupported listing '%s'", topic.Name, topic.Listing)
+		}
+
+		if topic.Title == "" {
+			topic.Title = "r/" + strings.Join(topic.Subreddits, ", r/") + " digest"
+		}
+	}
+
+	return nil
+}
+
+// normalize validates request bounds and canonicalizes search mode names
+func (r *RequestsConfig) normalize() error {
+	if r.MaxQueryLength < 1 || r.MaxQueryLength > 10000 {
+		return fmt.Errorf("requests.max_query_length must be between 1 and 10000, got %d", r.MaxQueryLength)
+	}
+	if r.MaxBodyKB < 1 || r.MaxBodyKB > 10240 {
+		return fmt.Errorf("requests.max_body_kb must be between 1 and 10240, got %d", r.MaxBodyKB)
+	}
+	if r.Highlights < 0 || r.Highlights > MaxResultHighlights {
+		return fmt.Errorf("requests.highlights must be between 0 and %d, got %d", MaxResultHighlights, r.Highlights)
+	}
+
+	modes := make([]string, 0, len(r.SearchModes))
+	for _, mode := range r.SearchModes {
+		known := ""
+		for _, supported := range SearchModes {
+			if strings.EqualFold(strings.TrimSpace(mode), supported) {
+				known = supported
+			}
+		}
+		if known == "" {
+			return fmt.Errorf("requests.search_modes: unknown mode '%s' (expected %s)", mode, strings.Join(SearchModes, ", "))
+		}
+		modes = append(modes, known)
+	}
+	if len(modes) == 0 {
+		return fmt.Errorf("requests.search_modes must list at least one mode")
+	}
+	r.SearchModes = modes
+
+	var models []string
+	for _, name := range r.Models {
+		if name = strings.TrimSpace(name); name != "" {
+			models = append(models, name)
+		}
+	}
+	r.Models = models
+	return nil
+}
+
+// OverrideOrigins replaces AllowOrigins with a comma-separated list, as
+// given in CORS_ALLOW_ORIGINS
+func (c *CORSConfig) OverrideOrigins(list string) error {
+	origins, err := normalizeOrigins(strings.Split(list, ","))
+	if err != nil {
+		return fmt.Errorf("CORS_ALLOW_ORIGINS: %w", err)
+	}
+	c.AllowOrigins = origins
+	return nil
+}
+
+// normalize checks the URLs are absolute when Reddit login is enabled
+func (r *RedditLoginConfig) normalize() error {
+	r.RedirectURL = strings.TrimSpace(r.RedirectURL)
+	r.AfterLoginURL = strings.TrimSpace(r.AfterLoginURL)
+	if !r.Enabled {
+		return nil
+	}
+	if !absoluteURL(r.RedirectURL) {
+		return fmt.Errorf("accounts.reddit.redirect_url must be an absolute http(s) URL, got '%s'", r.RedirectURL)
+	}
+	if r.AfterLoginURL != "" && !absoluteURL(r.AfterLoginURL) {
+		return fmt.Errorf("accounts.reddit.after_login_url must be an absolute http(s) URL, got '%s'", r.AfterLoginURL)
+	}
+	return nil
+}
+
+// normalize checks the link lifetimes and the public URL
+func (s *SharingConfig) normalize() error {
+	if s.DefaultTTL < time.Minute {
+		return fmt.Errorf("sharing.default_ttl must be at least 1m, got %s", s.DefaultTTL)
+	}
+	if s.MaxTTL < s.DefaultTTL {
+		return fmt.Errorf("sharing.max_ttl must be at least default_ttl (%s), got %s", s.DefaultTTL, s.MaxTTL)
+	}
+	s.PublicURL = strings.TrimRight(strings.TrimSpace(s.PublicURL), "/")
+	if s.PublicURL != "" && !absoluteURL(s.PublicURL) {
+		return fmt.Errorf("sharing.public_url must be an absolute http(s) URL, got '%s'", s.PublicURL)
+	}
+	return nil
+}
+
+// normalize validates the budgets
+func (b *BudgetsConfig) normalize() error {
+	b.WebhookURL = strings.TrimSpace(b.WebhookURL)
+	if b.WebhookURL != "" && !absoluteURL(b.WebhookURL) {
+		return fmt.Errorf("budgets.webhook_url must be an absolute http(s) URL, got '%s'", b.WebhookURL)
+	}
+	for provider, budget := range b.Providers {
+		if budget.DailyUSD < 0 || budget.MonthlyUSD < 0 || budget.InputPerMillion < 0 || budget.OutputPerMillion < 0 {
+			return fmt.Errorf("budgets.providers.%s: budgets and prices must not be negative, got %+v", provider, budget)
+		}
+	}
+	return nil
+}
+
+// absoluteURL reports whether raw is an http or https URL with a host
+func absoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// normalize validates origins and route prefixes
+func (c *CORSConfig) normalize() error {
+	origins, err := normalizeOrigins(c.AllowOrigins)
+	if err != nil {
+		return fmt.Errorf("cors.allow_origins: %w", err)
+	}
+	c.AllowOrigins = origins
+
+	seen := make(map[string]bool, len(c.Routes))
+	for i := range c.Routes {
+		route := &c.Routes[i]
+		route.PathPrefix = strings.TrimSpace(route.PathPrefix)
+		if !strings.HasPrefix(route.PathPrefix, "/") {
+			return fmt.Errorf("cors.routes: path_prefix '%s' must start with /", route.PathPrefix)
+		}
+		if seen[route.PathPrefix] {
+			return fmt.Errorf("cors.routes: duplicate path_prefix '%s'", route.PathPrefix)
+		}
+		seen[route.PathPrefix] = true
+
+		if route.AllowOrigins, err = normalizeOrigins(route.AllowOrigins); err != nil {
+			return fmt.Errorf("cors.routes.%s: %w", route.PathPrefix, err)
+		}
+	}
+	return nil
+}
+
+// normalizeOrigins lowercases origins and drops trailing slashes and blank
+// entries. Browsers send origins as scheme://host[:port], so anything else
+// could never match.
+func normalizeOrigins(origins []string) ([]string, error) {
+	normalized := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+		if origin == "" {
+			continue
+		}
+		if origin != "*" {
+			scheme, host, ok := strings.Cut(origin, "://")
+			if !ok || (scheme != "http" && scheme != "https") {
+				return nil, fmt.Errorf("origin '%s' must start with http:// or https://", origin)
+			}
+			if host == "" || strings.Contains(host, "/") {
+				return nil, fmt.Errorf("origin '%s' must be scheme://host[:port] without a path", origin)
+			}
+		}
+		normalized = append(normalized, origin)
+	}
+	return normalized, nil
+}
+
+// NormalizeSubreddit lowercases a subreddit name and strips any "r/" prefix
+func NormalizeSubreddit(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.TrimPrefix(name, "r/")
+}
@@ -0,0 +1,267 @@
+// File: backend/internal/config/config_test.go
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Prompts.SubredditShare != Default().Prompts.SubredditShare {
+		t.Errorf("Expected default subreddit share, got %v", cfg.Prompts.SubredditShare)
+	}
+}
+
+func TestLoadNormalizesSubredditKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+prompts:
+  subreddit_instructions:
+    r/WallStreetBets: "  Treat as satire.  "
+  subreddit_content_length:
+    r/AskHistorians: 3000
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := cfg.Prompts.SubredditInstructions["wallstreetbets"]; got != "Treat as satire." {
+		t.Errorf("Expected normalized instruction, got %q", got)
+	}
+	if got := cfg.Prompts.SubredditContentLength["askhistorians"]; got != 3000 {
+		t.Errorf("Expected normalized content length, got %d", got)
+	}
+
+	// Omitted settings keep their defaults
+	if cfg.Prompts.SubredditShare != 0.5 {
+		t.Errorf("Expected default subreddit share, got %v", cfg.Prompts.SubredditShare)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("promts: {}\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for a misspelled key")
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "config.example.yaml"))
+	if err != nil {
+		t.Fatalf("config.example.yaml failed to load: %v", err)
+	}
+
+	// Redefining a default category replaces it; other defaults are kept
+	if _, ok := cfg.ContentPolicy.Categories["adult"]; !ok {
+		t.Error("Expected the adult category to be defined")
+	}
+	if _, ok := cfg.ContentPolicy.Categories["gambling"]; !ok {
+		t.Error("Expected the gambling category from the example")
+	}
+}
+
+func TestCORSOrigins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+cors:
+  allow_origins: ["HTTPS://App.Example.com/", "http://localhost:*", ""]
+  routes:
+    - path_prefix: /api/admin
+      allow_origins: []
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"https://app.example.com", "http://localhost:*"}
+	if len(cfg.CORS.AllowOrigins) != len(want) || cfg.CORS.AllowOrigins[0] != want[0] || cfg.CORS.AllowOrigins[1] != want[1] {
+		t.Errorf("Expected normalized origins %v, got %v", want, cfg.CORS.AllowOrigins)
+	}
+
+	if err := cfg.CORS.OverrideOrigins("https://a.example.com, *"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.CORS.AllowOrigins) != 2 || cfg.CORS.AllowOrigins[1] != "*" {
+		t.Errorf("Expected origins from the override, got %v", cfg.CORS.AllowOrigins)
+	}
+
+	for _, invalid := range []string{"example.com", "ftp://example.com", "https://example.com/app"} {
+		if err := cfg.CORS.OverrideOrigins(invalid); err == nil {
+			t.Errorf("Expected an error for origin %q", invalid)
+		}
+	}
+}
+
+func TestRequestsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+requests:
+  search_modes: [posts, " Comments "]
+  models: [Claude, ""]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.Requests.SearchModes) != 2 || cfg.Requests.SearchModes[0] != "Posts" || cfg.Requests.SearchModes[1] != "Comments" {
+		t.Errorf("Expected canonical search modes, got %v", cfg.Requests.SearchModes)
+	}
+	if len(cfg.Requests.Models) != 1 || cfg.Requests.Models[0] != "Claude" {
+		t.Errorf("Expected blank model names dropped, got %v", cfg.Requests.Models)
+	}
+
+	for _, invalid := range []RequestsConfig{
+		{MaxQueryLength: 0, MaxBodyKB: 64, SearchModes: SearchModes},
+		{MaxQueryLength: 500, MaxBodyKB: 0, SearchModes: SearchModes},
+		{MaxQueryLength: 500, MaxBodyKB: 64, SearchModes: []string{"Images"}},
+		{MaxQueryLength: 500, MaxBodyKB: 64},
+		{MaxQueryLength: 500, MaxBodyKB: 64, SearchModes: SearchModes, Highlights: MaxResultHighlights + 1},
+	} {
+		if err := invalid.normalize(); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestSharingConfig(t *testing.T) {
+	sharing := SharingConfig{PublicURL: " https://api.example.com/ ", DefaultTTL: time.Hour, MaxTTL: time.Hour}
+	if err := sharing.normalize(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sharing.PublicURL != "https://api.example.com" {
+		t.Errorf("Expected the trailing slash trimmed, got %q", sharing.PublicURL)
+	}
+
+	for _, invalid := range []SharingConfig{
+		{DefaultTTL: time.Second, MaxTTL: time.Hour},
+		{DefaultTTL: time.Hour, MaxTTL: time.Minute},
+		{PublicURL: "api.example.com", DefaultTTL: time.Hour, MaxTTL: time.Hour},
+	} {
+		if err := invalid.normalize(); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestBudgetsConfig(t *testing.T) {
+	budgets := BudgetsConfig{
+		WebhookURL: " https://hooks.example.com/alerts ",
+		Providers:  map[string]ProviderBudget{"Anthropic": {DailyUSD: 5, InputPerMillion: 3}},
+	}
+	if err := budgets.normalize(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if budgets.WebhookURL != "https://hooks.example.com/alerts" {
+		t.Errorf("Expected the webhook URL trimmed, got %q", budgets.WebhookURL)
+	}
+
+	for _, invalid := range []BudgetsConfig{
+		{WebhookURL: "hooks.example.com"},
+		{Providers: map[string]ProviderBudget{"OpenAI": {MonthlyUSD: -1}}},
+		{Providers: map[string]ProviderBudget{"OpenAI": {OutputPerMillion: -10}}},
+	} {
+		if err := invalid.normalize(); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestAIProbesConfig(t *testing.T) {
+	for _, probes := range []AIProbesConfig{
+		{Interval: time.Second, Timeout: time.Second},
+		{Interval: time.Minute, Timeout: 0},
+		{Interval: time.Minute, Timeout: 2 * time.Minute},
+	} {
+		cfg := Default()
+		cfg.AIProbes = probes
+		if err := cfg.normalize(); err == nil {
+			t.Errorf("Expected an error for %+v", probes)
+		}
+	}
+}
+
+func TestRegionsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+regions:
+  subreddits:
+    UK: [r/UnitedKingdom]
+    berlin: [berlin, r/germany]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	regions := cfg.Regions.Subreddits
+	if got := regions["uk"]; len(got) != 1 || got[0] != "unitedkingdom" {
+		t.Errorf("Expected the configured uk communities to replace the defaults, got %v", got)
+	}
+	if got := regions["berlin"]; len(got) != 2 || got[1] != "germany" {
+		t.Errorf("Expected normalized berlin communities, got %v", got)
+	}
+	if len(regions["nyc"]) == 0 {
+		t.Errorf("Expected the default regions to be kept, got %v", regions)
+	}
+
+	cfg = Default()
+	cfg.Regions.Subreddits = map[string][]string{"mars": {" "}}
+	if err := cfg.normalize(); err == nil {
+		t.Error("Expected an error for a region without communities")
+	}
+}
+
+func TestSafeSearchConfig(t *testing.T) {
+	if cfg := Default(); cfg.SafeSearch.Enabled || len(cfg.SafeSearch.ExcludedSubreddits) == 0 {
+		t.Errorf("Expected safe search off with default exclusions, got %+v", cfg.SafeSearch)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+safe_search:
+  enabled: true
+  excluded_subreddits: [r/WatchPeopleDie, " ", gore]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cfg.SafeSearch.Enabled {
+		t.Error("Expected safe search to be enabled")
+	}
+	if got := cfg.SafeSearch.ExcludedSubreddits; len(got) != 2 || got[0] != "watchpeopledie" || got[1] != "gore" {
+		t.Errorf("Expected normalized exclusions, got %v", got)
+	}
+}
@@ -0,0 +1,184 @@
+// File: backend/internal/contentpolicy/policy.go
+
+// Package contentpolicy applies the configured content policy (NSFW
+// handling, blocked term categories and profanity masking) to search results
+// and generated answers.
+package contentpolicy
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// defaultProfanity is the built-in profanity list; a trailing * matches any suffix
+var defaultProfanity = []string{
+	"fuck*", "motherfuck*", "shit*", "bullshit", "bitch*", "asshole*",
+	"bastard*", "cunt*", "dickhead*", "wank*", "twat*",
+}
+
+// defaultProfanityPattern masks the built-in list for Clean without a policy
+var defaultProfanityPattern = compileTerms(defaultProfanity)
+
+// Policy is a compiled content policy
+type Policy struct {
+	nsfw      string
+	blocked   map[string]*regexp.Regexp
+	profanity *regexp.Regexp
+	// clean masks the built-in and configured profanity for Clean, whether
+	// or not the policy masks it everywhere
+	clean *regexp.Regexp
+}
+
+// New compiles a content policy from configuration
+func New(cfg config.ContentPolicyConfig) *Policy {
+	policy := &Policy{
+		nsfw:    cfg.NSFW,
+		blocked: make(map[string]*regexp.Regexp),
+	}
+
+	for _, category := range cfg.BlockedCategories {
+		if pattern := compileTerms(cfg.Categories[category]); pattern != nil {
+			policy.blocked[category] = pattern
+		}
+	}
+
+	policy.clean = compileTerms(append(append([]string{}, defaultProfanity...), cfg.ProfanityWords...))
+	if cfg.MaskProfanity {
+		policy.profanity = policy.clean
+	}
+
+	return policy
+}
+
+// Rewrites reports whether the policy removes or rewrites any content
+func (p *Policy) Rewrites() bool {
+	if p == nil {
+		return false
+	}
+	return (p.nsfw != "" && p.nsfw != config.NSFWAllow) || len(p.blocked) > 0 || p.profanity != nil
+}
+
+// FilterResults removes or rewrites results according to the policy
+func (p *Policy) FilterResults(results []models.SearchResult) []models.SearchResult {
+	if p == nil {
+		return results
+	}
+
+	var filtered []models.SearchResult
+	for _, result := range results {
+		if result.NSFW {
+			switch p.nsfw {
+			case config.NSFWBlock:
+				continue
+			case config.NSFWLabel:
+				if !strings.HasPrefix(result.Title, "[NSFW]") {
+					result.Title = "[NSFW] " + result.Title
+				}
+			}
+		}
+
+		if categories := p.matchBlocked(result.Title + "\n" + result.Content); len(categories) > 0 {
+			log.Printf("Content policy removed result %s from r/%s (%s)",
+				result.ID, result.Subreddit, strings.Join(categories, ", "))
+			continue
+		}
+
+		result.Title = p.maskProfanity(result.Title)
+		result.Content = p.maskProfanity(result.Content)
+		if len(result.Highlights) > 0 {
+			highlights := make([]string, len(result.Highlights))
+			for i, highlight := range result.Highlights {
+				highlights[i] = p.maskProfanity(highlight)
+			}
+			result.Highlights = highlights
+		}
+
+		filtered = append(filtered, result)
+	}
+
+	return filtered
+}
+
+// ProcessText masks blocked terms and profanity in generated text. It returns
+// the rewritten text and the blocked categories that were found.
+func (p *Policy) ProcessText(text string) (string, []string) {
+	if p == nil {
+		return text, nil
+	}
+
+	categories := p.matchBlocked(text)
+	for _, category := range categories {
+		text = p.blocked[category].ReplaceAllStringFunc(text, mask)
+	}
+
+	return p.maskProfanity(text), categories
+}
+
+// Clean masks profanity in text for requests that ask for clean language,
+// whether or not the policy masks it. A nil policy masks the built-in list.
+func (p *Policy) Clean(text string) string {
+	pattern := defaultProfanityPattern
+	if p != nil {
+		pattern = p.clean
+	}
+	return pattern.ReplaceAllStringFunc(text, mask)
+}
+
+// matchBlocked returns the blocked categories found in text, sorted
+func (p *Policy) matchBlocked(text string) []string {
+	var matched []string
+	for category, pattern := range p.blocked {
+		if pattern.MatchString(text) {
+			matched = append(matched, category)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// maskProfanity replaces profane words with asterisks
+func (p *Policy) maskProfanity(text string) string {
+	if p.profanity == nil {
+		return text
+	}
+	return p.profanity.ReplaceAllStringFunc(text, mask)
+}
+
+// mask keeps the first letter of a word and replaces the rest with asterisks
+func mask(word string) string {
+	runes := []rune(word)
+	if len(runes) <= 1 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+}
+
+// compileTerms builds a case-insensitive whole-word pattern for terms. A
+// trailing * matches any word suffix.
+func compileTerms(terms []string) *regexp.Regexp {
+	var alternatives []string
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if strings.HasSuffix(term, "*") {
+			alternatives = append(alternatives, regexp.QuoteMeta(strings.TrimSuffix(term, "*"))+`\w*`)
+		} else {
+			alternatives = append(alternatives, regexp.QuoteMeta(term))
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return nil
+	}
+
+	return regexp.MustCompile(fmt.Sprintf(`(?i)\b(?:%s)\b`, strings.Join(alternatives, "|")))
+}
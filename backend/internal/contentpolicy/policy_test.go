@@ -0,0 +1,95 @@
+// File: backend/internal/contentpolicy/policy_test.go
+
+package contentpolicy
+
+import (
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestFilterResults(t *testing.T) {
+	policy := New(config.ContentPolicyConfig{
+		NSFW:              config.NSFWLabel,
+		Categories:        map[string][]string{"gambling": {"casino*"}},
+		BlockedCategories: []string{"gambling"},
+		MaskProfanity:     true,
+	})
+
+	results := []models.SearchResult{
+		{ID: "1", Title: "Best casinos in Vegas"},
+		{ID: "2", Title: "Spicy memes", NSFW: true},
+		{ID: "3", Title: "This is shitty advice", Content: "What the fuck"},
+	}
+
+	filtered := policy.FilterResults(results)
+	if len(filtered) != 2 {
+		t.Fatalf("Expected the gambling result to be dropped, got %+v", filtered)
+	}
+	if filtered[0].Title != "[NSFW] Spicy memes" {
+		t.Errorf("Expected NSFW label, got %q", filtered[0].Title)
+	}
+	if filtered[1].Title != "This is s***** advice" || filtered[1].Content != "What the f***" {
+		t.Errorf("Expected profanity to be masked, got %q / %q", filtered[1].Title, filtered[1].Content)
+	}
+
+	// Blocking NSFW drops the result entirely
+	blocking := New(config.ContentPolicyConfig{NSFW: config.NSFWBlock})
+	if got := blocking.FilterResults(results[1:2]); len(got) != 0 {
+		t.Errorf("Expected NSFW result to be blocked, got %+v", got)
+	}
+}
+
+func TestProcessText(t *testing.T) {
+	policy := New(config.ContentPolicyConfig{
+		Categories:        map[string][]string{"gambling": {"casino*", "sports betting"}},
+		BlockedCategories: []string{"gambling"},
+	})
+
+	text, categories := policy.ProcessText("Try Casinos or sports betting, not casual games.")
+	if text != "Try C****** or s*************, not casual games." {
+		t.Errorf("Unexpected masked text: %q", text)
+	}
+	if len(categories) != 1 || categories[0] != "gambling" {
+		t.Errorf("Expected gambling category, got %v", categories)
+	}
+
+	// A nil policy leaves text untouched
+	var none *Policy
+	if text, _ := none.ProcessText("casino"); text != "casino" {
+		t.Errorf("Expected nil policy to be a no-op, got %q", text)
+	}
+}
+
+func TestClean(t *testing.T) {
+	// Clean masks profanity even when the policy doesn't mask it everywhere
+	policy := New(config.ContentPolicyConfig{ProfanityWords: []string{"frak*"}})
+	if text, _ := policy.ProcessText("Holy shit"); text != "Holy shit" {
+		t.Errorf("Expected the policy to leave profanity alone, got %q", text)
+	}
+	if text := policy.Clean("Holy shit, frakking great"); text != "Holy s***, f******* great" {
+		t.Errorf("Unexpected cleaned text: %q", text)
+	}
+
+	var none *Policy
+	if text := none.Clean("What the fuck"); text != "What the f***" {
+		t.Errorf("Expected the built-in list without a policy, got %q", text)
+	}
+}
+
+func TestRewrites(t *testing.T) {
+	var none *Policy
+	if none.Rewrites() || New(config.ContentPolicyConfig{NSFW: config.NSFWAllow}).Rewrites() {
+		t.Error("Expected a policy that changes nothing not to rewrite")
+	}
+	for _, cfg := range []config.ContentPolicyConfig{
+		{NSFW: config.NSFWLabel},
+		{NSFW: config.NSFWAllow, MaskProfanity: true},
+		{NSFW: config.NSFWAllow, Categories: map[string][]string{"gambling": {"casino"}}, BlockedCategories: []string{"gambling"}},
+	} {
+		if !New(cfg).Rewrites() {
+			t.Errorf("Expected %+v to rewrite", cfg)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// File: backend/internal/digest/scheduler.go
+
+// Package digest generates periodic AI summaries of subreddit activity for
+// configured topics and keeps the latest one per topic.
+package digest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+const (
+	// checkInterval is how often the scheduler looks for due topics
+	checkInterval = time.Minute
+	// retryDelay is how long a topic waits after a failed run
+	retryDelay = 15 * time.Minute
+)
+
+// ErrUnknownTopic is returned for topics that are not configured
+var ErrUnknownTopic = errors.New("unknown digest topic")
+
+// Fetcher retrieves subreddit listings. *services.RedditService implements it.
+type Fetcher interface {
+	FetchListing(ctx context.Context, subreddit, listing string, limit int) ([]models.SearchResult, error)
+}
+
+// Answerer writes an answer from supplied results. *services.SearchPipeline
+// implements it.
+type Answerer interface {
+	RunWithResults(ctx context.Context, req models.SearchRequest, results []models.SearchResult, source string) (*models.SearchResponse, error)
+}
+
+// Scheduler generates digests on a fixed interval and caches the latest
+// digest for each topic in memory and in the store
+type Scheduler struct {
+	fetcher  Fetcher
+	answerer Answerer
+	store    *store.Store
+	cfg      config.DigestConfig
+	topics   map[string]config.DigestTopic
+
+	mu          sync.RWMutex
+	latest      map[string]*models.Digest
+	lastAttempt map[string]time.Time
+}
+
+// New creates a digest scheduler. Call Start to begin generating digests.
+func New(fetcher Fetcher, answerer Answerer, dataStore *store.Store, cfg config.DigestConfig) *Scheduler {
+	topics := make(map[string]config.DigestTopic, len(cfg.Topics))
+	for _, topic := range cfg.Topics {
+		topics[topic.Name] = topic
+	}
+
+	return &Scheduler{
+		fetcher:     fetcher,
+		answerer:    answerer,
+		store:       dataStore,
+		cfg:         cfg,
+		topics:      topics,
+		latest:      make(map[string]*models.Digest),
+		lastAttempt: make(map[string]time.Time),
+	}
+}
+
+// Start generates due digests immediately and then keeps checking until ctx
+// is cancelled. Digests stored before a restart are reused while fresh. It
+// blocks, so run it in a goroutine.
+func (s *Scheduler) Start(ctx context.Context) {
+	log.Printf("Digests: %d topics every %s", len(s.topics), s.cfg.Interval)
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		s.generateDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Get returns the latest digest for a topic. It returns ErrUnknownTopic for
+// unconfigured topics and store.ErrNotFound when none has been generated yet.
+func (s *Scheduler) Get(ctx context.Context, name string) (*models.Digest, error) {
+	if _, ok := s.topics[name]; !ok {
+		return nil, ErrUnknownTopic
+	}
+
+	s.mu.RLock()
+	digest := s.latest[name]
+	s.mu.RUnlock()
+	if digest != nil {
+		return digest, nil
+	}
+
+	digest, err := s.store.GetDigest(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.latest[name] = digest
+	s.mu.Unlock()
+	return digest, nil
+}
+
+// generateDue generates every topic whose digest is missing or older than
+// the interval, skipping topics that failed recently
+func (s *Scheduler) generateDue(ctx context.Context) {
+	now := time.Now()
+
+	for name, topic := range s.topics {
+		if ctx.Err() != nil {
+			return
+		}
+
+		digest, err := s.Get(ctx, name)
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Digests: error loading %s: %v", name, err)
+		}
+		if digest != nil && now.Sub(time.Unix(digest.GeneratedAt, 0)) < s.cfg.Interval {
+			continue
+		}
+
+		s.mu.Lock()
+		lastAttempt := s.lastAttempt[name]
+		s.lastAttempt[name] = now
+		s.mu.Unlock()
+		if now.Sub(lastAttempt) < retryDelay {
+			continue
+		}
+
+		if _, err := s.Generate(ctx, topic); err != nil {
+			log.Printf("Digests: error generating %s: %v", name, err)
+		}
+	}
+}
+
+// Generate builds a digest for topic from its subreddits' listings and
+// stores it as the topic's latest
+func (s *Scheduler) Generate(ctx context.Context, topic config.DigestTopic) (*models.Digest, error) {
+	startTime := time.Now()
+
+	posts := s.collectPosts(ctx, topic)
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("no posts found in r/%s", strings.Join(topic.Subreddits, ", r/"))
+	}
+
+	req := models.SearchRequest{
+		Query:        topicPrompt(topic),
+		SearchMode:   "Posts",
+		ModelName:    s.cfg.ModelName,
+		Limit:        len(posts),
+		Subreddits:   topic.Subreddits,
+		AnswerFormat: models.AnswerFormatBullets,
+	}
+	response, err := s.answerer.RunWithResults(ctx, req, posts, models.SourceReddit)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &models.Digest{
+		Topic:       topic.Name,
+		Title:       topic.Title,
+		Subreddits:  topic.Subreddits,
+		Answer:      response.Answer,
+		Citations:   response.Citations,
+		Results:     response.Results,
+		Warnings:    response.Warnings,
+		ModelName:   s.cfg.ModelName,
+		GeneratedAt: startTime.Unix(),
+		NextUpdate:  startTime.Add(s.cfg.Interval).Unix(),
+	}
+
+	if err := s.store.SaveDigest(ctx, digest); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.latest[topic.Name] = digest
+	s.mu.Unlock()
+
+	log.Printf("Digests: generated %s from %d posts in %.1fs", topic.Name, len(posts), time.Since(startTime).Seconds())
+	return digest, nil
+}
+
+// collectPosts fetches the topic's listings and keeps the highest-scoring
+// posts. Subreddits that fail to load are skipped.
+func (s *Scheduler) collectPosts(ctx context.Context, topic config.DigestTopic) []models.SearchResult {
+	var posts []models.SearchResult
+	for _, subreddit := range topic.Subreddits {
+		results, err := s.fetcher.FetchListing(ctx, subreddit, topic.Listing, s.cfg.PostsPerDigest)
+		if err != nil {
+			log.Printf("Digests: error fetching r/%s/%s: %v", subreddit, topic.Listing, err)
+			continue
+		}
+		for _, result := range results {
+			if result.Type == "post" {
+				posts = append(posts, result)
+			}
+		}
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].Score > posts[j].Score
+	})
+	if len(posts) > s.cfg.PostsPerDigest {
+		posts = posts[:s.cfg.PostsPerDigest]
+	}
+	return posts
+}
+
+// topicPrompt returns the question the model answers for a topic
+func topicPrompt(topic config.DigestTopic) string {
+	if topic.Prompt != "" {
+		return topic.Prompt
+	}
+
+	period := "over the past day"
+	if topic.Listing == "new" {
+		period = "most recently"
+	}
+	return fmt.Sprintf("What were the most discussed topics and notable posts in r/%s %s?",
+		strings.Join(topic.Subreddits, ", r/"), period)
+}
@@ -0,0 +1,85 @@
+// File: backend/internal/digest/scheduler_test.go
+
+package digest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// staticFetcher serves canned listings keyed by subreddit
+type staticFetcher map[string][]models.SearchResult
+
+func (f staticFetcher) FetchListing(ctx context.Context, subreddit, listing string, limit int) ([]models.SearchResult, error) {
+	return f[subreddit], nil
+}
+
+// recordingAnswerer echoes the results back and remembers the request
+type recordingAnswerer struct {
+	req models.SearchRequest
+}
+
+func (a *recordingAnswerer) RunWithResults(ctx context.Context, req models.SearchRequest, results []models.SearchResult, source string) (*models.SearchResponse, error) {
+	a.req = req
+	return &models.SearchResponse{Answer: "- Summary", Results: results}, nil
+}
+
+func TestGenerateAndGet(t *testing.T) {
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer dataStore.Close()
+
+	fetcher := staticFetcher{
+		"buildapc": {
+			{ID: "a", Type: "post", Score: 10},
+			{ID: "b", Type: "post", Score: 300},
+		},
+		"hardware": {
+			{ID: "c", Type: "post", Score: 50},
+			{ID: "d", Type: "comment", Score: 999},
+		},
+	}
+	topic := config.DigestTopic{Name: "pc", Title: "PC digest", Subreddits: []string{"buildapc", "hardware"}, Listing: "top"}
+	cfg := config.DigestConfig{Interval: time.Hour, ModelName: "Claude", PostsPerDigest: 2, Topics: []config.DigestTopic{topic}}
+
+	answerer := &recordingAnswerer{}
+	scheduler := New(fetcher, answerer, dataStore, cfg)
+	ctx := context.Background()
+
+	if _, err := scheduler.Get(ctx, "pc"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound before the first run, got %v", err)
+	}
+	if _, err := scheduler.Get(ctx, "missing"); !errors.Is(err, ErrUnknownTopic) {
+		t.Errorf("Expected ErrUnknownTopic, got %v", err)
+	}
+
+	if _, err := scheduler.Generate(ctx, topic); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	// Comments are skipped and only the top-scoring posts are kept
+	if answerer.req.Limit != 2 || answerer.req.AnswerFormat != models.AnswerFormatBullets {
+		t.Errorf("Unexpected request %+v", answerer.req)
+	}
+
+	// A fresh scheduler reads the stored digest
+	digest, err := New(fetcher, answerer, dataStore, cfg).Get(ctx, "pc")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if len(digest.Results) != 2 || digest.Results[0].ID != "b" || digest.Results[1].ID != "c" {
+		t.Errorf("Expected posts b and c by score, got %+v", digest.Results)
+	}
+	if digest.NextUpdate-digest.GeneratedAt != 3600 {
+		t.Errorf("Expected next update one interval later, got %d", digest.NextUpdate-digest.GeneratedAt)
+	}
+}
@@ -0,0 +1,218 @@
+// File: backend/internal/embeddings/embeddings.go
+
+// Package embeddings turns text into vectors and stores them for similarity
+// search. Providers (OpenAI, local hashing) and vector stores (in-memory,
+// SQLite, pgvector) are interchangeable behind small interfaces so features
+// such as semantic caching, reranking and related searches share one setup.
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// maxBatchSize caps how many texts are sent to the provider in one request
+const maxBatchSize = 64
+
+// ErrDimensionMismatch is returned when a vector does not match the store's
+// configured dimensions
+var ErrDimensionMismatch = errors.New("vector dimensions do not match")
+
+// Provider embeds texts into fixed-size vectors
+type Provider interface {
+	// Embed returns one vector per input text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Dimensions is the length of the vectors Embed returns
+	Dimensions() int
+	// Name identifies the vector space, e.g. "openai/text-embedding-3-small".
+	// Vectors from providers with different names are not comparable.
+	Name() string
+}
+
+// Record is a stored vector with its identifier and metadata
+type Record struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]string
+}
+
+// Match is a search hit with its cosine similarity to the query (-1 to 1)
+type Match struct {
+	Record
+	Score float64
+}
+
+// VectorStore persists records and finds the nearest ones to a vector.
+// Namespaces keep unrelated collections (cache keys, indexed posts...) apart.
+type VectorStore interface {
+	// Upsert inserts records, replacing any with the same namespace and ID
+	Upsert(ctx context.Context, namespace string, records []Record) error
+	// Search returns up to limit records ordered by descending similarity
+	Search(ctx context.Context, namespace string, vector []float32, limit int) ([]Match, error)
+	// Delete removes records by ID; unknown IDs are ignored
+	Delete(ctx context.Context, namespace string, ids []string) error
+	Close() error
+}
+
+// Document is text to be embedded and stored under an ID
+type Document struct {
+	ID       string
+	Text     string
+	Metadata map[string]string
+}
+
+// Service embeds text with a provider and stores it in a vector store
+type Service struct {
+	provider Provider
+	store    VectorStore
+}
+
+// NewService creates an embedding service
+func NewService(provider Provider, store VectorStore) *Service {
+	return &Service{provider: provider, store: store}
+}
+
+// Enabled reports whether the service can be used. Callers hold a nil
+// *Service when embeddings are turned off.
+func (s *Service) Enabled() bool {
+	return s != nil
+}
+
+// Provider returns the embedding provider
+func (s *Service) Provider() Provider {
+	return s.provider
+}
+
+// Embed embeds texts in batches and returns unit-length vectors
+func (s *Service) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+
+	for start := 0; start < len(texts); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		batch, err := s.provider.Embed(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("%s returned %d embeddings for %d texts", s.provider.Name(), len(batch), end-start)
+		}
+
+		for _, vector := range batch {
+			vectors = append(vectors, Normalize(vector))
+		}
+	}
+
+	return vectors, nil
+}
+
+// Index embeds documents and upserts them into namespace
+func (s *Service) Index(ctx context.Context, namespace string, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.Text
+	}
+
+	vectors, err := s.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("error embedding documents: %w", err)
+	}
+
+	records := make([]Record, len(docs))
+	for i, doc := range docs {
+		records[i] = Record{ID: doc.ID, Vector: vectors[i], Metadata: doc.Metadata}
+	}
+
+	return s.store.Upsert(ctx, namespace, records)
+}
+
+// Search embeds text and returns up to limit matches in namespace scoring at
+// least minScore
+func (s *Service) Search(ctx context.Context, namespace, text string, limit int, minScore float64) ([]Match, error) {
+	vectors, err := s.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, fmt.Errorf("error embedding query: %w", err)
+	}
+
+	matches, err := s.store.Search(ctx, namespace, vectors[0], limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := matches[:0]
+	for _, match := range matches {
+		if match.Score >= minScore {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered, nil
+}
+
+// Delete removes documents from namespace
+func (s *Service) Delete(ctx context.Context, namespace string, ids []string) error {
+	return s.store.Delete(ctx, namespace, ids)
+}
+
+// Close closes the vector store
+func (s *Service) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.store.Close()
+}
+
+// Normalize scales v to unit length in place and returns it. Zero vectors
+// are returned unchanged.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when their lengths
+// differ or either is a zero vector
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// topMatches sorts matches by descending score and truncates them to limit
+func topMatches(matches []Match, limit int) []Match {
+	sortMatches(matches)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
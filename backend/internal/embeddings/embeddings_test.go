@@ -0,0 +1,123 @@
+// File: backend/internal/embeddings/embeddings_test.go
+
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalProviderSimilarity(t *testing.T) {
+	service := NewService(NewLocalProvider(256), NewMemoryStore(256))
+
+	vectors, err := service.Embed(context.Background(), []string{
+		"best mechanical keyboard for programming",
+		"Best mechanical keyboards for programming?",
+		"how to grow tomatoes on a balcony",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	similar := Cosine(vectors[0], vectors[1])
+	unrelated := Cosine(vectors[0], vectors[2])
+	if similar <= unrelated {
+		t.Errorf("Expected near-duplicate queries to score higher (%.2f) than unrelated ones (%.2f)", similar, unrelated)
+	}
+}
+
+func TestVectorStores(t *testing.T) {
+	sqliteStore, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "vectors.db"), 256)
+	if err != nil {
+		t.Fatalf("Failed to open SQLite store: %v", err)
+	}
+
+	stores := map[string]VectorStore{
+		"memory": NewMemoryStore(256),
+		"sqlite": sqliteStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			defer store.Close()
+
+			ctx := context.Background()
+			service := NewService(NewLocalProvider(256), store)
+
+			err := service.Index(ctx, "posts", []Document{
+				{ID: "a", Text: "Keychron keyboards are great for coding", Metadata: map[string]string{"subreddit": "MechanicalKeyboards"}},
+				{ID: "b", Text: "Tomato plants need lots of sun"},
+			})
+			if err != nil {
+				t.Fatalf("Index failed: %v", err)
+			}
+
+			matches, err := service.Search(ctx, "posts", "keyboards for coding", 5, 0.2)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(matches) != 1 || matches[0].ID != "a" {
+				t.Fatalf("Expected only document a to match, got %+v", matches)
+			}
+			if matches[0].Metadata["subreddit"] != "MechanicalKeyboards" {
+				t.Errorf("Expected metadata to round-trip, got %v", matches[0].Metadata)
+			}
+
+			// Other namespaces are isolated
+			if matches, _ := service.Search(ctx, "cache", "keyboards for coding", 5, 0); len(matches) != 0 {
+				t.Errorf("Expected no matches in an empty namespace, got %d", len(matches))
+			}
+
+			if err := service.Delete(ctx, "posts", []string{"a"}); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			matches, _ = service.Search(ctx, "posts", "keyboards for coding", 5, 0.2)
+			if len(matches) != 0 {
+				t.Errorf("Expected deleted document to be gone, got %+v", matches)
+			}
+
+			if err := store.Upsert(ctx, "posts", []Record{{ID: "c", Vector: []float32{1, 2}}}); err != ErrDimensionMismatch {
+				t.Errorf("Expected ErrDimensionMismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Unexpected request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+
+		var request struct {
+			Input      []string `json:"input"`
+			Dimensions int      `json:"dimensions"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Dimensions != 2 {
+			t.Errorf("Expected dimensions 2, got %d", request.Dimensions)
+		}
+
+		// Return the vectors out of order; the provider must use the index
+		w.Write([]byte(`{"data": [
+			{"index": 1, "embedding": [0, 1]},
+			{"index": 0, "embedding": [1, 0]}
+		]}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", "text-embedding-3-small", 2)
+	provider.baseURL = server.URL
+
+	vectors, err := provider.Embed(context.Background(), []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("Expected vectors in input order, got %v", vectors)
+	}
+}
@@ -0,0 +1,77 @@
+// File: backend/internal/embeddings/local.go
+
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// bigramWeight is the contribution of a word pair relative to a single word
+const bigramWeight = 0.5
+
+// LocalProvider embeds text without any external API by hashing words and
+// word pairs into a fixed number of buckets. It captures lexical overlap
+// rather than meaning, so "cheap laptop" and "budget notebook" are not close,
+// but it is free, deterministic and good enough for near-duplicate queries.
+type LocalProvider struct {
+	dimensions int
+}
+
+// NewLocalProvider creates a hashing provider producing vectors of the given size
+func NewLocalProvider(dimensions int) *LocalProvider {
+	return &LocalProvider{dimensions: dimensions}
+}
+
+// Dimensions implements Provider
+func (p *LocalProvider) Dimensions() int {
+	return p.dimensions
+}
+
+// Name implements Provider
+func (p *LocalProvider) Name() string {
+	return fmt.Sprintf("local/hash-%d", p.dimensions)
+}
+
+// Embed implements Provider
+func (p *LocalProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = p.embed(text)
+	}
+	return vectors, nil
+}
+
+// embed hashes the words and adjacent word pairs of text into a vector. A
+// second hash bit picks the sign so collisions tend to cancel out.
+func (p *LocalProvider) embed(text string) []float32 {
+	vector := make([]float32, p.dimensions)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	add := func(feature string, weight float32) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		sum := h.Sum64()
+
+		bucket := sum % uint64(p.dimensions)
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vector[bucket] += weight
+	}
+
+	for i, word := range words {
+		add(word, 1)
+		if i > 0 {
+			add(words[i-1]+" "+word, bigramWeight)
+		}
+	}
+
+	return Normalize(vector)
+}
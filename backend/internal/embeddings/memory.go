@@ -0,0 +1,94 @@
+// File: backend/internal/embeddings/memory.go
+
+package embeddings
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps vectors in process memory and searches them exhaustively.
+// It is the default store and suits single-replica deployments with up to a
+// few tens of thousands of records; its contents are lost on restart.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	dimensions int
+	namespaces map[string]map[string]Record
+}
+
+// NewMemoryStore creates an empty in-memory store for vectors of the given size
+func NewMemoryStore(dimensions int) *MemoryStore {
+	return &MemoryStore{
+		dimensions: dimensions,
+		namespaces: make(map[string]map[string]Record),
+	}
+}
+
+// Upsert implements VectorStore
+func (s *MemoryStore) Upsert(ctx context.Context, namespace string, records []Record) error {
+	for _, record := range records {
+		if len(record.Vector) != s.dimensions {
+			return ErrDimensionMismatch
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	collection, ok := s.namespaces[namespace]
+	if !ok {
+		collection = make(map[string]Record)
+		s.namespaces[namespace] = collection
+	}
+	for _, record := range records {
+		collection[record.ID] = record
+	}
+	return nil
+}
+
+// Search implements VectorStore
+func (s *MemoryStore) Search(ctx context.Context, namespace string, vector []float32, limit int) ([]Match, error) {
+	if len(vector) != s.dimensions {
+		return nil, ErrDimensionMismatch
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collection := s.namespaces[namespace]
+	matches := make([]Match, 0, len(collection))
+	for _, record := range collection {
+		matches = append(matches, Match{Record: record, Score: Cosine(vector, record.Vector)})
+	}
+
+	return topMatches(matches, limit), nil
+}
+
+// Delete implements VectorStore
+func (s *MemoryStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	collection := s.namespaces[namespace]
+	for _, id := range ids {
+		delete(collection, id)
+	}
+	return nil
+}
+
+// Close implements VectorStore
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// sortMatches orders matches by descending score, breaking ties by ID so
+// results are stable
+func sortMatches(matches []Match) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+}
@@ -0,0 +1,117 @@
+// File: backend/internal/embeddings/openai.go
+
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxInputLength caps each text sent for embedding; long posts are embedded
+// by their beginning
+const maxInputLength = 8000
+
+// OpenAIProvider embeds text with the OpenAI embeddings endpoint
+type OpenAIProvider struct {
+	apiKey     string
+	model      string
+	dimensions int
+	baseURL    string
+	client     *http.Client
+}
+
+// NewOpenAIProvider creates a provider for the given model. The text-embedding-3
+// models shorten their output to the requested dimensions.
+func NewOpenAIProvider(apiKey, model string, dimensions int) *OpenAIProvider {
+	return &OpenAIProvider{
+		apiKey:     apiKey,
+		model:      model,
+		dimensions: dimensions,
+		baseURL:    "https://api.openai.com/v1",
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Dimensions implements Provider
+func (p *OpenAIProvider) Dimensions() int {
+	return p.dimensions
+}
+
+// Name implements Provider
+func (p *OpenAIProvider) Name() string {
+	return fmt.Sprintf("openai/%s-%d", p.model, p.dimensions)
+}
+
+// Embed implements Provider
+func (p *OpenAIProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	inputs := make([]string, len(texts))
+	for i, text := range texts {
+		if len(text) > maxInputLength {
+			text = text[:maxInputLength]
+		}
+		// The API rejects empty strings
+		if text == "" {
+			text = " "
+		}
+		inputs[i] = text
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model":      p.model,
+		"input":      inputs,
+		"dimensions": p.dimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/embeddings", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request to OpenAI embeddings API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error response from OpenAI embeddings API (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAI embeddings response: %w", err)
+	}
+
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("OpenAI embeddings returned %d vectors for %d inputs", len(response.Data), len(texts))
+	}
+
+	// Results carry their input index and are not guaranteed to be in order
+	vectors := make([][]float32, len(texts))
+	for _, item := range response.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("OpenAI embeddings returned out-of-range index %d", item.Index)
+		}
+		if len(item.Embedding) != p.dimensions {
+			return nil, fmt.Errorf("%w: expected %d, got %d", ErrDimensionMismatch, p.dimensions, len(item.Embedding))
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
@@ -0,0 +1,173 @@
+// File: backend/internal/embeddings/pgvector.go
+
+package embeddings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq" // Postgres driver for the pgvector store
+)
+
+// PgvectorStore keeps vectors in Postgres with the pgvector extension and
+// uses an HNSW index for approximate nearest-neighbour search. It is the
+// store to use when several replicas share one index.
+type PgvectorStore struct {
+	db         *sql.DB
+	dimensions int
+}
+
+// OpenPgvectorStore connects to Postgres, enables the vector extension and
+// creates the embeddings table if needed
+func OpenPgvectorStore(ctx context.Context, dsn string, dimensions int) (*PgvectorStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening Postgres connection: %w", err)
+	}
+
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS embeddings (
+			namespace  TEXT NOT NULL,
+			id         TEXT NOT NULL,
+			embedding  vector(%d) NOT NULL,
+			metadata   JSONB NOT NULL DEFAULT '{}',
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+			PRIMARY KEY (namespace, id)
+		)`, dimensions),
+		"CREATE INDEX IF NOT EXISTS embeddings_hnsw_idx ON embeddings USING hnsw (embedding vector_cosine_ops)",
+	}
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error preparing pgvector schema: %w", err)
+		}
+	}
+
+	return &PgvectorStore{db: db, dimensions: dimensions}, nil
+}
+
+// Upsert implements VectorStore
+func (s *PgvectorStore) Upsert(ctx context.Context, namespace string, records []Record) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, record := range records {
+		if len(record.Vector) != s.dimensions {
+			return ErrDimensionMismatch
+		}
+
+		metadata, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return fmt.Errorf("error encoding metadata: %w", err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO embeddings (namespace, id, embedding, metadata, updated_at)
+			VALUES ($1, $2, $3::vector, $4::jsonb, now())
+			ON CONFLICT (namespace, id) DO UPDATE SET
+				embedding = excluded.embedding,
+				metadata = excluded.metadata,
+				updated_at = excluded.updated_at`,
+			namespace, record.ID, formatVector(record.Vector), string(metadata))
+		if err != nil {
+			return fmt.Errorf("error storing embedding %s: %w", record.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Search implements VectorStore
+func (s *PgvectorStore) Search(ctx context.Context, namespace string, vector []float32, limit int) ([]Match, error) {
+	if len(vector) != s.dimensions {
+		return nil, ErrDimensionMismatch
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, embedding::text, metadata::text, 1 - (embedding <=> $2::vector) AS score
+		FROM embeddings
+		WHERE namespace = $1
+		ORDER BY embedding <=> $2::vector
+		LIMIT $3`,
+		namespace, formatVector(vector), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var (
+			match            Match
+			vector, metadata string
+		)
+		if err := rows.Scan(&match.ID, &vector, &metadata, &match.Score); err != nil {
+			return nil, fmt.Errorf("error scanning embedding: %w", err)
+		}
+
+		if match.Vector, err = parseVector(vector); err != nil {
+			return nil, fmt.Errorf("error decoding embedding %s: %w", match.ID, err)
+		}
+		if err := json.Unmarshal([]byte(metadata), &match.Metadata); err != nil {
+			return nil, fmt.Errorf("error decoding metadata for %s: %w", match.ID, err)
+		}
+
+		matches = append(matches, match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading embeddings: %w", err)
+	}
+
+	return matches, nil
+}
+
+// Delete implements VectorStore
+func (s *PgvectorStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	for _, id := range ids {
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM embeddings WHERE namespace = $1 AND id = $2", namespace, id); err != nil {
+			return fmt.Errorf("error deleting embedding %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Close implements VectorStore
+func (s *PgvectorStore) Close() error {
+	return s.db.Close()
+}
+
+// formatVector renders a vector in pgvector's text format, e.g. "[1,0.5]"
+func formatVector(vector []float32) string {
+	parts := make([]string, len(vector))
+	for i, x := range vector {
+		parts[i] = strconv.FormatFloat(float64(x), 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]"
+}
+
+// parseVector parses pgvector's text format
+func parseVector(text string) ([]float32, error) {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "["), "]")
+	if text == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(text, ",")
+	vector := make([]float32, len(parts))
+	for i, part := range parts {
+		x, err := strconv.ParseFloat(part, 32)
+		if err != nil {
+			return nil, err
+		}
+		vector[i] = float32(x)
+	}
+	return vector, nil
+}
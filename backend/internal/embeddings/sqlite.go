@@ -0,0 +1,174 @@
+// File: backend/internal/embeddings/sqlite.go
+
+package embeddings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver, keeps CGO_ENABLED=0 builds working
+)
+
+// SQLiteStore persists vectors in a SQLite database.
+//
+// The pure Go driver cannot load the sqlite-vss extension, so vectors are
+// stored as float32 blobs and searched with an exact scan of the namespace.
+// That is fast enough for the tens of thousands of records a single node
+// indexes; larger deployments should use pgvector.
+type SQLiteStore struct {
+	db         *sql.DB
+	dimensions int
+}
+
+// OpenSQLiteStore opens (or creates) a vector database at path
+func OpenSQLiteStore(path string, dimensions int) (*SQLiteStore, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening vector database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS embeddings (
+			namespace  TEXT NOT NULL,
+			id         TEXT NOT NULL,
+			dimensions INTEGER NOT NULL,
+			vector     BLOB NOT NULL,
+			metadata   TEXT NOT NULL DEFAULT '{}',
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (namespace, id)
+		)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating embeddings table: %w", err)
+	}
+
+	return &SQLiteStore{db: db, dimensions: dimensions}, nil
+}
+
+// Upsert implements VectorStore
+func (s *SQLiteStore) Upsert(ctx context.Context, namespace string, records []Record) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO embeddings (namespace, id, dimensions, vector, metadata, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (namespace, id) DO UPDATE SET
+			dimensions = excluded.dimensions,
+			vector = excluded.vector,
+			metadata = excluded.metadata,
+			updated_at = excluded.updated_at`)
+	if err != nil {
+		return fmt.Errorf("error preparing upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		if len(record.Vector) != s.dimensions {
+			return ErrDimensionMismatch
+		}
+
+		metadata, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return fmt.Errorf("error encoding metadata: %w", err)
+		}
+
+		if _, err := stmt.ExecContext(ctx, namespace, record.ID, s.dimensions, encodeVector(record.Vector), string(metadata)); err != nil {
+			return fmt.Errorf("error storing embedding %s: %w", record.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Search implements VectorStore
+func (s *SQLiteStore) Search(ctx context.Context, namespace string, vector []float32, limit int) ([]Match, error) {
+	if len(vector) != s.dimensions {
+		return nil, ErrDimensionMismatch
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, vector, metadata FROM embeddings WHERE namespace = ? AND dimensions = ?",
+		namespace, s.dimensions)
+	if err != nil {
+		return nil, fmt.Errorf("error querying embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []Match
+	for rows.Next() {
+		var (
+			id       string
+			blob     []byte
+			metadata string
+		)
+		if err := rows.Scan(&id, &blob, &metadata); err != nil {
+			return nil, fmt.Errorf("error scanning embedding: %w", err)
+		}
+
+		record := Record{ID: id, Vector: decodeVector(blob)}
+		if err := json.Unmarshal([]byte(metadata), &record.Metadata); err != nil {
+			return nil, fmt.Errorf("error decoding metadata for %s: %w", id, err)
+		}
+
+		matches = append(matches, Match{Record: record, Score: Cosine(vector, record.Vector)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading embeddings: %w", err)
+	}
+
+	return topMatches(matches, limit), nil
+}
+
+// Delete implements VectorStore
+func (s *SQLiteStore) Delete(ctx context.Context, namespace string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids)+1)
+	args = append(args, namespace)
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := fmt.Sprintf("DELETE FROM embeddings WHERE namespace = ? AND id IN (%s)", placeholders)
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error deleting embeddings: %w", err)
+	}
+	return nil
+}
+
+// Close implements VectorStore
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// encodeVector packs a vector as little-endian float32s
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, x := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf
+}
+
+// decodeVector unpacks a vector written by encodeVector
+func decodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
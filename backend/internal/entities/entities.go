@@ -0,0 +1,340 @@
+// File: backend/internal/entities/entities.go
+
+// Package entities extracts named entities, such as products, games, people
+// and models, from search results and counts how often they are mentioned.
+// Extraction is heuristic: runs of capitalized words and model numbers like
+// "LG C3", "RTX 4090" or "iPhone 15 Pro".
+package entities
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+const (
+	// maxEntities caps the entities returned
+	maxEntities = 15
+	// maxEntityWords drops longer capitalized runs, which are usually title
+	// case headings rather than names
+	maxEntityWords = 4
+	// minMentions keeps one-off capitalized words out of the list
+	minMentions = 2
+	// maxResultIDs caps Entity.ResultIDs
+	maxResultIDs = 5
+)
+
+// stopwords are capitalized words that aren't names: sentence starters,
+// Reddit shorthand, and dates
+var stopwords = toSet(
+	"a", "an", "the", "i", "i'm", "i've", "i'd", "i'll", "im", "ive", "it", "its", "it's",
+	"this", "that", "these", "those", "there", "here", "they", "we", "you", "he", "she",
+	"my", "your", "our", "their", "his", "her", "me", "us", "them",
+	"and", "or", "but", "so", "if", "then", "also", "just", "not", "no", "yes", "yeah", "ok", "okay",
+	"in", "on", "at", "for", "of", "to", "from", "with", "by", "as", "about", "after", "before",
+	"is", "are", "was", "were", "be", "been", "do", "does", "did", "have", "has", "had",
+	"can", "could", "would", "should", "will", "may", "might", "must",
+	"what", "which", "who", "why", "how", "when", "where",
+	"best", "top", "good", "great", "better", "new", "old", "any", "all", "some", "most", "more",
+	"edit", "update", "tl;dr", "tldr", "op", "imo", "imho", "afaik", "fwiw", "ymmv", "psa",
+	"lol", "lmao", "til", "eli5", "ama", "iirc", "btw", "thanks", "thank", "please", "hi", "hey",
+	"reddit", "subreddit", "comment", "post",
+	"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+	"january", "february", "march", "april", "june", "july", "august",
+	"september", "october", "november", "december",
+)
+
+// token is a word and whether it starts a sentence
+type token struct {
+	text          string
+	sentenceStart bool
+}
+
+// mention is one occurrence of an entity
+type mention struct {
+	key  string // Case-folded name
+	name string // As written
+}
+
+// Extract returns the entities mentioned at least twice across results,
+// those mentioned in the most results first. Words of the query are the
+// topic rather than items, so names made only of them are left out.
+func Extract(query string, results []models.SearchResult) []models.Entity {
+	queryWords := make(map[string]bool)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		queryWords[strings.Trim(word, `"'.,!?()`)] = true
+	}
+
+	type tally struct {
+		entity   models.Entity
+		spelling map[string]int
+		order    int
+	}
+	texts := make([]string, len(results))
+	for i, result := range results {
+		// A comment's title is its post's, so it would count the post again
+		texts[i] = result.Content
+		if result.Type != "comment" {
+			texts[i] = result.Title + ".\n" + result.Content
+		}
+	}
+	known := midSentenceNames(texts)
+
+	tallies := make(map[string]*tally)
+	for i, result := range results {
+		seen := make(map[string]bool)
+		for _, m := range mentions(texts[i], queryWords, known) {
+			t, ok := tallies[m.key]
+			if !ok {
+				t = &tally{spelling: make(map[string]int), order: len(tallies)}
+				tallies[m.key] = t
+			}
+			t.entity.Mentions++
+			t.spelling[m.name]++
+			if !seen[m.key] {
+				seen[m.key] = true
+				t.entity.Results++
+				t.entity.Score += result.Score
+				if len(t.entity.ResultIDs) < maxResultIDs {
+					t.entity.ResultIDs = append(t.entity.ResultIDs, result.ID)
+				}
+			}
+		}
+	}
+
+	var kept []*tally
+	for _, t := range tallies {
+		if t.entity.Mentions < minMentions {
+			continue
+		}
+		t.entity.Name = mostCommon(t.spelling)
+		kept = append(kept, t)
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		a, b := kept[i].entity, kept[j].entity
+		if a.Results != b.Results {
+			return a.Results > b.Results
+		}
+		if a.Mentions != b.Mentions {
+			return a.Mentions > b.Mentions
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return kept[i].order < kept[j].order
+	})
+
+	if len(kept) > maxEntities {
+		kept = kept[:maxEntities]
+	}
+	entities := make([]models.Entity, len(kept))
+	for i, t := range kept {
+		entities[i] = t.entity
+	}
+	return entities
+}
+
+// mentions finds the names in text: runs of up to maxEntityWords name-like
+// words within a sentence. known holds the case-folded words seen
+// capitalized mid-sentence, which are names even when opening one.
+func mentions(text string, queryWords, known map[string]bool) []mention {
+	var found []mention
+	for _, sentence := range sentences(tokenize(text)) {
+		heading := isHeading(sentence)
+		var span []token
+		flush := func() {
+			if m, ok := spanMention(span, queryWords, heading); ok {
+				found = append(found, m)
+			}
+			span = nil
+		}
+
+		for _, tok := range sentence {
+			// A sentence's first word is capitalized anyway ("Try the LG C3")
+			if tok.sentenceStart && !distinctive(tok.text) && !known[strings.ToLower(tok.text)] {
+				continue
+			}
+			// Numbers continue a name ("iPhone 15") but don't start one
+			if startsName(tok.text) || (len(span) > 0 && isNumber(tok.text)) {
+				span = append(span, tok)
+				continue
+			}
+			flush()
+		}
+		flush()
+	}
+	return found
+}
+
+// midSentenceNames returns the case-folded words capitalized somewhere other
+// than at the start of a sentence
+func midSentenceNames(texts []string) map[string]bool {
+	known := make(map[string]bool)
+	for _, text := range texts {
+		for _, tok := range tokenize(text) {
+			if !tok.sentenceStart && startsName(tok.text) {
+				known[strings.ToLower(tok.text)] = true
+			}
+		}
+	}
+	return known
+}
+
+// sentences splits tokens at sentence starts
+func sentences(tokens []token) [][]token {
+	var split [][]token
+	for i, tok := range tokens {
+		if tok.sentenceStart || i == 0 {
+			split = append(split, nil)
+		}
+		split[len(split)-1] = append(split[len(split)-1], tok)
+	}
+	return split
+}
+
+// isHeading reports whether a sentence is written in title case, like most
+// post titles, where capitals don't mark names
+func isHeading(sentence []token) bool {
+	if len(sentence) < 4 {
+		return false
+	}
+	capitalized := 0
+	for _, tok := range sentence {
+		if first, _ := utf8.DecodeRuneInString(tok.text); unicode.IsUpper(first) {
+			capitalized++
+		}
+	}
+	return capitalized*4 >= len(sentence)*3
+}
+
+// spanMention turns a run of name-like words into a mention, if it is one.
+// In headings only runs with a distinctive word ("LG C3") count.
+func spanMention(span []token, queryWords map[string]bool, heading bool) (mention, bool) {
+	if len(span) == 0 || len(span) > maxEntityWords {
+		return mention{}, false
+	}
+
+	words := make([]string, len(span))
+	topical, plain := true, true
+	for i, tok := range span {
+		words[i] = tok.text
+		if distinctive(tok.text) {
+			plain = false
+		}
+		lower := strings.ToLower(tok.text)
+		if !queryWords[lower] && !queryWords[strings.TrimSuffix(lower, "s")] && !queryWords[lower+"s"] {
+			topical = false
+		}
+	}
+	if topical || (heading && plain) {
+		return mention{}, false
+	}
+
+	name := strings.Join(words, " ")
+	return mention{key: strings.ToLower(name), name: name}, true
+}
+
+// startsName reports whether word can be part of a name: capitalized, or
+// mixing letters and digits or cases, and not a stopword
+func startsName(word string) bool {
+	if stopwords[strings.ToLower(word)] {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(first) || distinctive(word)
+}
+
+// distinctive reports whether word looks like a name wherever it appears:
+// it has an uppercase letter after the first, as in "iPhone" or "OLED", or
+// mixes letters and digits, as in "C3" or "PS5"
+func distinctive(word string) bool {
+	var letters, digits, innerUpper bool
+	for i, r := range word {
+		switch {
+		case unicode.IsDigit(r):
+			digits = true
+		case unicode.IsLetter(r):
+			letters = true
+			if i > 0 && unicode.IsUpper(r) {
+				innerUpper = true
+			}
+		}
+	}
+	return letters && (digits || innerUpper)
+}
+
+// isNumber reports whether word is a short number, like a model number
+func isNumber(word string) bool {
+	if word == "" || len(word) > 5 {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits text into words, noting which start a sentence. Hyphens,
+// periods and apostrophes inside words are kept ("GPT-4o", "Node.js") and
+// trailing possessives dropped ("Sony's" becomes "Sony").
+func tokenize(text string) []token {
+	var (
+		tokens        []token
+		word          strings.Builder
+		sentenceStart = true
+	)
+	runes := []rune(text)
+	emit := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.TrimSuffix(strings.TrimSuffix(word.String(), "'s"), "’s")
+		tokens = append(tokens, token{text: w, sentenceStart: sentenceStart})
+		sentenceStart = false
+		word.Reset()
+	}
+
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		// Joiners are part of the word when letters or digits follow
+		if strings.ContainsRune("-.'’", r) && word.Len() > 0 && i+1 < len(runes) &&
+			(unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1])) {
+			word.WriteRune(r)
+			continue
+		}
+		emit()
+		if strings.ContainsRune(".!?\n:;", r) {
+			sentenceStart = true
+		}
+	}
+	emit()
+	return tokens
+}
+
+// mostCommon returns the most frequent spelling, preferring the
+// alphabetically first on ties so results are stable
+func mostCommon(spellings map[string]int) string {
+	best, bestCount := "", 0
+	for spelling, count := range spellings {
+		if count > bestCount || (count == bestCount && spelling < best) {
+			best, bestCount = spelling, count
+		}
+	}
+	return best
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,66 @@
+// File: backend/internal/entities/entities_test.go
+
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestExtract(t *testing.T) {
+	results := []models.SearchResult{
+		{ID: "p1", Type: "post", Score: 500, Title: "Best TVs for Movies in 2024",
+			Content: "I compared the LG C3 and the Sony A95L. The LG C3 wins on price, Sony's A95L on brightness."},
+		{ID: "c1", Type: "comment", Score: 120, Title: "Comment on: Best TVs for Movies in 2024",
+			Content: "Samsung is fine, but I'd get the LG C3. My friend has a Samsung S90C and loves it."},
+		{ID: "c2", Type: "comment", Score: 80, Title: "Comment on: Best TVs for Movies in 2024",
+			Content: "Edit: the Samsung S90C is on sale. Great TV! The iPhone 15 Pro app works with it. TVs are cheap now."},
+		{ID: "c3", Type: "comment", Score: 10, Content: "Honestly the Hisense U8 is underrated."},
+	}
+
+	entities := Extract("top 5 TVs", results)
+	var names []string
+	for _, entity := range entities {
+		names = append(names, entity.Name)
+	}
+	want := []string{"LG C3", "Samsung S90C", "Sony A95L"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v, got %v", want, names)
+	}
+
+	lg := entities[0]
+	if lg.Mentions != 3 || lg.Results != 2 || lg.Score != 620 || !reflect.DeepEqual(lg.ResultIDs, []string{"p1", "c1"}) {
+		t.Errorf("Unexpected LG C3 tally %+v", lg)
+	}
+	// "Sony's A95L" counts with the possessive dropped
+	if sony := entities[2]; sony.Mentions != 2 || sony.Results != 1 {
+		t.Errorf("Unexpected Sony A95L tally %+v", sony)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"I upgraded to an RTX 4090 from a GTX 1080.", []string{"RTX 4090", "GTX 1080"}},
+		{"Try GPT-4o or Node.js instead.", []string{"GPT-4o", "Node.js"}},
+		// Sentence-initial words count once seen capitalized mid-sentence
+		{"Hollow Knight is great. I replay Hollow Knight yearly.", []string{"Hollow Knight", "Hollow Knight"}},
+		// Other sentence-initial capitals and title case headings aren't names
+		{"Honestly it depends. Why Every Gamer Should Own This Console Today", nil},
+		{"The PS5 sold out in 2020.", []string{"PS5"}},
+		{"Ask Bill Gates about it.", []string{"Bill Gates"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, m := range mentions(tt.text, map[string]bool{}, midSentenceNames([]string{tt.text})) {
+			got = append(got, m.name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mentions(%q): expected %v, got %v", tt.text, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// File: backend/internal/export/annotations.go
+
+package export
+
+import "github.com/pranesh-j/subplexity/internal/models"
+
+// annotatedResult is a result with the note the exporting client attached
+type annotatedResult struct {
+	models.SearchResult
+	Note string
+}
+
+// annotatedResults pairs notes with the results they're on, in result
+// order. Notes on results the response doesn't have are left out.
+func annotatedResults(results []models.SearchResult, annotations []models.Annotation) []annotatedResult {
+	if len(annotations) == 0 {
+		return nil
+	}
+	notes := make(map[string]string, len(annotations))
+	for _, annotation := range annotations {
+		notes[annotation.ResultID] = annotation.Note
+	}
+
+	var annotated []annotatedResult
+	for _, result := range results {
+		if note, ok := notes[result.ID]; ok {
+			annotated = append(annotated, annotatedResult{SearchResult: result, Note: note})
+		}
+	}
+	return annotated
+}
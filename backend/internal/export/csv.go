@@ -0,0 +1,42 @@
+// File: backend/internal/export/csv.go
+
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// csvHeader lists the columns written by WriteCSV
+var csvHeader = []string{"title", "subreddit", "author", "score", "url", "created"}
+
+// WriteCSV writes search results as CSV for spreadsheet analysis
+func WriteCSV(w io.Writer, results []models.SearchResult) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, result := range results {
+		record := []string{
+			result.Title,
+			result.Subreddit,
+			result.Author,
+			strconv.Itoa(result.Score),
+			result.URL,
+			time.Unix(result.CreatedUTC, 0).UTC().Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,98 @@
+// File: backend/internal/export/embed.go
+
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// embedTemplate is a small card meant for an iframe: inline styles only, and
+// links open outside the frame
+var embedTemplate = template.Must(template.New("embed").Funcs(template.FuncMap{
+	"date": func(ts int64) string { return time.Unix(ts, 0).UTC().Format("January 2, 2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<base target="_blank">
+<title>{{.Query}} · Subplexity</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 1rem; color: #1a1a1b; line-height: 1.5; font-size: 0.95rem; }
+h1 { font-size: 1.1rem; margin: 0 0 0.5rem; }
+a { color: #0079d3; }
+a.cite { text-decoration: none; font-size: 0.8em; vertical-align: super; }
+ol { padding-left: 1.25rem; margin: 0.5rem 0; font-size: 0.85rem; }
+footer { color: #787c7e; font-size: 0.8rem; border-top: 1px solid #edeff1; padding-top: 0.5rem; margin-top: 0.75rem; }
+</style>
+</head>
+<body>
+<h1>{{.Query}}</h1>
+{{.Answer}}
+{{if .Citations}}<ol>
+{{range .Citations}}<li value="{{.Index}}"><a href="{{.URL}}" rel="noopener">{{.Title}}</a> — r/{{.Subreddit}}</li>
+{{end}}</ol>{{end}}
+<footer>Answered by <a href="{{.ReportURL}}" rel="noopener">Subplexity</a> from {{.TotalCount}} Reddit results · {{date .LastUpdated}}</footer>
+</body>
+</html>
+`))
+
+// NewEmbed builds the widget payload for a saved response, keeping its
+// first maxCitations citations
+func NewEmbed(response *models.SearchResponse, maxCitations int) *models.Embed {
+	citations := response.Citations
+	if len(citations) > maxCitations {
+		citations = citations[:maxCitations]
+	}
+	if citations == nil {
+		citations = []models.Citation{}
+	}
+
+	return &models.Embed{
+		ID:          response.ID,
+		Query:       response.RequestParams.Query,
+		Answer:      response.Answer,
+		Citations:   citations,
+		TotalCount:  response.TotalCount,
+		LastUpdated: response.LastUpdated,
+		ReportURL:   "/api/reports/" + response.ID,
+	}
+}
+
+// WriteEmbedHTML renders the widget as an HTML card. Citation markers for
+// the listed sources link straight to them; others are left as text.
+func WriteEmbedHTML(w io.Writer, embed *models.Embed) error {
+	answer, err := renderMarkdownHTML(embed.Answer, false)
+	if err != nil {
+		return fmt.Errorf("error rendering answer: %w", err)
+	}
+
+	urls := make(map[string]string, len(embed.Citations))
+	for _, citation := range embed.Citations {
+		urls[strconv.Itoa(citation.Index)] = citation.URL
+	}
+	answer = template.HTML(citationMarkerRegex.ReplaceAllStringFunc(string(answer), func(marker string) string {
+		url, ok := urls[marker[1:len(marker)-1]]
+		// Only web links; html/template would neutralize anything else
+		if !ok || !(strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")) {
+			return marker
+		}
+		return fmt.Sprintf(`<a class="cite" href="%s" rel="noopener">%s</a>`, template.HTMLEscapeString(url), marker)
+	}))
+
+	return embedTemplate.Execute(w, map[string]interface{}{
+		"Query":       embed.Query,
+		"Answer":      answer,
+		"Citations":   embed.Citations,
+		"TotalCount":  embed.TotalCount,
+		"LastUpdated": embed.LastUpdated,
+		"ReportURL":   embed.ReportURL,
+	})
+}
@@ -0,0 +1,171 @@
+// File: backend/internal/export/html.go
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/yuin/goldmark"
+)
+
+// citationMarkerRegex matches [n] citation markers in rendered HTML
+var citationMarkerRegex = regexp.MustCompile(`\[([0-9]+)\]`)
+
+// reportTemplate is a self-contained page: all styling is inline so the file
+// can be shared or archived without access to the app
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc":  func(i int) int { return i + 1 },
+	"date": func(ts int64) string { return time.Unix(ts, 0).UTC().Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · Subplexity report</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1a1a1b; line-height: 1.6; }
+h1 { font-size: 1.75rem; margin-bottom: 0.25rem; }
+.meta { color: #787c7e; font-size: 0.9rem; margin-bottom: 2rem; }
+section { margin-bottom: 2rem; }
+details { border: 1px solid #edeff1; border-radius: 6px; padding: 0.5rem 1rem; margin-bottom: 0.5rem; }
+summary { cursor: pointer; font-weight: 600; }
+a { color: #0079d3; }
+a.cite { text-decoration: none; font-size: 0.8em; vertical-align: super; }
+table { width: 100%; border-collapse: collapse; font-size: 0.9rem; }
+th, td { text-align: left; padding: 0.5rem; border-bottom: 1px solid #edeff1; vertical-align: top; }
+th { background: #f6f7f8; }
+td.num { text-align: right; white-space: nowrap; }
+.note { border-left: 3px solid #0079d3; padding: 0 1rem; margin-bottom: 1rem; }
+.note p { white-space: pre-wrap; margin: 0.25rem 0 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="meta">Generated by Subplexity from {{.TotalCount}} Reddit results · {{.Generated}}</div>
+
+<section>
+<h2>Answer</h2>
+{{.Answer}}
+</section>
+
+{{if .Steps}}
+<section>
+<h2>Reasoning</h2>
+{{range $i, $step := .Steps}}
+<details>
+<summary>{{$step.Title}}</summary>
+{{$step.Content}}
+</details>
+{{end}}
+</section>
+{{end}}
+
+{{if .Citations}}
+<section>
+<h2>Sources</h2>
+<ol>
+{{range .Citations}}<li id="source-{{.Index}}" value="{{.Index}}"><a href="{{.URL}}" target="_blank" rel="noopener">{{.Title}}</a> — r/{{.Subreddit}}</li>
+{{end}}</ol>
+</section>
+{{end}}
+
+{{if .Notes}}
+<section>
+<h2>Notes</h2>
+{{range .Notes}}<div class="note"><a href="{{.URL}}" target="_blank" rel="noopener">{{.Title}}</a> — r/{{.Subreddit}}
+<p>{{.Note}}</p></div>
+{{end}}</section>
+{{end}}
+
+{{if .Results}}
+<section>
+<h2>Results</h2>
+<table>
+<thead><tr><th>#</th><th>Title</th><th>Subreddit</th><th>Author</th><th>Score</th><th>Comments</th><th>Posted</th></tr></thead>
+<tbody>
+{{range $i, $result := .Results}}<tr>
+<td class="num">{{inc $i}}</td>
+<td><a href="{{$result.URL}}" target="_blank" rel="noopener">{{$result.Title}}</a></td>
+<td>r/{{$result.Subreddit}}</td>
+<td>u/{{$result.Author}}</td>
+<td class="num">{{$result.Score}}</td>
+<td class="num">{{$result.CommentCount}}</td>
+<td>{{date $result.CreatedUTC}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+</section>
+{{end}}
+</body>
+</html>
+`))
+
+// reportStep is a reasoning step with its content pre-rendered to HTML
+type reportStep struct {
+	Title   string
+	Content template.HTML
+}
+
+// WriteHTML renders a self-contained HTML report for a search response,
+// including the viewing client's notes on its results
+func WriteHTML(w io.Writer, response *models.SearchResponse, annotations []models.Annotation) error {
+	answer, err := renderMarkdownHTML(response.Answer, true)
+	if err != nil {
+		return fmt.Errorf("error rendering answer: %w", err)
+	}
+
+	var steps []reportStep
+	for _, step := range response.ReasoningSteps {
+		content, err := renderMarkdownHTML(step.Content, true)
+		if err != nil {
+			return fmt.Errorf("error rendering reasoning step: %w", err)
+		}
+		steps = append(steps, reportStep{Title: step.Title, Content: content})
+	}
+
+	// Keep the raw reasoning visible when no structured steps were extracted
+	if len(steps) == 0 && strings.TrimSpace(response.Reasoning) != "" {
+		content, err := renderMarkdownHTML(response.Reasoning, true)
+		if err != nil {
+			return fmt.Errorf("error rendering reasoning: %w", err)
+		}
+		steps = append(steps, reportStep{Title: "Analysis of search results", Content: content})
+	}
+
+	data := map[string]interface{}{
+		"Title":      markdownTitle(response),
+		"TotalCount": response.TotalCount,
+		"Generated":  time.Unix(response.LastUpdated, 0).UTC().Format("January 2, 2006 15:04 MST"),
+		"Answer":     answer,
+		"Steps":      steps,
+		"Citations":  response.Citations,
+		"Notes":      annotatedResults(response.Results, annotations),
+		"Results":    response.Results,
+	}
+
+	return reportTemplate.Execute(w, data)
+}
+
+// renderMarkdownHTML converts Markdown to HTML. Raw HTML in the source is
+// escaped by goldmark's default renderer, so model output can't inject markup.
+// When linkCitations is set, [n] markers become links to the source list.
+func renderMarkdownHTML(source string, linkCitations bool) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+
+	rendered := buf.String()
+	if linkCitations {
+		rendered = citationMarkerRegex.ReplaceAllString(rendered, `<a class="cite" href="#source-$1">[$1]</a>`)
+	}
+
+	return template.HTML(rendered), nil
+}
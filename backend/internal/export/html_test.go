@@ -0,0 +1,48 @@
+// File: backend/internal/export/html_test.go
+
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestWriteHTMLEscapesRedditContent(t *testing.T) {
+	const script = `<script>alert(1)</script>`
+	response := &models.SearchResponse{
+		Answer:         "See [1]. " + script + " [click](javascript:alert(1))",
+		ReasoningSteps: []models.ReasoningStep{{Title: "Step " + script, Content: "<img src=x onerror=alert(1)>"}},
+		Citations: []models.Citation{
+			{Index: 1, Title: "Post " + script, URL: "javascript:alert(1)", Subreddit: "golang" + script},
+		},
+		Results: []models.SearchResult{
+			{ID: "a1", Title: `"><svg onload=alert(1)>`, URL: "https://reddit.com/a1", Subreddit: "golang", Author: "u" + script},
+		},
+		TotalCount:    1,
+		RequestParams: models.RequestParams{Query: "</title>" + script},
+	}
+	annotations := []models.Annotation{{ResultID: "a1", Note: script}}
+
+	var out strings.Builder
+	if err := WriteHTML(&out, response, annotations); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	html := out.String()
+
+	for _, unsafe := range []string{"<script>", "<img", "<svg", "javascript:", "</title><"} {
+		if strings.Contains(html, unsafe) {
+			t.Errorf("Expected %q to be escaped in:\n%s", unsafe, html)
+		}
+	}
+	for _, want := range []string{
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+		`&#34;&gt;&lt;svg onload=alert(1)&gt;`,
+		`<a class="cite" href="#source-1">[1]</a>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in:\n%s", want, html)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// File: backend/internal/export/markdown.go
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// WriteMarkdown renders the answer, reasoning steps and citations of a search
+// response into a single Markdown document, followed by the exporting
+// client's notes on its results
+func WriteMarkdown(w io.Writer, response *models.SearchResponse, annotations []models.Annotation) error {
+	var builder strings.Builder
+
+	// Title and metadata
+	builder.WriteString(fmt.Sprintf("# %s\n\n", markdownTitle(response)))
+	builder.WriteString(fmt.Sprintf("_Generated by Subplexity from %d Reddit results on %s_\n\n",
+		response.TotalCount, time.Unix(response.LastUpdated, 0).UTC().Format("January 2, 2006")))
+
+	// Answer
+	builder.WriteString("## Answer\n\n")
+	builder.WriteString(strings.TrimSpace(response.Answer))
+	builder.WriteString("\n\n")
+
+	// Reasoning steps (fall back to the raw reasoning text)
+	if len(response.ReasoningSteps) > 0 {
+		builder.WriteString("## Reasoning\n\n")
+		for i, step := range response.ReasoningSteps {
+			builder.WriteString(fmt.Sprintf("### %d. %s\n\n", i+1, step.Title))
+			builder.WriteString(strings.TrimSpace(step.Content))
+			builder.WriteString("\n\n")
+		}
+	} else if strings.TrimSpace(response.Reasoning) != "" {
+		builder.WriteString("## Reasoning\n\n")
+		builder.WriteString(strings.TrimSpace(response.Reasoning))
+		builder.WriteString("\n\n")
+	}
+
+	// Numbered citation list, matching the [n] markers in the answer
+	if len(response.Citations) > 0 {
+		builder.WriteString("## Sources\n\n")
+		for _, citation := range response.Citations {
+			builder.WriteString(fmt.Sprintf("%d. [%s](%s) — r/%s\n",
+				citation.Index, escapeMarkdownLinkText(citation.Title), citation.URL, citation.Subreddit))
+		}
+		builder.WriteString("\n")
+	}
+
+	if notes := annotatedResults(response.Results, annotations); len(notes) > 0 {
+		builder.WriteString("## Notes\n\n")
+		for _, result := range notes {
+			builder.WriteString(fmt.Sprintf("**[%s](%s)** — r/%s\n\n",
+				escapeMarkdownLinkText(result.Title), result.URL, result.Subreddit))
+			for _, line := range strings.Split(strings.TrimSpace(result.Note), "\n") {
+				builder.WriteString("> " + line + "\n")
+			}
+			builder.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, builder.String())
+	return err
+}
+
+// markdownTitle picks a document title for a response
+func markdownTitle(response *models.SearchResponse) string {
+	if query := strings.TrimSpace(response.RequestParams.Query); query != "" {
+		return query
+	}
+	return "Subplexity answer"
+}
+
+// markdownLinkTextReplacer escapes characters that would break a Markdown
+// link label. Backslashes are escaped too, so a title ending in one doesn't
+// escape the closing bracket, and line breaks, which end the link, become
+// spaces.
+var markdownLinkTextReplacer = strings.NewReplacer("\\", "\\\\", "[", "\\[", "]", "\\]", "\r\n", " ", "\n", " ", "\r", " ")
+
+// escapeMarkdownLinkText escapes characters that would break a Markdown link label
+func escapeMarkdownLinkText(text string) string {
+	return markdownLinkTextReplacer.Replace(text)
+}
@@ -0,0 +1,98 @@
+// File: backend/internal/export/markdown_test.go
+
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	updated := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC).Unix()
+	full := &models.SearchResponse{
+		Answer:         "Most people recommend Go [1].",
+		ReasoningSteps: []models.ReasoningStep{{Title: "Compare options", Content: "Go came up most."}},
+		Citations: []models.Citation{
+			{Index: 1, Title: "Which language?", URL: "https://reddit.com/r/golang/comments/a1", Subreddit: "golang"},
+		},
+		Results: []models.SearchResult{
+			{ID: "a1", Title: "Which language?", URL: "https://reddit.com/r/golang/comments/a1", Subreddit: "golang"},
+		},
+		TotalCount:    1,
+		LastUpdated:   updated,
+		RequestParams: models.RequestParams{Query: "best backend language"},
+	}
+
+	tests := []struct {
+		name        string
+		response    *models.SearchResponse
+		annotations []models.Annotation
+		want        []string
+		notWant     []string
+	}{
+		{
+			name:        "Full response",
+			response:    full,
+			annotations: []models.Annotation{{ResultID: "a1", Note: "Check the\nbenchmarks"}},
+			want: []string{
+				"# best backend language\n",
+				"_Generated by Subplexity from 1 Reddit results on March 9, 2024_",
+				"## Answer\n\nMost people recommend Go [1].\n",
+				"### 1. Compare options\n\nGo came up most.\n",
+				"1. [Which language?](https://reddit.com/r/golang/comments/a1) — r/golang\n",
+				"## Notes\n\n**[Which language?](https://reddit.com/r/golang/comments/a1)** — r/golang\n\n> Check the\n> benchmarks\n",
+			},
+		},
+		{
+			name: "Empty results",
+			response: &models.SearchResponse{
+				Answer:      "Nothing relevant was found.",
+				Reasoning:   "No results matched.",
+				LastUpdated: updated,
+			},
+			annotations: []models.Annotation{{ResultID: "gone", Note: "orphaned"}},
+			want: []string{
+				"# Subplexity answer\n",
+				"from 0 Reddit results",
+				"## Reasoning\n\nNo results matched.\n",
+			},
+			notWant: []string{"## Sources", "## Notes", "orphaned"},
+		},
+		{
+			name: "Markdown in titles",
+			response: &models.SearchResponse{
+				Citations: []models.Citation{
+					{Index: 1, Title: "[Help] is *this* a bug?", URL: "https://reddit.com/1", Subreddit: "golang"},
+					{Index: 2, Title: "Paths like C:\\", URL: "https://reddit.com/2", Subreddit: "golang"},
+					{Index: 3, Title: "Two\nlines", URL: "https://reddit.com/3", Subreddit: "golang"},
+				},
+				LastUpdated: updated,
+			},
+			want: []string{
+				"1. [\\[Help\\] is *this* a bug?](https://reddit.com/1)",
+				"2. [Paths like C:\\\\](https://reddit.com/2)",
+				"3. [Two lines](https://reddit.com/3)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := WriteMarkdown(&out, tt.response, tt.annotations); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: expected %q in:\n%s", tt.name, want, out.String())
+			}
+		}
+		for _, notWant := range tt.notWant {
+			if strings.Contains(out.String(), notWant) {
+				t.Errorf("%s: unexpected %q in:\n%s", tt.name, notWant, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// File: backend/internal/indexer/indexer.go
+
+// Package indexer crawls configured subreddits in the background and keeps
+// their recent posts, with embeddings, in a local index. Searches on popular
+// topics can then be answered from the index without a Reddit round trip.
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/embeddings"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/store"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// namespace is the vector store namespace holding post embeddings
+const namespace = "indexed-posts"
+
+// maxEmbeddedContent caps how much of a post body is embedded; the title and
+// opening paragraphs carry most of the topic
+const maxEmbeddedContent = 2000
+
+// candidateFactor widens the vector search when results are filtered by
+// subreddit afterwards
+const candidateFactor = 4
+
+// Fetcher retrieves subreddit listings. *services.RedditService implements it.
+type Fetcher interface {
+	FetchListing(ctx context.Context, subreddit, listing string, limit int) ([]models.SearchResult, error)
+}
+
+// Indexer periodically crawls subreddits and answers queries from what it
+// has stored
+type Indexer struct {
+	fetcher    Fetcher
+	store      *store.Store
+	embeddings *embeddings.Service
+	cfg        config.IndexerConfig
+}
+
+// New creates an indexer. Call Start to begin crawling.
+func New(fetcher Fetcher, dataStore *store.Store, embeddingService *embeddings.Service, cfg config.IndexerConfig) *Indexer {
+	return &Indexer{
+		fetcher:    fetcher,
+		store:      dataStore,
+		embeddings: embeddingService,
+		cfg:        cfg,
+	}
+}
+
+// Start crawls immediately and then every configured interval until ctx is
+// cancelled. It blocks, so run it in a goroutine.
+func (i *Indexer) Start(ctx context.Context) {
+	log.Printf("Indexer: crawling %d subreddits every %s", len(i.cfg.Subreddits), i.cfg.Interval)
+
+	ticker := time.NewTicker(i.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.RunOnce(ctx); err != nil {
+			log.Printf("Indexer: crawl failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce crawls every configured listing, stores and embeds new posts, and
+// prunes posts past the retention period. Failures on individual listings
+// are logged and skipped.
+func (i *Indexer) RunOnce(ctx context.Context) error {
+	startTime := time.Now()
+	indexed := 0
+
+	for _, subreddit := range i.cfg.Subreddits {
+		posts := make(map[string]models.SearchResult)
+		for _, listing := range i.cfg.Listings {
+			results, err := i.fetcher.FetchListing(ctx, subreddit, listing, i.cfg.PostsPerListing)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				log.Printf("Indexer: error fetching r/%s/%s: %v", subreddit, listing, err)
+				continue
+			}
+			for _, result := range results {
+				if result.Type == "post" {
+					posts[result.ID] = result
+				}
+			}
+		}
+
+		if err := i.index(ctx, posts); err != nil {
+			log.Printf("Indexer: error indexing r/%s: %v", subreddit, err)
+			continue
+		}
+		indexed += len(posts)
+	}
+
+	pruned, err := i.prune(ctx)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Indexer: indexed %d posts, pruned %d in %.1fs", indexed, pruned, time.Since(startTime).Seconds())
+	return nil
+}
+
+// index stores posts and their embeddings
+func (i *Indexer) index(ctx context.Context, posts map[string]models.SearchResult) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	results := make([]models.SearchResult, 0, len(posts))
+	docs := make([]embeddings.Document, 0, len(posts))
+	for _, post := range posts {
+		content := utils.Truncate(post.Content, maxEmbeddedContent)
+
+		// Raw Reddit JSON is for debugging live searches, not worth storing
+		post.Raw = nil
+		results = append(results, post)
+		docs = append(docs, embeddings.Document{
+			ID:       post.ID,
+			Text:     post.Title + "\n" + content,
+			Metadata: map[string]string{"subreddit": strings.ToLower(post.Subreddit)},
+		})
+	}
+
+	if err := i.store.UpsertIndexedPosts(ctx, results); err != nil {
+		return err
+	}
+	return i.embeddings.Index(ctx, namespace, docs)
+}
+
+// prune removes posts older than the retention period
+func (i *Indexer) prune(ctx context.Context) (int, error) {
+	if i.cfg.Retention <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-i.cfg.Retention).Unix()
+	ids, err := i.store.PruneIndexedPosts(ctx, before)
+	if err != nil {
+		return 0, err
+	}
+	if err := i.embeddings.Delete(ctx, namespace, ids); err != nil {
+		return 0, fmt.Errorf("error deleting pruned embeddings: %w", err)
+	}
+	return len(ids), nil
+}
+
+// Search returns up to limit indexed posts relevant to query, most similar
+// first. It returns nil when fewer than the configured minimum are relevant,
+// signalling that the query should go to Reddit instead. A non-empty
+// subreddits list restricts matches to those communities.
+func (i *Indexer) Search(ctx context.Context, query string, subreddits []string, limit int) ([]models.SearchResult, error) {
+	allowed := make(map[string]bool, len(subreddits))
+	for _, subreddit := range subreddits {
+		allowed[config.NormalizeSubreddit(subreddit)] = true
+	}
+
+	candidates := limit
+	if len(allowed) > 0 {
+		candidates *= candidateFactor
+	}
+
+	matches, err := i.embeddings.Search(ctx, namespace, query, candidates, i.cfg.MinSimilarity)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	similarity := make(map[string]float64, len(matches))
+	for _, match := range matches {
+		if len(allowed) > 0 && !allowed[match.Metadata["subreddit"]] {
+			continue
+		}
+		ids = append(ids, match.ID)
+		similarity[match.ID] = match.Score
+		if len(ids) == limit {
+			break
+		}
+	}
+
+	if len(ids) < i.cfg.MinResults {
+		return nil, nil
+	}
+
+	posts, err := i.store.GetIndexedPosts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) < i.cfg.MinResults {
+		return nil, nil
+	}
+
+	// Relevance is similarity relative to the closest post, as for results
+	// ranked from Reddit
+	best := 0.0
+	for _, post := range posts {
+		best = math.Max(best, similarity[post.ID])
+	}
+	for j := range posts {
+		if best > 0 {
+			posts[j].Relevance = math.Round(math.Min(similarity[posts[j].ID]/best, 1)*1000) / 1000
+		}
+	}
+	return posts, nil
+}
@@ -0,0 +1,94 @@
+// File: backend/internal/indexer/indexer_test.go
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/embeddings"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// staticFetcher serves canned listings keyed by subreddit
+type staticFetcher map[string][]models.SearchResult
+
+func (f staticFetcher) FetchListing(ctx context.Context, subreddit, listing string, limit int) ([]models.SearchResult, error) {
+	return f[subreddit], nil
+}
+
+func TestIndexAndSearch(t *testing.T) {
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer dataStore.Close()
+
+	now := time.Now().Unix()
+	post := func(id, subreddit, title string, created int64) models.SearchResult {
+		return models.SearchResult{ID: id, Type: "post", Subreddit: subreddit, Title: title, CreatedUTC: created}
+	}
+
+	var llama []models.SearchResult
+	for n := 0; n < 3; n++ {
+		llama = append(llama, post(fmt.Sprintf("l%d", n), "LocalLLaMA", "Best local model for coding on a 24GB GPU", now))
+	}
+	llama = append(llama, post("old", "LocalLLaMA", "Best local model for coding on a 24GB GPU", now-30*24*3600))
+
+	fetcher := staticFetcher{
+		"localllama": llama,
+		"buildapc":   {post("b1", "buildapc", "Best GPU for running a local model for coding", now)},
+	}
+
+	provider := embeddings.NewLocalProvider(256)
+	cfg := config.IndexerConfig{
+		Subreddits:      []string{"localllama", "buildapc"},
+		Listings:        []string{"new"},
+		PostsPerListing: 50,
+		Retention:       7 * 24 * time.Hour,
+		MinSimilarity:   0.3,
+		MinResults:      3,
+	}
+	idx := New(fetcher, dataStore, embeddings.NewService(provider, embeddings.NewMemoryStore(256)), cfg)
+
+	ctx := context.Background()
+	if err := idx.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce failed: %v", err)
+	}
+
+	results, err := idx.Search(ctx, "best local model for coding", nil, 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results (old post pruned), got %d", len(results))
+	}
+	for i, result := range results {
+		if result.ID == "old" {
+			t.Errorf("Expected posts past retention to be pruned")
+		}
+		if result.Relevance <= 0 || result.Relevance > 1 || (i == 0 && result.Relevance != 1) {
+			t.Errorf("Expected relevance in (0, 1] with 1 for the closest post, got %v for %s", result.Relevance, result.ID)
+		}
+	}
+
+	// Restricting to a subreddit with too few matches defers to Reddit
+	results, err = idx.Search(ctx, "best local model for coding", []string{"r/buildapc"}, 10)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if results != nil {
+		t.Errorf("Expected no results below MinResults, got %d", len(results))
+	}
+
+	// Unrelated queries are not answered from the index
+	results, _ = idx.Search(ctx, "sourdough starter hydration", nil, 10)
+	if results != nil {
+		t.Errorf("Expected no results for an unrelated query, got %d", len(results))
+	}
+}
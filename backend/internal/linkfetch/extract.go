@@ -0,0 +1,168 @@
+// File: backend/internal/linkfetch/extract.go
+
+package linkfetch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// minParagraphChars filters out captions, bylines and button labels
+const minParagraphChars = 60
+
+// skippedElements never contain article text
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Figcaption: true,
+}
+
+// page collects what extract needs while walking a document
+type page struct {
+	title, ogTitle, siteName, description string
+	paragraphs, articleParagraphs         []string
+}
+
+// extract builds a preview from an HTML page: its title, site name and a
+// summary made of the description and leading paragraphs, preferring those
+// inside <article>, capped at maxChars
+func extract(body []byte, maxChars int) (*models.LinkPreview, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing page: %w", err)
+	}
+
+	var p page
+	p.walk(doc, false)
+
+	paragraphs := p.articleParagraphs
+	if len(paragraphs) == 0 {
+		paragraphs = p.paragraphs
+	}
+
+	var parts []string
+	if p.description != "" {
+		parts = append(parts, p.description)
+	}
+	for _, paragraph := range paragraphs {
+		// Descriptions are often the first paragraph verbatim
+		if paragraph != p.description {
+			parts = append(parts, paragraph)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("no readable text on page")
+	}
+
+	title := p.ogTitle
+	if title == "" {
+		title = p.title
+	}
+	return &models.LinkPreview{
+		Title:    title,
+		SiteName: p.siteName,
+		Summary:  truncate(strings.Join(parts, "\n\n"), maxChars),
+	}, nil
+}
+
+// walk visits n and its descendants
+func (p *page) walk(n *html.Node, inArticle bool) {
+	if n.Type == html.ElementNode {
+		switch {
+		case skippedElements[n.DataAtom]:
+			return
+		case n.DataAtom == atom.Title && p.title == "":
+			p.title = collapse(text(n))
+			return
+		case n.DataAtom == atom.Meta:
+			p.meta(n)
+			return
+		case n.DataAtom == atom.Article || n.DataAtom == atom.Main:
+			inArticle = true
+		case n.DataAtom == atom.P:
+			if paragraph := collapse(text(n)); utf8.RuneCountInString(paragraph) >= minParagraphChars {
+				p.paragraphs = append(p.paragraphs, paragraph)
+				if inArticle {
+					p.articleParagraphs = append(p.articleParagraphs, paragraph)
+				}
+			}
+			return
+		}
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		p.walk(child, inArticle)
+	}
+}
+
+// meta records the title, site name and description from meta tags
+func (p *page) meta(n *html.Node) {
+	var key, content string
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "name", "property":
+			key = strings.ToLower(attr.Val)
+		case "content":
+			content = collapse(attr.Val)
+		}
+	}
+
+	switch key {
+	case "og:title":
+		p.ogTitle = content
+	case "og:site_name":
+		p.siteName = content
+	case "description", "og:description":
+		if p.description == "" {
+			p.description = content
+		}
+	}
+}
+
+// text returns the text inside n, skipping non-content elements
+func text(n *html.Node) string {
+	var b strings.Builder
+	var visit func(*html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+			return
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Br {
+			b.WriteString(" ")
+		}
+	}
+	visit(n)
+	return b.String()
+}
+
+// collapse trims text and reduces runs of whitespace to single spaces
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most maxChars runes, ending at a word boundary
+func truncate(s string, maxChars int) string {
+	if utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+	runes := []rune(s)[:maxChars]
+	cut := string(runes)
+	if i := strings.LastIndexAny(cut, " \n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "..."
+}
@@ -0,0 +1,224 @@
+// File: backend/internal/linkfetch/linkfetch.go
+
+// Package linkfetch fetches the pages that link posts point to and extracts
+// a short readable summary, so answers can draw on articles a discussion is
+// about rather than only the comments.
+package linkfetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// userAgent identifies the fetcher to the sites it reads
+const userAgent = "Mozilla/5.0 (compatible; Subplexity/1.0; +https://subplexity.vercel.app)"
+
+// previewTTL is how long a page's preview, or the failure to get one, is reused
+const previewTTL = time.Hour
+
+// ErrBlockedAddress is returned for pages on loopback, private or otherwise
+// internal addresses, which must never be fetched on a user's behalf
+var ErrBlockedAddress = errors.New("address is not publicly routable")
+
+// Fetcher fetches linked pages and extracts previews
+type Fetcher struct {
+	cfg    config.LinkFetchConfig
+	client *http.Client
+	cache  *cache.Cache[*models.LinkPreview]
+}
+
+// New creates a fetcher from configuration
+func New(cfg config.LinkFetchConfig) *Fetcher {
+	return newFetcher(cfg, false)
+}
+
+// newFetcher creates a fetcher; allowPrivate lifts the address check for tests
+func newFetcher(cfg config.LinkFetchConfig, allowPrivate bool) *Fetcher {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !allowPrivate {
+		// Checked after DNS resolution, for every connection including redirects
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		}
+	}
+
+	return &Fetcher{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			Transport: &http.Transport{
+				Proxy:                 nil, // A proxy would hide the real destination from the check
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   cfg.Timeout,
+				ResponseHeaderTimeout: cfg.Timeout,
+				MaxIdleConns:          20,
+				IdleConnTimeout:       30 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return checkScheme(req.URL)
+			},
+		},
+		cache: cache.NewCache[*models.LinkPreview](cache.Config{
+			MaxItems:   2000,
+			DefaultTTL: previewTTL,
+		}),
+	}
+}
+
+// Enrich adds previews to the top link posts that have no text of their own,
+// fetching up to MaxLinks pages concurrently. It returns a copy of results;
+// the input, which may be shared with a cache, is not modified. Pages that
+// can't be fetched are skipped.
+func (f *Fetcher) Enrich(ctx context.Context, results []models.SearchResult) []models.SearchResult {
+	enriched := make([]models.SearchResult, len(results))
+	copy(enriched, results)
+
+	var wg sync.WaitGroup
+	fetched := 0
+	for i := range enriched {
+		if fetched >= f.cfg.MaxLinks {
+			break
+		}
+		result := &enriched[i]
+		// Reddit-hosted images, videos and galleries have no article to read
+		if result.Type != "post" || result.ExternalURL == "" || isRedditHosted(result.ExternalURL) ||
+			strings.TrimSpace(result.Content) != "" {
+			continue
+		}
+
+		fetched++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			preview, err := f.Fetch(ctx, result.ExternalURL)
+			if err != nil {
+				log.Printf("Skipping linked page %s: %v", result.ExternalURL, err)
+				return
+			}
+			result.LinkPreview = preview
+		}()
+	}
+	wg.Wait()
+
+	return enriched
+}
+
+// Fetch returns a preview of the page at rawURL. Results, including
+// failures, are cached for an hour.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	if preview, found := f.cache.Get(rawURL); found {
+		if preview == nil {
+			return nil, errors.New("page failed recently")
+		}
+		return preview, nil
+	}
+
+	preview, err := f.fetch(ctx, rawURL)
+	// Don't remember failures caused by the caller giving up
+	if err == nil || ctx.Err() == nil {
+		f.cache.Set(rawURL, preview)
+	}
+	return preview, err
+}
+
+// fetch downloads and extracts a page
+func (f *Fetcher) fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := checkScheme(pageURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("page returned status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("not an HTML page (%s)", mediaType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(f.cfg.MaxPageKB)<<10))
+	if err != nil {
+		return nil, fmt.Errorf("error reading page: %w", err)
+	}
+
+	preview, err := extract(body, f.cfg.SummaryChars)
+	if err != nil {
+		return nil, err
+	}
+	if preview.SiteName == "" {
+		preview.SiteName = strings.TrimPrefix(resp.Request.URL.Hostname(), "www.")
+	}
+	return preview, nil
+}
+
+// isRedditHosted reports whether a URL is on one of Reddit's own domains
+func isRedditHosted(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "reddit.com" || strings.HasSuffix(host, ".reddit.com") ||
+		host == "redd.it" || strings.HasSuffix(host, ".redd.it")
+}
+
+// checkScheme allows only web URLs
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported URL scheme '%s'", u.Scheme)
+	}
+	return nil
+}
+
+// checkAddress rejects connections to addresses that aren't publicly routable
+func checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() ||
+		ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("%s: %w", host, ErrBlockedAddress)
+	}
+	// Carrier-grade NAT (100.64.0.0/10) is shared address space, not public
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return fmt.Errorf("%s: %w", host, ErrBlockedAddress)
+	}
+	return nil
+}
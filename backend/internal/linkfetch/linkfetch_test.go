@@ -0,0 +1,128 @@
+// File: backend/internal/linkfetch/linkfetch_test.go
+
+package linkfetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html><head>
+<title>Fallback title | Example News</title>
+<meta property="og:title" content="Rust 2.0 announced">
+<meta property="og:site_name" content="Example News">
+<meta name="description" content="The Rust team announced a new edition today.">
+<script>var tracking = "<p>not text</p>";</script>
+</head><body>
+<nav><p>Home | World | Technology | Science | Sports | Opinion | Subscribe now</p></nav>
+<p>This sidebar paragraph is outside the article and long enough to count.</p>
+<article>
+<p>The Rust team announced a new edition today.</p>
+<p>The release focuses on async closures, a stabilized   trait solver and faster compile times for large workspaces.</p>
+<p>Short caption</p>
+<aside><p>Related: ten other stories you might like to read while you are here today.</p></aside>
+</article>
+<footer><p>Copyright Example News. All rights reserved. Terms of use and privacy policy.</p></footer>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	preview, err := extract([]byte(articlePage), 1000)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+
+	if preview.Title != "Rust 2.0 announced" || preview.SiteName != "Example News" {
+		t.Errorf("title/site = %q/%q", preview.Title, preview.SiteName)
+	}
+	want := "The Rust team announced a new edition today.\n\n" +
+		"The release focuses on async closures, a stabilized trait solver and faster compile times for large workspaces."
+	if preview.Summary != want {
+		t.Errorf("summary = %q, want %q", preview.Summary, want)
+	}
+
+	short, _ := extract([]byte(articlePage), 60)
+	if len([]rune(short.Summary)) > 63 || !strings.HasSuffix(short.Summary, "...") {
+		t.Errorf("truncated summary = %q", short.Summary)
+	}
+
+	if _, err := extract([]byte("<html><body><p>tiny</p></body></html>"), 1000); err == nil {
+		t.Error("expected an error for a page without readable text")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/image.png" {
+			w.Header().Set("Content-Type", "image/png")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(articlePage))
+	}))
+	defer server.Close()
+
+	cfg := config.Default().LinkFetching
+	cfg.MaxLinks = 2
+	fetcher := newFetcher(cfg, true)
+
+	results := []models.SearchResult{
+		{ID: "i", Type: "post", ExternalURL: "https://i.redd.it/abc123.jpg"},
+		{ID: "a", Type: "post", ExternalURL: server.URL + "/article"},
+		{ID: "b", Type: "post", ExternalURL: server.URL + "/image.png"},
+		{ID: "c", Type: "post", ExternalURL: server.URL + "/other", Content: "Self text"},
+		{ID: "d", Type: "post", ExternalURL: server.URL + "/beyond-limit"},
+	}
+	enriched := fetcher.Enrich(context.Background(), results)
+
+	if enriched[1].LinkPreview == nil || enriched[1].LinkPreview.Title != "Rust 2.0 announced" {
+		t.Errorf("article preview = %+v", enriched[1].LinkPreview)
+	}
+	for _, i := range []int{0, 2, 3, 4} {
+		if enriched[i].LinkPreview != nil {
+			t.Errorf("result %s should not have a preview", enriched[i].ID)
+		}
+	}
+	if results[1].LinkPreview != nil {
+		t.Error("Enrich modified its input")
+	}
+
+	// Both outcomes are cached
+	fetcher.Enrich(context.Background(), results)
+	if requests != 2 {
+		t.Errorf("server saw %d requests, want 2", requests)
+	}
+}
+
+func TestFetchRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	fetcher := New(config.Default().LinkFetching)
+	if _, err := fetcher.Fetch(context.Background(), server.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("loopback fetch error = %v, want ErrBlockedAddress", err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), "file:///etc/passwd"); err == nil {
+		t.Error("expected an error for a file URL")
+	}
+
+	for _, address := range []string{"10.0.0.1:80", "192.168.1.1:443", "169.254.169.254:80", "[::1]:80", "100.64.0.1:80"} {
+		if err := checkAddress(address); err == nil {
+			t.Errorf("%s was allowed", address)
+		}
+	}
+	if err := checkAddress("93.184.216.34:443"); err != nil {
+		t.Errorf("public address refused: %v", err)
+	}
+}
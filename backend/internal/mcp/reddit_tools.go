@@ -0,0 +1,220 @@
+// File: backend/internal/mcp/reddit_tools.go
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+)
+
+const (
+	defaultSearchLimit   = 10
+	maxSearchLimit       = 50
+	defaultThreadComment = 20
+	maxThreadComments    = 50
+	// maxContentChars keeps tool results within a client's context window
+	maxContentChars = 2000
+)
+
+var (
+	// postIDPattern matches Reddit base-36 post IDs
+	postIDPattern = regexp.MustCompile(`^[a-z0-9]{1,12}$`)
+	// threadURLPattern finds the post ID in a Reddit thread URL
+	threadURLPattern = regexp.MustCompile(`/comments/([a-z0-9]{1,12})`)
+)
+
+// Result is a search result or comment as returned by the tools
+type Result struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Title        string `json:"title,omitempty"`
+	Subreddit    string `json:"subreddit"`
+	Author       string `json:"author,omitempty"`
+	Content      string `json:"content,omitempty"`
+	URL          string `json:"url"`
+	ExternalURL  string `json:"externalUrl,omitempty"`
+	Score        int    `json:"score"`
+	CommentCount int    `json:"commentCount,omitempty"`
+	CreatedUTC   int64  `json:"createdUtc"`
+}
+
+// RedditTools returns the search_reddit and summarize_thread tools
+func RedditTools(redditService *services.RedditService) []Tool {
+	return []Tool{
+		{
+			Name: "search_reddit",
+			Description: "Search Reddit posts, comments and communities. Returns the most relevant results with " +
+				"their scores and links, for answering questions with what Reddit users say.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query": map[string]interface{}{"type": "string", "description": "What to search for"},
+					"subreddits": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only search these communities, e.g. [\"golang\"]",
+					},
+					"mode": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"All", "Posts", "Comments", "Communities"},
+						"description": "Kind of results; defaults to All",
+					},
+					"limit": map[string]interface{}{
+						"type":    "integer",
+						"minimum": 1,
+						"maximum": maxSearchLimit,
+						"default": defaultSearchLimit,
+					},
+				},
+				"required": []string{"query"},
+			},
+			Call: func(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+				return searchReddit(ctx, redditService, arguments)
+			},
+		},
+		{
+			Name: "summarize_thread",
+			Description: "Fetch a Reddit thread for summarizing: the post and its top comments by score. " +
+				"Accepts a post ID or a thread URL.",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"post": map[string]interface{}{
+						"type":        "string",
+						"description": "Post ID (e.g. 1abc23) or thread URL",
+					},
+					"comments": map[string]interface{}{
+						"type":        "integer",
+						"minimum":     1,
+						"maximum":     maxThreadComments,
+						"default":     defaultThreadComment,
+						"description": "How many top comments to include",
+					},
+				},
+				"required": []string{"post"},
+			},
+			Call: func(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+				return summarizeThread(ctx, redditService, arguments)
+			},
+		},
+	}
+}
+
+func searchReddit(ctx context.Context, redditService *services.RedditService, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Query      string   `json:"query"`
+		Subreddits []string `json:"subreddits"`
+		Mode       string   `json:"mode"`
+		Limit      int      `json:"limit"`
+	}
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+	switch args.Mode {
+	case "":
+		args.Mode = "All"
+	case "All", "Posts", "Comments", "Communities":
+	default:
+		return nil, fmt.Errorf("mode must be All, Posts, Comments or Communities, got %q", args.Mode)
+	}
+	if args.Limit <= 0 {
+		args.Limit = defaultSearchLimit
+	}
+	if args.Limit > maxSearchLimit {
+		args.Limit = maxSearchLimit
+	}
+
+	results, err := redditService.SearchRedditWithOptions(ctx, args.Query, args.Mode, args.Limit,
+		services.SearchOptions{Subreddits: args.Subreddits})
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		Query   string   `json:"query"`
+		Results []Result `json:"results"`
+	}{args.Query, toResults(results)}, nil
+}
+
+func summarizeThread(ctx context.Context, redditService *services.RedditService, arguments json.RawMessage) (interface{}, error) {
+	var args struct {
+		Post     string `json:"post"`
+		Comments int    `json:"comments"`
+	}
+	if err := json.Unmarshal(arguments, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	postID, err := parsePostID(args.Post)
+	if err != nil {
+		return nil, err
+	}
+	if args.Comments <= 0 {
+		args.Comments = defaultThreadComment
+	}
+	if args.Comments > maxThreadComments {
+		args.Comments = maxThreadComments
+	}
+
+	thread, err := redditService.GetComments(ctx, postID, services.CommentOptions{
+		Sort:  "top",
+		Limit: 100,
+		Depth: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := toResults(services.ThreadResults(thread, args.Comments))
+	return struct {
+		Post     Result   `json:"post"`
+		Comments []Result `json:"comments"`
+	}{results[0], results[1:]}, nil
+}
+
+// parsePostID accepts a post ID, with or without its t3_ prefix, or a
+// thread URL
+func parsePostID(post string) (string, error) {
+	post = strings.ToLower(strings.TrimSpace(post))
+	if match := threadURLPattern.FindStringSubmatch(post); match != nil {
+		return match[1], nil
+	}
+	post = strings.TrimPrefix(post, "t3_")
+	if !postIDPattern.MatchString(post) {
+		return "", errors.New("post must be a Reddit post ID or thread URL")
+	}
+	return post, nil
+}
+
+func toResults(results []models.SearchResult) []Result {
+	out := make([]Result, 0, len(results))
+	for _, result := range results {
+		content := result.Content
+		if runes := []rune(content); len(runes) > maxContentChars {
+			content = string(runes[:maxContentChars]) + "..."
+		}
+		out = append(out, Result{
+			ID:           result.ID,
+			Type:         result.Type,
+			Title:        result.Title,
+			Subreddit:    result.Subreddit,
+			Author:       result.Author,
+			Content:      content,
+			URL:          result.Permalink,
+			ExternalURL:  result.ExternalURL,
+			Score:        result.Score,
+			CommentCount: result.CommentCount,
+			CreatedUTC:   result.CreatedUTC,
+		})
+	}
+	return out
+}
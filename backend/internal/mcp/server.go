@@ -0,0 +1,293 @@
+// File: backend/internal/mcp/server.go
+
+// Package mcp serves tools over the Model Context Protocol, so desktop AI
+// clients such as Claude Desktop can call them. Messages are JSON-RPC 2.0,
+// one per line, over the stdio transport.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// protocolVersions are the MCP revisions this server speaks, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// maxMessageBytes bounds a single incoming message
+const maxMessageBytes = 4 << 20
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function clients can call
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the arguments object
+	InputSchema map[string]interface{}
+	// Call runs the tool. Its result is returned to the client as structured
+	// content and as JSON text for clients that only read text. Errors are
+	// reported to the model as a failed tool call, not a protocol error.
+	Call func(ctx context.Context, arguments json.RawMessage) (interface{}, error)
+}
+
+// Server answers MCP requests for a fixed set of tools
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+	byName  map[string]Tool
+
+	writeMu sync.Mutex
+	out     io.Writer
+
+	callsMu sync.Mutex
+	calls   map[string]context.CancelFunc // In-flight tool calls by request ID
+}
+
+// NewServer creates a server that identifies itself as name and version
+func NewServer(name, version string, tools []Tool) *Server {
+	byName := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	return &Server{
+		name:    name,
+		version: version,
+		tools:   tools,
+		byName:  byName,
+		calls:   make(map[string]context.CancelFunc),
+	}
+}
+
+// message is an incoming request or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted, then waits for in-flight tool calls. Tool calls run
+// concurrently; other requests are answered in order.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	s.out = out
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			s.writeError(json.RawMessage("null"), codeParseError, "invalid JSON")
+			continue
+		}
+		if msg.JSONRPC != "2.0" || msg.Method == "" {
+			if msg.ID != nil {
+				s.writeError(msg.ID, codeInvalidRequest, "not a JSON-RPC 2.0 request")
+			}
+			continue
+		}
+
+		if msg.Method == "tools/call" && msg.ID != nil {
+			callCtx, cancelCall := context.WithCancel(ctx)
+			s.trackCall(msg.ID, cancelCall)
+			wg.Add(1)
+			go func(msg message) {
+				defer wg.Done()
+				defer s.untrackCall(msg.ID)
+				s.handle(callCtx, msg)
+			}(msg)
+			continue
+		}
+		s.handle(ctx, msg)
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading MCP messages: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message. Notifications get no response.
+func (s *Server) handle(ctx context.Context, msg message) {
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		s.writeResult(msg.ID, map[string]interface{}{
+			"protocolVersion": negotiateVersion(params.ProtocolVersion),
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		})
+	case "ping":
+		s.writeResult(msg.ID, map[string]interface{}{})
+	case "tools/list":
+		s.writeResult(msg.ID, map[string]interface{}{"tools": s.toolList()})
+	case "tools/call":
+		s.callTool(ctx, msg)
+	case "notifications/cancelled":
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if json.Unmarshal(msg.Params, &params) == nil {
+			s.cancelCall(params.RequestID)
+		}
+	default:
+		// Other notifications, such as notifications/initialized, need no
+		// action
+		if msg.ID != nil {
+			s.writeError(msg.ID, codeMethodNotFound, "method not found: "+msg.Method)
+		}
+	}
+}
+
+func (s *Server) toolList() []map[string]interface{} {
+	tools := make([]map[string]interface{}, 0, len(s.tools))
+	for _, tool := range s.tools {
+		tools = append(tools, map[string]interface{}{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"inputSchema": tool.InputSchema,
+		})
+	}
+	return tools
+}
+
+func (s *Server) callTool(ctx context.Context, msg message) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		s.writeError(msg.ID, codeInvalidParams, "invalid tools/call params")
+		return
+	}
+	tool, ok := s.byName[params.Name]
+	if !ok {
+		s.writeError(msg.ID, codeInvalidParams, "unknown tool: "+params.Name)
+		return
+	}
+	if len(params.Arguments) == 0 {
+		params.Arguments = json.RawMessage("{}")
+	}
+
+	result, err := tool.Call(ctx, params.Arguments)
+	if ctx.Err() != nil {
+		// Cancelled calls get no response
+		return
+	}
+	if err != nil {
+		log.Printf("MCP tool %s failed: %v", tool.Name, err)
+		s.writeResult(msg.ID, map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		})
+		return
+	}
+
+	text, err := json.Marshal(result)
+	if err != nil {
+		s.writeResult(msg.ID, map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": "failed to encode result: " + err.Error()}},
+			"isError": true,
+		})
+		return
+	}
+	s.writeResult(msg.ID, map[string]interface{}{
+		"content":           []map[string]string{{"type": "text", "text": string(text)}},
+		"structuredContent": result,
+	})
+}
+
+// negotiateVersion answers the client's protocol version when it is
+// supported and the newest supported version otherwise
+func negotiateVersion(requested string) string {
+	for _, version := range protocolVersions {
+		if version == requested {
+			return version
+		}
+	}
+	return protocolVersions[0]
+}
+
+func (s *Server) trackCall(id json.RawMessage, cancel context.CancelFunc) {
+	s.callsMu.Lock()
+	defer s.callsMu.Unlock()
+	s.calls[string(id)] = cancel
+}
+
+func (s *Server) untrackCall(id json.RawMessage) {
+	s.callsMu.Lock()
+	defer s.callsMu.Unlock()
+	if cancel, ok := s.calls[string(id)]; ok {
+		cancel()
+		delete(s.calls, string(id))
+	}
+}
+
+func (s *Server) cancelCall(id json.RawMessage) {
+	s.callsMu.Lock()
+	defer s.callsMu.Unlock()
+	if cancel, ok := s.calls[string(id)]; ok {
+		cancel()
+	}
+}
+
+func (s *Server) writeResult(id json.RawMessage, result interface{}) {
+	s.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) writeError(id json.RawMessage, code int, text string) {
+	s.write(response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: text}})
+}
+
+// write sends one message per line; stdout carries nothing else
+func (s *Server) write(resp response) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("Failed to encode MCP response: %v", err)
+		return
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := s.out.Write(append(data, '\n')); err != nil {
+		log.Printf("Failed to write MCP response: %v", err)
+	}
+}
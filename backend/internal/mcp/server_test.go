@@ -0,0 +1,126 @@
+// File: backend/internal/mcp/server_test.go
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// serve runs a session of requests and returns the responses by ID
+func serve(t *testing.T, tools []Tool, requests ...string) map[string]map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	server := NewServer("test", "0.1.0", tools)
+	if err := server.Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	responses := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		id, _ := json.Marshal(resp["id"])
+		responses[string(id)] = resp
+	}
+	return responses
+}
+
+func TestServer(t *testing.T) {
+	tools := []Tool{
+		{
+			Name:        "echo",
+			Description: "Echoes its text",
+			InputSchema: map[string]interface{}{"type": "object"},
+			Call: func(ctx context.Context, arguments json.RawMessage) (interface{}, error) {
+				var args struct {
+					Text string `json:"text"`
+				}
+				if err := json.Unmarshal(arguments, &args); err != nil {
+					return nil, err
+				}
+				if args.Text == "" {
+					return nil, errors.New("text is required")
+				}
+				return map[string]string{"text": args.Text}, nil
+			},
+		},
+	}
+
+	responses := serve(t, tools,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"missing"}}`,
+		`{"jsonrpc":"2.0","id":"six","method":"resources/list"}`,
+		`not json`,
+	)
+	if len(responses) != 7 {
+		t.Fatalf("got %d responses, want 7: %v", len(responses), responses)
+	}
+
+	initResult := responses["1"]["result"].(map[string]interface{})
+	if initResult["protocolVersion"] != "2024-11-05" {
+		t.Errorf("protocolVersion = %v, want the client's 2024-11-05", initResult["protocolVersion"])
+	}
+
+	listed := responses["2"]["result"].(map[string]interface{})["tools"].([]interface{})
+	if len(listed) != 1 || listed[0].(map[string]interface{})["name"] != "echo" {
+		t.Errorf("tools/list = %v", listed)
+	}
+
+	call := responses["3"]["result"].(map[string]interface{})
+	if call["isError"] != nil {
+		t.Errorf("successful call marked as error: %v", call)
+	}
+	if structured := call["structuredContent"].(map[string]interface{}); structured["text"] != "hi" {
+		t.Errorf("structuredContent = %v", structured)
+	}
+	text := call["content"].([]interface{})[0].(map[string]interface{})["text"]
+	if text != `{"text":"hi"}` {
+		t.Errorf("text content = %v", text)
+	}
+
+	failed := responses["4"]["result"].(map[string]interface{})
+	if failed["isError"] != true {
+		t.Errorf("failed call not marked as error: %v", failed)
+	}
+
+	if code := responses["5"]["error"].(map[string]interface{})["code"]; code != float64(codeInvalidParams) {
+		t.Errorf("unknown tool error code = %v", code)
+	}
+	if code := responses[`"six"`]["error"].(map[string]interface{})["code"]; code != float64(codeMethodNotFound) {
+		t.Errorf("unknown method error code = %v", code)
+	}
+	if code := responses["null"]["error"].(map[string]interface{})["code"]; code != float64(codeParseError) {
+		t.Errorf("invalid JSON error code = %v", code)
+	}
+}
+
+func TestParsePostID(t *testing.T) {
+	tests := map[string]string{
+		"1abc23":    "1abc23",
+		"t3_1abc23": "1abc23",
+		"https://www.reddit.com/r/golang/comments/1abc23/some_title/": "1abc23",
+	}
+	for input, want := range tests {
+		got, err := parsePostID(input)
+		if err != nil || got != want {
+			t.Errorf("parsePostID(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := parsePostID("not a post"); err == nil {
+		t.Error("parsePostID accepted an invalid post")
+	}
+}
@@ -0,0 +1,37 @@
+// File: backend/internal/models/account.go
+
+package models
+
+// Account is a registered user. Accounts created by signing in with Reddit
+// have no email; they have a RedditUsername instead.
+type Account struct {
+	ID    string `json:"id"`
+	Email string `json:"email,omitempty"`
+	// RedditUsername is the Reddit account linked by signing in with Reddit
+	RedditUsername string `json:"redditUsername,omitempty"`
+	CreatedAt      int64  `json:"createdAt"`
+}
+
+// Credentials are the body of register and login requests
+type Credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Session is a signed-in account and the bearer token that authenticates it
+type Session struct {
+	Token     string  `json:"token"`
+	ExpiresAt int64   `json:"expiresAt"`
+	Account   Account `json:"account"`
+}
+
+// RedditLink is a Reddit account linked to an account by signing in with
+// Reddit, with its OAuth tokens encrypted
+type RedditLink struct {
+	AccountID string
+	Username  string
+	// Tokens is the sealed token, see auth.Sealer
+	Tokens    string
+	LinkedAt  int64
+	UpdatedAt int64
+}
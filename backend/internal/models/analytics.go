@@ -0,0 +1,38 @@
+// File: backend/internal/models/analytics.go
+
+package models
+
+// Analytics summarizes search traffic across all clients over a range of
+// days, for operators
+type Analytics struct {
+	Since         int64            `json:"since"` // Start of the first day, UTC
+	Until         int64            `json:"until"`
+	Searches      int              `json:"searches"`
+	Latency       LatencySummary   `json:"latency"`
+	CacheHitRatio *float64         `json:"cacheHitRatio,omitempty"` // Unset when no searches tracked cache use
+	Days          []DailyAnalytics `json:"days"`                    // Oldest first, including days without searches
+	Models        []UsageCount     `json:"models"`
+	TopQueries    []UsageCount     `json:"topQueries"`
+	TopSubreddits []UsageCount     `json:"topSubreddits"`
+}
+
+// DailyAnalytics summarizes one UTC day of search traffic
+type DailyAnalytics struct {
+	Date          string         `json:"date"` // YYYY-MM-DD
+	Searches      int            `json:"searches"`
+	Latency       LatencySummary `json:"latency"`
+	CacheHitRatio *float64       `json:"cacheHitRatio,omitempty"`
+}
+
+// LatencySummary holds percentiles of search latency, in seconds
+type LatencySummary struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// UsageCount is how many searches used a model, query or subreddit
+type UsageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,17 @@
+// File: backend/internal/models/annotation.go
+
+package models
+
+// Annotation is a private note a client attached to one result of a search
+type Annotation struct {
+	SnapshotID string `json:"snapshotId"`
+	ResultID   string `json:"resultId"`
+	Note       string `json:"note"`
+	CreatedAt  int64  `json:"createdAt"`
+	UpdatedAt  int64  `json:"updatedAt"`
+}
+
+// AnnotationRequest sets the note on a result
+type AnnotationRequest struct {
+	Note string `json:"note"`
+}
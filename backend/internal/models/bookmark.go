@@ -0,0 +1,68 @@
+// File: backend/internal/models/bookmark.go
+
+package models
+
+// Bookmark kinds
+const (
+	BookmarkKindResult = "result" // One result of a search
+	BookmarkKindAnswer = "answer" // A search's whole answer
+)
+
+// Bookmark is a result or answer a client kept. Its content is copied from
+// the search snapshot, so it outlives the snapshot.
+type Bookmark struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// SnapshotID and Query identify the search it was bookmarked from
+	SnapshotID string `json:"snapshotId"`
+	Query      string `json:"query"`
+	// Result is set for result bookmarks
+	Result *SearchResult `json:"result,omitempty"`
+	// Answer and Citations are set for answer bookmarks
+	Answer    string     `json:"answer,omitempty"`
+	Citations []Citation `json:"citations,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	Tags      []string   `json:"tags"`
+	CreatedAt int64      `json:"createdAt"`
+	UpdatedAt int64      `json:"updatedAt"`
+}
+
+// BookmarkRequest is the body of a request to bookmark a result or answer
+type BookmarkRequest struct {
+	// SnapshotID is the ID of the search response
+	SnapshotID string `json:"snapshotId"`
+	// ResultID bookmarks one of the search's results; empty bookmarks the
+	// answer
+	ResultID string   `json:"resultId,omitempty"`
+	Note     string   `json:"note,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// BookmarkUpdate replaces a bookmark's note and tags
+type BookmarkUpdate struct {
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+// BookmarkFilter narrows a bookmark listing. Empty fields match everything.
+type BookmarkFilter struct {
+	Kind string
+	// Tags must all be on a bookmark
+	Tags []string
+	// Text is matched against the bookmark's content, note and tags
+	Text string
+}
+
+// BookmarkPage is one page of a client's bookmarks
+type BookmarkPage struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+	Total     int        `json:"total"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+}
+
+// TagCount is a bookmark tag and how many bookmarks have it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
@@ -0,0 +1,47 @@
+// File: backend/internal/models/catalog.go
+
+package models
+
+// Model modes reported in ModelInfo.Mode
+const (
+	ModelModeLive = "live" // Provider credentials are configured
+	ModelModeMock = "mock" // No credentials; canned responses are returned
+)
+
+// Model health states reported in ModelHealth.Status
+const (
+	ModelHealthUnknown  = "unknown"  // No calls since startup
+	ModelHealthOK       = "ok"       // The last call succeeded
+	ModelHealthDegraded = "degraded" // The last call failed
+	ModelHealthDown     = "down"     // Several consecutive calls failed
+)
+
+// ModelInfo describes a model clients can select
+type ModelInfo struct {
+	Name        string      `json:"name"`        // Value to send as SearchRequest.ModelName
+	DisplayName string      `json:"displayName"` // Label for model pickers
+	Provider    string      `json:"provider"`
+	ModelID     string      `json:"modelId"` // Provider's model identifier
+	Mode        string      `json:"mode"`    // ModelModeLive or ModelModeMock
+	Default     bool        `json:"default"`
+	MaxTokens   int         `json:"maxTokens"`  // Maximum answer length
+	TokenLimit  int         `json:"tokenLimit"` // Context window
+	JSONMode    bool        `json:"jsonMode"`   // Returns typed reasoning steps
+	Vision      bool        `json:"vision"`     // Accepts images from image posts
+	Health      ModelHealth `json:"health"`
+}
+
+// ModelHealth summarizes recent calls to a model
+type ModelHealth struct {
+	Status              string `json:"status"`
+	LastSuccess         int64  `json:"lastSuccess,omitempty"` // Unix timestamp
+	LastFailure         int64  `json:"lastFailure,omitempty"` // Unix timestamp
+	LastError           string `json:"lastError,omitempty"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+}
+
+// ModelCatalog is the response of the models endpoint
+type ModelCatalog struct {
+	Models       []ModelInfo `json:"models"`
+	DefaultModel string      `json:"defaultModel"`
+}
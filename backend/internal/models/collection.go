@@ -0,0 +1,85 @@
+// File: backend/internal/models/collection.go
+
+package models
+
+// Collection groups searches and selected results a client is researching,
+// with the latest report synthesized across them
+type Collection struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Searches are whole searches; all their results are sources
+	Searches []CollectionSearch `json:"searches"`
+	// Results are single results picked from searches, copied so they
+	// outlive them
+	Results   []CollectionResult `json:"results"`
+	Report    *CollectionReport  `json:"report,omitempty"`
+	CreatedAt int64              `json:"createdAt"`
+	UpdatedAt int64              `json:"updatedAt"`
+}
+
+// CollectionSearch is a search added to a collection
+type CollectionSearch struct {
+	SnapshotID string `json:"snapshotId"`
+	Query      string `json:"query"`
+	AddedAt    int64  `json:"addedAt"`
+}
+
+// CollectionResult is a single result added to a collection
+type CollectionResult struct {
+	SnapshotID string       `json:"snapshotId"`
+	Query      string       `json:"query"`
+	Result     SearchResult `json:"result"`
+	AddedAt    int64        `json:"addedAt"`
+}
+
+// CollectionReport is an AI report combining a collection's sources
+type CollectionReport struct {
+	Prompt    string     `json:"prompt"`
+	Answer    string     `json:"answer"`
+	Citations []Citation `json:"citations,omitempty"`
+	// Sources are the results the report was written from; citations
+	// refer to them
+	Sources     []SearchResult `json:"sources"`
+	Warnings    []string       `json:"warnings,omitempty"`
+	ModelName   string         `json:"modelName"`
+	GeneratedAt int64          `json:"generatedAt"` // Unix timestamp
+}
+
+// CollectionSummary describes a collection in listings, without its items
+type CollectionSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Searches    int    `json:"searches"`
+	Results     int    `json:"results"`
+	// ReportedAt is when the latest report was generated, 0 if none was
+	ReportedAt int64 `json:"reportedAt,omitempty"`
+	CreatedAt  int64 `json:"createdAt"`
+	UpdatedAt  int64 `json:"updatedAt"`
+}
+
+// CollectionRequest creates a collection or renames one
+type CollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// CollectionItemsRequest adds a search, or some of its results, to a
+// collection
+type CollectionItemsRequest struct {
+	// SnapshotID is the ID of the search response
+	SnapshotID string `json:"snapshotId"`
+	// ResultIDs picks results of the search; empty adds the whole search
+	ResultIDs []string `json:"resultIds,omitempty"`
+}
+
+// SynthesizeRequest asks for a report across a collection's sources
+type SynthesizeRequest struct {
+	// Prompt is the question the report answers; it defaults to a
+	// general report on the collection
+	Prompt         string `json:"prompt,omitempty"`
+	ModelName      string `json:"modelName,omitempty"`
+	AnswerFormat   string `json:"answerFormat,omitempty"` // Defaults to "essay"
+	AnswerLanguage string `json:"answerLanguage,omitempty"`
+}
@@ -0,0 +1,36 @@
+// File: backend/internal/models/comments.go
+
+package models
+
+// Comment is a Reddit comment with its nested replies
+type Comment struct {
+	ID            string        `json:"id"`
+	ParentID      string        `json:"parentId"` // Fullname of the parent: t3_ for the post, t1_ for a comment
+	Author        string        `json:"author"`
+	Body          string        `json:"body"`
+	Score         int           `json:"score"`
+	CreatedUTC    int64         `json:"createdUtc"`
+	URL           string        `json:"url"`
+	Depth         int           `json:"depth"`
+	IsSubmitter   bool          `json:"isSubmitter,omitempty"`   // Written by the post's author
+	Distinguished string        `json:"distinguished,omitempty"` // "moderator" or "admin"
+	Stickied      bool          `json:"stickied,omitempty"`
+	Replies       []Comment     `json:"replies,omitempty"`
+	More          *MoreComments `json:"more,omitempty"` // Replies Reddit didn't include
+}
+
+// MoreComments is a stub for comments that were not returned. When Children
+// is empty, the thread continues past the depth limit and must be opened by
+// its parent's permalink.
+type MoreComments struct {
+	Count    int      `json:"count"`
+	ParentID string   `json:"parentId"`
+	Children []string `json:"children,omitempty"` // IDs of the comments that were left out
+}
+
+// CommentThread is a post with its comment tree
+type CommentThread struct {
+	Post     SearchResult  `json:"post"`
+	Comments []Comment     `json:"comments"`
+	More     *MoreComments `json:"more,omitempty"` // Top-level comments not returned
+}
@@ -0,0 +1,38 @@
+// File: backend/internal/models/concurrency.go
+
+package models
+
+// ConcurrencySettings bounds the adaptive limit on concurrent Reddit requests
+type ConcurrencySettings struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+	// TargetLatencyMs is the response time above which the limit is lowered
+	TargetLatencyMs int64 `json:"targetLatencyMs"`
+}
+
+// ConcurrencyStatus reports the adaptive Reddit concurrency limit
+type ConcurrencyStatus struct {
+	ConcurrencySettings
+	Limit     int    `json:"limit"`     // Current limit
+	InFlight  int    `json:"inFlight"`  // Requests running now
+	Throttled uint64 `json:"throttled"` // 429 responses seen since startup
+}
+
+// RateLimitBudget is the request budget Reddit last reported for this
+// server, from its X-Ratelimit-* response headers
+type RateLimitBudget struct {
+	Remaining  float64 `json:"remaining"`  // Requests left in the current window
+	Used       int     `json:"used"`       // Requests made in the current window
+	ResetAt    int64   `json:"resetAt"`    // When the window ends
+	ObservedAt int64   `json:"observedAt"` // When Reddit reported it; 0 before any response
+}
+
+// SearchLoad reports the searches running against the server's limits, see
+// config.LimitsConfig
+type SearchLoad struct {
+	Running        int   `json:"running"`
+	Waiting        int   `json:"waiting"` // Searches queued for a free slot
+	MaxConcurrent  int   `json:"maxConcurrent"`
+	ResultBytes    int64 `json:"resultBytes"` // Estimated memory held by running searches' results
+	MaxResultBytes int64 `json:"maxResultBytes"`
+}
@@ -0,0 +1,17 @@
+// File: backend/internal/models/digest.go
+
+package models
+
+// Digest is a periodically generated AI summary of a topic's recent posts
+type Digest struct {
+	Topic       string         `json:"topic"`
+	Title       string         `json:"title"`
+	Subreddits  []string       `json:"subreddits"`
+	Answer      string         `json:"answer"`
+	Citations   []Citation     `json:"citations,omitempty"`
+	Results     []SearchResult `json:"results"`
+	Warnings    []string       `json:"warnings,omitempty"`
+	ModelName   string         `json:"modelName"`
+	GeneratedAt int64          `json:"generatedAt"` // Unix timestamp
+	NextUpdate  int64          `json:"nextUpdate"`  // Unix timestamp of the next scheduled run
+}
@@ -0,0 +1,14 @@
+// File: backend/internal/models/embed.go
+
+package models
+
+// Embed is the compact form of a saved answer used by the embeddable widget
+type Embed struct {
+	ID          string     `json:"id"`
+	Query       string     `json:"query"`
+	Answer      string     `json:"answer"`    // Markdown, with [n] citation markers
+	Citations   []Citation `json:"citations"` // The first few sources only
+	TotalCount  int        `json:"totalCount"`
+	LastUpdated int64      `json:"lastUpdated"`
+	ReportURL   string     `json:"reportUrl"` // Full report, relative to the API host
+}
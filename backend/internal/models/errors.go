@@ -0,0 +1,86 @@
+// File: backend/internal/models/errors.go
+
+package models
+
+// Error codes reported in ErrorResponse.Code. Clients should branch on the
+// code rather than the message, which is meant for people.
+const (
+	ErrorCodeInvalidRequest      = "invalid_request"       // The request failed validation; see Fields
+	ErrorCodePayloadTooLarge     = "payload_too_large"     // The request body is over the size limit
+	ErrorCodeUnauthorized        = "unauthorized"          // Missing or wrong credentials
+	ErrorCodeNotFound            = "not_found"             // The requested resource doesn't exist
+	ErrorCodeConflict            = "conflict"              // The resource already exists
+	ErrorCodeExpired             = "expired"               // The link has expired
+	ErrorCodeNotReady            = "not_ready"             // The resource exists but isn't available yet
+	ErrorCodeDisabled            = "disabled"              // The feature is turned off on this server
+	ErrorCodeTooManyRequests     = "too_many_requests"     // The client has too many requests in flight
+	ErrorCodeQuotaExceeded       = "quota_exceeded"        // The client used up a daily or monthly quota
+	ErrorCodeRedditLoginRequired = "reddit_login_required" // The search needs an account signed in with Reddit
+	ErrorCodeOverloaded          = "overloaded"            // This server is shedding load
+	ErrorCodeTimeout             = "timeout"               // The request ran out of time
+	ErrorCodeRedditRateLimited   = "reddit_rate_limited"   // Reddit is rate limiting this server
+	ErrorCodeRedditUnavailable   = "reddit_unavailable"    // Reddit is down or unreachable
+	ErrorCodeAIRateLimited       = "ai_rate_limited"       // The AI provider is rate limiting this server
+	ErrorCodeAIUnavailable       = "ai_unavailable"        // The AI provider is down or unreachable
+	ErrorCodeUpstream            = "upstream_error"        // Another service this server depends on failed
+	ErrorCodeInternal            = "internal"              // Anything else
+)
+
+// retryableCodes are the error codes a later retry of the same request may
+// not get
+var retryableCodes = map[string]bool{
+	ErrorCodeNotReady:          true,
+	ErrorCodeTooManyRequests:   true,
+	ErrorCodeOverloaded:        true,
+	ErrorCodeTimeout:           true,
+	ErrorCodeRedditRateLimited: true,
+	ErrorCodeRedditUnavailable: true,
+	ErrorCodeAIRateLimited:     true,
+	ErrorCodeAIUnavailable:     true,
+	ErrorCodeUpstream:          true,
+}
+
+// ErrorResponse is the body of every API error response
+type ErrorResponse struct {
+	// Error is a human-readable message
+	Error   string `json:"error"`
+	Code    string `json:"code"`
+	Details string `json:"details,omitempty"`
+	// Retryable is set when the same request may succeed later, usually
+	// after the Retry-After header's delay when there is one
+	Retryable bool `json:"retryable"`
+	// RequestID matches the X-Request-ID response header, for reporting
+	// problems
+	RequestID string `json:"requestId,omitempty"`
+	// Fields lists each invalid field for ErrorCodeInvalidRequest
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// NewErrorResponse returns an error body for code, with Retryable set from it
+func NewErrorResponse(code, message, details string) ErrorResponse {
+	return ErrorResponse{
+		Error:     message,
+		Code:      code,
+		Details:   details,
+		Retryable: retryableCodes[code],
+	}
+}
+
+// FieldError explains why one field of a request was rejected
+type FieldError struct {
+	// Field is the field's JSON name, e.g. "searchMode", or its path within
+	// the request, e.g. "queries[2].query"
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// RecentError is a server error response, kept for the admin dashboard
+type RecentError struct {
+	OccurredAt int64  `json:"occurredAt"`
+	Method     string `json:"method"`
+	Path       string `json:"path"` // Route pattern, e.g. /api/search/:id
+	Status     int    `json:"status"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	RequestID  string `json:"requestId,omitempty"`
+}
@@ -0,0 +1,25 @@
+// File: backend/internal/models/feedback.go
+
+package models
+
+// Feedback ratings
+const (
+	RatingUp   = "up"
+	RatingDown = "down"
+)
+
+// FeedbackRequest represents a user's judgment of an answer
+type FeedbackRequest struct {
+	SearchID string `json:"searchId"`
+	Rating   string `json:"rating"` // "up" or "down"
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Feedback is a stored judgment tied to a search snapshot
+type Feedback struct {
+	ID        string `json:"id"`
+	SearchID  string `json:"searchId"`
+	Rating    string `json:"rating"`
+	Comment   string `json:"comment,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+}
@@ -0,0 +1,26 @@
+// File: backend/internal/models/health.go
+
+package models
+
+// Health states for HealthReport and HealthCheck
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded" // Working with reduced capability
+	HealthDown     = "down"
+)
+
+// HealthReport is the response of the health endpoint
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Time   string                 `json:"time"` // RFC 3339
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// HealthCheck is the status of a single dependency
+type HealthCheck struct {
+	Status string `json:"status"`
+	// Critical dependencies being down make the whole service unavailable
+	Critical bool                   `json:"critical"`
+	Message  string                 `json:"message,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
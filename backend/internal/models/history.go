@@ -0,0 +1,23 @@
+// File: backend/internal/models/history.go
+
+package models
+
+// HistoryEntry records a single search that was run
+type HistoryEntry struct {
+	ID          string        `json:"id"`
+	Query       string        `json:"query"`
+	Params      RequestParams `json:"params"`
+	ElapsedTime float64       `json:"elapsedTime"`
+	ResultCount int           `json:"resultCount"`
+	SnapshotID  string        `json:"snapshotId,omitempty"` // ID of the stored response, for export
+	CacheHit    *bool         `json:"cacheHit,omitempty"`   // Whether the Reddit results came from cache; unset when unknown
+	CreatedAt   int64         `json:"createdAt"`
+}
+
+// HistoryPage is one page of a client's search history
+type HistoryPage struct {
+	Entries []HistoryEntry `json:"entries"`
+	Total   int            `json:"total"`
+	Limit   int            `json:"limit"`
+	Offset  int            `json:"offset"`
+}
@@ -0,0 +1,11 @@
+// File: backend/internal/models/link.go
+
+package models
+
+// LinkPreview is the readable content of a page a link post points to
+type LinkPreview struct {
+	Title    string `json:"title,omitempty"`
+	SiteName string `json:"siteName,omitempty"`
+	// Summary is the page's description and leading paragraphs, capped in length
+	Summary string `json:"summary"`
+}
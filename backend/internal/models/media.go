@@ -0,0 +1,41 @@
+// File: backend/internal/models/media.go
+
+package models
+
+// Media describes the images and video attached to a post
+type Media struct {
+	Thumbnail string `json:"thumbnail,omitempty"` // Small preview image URL
+	// Images are the post's preview image or, for galleries, every gallery
+	// item in order
+	Images []MediaImage `json:"images,omitempty"`
+	Video  *MediaVideo  `json:"video,omitempty"`
+}
+
+// MediaImage is a single image or animated image
+type MediaImage struct {
+	URL     string `json:"url"`
+	Width   int    `json:"width,omitempty"`
+	Height  int    `json:"height,omitempty"`
+	Caption string `json:"caption,omitempty"` // Gallery item caption
+	// Animated is set for GIFs; URL then points at an MP4 rendition when
+	// Reddit provides one
+	Animated bool `json:"animated,omitempty"`
+}
+
+// MediaVideo is a video hosted on Reddit or embedded from another site
+type MediaVideo struct {
+	URL      string `json:"url"`                // Playable URL (Reddit) or the embedded page
+	Provider string `json:"provider,omitempty"` // e.g. "reddit", "YouTube"
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+	Duration int    `json:"duration,omitempty"` // Seconds
+	HLSURL   string `json:"hlsUrl,omitempty"`   // Adaptive stream with audio, Reddit videos only
+}
+
+// Transcript is an excerpt of a video's captions
+type Transcript struct {
+	Language string `json:"language,omitempty"`
+	// Generated is set for automatic speech recognition captions
+	Generated bool   `json:"generated,omitempty"`
+	Excerpt   string `json:"excerpt"`
+}
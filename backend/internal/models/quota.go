@@ -0,0 +1,9 @@
+// File: backend/internal/models/quota.go
+
+package models
+
+// QuotaUsage is what a client has used of its quota in one period
+type QuotaUsage struct {
+	Requests int64 `json:"requests"`
+	Tokens   int64 `json:"tokens"` // Estimated AI tokens, prompt and response
+}
@@ -0,0 +1,38 @@
+// File: backend/internal/models/saved_search.go
+
+package models
+
+// SavedSearch is a named query with its search parameters, stored so it can
+// be re-run later
+type SavedSearch struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Query      string   `json:"query"`
+	SearchMode string   `json:"searchMode"`
+	ModelName  string   `json:"modelName"`
+	Limit      int      `json:"limit,omitempty"`
+	Subreddits []string `json:"subreddits,omitempty"`
+	CreatedAt  int64    `json:"createdAt"`
+	UpdatedAt  int64    `json:"updatedAt"`
+}
+
+// SavedSearchRequest represents a request to create or update a saved search
+type SavedSearchRequest struct {
+	Name       string   `json:"name"`
+	Query      string   `json:"query"`
+	SearchMode string   `json:"searchMode"`
+	ModelName  string   `json:"modelName"`
+	Limit      int      `json:"limit,omitempty"`
+	Subreddits []string `json:"subreddits,omitempty"`
+}
+
+// SearchRequest converts the saved search into a request for the pipeline
+func (s *SavedSearch) SearchRequest() SearchRequest {
+	return SearchRequest{
+		Query:      s.Query,
+		SearchMode: s.SearchMode,
+		ModelName:  s.ModelName,
+		Limit:      s.Limit,
+		Subreddits: s.Subreddits,
+	}
+}
@@ -1,14 +1,119 @@
 // backend/internal/models/search.go
 package models
 
+import "encoding/json"
+
 // SearchRequest represents the incoming search request
 type SearchRequest struct {
-	Query      string `json:"query"`
-	SearchMode string `json:"searchMode"`
-	ModelName  string `json:"modelName"`
-	Limit      int    `json:"limit,omitempty"`
+	Query      string   `json:"query"`
+	SearchMode string   `json:"searchMode"`
+	ModelName  string   `json:"modelName"`
+	Limit      int      `json:"limit,omitempty"`
+	Subreddits []string `json:"subreddits,omitempty"` // Restrict the search to these communities
+	// RedditScope searches the signed-in Reddit user's own content instead
+	// of all of Reddit: "subscriptions", "saved", or "multi:<name>" for one
+	// of their multireddits. Needs an account signed in with Reddit.
+	RedditScope string `json:"redditScope,omitempty"`
+	// AnswerLanguage is the language the answer is written in, e.g. "Spanish"
+	// or "es". Defaults to the model's choice (usually the query language).
+	AnswerLanguage string `json:"answerLanguage,omitempty"`
+	// AnswerFormat is the structure of the answer: "bullets", "table" or
+	// "essay". Defaults to the model's choice.
+	AnswerFormat string `json:"answerFormat,omitempty"`
+	// Locale is the language tag relative times such as "3 days ago" are
+	// written in, e.g. "de" or "pt-BR". HTTP handlers default it from the
+	// Accept-Language header; unsupported languages get English.
+	Locale string `json:"locale,omitempty"`
+	// Timezone is the client's IANA timezone, e.g. "America/New_York". When
+	// set, results and LastUpdated also carry local times, and relative
+	// times follow the client's day boundaries.
+	Timezone string `json:"timezone,omitempty"`
+	// Verify cross-checks the answer against the results with a second model
+	// and reports unsupported statements in SearchResponse.Warnings
+	Verify bool `json:"verify,omitempty"`
+	// SelfConsistency samples several answers and reports how well they
+	// agree. Slower, but useful for subjective "what does Reddit think" queries.
+	SelfConsistency bool `json:"selfConsistency,omitempty"`
+	// SkipAI returns the ranked, highlighted results without an answer,
+	// making no model calls. Cheaper and faster for clients that only want
+	// retrieval.
+	SkipAI bool `json:"skipAI,omitempty"`
+	// DeferAnswer returns the ranked results as soon as they are retrieved,
+	// with SearchResponse.PendingAnswerID, instead of waiting for the
+	// answer. The full response is fetched from
+	// GET /api/search/answers/{pendingAnswerId}, as JSON or an event stream.
+	// Only POST /api/search supports it.
+	DeferAnswer bool `json:"deferAnswer,omitempty"`
+	// IncludeRaw attaches the JSON Reddit returned for each result, for
+	// debugging how a result was parsed or scored. Large payloads are capped.
+	// It is ignored with cleanLanguage, safeSearch or a content policy that
+	// rewrites results, since the JSON isn't masked.
+	IncludeRaw bool `json:"includeRaw,omitempty"`
+	// MaxContentLength is how many characters of each result's content the
+	// model sees. Defaults to the length configured for the result's
+	// subreddit, or the model's own cap.
+	MaxContentLength int `json:"maxContentLength,omitempty"`
+	// Highlights is the number of key excerpts kept per result, at most 10.
+	// Defaults to the configured number, usually 3.
+	Highlights int `json:"highlights,omitempty"`
+	// MinSubreddits is how many communities the results the answer is based
+	// on should come from, at most 5. When they come from fewer, an extra
+	// search looks in related communities. Searches scoped to subreddits or
+	// a Reddit scope aren't broadened.
+	MinSubreddits int `json:"minSubreddits,omitempty"`
+	// RecencyBias is how strongly newer results are favored in ranking:
+	// "none" ranks old and new alike, "low" weakens and "high" strengthens
+	// the preference. Defaults to favoring newer results only for
+	// time-sensitive queries.
+	RecencyBias string `json:"recencyBias,omitempty"`
+	// MinScore drops posts and comments with fewer net upvotes, and
+	// MinComments posts with fewer comments, before results are ranked.
+	// Communities and wiki pages are kept either way.
+	MinScore    int `json:"minScore,omitempty"`
+	MinComments int `json:"minComments,omitempty"`
+	// ExcludeSubreddits are communities whose content is left out, e.g.
+	// satire subreddits for serious questions. Accepts "r/name" or "name".
+	ExcludeSubreddits []string `json:"excludeSubreddits,omitempty"`
+	// ExcludeAuthors are users whose posts and comments are left out.
+	// Accepts "u/name" or "name". Unset, it leaves out bots such as
+	// AutoModerator; an empty list leaves out no one.
+	ExcludeAuthors []string `json:"excludeAuthors,omitempty"`
+	// Region is where the user is, e.g. "uk" or "nyc", for questions with
+	// local answers. The region's communities are searched too and their
+	// results ranked higher. The server's configuration lists the regions.
+	Region string `json:"region,omitempty"`
+	// CleanLanguage asks for an answer without profanity, e.g. for
+	// workplace tools: quotes are paraphrased and any profanity left is
+	// masked, whatever the server's content policy
+	CleanLanguage bool `json:"cleanLanguage,omitempty"`
+	// SafeSearch drops NSFW results, moderates results and the answer and
+	// leaves out communities unsuitable for e.g. classrooms. The server may
+	// apply it to every request.
+	SafeSearch bool `json:"safeSearch,omitempty"`
 }
 
+// Recency biases a client can request
+const (
+	RecencyBiasNone = "none"
+	RecencyBiasLow  = "low"
+	RecencyBiasHigh = "high"
+)
+
+// Answer formats a client can request
+const (
+	AnswerFormatBullets = "bullets"
+	AnswerFormatTable   = "table"
+	AnswerFormatEssay   = "essay"
+)
+
+// Reddit scopes a client can request
+const (
+	RedditScopeSubscriptions = "subscriptions"
+	RedditScopeSaved         = "saved"
+	// RedditScopeMultiPrefix is followed by the multireddit's name
+	RedditScopeMultiPrefix = "multi:"
+)
+
 // SearchResult represents a single result from Reddit
 type SearchResult struct {
 	ID           string   `json:"id"`
@@ -16,12 +121,69 @@ type SearchResult struct {
 	Subreddit    string   `json:"subreddit"`
 	Author       string   `json:"author"`
 	Content      string   `json:"content"`
-	URL          string   `json:"url"`
+	URL          string   `json:"url"`                 // Same as Permalink; kept for older clients
+	Permalink    string   `json:"permalink,omitempty"` // Reddit page the result is discussed on
 	CreatedUTC   int64    `json:"createdUtc"`
+	EditedUTC    int64    `json:"editedUtc,omitempty"` // Last edit by the author; zero if never edited
 	Score        int      `json:"score"`
 	CommentCount int      `json:"commentCount,omitempty"`
-	Type         string   `json:"type"` // "post", "comment", or "subreddit"
-	Highlights   []string `json:"highlights,omitempty"` // Key excerpts to highlight
+	UpvoteRatio  float64  `json:"upvoteRatio,omitempty"` // Share of votes that are upvotes, 0-1; posts only
+	Type         string   `json:"type"`                  // "post", "comment", "subreddit" or "wiki"
+	Highlights   []string `json:"highlights,omitempty"`  // Key excerpts to highlight
+	Relevance    float64  `json:"relevance,omitempty"`   // Ranking score, 0-1, relative to the search's best result
+	NSFW         bool     `json:"nsfw,omitempty"`        // Marked over 18 on Reddit
+	Awards       int      `json:"awards,omitempty"`      // Awards received
+	Gilded       int      `json:"gilded,omitempty"`      // Gold awards (gildings) received
+	Media        *Media   `json:"media,omitempty"`       // Images and video attached to a post
+	// ThumbnailURL and PreviewURL flatten Media for clients that only show a
+	// single image: the small thumbnail and the first full-size still image
+	ThumbnailURL string `json:"thumbnailUrl,omitempty"`
+	PreviewURL   string `json:"previewUrl,omitempty"`
+	// Distinguished is set for posts and comments a moderator or admin made
+	// in that capacity, such as rules, announcements and removal notices
+	Distinguished bool `json:"distinguished,omitempty"`
+	// Stickied is set for posts and comments pinned by the moderators
+	Stickied bool `json:"stickied,omitempty"`
+	// Archived threads are read-only: no new comments or votes
+	Archived bool `json:"archived,omitempty"`
+	// Locked threads accept no new comments
+	Locked bool `json:"locked,omitempty"`
+	// Removed is set when moderators, Reddit or the author removed or deleted
+	// the content, leaving only a "[removed]" or "[deleted]" placeholder
+	Removed bool `json:"removed,omitempty"`
+	// Flair is the post's link flair, e.g. "Discussion" or "Solved"
+	Flair string `json:"flair,omitempty"`
+	// AuthorFlair is the author's user flair in the subreddit, which in many
+	// communities shows verified credentials such as "Verified Engineer"
+	AuthorFlair string `json:"authorFlair,omitempty"`
+	// ExternalURL is the article, image or video a link post points to. It
+	// is empty for self posts and for crossposts, which link within Reddit.
+	ExternalURL string `json:"externalUrl,omitempty"`
+	// LinkPreview is extracted from ExternalURL when link fetching is enabled
+	LinkPreview *LinkPreview `json:"linkPreview,omitempty"`
+	// Transcript is retrieved for video posts when transcripts are enabled
+	Transcript *Transcript `json:"transcript,omitempty"`
+	// ParentID, LinkID and Depth place a comment in its thread. ParentID is
+	// the parent's fullname (t3_ for the post, t1_ for a comment) and LinkID
+	// the post's. Depth is 0 for top-level comments and, when Reddit doesn't
+	// report it, at least 1 for replies.
+	ParentID string `json:"parentId,omitempty"`
+	LinkID   string `json:"linkId,omitempty"`
+	Depth    int    `json:"depth,omitempty"`
+	// ThreadContext holds the comments a heavily cited reply responds to,
+	// outermost first
+	ThreadContext []Comment `json:"threadContext,omitempty"`
+	// Megathread is set for top comments taken from a megathread or live
+	// thread about a breaking-news query
+	Megathread bool `json:"megathread,omitempty"`
+	// CreatedLocal and CreatedAgo give CreatedUTC in the requested timezone,
+	// e.g. "2024-03-09 21:04 PST" and "yesterday". Only set when the request
+	// has a timezone.
+	CreatedLocal string `json:"createdLocal,omitempty"`
+	CreatedAgo   string `json:"createdAgo,omitempty"`
+	// Raw is the JSON Reddit returned for the result, only included when the
+	// request asked for it. Results from the local index have none.
+	Raw json.RawMessage `json:"raw,omitempty"`
 }
 
 // Citation represents a reference to a source in the results
@@ -30,8 +192,9 @@ type Citation struct {
 	Text      string `json:"text"`
 	URL       string `json:"url"`
 	Title     string `json:"title"`
-	Type      string `json:"type"` // "post", "comment", "subreddit"
+	Type      string `json:"type"` // "post", "comment", "subreddit", "wiki"
 	Subreddit string `json:"subreddit"`
+	Archived  bool   `json:"archived,omitempty"` // The cited thread is read-only
 }
 
 // ReasoningStep represents a single step in the AI's reasoning process
@@ -40,23 +203,231 @@ type ReasoningStep struct {
 	Content string `json:"content"`
 }
 
-// SearchResponse represents the search response with enhanced RAG information
+// SearchResponse represents the search response with enhanced RAG information.
+//
+// It is served as-is by every route of an API version, so changes follow the
+// API's compatibility policy. Within a version, fields may be added but must
+// be optional for clients (omitempty, or a zero value that means "unknown"),
+// and existing fields keep their name, type and meaning. Removing or
+// renaming a field, changing its type, or changing what a zero value means
+// needs a new version under /api/vN, with the previous version still served.
+// New nested data such as clusters, scores or media goes in new fields
+// rather than reshaping existing ones.
 type SearchResponse struct {
-	Results        []SearchResult  `json:"results"`
-	TotalCount     int             `json:"totalCount"`
-	Reasoning      string          `json:"reasoning,omitempty"`
-	ReasoningSteps []ReasoningStep `json:"reasoningSteps,omitempty"`
-	Answer         string          `json:"answer,omitempty"`
-	Citations      []Citation      `json:"citations,omitempty"`
-	ElapsedTime    float64         `json:"elapsedTime"`
-	LastUpdated    int64           `json:"lastUpdated"` // Unix timestamp of data freshness
-	RequestParams  RequestParams   `json:"requestParams,omitempty"`
+	ID             string             `json:"id,omitempty"` // Snapshot ID, set once the response is persisted
+	Results        []SearchResult     `json:"results"`
+	TotalCount     int                `json:"totalCount"`
+	Reasoning      string             `json:"reasoning,omitempty"`
+	ReasoningSteps []ReasoningStep    `json:"reasoningSteps,omitempty"`
+	Answer         string             `json:"answer,omitempty"`
+	Citations      []Citation         `json:"citations,omitempty"`
+	Warnings       []string           `json:"warnings,omitempty"`    // Quality issues found in the answer, e.g. unsupported statements
+	Consistency    *ConsistencyReport `json:"consistency,omitempty"` // Set when self-consistency mode was requested
+	ElapsedTime    float64            `json:"elapsedTime"`
+	LastUpdated    int64              `json:"lastUpdated"` // Unix timestamp of data freshness
+	// LastUpdatedLocal gives LastUpdated in the requested timezone; only set
+	// when the request has a timezone
+	LastUpdatedLocal string        `json:"lastUpdatedLocal,omitempty"`
+	Source           string        `json:"source,omitempty"` // Where results came from: SourceReddit or SourceIndex
+	RequestParams    RequestParams `json:"requestParams,omitempty"`
+	// AnswerError is set when results were found but the AI answer failed,
+	// e.g. with ErrorCodeAIRateLimited
+	AnswerError *ErrorResponse `json:"answerError,omitempty"`
+	// RelatedSubreddits suggest communities to scope a follow-up search to
+	RelatedSubreddits []RelatedSubreddit `json:"relatedSubreddits,omitempty"`
+	// Entities are the products, games, people and models the results
+	// mention most, for anchoring rankings on concrete items
+	Entities []Entity `json:"entities,omitempty"`
+	// Comparison lays out the answer to an "X vs Y" query as a table
+	Comparison *Comparison `json:"comparison,omitempty"`
+	// Ranking holds the entries of the answer to a "top N" query, best first
+	Ranking []RankingEntry `json:"ranking,omitempty"`
+	// Stats summarize the results, e.g. for "based on 42 posts across 9
+	// subreddits from the last month"
+	Stats *ResultStats `json:"stats,omitempty"`
+	// TopTerms are the words the results center on, by TF-IDF, leaving out
+	// the query's own words
+	TopTerms []TopTerm `json:"topTerms,omitempty"`
+	// Controversial is set when Reddit is split on the question; the answer
+	// then presents each camp with its own citations
+	Controversial bool `json:"controversial,omitempty"`
+	// Diversity reports how many communities the answer draws on; set when
+	// the request has minSubreddits
+	Diversity *SourceDiversity `json:"diversity,omitempty"`
+	// PendingAnswerID is set on the results returned first for requests
+	// with deferAnswer; the answer is fetched with it once generated
+	PendingAnswerID string `json:"pendingAnswerId,omitempty"`
+}
+
+// SourceDiversity describes how many communities the results an answer is
+// based on come from
+type SourceDiversity struct {
+	Subreddits    int  `json:"subreddits"`    // Distinct communities among those results
+	MinSubreddits int  `json:"minSubreddits"` // As requested
+	Broadened     bool `json:"broadened"`     // An extra search added results from other communities
+}
+
+// TopTerm is a word that characterizes a response's results
+type TopTerm struct {
+	Term    string  `json:"term"`
+	Score   float64 `json:"score"`   // TF-IDF weight, only comparable within a response
+	Results int     `json:"results"` // Results using it
+}
+
+// ResultStats are aggregate statistics over a response's results. Community
+// results (type "subreddit") only count towards Results, since their score
+// is a subscriber count and they have no age.
+type ResultStats struct {
+	Results    int `json:"results"`
+	Posts      int `json:"posts"`
+	Comments   int `json:"comments"`
+	Subreddits int `json:"subreddits"` // Distinct communities results came from
+	// SubredditBreakdown counts results per community, most first
+	SubredditBreakdown []SubredditCount   `json:"subredditBreakdown,omitempty"`
+	Score              *ScoreDistribution `json:"score,omitempty"`
+	// TotalComments sums the comment counts of post results
+	TotalComments int `json:"totalComments"`
+	// AverageAgeSeconds is the mean age of results when the response was
+	// made; OldestUTC and NewestUTC bound their creation times
+	AverageAgeSeconds int64 `json:"averageAgeSeconds,omitempty"`
+	OldestUTC         int64 `json:"oldestUtc,omitempty"`
+	NewestUTC         int64 `json:"newestUtc,omitempty"`
+}
+
+// SubredditCount is how many results came from a community
+type SubredditCount struct {
+	Name    string `json:"name"`
+	Results int    `json:"results"`
+}
+
+// ScoreDistribution describes the Reddit scores of results
+type ScoreDistribution struct {
+	Min    int     `json:"min"`
+	P25    int     `json:"p25"`
+	Median int     `json:"median"`
+	P75    int     `json:"p75"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+	Total  int     `json:"total"`
+}
+
+// RankingEntry is one item of a ranked answer
+type RankingEntry struct {
+	Rank          int    `json:"rank"` // 1 is best
+	Name          string `json:"name"`
+	Justification string `json:"justification"`
+	// Citations are the supporting results, numbered like Citation.Index
+	Citations []int `json:"citations,omitempty"`
+}
+
+// Comparison is a structured comparison of the options a query weighs
+// against each other, for rendering as a table next to the prose answer
+type Comparison struct {
+	Options  []string              `json:"options"`
+	Criteria []ComparisonCriterion `json:"criteria"`
+	Verdict  string                `json:"verdict,omitempty"`
+}
+
+// ComparisonCriterion is one row of a comparison, such as price or
+// performance
+type ComparisonCriterion struct {
+	Name string `json:"name"`
+	// Evidence holds one entry per option, in Comparison.Options order
+	Evidence []ComparisonEvidence `json:"evidence"`
+}
+
+// ComparisonEvidence is what the results say about one option for a
+// criterion
+type ComparisonEvidence struct {
+	Summary string `json:"summary"` // Empty when the results don't cover it
+	// Citations are the supporting results, numbered like Citation.Index
+	Citations []int `json:"citations,omitempty"`
+}
+
+// Entity is a named thing the results mention, such as a product, game,
+// person or model
+type Entity struct {
+	Name     string `json:"name"`     // Most common spelling
+	Mentions int    `json:"mentions"` // Times mentioned across results
+	Results  int    `json:"results"`  // Results mentioning it
+	Score    int    `json:"score"`    // Total Reddit score of those results
+	// ResultIDs are the first few results mentioning it, in result order
+	ResultIDs []string `json:"resultIds,omitempty"`
+}
+
+// RelatedSubreddit is a community related to a search: one its results came
+// from, or one whose name or description matches the query
+type RelatedSubreddit struct {
+	Name string `json:"name"`
+	// Results is how many of the search's results came from the community;
+	// zero for communities only found by matching the query
+	Results     int    `json:"results,omitempty"`
+	Subscribers int    `json:"subscribers,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Result sources reported in SearchResponse.Source
+const (
+	SourceReddit = "reddit" // Live Reddit search
+	SourceIndex  = "index"  // Local index of crawled subreddits
+)
+
+// ConsistencyReport describes how well independently sampled answers agreed
+type ConsistencyReport struct {
+	Samples   int     `json:"samples"`   // Number of answers compared
+	Agreement float64 `json:"agreement"` // Mean pairwise agreement, 0-1
+	Consensus bool    `json:"consensus"` // Whether agreement met the threshold
+	// Alternatives are the other sampled answers, included when there was
+	// no consensus so clients can show the competing readings
+	Alternatives []string `json:"alternatives,omitempty"`
 }
 
 // RequestParams captures the original request parameters for reference
 type RequestParams struct {
-	Query      string `json:"query"`
-	SearchMode string `json:"searchMode"`
-	ModelName  string `json:"modelName"`
-	Limit      int    `json:"limit"`
-}
\ No newline at end of file
+	Query             string   `json:"query"`
+	SearchMode        string   `json:"searchMode"`
+	ModelName         string   `json:"modelName"`
+	Limit             int      `json:"limit"`
+	Subreddits        []string `json:"subreddits,omitempty"`
+	AnswerLanguage    string   `json:"answerLanguage,omitempty"`
+	AnswerFormat      string   `json:"answerFormat,omitempty"`
+	Locale            string   `json:"locale,omitempty"`
+	Timezone          string   `json:"timezone,omitempty"`
+	Verify            bool     `json:"verify,omitempty"`
+	SelfConsistency   bool     `json:"selfConsistency,omitempty"`
+	SkipAI            bool     `json:"skipAI,omitempty"`
+	IncludeRaw        bool     `json:"includeRaw,omitempty"`
+	MaxContentLength  int      `json:"maxContentLength,omitempty"`
+	Highlights        int      `json:"highlights,omitempty"`
+	MinSubreddits     int      `json:"minSubreddits,omitempty"`
+	RecencyBias       string   `json:"recencyBias,omitempty"`
+	MinScore          int      `json:"minScore,omitempty"`
+	MinComments       int      `json:"minComments,omitempty"`
+	ExcludeSubreddits []string `json:"excludeSubreddits,omitempty"`
+	ExcludeAuthors    []string `json:"excludeAuthors,omitempty"`
+	Region            string   `json:"region,omitempty"`
+	CleanLanguage     bool     `json:"cleanLanguage,omitempty"`
+	SafeSearch        bool     `json:"safeSearch,omitempty"`
+	RedditScope       string   `json:"redditScope,omitempty"`
+}
+
+// BatchSearchRequest represents a request to run several searches at once
+type BatchSearchRequest struct {
+	Queries []SearchRequest `json:"queries"`
+}
+
+// BatchSearchItem holds the outcome of a single query within a batch
+type BatchSearchItem struct {
+	Index    int             `json:"index"`
+	Query    string          `json:"query"`
+	Response *SearchResponse `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// BatchSearchResponse represents the combined response of a batch search
+type BatchSearchResponse struct {
+	Results     []BatchSearchItem `json:"results"`
+	Succeeded   int               `json:"succeeded"`
+	Failed      int               `json:"failed"`
+	ElapsedTime float64           `json:"elapsedTime"`
+}
@@ -0,0 +1,21 @@
+// File: backend/internal/models/share.go
+
+package models
+
+// ShareRequest asks for a public link to a search's answer
+type ShareRequest struct {
+	// SnapshotID is the ID of the search response
+	SnapshotID string `json:"snapshotId"`
+	// ExpiresIn is how long the link lasts, in seconds. 0 uses the server's
+	// default; longer than the server allows is an error.
+	ExpiresIn int64 `json:"expiresIn,omitempty"`
+}
+
+// Share is a public link to a search's answer
+type Share struct {
+	// Token identifies the link; URL is the link itself
+	Token      string `json:"token"`
+	URL        string `json:"url"`
+	SnapshotID string `json:"snapshotId"`
+	ExpiresAt  int64  `json:"expiresAt"` // Unix timestamp
+}
@@ -0,0 +1,12 @@
+// File: backend/internal/models/suggest.go
+
+package models
+
+// SuggestResponse holds typeahead suggestions for a partly typed query
+type SuggestResponse struct {
+	Query string `json:"query"`
+	// Queries are past searches starting with the query, most popular first
+	Queries []string `json:"queries"`
+	// Subreddits are communities whose names start with the query
+	Subreddits []SearchResult `json:"subreddits"`
+}
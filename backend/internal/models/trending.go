@@ -0,0 +1,10 @@
+// File: backend/internal/models/trending.go
+
+package models
+
+// TrendingResponse lists what is currently popular on Reddit
+type TrendingResponse struct {
+	Posts       []SearchResult `json:"posts"`
+	Subreddits  []SearchResult `json:"subreddits"`
+	LastUpdated int64          `json:"lastUpdated"` // Unix timestamp of data freshness
+}
@@ -0,0 +1,14 @@
+// File: backend/internal/models/user.go
+
+package models
+
+// RedditUser is a Reddit account's public profile
+type RedditUser struct {
+	Name         string `json:"name"`
+	CreatedUTC   int64  `json:"createdUtc"`
+	LinkKarma    int    `json:"linkKarma"`
+	CommentKarma int    `json:"commentKarma"`
+	Verified     bool   `json:"verified,omitempty"`    // Verified email address
+	IsModerator  bool   `json:"isModerator,omitempty"` // Moderates at least one community
+	Suspended    bool   `json:"suspended,omitempty"`
+}
@@ -0,0 +1,111 @@
+// File: backend/internal/moderation/moderation.go
+
+// Package moderation checks text against a provider moderation API and
+// applies configurable per-category thresholds to the scores it returns.
+package moderation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+)
+
+// maxBatchSize caps how many texts are sent to the provider in one request
+const maxBatchSize = 32
+
+// Moderator scores texts by moderation category. The returned slice has one
+// entry per input text, mapping category names to scores between 0 and 1.
+type Moderator interface {
+	Score(ctx context.Context, texts []string) ([]map[string]float64, error)
+}
+
+// Verdict is the outcome of checking a single text
+type Verdict struct {
+	Flagged    bool
+	Categories []string // Categories whose score met the threshold, sorted
+}
+
+// Service applies the configured thresholds to a moderator's scores
+type Service struct {
+	moderator        Moderator
+	categories       map[string]bool
+	thresholds       map[string]float64
+	defaultThreshold float64
+	checkResults     bool
+	checkAnswers     bool
+}
+
+// NewService creates a moderation service. Categories and thresholds come
+// from cfg; an empty category list enforces every category.
+func NewService(moderator Moderator, cfg config.ModerationConfig) *Service {
+	categories := make(map[string]bool, len(cfg.Categories))
+	for _, category := range cfg.Categories {
+		categories[category] = true
+	}
+
+	return &Service{
+		moderator:        moderator,
+		categories:       categories,
+		thresholds:       cfg.Thresholds,
+		defaultThreshold: cfg.DefaultThreshold,
+		checkResults:     cfg.CheckResults,
+		checkAnswers:     cfg.CheckAnswers,
+	}
+}
+
+// ChecksResults reports whether retrieved content should be moderated
+func (s *Service) ChecksResults() bool {
+	return s != nil && s.checkResults
+}
+
+// ChecksAnswers reports whether generated answers should be moderated
+func (s *Service) ChecksAnswers() bool {
+	return s != nil && s.checkAnswers
+}
+
+// Check returns a verdict for each text, in order
+func (s *Service) Check(ctx context.Context, texts []string) ([]Verdict, error) {
+	verdicts := make([]Verdict, 0, len(texts))
+
+	for start := 0; start < len(texts); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		scores, err := s.moderator.Score(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, categoryScores := range scores {
+			verdicts = append(verdicts, s.evaluate(categoryScores))
+		}
+	}
+
+	return verdicts, nil
+}
+
+// evaluate applies the thresholds to one text's category scores
+func (s *Service) evaluate(scores map[string]float64) Verdict {
+	var verdict Verdict
+	for category, score := range scores {
+		if len(s.categories) > 0 && !s.categories[category] {
+			continue
+		}
+
+		threshold, ok := s.thresholds[category]
+		if !ok {
+			threshold = s.defaultThreshold
+		}
+
+		if score >= threshold {
+			verdict.Flagged = true
+			verdict.Categories = append(verdict.Categories, category)
+		}
+	}
+
+	sort.Strings(verdict.Categories)
+	return verdict
+}
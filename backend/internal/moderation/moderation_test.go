@@ -0,0 +1,106 @@
+// File: backend/internal/moderation/moderation_test.go
+
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+)
+
+// staticModerator returns the same scores for every text
+type staticModerator map[string]float64
+
+func (m staticModerator) Score(ctx context.Context, texts []string) ([]map[string]float64, error) {
+	scores := make([]map[string]float64, len(texts))
+	for i := range texts {
+		scores[i] = m
+	}
+	return scores, nil
+}
+
+func TestThresholdsAndCategories(t *testing.T) {
+	scores := staticModerator{"violence": 0.4, "sexual": 0.7, "hate": 0.2}
+
+	testCases := []struct {
+		name     string
+		cfg      config.ModerationConfig
+		expected []string
+	}{
+		{
+			name:     "Default threshold",
+			cfg:      config.ModerationConfig{DefaultThreshold: 0.5},
+			expected: []string{"sexual"},
+		},
+		{
+			name:     "Per-category override",
+			cfg:      config.ModerationConfig{DefaultThreshold: 0.5, Thresholds: map[string]float64{"violence": 0.3}},
+			expected: []string{"sexual", "violence"},
+		},
+		{
+			name:     "Category allow-list",
+			cfg:      config.ModerationConfig{DefaultThreshold: 0.1, Categories: []string{"hate"}},
+			expected: []string{"hate"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verdicts, err := NewService(scores, tc.cfg).Check(context.Background(), []string{"text"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := verdicts[0].Categories
+			if len(got) != len(tc.expected) {
+				t.Fatalf("Expected categories %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Errorf("Expected categories %v, got %v", tc.expected, got)
+				}
+			}
+			if !verdicts[0].Flagged {
+				t.Error("Expected text to be flagged")
+			}
+		})
+	}
+}
+
+func TestOpenAIModerator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("Expected API key to be sent, got %q", r.Header.Get("Authorization"))
+		}
+
+		var body struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		results := make([]map[string]interface{}, len(body.Input))
+		for i := range body.Input {
+			results[i] = map[string]interface{}{
+				"category_scores": map[string]float64{"violence": float64(i) * 0.9},
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	}))
+	defer server.Close()
+
+	moderator := NewOpenAIModerator("test-key", "omni-moderation-latest")
+	moderator.baseURL = server.URL
+
+	scores, err := moderator.Score(context.Background(), []string{"calm", "violent"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(scores) != 2 || scores[0]["violence"] != 0 || scores[1]["violence"] != 0.9 {
+		t.Errorf("Unexpected scores: %v", scores)
+	}
+}
@@ -0,0 +1,91 @@
+// File: backend/internal/moderation/openai.go
+
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxInputLength caps each text sent for moderation; long posts are judged by
+// their beginning
+const maxInputLength = 8000
+
+// OpenAIModerator scores text with the OpenAI moderation endpoint
+type OpenAIModerator struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenAIModerator creates a moderator using the given API key and model
+func NewOpenAIModerator(apiKey, model string) *OpenAIModerator {
+	return &OpenAIModerator{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: "https://api.openai.com/v1",
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Score implements Moderator
+func (m *OpenAIModerator) Score(ctx context.Context, texts []string) ([]map[string]float64, error) {
+	inputs := make([]string, len(texts))
+	for i, text := range texts {
+		if len(text) > maxInputLength {
+			text = text[:maxInputLength]
+		}
+		inputs[i] = text
+	}
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model": m.model,
+		"input": inputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling moderation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", m.baseURL+"/moderations", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("error creating moderation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request to OpenAI moderation API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("error response from OpenAI moderation API (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var response struct {
+		Results []struct {
+			CategoryScores map[string]float64 `json:"category_scores"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAI moderation response: %w", err)
+	}
+
+	if len(response.Results) != len(texts) {
+		return nil, fmt.Errorf("OpenAI moderation returned %d results for %d inputs", len(response.Results), len(texts))
+	}
+
+	scores := make([]map[string]float64, len(response.Results))
+	for i, result := range response.Results {
+		scores[i] = result.CategoryScores
+	}
+	return scores, nil
+}
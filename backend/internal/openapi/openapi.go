@@ -0,0 +1,313 @@
+// File: backend/internal/openapi/openapi.go
+
+// Package openapi builds an OpenAPI 3 document from operation annotations.
+// Schemas are generated from the Go types handlers bind and return, so the
+// published document follows the JSON the API actually produces.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.0.3"
+
+// Info describes the API as a whole
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Operation annotates one route
+type Operation struct {
+	Method      string // GET, POST, PUT or DELETE
+	Path        string // OpenAPI form, e.g. /api/search/{id}
+	Tag         string // Groups operations in the docs
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	// Request is a value of the JSON request body's type, or nil when the
+	// operation takes no body
+	Request   interface{}
+	Responses []Response
+	// Admin operations require the admin bearer token
+	Admin bool
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string
+	In          string // path, query or header
+	Description string
+	Type        string // string, integer or boolean
+	Required    bool   // Always true for path parameters
+}
+
+// Response is one possible response of an operation
+type Response struct {
+	Status      int
+	Description string
+	// Body is a value of the response body's type, or nil for no body
+	Body interface{}
+	// ContentType defaults to application/json
+	ContentType string
+}
+
+// ErrorBody is the JSON body of error responses
+type ErrorBody struct {
+	Error   string `json:"error"`
+	Details string `json:"details,omitempty"`
+}
+
+// Error is an error response with the standard body
+func Error(status int, description string) Response {
+	return Response{Status: status, Description: description, Body: ErrorBody{}}
+}
+
+// Build returns the OpenAPI document for operations as JSON
+func Build(info Info, operations []Operation) ([]byte, error) {
+	g := &generator{schemas: make(map[string]interface{})}
+
+	paths := make(map[string]map[string]interface{})
+	for _, op := range operations {
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]interface{})
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = g.operation(op)
+	}
+
+	document := map[string]interface{}{
+		"openapi": Version,
+		"info": map[string]interface{}{
+			"title":       info.Title,
+			"version":     info.Version,
+			"description": info.Description,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]interface{}{
+				// Optional on public routes; identifies the caller for
+				// history and saved searches instead of their IP address
+				"apiKey": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": "X-API-Key",
+				},
+				"adminToken": map[string]interface{}{
+					"type":   "http",
+					"scheme": "bearer",
+				},
+			},
+		},
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// generator accumulates the named schemas operations refer to
+type generator struct {
+	schemas map[string]interface{}
+}
+
+func (g *generator) operation(op Operation) map[string]interface{} {
+	out := map[string]interface{}{
+		"summary":     op.Summary,
+		"operationId": operationID(op),
+	}
+	if op.Tag != "" {
+		out["tags"] = []string{op.Tag}
+	}
+	if op.Description != "" {
+		out["description"] = op.Description
+	}
+
+	if len(op.Parameters) > 0 {
+		params := make([]interface{}, 0, len(op.Parameters))
+		for _, param := range op.Parameters {
+			paramType := param.Type
+			if paramType == "" {
+				paramType = "string"
+			}
+			entry := map[string]interface{}{
+				"name":     param.Name,
+				"in":       param.In,
+				"required": param.Required || param.In == "path",
+				"schema":   map[string]interface{}{"type": paramType},
+			}
+			if param.Description != "" {
+				entry["description"] = param.Description
+			}
+			params = append(params, entry)
+		}
+		out["parameters"] = params
+	}
+
+	if op.Request != nil {
+		out["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": g.schema(reflect.TypeOf(op.Request)),
+				},
+			},
+		}
+	}
+
+	responses := make(map[string]interface{}, len(op.Responses))
+	for _, response := range op.Responses {
+		description := response.Description
+		if description == "" {
+			description = http.StatusText(response.Status)
+		}
+		entry := map[string]interface{}{"description": description}
+		if response.Body != nil {
+			contentType := response.ContentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			entry["content"] = map[string]interface{}{
+				contentType: map[string]interface{}{
+					"schema": g.schema(reflect.TypeOf(response.Body)),
+				},
+			}
+		}
+		responses[strconv.Itoa(response.Status)] = entry
+	}
+	out["responses"] = responses
+
+	if op.Admin {
+		out["security"] = []interface{}{map[string]interface{}{"adminToken": []string{}}}
+	} else {
+		// The key is optional, hence the empty alternative
+		out["security"] = []interface{}{
+			map[string]interface{}{"apiKey": []string{}},
+			map[string]interface{}{},
+		}
+	}
+	return out
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	rawJSONType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema returns the schema for t. Named structs become components and are
+// referenced, so recursive types such as comment trees terminate.
+func (g *generator) schema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64", "description": "Nanoseconds"}
+	case rawJSONType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		// Unexported request types still get a conventional schema name
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+		if _, ok := g.schemas[name]; !ok {
+			// Reserve the name before recursing
+			g.schemas[name] = nil
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	default:
+		// interface{} and anything else JSON can't constrain
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct's JSON fields. Embedded structs without a
+// JSON name are flattened, as encoding/json does.
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	g.addFields(t, properties)
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+func (g *generator) addFields(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(embedded, properties)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+	}
+}
+
+// operationID derives a stable ID from the method and path, e.g.
+// GET /api/search/{id}/export becomes getSearchByIdExport
+func operationID(op Operation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, segment := range strings.Split(strings.TrimPrefix(op.Path, "/api"), "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.HasPrefix(segment, "{") {
+			b.WriteString("By")
+			segment = strings.Trim(segment, "{}")
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+// File: backend/internal/openapi/openapi_test.go
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type node struct {
+	Name     string    `json:"name"`
+	Children []node    `json:"children,omitempty"`
+	Created  time.Time `json:"created"`
+	Hidden   string    `json:"-"`
+	internal string
+	embedded
+}
+
+type embedded struct {
+	Score float64 `json:"score"`
+}
+
+func TestBuild(t *testing.T) {
+	data, err := Build(Info{Title: "Test", Version: "1"}, []Operation{
+		{
+			Method:     "POST",
+			Path:       "/api/nodes/{id}",
+			Summary:    "Update a node",
+			Parameters: []Parameter{{Name: "id", In: "path"}},
+			Request:    node{},
+			Responses: []Response{
+				{Status: http.StatusOK, Body: &node{}},
+				Error(http.StatusNotFound, "Node not found"),
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	var document struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Required bool `json:"required"`
+			} `json:"parameters"`
+			Responses map[string]json.RawMessage `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	if document.OpenAPI != Version {
+		t.Errorf("openapi = %q", document.OpenAPI)
+	}
+	op, ok := document.Paths["/api/nodes/{id}"]["post"]
+	if !ok {
+		t.Fatalf("Missing operation, got paths %v", document.Paths)
+	}
+	if op.OperationID != "postNodesById" {
+		t.Errorf("operationId = %q", op.OperationID)
+	}
+	if len(op.Parameters) != 1 || !op.Parameters[0].Required {
+		t.Errorf("Path parameters must be required, got %+v", op.Parameters)
+	}
+	if _, ok := op.Responses["404"]; !ok {
+		t.Errorf("Missing 404 response")
+	}
+
+	schema, ok := document.Components.Schemas["Node"]
+	if !ok {
+		t.Fatalf("Missing Node schema, got %v", document.Components.Schemas)
+	}
+	for _, name := range []string{"name", "children", "created", "score"} {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("Missing property %s", name)
+		}
+	}
+	for _, name := range []string{"Hidden", "-", "internal", "embedded"} {
+		if _, ok := schema.Properties[name]; ok {
+			t.Errorf("Unexpected property %s", name)
+		}
+	}
+	if schema.Properties["created"]["format"] != "date-time" {
+		t.Errorf("created = %v, want a date-time string", schema.Properties["created"])
+	}
+	items, _ := schema.Properties["children"]["items"].(map[string]interface{})
+	if items["$ref"] != "#/components/schemas/Node" {
+		t.Errorf("children = %v, want a reference to Node", schema.Properties["children"])
+	}
+	if _, ok := document.Components.Schemas["ErrorBody"]; !ok {
+		t.Errorf("Missing ErrorBody schema")
+	}
+}
@@ -0,0 +1,97 @@
+// File: backend/internal/pending/pending.go
+
+// Package pending tracks answers still being generated for searches that
+// returned their results first, so clients can fetch the answer once the
+// model is done.
+package pending
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// Answer is the outcome of a search whose answer is being generated
+type Answer struct {
+	done     chan struct{}
+	response *models.SearchResponse
+	err      error
+}
+
+// Done is closed once the search has finished
+func (a *Answer) Done() <-chan struct{} {
+	return a.done
+}
+
+// Result returns the full response, or the error the search failed with.
+// It must only be called once Done is closed.
+func (a *Answer) Result() (*models.SearchResponse, error) {
+	return a.response, a.err
+}
+
+// Answers holds pending answers by ID. Finished answers are kept for a
+// retention period, then forgotten.
+type Answers struct {
+	mu        sync.Mutex
+	answers   map[string]*Answer
+	retention time.Duration
+}
+
+// New creates an empty set of pending answers that keeps finished ones for
+// retention
+func New(retention time.Duration) *Answers {
+	return &Answers{
+		answers:   make(map[string]*Answer),
+		retention: retention,
+	}
+}
+
+// Start registers a new pending answer and returns its ID
+func (a *Answers) Start() string {
+	id := newID()
+	a.mu.Lock()
+	a.answers[id] = &Answer{done: make(chan struct{})}
+	a.mu.Unlock()
+	return id
+}
+
+// Finish records the outcome of the search with the given ID and wakes the
+// clients waiting for it. Unknown IDs are ignored.
+func (a *Answers) Finish(id string, response *models.SearchResponse, err error) {
+	a.mu.Lock()
+	answer, ok := a.answers[id]
+	a.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	answer.response, answer.err = response, err
+	close(answer.done)
+	time.AfterFunc(a.retention, func() {
+		a.mu.Lock()
+		delete(a.answers, id)
+		a.mu.Unlock()
+	})
+}
+
+// Get returns the pending answer with the given ID, finished or not
+func (a *Answers) Get(id string) (*Answer, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	answer, ok := a.answers[id]
+	return answer, ok
+}
+
+// newID generates a random, unguessable answer ID
+func newID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		// crypto/rand failing means the system is unusable anyway
+		panic(fmt.Sprintf("error generating id: %v", err))
+	}
+	return hex.EncodeToString(buf)
+}
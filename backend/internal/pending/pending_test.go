@@ -0,0 +1,62 @@
+// File: backend/internal/pending/pending_test.go
+
+package pending
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestAnswers(t *testing.T) {
+	answers := New(20 * time.Millisecond)
+
+	id := answers.Start()
+	answer, ok := answers.Get(id)
+	if !ok {
+		t.Fatal("Expected the started answer to be found")
+	}
+	select {
+	case <-answer.Done():
+		t.Fatal("Expected the answer to be pending")
+	default:
+	}
+
+	answers.Finish(id, &models.SearchResponse{Answer: "Go 1.22 is out."}, nil)
+	select {
+	case <-answer.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected the answer to be done")
+	}
+	if response, err := answer.Result(); err != nil || response.Answer != "Go 1.22 is out." {
+		t.Errorf("Unexpected result %+v, %v", response, err)
+	}
+
+	failed := answers.Start()
+	answers.Finish(failed, nil, errors.New("model unavailable"))
+	if answer, _ := answers.Get(failed); answer == nil {
+		t.Error("Expected the failed answer to be found")
+	} else if _, err := answer.Result(); err == nil {
+		t.Error("Expected the search error")
+	}
+
+	// Unknown IDs are ignored
+	answers.Finish("unknown", nil, nil)
+	if _, ok := answers.Get("unknown"); ok {
+		t.Error("Expected no answer for an unknown ID")
+	}
+
+	// Finished answers are forgotten after the retention period
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := answers.Get(id); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the finished answer to be forgotten")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
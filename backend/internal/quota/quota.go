@@ -0,0 +1,156 @@
+// File: backend/internal/quota/quota.go
+
+// Package quota enforces daily and monthly limits on the search requests and
+// AI tokens each client uses, with usage kept in the store so it survives
+// restarts and is shared by replicas using the same database.
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// Limiter checks and records clients' quota usage
+type Limiter struct {
+	store *store.Store
+	cfg   config.QuotasConfig
+	now   func() time.Time
+
+	// mu serializes checking and counting a request, so concurrent
+	// requests from one client can't all slip under the limit
+	mu sync.Mutex
+	// prunedBefore is the day usage was last pruned up to
+	prunedBefore string
+}
+
+// Status is a client's quota after a request was counted
+type Status struct {
+	// Limits are the configured quotas; 0 means no limit
+	Limits  config.QuotasConfig
+	Daily   models.QuotaUsage
+	Monthly models.QuotaUsage
+	// DailyReset and MonthlyReset are when the periods end, in UTC
+	DailyReset   time.Time
+	MonthlyReset time.Time
+	// Exceeded names the quota that was used up, e.g. "daily requests";
+	// the request was refused and not counted. Empty when within quota.
+	Exceeded string
+
+	day string
+}
+
+// New creates a limiter storing usage in dataStore
+func New(dataStore *store.Store, cfg config.QuotasConfig) *Limiter {
+	return &Limiter{store: dataStore, cfg: cfg, now: time.Now}
+}
+
+// Begin counts a request from owner against its quota. When a quota is used
+// up the request isn't counted and Status.Exceeded is set. Tokens are only
+// checked up to what earlier requests used, since a request's own usage is
+// known once it's done.
+func (l *Limiter) Begin(ctx context.Context, owner string) (*Status, error) {
+	return l.BeginN(ctx, owner, 1)
+}
+
+// BeginN is Begin for n requests at once, such as the queries of a batch.
+// Either all of them fit within the quota and are counted, or none are.
+func (l *Limiter) BeginN(ctx context.Context, owner string, n int64) (*Status, error) {
+	now := l.now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	status := &Status{
+		Limits:       l.cfg,
+		DailyReset:   day.AddDate(0, 0, 1),
+		MonthlyReset: month.AddDate(0, 1, 0),
+		day:          day.Format(store.QuotaDayLayout),
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.prune(ctx, month.AddDate(0, -1, 0).Format(store.QuotaDayLayout))
+
+	var err error
+	status.Daily, status.Monthly, err = l.store.QuotaUsage(ctx, owner, status.day)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Exceeded = status.exceeded(n)
+	if status.Exceeded != "" {
+		return status, nil
+	}
+
+	if err := l.store.AddQuotaUsage(ctx, owner, status.day, n, 0); err != nil {
+		return nil, err
+	}
+	status.Daily.Requests += n
+	status.Monthly.Requests += n
+	return status, nil
+}
+
+// Finish charges the tokens a request begun with Begin used. They count
+// toward the day the request started.
+func (l *Limiter) Finish(ctx context.Context, owner string, status *Status, tokens int64) error {
+	if tokens <= 0 {
+		return nil
+	}
+	return l.store.AddQuotaUsage(ctx, owner, status.day, 0, tokens)
+}
+
+// prune deletes usage from before day, once per cutoff. Begin passes the
+// start of the previous month, so usage is kept for a month after it stops
+// counting.
+func (l *Limiter) prune(ctx context.Context, day string) {
+	if l.prunedBefore == day {
+		return
+	}
+	removed, err := l.store.PruneQuotaUsage(ctx, day)
+	if err != nil {
+		log.Printf("Failed to prune quota usage: %v", err)
+		return
+	}
+	l.prunedBefore = day
+	if removed > 0 {
+		log.Printf("Pruned %d quota usage records from before %s", removed, day)
+	}
+}
+
+// exceeded names the first quota that n more requests would go over, or
+// returns ""
+func (s *Status) exceeded(n int64) string {
+	for _, quota := range []struct {
+		name  string
+		used  int64
+		limit int64
+	}{
+		{"daily requests", s.Daily.Requests + n - 1, s.Limits.DailyRequests},
+		{"monthly requests", s.Monthly.Requests + n - 1, s.Limits.MonthlyRequests},
+		{"daily tokens", s.Daily.Tokens, s.Limits.DailyTokens},
+		{"monthly tokens", s.Monthly.Tokens, s.Limits.MonthlyTokens},
+	} {
+		if quota.limit > 0 && quota.used >= quota.limit {
+			return fmt.Sprintf("%s (%d)", quota.name, quota.limit)
+		}
+	}
+	return ""
+}
+
+// ResetAt is when the quota that was exceeded frees up again
+func (s *Status) ResetAt() time.Time {
+	if s.Exceeded == "" {
+		return time.Time{}
+	}
+	if strings.HasPrefix(s.Exceeded, "daily") {
+		return s.DailyReset
+	}
+	return s.MonthlyReset
+}
@@ -0,0 +1,98 @@
+// File: backend/internal/quota/quota_test.go
+
+package quota
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+func TestLimiter(t *testing.T) {
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer dataStore.Close()
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC)
+	limiter := New(dataStore, config.QuotasConfig{Enabled: true, DailyRequests: 2, MonthlyTokens: 100})
+	limiter.now = func() time.Time { return now }
+
+	for i := 1; i <= 2; i++ {
+		status, err := limiter.Begin(ctx, "alice")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if status.Exceeded != "" || status.Daily.Requests != int64(i) {
+			t.Errorf("Request %d: unexpected status %+v", i, status)
+		}
+	}
+
+	status, err := limiter.Begin(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.Exceeded == "" || status.Daily.Requests != 2 {
+		t.Errorf("Expected the daily request quota to be exceeded, got %+v", status)
+	}
+	if want := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC); !status.ResetAt().Equal(want) {
+		t.Errorf("Expected reset at %s, got %s", want, status.ResetAt())
+	}
+
+	// Other clients have their own quota, and tokens only stop requests
+	// once earlier requests used them up
+	status, err = limiter.Begin(ctx, "bob")
+	if err != nil || status.Exceeded != "" {
+		t.Fatalf("Expected bob within quota, got %+v (%v)", status, err)
+	}
+	if err := limiter.Finish(ctx, "bob", status, 100); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status, err = limiter.Begin(ctx, "bob"); err != nil || status.Exceeded == "" {
+		t.Errorf("Expected the monthly token quota to be exceeded, got %+v (%v)", status, err)
+	}
+
+	// A new month resets both
+	now = now.Add(2 * time.Hour)
+	for _, owner := range []string{"alice", "bob"} {
+		if status, err := limiter.Begin(ctx, owner); err != nil || status.Exceeded != "" {
+			t.Errorf("Expected %s within quota in a new month, got %+v (%v)", owner, status, err)
+		}
+	}
+}
+
+func TestLimiterBeginN(t *testing.T) {
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer dataStore.Close()
+
+	ctx := context.Background()
+	limiter := New(dataStore, config.QuotasConfig{Enabled: true, DailyRequests: 5, MonthlyRequests: 100})
+
+	status, err := limiter.BeginN(ctx, "alice", 3)
+	if err != nil || status.Exceeded != "" || status.Daily.Requests != 3 {
+		t.Fatalf("Expected 3 requests counted, got %+v (%v)", status, err)
+	}
+
+	// A batch that doesn't fit is refused as a whole
+	status, err = limiter.BeginN(ctx, "alice", 3)
+	if err != nil || status.Exceeded == "" || status.Daily.Requests != 3 {
+		t.Fatalf("Expected 3 more requests to be refused, got %+v (%v)", status, err)
+	}
+	if !status.ResetAt().Equal(status.DailyReset) {
+		t.Errorf("Expected the daily reset, got %s", status.ResetAt())
+	}
+
+	status, err = limiter.BeginN(ctx, "alice", 2)
+	if err != nil || status.Exceeded != "" || status.Daily.Requests != 5 {
+		t.Errorf("Expected the last 2 requests to fit, got %+v (%v)", status, err)
+	}
+}
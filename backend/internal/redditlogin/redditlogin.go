@@ -0,0 +1,180 @@
+// File: backend/internal/redditlogin/redditlogin.go
+
+// Package redditlogin signs people in with their Reddit accounts and keeps
+// the Reddit tokens that let searches run on their behalf, encrypted in the
+// store and refreshed before they expire.
+package redditlogin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+// StateTTL is how long a user has to approve the login on Reddit
+const StateTTL = 10 * time.Minute
+
+// ErrInvalidState is returned when the callback's state wasn't issued to
+// this browser by State, or has expired
+var ErrInvalidState = errors.New("invalid or expired login state")
+
+// Service runs the Reddit OAuth flow and manages the linked tokens
+type Service struct {
+	store       *store.Store
+	reddit      *services.RedditService
+	sealer      *auth.Sealer
+	redirectURL string
+	now         func() time.Time
+
+	// mu serializes refreshes, so concurrent searches by one user don't
+	// each refresh their token
+	mu sync.Mutex
+}
+
+// state is the sealed payload of the OAuth state parameter
+type state struct {
+	Nonce     string `json:"nonce"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// New creates a service whose Reddit app redirects back to redirectURL
+func New(dataStore *store.Store, reddit *services.RedditService, sealer *auth.Sealer, redirectURL string) *Service {
+	return &Service{
+		store:       dataStore,
+		reddit:      reddit,
+		sealer:      sealer,
+		redirectURL: redirectURL,
+		now:         time.Now,
+	}
+}
+
+// State starts a login. It returns the OAuth state to send to Reddit and a
+// nonce the browser must present with it on the callback, so a login can't
+// be completed in a browser other than the one that started it.
+func (s *Service) State() (string, string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("error generating nonce: %w", err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(buf)
+
+	payload, err := json.Marshal(state{Nonce: nonce, ExpiresAt: s.now().Add(StateTTL).Unix()})
+	if err != nil {
+		return "", "", err
+	}
+	sealed, err := s.sealer.Seal(payload)
+	if err != nil {
+		return "", "", err
+	}
+	return sealed, nonce, nil
+}
+
+// CheckState verifies a callback's state against the browser's nonce
+func (s *Service) CheckState(sealed, nonce string) error {
+	payload, err := s.sealer.Open(sealed)
+	if err != nil {
+		return ErrInvalidState
+	}
+	var st state
+	if err := json.Unmarshal(payload, &st); err != nil || nonce == "" || st.Nonce != nonce || s.now().Unix() >= st.ExpiresAt {
+		return ErrInvalidState
+	}
+	return nil
+}
+
+// AuthorizeURL is the Reddit page that asks the user to approve the login
+func (s *Service) AuthorizeURL(state string) string {
+	return s.reddit.Auth().AuthorizeURL(state, s.redirectURL)
+}
+
+// SignIn completes a login with the code Reddit redirected back with. It
+// returns the account linked to the Reddit user, creating one when they
+// sign in for the first time, and keeps their new tokens.
+func (s *Service) SignIn(ctx context.Context, code string) (*models.Account, error) {
+	token, err := s.reddit.Auth().ExchangeCode(ctx, code, s.redirectURL)
+	if err != nil {
+		return nil, err
+	}
+	username, err := s.reddit.Identity(ctx, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.seal(token)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.store.RedditLinkByUsername(ctx, username)
+	if errors.Is(err, store.ErrNotFound) {
+		return s.store.CreateRedditAccount(ctx, &models.RedditLink{Username: username, Tokens: sealed})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.UpdateRedditTokens(ctx, link.AccountID, sealed); err != nil {
+		return nil, err
+	}
+	return s.store.Account(ctx, link.AccountID)
+}
+
+// User returns the Reddit user linked to an account with a current access
+// token, refreshing it first when it's about to expire. Accounts without a
+// link, or whose access Reddit revoked, get services.ErrRedditLoginRequired.
+func (s *Service) User(ctx context.Context, accountID string) (services.RedditUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, err := s.store.RedditLink(ctx, accountID)
+	if errors.Is(err, store.ErrNotFound) {
+		return services.RedditUser{}, services.ErrRedditLoginRequired
+	}
+	if err != nil {
+		return services.RedditUser{}, err
+	}
+
+	payload, err := s.sealer.Open(link.Tokens)
+	if err != nil {
+		// The encryption key changed; signing in again stores new tokens
+		return services.RedditUser{}, fmt.Errorf("%w: stored tokens can't be decrypted", services.ErrRedditLoginRequired)
+	}
+	var token services.RedditToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return services.RedditUser{}, fmt.Errorf("error decoding Reddit tokens: %w", err)
+	}
+
+	if token.NeedsRefresh(s.now()) {
+		refreshed, err := s.reddit.Auth().RefreshUserToken(ctx, &token)
+		if err != nil {
+			return services.RedditUser{}, err
+		}
+		sealed, err := s.seal(refreshed)
+		if err != nil {
+			return services.RedditUser{}, err
+		}
+		if err := s.store.UpdateRedditTokens(ctx, accountID, sealed); err != nil {
+			return services.RedditUser{}, err
+		}
+		token = *refreshed
+	}
+
+	return services.RedditUser{Name: link.Username, AccessToken: token.AccessToken}, nil
+}
+
+// seal encrypts a token for the store
+func (s *Service) seal(token *services.RedditToken) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("error encoding Reddit tokens: %w", err)
+	}
+	return s.sealer.Seal(payload)
+}
@@ -0,0 +1,80 @@
+// File: backend/internal/redditlogin/redditlogin_test.go
+
+package redditlogin
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/auth"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/services"
+	"github.com/pranesh-j/subplexity/internal/store"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	dataStore, err := store.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { dataStore.Close() })
+
+	sealer, err := auth.NewSealer([]byte(strings.Repeat("k", auth.MinSecretLength)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return New(dataStore, services.NewRedditService("id", "secret"), sealer, "https://example.com/callback")
+}
+
+func TestState(t *testing.T) {
+	s := newTestService(t)
+	now := time.Unix(1700000000, 0)
+	s.now = func() time.Time { return now }
+
+	state, nonce, err := s.State()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.CheckState(state, nonce); err != nil {
+		t.Errorf("Expected the state to check out, got %v", err)
+	}
+	if err := s.CheckState(state, "another-browser"); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Expected ErrInvalidState for another nonce, got %v", err)
+	}
+	if err := s.CheckState("forged", nonce); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Expected ErrInvalidState for a forged state, got %v", err)
+	}
+
+	now = now.Add(StateTTL)
+	if err := s.CheckState(state, nonce); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("Expected ErrInvalidState once expired, got %v", err)
+	}
+}
+
+func TestUser(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+
+	if _, err := s.User(ctx, "unlinked"); !errors.Is(err, services.ErrRedditLoginRequired) {
+		t.Errorf("Expected ErrRedditLoginRequired without a link, got %v", err)
+	}
+
+	sealed, err := s.seal(&services.RedditToken{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	account, err := s.store.CreateRedditAccount(ctx, &models.RedditLink{Username: "spez", Tokens: sealed})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	user, err := s.User(ctx, account.ID)
+	if err != nil || user.Name != "spez" || user.AccessToken != "access" {
+		t.Errorf("User() = %+v, %v", user, err)
+	}
+}
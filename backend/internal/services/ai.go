@@ -15,41 +15,137 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pranesh-j/subplexity/internal/config"
 	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
 )
 
 // AIService handles interactions with AI models
 type AIService struct {
 	modelConfig    map[string]*AIModelConfig
 	defaultModel   string
-	promptTemplate map[string]string
+	criticModel    *AIModelConfig
+	comparisonModel *AIModelConfig
+	prompts        *PromptTemplates
+	promptConfig   config.PromptConfig
 	maxRetries     int
+	health         *modelHealthTracker
+	images         *imageFetcher // Nil unless vision is enabled
+	budget         SpendBudget   // Nil unless budgets are enabled
+	fixtures       *aiFixtures   // Nil unless AI_MODE is "fixture"
 }
 
 // NewAIService creates a new AI service
 func NewAIService() *AIService {
+	prompts, err := LoadPromptTemplates(promptTemplatesDir())
+	if err != nil {
+		// Fall back to the built-in templates so a bad edit can't stop startup
+		log.Printf("Warning: %v; using built-in prompt templates", err)
+		prompts, err = LoadPromptTemplates("")
+		if err != nil {
+			log.Fatalf("Failed to load built-in prompt templates: %v", err)
+		}
+	}
+
 	service := &AIService{
 		modelConfig:    loadModelConfigurations(),
 		defaultModel:   "Claude",
-		promptTemplate: loadPromptTemplates(),
+		criticModel:    loadCriticModelConfiguration(),
+		comparisonModel: loadComparisonModelConfiguration(),
+		prompts:        prompts,
+		promptConfig:   config.Default().Prompts,
 		maxRetries:     3,
+		health:         newModelHealthTracker(),
+		fixtures:       aiFixturesFromEnv(),
 	}
 	
 	return service
 }
 
+// SetPromptConfig replaces the prompt configuration (subreddit
+// instructions, etc.). It should be called before serving requests.
+func (s *AIService) SetPromptConfig(cfg config.PromptConfig) {
+	s.promptConfig = cfg
+}
+
+// ReloadPromptTemplates re-reads prompt templates from disk. On error the
+// current templates stay in use.
+func (s *AIService) ReloadPromptTemplates() error {
+	return s.prompts.Reload()
+}
+
+// promptTemplatesDir returns the directory prompt templates are loaded from
+func promptTemplatesDir() string {
+	if dir := os.Getenv("PROMPT_TEMPLATES_DIR"); dir != "" {
+		return dir
+	}
+	return defaultPromptTemplatesDir
+}
+
+// AnswerOptions customizes how the answer is generated
+type AnswerOptions struct {
+	// Language is the language the answer is written in, regardless of the
+	// language of the sources. Empty leaves it up to the model.
+	Language string
+	// Format is one of the models.AnswerFormat* values. Empty leaves the
+	// structure up to the model.
+	Format string
+	// SelfConsistency samples several answers at a higher temperature and
+	// keeps the one most consistent with the rest, flagging disagreement
+	SelfConsistency bool
+	// Verify runs a second, cheaper model over the answer to flag statements
+	// the results don't support
+	Verify bool
+	// Locale formats relative times in the prompt, e.g. "vor 3 Tagen" for
+	// "de". Empty means English.
+	Locale string
+	// MaxContentLength caps each result's content in the prompt. Zero uses
+	// the configured length for the result's subreddit, or the model's cap.
+	MaxContentLength int
+	// Entities are the items results mention most, which anchor rankings
+	// on concrete items
+	Entities []models.Entity
+	// Controversial asks for both camps of a split question to be presented,
+	// each with its own citations
+	Controversial bool
+	// CleanLanguage asks for quotes to be paraphrased without profanity
+	CleanLanguage bool
+}
+
+// AnswerResult is the parsed output of an AI pass over search results
+type AnswerResult struct {
+	Reasoning      string
+	Answer         string
+	ReasoningSteps []models.ReasoningStep
+	Citations      []models.Citation
+	Warnings       []string
+	Consistency    *models.ConsistencyReport // Set in self-consistency mode
+	Comparison     *models.Comparison        // Set for comparison queries
+	Ranking        []models.RankingEntry     // Set for "top N" queries
+}
+
 // ProcessResults processes search results with AI
 func (s *AIService) ProcessResults(ctx context.Context, query string, results []models.SearchResult, modelName string) (string, string, []models.ReasoningStep, []models.Citation, error) {
+	result, err := s.ProcessResultsWithOptions(ctx, query, results, modelName, AnswerOptions{})
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	return result.Reasoning, result.Answer, result.ReasoningSteps, result.Citations, nil
+}
+
+// ProcessResultsWithOptions processes search results with AI, applying the
+// given answer options
+func (s *AIService) ProcessResultsWithOptions(ctx context.Context, query string, results []models.SearchResult, modelName string, opts AnswerOptions) (*AnswerResult, error) {
 	// Check for context cancellation first
 	select {
 	case <-ctx.Done():
-		return "", "", nil, nil, ctx.Err()
+		return nil, ctx.Err()
 	default:
 		// Continue processing
 	}
 
 	if len(results) == 0 {
-		return "", "No results found for this query.", nil, nil, nil
+		return &AnswerResult{Answer: "No results found for this query."}, nil
 	}
 
 	// Use default model if none specified
@@ -65,12 +161,93 @@ func (s *AIService) ProcessResults(ctx context.Context, query string, results []
 	}
 
 	// Build the prompt
-	prompt := s.buildPrompt(query, results, modelConfig)
+	prompt, err := s.buildPrompt(query, results, modelConfig, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach images from image posts for models that can see them
+	images := s.promptImages(ctx, results, modelConfig)
+	prompt += imageInstructions(images)
 
 	// Log prompt length for debugging
-	log.Printf("Generated prompt for '%s' with %d characters", query, len(prompt))
+	log.Printf("Generated prompt for '%s' with %d characters and %d images", query, len(prompt), len(images))
+
+	call := modelCall{Prompt: prompt, JSONMode: modelConfig.JSONMode, Images: images, Query: query, Fixture: fixtureAnswer}
+
+	var result *AnswerResult
+	if opts.SelfConsistency {
+		result, err = s.selfConsistentAnswer(ctx, query, call, results, modelConfig)
+	} else {
+		result, err = s.generateAnswer(ctx, query, call, results, modelConfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold "top N" answers to exactly N entries, asking again if needed
+	if n := rankingSize(query, opts); n > 0 {
+		resultCount := modelConfig.MaxResultsInPrompt
+		if resultCount <= 0 || resultCount > len(results) {
+			resultCount = len(results)
+		}
+		result = enforceRanking(result, n, resultCount, func(correction string) (*AnswerResult, error) {
+			corrected := call
+			corrected.Prompt += correction
+			return s.generateAnswer(ctx, query, corrected, results, modelConfig)
+		})
+	}
+
+	// Both camps of a split question need their own sources
+	if opts.Controversial && len(result.Citations) < 2 {
+		result.Warnings = append(result.Warnings, controversyCitationWarning)
+	}
+
+	// Cross-check the answer against the results if requested
+	if opts.Verify {
+		result.Warnings = append(result.Warnings, s.verifyAnswer(ctx, query, result.Answer, results, modelConfig, opts)...)
+	}
+
+	// Lay "X vs Y" answers out as a table too
+	if utils.ParseQuery(query).Intent == utils.ComparisonIntent {
+		if sides := utils.ComparisonSides(query); sides != nil {
+			result.Comparison = s.compareOptions(ctx, query, sides, result.Answer, results, modelConfig, opts)
+		}
+	}
+
+	return result, nil
+}
 
-	// Process with AI model with retries
+// generateAnswer makes a single model call (with retries) and parses the
+// response into an answer
+func (s *AIService) generateAnswer(ctx context.Context, query string, call modelCall, results []models.SearchResult, modelConfig *AIModelConfig) (*AnswerResult, error) {
+	response, err := s.callModelWithRetries(ctx, query, call, modelConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split the response into reasoning and answer
+	reasoning, answer, reasoningSteps := s.parseModelResponse(response, modelConfig)
+
+	// Extract citations
+	citations := s.extractCitations(answer, results)
+
+	// Perform quality checks
+	if err := s.validateResponse(answer, reasoning, reasoningSteps, citations); err != nil {
+		log.Printf("Response validation warning: %v", err)
+	}
+
+	return &AnswerResult{
+		Reasoning:      reasoning,
+		Answer:         answer,
+		ReasoningSteps: reasoningSteps,
+		Citations:      citations,
+	}, nil
+}
+
+// callModelWithRetries calls the model, retrying failed attempts with a
+// linear backoff
+func (s *AIService) callModelWithRetries(ctx context.Context, query string, call modelCall, modelConfig *AIModelConfig) (string, error) {
 	var response string
 	var err error
 	
@@ -78,7 +255,7 @@ func (s *AIService) ProcessResults(ctx context.Context, query string, results []
 		// Check for context cancellation before each attempt
 		select {
 		case <-ctx.Done():
-			return "", "", nil, nil, ctx.Err()
+			return "", ctx.Err()
 		default:
 			// Continue processing
 		}
@@ -89,52 +266,22 @@ func (s *AIService) ProcessResults(ctx context.Context, query string, results []
 			time.Sleep(time.Duration(attempt*500) * time.Millisecond)
 		}
 		
-		response, err = s.processWithModel(ctx, prompt, modelConfig)
+		response, err = s.callModel(ctx, call, modelConfig)
 		if err == nil {
-			break
+			return response, nil
 		}
 		
 		// If context was canceled during model processing, return immediately
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			return "", "", nil, nil, err
+			return "", err
 		}
 		
 		log.Printf("AI processing error (attempt %d/%d): %v", 
 			attempt+1, s.maxRetries, err)
 	}
 	
-	if err != nil {
-		return "", "", nil, nil, fmt.Errorf("AI processing failed after %d attempts: %w", 
-			s.maxRetries, err)
-	}
-
-	// Extract reasoning and answer
-	reasoning, answer, err := s.extractReasoningAndAnswer(response, modelConfig)
-	if err != nil {
-		log.Printf("Extraction error: %v, attempting fallback parsing", err)
-		// Try fallback parsing if standard extraction fails
-		reasoning, answer = s.fallbackParsing(response)
-		
-		if reasoning == "" && answer == "" {
-			// If still no success, use raw response
-			log.Printf("Fallback parsing failed, returning raw response")
-			reasoning = "Error parsing structured response."
-			answer = cleanupRawResponse(response)
-		}
-	}
-
-	// Extract reasoning steps
-	reasoningSteps := s.extractReasoningSteps(reasoning)
-
-	// Extract citations
-	citations := s.extractCitations(answer, results)
-
-	// Perform quality checks
-	if err := s.validateResponse(answer, reasoning, reasoningSteps, citations); err != nil {
-		log.Printf("Response validation warning: %v", err)
-	}
-
-	return reasoning, answer, reasoningSteps, citations, nil
+	return "", fmt.Errorf("AI processing failed after %d attempts: %w", 
+		s.maxRetries, err)
 }
 
 // fallbackParsing attempts alternative parsing strategies when standard extraction fails
@@ -250,11 +397,6 @@ func (s *AIService) validateResponse(answer, reasoning string, steps []models.Re
 		}
 	}
 	
-	// Check for inappropriate content
-	if containsInappropriateContent(answer) || containsInappropriateContent(reasoning) {
-		return errors.New("response contains potentially inappropriate content")
-	}
-	
 	// Check for sufficient citations when results are available and mentioned
 	if strings.Contains(answer, "[") && len(citations) == 0 {
 		return errors.New("answer mentions citations but none were extracted")
@@ -292,25 +434,37 @@ func calculateSimilarity(text1, text2 string) float64 {
 	return float64(overlapCount) / float64(minLength)
 }
 
-// containsInappropriateContent checks for potentially inappropriate content
-func containsInappropriateContent(text string) bool {
-	// This is a basic implementation; production would use more sophisticated approaches
-	inappropriateTerms := []string{
-		"porn", "nsfw", "explicit", "offensive", "graphically violent",
-	}
-	
-	lowercaseText := strings.ToLower(text)
-	for _, term := range inappropriateTerms {
-		if strings.Contains(lowercaseText, term) {
-			return true
-		}
+// modelCall describes a single request to a model provider
+type modelCall struct {
+	Prompt string
+	// JSONMode asks providers that support it to return a JSON object
+	JSONMode bool
+	// Temperature overrides the model's configured temperature when set
+	Temperature *float32
+	// Images are attached for models with Vision set
+	Images []promptImage
+	// Query and Fixture select the response in fixture mode: the query the
+	// call is about, and whether it asks for the answer or the critique
+	Query   string
+	Fixture string
+}
+
+// temperature returns the sampling temperature for the call
+func (c modelCall) temperature(modelConfig *AIModelConfig) float32 {
+	if c.Temperature != nil {
+		return *c.Temperature
 	}
-	
-	return false
+	return modelConfig.Temperature
 }
 
 // processWithModel sends the prompt to the AI model and gets a response
 func (s *AIService) processWithModel(ctx context.Context, prompt string, modelConfig *AIModelConfig) (string, error) {
+	return s.callModel(ctx, modelCall{Prompt: prompt}, modelConfig)
+}
+
+// callModel sends a request to the model's provider and returns the text of
+// its response
+func (s *AIService) callModel(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
 	// Implement actual API calls based on modelConfig.Provider
 	log.Printf("Processing query with %s model", modelConfig.Name)
 	
@@ -321,36 +475,71 @@ func (s *AIService) processWithModel(ctx context.Context, prompt string, modelCo
 	default:
 		// Continue processing
 	}
-	
+
+	// Fixture mode never reaches providers
+	if s.fixtures != nil {
+		return s.fixtureResponse(call), nil
+	}
+
+	if s.budget != nil {
+		if fallback, exceeded := s.budget.Exceeded(ctx, modelConfig.Provider); exceeded {
+			return s.callOverBudget(ctx, call, modelConfig, fallback)
+		}
+	}
+	return s.callProvider(ctx, call, modelConfig)
+}
+
+// callProvider sends a request to the model's provider, recording the
+// outcome in the model's health and the tokens used
+func (s *AIService) callProvider(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
+	var response string
+	var err error
 	switch modelConfig.Provider {
 	case "Anthropic":
-		return s.callAnthropicAPI(ctx, prompt, modelConfig)
+		response, err = s.callAnthropicAPI(ctx, call, modelConfig)
 	case "Google":
-		return s.callGoogleAPI(ctx, prompt, modelConfig)
+		response, err = s.callGoogleAPI(ctx, call, modelConfig)
 	case "OpenAI":
-		return s.callOpenAIAPI(ctx, prompt, modelConfig)
+		response, err = s.callOpenAIAPI(ctx, call, modelConfig)
 	case "DeepSeek":
-		return s.callDeepSeekAPI(ctx, prompt, modelConfig)
+		response, err = s.callDeepSeekAPI(ctx, call, modelConfig)
 	default:
 		// Use mock response for testing/development
 		log.Printf("Using mock response for provider: %s", modelConfig.Provider)
-		return s.generateMockResponse(prompt), nil
+		return s.mockResponse(call), nil
+	}
+
+	// Cancellation says nothing about the provider's health
+	if ctx.Err() == nil {
+		s.health.record(modelConfig.Name, err)
+	}
+	if err == nil {
+		countTokens(ctx, call.Prompt, response)
+		if s.budget != nil && hasCredentials(modelConfig.Provider) {
+			s.budget.Charge(ctx, modelConfig.Provider, estimateTokens(call.Prompt), estimateTokens(response))
+		}
 	}
+	return response, err
+}
+
+// responseFormat is the OpenAI-compatible response_format request field
+type responseFormat struct {
+	Type string `json:"type"`
 }
 
 // Fix the callAnthropicAPI function
-func (s *AIService) callAnthropicAPI(ctx context.Context, prompt string, modelConfig *AIModelConfig) (string, error) {
+func (s *AIService) callAnthropicAPI(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
     // Get API configuration from environment
     apiKey := os.Getenv("ANTHROPIC_API_KEY")
     if apiKey == "" {
         log.Println("Warning: ANTHROPIC_API_KEY not set, using mock response")
-        return s.generateMockResponse(prompt), nil
+        return s.mockResponse(call), nil
     }
     
     // Prepare request
     type anthropicMessage struct {
-        Role    string `json:"role"`
-        Content string `json:"content"`
+        Role    string      `json:"role"`
+        Content interface{} `json:"content"` // A string, or content blocks with images
     }
     
     type anthropicRequest struct {
@@ -361,18 +550,15 @@ func (s *AIService) callAnthropicAPI(ctx context.Context, prompt string, modelCo
     }
     
     // Determine model name based on configuration
-    modelName := "claude-3-opus-20240229" // Default model
-    if modelConfig.Name == "Claude" {
-        modelName = "claude-3-opus-20240229"
-    }
+    modelName := providerModelID(modelConfig)
     
     request := anthropicRequest{
         Model: modelName,
         Messages: []anthropicMessage{
-            {Role: "user", Content: prompt},
+            {Role: "user", Content: anthropicContent(call)},
         },
         MaxTokens:   modelConfig.MaxTokens,
-        Temperature: modelConfig.Temperature,
+        Temperature: call.temperature(modelConfig),
     }
     
     // Marshal request to JSON
@@ -397,14 +583,14 @@ func (s *AIService) callAnthropicAPI(ctx context.Context, prompt string, modelCo
     // Make the request
     resp, err := client.Do(req)
     if err != nil {
-        return "", fmt.Errorf("error making request to Anthropic API: %w", err)
+        return "", fmt.Errorf("%w: error making request to Anthropic API: %w", ErrAIUnavailable, err)
     }
     defer resp.Body.Close()
     
     // Check response status
     if resp.StatusCode != http.StatusOK {
         bodyBytes, _ := io.ReadAll(resp.Body)
-        return "", fmt.Errorf("error response from Anthropic API (status %d): %s", resp.StatusCode, string(bodyBytes))
+        return "", providerStatusError("Anthropic", resp.StatusCode, bodyBytes)
     }
     
     // Parse response
@@ -441,41 +627,64 @@ func (s *AIService) callAnthropicAPI(ctx context.Context, prompt string, modelCo
 // This is the updated callGoogleAPI function to fix the API integration
 // Replace the existing function in backend/internal/services/ai.go
 
-func (s *AIService) callGoogleAPI(ctx context.Context, prompt string, modelConfig *AIModelConfig) (string, error) {
+func (s *AIService) callGoogleAPI(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
     // Get API configuration from environment
     apiKey := os.Getenv("GOOGLE_API_KEY")
     if apiKey == "" {
         log.Println("Warning: GOOGLE_API_KEY not set, using mock response")
-        return s.generateMockResponse(prompt), nil
+        return s.mockResponse(call), nil
     }
     
     // Prepare request - Using the correct structure for Gemini 2.0 API
+    type googleInlineData struct {
+        MimeType string `json:"mime_type"`
+        Data     string `json:"data"`
+    }
+    
     type googlePart struct {
-        Text string `json:"text"`
+        Text       string            `json:"text,omitempty"`
+        InlineData *googleInlineData `json:"inline_data,omitempty"`
     }
     
     type googleContent struct {
         Parts []googlePart `json:"parts"`
     }
     
+    type googleGenerationConfig struct {
+        ResponseMimeType string   `json:"responseMimeType,omitempty"`
+        Temperature      *float32 `json:"temperature,omitempty"`
+    }
+    
     type googleRequest struct {
-        Contents []googleContent `json:"contents"`
-        Model    string          `json:"model"`
+        Contents         []googleContent         `json:"contents"`
+        Model            string                  `json:"model"`
+        GenerationConfig *googleGenerationConfig `json:"generationConfig,omitempty"`
     }
     
     // The model identifier for Gemini 2.0 Flash
-    modelIdentifier := "gemini-2.0-flash"
+    modelIdentifier := providerModelID(modelConfig)
     
     request := googleRequest{
         Contents: []googleContent{
             {
                 Parts: []googlePart{
-                    {Text: prompt},
+                    {Text: call.Prompt},
                 },
             },
         },
         Model: modelIdentifier,
     }
+    for _, img := range call.Images {
+        request.Contents[0].Parts = append(request.Contents[0].Parts, googlePart{
+            InlineData: &googleInlineData{MimeType: img.MediaType, Data: img.base64Data()},
+        })
+    }
+    if call.JSONMode || call.Temperature != nil {
+        request.GenerationConfig = &googleGenerationConfig{Temperature: call.Temperature}
+        if call.JSONMode {
+            request.GenerationConfig.ResponseMimeType = "application/json"
+        }
+    }
     
     // Marshal request to JSON
     requestBody, err := json.Marshal(request)
@@ -498,17 +707,19 @@ func (s *AIService) callGoogleAPI(ctx context.Context, prompt string, modelConfi
     client := &http.Client{Timeout: 50 * time.Second}
     resp, err := client.Do(req)
     if err != nil {
-        return "", fmt.Errorf("error making request to Google API: %w", err)
+        return "", fmt.Errorf("%w: error making request to Google API: %w", ErrAIUnavailable, err)
     }
     defer resp.Body.Close()
     
-    // Print the full request body for debugging
-    log.Printf("Google API request body: %s", string(requestBody))
+    // Print the request body for debugging, unless it carries image data
+    if len(call.Images) == 0 {
+        log.Printf("Google API request body: %s", string(requestBody))
+    }
     
     // Check response status
     if resp.StatusCode != http.StatusOK {
         bodyBytes, _ := io.ReadAll(resp.Body)
-        return "", fmt.Errorf("error response from Google API (status %d): %s", resp.StatusCode, string(bodyBytes))
+        return "", providerStatusError("Google", resp.StatusCode, bodyBytes)
     }
     
     // Parse response for the Gemini 2.0 structure
@@ -543,39 +754,43 @@ func (s *AIService) callGoogleAPI(ctx context.Context, prompt string, modelConfi
 
 
 // callOpenAIAPI makes API calls to OpenAI models
-func (s *AIService) callOpenAIAPI(ctx context.Context, prompt string, modelConfig *AIModelConfig) (string, error) {
+func (s *AIService) callOpenAIAPI(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
 	// Get API configuration from environment
 	apiKey := os.Getenv("OPENAI_API_KEY")
 	if apiKey == "" {
 		log.Println("Warning: OPENAI_API_KEY not set, using mock response")
-		return s.generateMockResponse(prompt), nil
+		return s.mockResponse(call), nil
 	}
 	
 	// Prepare request
 	type openaiMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
+		Role    string      `json:"role"`
+		Content interface{} `json:"content"` // A string, or content parts with images
 	}
 	
 	type openaiRequest struct {
-		Model       string          `json:"model"`
-		Messages    []openaiMessage `json:"messages"`
-		Temperature float32         `json:"temperature"`
-		MaxTokens   int             `json:"max_tokens"`
+		Model          string          `json:"model"`
+		Messages       []openaiMessage `json:"messages"`
+		Temperature    float32         `json:"temperature"`
+		MaxTokens      int             `json:"max_tokens"`
+		ResponseFormat *responseFormat `json:"response_format,omitempty"`
 	}
 	
 	// Determine model name based on configuration
-	modelName := "gpt-4" // Default model
+	modelName := providerModelID(modelConfig)
 	
 	request := openaiRequest{
 		Model: modelName,
 		Messages: []openaiMessage{
 			{Role: "system", Content: "You are a helpful assistant that analyzes Reddit search results."},
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: openAIContent(call)},
 		},
-		Temperature: modelConfig.Temperature,
+		Temperature: call.temperature(modelConfig),
 		MaxTokens:   modelConfig.MaxTokens,
 	}
+	if call.JSONMode {
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
 	
 	// Marshal request to JSON
 	requestBody, err := json.Marshal(request)
@@ -597,14 +812,14 @@ func (s *AIService) callOpenAIAPI(ctx context.Context, prompt string, modelConfi
 	client := &http.Client{Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error making request to OpenAI API: %w", err)
+		return "", fmt.Errorf("%w: error making request to OpenAI API: %w", ErrAIUnavailable, err)
 	}
 	defer resp.Body.Close()
 	
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("error response from OpenAI API (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return "", providerStatusError("OpenAI", resp.StatusCode, bodyBytes)
 	}
 	
 	// Parse response
@@ -634,12 +849,12 @@ func (s *AIService) callOpenAIAPI(ctx context.Context, prompt string, modelConfi
 }
 
 // callDeepSeekAPI makes API calls to DeepSeek models
-func (s *AIService) callDeepSeekAPI(ctx context.Context, prompt string, modelConfig *AIModelConfig) (string, error) {
+func (s *AIService) callDeepSeekAPI(ctx context.Context, call modelCall, modelConfig *AIModelConfig) (string, error) {
 	// Get API configuration from environment
 	apiKey := os.Getenv("DEEPSEEK_API_KEY")
 	if apiKey == "" {
 		log.Println("Warning: DEEPSEEK_API_KEY not set, using mock response")
-		return s.generateMockResponse(prompt), nil
+		return s.mockResponse(call), nil
 	}
 	
 	// Prepare request
@@ -649,23 +864,27 @@ func (s *AIService) callDeepSeekAPI(ctx context.Context, prompt string, modelCon
 	}
 	
 	type deepseekRequest struct {
-		Model       string            `json:"model"`
-		Messages    []deepseekMessage `json:"messages"`
-		Temperature float32           `json:"temperature"`
-		MaxTokens   int               `json:"max_tokens"`
+		Model          string            `json:"model"`
+		Messages       []deepseekMessage `json:"messages"`
+		Temperature    float32           `json:"temperature"`
+		MaxTokens      int               `json:"max_tokens"`
+		ResponseFormat *responseFormat   `json:"response_format,omitempty"`
 	}
 	
 	// Determine model name based on configuration
-	modelName := "deepseek-chat" // Default model
+	modelName := providerModelID(modelConfig)
 	
 	request := deepseekRequest{
 		Model: modelName,
 		Messages: []deepseekMessage{
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: call.Prompt},
 		},
-		Temperature: modelConfig.Temperature,
+		Temperature: call.temperature(modelConfig),
 		MaxTokens:   modelConfig.MaxTokens,
 	}
+	if call.JSONMode {
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
 	
 	// Marshal request to JSON
 	requestBody, err := json.Marshal(request)
@@ -687,14 +906,14 @@ func (s *AIService) callDeepSeekAPI(ctx context.Context, prompt string, modelCon
 	client := &http.Client{Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error making request to DeepSeek API: %w", err)
+		return "", fmt.Errorf("%w: error making request to DeepSeek API: %w", ErrAIUnavailable, err)
 	}
 	defer resp.Body.Close()
 	
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("error response from DeepSeek API (status %d): %s", resp.StatusCode, string(bodyBytes))
+		return "", providerStatusError("DeepSeek", resp.StatusCode, bodyBytes)
 	}
 	
 	// Parse response
@@ -723,6 +942,29 @@ func (s *AIService) callDeepSeekAPI(ctx context.Context, prompt string, modelCon
 	return resultText, nil
 }
 
+// mockResponse returns a canned response in the format the call asked for
+func (s *AIService) mockResponse(call modelCall) string {
+	if call.Fixture == fixtureComparison {
+		return generateMockComparisonResponse()
+	}
+	if call.JSONMode {
+		return generateMockJSONResponse()
+	}
+	return s.generateMockResponse(call.Prompt)
+}
+
+// generateMockJSONResponse creates a structured response for testing models
+// that run in JSON mode
+func generateMockJSONResponse() string {
+	return `{
+  "reasoning_steps": [
+    {"title": "Evaluating sources", "content": "Results [1] and [2] provide detailed first-hand explanations, while [3] is more anecdotal."},
+    {"title": "Identifying consensus", "content": "Most discussions agree on the main points, with minor disagreements in [3]."}
+  ],
+  "answer": "# Analysis of Reddit Discussions\n\nThe most common view, supported by multiple discussions [1][2], is that this topic has significant implications for most users. Some users note that results vary with individual circumstances [3]."
+}`
+}
+
 // generateMockResponse creates a realistic looking AI response for testing
 func (s *AIService) generateMockResponse(prompt string) string {
 	// This is a simplified mock that returns a formatted response
@@ -757,52 +999,6 @@ In summary, while there's general agreement on the basic principles, individual
 END_ANSWER`
 }
 
-// formatTimeAgo formats a time as a human-readable "time ago" string
-func formatTimeAgo(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
-	
-	switch {
-	case diff < time.Minute:
-		return "just now"
-	case diff < time.Hour:
-		minutes := int(diff.Minutes())
-		if minutes == 1 {
-			return "1 minute ago"
-		}
-		return fmt.Sprintf("%d minutes ago", minutes)
-	case diff < 24*time.Hour:
-		hours := int(diff.Hours())
-		if hours == 1 {
-			return "1 hour ago"
-		}
-		return fmt.Sprintf("%d hours ago", hours)
-	case diff < 48*time.Hour:
-		return "yesterday"
-	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
-		return fmt.Sprintf("%d days ago", days)
-	case diff < 30*24*time.Hour:
-		weeks := int(diff.Hours() / 24 / 7)
-		if weeks == 1 {
-			return "1 week ago"
-		}
-		return fmt.Sprintf("%d weeks ago", weeks)
-	case diff < 365*24*time.Hour:
-		months := int(diff.Hours() / 24 / 30)
-		if months == 1 {
-			return "1 month ago"
-		}
-		return fmt.Sprintf("%d months ago", months)
-	default:
-		years := int(diff.Hours() / 24 / 365)
-		if years == 1 {
-			return "1 year ago"
-		}
-		return fmt.Sprintf("%d years ago", years)
-	}
-}
-
 // Helper function for finding minimum of two integers
 func min(a, b int) int {
 	if a < b {
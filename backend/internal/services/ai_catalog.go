@@ -0,0 +1,167 @@
+// File: backend/internal/services/ai_catalog.go
+
+package services
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// downAfterFailures is how many consecutive failures mark a model as down
+const downAfterFailures = 3
+
+// maxHealthErrorLength caps provider error messages exposed in health reports
+const maxHealthErrorLength = 200
+
+// providerCredentialEnv names the environment variable holding each
+// provider's API key
+var providerCredentialEnv = map[string]string{
+	"Anthropic": "ANTHROPIC_API_KEY",
+	"Google":    "GOOGLE_API_KEY",
+	"OpenAI":    "OPENAI_API_KEY",
+	"DeepSeek":  "DEEPSEEK_API_KEY",
+}
+
+// defaultModelIDs is the provider model used when a configuration doesn't
+// set ModelID
+var defaultModelIDs = map[string]string{
+	"Anthropic": "claude-3-opus-20240229",
+	"Google":    "gemini-2.0-flash",
+	"OpenAI":    "gpt-4",
+	"DeepSeek":  "deepseek-chat",
+}
+
+// providerModelID returns the provider model identifier for a configuration
+func providerModelID(modelConfig *AIModelConfig) string {
+	if modelConfig.ModelID != "" {
+		return modelConfig.ModelID
+	}
+	return defaultModelIDs[modelConfig.Provider]
+}
+
+// hasCredentials reports whether the provider's API key is set
+func hasCredentials(provider string) bool {
+	env, ok := providerCredentialEnv[provider]
+	return ok && os.Getenv(env) != ""
+}
+
+// redactCredentials removes provider API keys from text. Some providers take
+// the key as a URL parameter, so transport errors can contain it.
+func redactCredentials(text string) string {
+	for _, env := range providerCredentialEnv {
+		if key := os.Getenv(env); key != "" {
+			text = strings.ReplaceAll(text, key, "[redacted]")
+		}
+	}
+	return text
+}
+
+// Catalog lists the selectable models with their limits, credential state
+// and recent health, sorted by name
+func (s *AIService) Catalog() models.ModelCatalog {
+	catalog := models.ModelCatalog{DefaultModel: s.defaultModel}
+
+	for key, modelConfig := range s.modelConfig {
+		// The fallback configuration isn't selectable
+		if key == "default" {
+			continue
+		}
+
+		info := models.ModelInfo{
+			Name:        modelConfig.Name,
+			DisplayName: modelConfig.DisplayName,
+			Provider:    modelConfig.Provider,
+			ModelID:     providerModelID(modelConfig),
+			Mode:        models.ModelModeMock,
+			Default:     modelConfig.Name == s.defaultModel,
+			MaxTokens:   modelConfig.MaxTokens,
+			TokenLimit:  modelConfig.TokenLimit,
+			JSONMode:    modelConfig.JSONMode,
+			Vision:      modelConfig.Vision,
+			Health:      s.health.snapshot(modelConfig.Name),
+		}
+		if info.DisplayName == "" {
+			info.DisplayName = modelConfig.Name
+		}
+		if hasCredentials(modelConfig.Provider) {
+			info.Mode = models.ModelModeLive
+		}
+
+		catalog.Models = append(catalog.Models, info)
+	}
+
+	sort.Slice(catalog.Models, func(i, j int) bool {
+		return catalog.Models[i].Name < catalog.Models[j].Name
+	})
+	return catalog
+}
+
+// PromptResultLimit returns how many of the results the named model is given
+// in its prompt, or 0 if it's given all of them. Unknown names get the
+// default model's limit, as when answering.
+func (s *AIService) PromptResultLimit(modelName string) int {
+	modelConfig, ok := s.modelConfig[modelName]
+	if !ok {
+		modelConfig = s.modelConfig[s.defaultModel]
+	}
+	if modelConfig == nil || modelConfig.MaxResultsInPrompt < 0 {
+		return 0
+	}
+	return modelConfig.MaxResultsInPrompt
+}
+
+// modelHealthTracker records the outcome of provider calls per model
+type modelHealthTracker struct {
+	mu     sync.Mutex
+	models map[string]*models.ModelHealth
+}
+
+// newModelHealthTracker creates an empty tracker
+func newModelHealthTracker() *modelHealthTracker {
+	return &modelHealthTracker{models: make(map[string]*models.ModelHealth)}
+}
+
+// record notes the outcome of a call to the named model
+func (t *modelHealthTracker) record(name string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.models[name]
+	if !ok {
+		health = &models.ModelHealth{}
+		t.models[name] = health
+	}
+
+	now := time.Now().Unix()
+	if err == nil {
+		health.Status = models.ModelHealthOK
+		health.LastSuccess = now
+		health.ConsecutiveFailures = 0
+		return
+	}
+
+	health.LastFailure = now
+	health.LastError = utils.TruncateWithEllipsis(redactCredentials(err.Error()), maxHealthErrorLength)
+	health.ConsecutiveFailures++
+	health.Status = models.ModelHealthDegraded
+	if health.ConsecutiveFailures >= downAfterFailures {
+		health.Status = models.ModelHealthDown
+	}
+}
+
+// snapshot returns a copy of the named model's health
+func (t *modelHealthTracker) snapshot(name string) models.ModelHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if health, ok := t.models[name]; ok {
+		return *health
+	}
+	return models.ModelHealth{Status: models.ModelHealthUnknown}
+}
@@ -0,0 +1,157 @@
+// File: backend/internal/services/ai_comparison.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// compareOptions asks the comparison model to lay the answer to a comparison
+// query out as criteria by option. The prose answer stands on its own, so
+// failures are only logged and leave the comparison unset.
+func (s *AIService) compareOptions(ctx context.Context, query string, options []string, answer string, results []models.SearchResult, answerModel *AIModelConfig, opts AnswerOptions) *models.Comparison {
+	if strings.TrimSpace(answer) == "" || len(results) == 0 {
+		return nil
+	}
+
+	comparison, err := s.buildComparison(ctx, query, options, answer, results, answerModel, opts)
+	if err != nil {
+		log.Printf("Comparison table for '%s' failed: %v", query, err)
+		return nil
+	}
+	return comparison
+}
+
+// buildComparison runs the comparison model over the results the answer was
+// based on and parses its table
+func (s *AIService) buildComparison(ctx context.Context, query string, options []string, answer string, results []models.SearchResult, answerModel *AIModelConfig, opts AnswerOptions) (*models.Comparison, error) {
+	tmpl := s.prompts.Get(s.comparisonModel.PromptTemplate)
+	if tmpl == nil || tmpl.Name() != s.comparisonModel.PromptTemplate {
+		return nil, fmt.Errorf("prompt template '%s' not found", s.comparisonModel.PromptTemplate)
+	}
+
+	// Citations must number results the way the answer does
+	resultLimit := answerModel.MaxResultsInPrompt
+	if resultLimit <= 0 || resultLimit > len(results) {
+		resultLimit = len(results)
+	}
+
+	var resultsText strings.Builder
+	for i, result := range results[:resultLimit] {
+		resultsText.WriteString(formatResultForPrompt(i+1, result, s.contentLength(result, answerModel, opts), opts.Locale))
+	}
+
+	var prompt strings.Builder
+	err := tmpl.Execute(&prompt, promptData{
+		Query:            query,
+		Results:          resultsText.String(),
+		ResultCount:      resultLimit,
+		TotalResultCount: len(results),
+		Answer:           answer,
+		Options:          options,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rendering comparison prompt: %w", err)
+	}
+
+	call := modelCall{Prompt: prompt.String(), JSONMode: true, Query: query, Fixture: fixtureComparison}
+	response, err := s.callModel(ctx, call, s.comparisonModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseComparison(response, options, resultLimit)
+}
+
+// comparisonResponse is the JSON shape requested from the comparison model.
+// Evidence is positional, so option names the model respells still line up.
+type comparisonResponse struct {
+	Criteria []struct {
+		Name     string `json:"name"`
+		Evidence []struct {
+			Summary   string `json:"summary"`
+			Citations []int  `json:"citations"`
+		} `json:"evidence"`
+	} `json:"criteria"`
+	Verdict string `json:"verdict"`
+}
+
+// parseComparison decodes a comparison model response. Each criterion gets
+// exactly one evidence entry per option, citations outside the results the
+// model saw are dropped, and criteria with no evidence at all are left out.
+func parseComparison(response string, options []string, resultCount int) (*models.Comparison, error) {
+	// Models sometimes wrap the object in a code fence or a sentence
+	start, end := strings.Index(response, "{"), strings.LastIndex(response, "}")
+	if start == -1 || end < start {
+		return nil, errors.New("comparison response has no JSON object")
+	}
+
+	var parsed comparisonResponse
+	if err := json.Unmarshal([]byte(response[start:end+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid comparison JSON: %w", err)
+	}
+
+	comparison := &models.Comparison{
+		Options: options,
+		Verdict: strings.TrimSpace(parsed.Verdict),
+	}
+	for _, criterion := range parsed.Criteria {
+		row := models.ComparisonCriterion{
+			Name:     strings.TrimSpace(criterion.Name),
+			Evidence: make([]models.ComparisonEvidence, len(options)),
+		}
+		covered := false
+		for i := range row.Evidence {
+			if i >= len(criterion.Evidence) {
+				break
+			}
+			evidence := &row.Evidence[i]
+			evidence.Summary = strings.TrimSpace(criterion.Evidence[i].Summary)
+			seen := make(map[int]bool)
+			for _, index := range criterion.Evidence[i].Citations {
+				if index < 1 || index > resultCount || seen[index] {
+					continue
+				}
+				seen[index] = true
+				evidence.Citations = append(evidence.Citations, index)
+			}
+			if evidence.Summary != "" {
+				covered = true
+			}
+		}
+		if row.Name == "" || !covered {
+			continue
+		}
+		comparison.Criteria = append(comparison.Criteria, row)
+	}
+
+	if len(comparison.Criteria) == 0 {
+		return nil, errors.New("comparison response has no criteria")
+	}
+	return comparison, nil
+}
+
+// generateMockComparisonResponse creates a comparison table for testing,
+// covering two options
+func generateMockComparisonResponse() string {
+	return `{
+  "criteria": [
+    {"name": "Community sentiment", "evidence": [
+      {"summary": "Most commenters recommend it for everyday use.", "citations": [1]},
+      {"summary": "Preferred by users with more specialised needs.", "citations": [2]}
+    ]},
+    {"name": "Value", "evidence": [
+      {"summary": "Considered good value, especially on sale.", "citations": [1, 3]},
+      {"summary": "", "citations": []}
+    ]}
+  ],
+  "verdict": "Both are well regarded; the first is the more common recommendation [1]."
+}`
+}
@@ -0,0 +1,150 @@
+// File: backend/internal/services/ai_consistency.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+const (
+	// consistencySamples is how many answers are sampled in self-consistency mode
+	consistencySamples = 3
+	// consistencyTemperature is the sampling temperature, higher than usual so
+	// the samples explore different readings of the results
+	consistencyTemperature float32 = 1.0
+	// consensusThreshold is the mean pairwise agreement at which the samples
+	// are considered to agree
+	consensusThreshold = 0.5
+)
+
+// selfConsistentAnswer samples several answers concurrently and returns the
+// one that agrees most with the others. When the samples disagree the other
+// answers are returned as alternatives and a warning is added.
+func (s *AIService) selfConsistentAnswer(ctx context.Context, query string, call modelCall, results []models.SearchResult, modelConfig *AIModelConfig) (*AnswerResult, error) {
+	temperature := consistencyTemperature
+	call.Temperature = &temperature
+
+	samples := make([]*AnswerResult, consistencySamples)
+	errs := make([]error, consistencySamples)
+	var wg sync.WaitGroup
+	for i := range samples {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			samples[i], errs[i] = s.generateAnswer(ctx, query, call, results, modelConfig)
+		}(i)
+	}
+	wg.Wait()
+
+	var answers []*AnswerResult
+	for i, sample := range samples {
+		if errs[i] != nil {
+			log.Printf("Self-consistency sample %d failed: %v", i+1, errs[i])
+			continue
+		}
+		answers = append(answers, sample)
+	}
+
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("all %d self-consistency samples failed: %w", consistencySamples, errors.Join(errs...))
+	}
+	if len(answers) == 1 {
+		result := answers[0]
+		result.Consistency = &models.ConsistencyReport{Samples: 1}
+		result.Warnings = append(result.Warnings, "Only one answer could be sampled, so its consistency could not be checked.")
+		return result, nil
+	}
+
+	best, agreement := selectConsistentAnswer(answers)
+	result := answers[best]
+	report := &models.ConsistencyReport{
+		Samples:   len(answers),
+		Agreement: agreement,
+		Consensus: agreement >= consensusThreshold,
+	}
+
+	if !report.Consensus {
+		for i, answer := range answers {
+			if i != best {
+				report.Alternatives = append(report.Alternatives, answer.Answer)
+			}
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"Sampled answers disagreed (agreement %.2f). Reddit opinion on this may be divided; see the alternative answers.", agreement))
+	}
+
+	log.Printf("Self-consistency for '%s': %d samples, agreement %.2f", query, len(answers), agreement)
+
+	result.Consistency = report
+	return result, nil
+}
+
+// selectConsistentAnswer returns the index of the answer with the highest
+// total agreement with the others, and the mean pairwise agreement
+func selectConsistentAnswer(answers []*AnswerResult) (int, float64) {
+	totals := make([]float64, len(answers))
+	var sum float64
+	pairs := 0
+
+	for i := 0; i < len(answers); i++ {
+		for j := i + 1; j < len(answers); j++ {
+			score := answerAgreement(answers[i], answers[j])
+			totals[i] += score
+			totals[j] += score
+			sum += score
+			pairs++
+		}
+	}
+
+	best := 0
+	for i, total := range totals {
+		if total > totals[best] {
+			best = i
+		}
+	}
+
+	return best, sum / float64(pairs)
+}
+
+// answerAgreement scores two answers from 0 to 1, weighing both what they say
+// (word overlap) and which results they rely on (shared citations)
+func answerAgreement(a, b *AnswerResult) float64 {
+	textScore := calculateSimilarity(a.Answer, b.Answer)
+	return (textScore + citationOverlap(a.Citations, b.Citations)) / 2
+}
+
+// citationOverlap is the Jaccard similarity of two sets of cited results.
+// Two answers citing nothing are treated as agreeing.
+func citationOverlap(a, b []models.Citation) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	setA := make(map[int]bool)
+	for _, citation := range a {
+		setA[citation.Index] = true
+	}
+
+	union := len(setA)
+	intersection := 0
+	seen := make(map[int]bool)
+	for _, citation := range b {
+		if seen[citation.Index] {
+			continue
+		}
+		seen[citation.Index] = true
+		if setA[citation.Index] {
+			intersection++
+		} else {
+			union++
+		}
+	}
+
+	return float64(intersection) / float64(union)
+}
@@ -0,0 +1,109 @@
+// File: backend/internal/services/ai_critic.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// verificationFailedWarning is surfaced when the critic pass itself fails, so
+// clients don't mistake an unchecked answer for a verified one
+const verificationFailedWarning = "The answer could not be cross-checked against the search results."
+
+// verifyAnswer asks the critic model to cross-check the answer against the
+// results and returns the statements it considers unsupported. The results
+// are limited the same way as for the model that wrote the answer, so the
+// critic sees exactly what the answer was based on.
+func (s *AIService) verifyAnswer(ctx context.Context, query, answer string, results []models.SearchResult, answerModel *AIModelConfig, opts AnswerOptions) []string {
+	unsupported, err := s.critiqueAnswer(ctx, query, answer, results, answerModel, opts)
+	if err != nil {
+		log.Printf("Answer verification failed: %v", err)
+		return []string{verificationFailedWarning}
+	}
+
+	if len(unsupported) > 0 {
+		log.Printf("Critic flagged %d unsupported statements for '%s'", len(unsupported), query)
+	}
+
+	var warnings []string
+	for _, statement := range unsupported {
+		warnings = append(warnings, "Unsupported statement: "+statement)
+	}
+	return warnings
+}
+
+// critiqueAnswer runs the critic model and parses its list of unsupported
+// statements
+func (s *AIService) critiqueAnswer(ctx context.Context, query, answer string, results []models.SearchResult, answerModel *AIModelConfig, opts AnswerOptions) ([]string, error) {
+	if strings.TrimSpace(answer) == "" {
+		return nil, nil
+	}
+
+	tmpl := s.prompts.Get(s.criticModel.PromptTemplate)
+	if tmpl == nil || tmpl.Name() != s.criticModel.PromptTemplate {
+		return nil, fmt.Errorf("prompt template '%s' not found", s.criticModel.PromptTemplate)
+	}
+
+	resultLimit := answerModel.MaxResultsInPrompt
+	if resultLimit <= 0 || resultLimit > len(results) {
+		resultLimit = len(results)
+	}
+
+	var resultsText strings.Builder
+	for i, result := range results[:resultLimit] {
+		resultsText.WriteString(formatResultForPrompt(i+1, result, s.contentLength(result, answerModel, opts), utils.DefaultLocale))
+	}
+
+	var prompt strings.Builder
+	err := tmpl.Execute(&prompt, promptData{
+		Query:            query,
+		Results:          resultsText.String(),
+		ResultCount:      resultLimit,
+		TotalResultCount: len(results),
+		Answer:           answer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error rendering critic prompt: %w", err)
+	}
+
+	response, err := s.callModel(ctx, modelCall{Prompt: prompt.String(), Query: query, Fixture: fixtureCritic}, s.criticModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUnsupportedStatements(response)
+}
+
+// parseUnsupportedStatements extracts the "- " lines between the
+// BEGIN_UNSUPPORTED and END_UNSUPPORTED markers of a critic response
+func parseUnsupportedStatements(response string) ([]string, error) {
+	start := strings.Index(response, "BEGIN_UNSUPPORTED")
+	end := strings.Index(response, "END_UNSUPPORTED")
+	if start == -1 || end == -1 || end < start {
+		return nil, errors.New("critic response is missing the unsupported statements section")
+	}
+
+	var statements []string
+	for _, line := range strings.Split(response[start+len("BEGIN_UNSUPPORTED"):end], "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+
+		statement := strings.TrimSpace(strings.TrimPrefix(line, "- "))
+		// Ignore the format placeholder if the model echoes it back
+		if statement == "" || strings.Contains(statement, "<statement>") {
+			continue
+		}
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
@@ -0,0 +1,129 @@
+// File: backend/internal/services/ai_fixtures.go
+
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AIModeFixture is the AI_MODE value that answers every model call from
+// fixture files instead of providers, so end-to-end tests and demos get the
+// same answers on every run
+const AIModeFixture = "fixture"
+
+// defaultAIFixturesDir is where fixture files are read from unless
+// AI_FIXTURES_DIR is set
+const defaultAIFixturesDir = "fixtures/ai"
+
+// What a model call is for, selecting the fixture response answering it
+const (
+	fixtureAnswer     = "answer"
+	fixtureCritic     = "critic"
+	fixtureComparison = "comparison"
+)
+
+// aiFixture is a fixture file, holding the raw model responses for one query
+type aiFixture struct {
+	Query string `json:"query"` // For readers; files are found by AIFixtureName
+	// Answer is the response to the answer prompt, in the format the model
+	// is configured for
+	Answer string `json:"answer"`
+	// Critic is the response to the verification prompt
+	Critic string `json:"critic,omitempty"`
+	// Comparison is the response to the comparison table prompt
+	Comparison string `json:"comparison,omitempty"`
+}
+
+// aiFixtures answers model calls from the fixture files in a directory
+type aiFixtures struct {
+	dir string
+}
+
+// AIFixtureName returns the name of the fixture file answering query: a
+// hash of the query, ignoring case and surrounding or repeated whitespace
+func AIFixtureName(query string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8]) + ".json"
+}
+
+// aiFixturesFromEnv returns the fixtures to answer from when AI_MODE is
+// "fixture", or nil
+func aiFixturesFromEnv() *aiFixtures {
+	if os.Getenv("AI_MODE") != AIModeFixture {
+		return nil
+	}
+	dir := os.Getenv("AI_FIXTURES_DIR")
+	if dir == "" {
+		dir = defaultAIFixturesDir
+	}
+	log.Printf("AI fixture mode: answering from fixtures in %s instead of AI providers", dir)
+	return &aiFixtures{dir: dir}
+}
+
+// fixtureResponse returns the fixture response to call. Calls without a
+// fixture, including those not tied to a query, get the built-in mock
+// response, so they are deterministic too.
+func (s *AIService) fixtureResponse(call modelCall) string {
+	if call.Query == "" || call.Fixture == "" {
+		return s.mockFixtureResponse(call)
+	}
+
+	name := AIFixtureName(call.Query)
+	fixture, err := s.fixtures.load(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			log.Printf("No AI fixture for '%s'; add %s to answer it", call.Query, filepath.Join(s.fixtures.dir, name))
+		} else {
+			log.Printf("Failed to load AI fixture for '%s': %v", call.Query, err)
+		}
+		return s.mockFixtureResponse(call)
+	}
+
+	response := fixture.Answer
+	switch call.Fixture {
+	case fixtureCritic:
+		response = fixture.Critic
+	case fixtureComparison:
+		response = fixture.Comparison
+	}
+	if response == "" {
+		log.Printf("AI fixture %s has no %s response", name, call.Fixture)
+		return s.mockFixtureResponse(call)
+	}
+	return response
+}
+
+// load reads a fixture file. Files are read on every call, so edits apply
+// without a restart.
+func (f *aiFixtures) load(name string) (*aiFixture, error) {
+	data, err := os.ReadFile(filepath.Join(f.dir, name))
+	if err != nil {
+		return nil, err
+	}
+	var fixture aiFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", name, err)
+	}
+	return &fixture, nil
+}
+
+// mockFixtureResponse returns the built-in response for calls without a
+// fixture. Critics find nothing unsupported.
+func (s *AIService) mockFixtureResponse(call modelCall) string {
+	switch call.Fixture {
+	case fixtureCritic:
+		return "BEGIN_UNSUPPORTED\nEND_UNSUPPORTED"
+	case fixtureComparison:
+		return generateMockComparisonResponse()
+	}
+	return s.mockResponse(call)
+}
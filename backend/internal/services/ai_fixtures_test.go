@@ -0,0 +1,70 @@
+// File: backend/internal/services/ai_fixtures_test.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestAIFixtureMode(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AI_MODE", AIModeFixture)
+	t.Setenv("AI_FIXTURES_DIR", dir)
+	// Fixture mode must not reach providers even with credentials
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+
+	query := "best mechanical keyboard for programming"
+	fixture, _ := json.Marshal(aiFixture{
+		Query:  query,
+		Answer: "BEGIN_REASONING\nResult [1] is first-hand.\nEND_REASONING\n\nBEGIN_ANSWER\nMost people recommend the Keychron Q1 [1].\nEND_ANSWER",
+		Critic: "BEGIN_UNSUPPORTED\n- The Keychron Q1 is the cheapest option\nEND_UNSUPPORTED",
+	})
+	if err := os.WriteFile(filepath.Join(dir, AIFixtureName(query)), fixture, 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	service := NewAIService()
+	results := []models.SearchResult{{ID: "kb1", Type: "post", Title: "Keychron Q1 after a year", Subreddit: "MechanicalKeyboards"}}
+
+	// The hash ignores case and extra whitespace
+	for _, q := range []string{query, "  Best mechanical  keyboard for PROGRAMMING "} {
+		result, err := service.ProcessResultsWithOptions(context.Background(), q, results, "Claude", AnswerOptions{Verify: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Answer != "Most people recommend the Keychron Q1 [1]." {
+			t.Errorf("Expected the fixture answer for %q, got %q", q, result.Answer)
+		}
+		if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "cheapest option") {
+			t.Errorf("Expected the fixture critique for %q, got %v", q, result.Warnings)
+		}
+	}
+
+	// Queries without a fixture get the built-in mock answer, every time
+	first, err := service.ProcessResultsWithOptions(context.Background(), "unrecorded question", results, "Claude", AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := service.ProcessResultsWithOptions(context.Background(), "unrecorded question", results, "Claude", AnswerOptions{})
+	if first.Answer == "" || first.Answer != second.Answer {
+		t.Errorf("Expected the same mock answer twice, got %q and %q", first.Answer, second.Answer)
+	}
+}
+
+func TestAIFixtureName(t *testing.T) {
+	name := AIFixtureName("best mechanical keyboard for programming")
+	if name != "78d71ebad6e7ff55.json" {
+		t.Errorf("Unexpected fixture name %s", name)
+	}
+	// The demo fixture must stay findable
+	if _, err := os.Stat(filepath.Join("..", "..", defaultAIFixturesDir, name)); err != nil {
+		t.Errorf("Expected a demo fixture: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 // AIModelConfig contains configuration for an AI model
 type AIModelConfig struct {
 	Name               string
+	DisplayName        string // Label shown in model pickers; empty uses Name
 	Provider           string // e.g., "OpenAI", "Anthropic"
 	PromptTemplate     string // Which prompt template to use
 	MaxTokens          int    // Maximum tokens for response
@@ -21,6 +22,9 @@ type AIModelConfig struct {
 	ResponseFormat     string // Expected response format
 	TokenLimit         int    // Total token limit for the model
 	QueryWeights       map[string]float32 // Weight different query complexities
+	ModelID            string // Provider model identifier; empty uses the provider default
+	JSONMode           bool   // Request a JSON response with typed reasoning steps (provider must support it)
+	Vision             bool   // Accepts images alongside the prompt
 }
 
 
@@ -33,6 +37,7 @@ func loadModelConfigurations() map[string]*AIModelConfig {
 			Name:               "Claude",
 			Provider:           "Anthropic",
 			PromptTemplate:     "claude",
+			Vision:             true,
 			MaxTokens:          2000,  // Reduced from 4000
 			MaxResultsInPrompt: 8,     // Reduced from 12
 			MaxContentLength:   800,   // Reduced from 1500
@@ -55,6 +60,7 @@ func loadModelConfigurations() map[string]*AIModelConfig {
 			Name:               "DeepSeek R1",
 			Provider:           "DeepSeek",
 			PromptTemplate:     "deepseek",
+			JSONMode:           true,
 			MaxTokens:          2000,
 			MaxResultsInPrompt: 5,
 			MaxContentLength:   800,
@@ -75,8 +81,11 @@ func loadModelConfigurations() map[string]*AIModelConfig {
 		},
 		"Google Gemini": {
 			Name:               "Google Gemini",
+			DisplayName:        "Gemini 2.0 Flash",
 			Provider:           "Google",
 			PromptTemplate:     "gemini",
+			JSONMode:           true,
+			Vision:             true,
 			MaxTokens:          2000,
 			MaxResultsInPrompt: 5,
 			MaxContentLength:   800,
@@ -95,6 +104,31 @@ func loadModelConfigurations() map[string]*AIModelConfig {
 				"subjective": 1.0,
 			},
 		},
+		"GPT-4o": {
+			Name:               "GPT-4o",
+			Provider:           "OpenAI",
+			PromptTemplate:     "default",
+			ModelID:            "gpt-4o",
+			JSONMode:           true,
+			Vision:             true,
+			MaxTokens:          2000,
+			MaxResultsInPrompt: 8,
+			MaxContentLength:   800,
+			Temperature:        0.7,
+			SectionMarkers: map[string]string{
+				"reasoning_start": "BEGIN_REASONING",
+				"reasoning_end":   "END_REASONING",
+				"answer_start":    "BEGIN_ANSWER",
+				"answer_end":      "END_ANSWER",
+			},
+			ResponseFormat: "markdown",
+			TokenLimit:     128000,
+			QueryWeights: map[string]float32{
+				"analytical": 1.1,
+				"technical":  1.1,
+				"subjective": 1.0,
+			},
+		},
 	}
 	
 	// Add a default configuration that will be used if model is not found
@@ -124,6 +158,42 @@ func loadModelConfigurations() map[string]*AIModelConfig {
 	return configs
 }
 
+// loadCriticModelConfiguration returns the configuration for the model that
+// cross-checks answers. It favours a cheap, fast model since it runs in
+// addition to the main answer.
+func loadCriticModelConfiguration() *AIModelConfig {
+	return &AIModelConfig{
+		Name:               "Critic",
+		Provider:           "Anthropic",
+		PromptTemplate:     "critic",
+		MaxTokens:          800,
+		MaxResultsInPrompt: 8,
+		MaxContentLength:   800,
+		Temperature:        0,
+		ResponseFormat:     "text",
+		TokenLimit:         100000,
+		ModelID:            "claude-3-haiku-20240307",
+	}
+}
+
+// loadComparisonModelConfiguration returns the configuration for the model
+// that lays comparison answers out as tables. Like the critic, it runs in
+// addition to the main answer, so it favours a cheap, fast model.
+func loadComparisonModelConfiguration() *AIModelConfig {
+	return &AIModelConfig{
+		Name:               "Comparison",
+		Provider:           "Anthropic",
+		PromptTemplate:     "comparison",
+		MaxTokens:          1500,
+		MaxResultsInPrompt: 8,
+		MaxContentLength:   800,
+		Temperature:        0,
+		ResponseFormat:     "json",
+		TokenLimit:         100000,
+		ModelID:            "claude-3-haiku-20240307",
+	}
+}
+
 // SelectModelForQuery determines the best model based on query and results
 func SelectModelForQuery(query string, results []models.SearchResult, availableModels map[string]*AIModelConfig) *AIModelConfig {
 	// If only one model available, use it
@@ -3,13 +3,104 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"regexp"
 	"strings"
 
 	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
 )
 
+// answerHeaderRegex matches headers that introduce the answer itself rather
+// than a reasoning step
+var answerHeaderRegex = regexp.MustCompile(`(?i)^(?:final\s+)?(?:answer|conclusions?)\s*:?$`)
+
+// parseModelResponse splits a model response into reasoning, answer and
+// reasoning steps. Models in JSON mode return typed steps directly; if that
+// output is malformed, or for text models, the marker and regex parsing
+// pipeline is used instead.
+func (s *AIService) parseModelResponse(response string, modelConfig *AIModelConfig) (string, string, []models.ReasoningStep) {
+	if modelConfig.JSONMode {
+		steps, answer, err := parseStructuredResponse(response)
+		if err == nil {
+			return formatReasoningSteps(steps), answer, steps
+		}
+		log.Printf("Structured response parsing failed, falling back to text parsing: %v", err)
+	}
+
+	// Extract reasoning and answer
+	reasoning, answer, err := s.extractReasoningAndAnswer(response, modelConfig)
+	if err != nil {
+		log.Printf("Extraction error: %v, attempting fallback parsing", err)
+		// Try fallback parsing if standard extraction fails
+		reasoning, answer = s.fallbackParsing(response)
+		
+		if reasoning == "" && answer == "" {
+			// If still no success, use raw response
+			log.Printf("Fallback parsing failed, returning raw response")
+			reasoning = "Error parsing structured response."
+			answer = cleanupRawResponse(response)
+		}
+	}
+
+	return reasoning, answer, s.extractReasoningSteps(reasoning)
+}
+
+// structuredResponse is the JSON shape requested from models in JSON mode
+type structuredResponse struct {
+	ReasoningSteps []models.ReasoningStep `json:"reasoning_steps"`
+	Answer         string                 `json:"answer"`
+}
+
+// parseStructuredResponse decodes a JSON mode response
+func parseStructuredResponse(response string) ([]models.ReasoningStep, string, error) {
+	// Some models wrap JSON in a code fence even in JSON mode
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+
+	var parsed structuredResponse
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		return nil, "", fmt.Errorf("invalid JSON response: %w", err)
+	}
+
+	answer := strings.TrimSpace(parsed.Answer)
+	if answer == "" {
+		return nil, "", errors.New("JSON response has no answer")
+	}
+
+	var steps []models.ReasoningStep
+	for _, step := range parsed.ReasoningSteps {
+		step.Title = strings.TrimSpace(step.Title)
+		step.Content = strings.TrimSpace(step.Content)
+		if step.Content == "" {
+			continue
+		}
+		if step.Title == "" {
+			step.Title = generateStepTitle(step.Content, len(steps)+1)
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, answer, nil
+}
+
+// formatReasoningSteps renders typed steps as markdown reasoning text
+func formatReasoningSteps(steps []models.ReasoningStep) string {
+	var builder strings.Builder
+	for i, step := range steps {
+		if i > 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString(fmt.Sprintf("## %s\n\n%s", step.Title, step.Content))
+	}
+	return builder.String()
+}
+
 // extractReasoningAndAnswer extracts the reasoning and answer sections from the AI response
 func (s *AIService) extractReasoningAndAnswer(response string, modelConfig *AIModelConfig) (string, string, error) {
 	// Get the section markers for the model
@@ -143,9 +234,8 @@ func (s *AIService) extractReasoningSteps(reasoning string) []models.ReasoningSt
 			header = strings.TrimSpace(header)
 			content = strings.TrimSpace(content)
 			
-			// Skip if this seems to be the start of an answer section
-			if strings.Contains(strings.ToLower(header), "answer") ||
-			   strings.Contains(strings.ToLower(header), "conclusion") {
+			// Skip if this is the start of an answer section
+			if answerHeaderRegex.MatchString(header) {
 				continue
 			}
 			
@@ -211,9 +301,7 @@ func generateStepTitle(content string, stepNumber int) string {
 		firstSentence := sentences[0]
 		
 		// Trim to a reasonable length
-		if len(firstSentence) > 50 {
-			firstSentence = firstSentence[:47] + "..."
-		}
+		firstSentence = utils.TruncateWithEllipsis(firstSentence, 50)
 		
 		// Add step number
 		return fmt.Sprintf("Step %d: %s", stepNumber, firstSentence)
@@ -279,6 +367,7 @@ func (s *AIService) extractCitations(answer string, results []models.SearchResul
 			Title:     result.Title,
 			Type:      result.Type,
 			Subreddit: result.Subreddit,
+			Archived:  result.Archived,
 		}
 		
 		citations = append(citations, citation)
@@ -336,17 +425,7 @@ func extractCitationContext(text string, index int) string {
 	sentence = strings.TrimSpace(sentence)
 	
 	// Truncate if too long
-	if len(sentence) > 200 {
-		// Try to find a clean breakpoint
-		breakPoint := 197
-		for i := 197; i >= 170; i-- {
-			if sentence[i] == ' ' || sentence[i] == ',' {
-				breakPoint = i
-				break
-			}
-		}
-		sentence = sentence[:breakPoint] + "..."
-	}
+	sentence = utils.TruncateWithEllipsis(sentence, 200)
 	
 	return sentence
 }
\ No newline at end of file
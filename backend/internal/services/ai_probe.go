@@ -0,0 +1,97 @@
+// File: backend/internal/services/ai_probe.go
+
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// probePrompt is the tiny request sent to check a model
+const probePrompt = "Reply with the single word OK."
+
+// probeMaxTokens caps probe responses
+const probeMaxTokens = 5
+
+// RunProbes probes the configured models now and then every interval, until
+// ctx is cancelled
+func (s *AIService) RunProbes(ctx context.Context, cfg config.AIProbesConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.ProbeModels(ctx, cfg.Timeout)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeModels sends a tiny request to every configured model whose provider
+// has an API key, concurrently, recording the outcomes in the models'
+// health. Models without credentials answer with mock responses, so probing
+// them would say nothing. Nothing is probed in fixture mode.
+func (s *AIService) ProbeModels(ctx context.Context, timeout time.Duration) {
+	if s.fixtures != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for key, modelConfig := range s.modelConfig {
+		if key == "default" || !hasCredentials(modelConfig.Provider) {
+			continue
+		}
+
+		probe := *modelConfig
+		probe.MaxTokens = probeMaxTokens
+		probe.JSONMode = false
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			before := s.health.snapshot(probe.Name).Status
+			if _, err := s.callProvider(probeCtx, modelCall{Prompt: probePrompt}, &probe); err != nil {
+				log.Printf("Probe of %s failed: %v", probe.Name, redactCredentials(err.Error()))
+			}
+			if after := s.health.snapshot(probe.Name).Status; after != before {
+				switch {
+				case after == models.ModelHealthDown:
+					log.Printf("Model %s is down; leaving it out of model selection", probe.Name)
+				case before == models.ModelHealthDown:
+					log.Printf("Model %s recovered", probe.Name)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// SelectModel picks the best model for a query, as SelectModelForQuery
+// does, from the models that aren't down
+func (s *AIService) SelectModel(query string, results []models.SearchResult) *AIModelConfig {
+	return SelectModelForQuery(query, results, s.healthyModels())
+}
+
+// healthyModels returns the configured models, except those whose recent
+// calls or probes failed repeatedly. The fallback configuration is always
+// included.
+func (s *AIService) healthyModels() map[string]*AIModelConfig {
+	healthy := make(map[string]*AIModelConfig, len(s.modelConfig))
+	for key, modelConfig := range s.modelConfig {
+		if key != "default" && s.health.snapshot(modelConfig.Name).Status == models.ModelHealthDown {
+			continue
+		}
+		healthy[key] = modelConfig
+	}
+	return healthy
+}
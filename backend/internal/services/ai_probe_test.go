@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestSelectModelSkipsDownModels(t *testing.T) {
+	service := NewAIService()
+	query := "explain the difference between these frameworks"
+
+	best := service.SelectModel(query, nil)
+	if best == nil {
+		t.Fatal("Expected a model to be selected")
+	}
+
+	// Repeated failures take the model out of selection until it recovers
+	for i := 0; i < downAfterFailures; i++ {
+		service.health.record(best.Name, errors.New("provider unavailable"))
+	}
+	if got := service.SelectModel(query, nil); got == nil || got.Name == best.Name {
+		t.Errorf("Expected a model other than the down %s, got %v", best.Name, got)
+	}
+
+	// Models can tie on score, so check the recovered model is eligible again
+	// rather than that it wins
+	service.health.record(best.Name, nil)
+	eligible := false
+	for _, modelConfig := range service.healthyModels() {
+		if modelConfig.Name == best.Name {
+			eligible = true
+		}
+	}
+	if !eligible {
+		t.Errorf("Expected the recovered %s to be selectable again", best.Name)
+	}
+}
+
+func TestProbeModelsSkipsMissingCredentials(t *testing.T) {
+	for _, env := range providerCredentialEnv {
+		t.Setenv(env, "")
+	}
+	service := NewAIService()
+
+	service.ProbeModels(context.Background(), time.Second)
+	for _, info := range service.Catalog().Models {
+		if info.Health.Status != models.ModelHealthUnknown {
+			t.Errorf("Expected %s not to be probed without an API key, got %+v", info.Name, info.Health)
+		}
+	}
+}
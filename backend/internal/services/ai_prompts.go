@@ -4,207 +4,21 @@ package services
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/pranesh-j/subplexity/internal/config"
 	"github.com/pranesh-j/subplexity/internal/models"
 	"github.com/pranesh-j/subplexity/internal/utils"
 )
 
-// loadPromptTemplates returns the templates for AI prompts
-func loadPromptTemplates() map[string]string {
-	templates := make(map[string]string)
-	
-	// Default template (used as fallback) - Enhanced to be domain-agnostic
-	templates["default"] = `You are an AI assistant specialized in analyzing Reddit content to answer user queries. You provide unbiased, balanced answers based only on the search results provided.
-
-USER QUERY: {{QUERY}}
-
-Below are {{RESULT_COUNT}} relevant search results from Reddit (out of {{TOTAL_RESULT_COUNT}} total results).
-Please analyze these results and provide a comprehensive answer to the query.
-
-===== SEARCH RESULTS =====
-{{RESULTS}}
-==========================
-
-Follow these strict guidelines:
-1. First, carefully analyze the search results and extract relevant information.
-2. Identify the most credible and relevant sources among the results.
-3. Note any conflicts or agreements between different sources.
-4. Consider the time relevance of each result in relation to the query.
-5. If the results don't provide enough information to fully answer the query, be honest and transparent about this limitation.
-6. Provide a direct answer to the query based on the search results.
-7. Use [1], [2], etc. to cite specific results when making claims.
-8. DO NOT invent information not present in the results.
-9. When ranking or listing items, consider factors like community consensus, upvotes, and comment engagement.
-10. If the query is time-sensitive (e.g., asking about "now" or "current"), prioritize recent results and clearly state when the information is from.
-11. Format your response with the following structure EXACTLY:
-
-BEGIN_REASONING
-[Provide your detailed analysis of the search results here. This section is for your reasoning process. Include evaluation of sources, conflicts between sources, and how you arrived at your conclusions. Assess the reliability of the results and how well they address the query.]
-END_REASONING
-
-BEGIN_ANSWER
-[Provide a clear, direct answer to the query here. Use citations to reference specific search results. Format in markdown for better readability. Be concise but comprehensive. If the available results don't adequately answer the query, acknowledge this limitation clearly.]
-END_ANSWER`
-
-	// Claude-specific template with improved instructions
-	templates["claude"] = `You are Claude, an AI assistant specialized in analyzing Reddit content to answer user queries. You are known for your careful reasoning and high-quality, evidence-based responses.
-
-USER QUERY: {{QUERY}}
-
-Below are {{RESULT_COUNT}} relevant search results from Reddit (out of {{TOTAL_RESULT_COUNT}} total results).
-Please analyze these results and provide a comprehensive answer to the query.
-
-===== SEARCH RESULTS =====
-{{RESULTS}}
-==========================
-
-Follow these strict guidelines:
-1. First analyze the search results and extract relevant information.
-2. Then provide a direct answer to the query based on the search results.
-3. Use [1], [2], etc. to cite specific results when making claims.
-4. DO NOT make up information not present in the results.
-5. If the query is time-sensitive (asking about current trends, recent events, etc.), be explicit about the recency of your sources.
-6. If the results don't contain sufficient information to properly answer the query, acknowledge this limitation clearly.
-7. For ranking-type queries (asking for "top", "best", etc.), base your rankings on Reddit engagement metrics and community consensus.
-8. Format your response EXACTLY as shown below:
-
-BEGIN_REASONING
-[Your detailed analysis should include:
-- Evaluation of the credibility and relevance of each source
-- Identification of consensus and disagreements across sources
-- Analysis of different perspectives presented
-- How you determined what information was most reliable and relevant
-- Any limitations or gaps in the available information
-- Consideration of time relevance for time-sensitive queries]
-END_REASONING
-
-BEGIN_ANSWER
-[Your answer should:
-- Directly address the user's question using information from the search results
-- Use citations like [1], [2] to reference specific search results
-- Be formatted in markdown for better readability
-- Present information in a structured, organized manner
-- Be comprehensive yet concise
-- Acknowledge limitations or uncertainties when appropriate
-- For ranking queries, explain the basis of your rankings (community consensus, upvotes, expert opinions, etc.)]
-END_ANSWER
-
-EXAMPLE RESPONSE:
-BEGIN_REASONING
-The search results provide several perspectives on remote work productivity. Results [1], [3], and [5] come from r/productivity and discuss individual experiences, while result [2] from r/science references an academic study, giving it higher credibility.
-
-The academic study in [2] found a 13% productivity increase for remote workers, which is supported by anecdotal evidence in [1] where the user reports completing tasks more efficiently without office distractions. However, result [5] presents a counterpoint, with the user describing productivity challenges at home.
-
-Results [3] and [4] highlight that productivity varies by job type and personality, suggesting that remote work isn't universally beneficial or detrimental. This nuance is important to include in the answer.
-
-Overall, the most reliable information comes from the academic study [2], but the personal experiences provide valuable context about factors that influence remote work productivity.
-END_REASONING
-
-BEGIN_ANSWER
-## Does remote work increase productivity?
-
-Based on the Reddit discussions, remote work's impact on productivity appears **mixed and dependent on several factors**:
-
-### Evidence Supporting Increased Productivity
-- An academic study reported a **13% productivity increase** among remote workers over a 9-month period [2]
-- Many remote workers report fewer distractions compared to traditional offices [1]
-- Time saved from commuting can be redirected to work tasks [3]
-
-### Factors Affecting Remote Work Productivity
-- **Job type**: Tasks requiring deep focus benefit more than collaborative work [3]
-- **Home environment**: Having a dedicated workspace significantly impacts success [5]
-- **Personal work style**: Self-motivated individuals typically adapt better [4]
-
-The consensus suggests that remote work productivity benefits are real but not universal. The most successful remote workers tend to have established routines, dedicated workspaces, and jobs that don't require constant collaboration.
-END_ANSWER`
-
-	// DeepSeek-specific template optimized for technical content
-	templates["deepseek"] = `You are an AI assistant specialized in analyzing Reddit content, with particular expertise in providing accurate, unbiased answers. Your task is to systematically analyze search results to answer the user's query.
-
-USER QUERY: {{QUERY}}
-
-Below are {{RESULT_COUNT}} relevant search results from Reddit (out of {{TOTAL_RESULT_COUNT}} total results).
-I need you to analyze these results and provide a comprehensive, evidence-based answer.
-
-===== SEARCH RESULTS =====
-{{RESULTS}}
-==========================
-
-Follow these strict guidelines:
-1. Analyze each result carefully, evaluating relevance and credibility 
-2. Extract all pertinent details and information that helps answer the query
-3. Structure your thinking methodically and show your reasoning step-by-step
-4. Provide an answer that directly addresses the query using only information from the results
-5. Use precise citations [1], [2], etc. when referencing specific results
-6. DO NOT fabricate information or include facts not present in the results
-7. For time-sensitive queries (about current trends, recent events, etc.), explicitly note when the information was posted
-8. If the results don't provide sufficient information to answer the query adequately, acknowledge this limitation
-9. For ranking-type queries, use community consensus, upvote patterns, and expert opinions from the results
-10. Format your response EXACTLY as shown below:
-
-BEGIN_REASONING
-[Provide a structured, methodical analysis of the search results here. Analyze the accuracy and credibility of claims. Identify any consensus or disagreements. Evaluate the reliability of sources and consider the time relevance of different results.]
-END_REASONING
-
-BEGIN_ANSWER
-[Provide a clear, accurate answer to the query. Use proper citations ([1], [2], etc.) when referencing information from the results. Format using markdown with appropriate headers, code blocks, and bullet points as needed. Include relevant details, and recognize limitations in the available information.]
-END_ANSWER`
-
-	// Gemini-specific template with structured reasoning steps
-	templates["gemini"] = `You are an AI assistant leveraging Google's language capabilities to analyze Reddit content and answer user queries with well-structured responses.
-
-USER QUERY: {{QUERY}}
-
-Below are {{RESULT_COUNT}} relevant search results from Reddit (out of {{TOTAL_RESULT_COUNT}} total results).
-Analyze these results and provide a comprehensive, well-reasoned answer to the query.
-
-===== SEARCH RESULTS =====
-{{RESULTS}}
-==========================
-
-Follow these strict guidelines:
-1. First, break down your analysis into clear reasoning steps.
-2. Carefully evaluate the reliability and relevance of each search result.
-3. Provide a direct answer to the query using only information from the search results.
-4. Use [1], [2], etc. to cite specific results when making claims.
-5. DO NOT include information that isn't present in the results.
-6. For time-sensitive queries, note the recency and temporal context of the information.
-7. For ranking-type queries, base your rankings on community consensus and engagement metrics visible in the results.
-8. Acknowledge data limitations when the search results don't provide sufficient information.
-9. Format your response EXACTLY as follows:
-
-BEGIN_REASONING
-## Step 1: Understanding the Query
-[Analyze what the query is asking, identify key terms, and determine what would constitute a good answer]
-
-## Step 2: Evaluating Sources
-[Assess the credibility and relevance of each search result, noting which subreddits and sources seem most reliable]
-
-## Step 3: Extracting Key Information
-[Pull out the most important facts, opinions, and contexts from the results that help answer the query]
-
-## Step 4: Identifying Consensus and Disagreements
-[Note where sources agree and disagree, and analyze the reasons for any contradictions]
-
-## Step 5: Forming Conclusions
-[Synthesize the information into a coherent understanding, explaining how you weighed different sources]
-END_REASONING
-
-BEGIN_ANSWER
-[Provide a clear, direct answer to the query here. Structure with markdown headings and formatting. Use citations [1], [2], etc. to reference specific search results. Be comprehensive but concise, and acknowledge any limitations in the available information.]
-END_ANSWER`
-
-	return templates
-}
-
 // buildPrompt creates the final prompt for the AI model
-func (s *AIService) buildPrompt(query string, results []models.SearchResult, modelConfig *AIModelConfig) string {
+func (s *AIService) buildPrompt(query string, results []models.SearchResult, modelConfig *AIModelConfig, opts AnswerOptions) (string, error) {
 	// Get the appropriate template
-	template := s.promptTemplate[modelConfig.PromptTemplate]
-	if template == "" {
-		template = s.promptTemplate["default"]
+	tmpl := s.prompts.Get(modelConfig.PromptTemplate)
+	if tmpl == nil {
+		return "", fmt.Errorf("no prompt template available for '%s'", modelConfig.PromptTemplate)
 	}
 	
 	// Create the results section
@@ -219,15 +33,22 @@ func (s *AIService) buildPrompt(query string, results []models.SearchResult, mod
 	// Add each result to the text
 	for i, result := range results[:resultLimit] {
 		// Format the result
-		resultEntry := formatResultForPrompt(i+1, result, modelConfig.MaxContentLength)
+		resultEntry := formatResultForPrompt(i+1, result, s.contentLength(result, modelConfig, opts), opts.Locale)
 		resultsText.WriteString(resultEntry)
 	}
 	
-	// Replace template variables
-	prompt := strings.ReplaceAll(template, "{{QUERY}}", query)
-	prompt = strings.ReplaceAll(prompt, "{{RESULTS}}", resultsText.String())
-	prompt = strings.ReplaceAll(prompt, "{{RESULT_COUNT}}", fmt.Sprintf("%d", resultLimit))
-	prompt = strings.ReplaceAll(prompt, "{{TOTAL_RESULT_COUNT}}", fmt.Sprintf("%d", len(results)))
+	// Fill in the template
+	var rendered strings.Builder
+	err := tmpl.Execute(&rendered, promptData{
+		Query:            query,
+		Results:          resultsText.String(),
+		ResultCount:      resultLimit,
+		TotalResultCount: len(results),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error rendering prompt template '%s': %w", tmpl.Name(), err)
+	}
+	prompt := rendered.String()
 	
 	// Add query-specific instructions based on analysis
 	params := utils.ParseQuery(query)
@@ -238,7 +59,7 @@ func (s *AIService) buildPrompt(query string, results []models.SearchResult, mod
 	// Time-sensitive query instructions
 	if params.IsTimeSensitive {
 		customInstructions.WriteString("\nADDITIONAL INSTRUCTIONS:\nThis query appears to be time-sensitive, referring to current or recent information. Please:\n")
-		customInstructions.WriteString("- Pay special attention to when each result was posted\n")
+		customInstructions.WriteString("- Pay special attention to when each result was posted or last edited\n")
 		customInstructions.WriteString("- Prioritize more recent information over older content\n")
 		customInstructions.WriteString("- Explicitly mention the time context of your answer (e.g., \"As of [date]...\")\n")
 		customInstructions.WriteString("- If the results don't include sufficiently recent information, acknowledge this limitation\n")
@@ -250,7 +71,32 @@ func (s *AIService) buildPrompt(query string, results []models.SearchResult, mod
 		customInstructions.WriteString("- Base your rankings primarily on evidence from the Reddit results\n")
 		customInstructions.WriteString("- Consider factors like upvotes, comment counts, and community consensus\n")
 		customInstructions.WriteString("- Explain the basis for your rankings in your response\n")
-		customInstructions.WriteString("- If specific quantities are requested (e.g., \"top 5\"), provide exactly that number if the data supports it\n")
+		if n := rankingSize(query, opts); n > 0 {
+			customInstructions.WriteString(strictRankingInstructions(n))
+		} else {
+			customInstructions.WriteString("- If specific quantities are requested (e.g., \"top 5\"), provide exactly that number if the data supports it\n")
+		}
+		if len(opts.Entities) > 0 {
+			customInstructions.WriteString("- Rank concrete items. The most mentioned in the results are:\n")
+			for _, entity := range opts.Entities {
+				customInstructions.WriteString(fmt.Sprintf("  - %s (%d mentions in %d results)\n", entity.Name, entity.Mentions, entity.Results))
+			}
+		}
+	}
+	
+	// Split opinion instructions
+	if opts.Controversial {
+		customInstructions.WriteString("\nADDITIONAL INSTRUCTIONS:\nReddit is split on this question: the results include controversial posts or comments that dispute each other. Please:\n")
+		customInstructions.WriteString("- Present each camp in its own section or paragraph, with the strongest arguments made for it\n")
+		customInstructions.WriteString("- Support each camp with its own citations, and don't cite the same result for opposing camps\n")
+		customInstructions.WriteString("- Say roughly how the community is divided, e.g. by upvotes, rather than declaring a winner the results don't support\n")
+	}
+	
+	// Author flair instructions
+	if hasAuthorFlair(results[:resultLimit]) {
+		customInstructions.WriteString("\nADDITIONAL INSTRUCTIONS:\nSome authors have community flair. Please:\n")
+		customInstructions.WriteString("- Treat flair that shows verified credentials (e.g., \"Verified Engineer\") as a sign of expertise on that topic\n")
+		customInstructions.WriteString("- Do not treat joke or decorative flair as a credential\n")
 	}
 	
 	// For queries with few results
@@ -261,30 +107,209 @@ func (s *AIService) buildPrompt(query string, results []models.SearchResult, mod
 		customInstructions.WriteString("- Suggest what additional information would be helpful to better answer the query\n")
 	}
 	
+	// Community-specific instructions from config
+	for _, subreddit := range s.dominantSubreddits(results[:resultLimit]) {
+		customInstructions.WriteString(fmt.Sprintf("\nADDITIONAL INSTRUCTIONS FOR r/%s CONTENT:\n", subreddit))
+		customInstructions.WriteString(s.promptConfig.SubredditInstructions[subreddit])
+		customInstructions.WriteString("\n")
+	}
+	
+	// Requested answer structure
+	if formatInstructions := answerFormatInstructions(opts.Format, params); formatInstructions != "" {
+		customInstructions.WriteString("\nADDITIONAL INSTRUCTIONS:\n")
+		customInstructions.WriteString(formatInstructions)
+	}
+	
+	// Requested output language
+	if opts.Language != "" {
+		customInstructions.WriteString(fmt.Sprintf("\nADDITIONAL INSTRUCTIONS:\nWrite your entire response in %s, even where the search results are in other languages. Please:\n", opts.Language))
+		customInstructions.WriteString("- Keep citation markers such as [1], [2] exactly as they are\n")
+		customInstructions.WriteString("- Keep the BEGIN_REASONING, END_REASONING, BEGIN_ANSWER and END_ANSWER markers in English\n")
+		customInstructions.WriteString("- Leave direct quotes from the results in their original language, followed by a translation\n")
+	}
+	
+	// Workplace-safe wording
+	if opts.CleanLanguage {
+		customInstructions.WriteString("\nADDITIONAL INSTRUCTIONS:\nThe answer will be shown in a workplace tool. Please:\n")
+		customInstructions.WriteString("- Do not use profanity, slurs or crude language, even where the results do\n")
+		customInstructions.WriteString("- Paraphrase quotes that contain profanity instead of quoting them directly, keeping their citations\n")
+	}
+	
 	// Add custom instructions if we have any
 	if customInstructions.Len() > 0 {
 		prompt = strings.Replace(prompt, "==========================", "==========================\n"+customInstructions.String(), 1)
 	}
 	
-	return prompt
+	// In JSON mode the response format instructions are replaced wholesale
+	if modelConfig.JSONMode {
+		prompt += jsonResponseInstructions
+	}
+	
+	return prompt, nil
+}
+
+// jsonResponseInstructions override the template's text response format for
+// models running in JSON mode
+const jsonResponseInstructions = `
+
+RESPONSE FORMAT OVERRIDE:
+Ignore the BEGIN_REASONING/BEGIN_ANSWER format described above. Respond with a single JSON object and nothing else, using exactly this shape:
+{
+  "reasoning_steps": [
+    {"title": "<short step title>", "content": "<the analysis for this step, in markdown>"}
+  ],
+  "answer": "<the complete answer in markdown, with citations like [1], [2]>"
+}
+Include between 2 and 6 reasoning steps, in the order you worked through them. Apply every other instruction above to the content of these fields.`
+
+// answerFormatInstructions describes how to structure the answer for the
+// requested format, adapted to the kind of query being answered
+func answerFormatInstructions(format string, params utils.QueryParams) string {
+	isRanking := params.Intent == utils.RankingIntent || params.HasRankingAspect
+
+	switch format {
+	case models.AnswerFormatBullets:
+		if isRanking {
+			return rankedListInstructions
+		}
+		return "Structure the answer section as concise Markdown bullet points:\n" +
+			"- One finding per bullet, most important first\n" +
+			"- End every bullet with the citation(s) supporting it, e.g. [2]\n" +
+			"- Group related bullets under short headings if there are more than six\n"
+	case models.AnswerFormatTable:
+		if params.Intent == utils.ComparisonIntent {
+			return "Structure the answer section as a Markdown comparison table:\n" +
+				"- One column per option being compared and one row per aspect (e.g. price, performance, community sentiment)\n" +
+				"- Put citations inside the cells they support, e.g. \"Faster startup [3]\"\n" +
+				"- Write \"No data\" in cells the results don't cover rather than guessing\n" +
+				"- Follow the table with a one or two sentence summary of the overall verdict\n"
+		}
+		if isRanking {
+			return rankedListInstructions
+		}
+		return "Structure the answer section around a Markdown table summarizing the key findings:\n" +
+			"- Use columns that fit the query, with a final \"Sources\" column holding citations like [1], [4]\n" +
+			"- Keep cells short; add at most two sentences of context after the table\n"
+	case models.AnswerFormatEssay:
+		return "Write the answer section as a short essay of flowing prose paragraphs:\n" +
+			"- Do not use bullet points, numbered lists or tables\n" +
+			"- Open with a direct answer, develop the supporting points, and close with a brief conclusion\n" +
+			"- Cite results inline, e.g. \"...as several users noted [2][5].\"\n"
+	}
+
+	return ""
+}
+
+// rankedListInstructions are used for ranking queries in list-like formats
+const rankedListInstructions = "Structure the answer section as a numbered Markdown list, best first:\n" +
+	"- One item per line, with a short justification\n" +
+	"- Give each item exactly one citation, the result that best supports its position, e.g. \"1. **Item** - reason [3]\"\n" +
+	"- If the query asks for a specific number of items, list exactly that many when the results support it\n"
+
+// contentLength returns how much of result's content goes in the prompt: the
+// requested length, else the length configured for its subreddit or for all
+// results, else the model's cap
+func (s *AIService) contentLength(result models.SearchResult, modelConfig *AIModelConfig, opts AnswerOptions) int {
+	if opts.MaxContentLength > 0 {
+		return opts.MaxContentLength
+	}
+	if length, ok := s.promptConfig.SubredditContentLength[config.NormalizeSubreddit(result.Subreddit)]; ok {
+		return length
+	}
+	if s.promptConfig.MaxContentLength > 0 {
+		return s.promptConfig.MaxContentLength
+	}
+	return modelConfig.MaxContentLength
+}
+
+// dominantSubreddits returns the configured subreddits that make up at least
+// the configured share of results, most frequent first
+func (s *AIService) dominantSubreddits(results []models.SearchResult) []string {
+	if len(s.promptConfig.SubredditInstructions) == 0 || len(results) == 0 {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[config.NormalizeSubreddit(result.Subreddit)]++
+	}
+
+	var dominant []string
+	for subreddit, count := range counts {
+		if _, ok := s.promptConfig.SubredditInstructions[subreddit]; !ok {
+			continue
+		}
+		if float64(count)/float64(len(results)) >= s.promptConfig.SubredditShare {
+			dominant = append(dominant, subreddit)
+		}
+	}
+
+	sort.Slice(dominant, func(i, j int) bool {
+		if counts[dominant[i]] != counts[dominant[j]] {
+			return counts[dominant[i]] > counts[dominant[j]]
+		}
+		return dominant[i] < dominant[j]
+	})
+
+	return dominant
+}
+
+// hasAuthorFlair reports whether any result's author has flair
+func hasAuthorFlair(results []models.SearchResult) bool {
+	for _, result := range results {
+		if result.AuthorFlair != "" {
+			return true
+		}
+	}
+	return false
 }
 
-// formatResultForPrompt formats a search result for inclusion in the prompt
-func formatResultForPrompt(index int, result models.SearchResult, maxContentLength int) string {
+// formatResultForPrompt formats a search result for inclusion in the
+// prompt, with relative times in locale
+func formatResultForPrompt(index int, result models.SearchResult, maxContentLength int, locale string) string {
 	var builder strings.Builder
 	
 	// Format the result header
 	builder.WriteString(fmt.Sprintf("[%d] %s\n", index, result.Title))
-	builder.WriteString(fmt.Sprintf("Type: %s | Subreddit: r/%s | Author: u/%s\n", 
+	builder.WriteString(fmt.Sprintf("Type: %s | Subreddit: r/%s | Author: u/%s", 
 		result.Type, result.Subreddit, result.Author))
+	// Communities often use author flair to show verified credentials
+	if result.AuthorFlair != "" {
+		builder.WriteString(fmt.Sprintf(" (flair: %s)", result.AuthorFlair))
+	}
+	if result.Flair != "" {
+		builder.WriteString(fmt.Sprintf(" | Post flair: %s", result.Flair))
+	}
+	builder.WriteString("\n")
 	builder.WriteString(fmt.Sprintf("Score: %d", result.Score))
 	
 	if result.CommentCount > 0 {
 		builder.WriteString(fmt.Sprintf(" | Comments: %d", result.CommentCount))
 	}
+	if awards := awardCount(result); awards > 0 {
+		builder.WriteString(fmt.Sprintf(" | Awards: %d", awards))
+	}
+	if result.UpvoteRatio > 0 && result.UpvoteRatio < controversialUpvoteRatio {
+		builder.WriteString(fmt.Sprintf(" | Controversial: %.0f%% upvoted", result.UpvoteRatio*100))
+	}
+	if result.Distinguished {
+		builder.WriteString(" | Moderator")
+	}
+	if result.Stickied {
+		builder.WriteString(" | Pinned")
+	}
+	if result.Archived {
+		builder.WriteString(" | Archived")
+	} else if result.Locked {
+		builder.WriteString(" | Locked")
+	}
 	
 	// Add created time
-	builder.WriteString(fmt.Sprintf(" | Posted: %s\n", formatTimeAgo(time.Unix(result.CreatedUTC, 0))))
+	builder.WriteString(fmt.Sprintf(" | Posted: %s", utils.FormatTimeAgo(time.Unix(result.CreatedUTC, 0), locale)))
+	if result.EditedUTC > result.CreatedUTC {
+		builder.WriteString(fmt.Sprintf(" | Edited: %s", utils.FormatTimeAgo(time.Unix(result.EditedUTC, 0), locale)))
+	}
+	builder.WriteString("\n")
 	
 	// Add URL
 	builder.WriteString(fmt.Sprintf("URL: %s\n\n", result.URL))
@@ -293,14 +318,45 @@ func formatResultForPrompt(index int, result models.SearchResult, maxContentLeng
 	content := result.Content
 	if content == "" {
 		content = "(No content available)"
-	} else if len(content) > maxContentLength {
-		content = content[:maxContentLength] + "..."
+	} else {
+		content = utils.TruncateWithEllipsis(content, maxContentLength)
+	}
+	
+	// Show what a reply responds to, when it was fetched
+	if len(result.ThreadContext) > 0 {
+		builder.WriteString("In reply to:\n")
+		for _, parent := range result.ThreadContext {
+			body := utils.TruncateWithEllipsis(parent.Body, maxContentLength/2)
+			builder.WriteString(fmt.Sprintf("> u/%s: %s\n", parent.Author, strings.ReplaceAll(body, "\n", " ")))
+		}
+		builder.WriteString("\n")
 	}
 	
 	builder.WriteString("Content:\n")
 	builder.WriteString(content)
 	builder.WriteString("\n\n")
 	
+	// Add the linked article for link posts
+	if preview := result.LinkPreview; preview != nil {
+		builder.WriteString(fmt.Sprintf("Linked article: %s (%s)\n", preview.Title, preview.SiteName))
+		builder.WriteString(fmt.Sprintf("Link: %s\n", result.ExternalURL))
+		summary := utils.TruncateWithEllipsis(preview.Summary, maxContentLength)
+		builder.WriteString(summary)
+		builder.WriteString("\n\n")
+	}
+	
+	// Add what the video says for video posts
+	if transcript := result.Transcript; transcript != nil {
+		if transcript.Generated {
+			builder.WriteString("Video transcript (automatic captions, excerpt):\n")
+		} else {
+			builder.WriteString("Video transcript (excerpt):\n")
+		}
+		excerpt := utils.TruncateWithEllipsis(transcript.Excerpt, maxContentLength)
+		builder.WriteString(excerpt)
+		builder.WriteString("\n\n")
+	}
+	
 	// Add highlights if available
 	if len(result.Highlights) > 0 {
 		builder.WriteString("Key excerpts:\n")
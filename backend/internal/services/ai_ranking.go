@@ -0,0 +1,210 @@
+// File: backend/internal/services/ai_ranking.go
+
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+const (
+	// maxRankingRetries is how many times an answer that isn't a ranking of
+	// exactly N entries is sent back to the model with a correction
+	maxRankingRetries = 2
+	// maxStrictRankingSize is the largest "top N" held to exactly N entries;
+	// longer lists are left to the model
+	maxStrictRankingSize = 25
+)
+
+var (
+	// rankingItemRegex matches a top-level numbered list item, including one
+	// written as a heading ("### 1. Item")
+	rankingItemRegex = regexp.MustCompile(`^(?:#{1,6}\s*)?(\d{1,3})[.)]\s+(.+)$`)
+	// rankingBoldRegex matches an item whose name is in bold
+	rankingBoldRegex = regexp.MustCompile(`^\*\*(.+?)\*\*\s*(?:[-–—:]\s*)?(.*)$`)
+	// rankingSeparatorRegex separates a plain item's name from its
+	// justification
+	rankingSeparatorRegex = regexp.MustCompile(`\s+[-–—]\s+|:\s+`)
+	// rankingCitationRegex matches citation markers like [3]
+	rankingCitationRegex = regexp.MustCompile(`\[(\d+)\]`)
+	// rankingDanglingPunctuationRegex matches the space a removed citation
+	// leaves before punctuation
+	rankingDanglingPunctuationRegex = regexp.MustCompile(`\s+([.,;:!?])`)
+)
+
+// rankingSize returns how many entries the answer to query must rank, or 0
+// if it isn't held to a number. Essays are prose by request, so they aren't.
+func rankingSize(query string, opts AnswerOptions) int {
+	n := utils.ParseQuery(query).QuantityRequested
+	if n <= 0 || n > maxStrictRankingSize || opts.Format == models.AnswerFormatEssay {
+		return 0
+	}
+	return n
+}
+
+// strictRankingInstructions describes the list a "top N" answer must contain
+func strictRankingInstructions(n int) string {
+	return fmt.Sprintf("- The answer must contain a numbered list of exactly %d items, best first, one per line, formatted as \"1. **Name** - justification [citation]\"\n", n) +
+		"- Give every item a one sentence justification and cite at least one search result supporting it\n" +
+		"- Do not number anything else in the answer\n"
+}
+
+// enforceRanking checks that result's answer ranks exactly n entries, each
+// with a justification and a citation, and sets result.Ranking. Otherwise it
+// asks regenerate for a new answer with a correction describing what was
+// wrong, up to maxRankingRetries times. If the answer still falls short, the
+// last one is kept with a warning.
+func enforceRanking(result *AnswerResult, n, resultCount int, regenerate func(correction string) (*AnswerResult, error)) *AnswerResult {
+	for attempt := 0; ; attempt++ {
+		entries := parseRanking(result.Answer, resultCount)
+		problems := validateRanking(entries, n)
+		result.Ranking = entries
+		if len(problems) == 0 {
+			return result
+		}
+
+		if attempt == maxRankingRetries {
+			log.Printf("Ranking still invalid after %d corrections: %s", attempt, strings.Join(problems, "; "))
+			result.Warnings = append(result.Warnings, "The answer doesn't rank exactly the requested items: "+strings.Join(problems, "; "))
+			return result
+		}
+
+		log.Printf("Ranking invalid, asking for a correction: %s", strings.Join(problems, "; "))
+		retry, err := regenerate(rankingCorrection(n, problems))
+		if err != nil {
+			log.Printf("Ranking correction failed, keeping the previous answer: %v", err)
+			result.Warnings = append(result.Warnings, "The answer doesn't rank exactly the requested items: "+strings.Join(problems, "; "))
+			return result
+		}
+		retry.Consistency = result.Consistency
+		result = retry
+	}
+}
+
+// rankingCorrection is appended to the answer prompt when a ranking is sent
+// back, so the model answers again knowing what to fix
+func rankingCorrection(n int, problems []string) string {
+	var correction strings.Builder
+	correction.WriteString("\n\nCORRECTION:\nA previous response to this prompt did not follow the required ranking format:\n")
+	for _, problem := range problems {
+		correction.WriteString("- " + problem + "\n")
+	}
+	correction.WriteString("Answer again from the same search results, in the same response format. Please:\n")
+	correction.WriteString(strictRankingInstructions(n))
+	return correction.String()
+}
+
+// parseRanking extracts the entries of the first numbered list in answer.
+// Indented lines below an item, such as sub-bullets, add to its
+// justification and citations. Citations of results the model didn't see
+// are dropped.
+func parseRanking(answer string, resultCount int) []models.RankingEntry {
+	var (
+		entries []models.RankingEntry
+		current *models.RankingEntry
+	)
+	for _, line := range strings.Split(answer, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indented := strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "  ")
+		if match := rankingItemRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil && !indented {
+			// A list numbered from 1 again is a different list
+			if number, _ := strconv.Atoi(match[1]); number == 1 && len(entries) > 0 {
+				break
+			}
+			entries = append(entries, parseRankingItem(match[2], len(entries)+1, resultCount))
+			current = &entries[len(entries)-1]
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		// The list is over once unindented prose follows it
+		if !indented {
+			break
+		}
+		detail := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-*"))
+		current.Citations = appendCitations(current.Citations, detail, resultCount)
+		if text := stripCitations(detail); text != "" {
+			current.Justification = strings.TrimSpace(current.Justification + " " + text)
+		}
+	}
+	return entries
+}
+
+// parseRankingItem splits a list item into its name, justification and
+// citations
+func parseRankingItem(item string, rank, resultCount int) models.RankingEntry {
+	entry := models.RankingEntry{Rank: rank}
+	entry.Citations = appendCitations(nil, item, resultCount)
+
+	var name, justification string
+	if match := rankingBoldRegex.FindStringSubmatch(item); match != nil {
+		name, justification = match[1], match[2]
+	} else if loc := rankingSeparatorRegex.FindStringIndex(item); loc != nil {
+		name, justification = item[:loc[0]], item[loc[1]:]
+	} else {
+		name = item
+	}
+	entry.Name = strings.Trim(stripCitations(name), " *_")
+	entry.Justification = stripCitations(justification)
+	return entry
+}
+
+// validateRanking describes what keeps entries from being a ranking of
+// exactly n justified, cited items
+func validateRanking(entries []models.RankingEntry, n int) []string {
+	var problems []string
+	if len(entries) != n {
+		problems = append(problems, fmt.Sprintf("the answer ranked %d items instead of exactly %d", len(entries), n))
+	}
+	for _, entry := range entries {
+		switch {
+		case entry.Name == "":
+			problems = append(problems, fmt.Sprintf("item %d has no name", entry.Rank))
+		case entry.Justification == "":
+			problems = append(problems, fmt.Sprintf("item %d (%s) has no justification", entry.Rank, entry.Name))
+		case len(entry.Citations) == 0:
+			problems = append(problems, fmt.Sprintf("item %d (%s) cites no search result", entry.Rank, entry.Name))
+		}
+	}
+	return problems
+}
+
+// appendCitations adds the results cited in text to citations, skipping
+// duplicates and numbers outside 1..resultCount
+func appendCitations(citations []int, text string, resultCount int) []int {
+	for _, match := range rankingCitationRegex.FindAllStringSubmatch(text, -1) {
+		index, err := strconv.Atoi(match[1])
+		if err != nil || index < 1 || index > resultCount {
+			continue
+		}
+		duplicate := false
+		for _, existing := range citations {
+			if existing == index {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			citations = append(citations, index)
+		}
+	}
+	return citations
+}
+
+// stripCitations removes citation markers from text
+func stripCitations(text string) string {
+	text = rankingCitationRegex.ReplaceAllString(text, "")
+	text = strings.Join(strings.Fields(text), " ")
+	return rankingDanglingPunctuationRegex.ReplaceAllString(text, "$1")
+}
@@ -0,0 +1,108 @@
+// File: backend/internal/services/ai_ranking_test.go
+
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestParseRanking(t *testing.T) {
+	answer := `# Top 3 TVs
+
+Here is what r/4kTV recommends:
+
+1. **LG C3** - The best all-rounder for movies [1][2].
+2. Sony A95L: Brightest QD-OLED, but pricey [3] [9]
+   - Owners praise its processing [4]
+### 3. **Samsung S90C**
+   Great value when on sale [2]
+
+Prices change often, so check deals [5].
+
+1. This list is not part of the ranking [1]`
+
+	entries := parseRanking(answer, 5)
+	expected := []models.RankingEntry{
+		{Rank: 1, Name: "LG C3", Justification: "The best all-rounder for movies.", Citations: []int{1, 2}},
+		{Rank: 2, Name: "Sony A95L", Justification: "Brightest QD-OLED, but pricey Owners praise its processing", Citations: []int{3, 4}},
+		{Rank: 3, Name: "Samsung S90C", Justification: "Great value when on sale", Citations: []int{2}},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, entries)
+	}
+
+	if problems := validateRanking(entries, 3); len(problems) != 0 {
+		t.Errorf("Expected a valid ranking, got %v", problems)
+	}
+	problems := validateRanking(entries[:2], 3)
+	if len(problems) != 1 || !strings.Contains(problems[0], "2 items instead of exactly 3") {
+		t.Errorf("Expected a count problem, got %v", problems)
+	}
+	entries[1].Citations = nil
+	if problems := validateRanking(entries, 3); len(problems) != 1 || !strings.Contains(problems[0], "Sony A95L") {
+		t.Errorf("Expected an uncited item problem, got %v", problems)
+	}
+}
+
+func TestEnforceRanking(t *testing.T) {
+	twoItems := &AnswerResult{Answer: "1. **A** - good [1]\n2. **B** - fine [2]"}
+	threeItems := &AnswerResult{Answer: "1. **A** - good [1]\n2. **B** - fine [2]\n3. **C** - ok [1]"}
+
+	// A wrong count is corrected
+	var corrections []string
+	result := enforceRanking(twoItems, 3, 2, func(correction string) (*AnswerResult, error) {
+		corrections = append(corrections, correction)
+		return threeItems, nil
+	})
+	if len(corrections) != 1 || !strings.Contains(corrections[0], "ranked 2 items instead of exactly 3") {
+		t.Errorf("Expected one correction naming the count, got %q", corrections)
+	}
+	if len(result.Ranking) != 3 || len(result.Warnings) != 0 {
+		t.Errorf("Expected the corrected ranking without warnings, got %+v", result)
+	}
+
+	// Answers that stay wrong are kept with a warning
+	calls := 0
+	result = enforceRanking(&AnswerResult{Answer: "No list here."}, 3, 2, func(string) (*AnswerResult, error) {
+		calls++
+		return &AnswerResult{Answer: "1. **A** - good [1]"}, nil
+	})
+	if calls != maxRankingRetries {
+		t.Errorf("Expected %d corrections, got %d", maxRankingRetries, calls)
+	}
+	if len(result.Ranking) != 1 || len(result.Warnings) != 1 {
+		t.Errorf("Expected the last ranking with a warning, got %+v", result)
+	}
+
+	// A failed correction keeps the answer it was correcting
+	result = enforceRanking(&AnswerResult{Answer: "1. **A** - good [1]"}, 3, 2, func(string) (*AnswerResult, error) {
+		return nil, errors.New("rate limited")
+	})
+	if result.Answer != "1. **A** - good [1]" || len(result.Warnings) != 1 {
+		t.Errorf("Expected the original answer with a warning, got %+v", result)
+	}
+}
+
+func TestRankingSize(t *testing.T) {
+	testCases := []struct {
+		query    string
+		format   string
+		expected int
+	}{
+		{"top 5 TVs", "", 5},
+		{"best three laptops", models.AnswerFormatBullets, 3},
+		{"top 5 TVs", models.AnswerFormatEssay, 0},
+		{"top 100 songs", "", 0},
+		{"best TVs", "", 0},
+	}
+	for _, tc := range testCases {
+		if got := rankingSize(tc.query, AnswerOptions{Format: tc.format}); got != tc.expected {
+			t.Errorf("rankingSize(%q, %q): expected %d, got %d", tc.query, tc.format, tc.expected, got)
+		}
+	}
+}
@@ -5,11 +5,15 @@ package services
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
-	"time"
+	"unicode/utf8"
 
+	"github.com/pranesh-j/subplexity/internal/config"
 	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
 )
 
 func TestExtractReasoningAndAnswer(t *testing.T) {
@@ -270,71 +274,6 @@ This references an out-of-range source [4] that doesn't exist.`,
 	}
 }
 
-func TestFormatTimeAgo(t *testing.T) {
-	// Test cases
-	now := time.Now()
-	testCases := []struct {
-		name     string
-		time     time.Time
-		expected string
-	}{
-		{
-			name:     "Just now",
-			time:     now.Add(-30 * time.Second),
-			expected: "just now",
-		},
-		{
-			name:     "Minutes ago",
-			time:     now.Add(-5 * time.Minute),
-			expected: "5 minutes ago",
-		},
-		{
-			name:     "Hours ago",
-			time:     now.Add(-3 * time.Hour),
-			expected: "3 hours ago",
-		},
-		{
-			name:     "Yesterday",
-			time:     now.Add(-30 * time.Hour),
-			expected: "yesterday",
-		},
-		{
-			name:     "Days ago",
-			time:     now.Add(-5 * 24 * time.Hour),
-			expected: "5 days ago",
-		},
-		{
-			name:     "Weeks ago",
-			time:     now.Add(-3 * 7 * 24 * time.Hour),
-			expected: "3 weeks ago",
-		},
-		{
-			name:     "Months ago",
-			time:     now.Add(-2 * 30 * 24 * time.Hour),
-			expected: "2 months ago",
-		},
-		{
-			name:     "Years ago",
-			time:     now.Add(-3 * 365 * 24 * time.Hour),
-			expected: "3 years ago",
-		},
-	}
-	
-	// Run test cases
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := formatTimeAgo(tc.time)
-			
-			// Some flexibility in the exact wording for time-based tests
-			// to avoid flaky tests when time boundaries are close
-			if !strings.Contains(result, tc.expected) && 
-			   !strings.Contains(tc.expected, result) {
-				t.Errorf("Expected time ago containing %q, got %q", tc.expected, result)
-			}
-		})
-	}
-}
-
 func TestProcessWithModel(t *testing.T) {
 	// Create a test service
 	service := NewAIService()
@@ -432,4 +371,426 @@ func TestProcessResults(t *testing.T) {
 		// In a real implementation, this should check for context.Canceled error
 		t.Log("Context cancellation test completed, error:", err)
 	})
-}
\ No newline at end of file
+}
+
+func TestPromptTemplateOverrideAndReload(t *testing.T) {
+	dir := t.TempDir()
+	override := filepath.Join(dir, "claude.tmpl")
+	if err := os.WriteFile(override, []byte("Custom prompt for {{.Query}}"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	prompts, err := LoadPromptTemplates(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error loading templates: %v", err)
+	}
+
+	var out strings.Builder
+	if err := prompts.Get("claude").Execute(&out, promptData{Query: "test"}); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	if out.String() != "Custom prompt for test" {
+		t.Errorf("Expected override to be used, got %q", out.String())
+	}
+
+	// Built-in templates are still available alongside overrides
+	if prompts.Get("gemini") == nil {
+		t.Error("Expected built-in gemini template to be loaded")
+	}
+
+	// A broken edit must not replace the working templates
+	if err := os.WriteFile(override, []byte("Broken {{.Query"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := prompts.Reload(); err == nil {
+		t.Error("Expected reload of a broken template to fail")
+	}
+
+	out.Reset()
+	if err := prompts.Get("claude").Execute(&out, promptData{Query: "test"}); err != nil {
+		t.Fatalf("Unexpected error executing template: %v", err)
+	}
+	if out.String() != "Custom prompt for test" {
+		t.Errorf("Expected previous template to stay in use, got %q", out.String())
+	}
+}
+
+func TestSubredditInstructionsInPrompt(t *testing.T) {
+	service := NewAIService()
+	service.SetPromptConfig(config.PromptConfig{
+		SubredditInstructions: map[string]string{"wallstreetbets": "Treat content as satire."},
+		SubredditShare:        0.5,
+	})
+
+	results := []models.SearchResult{
+		{Title: "YOLO", Subreddit: "wallstreetbets"},
+		{Title: "Gains", Subreddit: "WallStreetBets"},
+		{Title: "Index funds", Subreddit: "investing"},
+	}
+
+	prompt, err := service.buildPrompt("stock tips", results, service.modelConfig["Claude"], AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Treat content as satire.") {
+		t.Error("Expected subreddit instructions when the community dominates the results")
+	}
+
+	// Exactly the configured share still counts as predominant
+	prompt, err = service.buildPrompt("stock tips", results[1:], service.modelConfig["Claude"], AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Treat content as satire.") {
+		t.Error("Expected instructions at exactly the configured share")
+	}
+
+	// Results from other communities don't trigger the instructions
+	prompt, err = service.buildPrompt("stock tips", results[2:], service.modelConfig["Claude"], AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(prompt, "Treat content as satire.") {
+		t.Error("Expected no subreddit instructions without matching results")
+	}
+}
+
+func TestResultContentLength(t *testing.T) {
+	service := NewAIService()
+	model := service.modelConfig["Claude"]
+	historians := models.SearchResult{Subreddit: "AskHistorians"}
+	memes := models.SearchResult{Subreddit: "memes"}
+
+	if got := service.contentLength(memes, model, AnswerOptions{}); got != model.MaxContentLength {
+		t.Errorf("Expected the model's cap without configuration, got %d", got)
+	}
+
+	service.SetPromptConfig(config.PromptConfig{
+		MaxContentLength:       600,
+		SubredditContentLength: map[string]int{"askhistorians": 3000},
+	})
+	if got := service.contentLength(historians, model, AnswerOptions{}); got != 3000 {
+		t.Errorf("Expected the subreddit's configured length, got %d", got)
+	}
+	if got := service.contentLength(memes, model, AnswerOptions{}); got != 600 {
+		t.Errorf("Expected the configured length, got %d", got)
+	}
+	if got := service.contentLength(historians, model, AnswerOptions{MaxContentLength: 200}); got != 200 {
+		t.Errorf("Expected the requested length to win, got %d", got)
+	}
+
+	long := models.SearchResult{Title: "Why did Rome fall?", Subreddit: "AskHistorians", Content: strings.Repeat("history ", 300)}
+	prompt, err := service.buildPrompt("rome", []models.SearchResult{long}, model, AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Count(prompt, "history") < 300 {
+		t.Error("Expected the long-form result's full content in the prompt")
+	}
+}
+
+func TestAnswerLanguageInPrompt(t *testing.T) {
+	service := NewAIService()
+	results := []models.SearchResult{{Title: "Receta de paella", Subreddit: "spain"}}
+
+	prompt, err := service.buildPrompt("paella recipe", results, service.modelConfig["Claude"], AnswerOptions{Language: "German"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(prompt, "Write your entire response in German") {
+		t.Error("Expected the prompt to request the answer language")
+	}
+	if !strings.Contains(prompt, "Keep citation markers") {
+		t.Error("Expected the prompt to preserve citation markers")
+	}
+}
+
+func TestRankingPromptEntities(t *testing.T) {
+	service := NewAIService()
+	results := []models.SearchResult{{Title: "LG C3 vs Sony A95L", Subreddit: "4kTV"}}
+	entities := []models.Entity{{Name: "LG C3", Mentions: 7, Results: 4}}
+
+	prompt, err := service.buildPrompt("top 5 TVs", results, service.modelConfig["Claude"], AnswerOptions{Entities: entities})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "LG C3 (7 mentions in 4 results)") {
+		t.Error("Expected ranking prompts to list the most mentioned items")
+	}
+
+	prompt, err = service.buildPrompt("how do TVs work", results, service.modelConfig["Claude"], AnswerOptions{Entities: entities})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(prompt, "mentions in") {
+		t.Error("Expected only ranking prompts to list items")
+	}
+}
+
+func TestControversialPrompt(t *testing.T) {
+	service := NewAIService()
+	results := []models.SearchResult{{Title: "Is keto worth it?", Subreddit: "nutrition", Type: "post"}}
+
+	prompt, err := service.buildPrompt("is keto worth it", results, service.modelConfig["Claude"], AnswerOptions{Controversial: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Reddit is split on this question") || !strings.Contains(prompt, "its own citations") {
+		t.Error("Expected the prompt to ask for both camps with separate citations")
+	}
+
+	prompt, _ = service.buildPrompt("is keto worth it", results, service.modelConfig["Claude"], AnswerOptions{})
+	if strings.Contains(prompt, "Reddit is split") {
+		t.Error("Expected no split instructions for agreeing results")
+	}
+}
+
+func TestCleanLanguagePrompt(t *testing.T) {
+	service := NewAIService()
+	results := []models.SearchResult{{Title: "Worst boss stories", Subreddit: "antiwork", Type: "post"}}
+
+	prompt, err := service.buildPrompt("worst boss stories", results, service.modelConfig["Claude"], AnswerOptions{CleanLanguage: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(prompt, "Paraphrase quotes that contain profanity") {
+		t.Error("Expected the prompt to ask for profanity to be paraphrased")
+	}
+
+	prompt, _ = service.buildPrompt("worst boss stories", results, service.modelConfig["Claude"], AnswerOptions{})
+	if strings.Contains(prompt, "workplace tool") {
+		t.Error("Expected no clean language instructions unless asked for")
+	}
+}
+
+func TestAnswerFormatInstructions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		format   string
+		expected string
+	}{
+		{"Table for comparison", "python vs go for web servers", models.AnswerFormatTable, "comparison table"},
+		{"Bullets for ranking", "best mechanical keyboards", models.AnswerFormatBullets, "numbered Markdown list"},
+		{"Essay", "is remote work productive", models.AnswerFormatEssay, "flowing prose"},
+		{"Default", "is remote work productive", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			instructions := answerFormatInstructions(tc.format, utils.ParseQuery(tc.query))
+
+			if tc.expected == "" {
+				if instructions != "" {
+					t.Errorf("Expected no format instructions, got %q", instructions)
+				}
+				return
+			}
+			if !strings.Contains(instructions, tc.expected) {
+				t.Errorf("Expected instructions containing %q, got %q", tc.expected, instructions)
+			}
+		})
+	}
+}
+
+func TestParseUnsupportedStatements(t *testing.T) {
+	response := `Here is my review.
+BEGIN_UNSUPPORTED
+- "Battery lasts 20 hours" - no result mentions battery life
+- "Most users prefer the Pro model" - result [2] says the opposite
+END_UNSUPPORTED`
+
+	statements, err := parseUnsupportedStatements(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d: %v", len(statements), statements)
+	}
+	if !strings.Contains(statements[0], "Battery lasts 20 hours") {
+		t.Errorf("Unexpected first statement: %q", statements[0])
+	}
+
+	// An empty section means everything was supported
+	statements, err = parseUnsupportedStatements("BEGIN_UNSUPPORTED\nEND_UNSUPPORTED")
+	if err != nil || len(statements) != 0 {
+		t.Errorf("Expected no statements and no error, got %v, %v", statements, err)
+	}
+
+	// Missing markers are an error, not a clean bill of health
+	if _, err := parseUnsupportedStatements("Everything looks fine."); err == nil {
+		t.Error("Expected an error when markers are missing")
+	}
+}
+
+func TestParseComparison(t *testing.T) {
+	response := "```json\n" + `{
+  "criteria": [
+    {"name": "Speed", "evidence": [
+      {"summary": "Compiled and fast [2].", "citations": [2, 2, 9]},
+      {"summary": "Slower, but fast enough for scripts.", "citations": [1]}
+    ]},
+    {"name": "Tooling", "evidence": [{"summary": "Built-in formatter.", "citations": [3]}]},
+    {"name": "Jobs", "evidence": [{"summary": ""}, {"summary": " "}]}
+  ],
+  "verdict": "Go for services, Python for scripts."
+}` + "\n```"
+
+	comparison, err := parseComparison(response, []string{"Go", "Python"}, 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(comparison.Criteria) != 2 {
+		t.Fatalf("Expected criteria without evidence to be dropped, got %+v", comparison.Criteria)
+	}
+
+	speed := comparison.Criteria[0]
+	if len(speed.Evidence) != 2 || len(speed.Evidence[0].Citations) != 1 || speed.Evidence[0].Citations[0] != 2 {
+		t.Errorf("Expected duplicate and out of range citations to be dropped, got %+v", speed.Evidence)
+	}
+	// Missing evidence is padded so every row has a cell per option
+	tooling := comparison.Criteria[1]
+	if len(tooling.Evidence) != 2 || tooling.Evidence[1].Summary != "" {
+		t.Errorf("Expected an empty cell for Python tooling, got %+v", tooling.Evidence)
+	}
+	if comparison.Verdict != "Go for services, Python for scripts." {
+		t.Errorf("Unexpected verdict %q", comparison.Verdict)
+	}
+
+	if _, err := parseComparison(`{"criteria": []}`, []string{"Go", "Python"}, 5); err == nil {
+		t.Error("Expected an error for a comparison without criteria")
+	}
+}
+
+func TestProcessResultsComparison(t *testing.T) {
+	service := NewAIService()
+	results := []models.SearchResult{{ID: "r1", Title: "Go or Python for a backend?", Subreddit: "golang", Type: "post"}}
+
+	result, err := service.ProcessResultsWithOptions(context.Background(), "python vs go", results, "Claude", AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Comparison == nil {
+		t.Fatal("Expected a comparison for an \"X vs Y\" query")
+	}
+	if len(result.Comparison.Options) != 2 || result.Comparison.Options[0] != "python" {
+		t.Errorf("Unexpected options %v", result.Comparison.Options)
+	}
+
+	result, err = service.ProcessResultsWithOptions(context.Background(), "python tutorials", results, "Claude", AnswerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Comparison != nil {
+		t.Error("Expected no comparison for other queries")
+	}
+}
+
+func TestParseModelResponseJSONMode(t *testing.T) {
+	service := NewAIService()
+	modelConfig := service.modelConfig["Google Gemini"]
+
+	response := "```json\n" + `{
+  "reasoning_steps": [
+    {"title": "Evaluating sources", "content": "Result [1] is a first-hand account."},
+    {"title": "", "content": "Result [2] disagrees."}
+  ],
+  "answer": "Most users agree [1]."
+}` + "\n```"
+
+	reasoning, answer, steps := service.parseModelResponse(response, modelConfig)
+	if answer != "Most users agree [1]." {
+		t.Errorf("Unexpected answer: %q", answer)
+	}
+	if len(steps) != 2 || steps[0].Title != "Evaluating sources" || steps[1].Title == "" {
+		t.Errorf("Expected two titled steps, got %+v", steps)
+	}
+	if !strings.Contains(reasoning, "## Evaluating sources") {
+		t.Errorf("Expected reasoning to be rendered from steps, got %q", reasoning)
+	}
+
+	// Malformed JSON falls back to the text pipeline
+	text := "BEGIN_REASONING\nSome reasoning.\nEND_REASONING\n\nBEGIN_ANSWER\nThe answer.\nEND_ANSWER"
+	_, answer, _ = service.parseModelResponse(text, modelConfig)
+	if answer != "The answer." {
+		t.Errorf("Expected fallback parsing, got answer %q", answer)
+	}
+}
+
+func TestSelectConsistentAnswer(t *testing.T) {
+	cite := func(indexes ...int) []models.Citation {
+		var citations []models.Citation
+		for _, index := range indexes {
+			citations = append(citations, models.Citation{Index: index})
+		}
+		return citations
+	}
+
+	answers := []*AnswerResult{
+		{Answer: "Most users recommend the Keychron keyboard for programming", Citations: cite(1, 2)},
+		{Answer: "Users mostly recommend the Keychron keyboard for programming", Citations: cite(1, 2)},
+		{Answer: "Nobody agrees on anything here", Citations: cite(5)},
+	}
+
+	best, agreement := selectConsistentAnswer(answers)
+	if best == 2 {
+		t.Errorf("Expected one of the agreeing answers to be selected, got the outlier")
+	}
+	if agreement <= 0 || agreement >= 1 {
+		t.Errorf("Expected partial agreement, got %.2f", agreement)
+	}
+
+	if overlap := citationOverlap(cite(1, 2), cite(2, 3)); overlap < 0.33 || overlap > 0.34 {
+		t.Errorf("Expected Jaccard overlap of 1/3, got %.2f", overlap)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	t.Setenv("GOOGLE_API_KEY", "secret-key")
+	t.Setenv("DEEPSEEK_API_KEY", "")
+
+	service := NewAIService()
+	for i := 0; i < 3; i++ {
+		service.health.record("Google Gemini", errors.New("request to https://example.com/?key=secret-key failed"))
+	}
+
+	catalog := service.Catalog()
+	infos := make(map[string]models.ModelInfo)
+	for _, info := range catalog.Models {
+		infos[info.Name] = info
+	}
+
+	if _, ok := infos["Default"]; ok {
+		t.Error("Expected the fallback configuration to be hidden")
+	}
+	if !infos[catalog.DefaultModel].Default {
+		t.Errorf("Expected %s to be marked as default", catalog.DefaultModel)
+	}
+
+	gemini := infos["Google Gemini"]
+	if gemini.Mode != models.ModelModeLive || gemini.DisplayName != "Gemini 2.0 Flash" || gemini.ModelID != "gemini-2.0-flash" {
+		t.Errorf("Unexpected Gemini info: %+v", gemini)
+	}
+	if gemini.Health.Status != models.ModelHealthDown || strings.Contains(gemini.Health.LastError, "secret-key") {
+		t.Errorf("Expected Gemini to be down with a redacted error, got %+v", gemini.Health)
+	}
+
+	if deepseek := infos["DeepSeek R1"]; deepseek.Mode != models.ModelModeMock || deepseek.Health.Status != models.ModelHealthUnknown {
+		t.Errorf("Expected DeepSeek in mock mode with unknown health, got %+v", deepseek)
+	}
+}
+
+func TestExtractCitationContextUnicode(t *testing.T) {
+	answer := "Intro. " + strings.Repeat("Los usuarios recomiendan el café de Colombia ", 8) + "[1]. Fin."
+	context := extractCitationContext(answer, 1)
+	if !utf8.ValidString(context) {
+		t.Errorf("Citation context is not valid UTF-8: %q", context)
+	}
+	if n := utf8.RuneCountInString(context); n > 200 {
+		t.Errorf("Expected at most 200 characters, got %d", n)
+	}
+	if !strings.HasPrefix(context, "Los usuarios") {
+		t.Errorf("Expected the citing sentence, got %q", context)
+	}
+}
@@ -0,0 +1,284 @@
+// File: backend/internal/services/ai_vision.go
+
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// supportedImageTypes are the image formats every vision provider accepts
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// promptImage is an image attached to a model call
+type promptImage struct {
+	Result    int // 1-based index of the result in the prompt
+	MediaType string
+	Data      []byte
+}
+
+// base64Data returns the image encoded for provider requests
+func (img promptImage) base64Data() string {
+	return base64.StdEncoding.EncodeToString(img.Data)
+}
+
+// dataURL returns the image as a data: URL
+func (img promptImage) dataURL() string {
+	return "data:" + img.MediaType + ";base64," + img.base64Data()
+}
+
+// imageCandidate is an image post's preferred image and its fallbacks
+type imageCandidate struct {
+	Result int
+	URLs   []string
+}
+
+// imageFetcher downloads post images from Reddit's media hosts
+type imageFetcher struct {
+	cfg    config.VisionConfig
+	client *http.Client
+}
+
+// SetVisionConfig controls attaching post images for models that accept
+// them. It should be called before serving requests.
+func (s *AIService) SetVisionConfig(cfg config.VisionConfig) {
+	if !cfg.Enabled {
+		s.images = nil
+		return
+	}
+	s.images = &imageFetcher{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return errors.New("too many redirects")
+				}
+				return checkRedditMediaURL(req.URL)
+			},
+		},
+	}
+}
+
+// promptImages downloads images for the top image posts among the results
+// included in the prompt. It returns nothing when vision is disabled or the
+// model doesn't accept images; images that can't be fetched are skipped.
+func (s *AIService) promptImages(ctx context.Context, results []models.SearchResult, modelConfig *AIModelConfig) []promptImage {
+	if s.images == nil || !modelConfig.Vision {
+		return nil
+	}
+
+	resultLimit := modelConfig.MaxResultsInPrompt
+	if resultLimit <= 0 || resultLimit > len(results) {
+		resultLimit = len(results)
+	}
+	candidates := imageCandidates(results[:resultLimit], s.images.cfg.MaxImages)
+
+	fetched := make([]*promptImage, len(candidates))
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		wg.Add(1)
+		go func(i int, candidate imageCandidate) {
+			defer wg.Done()
+			for _, imageURL := range candidate.URLs {
+				img, err := s.images.fetch(ctx, imageURL)
+				if err != nil {
+					log.Printf("Skipping image %s for result [%d]: %v", imageURL, candidate.Result, err)
+					continue
+				}
+				img.Result = candidate.Result
+				fetched[i] = img
+				return
+			}
+		}(i, candidate)
+	}
+	wg.Wait()
+
+	var images []promptImage
+	for _, img := range fetched {
+		if img != nil {
+			images = append(images, *img)
+		}
+	}
+	return images
+}
+
+// imageCandidates picks up to maxImages image posts in rank order. Each
+// prefers its first still image, falling back to the thumbnail. Animated
+// images and videos are skipped unless they have a thumbnail.
+func imageCandidates(results []models.SearchResult, maxImages int) []imageCandidate {
+	var candidates []imageCandidate
+	for i, result := range results {
+		if len(candidates) >= maxImages {
+			break
+		}
+		if result.Type != "post" || result.Media == nil {
+			continue
+		}
+
+		var urls []string
+		for _, image := range result.Media.Images {
+			if !image.Animated {
+				urls = append(urls, image.URL)
+				break
+			}
+		}
+		if result.Media.Thumbnail != "" {
+			urls = append(urls, result.Media.Thumbnail)
+		}
+		if len(urls) > 0 {
+			candidates = append(candidates, imageCandidate{Result: i + 1, URLs: urls})
+		}
+	}
+	return candidates
+}
+
+// fetch downloads an image, refusing hosts other than Reddit's and images
+// over the size limit
+func (f *imageFetcher) fetch(ctx context.Context, rawURL string) (*promptImage, error) {
+	imageURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := checkRedditMediaURL(imageURL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image returned status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !supportedImageTypes[mediaType] {
+		return nil, fmt.Errorf("unsupported image type '%s'", mediaType)
+	}
+
+	maxBytes := int64(f.cfg.MaxImageKB) << 10
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("image is larger than %dKB", f.cfg.MaxImageKB)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("image is larger than %dKB", f.cfg.MaxImageKB)
+	}
+
+	return &promptImage{MediaType: mediaType, Data: data}, nil
+}
+
+// checkRedditMediaURL allows only HTTPS URLs on Reddit's image hosts, so
+// post data can't make the server fetch arbitrary addresses
+func checkRedditMediaURL(u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+	if u.Scheme != "https" ||
+		!(strings.HasSuffix(host, ".redd.it") || strings.HasSuffix(host, ".redditmedia.com")) {
+		return fmt.Errorf("not a Reddit media URL: %s", u.Redacted())
+	}
+	return nil
+}
+
+// imageInstructions tells the model which results the attached images
+// belong to
+func imageInstructions(images []promptImage) string {
+	if len(images) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nATTACHED IMAGES:\nThe images attached to this message come from image posts in the results, in this order:\n")
+	for i, img := range images {
+		b.WriteString(fmt.Sprintf("- Image %d is from result [%d]\n", i+1, img.Result))
+	}
+	b.WriteString("Use what the images show where it helps answer the query, and cite the result number as you would for its text. Don't describe images that aren't relevant.")
+	return b.String()
+}
+
+// anthropicContentBlock is a block in an Anthropic message
+type anthropicContentBlock struct {
+	Type   string                `json:"type"`
+	Text   string                `json:"text,omitempty"`
+	Source *anthropicImageSource `json:"source,omitempty"`
+}
+
+// anthropicImageSource is the data of an Anthropic image block
+type anthropicImageSource struct {
+	Type      string `json:"type"`
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
+}
+
+// anthropicContent returns the user message content for a call: the prompt
+// alone, or image blocks followed by the prompt
+func anthropicContent(call modelCall) interface{} {
+	if len(call.Images) == 0 {
+		return call.Prompt
+	}
+
+	blocks := make([]anthropicContentBlock, 0, len(call.Images)+1)
+	for _, img := range call.Images {
+		blocks = append(blocks, anthropicContentBlock{
+			Type:   "image",
+			Source: &anthropicImageSource{Type: "base64", MediaType: img.MediaType, Data: img.base64Data()},
+		})
+	}
+	return append(blocks, anthropicContentBlock{Type: "text", Text: call.Prompt})
+}
+
+// openAIContentPart is a part of an OpenAI chat message
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+// openAIImageURL is the image of an OpenAI image part
+type openAIImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail"`
+}
+
+// openAIContent returns the user message content for a call: the prompt
+// alone, or the prompt followed by images at low detail to bound cost
+func openAIContent(call modelCall) interface{} {
+	if len(call.Images) == 0 {
+		return call.Prompt
+	}
+
+	parts := make([]openAIContentPart, 0, len(call.Images)+1)
+	parts = append(parts, openAIContentPart{Type: "text", Text: call.Prompt})
+	for _, img := range call.Images {
+		parts = append(parts, openAIContentPart{
+			Type:     "image_url",
+			ImageURL: &openAIImageURL{URL: img.dataURL(), Detail: "low"},
+		})
+	}
+	return parts
+}
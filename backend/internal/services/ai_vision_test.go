@@ -0,0 +1,97 @@
+// File: backend/internal/services/ai_vision_test.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestImageCandidates(t *testing.T) {
+	results := []models.SearchResult{
+		{Type: "post", Media: &models.Media{
+			Thumbnail: "https://b.thumbs.redditmedia.com/1.jpg",
+			Images: []models.MediaImage{
+				{URL: "https://preview.redd.it/anim.gif?format=mp4", Animated: true},
+				{URL: "https://preview.redd.it/still.jpg"},
+			},
+		}},
+		{Type: "post"},
+		{Type: "comment", Media: &models.Media{Thumbnail: "https://b.thumbs.redditmedia.com/c.jpg"}},
+		{Type: "post", Media: &models.Media{Video: &models.MediaVideo{URL: "https://v.redd.it/x"}}},
+		{Type: "post", Media: &models.Media{Thumbnail: "https://b.thumbs.redditmedia.com/5.jpg"}},
+		{Type: "post", Media: &models.Media{Thumbnail: "https://b.thumbs.redditmedia.com/6.jpg"}},
+	}
+
+	candidates := imageCandidates(results, 2)
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %+v", candidates)
+	}
+	if first := candidates[0]; first.Result != 1 || len(first.URLs) != 2 ||
+		first.URLs[0] != "https://preview.redd.it/still.jpg" || first.URLs[1] != "https://b.thumbs.redditmedia.com/1.jpg" {
+		t.Errorf("Unexpected first candidate: %+v", first)
+	}
+	if candidates[1].Result != 5 {
+		t.Errorf("Expected the second candidate to be result 5, got %d", candidates[1].Result)
+	}
+}
+
+func TestCheckRedditMediaURL(t *testing.T) {
+	allowed := []string{"https://i.redd.it/a.jpg", "https://preview.redd.it/a.jpg?s=1", "https://b.thumbs.redditmedia.com/a.jpg"}
+	refused := []string{"http://i.redd.it/a.jpg", "https://redd.it.example.com/a.jpg", "https://169.254.169.254/latest", "https://evilredd.it/a.jpg"}
+
+	for _, raw := range allowed {
+		u, _ := url.Parse(raw)
+		if err := checkRedditMediaURL(u); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", raw, err)
+		}
+	}
+	for _, raw := range refused {
+		u, _ := url.Parse(raw)
+		if err := checkRedditMediaURL(u); err == nil {
+			t.Errorf("Expected %s to be refused", raw)
+		}
+	}
+}
+
+func TestVisionContent(t *testing.T) {
+	call := modelCall{Prompt: "prompt", Images: []promptImage{{Result: 3, MediaType: "image/png", Data: []byte("png")}}}
+
+	anthropic, _ := json.Marshal(anthropicContent(call))
+	if want := `[{"type":"image","source":{"type":"base64","media_type":"image/png","data":"cG5n"}},{"type":"text","text":"prompt"}]`; string(anthropic) != want {
+		t.Errorf("Unexpected Anthropic content: %s", anthropic)
+	}
+	openai, _ := json.Marshal(openAIContent(call))
+	if want := `[{"type":"text","text":"prompt"},{"type":"image_url","image_url":{"url":"data:image/png;base64,cG5n","detail":"low"}}]`; string(openai) != want {
+		t.Errorf("Unexpected OpenAI content: %s", openai)
+	}
+	if text, _ := json.Marshal(openAIContent(modelCall{Prompt: "prompt"})); string(text) != `"prompt"` {
+		t.Errorf("Expected plain text content without images, got %s", text)
+	}
+
+	if instructions := imageInstructions(call.Images); !strings.Contains(instructions, "Image 1 is from result [3]") {
+		t.Errorf("Unexpected image instructions: %s", instructions)
+	}
+}
+
+func TestPromptImagesRequiresVisionModel(t *testing.T) {
+	service := NewAIService()
+	cfg := config.Default().Vision
+	cfg.Enabled = true
+	service.SetVisionConfig(cfg)
+
+	// Non-Reddit hosts are never fetched, so this makes no requests
+	results := []models.SearchResult{{Type: "post", Media: &models.Media{Thumbnail: "https://example.com/t.jpg"}}}
+	if images := service.promptImages(context.Background(), results, service.modelConfig["DeepSeek R1"]); images != nil {
+		t.Errorf("Expected no images for a text-only model, got %d", len(images))
+	}
+	if images := service.promptImages(context.Background(), results, service.modelConfig["GPT-4o"]); len(images) != 0 {
+		t.Errorf("Expected the non-Reddit image to be skipped, got %d", len(images))
+	}
+}
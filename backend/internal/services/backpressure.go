@@ -0,0 +1,142 @@
+// File: backend/internal/services/backpressure.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// ErrOverloaded is returned when the server is at its limit of concurrent
+// searches or in-flight result memory. Clients should retry shortly.
+var ErrOverloaded = errors.New("server is busy, please retry shortly")
+
+// resultOverheadBytes approximates the per-result cost beyond its strings
+const resultOverheadBytes = 256
+
+// backpressure limits how many searches run at once and how much result
+// memory they hold, so a burst of large queries can't exhaust the process
+type backpressure struct {
+	searches         chan struct{}
+	waiting          atomic.Int64 // Searches queued for a slot
+	queueTimeout     time.Duration
+	maxResultBytes   int64
+	resultBytes      atomic.Int64
+	maxResponseBytes int
+}
+
+// newBackpressure creates limits from configuration
+func newBackpressure(cfg config.LimitsConfig) *backpressure {
+	return &backpressure{
+		searches:         make(chan struct{}, cfg.MaxConcurrentSearches),
+		queueTimeout:     cfg.QueueTimeout,
+		maxResultBytes:   int64(cfg.MaxResultMemoryMB) << 20,
+		maxResponseBytes: cfg.MaxResponseKB << 10,
+	}
+}
+
+// acquireSearch waits up to the queue timeout for a search slot
+func (b *backpressure) acquireSearch(ctx context.Context) error {
+	// Take a free slot without racing the timer
+	select {
+	case b.searches <- struct{}{}:
+		return nil
+	default:
+	}
+
+	b.waiting.Add(1)
+	defer b.waiting.Add(-1)
+	timer := time.NewTimer(b.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case b.searches <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%w: too many concurrent searches", ErrOverloaded)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseSearch frees a slot taken by acquireSearch
+func (b *backpressure) releaseSearch() {
+	<-b.searches
+}
+
+// load reports the searches running and waiting against the limits
+func (b *backpressure) load() models.SearchLoad {
+	return models.SearchLoad{
+		Running:        len(b.searches),
+		Waiting:        int(b.waiting.Load()),
+		MaxConcurrent:  cap(b.searches),
+		ResultBytes:    b.resultBytes.Load(),
+		MaxResultBytes: b.maxResultBytes,
+	}
+}
+
+// reserveResults accounts for results held by a running search. It returns a
+// release function, or ErrOverloaded if holding them would exceed the limit.
+func (b *backpressure) reserveResults(results []models.SearchResult) (func(), error) {
+	size := resultsSize(results)
+	if b.resultBytes.Add(size) > b.maxResultBytes {
+		b.resultBytes.Add(-size)
+		return nil, fmt.Errorf("%w: too much result data in flight", ErrOverloaded)
+	}
+	return func() { b.resultBytes.Add(-size) }, nil
+}
+
+// resultsSize estimates the memory held by results
+func resultsSize(results []models.SearchResult) int64 {
+	var size int64
+	for _, result := range results {
+		size += resultOverheadBytes + int64(len(result.ID)+len(result.Title)+len(result.Subreddit)+
+			len(result.Author)+len(result.Content)+len(result.URL)+len(result.Raw))
+		for _, highlight := range result.Highlights {
+			size += int64(len(highlight))
+		}
+		if result.Media != nil {
+			size += resultOverheadBytes * int64(1+len(result.Media.Images))
+		}
+	}
+	return size
+}
+
+// truncateResponse drops the lowest-ranked results until the response
+// encodes within the size limit, adding a warning when it does
+func (b *backpressure) truncateResponse(response *models.SearchResponse) {
+	if b.maxResponseBytes <= 0 || encodedSize(response) <= b.maxResponseBytes {
+		return
+	}
+
+	// Find the most results that fit; the answer and citations always stay
+	all := response.Results
+	kept := sort.Search(len(all)+1, func(n int) bool {
+		response.Results = all[:n]
+		return encodedSize(response) > b.maxResponseBytes
+	}) - 1
+	if kept < 0 {
+		kept = 0
+	}
+
+	response.Results = all[:kept]
+	response.Warnings = append(response.Warnings, fmt.Sprintf(
+		"Response too large: showing the top %d of %d results", kept, len(all)))
+}
+
+// encodedSize returns the JSON size of a response
+func encodedSize(response *models.SearchResponse) int {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
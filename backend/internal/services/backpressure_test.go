@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestBackpressure(t *testing.T) {
+	limits := newBackpressure(config.LimitsConfig{
+		MaxConcurrentSearches: 1,
+		QueueTimeout:          10 * time.Millisecond,
+		MaxResultMemoryMB:     1,
+		MaxResponseKB:         64,
+	})
+
+	if err := limits.acquireSearch(context.Background()); err != nil {
+		t.Fatalf("acquireSearch: %v", err)
+	}
+	if load := limits.load(); load.Running != 1 || load.MaxConcurrent != 1 || load.Waiting != 0 {
+		t.Errorf("load with one search running = %+v", load)
+	}
+	if err := limits.acquireSearch(context.Background()); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("second search: got %v, want ErrOverloaded", err)
+	}
+	limits.releaseSearch()
+
+	// Each result is about 10KB, so 100 fit in 1MB but 200 don't
+	results := make([]models.SearchResult, 100)
+	for i := range results {
+		results[i] = models.SearchResult{ID: "id", Title: "title", Content: strings.Repeat("x", 10000)}
+	}
+	release, err := limits.reserveResults(results)
+	if err != nil {
+		t.Fatalf("reserveResults: %v", err)
+	}
+	if _, err := limits.reserveResults(results); !errors.Is(err, ErrOverloaded) {
+		t.Errorf("second reservation: got %v, want ErrOverloaded", err)
+	}
+	release()
+	if limits.resultBytes.Load() != 0 {
+		t.Errorf("result bytes after release = %d, want 0", limits.resultBytes.Load())
+	}
+
+	// A 1MB response is cut down to the 64KB limit, best results first
+	response := &models.SearchResponse{Results: results, TotalCount: len(results), Answer: "answer"}
+	limits.truncateResponse(response)
+	if n := len(response.Results); n == 0 || n >= 7 {
+		t.Errorf("kept %d results, want the few that fit in 64KB", n)
+	}
+	if size := encodedSize(response); size > 64<<10 {
+		t.Errorf("truncated response is %d bytes", size)
+	}
+	if len(response.Warnings) != 1 || response.TotalCount != 100 {
+		t.Errorf("warnings %v, total %d; want one warning and the original total", response.Warnings, response.TotalCount)
+	}
+}
@@ -0,0 +1,38 @@
+// File: backend/internal/services/budget.go
+
+package services
+
+import (
+	"context"
+	"log"
+)
+
+// SpendBudget caps what AI providers may cost. Exceeded reports whether a
+// provider's budget is spent, and the model to answer its calls instead,
+// empty for mock responses. Charge records the estimated tokens of a call.
+type SpendBudget interface {
+	Exceeded(ctx context.Context, provider string) (fallback string, exceeded bool)
+	Charge(ctx context.Context, provider string, promptTokens, responseTokens int64)
+}
+
+// SetBudget limits AI calls to a spend budget. A nil budget disables it. It
+// should be called before serving requests.
+func (s *AIService) SetBudget(budget SpendBudget) {
+	s.budget = budget
+}
+
+// callOverBudget answers a call to a model whose provider has spent its
+// budget: with the fallback model when its provider is within budget, and
+// with a mock response otherwise
+func (s *AIService) callOverBudget(ctx context.Context, call modelCall, modelConfig *AIModelConfig, fallback string) (string, error) {
+	fallbackConfig, ok := s.modelConfig[fallback]
+	if ok && fallbackConfig.Provider != modelConfig.Provider {
+		if _, exceeded := s.budget.Exceeded(ctx, fallbackConfig.Provider); !exceeded {
+			log.Printf("%s is over its AI budget; answering with %s instead", modelConfig.Provider, fallbackConfig.Name)
+			return s.callProvider(ctx, call, fallbackConfig)
+		}
+	}
+
+	log.Printf("%s is over its AI budget; returning a mock response", modelConfig.Provider)
+	return s.mockResponse(call), nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// stubBudget exceeds the budgets of the providers it maps to a fallback
+type stubBudget struct {
+	fallbacks map[string]string
+	checked   []string
+}
+
+func (b *stubBudget) Exceeded(ctx context.Context, provider string) (string, bool) {
+	b.checked = append(b.checked, provider)
+	fallback, exceeded := b.fallbacks[provider]
+	return fallback, exceeded
+}
+
+func (b *stubBudget) Charge(ctx context.Context, provider string, promptTokens, responseTokens int64) {
+}
+
+func TestCallModelOverBudget(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	service := NewAIService()
+	claude := service.modelConfig["Claude"]
+
+	tests := []struct {
+		name      string
+		fallbacks map[string]string
+		checked   []string
+	}{
+		{"Within budget", nil, []string{"Anthropic"}},
+		{"Fallback within budget", map[string]string{"Anthropic": "GPT-4o"}, []string{"Anthropic", "OpenAI"}},
+		{"Fallback over budget", map[string]string{"Anthropic": "GPT-4o", "OpenAI": ""}, []string{"Anthropic", "OpenAI"}},
+		{"No fallback", map[string]string{"Anthropic": ""}, []string{"Anthropic"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &stubBudget{fallbacks: tt.fallbacks}
+			service.SetBudget(budget)
+			response, err := service.callModel(context.Background(), modelCall{Prompt: "prompt"}, claude)
+			if err != nil || response == "" {
+				t.Fatalf("callModel = %q, %v; want a response", response, err)
+			}
+			if !reflect.DeepEqual(budget.checked, tt.checked) {
+				t.Errorf("checked budgets of %v, want %v", budget.checked, tt.checked)
+			}
+		})
+	}
+}
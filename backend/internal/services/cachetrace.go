@@ -0,0 +1,48 @@
+// File: backend/internal/services/cachetrace.go
+
+package services
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// CacheTracker records whether the Reddit searches made with a context were
+// answered from the result cache, e.g. for traffic analytics
+type CacheTracker struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type cacheTrackerKey struct{}
+
+// WithCacheTracker returns a context whose Reddit result cache lookups are
+// recorded by the returned tracker
+func WithCacheTracker(ctx context.Context) (context.Context, *CacheTracker) {
+	tracker := &CacheTracker{}
+	return context.WithValue(ctx, cacheTrackerKey{}, tracker), tracker
+}
+
+// Hit reports whether every lookup was a cache hit. ok is false when no
+// lookups were made, e.g. when the search failed before reaching Reddit.
+func (t *CacheTracker) Hit() (hit, ok bool) {
+	hits, misses := t.hits.Load(), t.misses.Load()
+	if hits+misses == 0 {
+		return false, false
+	}
+	return misses == 0, true
+}
+
+// trackCacheLookup records a result cache lookup on the context's tracker, if
+// it has one
+func trackCacheLookup(ctx context.Context, hit bool) {
+	tracker, ok := ctx.Value(cacheTrackerKey{}).(*CacheTracker)
+	if !ok {
+		return
+	}
+	if hit {
+		tracker.hits.Add(1)
+	} else {
+		tracker.misses.Add(1)
+	}
+}
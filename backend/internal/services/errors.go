@@ -0,0 +1,65 @@
+// File: backend/internal/services/errors.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// Upstream failures, wrapped by the errors Reddit and AI provider calls
+// return so callers can tell them apart with errors.Is
+var (
+	ErrRedditRateLimited = errors.New("rate limited by Reddit API")
+	ErrRedditUnavailable = errors.New("Reddit API unavailable")
+	ErrAIRateLimited     = errors.New("rate limited by AI provider")
+	ErrAIUnavailable     = errors.New("AI provider unavailable")
+)
+
+// ErrRedditLoginRequired is returned for searches scoped to a Reddit user's
+// own content when the caller isn't signed in with Reddit, or Reddit revoked
+// the server's access to their account
+var ErrRedditLoginRequired = errors.New("sign in with Reddit to search your own content")
+
+// ErrorCode classifies an error from the services as one of the
+// models.ErrorCode constants, for API clients
+func ErrorCode(err error) string {
+	var invalid *ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		return models.ErrorCodeInvalidRequest
+	case errors.Is(err, ErrRedditLoginRequired):
+		return models.ErrorCodeRedditLoginRequired
+	case errors.Is(err, ErrOverloaded):
+		return models.ErrorCodeOverloaded
+	case errors.Is(err, context.DeadlineExceeded):
+		return models.ErrorCodeTimeout
+	case errors.Is(err, ErrRedditRateLimited):
+		return models.ErrorCodeRedditRateLimited
+	case errors.Is(err, ErrRedditUnavailable):
+		return models.ErrorCodeRedditUnavailable
+	case errors.Is(err, ErrAIRateLimited):
+		return models.ErrorCodeAIRateLimited
+	case errors.Is(err, ErrAIUnavailable):
+		return models.ErrorCodeAIUnavailable
+	default:
+		return models.ErrorCodeInternal
+	}
+}
+
+// providerStatusError reports a non-200 response from an AI provider,
+// wrapping ErrAIRateLimited or ErrAIUnavailable when retrying later may help
+func providerStatusError(provider string, status int, body []byte) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s API returned status %d: %s", ErrAIRateLimited, provider, status, body)
+	case status >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: %s API returned status %d: %s", ErrAIUnavailable, provider, status, body)
+	default:
+		return fmt.Errorf("error response from %s API (status %d): %s", provider, status, body)
+	}
+}
@@ -0,0 +1,37 @@
+// File: backend/internal/services/errors_test.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"Validation", fmt.Errorf("search: %w", &ValidationError{}), models.ErrorCodeInvalidRequest},
+		{"Reddit login", fmt.Errorf("failed to search Reddit: %w", ErrRedditLoginRequired), models.ErrorCodeRedditLoginRequired},
+		{"Overloaded", ErrOverloaded, models.ErrorCodeOverloaded},
+		{"Timeout", fmt.Errorf("failed to search Reddit: %w", context.DeadlineExceeded), models.ErrorCodeTimeout},
+		{"Reddit rate limited", fmt.Errorf("failed to search Reddit: %w", ErrRedditRateLimited), models.ErrorCodeRedditRateLimited},
+		{"Reddit down", fmt.Errorf("failed to search Reddit: %w", ErrRedditUnavailable), models.ErrorCodeRedditUnavailable},
+		{"AI rate limited", providerStatusError("Anthropic", http.StatusTooManyRequests, nil), models.ErrorCodeAIRateLimited},
+		{"AI down", providerStatusError("OpenAI", http.StatusServiceUnavailable, nil), models.ErrorCodeAIUnavailable},
+		{"AI bad request", providerStatusError("OpenAI", http.StatusBadRequest, nil), models.ErrorCodeInternal},
+		{"Other", errors.New("boom"), models.ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.want {
+			t.Errorf("%s: ErrorCode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
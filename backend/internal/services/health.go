@@ -0,0 +1,105 @@
+// File: backend/internal/services/health.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// cacheNearlyFullRatio is the share of the cache budget at which the cache is
+// reported as degraded, since further inserts evict entries early
+const cacheNearlyFullRatio = 0.9
+
+// CheckAuth probes Reddit authentication by obtaining an access token,
+// refreshing it if needed. Without credentials the service falls back to the
+// rate-limited public API, which is reported as degraded.
+func (s *RedditService) CheckAuth(ctx context.Context) models.HealthCheck {
+	check := models.HealthCheck{
+		Status:   models.HealthOK,
+		Critical: true,
+		Details:  s.auth.GetAuthStatus(),
+	}
+
+	if !s.auth.HasCredentials() {
+		check.Status = models.HealthDegraded
+		check.Message = "Reddit API credentials are not set; using the rate-limited public API"
+		return check
+	}
+
+	if _, err := s.auth.GetAccessToken(ctx); err != nil {
+		check.Status = models.HealthDown
+		check.Message = fmt.Sprintf("Failed to obtain a Reddit access token: %v", err)
+	}
+	return check
+}
+
+// CacheHealth reports how full the result cache is
+func (s *RedditService) CacheHealth() models.HealthCheck {
+	size, maxSize := s.resultCache.Size(), s.resultCache.MaxSize()
+	check := models.HealthCheck{
+		Status: models.HealthOK,
+		Details: map[string]interface{}{
+			"entries":        s.resultCache.Len(),
+			"size_bytes":     size,
+			"max_size_bytes": maxSize,
+		},
+	}
+
+	if maxSize > 0 && float64(size) >= cacheNearlyFullRatio*float64(maxSize) {
+		check.Status = models.HealthDegraded
+		check.Message = "Result cache is nearly full; entries are being evicted early"
+	}
+	return check
+}
+
+// ProviderHealth reports, for each provider used by a configured model,
+// whether its API key is set and how its models' recent calls went. Keys are
+// lowercase provider names.
+func (s *AIService) ProviderHealth() map[string]models.HealthCheck {
+	modelsByProvider := make(map[string][]string)
+	for key, modelConfig := range s.modelConfig {
+		if key != "default" {
+			modelsByProvider[modelConfig.Provider] = append(modelsByProvider[modelConfig.Provider], modelConfig.Name)
+		}
+	}
+
+	checks := make(map[string]models.HealthCheck, len(modelsByProvider))
+	for provider, names := range modelsByProvider {
+		sort.Strings(names)
+		check := models.HealthCheck{
+			Status:  models.HealthOK,
+			Details: map[string]interface{}{"models": names},
+		}
+
+		if !hasCredentials(provider) {
+			check.Status = models.HealthDegraded
+			check.Message = fmt.Sprintf("%s is not set; %s models return mock responses", providerCredentialEnv[provider], provider)
+			checks[strings.ToLower(provider)] = check
+			continue
+		}
+
+		// The provider is as healthy as its least healthy model
+		for _, name := range names {
+			health := s.health.snapshot(name)
+			switch health.Status {
+			case models.ModelHealthDown:
+				check.Status = models.HealthDown
+				check.Message = fmt.Sprintf("%s: %s", name, health.LastError)
+			case models.ModelHealthDegraded:
+				if check.Status == models.HealthOK {
+					check.Status = models.HealthDegraded
+					check.Message = fmt.Sprintf("%s: %s", name, health.LastError)
+				}
+			}
+		}
+
+		checks[strings.ToLower(provider)] = check
+	}
+
+	return checks
+}
@@ -0,0 +1,863 @@
+// File: backend/internal/services/pipeline.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/contentpolicy"
+	"github.com/pranesh-j/subplexity/internal/entities"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/moderation"
+	"github.com/pranesh-j/subplexity/internal/topterms"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+const (
+	defaultSearchLimit = 25
+	maxSearchLimit     = 100
+	defaultSearchMode  = "All"
+	defaultModelName   = "Claude"
+)
+
+// Thread context is fetched for a reply the answer cites at least
+// heavyCitationCount times, covering up to threadContextLevels parents
+const (
+	heavyCitationCount  = 3
+	threadContextLevels = 3
+)
+
+// citationMarkerPattern matches citation markers such as [3] in answers
+var citationMarkerPattern = regexp.MustCompile(`\[([0-9]+)\]`)
+
+// languageNames maps common ISO 639-1 codes to the names used in prompts
+var languageNames = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"hi": "Hindi",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"sv": "Swedish",
+	"tr": "Turkish",
+	"uk": "Ukrainian",
+	"zh": "Chinese",
+}
+
+// SearchPipeline runs the full search flow: Reddit retrieval, relevance
+// filtering and AI analysis. It is shared by every entry point that needs
+// to answer a query (single search, batch search, etc.)
+type SearchPipeline struct {
+	reddit      *RedditService
+	ai          *AIService
+	moderator   *moderation.Service
+	policy      *contentpolicy.Policy
+	index       LocalIndex
+	links       ResultEnricher
+	transcripts ResultEnricher
+	limits      *backpressure
+	rules       requestRules
+	safeSearch  config.SafeSearchConfig
+}
+
+// LocalIndex answers queries from locally stored posts. Search returns no
+// results when the index can't answer the query and Reddit should be used.
+type LocalIndex interface {
+	Search(ctx context.Context, query string, subreddits []string, limit int) ([]models.SearchResult, error)
+}
+
+// ResultEnricher adds content fetched from elsewhere to results, such as
+// linked articles or video transcripts. Enrich returns a copy of results and
+// leaves the input unmodified.
+type ResultEnricher interface {
+	Enrich(ctx context.Context, results []models.SearchResult) []models.SearchResult
+}
+
+// NewSearchPipeline creates a new search pipeline
+func NewSearchPipeline(redditService *RedditService, aiService *AIService) *SearchPipeline {
+	pipeline := &SearchPipeline{
+		reddit:     redditService,
+		ai:         aiService,
+		limits:     newBackpressure(config.Default().Limits),
+		safeSearch: config.Default().SafeSearch,
+	}
+	pipeline.SetRequestRules(config.Default().Requests)
+	return pipeline
+}
+
+// SetLimits replaces the limits on concurrent searches, in-flight result
+// memory and response size. It should be called before serving requests.
+func (p *SearchPipeline) SetLimits(cfg config.LimitsConfig) {
+	p.limits = newBackpressure(cfg)
+}
+
+// Load reports the searches running and waiting for a slot
+func (p *SearchPipeline) Load() models.SearchLoad {
+	return p.limits.load()
+}
+
+// SetModerator enables content moderation of results and answers. A nil
+// moderator disables it.
+func (p *SearchPipeline) SetModerator(moderator *moderation.Service) {
+	p.moderator = moderator
+}
+
+// SetContentPolicy applies a content policy to results and answers. A nil
+// policy disables it.
+func (p *SearchPipeline) SetContentPolicy(policy *contentpolicy.Policy) {
+	p.policy = policy
+}
+
+// SetLocalIndex makes the pipeline consult a local index before Reddit. A
+// nil index disables it.
+func (p *SearchPipeline) SetLocalIndex(index LocalIndex) {
+	p.index = index
+}
+
+// SetLinkEnricher makes the pipeline fetch linked articles for link posts
+// before analysis. A nil enricher disables it.
+func (p *SearchPipeline) SetLinkEnricher(enricher ResultEnricher) {
+	p.links = enricher
+}
+
+// SetTranscriptEnricher makes the pipeline fetch captions for video posts
+// before analysis. A nil enricher disables it.
+func (p *SearchPipeline) SetTranscriptEnricher(enricher ResultEnricher) {
+	p.transcripts = enricher
+}
+
+// NormalizeRequest applies default values and caps to a search request
+func NormalizeRequest(req *models.SearchRequest) {
+	if req.Limit <= 0 {
+		req.Limit = defaultSearchLimit
+	}
+	if req.Limit > maxSearchLimit {
+		req.Limit = maxSearchLimit // Cap the maximum limit
+	}
+	if req.SearchMode == "" {
+		req.SearchMode = defaultSearchMode
+	}
+	if req.ModelName == "" {
+		req.ModelName = defaultModelName
+	}
+
+	// Accept "r/name" as well as "name" for subreddit scopes
+	req.Subreddits = subredditNames(req.Subreddits)
+	req.ExcludeSubreddits = subredditNames(req.ExcludeSubreddits)
+	// Keep an empty list, which leaves out no one rather than the defaults
+	if req.ExcludeAuthors != nil {
+		authors := make([]string, 0, len(req.ExcludeAuthors))
+		for _, author := range req.ExcludeAuthors {
+			if author = strings.TrimPrefix(strings.TrimSpace(author), "u/"); author != "" {
+				authors = append(authors, author)
+			}
+		}
+		req.ExcludeAuthors = authors
+	}
+
+	// Scope keywords are case-insensitive; multireddit names keep their case
+	req.RedditScope = strings.TrimSpace(req.RedditScope)
+	if prefix := models.RedditScopeMultiPrefix; len(req.RedditScope) > len(prefix) && strings.EqualFold(req.RedditScope[:len(prefix)], prefix) {
+		req.RedditScope = prefix + req.RedditScope[len(prefix):]
+	} else {
+		req.RedditScope = strings.ToLower(req.RedditScope)
+	}
+
+	// Expand language codes so the prompt names the language unambiguously
+	req.AnswerLanguage = strings.TrimSpace(req.AnswerLanguage)
+	if name, ok := languageNames[strings.ToLower(req.AnswerLanguage)]; ok {
+		req.AnswerLanguage = name
+	}
+	req.AnswerFormat = strings.ToLower(strings.TrimSpace(req.AnswerFormat))
+	req.RecencyBias = strings.ToLower(strings.TrimSpace(req.RecencyBias))
+	req.Region = strings.ToLower(strings.TrimSpace(req.Region))
+
+	// Keep only the language, and fall back to English for unsupported ones
+	req.Locale = utils.NormalizeLocale(req.Locale)
+	if req.Locale == "" {
+		req.Locale = utils.DefaultLocale
+	}
+	req.Timezone = strings.TrimSpace(req.Timezone)
+}
+
+// ResultsFunc receives the results a search will be answered from, once
+// they have been retrieved and moderated and before the answer is generated
+type ResultsFunc func(results []models.SearchResult, source string)
+
+// Run executes the search pipeline for a single request
+func (p *SearchPipeline) Run(ctx context.Context, req models.SearchRequest) (*models.SearchResponse, error) {
+	return p.RunStream(ctx, req, nil)
+}
+
+// RunStream is Run, calling onResults with the results before the answer
+// is generated so callers can show them while the model works
+func (p *SearchPipeline) RunStream(ctx context.Context, req models.SearchRequest, onResults ResultsFunc) (*models.SearchResponse, error) {
+	if err := p.Validate(&req); err != nil {
+		return nil, err
+	}
+
+	NormalizeRequest(&req)
+	p.applySafeSearch(&req)
+
+	if err := p.limits.acquireSearch(ctx); err != nil {
+		return nil, err
+	}
+	defer p.limits.releaseSearch()
+
+	// Measure execution time
+	startTime := time.Now()
+
+	results, source, err := p.retrieve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter out completely irrelevant results based on the query terms
+	results = filterByQueryKeywords(req.Query, results)
+
+	return p.answerWithinLimits(ctx, req, results, source, startTime, onResults)
+}
+
+// RunWithResults answers a request from results the caller already has, such
+// as a subreddit listing. Moderation, the content policy and AI analysis
+// apply as in Run, but results are not filtered by query keywords.
+func (p *SearchPipeline) RunWithResults(ctx context.Context, req models.SearchRequest, results []models.SearchResult, source string) (*models.SearchResponse, error) {
+	if err := p.Validate(&req); err != nil {
+		return nil, err
+	}
+
+	NormalizeRequest(&req)
+	p.applySafeSearch(&req)
+
+	if err := p.limits.acquireSearch(ctx); err != nil {
+		return nil, err
+	}
+	defer p.limits.releaseSearch()
+
+	return p.answerWithinLimits(ctx, req, results, source, time.Now(), nil)
+}
+
+// answerWithinLimits answers a request while accounting for the memory its
+// results hold, and trims the response to the size limit
+func (p *SearchPipeline) answerWithinLimits(ctx context.Context, req models.SearchRequest, results []models.SearchResult, source string, startTime time.Time, onResults ResultsFunc) (*models.SearchResponse, error) {
+	release, err := p.limits.reserveResults(results)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	response := p.answer(ctx, req, results, source, startTime, onResults)
+	localizeLastUpdated(response, req)
+	p.limits.truncateResponse(response)
+	return response, nil
+}
+
+// answer moderates results and generates the AI answer for a normalized
+// request. onResults, when set, is called with the moderated results.
+func (p *SearchPipeline) answer(ctx context.Context, req models.SearchRequest, results []models.SearchResult, source string, startTime time.Time, onResults ResultsFunc) *models.SearchResponse {
+	requestParams := models.RequestParams{
+		Query:             req.Query,
+		SearchMode:        req.SearchMode,
+		ModelName:         req.ModelName,
+		Limit:             req.Limit,
+		Subreddits:        req.Subreddits,
+		AnswerLanguage:    req.AnswerLanguage,
+		AnswerFormat:      req.AnswerFormat,
+		Locale:            req.Locale,
+		Timezone:          req.Timezone,
+		Verify:            req.Verify,
+		SelfConsistency:   req.SelfConsistency,
+		SkipAI:            req.SkipAI,
+		IncludeRaw:        p.includesRaw(req),
+		MaxContentLength:  req.MaxContentLength,
+		Highlights:        req.Highlights,
+		MinSubreddits:     req.MinSubreddits,
+		RecencyBias:       req.RecencyBias,
+		MinScore:          req.MinScore,
+		MinComments:       req.MinComments,
+		ExcludeSubreddits: req.ExcludeSubreddits,
+		ExcludeAuthors:    req.ExcludeAuthors,
+		Region:            req.Region,
+		CleanLanguage:     req.CleanLanguage,
+		SafeSearch:        req.SafeSearch,
+		RedditScope:       req.RedditScope,
+	}
+
+	// Broaden one-sided results first, so added results are moderated too
+	results, broadened := p.diversify(ctx, req, results)
+
+	// Drop flagged content before it reaches the model or the client, and
+	// removed content that is only a placeholder
+	results = withoutRemoved(results)
+	results = safeResults(req, results)
+	if !p.includesRaw(req) {
+		results = withoutRaw(results)
+	}
+	results = limitHighlights(results, p.highlightCount(req))
+	results = p.moderateResults(ctx, req, results)
+	results = p.policy.FilterResults(results)
+	results = localizeResults(results, req)
+	if onResults != nil {
+		onResults(results, source)
+	}
+	related := p.relatedSubreddits(ctx, req, results)
+	mentioned := entities.Extract(req.Query, results)
+	stats := resultStats(results, time.Now())
+	topTerms := topterms.Extract(req.Query, results)
+	controversial := isControversial(results)
+	diversity := p.sourceDiversity(req, results, broadened)
+
+	// Retrieval-only requests are done once results are ranked and moderated
+	if req.SkipAI {
+		if results == nil {
+			results = []models.SearchResult{}
+		}
+		elapsedTime := time.Since(startTime).Seconds()
+		log.Printf("Search completed in %.2f seconds without AI, found %d results", elapsedTime, len(results))
+		return &models.SearchResponse{
+			Results:           results,
+			TotalCount:        len(results),
+			ElapsedTime:       elapsedTime,
+			LastUpdated:       time.Now().Unix(),
+			Source:            source,
+			RequestParams:     requestParams,
+			RelatedSubreddits: <-related,
+			Entities:          mentioned,
+			Stats:             stats,
+			TopTerms:          topTerms,
+			Controversial:     controversial,
+			Diversity:         diversity,
+		}
+	}
+
+	// If no results were found, return an empty response with explanation
+	if len(results) == 0 {
+		log.Println("No search results found")
+		return &models.SearchResponse{
+			Results:           []models.SearchResult{},
+			TotalCount:        0,
+			Reasoning:         "No results found for your query.",
+			Answer:            "There are no Reddit results matching your search criteria. Please try a different query or search mode.",
+			ElapsedTime:       time.Since(startTime).Seconds(),
+			LastUpdated:       time.Now().Unix(),
+			Source:            source,
+			RequestParams:     requestParams,
+			RelatedSubreddits: <-related,
+			Diversity:         diversity,
+		}
+	}
+
+	// Give the model the articles link posts are discussing and what
+	// video posts say
+	if p.links != nil {
+		results = p.links.Enrich(ctx, results)
+	}
+	if p.transcripts != nil {
+		results = p.transcripts.Enrich(ctx, results)
+	}
+
+	// Process results with AI (with error handling)
+	answerOpts := AnswerOptions{
+		Language:         req.AnswerLanguage,
+		Format:           req.AnswerFormat,
+		Verify:           req.Verify,
+		Locale:           req.Locale,
+		SelfConsistency:  req.SelfConsistency,
+		MaxContentLength: req.MaxContentLength,
+		Entities:         mentioned,
+		Controversial:    controversial,
+		CleanLanguage:    req.CleanLanguage,
+	}
+	aiResult, aiErr := p.ai.ProcessResultsWithOptions(ctx, req.Query, results, req.ModelName, answerOpts)
+	var answerErr *models.ErrorResponse
+	if aiErr != nil {
+		log.Printf("AI processing error: %v", aiErr)
+		// Still continue - we'll return the raw results
+		aiResult = &AnswerResult{
+			Reasoning: "AI processing failed: " + aiErr.Error(),
+			Answer:    "The search found results, but AI analysis couldn't be completed. The raw results are still available.",
+		}
+		failure := models.NewErrorResponse(ErrorCode(aiErr), "AI analysis failed", aiErr.Error())
+		answerErr = &failure
+	}
+
+	// A reply the answer leans on may mean something else in context, so
+	// answer again with the comments it responds to
+	if aiErr == nil {
+		if contextual := p.withThreadContext(ctx, req, aiResult.Answer, results); contextual != nil {
+			retry, err := p.ai.ProcessResultsWithOptions(ctx, req.Query, contextual, req.ModelName, answerOpts)
+			if err != nil {
+				log.Printf("AI processing with thread context failed, keeping the first answer: %v", err)
+			} else {
+				results, aiResult = contextual, retry
+			}
+		}
+	}
+
+	// Withhold answers that fail moderation
+	p.moderateAnswer(ctx, req, aiResult)
+	p.applyAnswerPolicy(aiResult)
+	if req.CleanLanguage {
+		p.applyCleanLanguage(aiResult)
+	}
+
+	elapsedTime := time.Since(startTime).Seconds()
+	log.Printf("Search completed in %.2f seconds, found %d results", elapsedTime, len(results))
+
+	return &models.SearchResponse{
+		Results:           results,
+		TotalCount:        len(results),
+		Reasoning:         aiResult.Reasoning,
+		ReasoningSteps:    aiResult.ReasoningSteps,
+		Answer:            aiResult.Answer,
+		Citations:         aiResult.Citations,
+		Warnings:          aiResult.Warnings,
+		Consistency:       aiResult.Consistency,
+		Comparison:        aiResult.Comparison,
+		Ranking:           aiResult.Ranking,
+		Stats:             stats,
+		TopTerms:          topTerms,
+		Controversial:     controversial,
+		Diversity:         diversity,
+		AnswerError:       answerErr,
+		ElapsedTime:       elapsedTime,
+		LastUpdated:       time.Now().Unix(),
+		Source:            source,
+		RequestParams:     requestParams,
+		RelatedSubreddits: <-related,
+		Entities:          mentioned,
+	}
+}
+
+// retrieve fetches results for a request, from the local index when it can
+// answer the query and from Reddit otherwise. It also reports which was used.
+func (p *SearchPipeline) retrieve(ctx context.Context, req models.SearchRequest) ([]models.SearchResult, string, error) {
+	searchOpts := searchOptions(req)
+
+	// The user's own content is only on Reddit
+	if req.RedditScope != "" {
+		user, ok := redditUserFrom(ctx)
+		if !ok {
+			return nil, "", ErrRedditLoginRequired
+		}
+		results, err := p.reddit.SearchUserScope(ctx, user, req.Query, req.SearchMode, req.Limit, req.RedditScope)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to search Reddit: %w", err)
+		}
+		return withoutAuthors(searchOpts.filter(results), searchOpts.excludedAuthors()), models.SourceReddit, nil
+	}
+
+	// The index only holds posts
+	if p.index != nil && (req.SearchMode == "All" || req.SearchMode == "Posts") {
+		results, err := p.index.Search(ctx, req.Query, req.Subreddits, req.Limit)
+		if err != nil {
+			log.Printf("Local index search failed, falling back to Reddit: %v", err)
+		} else if results = withoutAuthors(searchOpts.filter(results), searchOpts.excludedAuthors()); len(results) > 0 {
+			log.Printf("Answering '%s' from the local index (%d results)", req.Query, len(results))
+			return results, models.SourceIndex, nil
+		}
+	}
+
+	results, err := p.reddit.SearchRedditWithOptions(ctx, req.Query, req.SearchMode, req.Limit, searchOpts)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search Reddit: %w", err)
+	}
+	return results, models.SourceReddit, nil
+}
+
+// subredditNames trims names and their "r/" prefixes, dropping empty ones
+func subredditNames(names []string) []string {
+	var trimmed []string
+	for _, sr := range names {
+		sr = strings.TrimPrefix(strings.TrimSpace(sr), "r/")
+		if sr != "" {
+			trimmed = append(trimmed, sr)
+		}
+	}
+	return trimmed
+}
+
+// searchOptions returns the Reddit search options a request asks for
+func searchOptions(req models.SearchRequest) SearchOptions {
+	return SearchOptions{
+		Subreddits:        req.Subreddits,
+		RecencyBias:       req.RecencyBias,
+		MinScore:          req.MinScore,
+		MinComments:       req.MinComments,
+		ExcludeSubreddits: req.ExcludeSubreddits,
+		ExcludeAuthors:    req.ExcludeAuthors,
+		Region:            req.Region,
+	}
+}
+
+// Warm runs retrieval for each request so its Reddit results are cached
+// before users ask for them. Requests run one at a time to stay well inside
+// Reddit's rate limits. It returns how many requests were warmed.
+func (p *SearchPipeline) Warm(ctx context.Context, reqs []models.SearchRequest) int {
+	warmed := 0
+	for _, req := range reqs {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := p.Validate(&req); err != nil {
+			continue
+		}
+		NormalizeRequest(&req)
+		p.applySafeSearch(&req)
+
+		if _, _, err := p.retrieve(ctx, req); err != nil {
+			log.Printf("Cache warm-up failed for '%s': %v", req.Query, err)
+			continue
+		}
+		warmed++
+	}
+	return warmed
+}
+
+// moderateResults removes results flagged by content moderation. If the
+// moderation provider fails, results are kept, except for safe requests,
+// which lose them all. Safe requests are moderated whenever a moderator is
+// configured.
+func (p *SearchPipeline) moderateResults(ctx context.Context, req models.SearchRequest, results []models.SearchResult) []models.SearchResult {
+	if !p.moderator.ChecksResults() && !p.moderates(req) || len(results) == 0 {
+		return results
+	}
+
+	texts := make([]string, len(results))
+	for i, result := range results {
+		texts[i] = result.Title + "\n" + result.Content
+	}
+
+	verdicts, err := p.moderator.Check(ctx, texts)
+	if err != nil && req.SafeSearch {
+		log.Printf("Result moderation failed, dropping unmoderated results of a safe search: %v", err)
+		return nil
+	}
+	if err != nil {
+		log.Printf("Result moderation failed, keeping unmoderated results: %v", err)
+		return results
+	}
+
+	var allowed []models.SearchResult
+	for i, result := range results {
+		if verdicts[i].Flagged {
+			log.Printf("Moderation removed result %s from r/%s (%s)",
+				result.ID, result.Subreddit, strings.Join(verdicts[i].Categories, ", "))
+			continue
+		}
+		allowed = append(allowed, result)
+	}
+
+	return allowed
+}
+
+// moderateAnswer replaces a flagged answer with a notice, and drops flagged
+// alternative answers from its consistency report. If the moderation
+// provider fails, the answer is kept and a warning is added, except for safe
+// requests, whose answer is withheld. Safe requests are moderated whenever a
+// moderator is configured.
+func (p *SearchPipeline) moderateAnswer(ctx context.Context, req models.SearchRequest, result *AnswerResult) {
+	if !p.moderator.ChecksAnswers() && !p.moderates(req) || strings.TrimSpace(result.Answer) == "" {
+		return
+	}
+
+	texts := []string{result.Answer}
+	if result.Consistency != nil {
+		texts = append(texts, result.Consistency.Alternatives...)
+	}
+	verdicts, err := p.moderator.Check(ctx, texts)
+	if err != nil && req.SafeSearch {
+		log.Printf("Answer moderation failed, withholding the answer to a safe search: %v", err)
+		withholdAnswer(result, "The answer was withheld because content moderation is unavailable.",
+			"Answer withheld: safe search could not check it")
+		return
+	}
+	if err != nil {
+		log.Printf("Answer moderation failed: %v", err)
+		result.Warnings = append(result.Warnings, "The answer could not be checked by content moderation.")
+		return
+	}
+
+	if !verdicts[0].Flagged {
+		if result.Consistency != nil {
+			var alternatives []string
+			for i, alternative := range result.Consistency.Alternatives {
+				if verdicts[i+1].Flagged {
+					log.Printf("Moderation withheld alternative answer (%s)", strings.Join(verdicts[i+1].Categories, ", "))
+					continue
+				}
+				alternatives = append(alternatives, alternative)
+			}
+			if len(alternatives) < len(result.Consistency.Alternatives) {
+				result.Warnings = append(result.Warnings, "Alternative answers flagged by content moderation were withheld.")
+			}
+			result.Consistency.Alternatives = alternatives
+		}
+		return
+	}
+
+	categories := strings.Join(verdicts[0].Categories, ", ")
+	log.Printf("Moderation withheld answer (%s)", categories)
+	withholdAnswer(result, "The generated answer was withheld because it was flagged by content moderation.",
+		"Answer withheld by content moderation: "+categories)
+}
+
+// withholdAnswer replaces the answer with notice and drops everything
+// generated alongside it
+func withholdAnswer(result *AnswerResult, notice, warning string) {
+	result.Answer = notice
+	result.Reasoning = ""
+	result.ReasoningSteps = nil
+	result.Citations = nil
+	result.Consistency = nil
+	result.Comparison = nil
+	result.Ranking = nil
+	result.Warnings = append(result.Warnings, warning)
+}
+
+// applyAnswerPolicy masks blocked terms and profanity in the generated text
+func (p *SearchPipeline) applyAnswerPolicy(result *AnswerResult) {
+	if p.policy == nil {
+		return
+	}
+
+	_, categories := p.policy.ProcessText(result.Answer)
+	if result.Consistency != nil {
+		for _, alternative := range result.Consistency.Alternatives {
+			_, matched := p.policy.ProcessText(alternative)
+			for _, category := range matched {
+				if !slices.Contains(categories, category) {
+					categories = append(categories, category)
+				}
+			}
+		}
+	}
+	rewriteAnswer(result, func(text string) string {
+		text, _ = p.policy.ProcessText(text)
+		return text
+	})
+
+	if len(categories) > 0 {
+		result.Warnings = append(result.Warnings,
+			"Parts of the answer were masked by the content policy: "+strings.Join(categories, ", "))
+	}
+}
+
+// applyCleanLanguage masks profanity the model used despite being asked to
+// paraphrase it, for requests with cleanLanguage
+func (p *SearchPipeline) applyCleanLanguage(result *AnswerResult) {
+	rewriteAnswer(result, p.policy.Clean)
+}
+
+// rewriteAnswer applies rewrite to each piece of generated text in result
+func rewriteAnswer(result *AnswerResult, rewrite func(string) string) {
+	result.Answer = rewrite(result.Answer)
+	result.Reasoning = rewrite(result.Reasoning)
+	if result.Consistency != nil {
+		for i := range result.Consistency.Alternatives {
+			result.Consistency.Alternatives[i] = rewrite(result.Consistency.Alternatives[i])
+		}
+	}
+	for i := range result.ReasoningSteps {
+		result.ReasoningSteps[i].Content = rewrite(result.ReasoningSteps[i].Content)
+	}
+	for i := range result.Ranking {
+		result.Ranking[i].Name = rewrite(result.Ranking[i].Name)
+		result.Ranking[i].Justification = rewrite(result.Ranking[i].Justification)
+	}
+	if comparison := result.Comparison; comparison != nil {
+		comparison.Verdict = rewrite(comparison.Verdict)
+		for i := range comparison.Criteria {
+			for j := range comparison.Criteria[i].Evidence {
+				evidence := &comparison.Criteria[i].Evidence[j]
+				evidence.Summary = rewrite(evidence.Summary)
+			}
+		}
+	}
+}
+
+// withThreadContext finds the reply an answer cites most and, when it is
+// cited heavily, returns a copy of results with the comments above it
+// attached. It returns nil when no context is needed, it can't be fetched,
+// or it fails moderation.
+func (p *SearchPipeline) withThreadContext(ctx context.Context, req models.SearchRequest, answer string, results []models.SearchResult) []models.SearchResult {
+	index := heavilyCitedReply(answer, results)
+	if index < 0 {
+		return nil
+	}
+	reply := results[index]
+
+	postID := strings.TrimPrefix(reply.LinkID, "t3_")
+	thread, err := p.reddit.GetCommentContext(ctx, postID, reply.ID, threadContextLevels)
+	if err != nil {
+		log.Printf("Error fetching context for comment %s: %v", reply.ID, err)
+		return nil
+	}
+	ancestors := commentAncestors(thread, reply.ID)
+	if len(ancestors) == 0 || !p.contextAllowed(ctx, req, ancestors) {
+		return nil
+	}
+
+	contextual := make([]models.SearchResult, len(results))
+	copy(contextual, results)
+	contextual[index].ThreadContext = ancestors
+	log.Printf("Answering again with %d parent comments of heavily cited comment %s", len(ancestors), reply.ID)
+	return contextual
+}
+
+// contextAllowed reports whether thread context passes the checks results
+// do. Context is optional, so it is left out whenever moderation flags any
+// of it or can't check it.
+func (p *SearchPipeline) contextAllowed(ctx context.Context, req models.SearchRequest, comments []models.Comment) bool {
+	if !p.moderator.ChecksResults() && !p.moderates(req) {
+		return true
+	}
+
+	texts := make([]string, len(comments))
+	for i, comment := range comments {
+		texts[i] = comment.Body
+	}
+	verdicts, err := p.moderator.Check(ctx, texts)
+	if err != nil {
+		log.Printf("Thread context moderation failed, leaving the context out: %v", err)
+		return false
+	}
+	for i, verdict := range verdicts {
+		if verdict.Flagged {
+			log.Printf("Moderation removed thread context %s (%s)", comments[i].ID, strings.Join(verdict.Categories, ", "))
+			return false
+		}
+	}
+	return true
+}
+
+// heavilyCitedReply returns the index in results of the reply comment an
+// answer cites most, if it is cited at least heavyCitationCount times, and
+// -1 otherwise
+func heavilyCitedReply(answer string, results []models.SearchResult) int {
+	counts := make(map[int]int)
+	for _, match := range citationMarkerPattern.FindAllStringSubmatch(answer, -1) {
+		var citation int
+		if _, err := fmt.Sscanf(match[1], "%d", &citation); err == nil {
+			counts[citation]++
+		}
+	}
+
+	best, bestCount := -1, heavyCitationCount-1
+	for i, result := range results {
+		if counts[i+1] <= bestCount || result.Type != "comment" ||
+			!strings.HasPrefix(result.ParentID, "t1_") || result.LinkID == "" || len(result.ThreadContext) > 0 {
+			continue
+		}
+		best, bestCount = i, counts[i+1]
+	}
+	return best
+}
+
+// withoutRemoved drops results whose content was removed or deleted. Removed
+// posts keep their titles, but without a body or comments they only mislead
+// the model.
+func withoutRemoved(results []models.SearchResult) []models.SearchResult {
+	kept := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if !result.Removed {
+			kept = append(kept, result)
+		}
+	}
+	if dropped := len(results) - len(kept); dropped > 0 {
+		log.Printf("Dropped %d removed or deleted results", dropped)
+	}
+	return kept
+}
+
+// includesRaw reports whether results keep the JSON Reddit returned for req.
+// That JSON isn't filtered or masked, so it's dropped whenever the content
+// policy, clean language or safe search would change what the caller sees.
+func (p *SearchPipeline) includesRaw(req models.SearchRequest) bool {
+	return req.IncludeRaw && !req.CleanLanguage && !req.SafeSearch && !p.policy.Rewrites()
+}
+
+// withoutRaw strips the Reddit JSON from results, leaving the caller's
+// results unchanged
+func withoutRaw(results []models.SearchResult) []models.SearchResult {
+	stripped := make([]models.SearchResult, len(results))
+	for i, result := range results {
+		result.Raw = nil
+		stripped[i] = result
+	}
+	return stripped
+}
+
+// highlightCount returns the number of highlights to keep per result for req
+func (p *SearchPipeline) highlightCount(req models.SearchRequest) int {
+	if req.Highlights > 0 {
+		return req.Highlights
+	}
+	return p.rules.highlights
+}
+
+// limitHighlights keeps the first n highlights of each result, which are
+// the best matches. The caller's results are left unchanged.
+func limitHighlights(results []models.SearchResult, n int) []models.SearchResult {
+	limited := make([]models.SearchResult, len(results))
+	for i, result := range results {
+		if len(result.Highlights) > n {
+			result.Highlights = result.Highlights[:n:n]
+		}
+		limited[i] = result
+	}
+	return limited
+}
+
+// filterByQueryKeywords drops results that mention none of the meaningful
+// query terms. The original results are kept if nothing would survive.
+func filterByQueryKeywords(query string, results []models.SearchResult) []models.SearchResult {
+	// Extract main keywords from the query, keeping words longer than 2 chars
+	var queryKeywords []string
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if len(word) > 2 {
+			queryKeywords = append(queryKeywords, word)
+		}
+	}
+
+	// Only perform filtering if we have meaningful keywords
+	if len(queryKeywords) == 0 {
+		return results
+	}
+
+	var relevantResults []models.SearchResult
+	for _, result := range results {
+		resultText := strings.ToLower(result.Title + " " + result.Content)
+
+		// Check if any main query terms are present
+		for _, term := range queryKeywords {
+			if strings.Contains(resultText, term) {
+				relevantResults = append(relevantResults, result)
+				break
+			}
+		}
+	}
+
+	// Only use filtered results if we found some
+	if len(relevantResults) == 0 {
+		return results
+	}
+
+	log.Printf("Filtered results from %d to %d relevant items based on query keywords",
+		len(results), len(relevantResults))
+	return relevantResults
+}
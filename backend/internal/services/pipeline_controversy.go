@@ -0,0 +1,85 @@
+// File: backend/internal/services/pipeline_controversy.go
+
+package services
+
+import (
+	"log"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+const (
+	// minControversialPostScore ignores the upvote ratio of posts with too
+	// few votes for it to mean much
+	minControversialPostScore = 5
+	// minSplitComments is how many comments must be downvoted or disputing
+	// before they can mark a split
+	minSplitComments = 2
+	// downvotedCommentShare is the share of comments which, downvoted while
+	// others are upvoted, marks a split
+	downvotedCommentShare = 0.25
+	// disputingCommentShare is the share of comments which, disputing
+	// another view, marks a split
+	disputingCommentShare = 0.3
+)
+
+// controversyCitationWarning is surfaced when the answer to a split question
+// can't be presenting both camps with separate citations
+const controversyCitationWarning = "Reddit is split on this question, but the answer cites fewer than two results, so it may present only one side."
+
+// disputePhrases mark a comment as arguing against another view
+var disputePhrases = []string{
+	"disagree", "not true", "that's wrong", "that is wrong", "you're wrong", "this is wrong",
+	"completely wrong", "counterpoint", "on the other hand", "i'd argue",
+	"i would argue", "unpopular opinion", "hard pass", "overrated", "not worth it",
+	"don't listen", "misinformation",
+}
+
+// isControversial reports whether Reddit is split on a question, judging by
+// the results: a controversial post (few upvotes relative to downvotes),
+// comments downvoted alongside upvoted ones, or many comments disputing
+// each other
+func isControversial(results []models.SearchResult) bool {
+	var comments, downvoted, upvoted, disputing int
+	for _, result := range results {
+		switch result.Type {
+		case "post":
+			if result.UpvoteRatio > 0 && result.UpvoteRatio < controversialUpvoteRatio && result.Score >= minControversialPostScore {
+				log.Printf("Results are controversial: post %s is %.0f%% upvoted", result.ID, result.UpvoteRatio*100)
+				return true
+			}
+		case "comment":
+			comments++
+			if result.Score < 0 {
+				downvoted++
+			} else if result.Score > 1 {
+				upvoted++
+			}
+			if disputes(result.Content) {
+				disputing++
+			}
+		}
+	}
+
+	if upvoted > 0 && downvoted >= minSplitComments && float64(downvoted) >= downvotedCommentShare*float64(comments) {
+		log.Printf("Results are controversial: %d of %d comments are downvoted", downvoted, comments)
+		return true
+	}
+	if disputing >= minSplitComments && float64(disputing) >= disputingCommentShare*float64(comments) {
+		log.Printf("Results are controversial: %d of %d comments dispute another view", disputing, comments)
+		return true
+	}
+	return false
+}
+
+// disputes reports whether text argues against another view
+func disputes(text string) bool {
+	text = strings.ToLower(strings.ReplaceAll(text, "’", "'"))
+	for _, phrase := range disputePhrases {
+		if strings.Contains(text, phrase) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// File: backend/internal/services/pipeline_controversy_test.go
+
+package services
+
+import (
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestIsControversial(t *testing.T) {
+	agreeing := []models.SearchResult{
+		{Type: "post", Score: 500, UpvoteRatio: 0.95},
+		{Type: "comment", Score: 120, Content: "Great advice, worked for me."},
+		{Type: "comment", Score: 40, Content: "Same here."},
+		{Type: "comment", Score: -1, Content: "First!"},
+	}
+
+	testCases := []struct {
+		name     string
+		results  []models.SearchResult
+		expected bool
+	}{
+		{"Agreement", agreeing, false},
+		{"Controversial post", append(agreeing, models.SearchResult{Type: "post", Score: 40, UpvoteRatio: 0.52}), true},
+		{"Controversial post with few votes", append(agreeing, models.SearchResult{Type: "post", Score: 2, UpvoteRatio: 0.5}), false},
+		{"Downvoted comments", append(agreeing, models.SearchResult{Type: "comment", Score: -12, Content: "Keto is the only way."}), true},
+		{"Disputing comments", append(agreeing,
+			models.SearchResult{Type: "comment", Score: 30, Content: "Hard disagree, it ruined my sleep."},
+			models.SearchResult{Type: "comment", Score: 25, Content: "That’s wrong, the study says otherwise."},
+		), true},
+	}
+	for _, tc := range testCases {
+		if got := isControversial(tc.results); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
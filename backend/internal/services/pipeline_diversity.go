@@ -0,0 +1,137 @@
+// File: backend/internal/services/pipeline_diversity.go
+
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// diversify runs a broadening search when the results the answer will be
+// based on come from fewer than req.MinSubreddits communities. It searches
+// the communities related to the query that aren't represented yet and
+// moves the best result from each new one into the prompt, in place of the
+// lowest ranked results there, which stay further down. It reports whether
+// results were added.
+func (p *SearchPipeline) diversify(ctx context.Context, req models.SearchRequest, results []models.SearchResult) ([]models.SearchResult, bool) {
+	// Scoped searches were asked to stay within their communities
+	if req.MinSubreddits < 2 || len(req.Subreddits) > 0 || req.RedditScope != "" || p.reddit == nil || len(results) == 0 {
+		return results, false
+	}
+
+	represented := subredditSet(p.promptResults(req, results))
+	need := req.MinSubreddits - len(represented)
+	if need <= 0 {
+		return results, false
+	}
+
+	neighbors, err := p.reddit.SubredditsForQuery(ctx, req.Query, maxRelatedSubreddits)
+	if err != nil {
+		log.Printf("Failed to find communities to broaden '%s': %v", req.Query, err)
+		return results, false
+	}
+	excluded := make(map[string]bool, len(req.ExcludeSubreddits))
+	for _, sr := range req.ExcludeSubreddits {
+		excluded[config.NormalizeSubreddit(sr)] = true
+	}
+	var communities []string
+	for _, neighbor := range neighbors {
+		key := config.NormalizeSubreddit(neighbor.Subreddit)
+		if !represented[key] && !excluded[key] && !neighbor.NSFW {
+			communities = append(communities, neighbor.Subreddit)
+		}
+	}
+	if len(communities) == 0 {
+		log.Printf("No other communities to broaden '%s' with", req.Query)
+		return results, false
+	}
+
+	opts := searchOptions(req)
+	opts.Subreddits = communities
+	extra, err := p.reddit.SearchRedditWithOptions(ctx, req.Query, req.SearchMode, req.Limit, opts)
+	if err != nil {
+		log.Printf("Broadening search for '%s' failed: %v", req.Query, err)
+		return results, false
+	}
+	extra = filterByQueryKeywords(req.Query, extra)
+
+	known := make(map[string]bool, len(results))
+	for _, result := range results {
+		known[result.ID] = true
+	}
+	var picks []models.SearchResult
+	for _, result := range extra {
+		key := config.NormalizeSubreddit(result.Subreddit)
+		if key == "" || represented[key] || known[result.ID] || result.Type == "subreddit" {
+			continue
+		}
+		represented[key] = true
+		picks = append(picks, result)
+		if len(picks) == need {
+			break
+		}
+	}
+	if len(picks) == 0 {
+		log.Printf("Broadening search for '%s' found no results in other communities", req.Query)
+		return results, false
+	}
+	log.Printf("Broadened '%s' with results from %d more communities", req.Query, len(picks))
+
+	at := len(results)
+	if limit := p.promptResultLimit(req); limit > 0 && limit-len(picks) < at {
+		at = limit - len(picks)
+		if at < 0 {
+			at = 0
+		}
+	}
+	broadened := make([]models.SearchResult, 0, len(results)+len(picks))
+	broadened = append(broadened, results[:at]...)
+	broadened = append(broadened, picks...)
+	broadened = append(broadened, results[at:]...)
+	return broadened, true
+}
+
+// sourceDiversity reports the communities the answer draws on, for requests
+// with minSubreddits
+func (p *SearchPipeline) sourceDiversity(req models.SearchRequest, results []models.SearchResult, broadened bool) *models.SourceDiversity {
+	if req.MinSubreddits <= 0 {
+		return nil
+	}
+	return &models.SourceDiversity{
+		Subreddits:    len(subredditSet(p.promptResults(req, results))),
+		MinSubreddits: req.MinSubreddits,
+		Broadened:     broadened,
+	}
+}
+
+// promptResults returns the results the request's model is given
+func (p *SearchPipeline) promptResults(req models.SearchRequest, results []models.SearchResult) []models.SearchResult {
+	if limit := p.promptResultLimit(req); limit > 0 && limit < len(results) {
+		return results[:limit]
+	}
+	return results
+}
+
+// promptResultLimit returns how many results the request's model is given,
+// or 0 for all
+func (p *SearchPipeline) promptResultLimit(req models.SearchRequest) int {
+	if p.ai == nil {
+		return 0
+	}
+	return p.ai.PromptResultLimit(req.ModelName)
+}
+
+// subredditSet returns the normalized names of the communities results come
+// from
+func subredditSet(results []models.SearchResult) map[string]bool {
+	set := make(map[string]bool)
+	for _, result := range results {
+		if key := config.NormalizeSubreddit(result.Subreddit); key != "" {
+			set[key] = true
+		}
+	}
+	return set
+}
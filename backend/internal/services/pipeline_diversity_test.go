@@ -0,0 +1,54 @@
+// File: backend/internal/services/pipeline_diversity_test.go
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+func TestPipelineMinSubreddits(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddPosts(redditmock.Post{ID: "gp2", Subreddit: "programming", Title: "Go 1.22 released with range over int", Author: "coder", Score: 10})
+	mock.AddSubreddits(
+		redditmock.Subreddit{Name: "golang", Description: "Go released its news here"},
+		redditmock.Subreddit{Name: "programming", Description: "Where Go released news gets discussed"},
+	)
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	req := models.SearchRequest{Query: "go released", SearchMode: "Posts", Limit: 1, SkipAI: true}
+	response, err := pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 1 || response.Diversity != nil {
+		t.Fatalf("Expected the one r/golang result without a diversity report, got %+v", response)
+	}
+
+	req.MinSubreddits = 2
+	response, err = pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 2 || response.Results[1].ID != "gp2" {
+		t.Fatalf("Expected a broadening search to add the r/programming result, got %+v", response.Results)
+	}
+	want := models.SourceDiversity{Subreddits: 2, MinSubreddits: 2, Broadened: true}
+	if response.Diversity == nil || *response.Diversity != want {
+		t.Errorf("Expected diversity %+v, got %+v", want, response.Diversity)
+	}
+
+	// Scoped searches stay within their communities
+	req.Subreddits = []string{"golang"}
+	response, err = pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want = models.SourceDiversity{Subreddits: 1, MinSubreddits: 2}
+	if len(response.Results) != 1 || response.Diversity == nil || *response.Diversity != want {
+		t.Errorf("Expected the scoped search unbroadened, got %+v and %+v", response.Results, response.Diversity)
+	}
+}
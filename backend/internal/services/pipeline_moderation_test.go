@@ -0,0 +1,141 @@
+// File: backend/internal/services/pipeline_moderation_test.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/contentpolicy"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/moderation"
+)
+
+// keywordModerator flags texts containing its keyword as violence
+type keywordModerator struct {
+	keyword string
+}
+
+func (m keywordModerator) Score(ctx context.Context, texts []string) ([]map[string]float64, error) {
+	scores := make([]map[string]float64, len(texts))
+	for i, text := range texts {
+		scores[i] = map[string]float64{"violence": 0}
+		if strings.Contains(text, m.keyword) {
+			scores[i]["violence"] = 1
+		}
+	}
+	return scores, nil
+}
+
+// newModeratedPipeline returns a pipeline whose answers are checked by a
+// keywordModerator flagging "attack"
+func newModeratedPipeline() *SearchPipeline {
+	pipeline := NewSearchPipeline(nil, nil)
+	pipeline.SetModerator(moderation.NewService(keywordModerator{keyword: "attack"},
+		config.ModerationConfig{DefaultThreshold: 0.5, CheckAnswers: true}))
+	return pipeline
+}
+
+func TestModerateAnswerAlternatives(t *testing.T) {
+	pipeline := newModeratedPipeline()
+
+	result := &AnswerResult{
+		Answer: "Use a firewall.",
+		Consistency: &models.ConsistencyReport{
+			Samples:      3,
+			Alternatives: []string{"Unplug the router.", "Attack them back: attack."},
+		},
+	}
+	pipeline.moderateAnswer(context.Background(), models.SearchRequest{}, result)
+	if alternatives := result.Consistency.Alternatives; len(alternatives) != 1 || alternatives[0] != "Unplug the router." {
+		t.Errorf("Expected the flagged alternative to be withheld, got %q", alternatives)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Expected a warning about the withheld alternative, got %q", result.Warnings)
+	}
+
+	// A withheld answer takes its alternatives with it
+	result = &AnswerResult{
+		Answer:      "Plan the attack.",
+		Consistency: &models.ConsistencyReport{Samples: 2, Alternatives: []string{"Use a firewall."}},
+	}
+	pipeline.moderateAnswer(context.Background(), models.SearchRequest{}, result)
+	if !strings.Contains(result.Answer, "withheld") || result.Consistency != nil {
+		t.Errorf("Expected the answer and its consistency report withheld, got %q and %+v", result.Answer, result.Consistency)
+	}
+}
+
+func TestRewriteAnswerAlternatives(t *testing.T) {
+	pipeline := NewSearchPipeline(nil, nil)
+	pipeline.SetContentPolicy(contentpolicy.New(config.ContentPolicyConfig{
+		Categories:        map[string][]string{"gambling": {"casino"}},
+		BlockedCategories: []string{"gambling"},
+	}))
+
+	result := &AnswerResult{
+		Answer:      "Save your money.",
+		Consistency: &models.ConsistencyReport{Alternatives: []string{"Try the casino, it's shit."}},
+	}
+	pipeline.applyAnswerPolicy(result)
+	pipeline.applyCleanLanguage(result)
+
+	alternative := result.Consistency.Alternatives[0]
+	if strings.Contains(alternative, "casino") || strings.Contains(alternative, "shit") {
+		t.Errorf("Expected the alternative to be masked, got %q", alternative)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "gambling") {
+		t.Errorf("Expected a content policy warning, got %q", result.Warnings)
+	}
+}
+
+// failingModerator is a moderation provider that is down
+type failingModerator struct{}
+
+func (failingModerator) Score(ctx context.Context, texts []string) ([]map[string]float64, error) {
+	return nil, errors.New("moderation unavailable")
+}
+
+func TestModerationFailsClosedForSafeSearch(t *testing.T) {
+	pipeline := NewSearchPipeline(nil, nil)
+	pipeline.SetModerator(moderation.NewService(failingModerator{}, config.ModerationConfig{DefaultThreshold: 0.5}))
+	results := []models.SearchResult{{ID: "a1", Title: "Go 1.22 released"}}
+
+	// Unsafe requests aren't moderated at all without checks configured
+	if kept := pipeline.moderateResults(context.Background(), models.SearchRequest{}, results); len(kept) != 1 {
+		t.Errorf("Expected unmoderated results kept, got %+v", kept)
+	}
+
+	safe := models.SearchRequest{SafeSearch: true}
+	if kept := pipeline.moderateResults(context.Background(), safe, results); len(kept) != 0 {
+		t.Errorf("Expected a safe search to drop results moderation couldn't check, got %+v", kept)
+	}
+
+	result := &AnswerResult{Answer: "Upgrade to Go 1.22 [1].", Citations: []models.Citation{{Index: 1}}}
+	pipeline.moderateAnswer(context.Background(), safe, result)
+	if !strings.Contains(result.Answer, "withheld") || result.Citations != nil || len(result.Warnings) != 1 {
+		t.Errorf("Expected a safe search to withhold an answer moderation couldn't check, got %+v", result)
+	}
+
+	comments := []models.Comment{{ID: "c1", Body: "Which version?"}}
+	if pipeline.contextAllowed(context.Background(), safe, comments) {
+		t.Error("Expected thread context moderation couldn't check to be left out")
+	}
+}
+
+func TestContextAllowed(t *testing.T) {
+	pipeline := NewSearchPipeline(nil, nil)
+	pipeline.SetModerator(moderation.NewService(keywordModerator{keyword: "attack"},
+		config.ModerationConfig{DefaultThreshold: 0.5, CheckResults: true}))
+
+	comments := []models.Comment{{ID: "c1", Body: "How do I secure my server?"}}
+	if !pipeline.contextAllowed(context.Background(), models.SearchRequest{}, comments) {
+		t.Error("Expected clean thread context to be allowed")
+	}
+	comments = append(comments, models.Comment{ID: "c2", Body: "Attack it first: attack."})
+	if pipeline.contextAllowed(context.Background(), models.SearchRequest{}, comments) {
+		t.Error("Expected flagged thread context to be left out")
+	}
+}
@@ -0,0 +1,99 @@
+// File: backend/internal/services/pipeline_related.go
+
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+const (
+	// maxRelatedSubreddits caps SearchResponse.RelatedSubreddits
+	maxRelatedSubreddits = 8
+	// maxRelatedDescription caps each related community's description
+	maxRelatedDescription = 200
+)
+
+// relatedSubreddits starts looking up the communities related to a search
+// and returns a channel delivering them, so the Reddit lookup overlaps with
+// answering. Communities results came from come first, most results first,
+// then communities matching the query. Those the request was already scoped
+// to are left out.
+func (p *SearchPipeline) relatedSubreddits(ctx context.Context, req models.SearchRequest, results []models.SearchResult) <-chan []models.RelatedSubreddit {
+	related := make(chan []models.RelatedSubreddit, 1)
+	go func() {
+		var neighbors []models.SearchResult
+		if p.reddit != nil {
+			var err error
+			if neighbors, err = p.reddit.SubredditsForQuery(ctx, req.Query, maxRelatedSubreddits); err != nil {
+				log.Printf("Failed to find subreddits related to '%s': %v", req.Query, err)
+			}
+		}
+		related <- mergeRelatedSubreddits(results, neighbors, append(append([]string{}, req.Subreddits...), req.ExcludeSubreddits...))
+	}()
+	return related
+}
+
+// mergeRelatedSubreddits combines the communities results came from with
+// neighbors, communities found by searching for the query
+func mergeRelatedSubreddits(results, neighbors []models.SearchResult, exclude []string) []models.RelatedSubreddit {
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[config.NormalizeSubreddit(name)] = true
+	}
+
+	var (
+		related     []models.RelatedSubreddit
+		communities []models.SearchResult
+	)
+	index := make(map[string]int)
+	for _, result := range results {
+		key := config.NormalizeSubreddit(result.Subreddit)
+		if key == "" || excluded[key] {
+			continue
+		}
+		// Community results are neighbors, not content from the community
+		if result.Type == "subreddit" {
+			communities = append(communities, result)
+			continue
+		}
+		if i, ok := index[key]; ok {
+			related[i].Results++
+			continue
+		}
+		index[key] = len(related)
+		related = append(related, models.RelatedSubreddit{Name: result.Subreddit, Results: 1})
+	}
+	// Stable, so ties keep the rank of their best result
+	sort.SliceStable(related, func(i, j int) bool { return related[i].Results > related[j].Results })
+	for i := range related {
+		index[config.NormalizeSubreddit(related[i].Name)] = i
+	}
+
+	for _, neighbor := range append(communities, neighbors...) {
+		key := config.NormalizeSubreddit(neighbor.Subreddit)
+		if key == "" || excluded[key] || neighbor.NSFW {
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(related)
+			index[key] = i
+			related = append(related, models.RelatedSubreddit{Name: neighbor.Subreddit})
+		}
+		if related[i].Subscribers == 0 {
+			related[i].Subscribers = neighbor.Score
+			related[i].Description = utils.TruncateWithEllipsis(neighbor.Content, maxRelatedDescription)
+		}
+	}
+
+	if len(related) > maxRelatedSubreddits {
+		related = related[:maxRelatedSubreddits]
+	}
+	return related
+}
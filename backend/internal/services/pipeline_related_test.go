@@ -0,0 +1,60 @@
+// File: backend/internal/services/pipeline_related_test.go
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+func TestMergeRelatedSubreddits(t *testing.T) {
+	results := []models.SearchResult{
+		{ID: "1", Type: "post", Subreddit: "golang"},
+		{ID: "2", Type: "comment", Subreddit: "rust"},
+		{ID: "3", Type: "post", Subreddit: "Rust"},
+		{ID: "4", Type: "post", Subreddit: "programming"},
+		{ID: "5", Type: "subreddit", Subreddit: "learnrust", Score: 90000, Content: "Learning Rust"},
+	}
+	neighbors := []models.SearchResult{
+		{Type: "subreddit", Subreddit: "rust", Score: 300000, Content: "The Rust programming language"},
+		{Type: "subreddit", Subreddit: "Golang", Score: 250000},
+		{Type: "subreddit", Subreddit: "nsfwcode", NSFW: true},
+		{Type: "subreddit", Subreddit: "rust_gamedev", Score: 40000},
+	}
+
+	related := mergeRelatedSubreddits(results, neighbors, []string{"r/Golang"})
+	want := []models.RelatedSubreddit{
+		{Name: "rust", Results: 2, Subscribers: 300000, Description: "The Rust programming language"},
+		{Name: "programming", Results: 1},
+		{Name: "learnrust", Subscribers: 90000, Description: "Learning Rust"},
+		{Name: "rust_gamedev", Subscribers: 40000},
+	}
+	if len(related) != len(want) {
+		t.Fatalf("Expected %+v, got %+v", want, related)
+	}
+	for i := range want {
+		if related[i] != want[i] {
+			t.Errorf("Related subreddit %d: expected %+v, got %+v", i, want[i], related[i])
+		}
+	}
+}
+
+func TestPipelineRelatedSubreddits(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddSubreddits(redditmock.Subreddit{Name: "golang", Description: "Go released its news here", Subscribers: 250000})
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	response, err := pipeline.Run(context.Background(), models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.RelatedSubreddits) != 1 {
+		t.Fatalf("Expected r/golang as related, got %+v", response.RelatedSubreddits)
+	}
+	if got := response.RelatedSubreddits[0]; got.Name != "golang" || got.Results != 1 || got.Subscribers != 250000 {
+		t.Errorf("Expected r/golang with its result and subscribers, got %+v", got)
+	}
+}
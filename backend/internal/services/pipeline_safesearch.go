@@ -0,0 +1,68 @@
+// File: backend/internal/services/pipeline_safesearch.go
+
+package services
+
+import (
+	"log"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// SetSafeSearch replaces the safe search configuration: whether every
+// request is searched safely and the communities safe searches leave out
+func (p *SearchPipeline) SetSafeSearch(cfg config.SafeSearchConfig) {
+	p.safeSearch = cfg
+}
+
+// applySafeSearch marks a normalized request as safe when the server
+// searches every request safely, and leaves the configured communities out
+// of safe requests
+func (p *SearchPipeline) applySafeSearch(req *models.SearchRequest) {
+	if p.safeSearch.Enabled {
+		req.SafeSearch = true
+	}
+	if !req.SafeSearch {
+		return
+	}
+
+	excluded := make(map[string]bool, len(req.ExcludeSubreddits))
+	for _, sr := range req.ExcludeSubreddits {
+		excluded[config.NormalizeSubreddit(sr)] = true
+	}
+	for _, sr := range p.safeSearch.ExcludedSubreddits {
+		if !excluded[sr] {
+			excluded[sr] = true
+			req.ExcludeSubreddits = append(req.ExcludeSubreddits, sr)
+		}
+	}
+}
+
+// safeResults drops NSFW results and results from excluded communities for
+// safe requests. Searches leave those communities out already, but results
+// callers supply, such as subreddit listings, may include them.
+func safeResults(req models.SearchRequest, results []models.SearchResult) []models.SearchResult {
+	if !req.SafeSearch {
+		return results
+	}
+
+	excluded := make(map[string]bool, len(req.ExcludeSubreddits))
+	for _, sr := range req.ExcludeSubreddits {
+		excluded[config.NormalizeSubreddit(sr)] = true
+	}
+	safe := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.NSFW || excluded[config.NormalizeSubreddit(result.Subreddit)] {
+			log.Printf("Safe search removed result %s from r/%s", result.ID, result.Subreddit)
+			continue
+		}
+		safe = append(safe, result)
+	}
+	return safe
+}
+
+// moderates reports whether results and answers must be moderated for a
+// request even when the moderation configuration checks neither
+func (p *SearchPipeline) moderates(req models.SearchRequest) bool {
+	return req.SafeSearch && p.moderator != nil
+}
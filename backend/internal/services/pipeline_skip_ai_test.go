@@ -0,0 +1,204 @@
+// File: backend/internal/services/pipeline_skip_ai_test.go
+
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/contentpolicy"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+func TestPipelineSkipAI(t *testing.T) {
+	reddit, _ := newMockRedditService(t)
+	// Without an AI service, any model call would panic
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	response, err := pipeline.Run(context.Background(), models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].ID != "gp1" {
+		t.Fatalf("Expected the mock post, got %+v", response.Results)
+	}
+	if response.Answer != "" || response.Reasoning != "" || response.AnswerError != nil {
+		t.Errorf("Expected no answer, got %+v", response)
+	}
+	if !response.RequestParams.SkipAI || response.TotalCount != 1 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+	if response.Results[0].Raw != nil {
+		t.Errorf("Expected no raw JSON unless asked for, got %s", response.Results[0].Raw)
+	}
+
+	// Raw JSON survives caching for requests that ask for it
+	raw, err := pipeline.Run(context.Background(), models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true, IncludeRaw: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(raw.Results) != 1 || !strings.Contains(string(raw.Results[0].Raw), `"id":"gp1"`) {
+		t.Errorf("Expected the post's raw JSON, got %+v", raw.Results)
+	}
+
+	// Raw JSON isn't masked, so it's dropped whenever results are rewritten
+	safe, err := pipeline.Run(context.Background(), models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true, IncludeRaw: true, SafeSearch: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(safe.Results) != 1 || safe.Results[0].Raw != nil || safe.RequestParams.IncludeRaw {
+		t.Errorf("Expected no raw JSON with safe search, got %+v", safe.Results)
+	}
+	pipeline.SetContentPolicy(contentpolicy.New(config.ContentPolicyConfig{NSFW: config.NSFWAllow, MaskProfanity: true}))
+	masked, err := pipeline.Run(context.Background(), models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true, IncludeRaw: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(masked.Results) != 1 || masked.Results[0].Raw != nil {
+		t.Errorf("Expected no raw JSON with a masking content policy, got %+v", masked.Results)
+	}
+
+	// Nothing found is an empty list rather than an explanation
+	empty, err := pipeline.RunWithResults(context.Background(), models.SearchRequest{Query: "go", SkipAI: true}, nil, models.SourceReddit)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if empty.Results == nil || empty.Answer != "" {
+		t.Errorf("Expected empty results and no answer, got %+v", empty)
+	}
+}
+
+func TestPipelineMinEngagement(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddPosts(
+		redditmock.Post{ID: "gp2", Subreddit: "golang", Title: "Go 1.22 released, thoughts?", Author: "newbie", Score: 1},
+		redditmock.Post{ID: "gp3", Subreddit: "golang", Title: "Go 1.22 released: what changed", Author: "writer", Score: 90, NumComments: 2},
+	)
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	req := models.SearchRequest{Query: "go released", SearchMode: "Posts", SkipAI: true}
+	response, err := pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected all three posts without thresholds, got %+v", response.Results)
+	}
+
+	req.MinScore = 10
+	req.MinComments = 1
+	response, err = pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].ID != "gp3" {
+		t.Errorf("Expected only the post with votes and comments, got %+v", response.Results)
+	}
+	if response.RequestParams.MinScore != 10 || response.RequestParams.MinComments != 1 {
+		t.Errorf("Expected the thresholds in the request params, got %+v", response.RequestParams)
+	}
+}
+
+func TestPipelineExcludeSubreddits(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddPosts(redditmock.Post{ID: "cj1", Subreddit: "circlejerk", Title: "Go 1.22 released, rewrite it in Rust", Author: "jerk", Score: 900})
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	response, err := pipeline.Run(context.Background(), models.SearchRequest{
+		Query: "go released", SearchMode: "Posts", SkipAI: true, ExcludeSubreddits: []string{"r/CircleJerk"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].ID != "gp1" {
+		t.Errorf("Expected only the r/golang post, got %+v", response.Results)
+	}
+
+	var excluded bool
+	for _, req := range mock.Requests() {
+		if req.Path == "/search.json" && strings.Contains(req.Query.Get("q"), "-subreddit:CircleJerk") {
+			excluded = true
+		}
+	}
+	if !excluded {
+		t.Errorf("Expected the search to exclude the community, got requests %+v", mock.Requests())
+	}
+}
+
+func TestPipelineExcludeAuthors(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddPosts(redditmock.Post{ID: "am1", Subreddit: "golang", Title: "Go 1.22 released: weekly thread", Author: "AutoModerator", Score: 50})
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	testCases := []struct {
+		name     string
+		authors  []string
+		expected string
+	}{
+		{"Default", nil, "gp1"},
+		{"None", []string{}, "am1,gp1"},
+		{"Listed", []string{" u/Gopher"}, "am1"},
+	}
+	for _, tc := range testCases {
+		response, err := pipeline.Run(context.Background(), models.SearchRequest{
+			Query: "go released", SearchMode: "Posts", SkipAI: true, ExcludeAuthors: tc.authors,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		var ids []string
+		for _, result := range response.Results {
+			ids = append(ids, result.ID)
+		}
+		sort.Strings(ids)
+		if got := strings.Join(ids, ","); got != tc.expected {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestPipelineSafeSearch(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	mock.AddPosts(
+		redditmock.Post{ID: "nsfw1", Subreddit: "golang", Title: "Go 1.22 released, NSFW memes", Author: "gopher", Score: 300, NSFW: true},
+		redditmock.Post{ID: "dr1", Subreddit: "drugs", Title: "Go 1.22 released while on caffeine", Author: "gopher", Score: 200},
+	)
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	testCases := []struct {
+		name     string
+		safe     bool
+		enabled  bool
+		expected string
+	}{
+		{"Off", false, false, "gp1,nsfw1,dr1"},
+		{"Requested", true, false, "gp1"},
+		{"Configured", false, true, "gp1"},
+	}
+	for _, tc := range testCases {
+		pipeline.SetSafeSearch(config.SafeSearchConfig{Enabled: tc.enabled, ExcludedSubreddits: []string{"drugs"}})
+		response, err := pipeline.Run(context.Background(), models.SearchRequest{
+			Query: "go released", SearchMode: "Posts", SkipAI: true, SafeSearch: tc.safe,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		var ids []string
+		for _, result := range response.Results {
+			ids = append(ids, result.ID)
+		}
+		sort.Strings(ids)
+		expected := strings.Split(tc.expected, ",")
+		sort.Strings(expected)
+		if strings.Join(ids, ",") != strings.Join(expected, ",") {
+			t.Errorf("%s: expected results %v, got %v", tc.name, expected, ids)
+		}
+		if response.RequestParams.SafeSearch != (tc.safe || tc.enabled) {
+			t.Errorf("%s: expected safeSearch %v in the request params", tc.name, tc.safe || tc.enabled)
+		}
+	}
+}
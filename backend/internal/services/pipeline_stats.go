@@ -0,0 +1,103 @@
+// File: backend/internal/services/pipeline_stats.go
+
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// resultStats aggregates results for SearchResponse.Stats, with ages
+// measured at now. It returns nil when there are no results.
+func resultStats(results []models.SearchResult, now time.Time) *models.ResultStats {
+	if len(results) == 0 {
+		return nil
+	}
+
+	stats := &models.ResultStats{Results: len(results)}
+	var (
+		scores   []int
+		totalAge int64
+		dated    int64
+	)
+	index := make(map[string]int)
+	for _, result := range results {
+		if result.Type == "subreddit" {
+			continue
+		}
+		switch result.Type {
+		case "post":
+			stats.Posts++
+			stats.TotalComments += result.CommentCount
+		case "comment":
+			stats.Comments++
+		}
+		scores = append(scores, result.Score)
+
+		if key := config.NormalizeSubreddit(result.Subreddit); key != "" {
+			if i, ok := index[key]; ok {
+				stats.SubredditBreakdown[i].Results++
+			} else {
+				index[key] = len(stats.SubredditBreakdown)
+				stats.SubredditBreakdown = append(stats.SubredditBreakdown, models.SubredditCount{Name: result.Subreddit, Results: 1})
+			}
+		}
+
+		if result.CreatedUTC > 0 {
+			totalAge += now.Unix() - result.CreatedUTC
+			dated++
+			if stats.OldestUTC == 0 || result.CreatedUTC < stats.OldestUTC {
+				stats.OldestUTC = result.CreatedUTC
+			}
+			if result.CreatedUTC > stats.NewestUTC {
+				stats.NewestUTC = result.CreatedUTC
+			}
+		}
+	}
+
+	// Stable, so ties keep the rank of their best result
+	sort.SliceStable(stats.SubredditBreakdown, func(i, j int) bool {
+		return stats.SubredditBreakdown[i].Results > stats.SubredditBreakdown[j].Results
+	})
+	stats.Subreddits = len(stats.SubredditBreakdown)
+	stats.Score = scoreDistribution(scores)
+	if dated > 0 {
+		stats.AverageAgeSeconds = totalAge / dated
+	}
+	return stats
+}
+
+// scoreDistribution summarizes scores, or returns nil when there are none
+func scoreDistribution(scores []int) *models.ScoreDistribution {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	sorted := append([]int(nil), scores...)
+	sort.Ints(sorted)
+	distribution := &models.ScoreDistribution{
+		Min:    sorted[0],
+		P25:    scorePercentile(sorted, 25),
+		Median: scorePercentile(sorted, 50),
+		P75:    scorePercentile(sorted, 75),
+		Max:    sorted[len(sorted)-1],
+	}
+	for _, score := range sorted {
+		distribution.Total += score
+	}
+	distribution.Mean = math.Round(float64(distribution.Total)/float64(len(sorted))*10) / 10
+	return distribution
+}
+
+// scorePercentile returns the nearest-rank percentile p of sorted scores
+func scorePercentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,48 @@
+// File: backend/internal/services/pipeline_stats_test.go
+
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestResultStats(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	day := int64(24 * 60 * 60)
+	results := []models.SearchResult{
+		{Type: "post", Subreddit: "OLED_Gaming", Score: 900, CommentCount: 120, CreatedUTC: now.Unix() - 2*day},
+		{Type: "comment", Subreddit: "4kTV", Score: 40, CreatedUTC: now.Unix() - 4*day},
+		{Type: "post", Subreddit: "4kTV", Score: 300, CommentCount: 30, CreatedUTC: now.Unix() - 6*day},
+		{Type: "comment", Subreddit: "oled_gaming", Score: 10},
+		// Community results only count as results
+		{Type: "subreddit", Subreddit: "televisions", Score: 250000},
+	}
+
+	stats := resultStats(results, now)
+	if stats.Results != 5 || stats.Posts != 2 || stats.Comments != 2 || stats.TotalComments != 150 {
+		t.Errorf("Unexpected counts %+v", stats)
+	}
+
+	expected := []models.SubredditCount{{Name: "OLED_Gaming", Results: 2}, {Name: "4kTV", Results: 2}}
+	if stats.Subreddits != 2 || !reflect.DeepEqual(stats.SubredditBreakdown, expected) {
+		t.Errorf("Expected breakdown %+v, got %d: %+v", expected, stats.Subreddits, stats.SubredditBreakdown)
+	}
+
+	score := models.ScoreDistribution{Min: 10, P25: 10, Median: 40, P75: 300, Max: 900, Mean: 312.5, Total: 1250}
+	if stats.Score == nil || *stats.Score != score {
+		t.Errorf("Expected scores %+v, got %+v", score, stats.Score)
+	}
+
+	// Results without a creation time don't count towards age
+	if stats.AverageAgeSeconds != 4*day || stats.OldestUTC != now.Unix()-6*day || stats.NewestUTC != now.Unix()-2*day {
+		t.Errorf("Unexpected ages %+v", stats)
+	}
+
+	if resultStats(nil, now) != nil {
+		t.Error("Expected no stats without results")
+	}
+}
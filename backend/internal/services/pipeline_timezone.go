@@ -0,0 +1,60 @@
+// File: backend/internal/services/pipeline_timezone.go
+
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+var errUnknownTimezone = errors.New("unknown timezone")
+
+// requestLocation loads a request's IANA timezone. It returns nil, without
+// an error, when the request has none.
+func requestLocation(timezone string) (*time.Location, error) {
+	timezone = strings.TrimSpace(timezone)
+	// time.LoadLocation treats "" as UTC and "Local" as the server's zone,
+	// neither of which a client means
+	if timezone == "" {
+		return nil, nil
+	}
+	if timezone == "Local" {
+		return nil, errUnknownTimezone
+	}
+	return time.LoadLocation(timezone)
+}
+
+// localizeResults returns results with their creation times given in the
+// request's timezone. Results are copied, as they may be shared with the
+// cache.
+func localizeResults(results []models.SearchResult, req models.SearchRequest) []models.SearchResult {
+	loc, err := requestLocation(req.Timezone)
+	if loc == nil || err != nil || len(results) == 0 {
+		return results
+	}
+
+	localized := make([]models.SearchResult, len(results))
+	for i, result := range results {
+		if result.CreatedUTC > 0 {
+			created := time.Unix(result.CreatedUTC, 0)
+			result.CreatedLocal = utils.FormatLocalTime(created, loc)
+			result.CreatedAgo = utils.FormatTimeAgoIn(created, loc, req.Locale)
+		}
+		localized[i] = result
+	}
+	return localized
+}
+
+// localizeLastUpdated gives a response's LastUpdated in the request's
+// timezone
+func localizeLastUpdated(response *models.SearchResponse, req models.SearchRequest) {
+	loc, err := requestLocation(req.Timezone)
+	if loc == nil || err != nil || response.LastUpdated == 0 {
+		return
+	}
+	response.LastUpdatedLocal = utils.FormatLocalTime(time.Unix(response.LastUpdated, 0), loc)
+}
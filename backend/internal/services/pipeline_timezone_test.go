@@ -0,0 +1,56 @@
+// File: backend/internal/services/pipeline_timezone_test.go
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestValidateRequestTimezone(t *testing.T) {
+	for timezone, valid := range map[string]bool{
+		"":                 true,
+		"UTC":              true,
+		"America/New_York": true,
+		" Europe/Berlin ":  true,
+		"Local":            false,
+		"Mars/Olympus":     false,
+	} {
+		err := ValidateRequest(&models.SearchRequest{Query: "q", Timezone: timezone})
+		if (err == nil) != valid {
+			t.Errorf("timezone %q: expected valid=%v, got %v", timezone, valid, err)
+		}
+	}
+}
+
+func TestLocalizeResults(t *testing.T) {
+	created := time.Date(2024, 7, 1, 18, 30, 0, 0, time.UTC).Unix()
+	results := []models.SearchResult{{ID: "a", CreatedUTC: created}, {ID: "b"}}
+
+	if got := localizeResults(results, models.SearchRequest{}); got[0].CreatedLocal != "" {
+		t.Errorf("Expected no local time without a timezone, got %q", got[0].CreatedLocal)
+	}
+
+	req := models.SearchRequest{Timezone: "Asia/Tokyo", Locale: "en"}
+	localized := localizeResults(results, req)
+	if localized[0].CreatedLocal != "2024-07-02 03:30 JST" {
+		t.Errorf("Expected Tokyo local time, got %q", localized[0].CreatedLocal)
+	}
+	if localized[0].CreatedAgo == "" {
+		t.Error("Expected a relative time")
+	}
+	if localized[1].CreatedLocal != "" {
+		t.Errorf("Expected no local time for a result without a creation time, got %q", localized[1].CreatedLocal)
+	}
+	if results[0].CreatedLocal != "" {
+		t.Error("Expected the caller's results to be left unchanged")
+	}
+
+	response := &models.SearchResponse{LastUpdated: created}
+	localizeLastUpdated(response, req)
+	if response.LastUpdatedLocal != "2024-07-02 03:30 JST" {
+		t.Errorf("Expected Tokyo LastUpdatedLocal, got %q", response.LastUpdatedLocal)
+	}
+}
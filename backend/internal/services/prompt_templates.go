@@ -0,0 +1,115 @@
+// File: backend/internal/services/prompt_templates.go
+
+package services
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/pranesh-j/subplexity/templates"
+)
+
+// defaultPromptTemplatesDir is where prompt templates are read from when
+// PROMPT_TEMPLATES_DIR isn't set
+const defaultPromptTemplatesDir = "templates/prompts"
+
+// promptData is the data passed to prompt templates
+type promptData struct {
+	Query            string
+	Results          string
+	ResultCount      int
+	TotalResultCount int
+	Answer           string   // Only set for the critic and comparison templates
+	Options          []string // Only set for the comparison template
+}
+
+// PromptTemplates holds the parsed prompt templates keyed by name (the file
+// name without its .tmpl extension). The built-in templates are always
+// loaded first; files in dir override them, so prompts can be edited and
+// reloaded without a rebuild.
+type PromptTemplates struct {
+	dir       string
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// LoadPromptTemplates loads the built-in templates and any overrides in dir
+func LoadPromptTemplates(dir string) (*PromptTemplates, error) {
+	p := &PromptTemplates{dir: dir}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads the templates. If any template fails to parse, the
+// previously loaded set is kept and the error is returned.
+func (p *PromptTemplates) Reload() error {
+	loaded, err := parsePromptTemplates(templates.Prompts, "prompts")
+	if err != nil {
+		return fmt.Errorf("error loading built-in prompt templates: %w", err)
+	}
+
+	overrides := 0
+	if p.dir != "" {
+		if _, statErr := os.Stat(p.dir); statErr == nil {
+			fromDisk, err := parsePromptTemplates(os.DirFS(p.dir), ".")
+			if err != nil {
+				return fmt.Errorf("error loading prompt templates from %s: %w", p.dir, err)
+			}
+			for name, tmpl := range fromDisk {
+				loaded[name] = tmpl
+			}
+			overrides = len(fromDisk)
+		}
+	}
+
+	p.mu.Lock()
+	p.templates = loaded
+	p.mu.Unlock()
+
+	log.Printf("Loaded %d prompt templates (%d from %s)", len(loaded), overrides, p.dir)
+	return nil
+}
+
+// Get returns the named template, falling back to "default"
+func (p *PromptTemplates) Get(name string) *template.Template {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if tmpl, ok := p.templates[name]; ok {
+		return tmpl
+	}
+	return p.templates["default"]
+}
+
+// parsePromptTemplates parses every .tmpl file in dir of fsys
+func parsePromptTemplates(fsys fs.FS, dir string) (map[string]*template.Template, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := make(map[string]*template.Template)
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = tmpl
+	}
+
+	return parsed, nil
+}
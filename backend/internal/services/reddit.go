@@ -21,8 +21,10 @@ import (
 	"time"
 
 	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/config"
 	"github.com/pranesh-j/subplexity/internal/models"
 	"github.com/pranesh-j/subplexity/internal/utils"
+	"golang.org/x/sync/singleflight"
 )
 // Constants for the Reddit service
 const (
@@ -31,12 +33,23 @@ const (
 	redditUserAgent      = "golang:com.subplexity.api:v1.0.0 (by /u/Pran_J)"
 	defaultRequestLimit  = 25
 	maxRequestLimit      = 100
-	maxConcurrentQueries = 5
-	initialRetryDelay    = 1 * time.Second
 	maxRetryDelay        = 30 * time.Second
 	maxRetries           = 3
+	// sharedRequestTimeout bounds a coalesced request, which outlives the
+	// caller that started it
+	sharedRequestTimeout = 60 * time.Second
 )
 
+// negativeCacheTTL is how long a search that found nothing is remembered,
+// so repeated typos and obscure topics don't fan out to Reddit each time
+// while new posts can still show up soon after. It is a variable so tests
+// can shorten it.
+var negativeCacheTTL = 2 * time.Minute
+
+// initialRetryDelay is the wait before retrying a failed Reddit request,
+// growing with each retry. It is a variable so tests can shorten it.
+var initialRetryDelay = 1 * time.Second
+
 // RedditServiceConfig contains configuration options for the Reddit service
 type RedditServiceConfig struct {
 	ClientID     string
@@ -48,11 +61,20 @@ type RedditServiceConfig struct {
 
 // RedditService handles interactions with the Reddit API
 type RedditService struct {
-	config       RedditServiceConfig
-	auth         *RedditAuth
-	resultCache  *cache.Cache
-	rateLimiter  chan struct{}
-	httpClient   *http.Client
+	config      RedditServiceConfig
+	auth        *RedditAuth
+	resultCache *cache.Cache[[]models.SearchResult]
+	// trendingCache and commentCache are small, short-lived caches for
+	// GetTrending and GetComments
+	trendingCache *cache.Cache[*models.TrendingResponse]
+	commentCache  *cache.Cache[*models.CommentThread]
+	// wikiCache holds wiki page listings and pages, which change rarely
+	wikiCache     *cache.Cache[[]byte]
+	limiter       *adaptiveLimiter // Bounds concurrent Reddit requests
+	rateLimit     rateLimitBudget  // Reddit's last reported request budget
+	httpClient    *http.Client
+	inflight      singleflight.Group // Identical concurrent requests, see executeRequest
+	regions       map[string][]string // Communities of each region hint, see SetRegions
 }
 
 // NewRedditService creates a new Reddit service instance
@@ -68,6 +90,10 @@ func NewRedditService(clientID, clientSecret string) *RedditService {
 		},
 	}
 
+	// Start from the default concurrency bounds; see SetConcurrencyConfig
+	limiter := newAdaptiveLimiter(config.Default().RedditConcurrency)
+	regions := config.Default().Regions.Subreddits
+
 	// Default configuration
 	config := RedditServiceConfig{
 		ClientID:     clientID,
@@ -81,15 +107,81 @@ func NewRedditService(clientID, clientSecret string) *RedditService {
 	auth := NewRedditAuth(clientID, clientSecret, redditUserAgent, httpClient)
 
 	// Create result cache
-	resultCache := cache.NewCache(config.CacheConfig)
+	resultCache := cache.NewCache[[]models.SearchResult](config.CacheConfig)
 
 	return &RedditService{
-		config:       config,
-		auth:         auth,
-		resultCache:  resultCache,
-		rateLimiter:  make(chan struct{}, maxConcurrentQueries),
-		httpClient:   httpClient,
+		config:      config,
+		auth:        auth,
+		resultCache: resultCache,
+		trendingCache: cache.NewCache[*models.TrendingResponse](cache.Config{
+			MaxItems:   100,
+			DefaultTTL: trendingCacheTTL,
+		}),
+		commentCache: cache.NewCache[*models.CommentThread](cache.Config{
+			MaxItems:   500,
+			DefaultTTL: commentsCacheTTL,
+		}),
+		wikiCache: cache.NewCache[[]byte](cache.Config{
+			MaxItems:   500,
+			DefaultTTL: wikiCacheTTL,
+		}),
+		limiter:     limiter,
+		httpClient:  httpClient,
+		regions:     regions,
+	}
+}
+
+// SetConcurrencyConfig replaces the bounds of the adaptive concurrency
+// limit. It should be called before serving requests.
+func (s *RedditService) SetConcurrencyConfig(cfg config.RedditConcurrencyConfig) {
+	s.limiter = newAdaptiveLimiter(cfg)
+}
+
+// SetHTTPClient sends the service's Reddit requests, including token
+// requests, through client, such as one pointed at a mock Reddit API. It
+// should be called before serving requests.
+func (s *RedditService) SetHTTPClient(client *http.Client) {
+	s.httpClient = client
+	s.config.HttpClient = client
+	s.auth = NewRedditAuth(s.config.ClientID, s.config.ClientSecret, s.config.UserAgent, client)
+}
+
+// SetRegions replaces the communities searched for each region hint. It
+// should be called before serving requests.
+func (s *RedditService) SetRegions(cfg config.RegionsConfig) {
+	s.regions = cfg.Subreddits
+}
+
+// RegionSubreddits returns the communities of a region hint, and whether
+// the region is known
+func (s *RedditService) RegionSubreddits(region string) ([]string, bool) {
+	subreddits, ok := s.regions[strings.ToLower(strings.TrimSpace(region))]
+	return subreddits, ok
+}
+
+// Regions returns the region hints searches accept, sorted
+func (s *RedditService) Regions() []string {
+	regions := make([]string, 0, len(s.regions))
+	for region := range s.regions {
+		regions = append(regions, region)
 	}
+	sort.Strings(regions)
+	return regions
+}
+
+// ConcurrencyStatus reports the adaptive limit on concurrent Reddit requests
+func (s *RedditService) ConcurrencyStatus() models.ConcurrencyStatus {
+	return s.limiter.Status()
+}
+
+// RateLimitBudget reports the request budget Reddit last gave this server
+func (s *RedditService) RateLimitBudget() models.RateLimitBudget {
+	return s.rateLimit.status()
+}
+
+// UpdateConcurrency changes the bounds of the concurrency limit at runtime
+func (s *RedditService) UpdateConcurrency(settings models.ConcurrencySettings) error {
+	return s.limiter.Update(settings)
 }
 
 // GetAuthStatus returns the current authentication status
@@ -98,10 +190,139 @@ func (s *RedditService) GetAuthStatus() map[string]interface{} {
 }
 
 
+// namedCache is the part of a cache.Cache the service manages by name
+type namedCache interface {
+	Stats() cache.Stats
+	Clear()
+}
+
+// caches returns the service's caches keyed by name
+func (s *RedditService) caches() map[string]namedCache {
+	return map[string]namedCache{
+		"search":   s.resultCache,
+		"trending": s.trendingCache,
+		"comments": s.commentCache,
+		"wiki":     s.wikiCache,
+	}
+}
+
+// CacheStats returns usage counters for each cache, keyed by cache name
+func (s *RedditService) CacheStats() map[string]cache.Stats {
+	stats := make(map[string]cache.Stats)
+	for name, c := range s.caches() {
+		stats[name] = c.Stats()
+	}
+	return stats
+}
+
+// FlushCaches clears the named caches, or all of them when none are named,
+// and returns the names cleared. Unknown names are ignored.
+func (s *RedditService) FlushCaches(names []string) []string {
+	caches := s.caches()
+	if len(names) == 0 {
+		for name := range caches {
+			names = append(names, name)
+		}
+	}
+
+	var flushed []string
+	for _, name := range names {
+		if c, ok := caches[name]; ok {
+			c.Clear()
+			flushed = append(flushed, name)
+		}
+	}
+	sort.Strings(flushed)
+	return flushed
+}
+
 // Updated SearchReddit function in backend/internal/services/reddit.go
 // This changes how it handles ranking queries like "top 5 TV shows right now"
 
+// SearchOptions carries optional per-request constraints for SearchReddit
+type SearchOptions struct {
+    Subreddits        []string // Restrict the search to these communities
+    RecencyBias       string   // How strongly to favor newer results; see models.SearchRequest
+    MinScore          int      // Drop posts and comments with fewer net upvotes
+    MinComments       int      // Drop posts with fewer comments
+    ExcludeSubreddits []string // Leave out content from these communities
+    ExcludeAuthors    []string // Leave out content by these users; nil leaves out defaultExcludedAuthors
+    Region            string   // Also search, and favor, the communities of this region hint
+}
+
+// defaultExcludedAuthors are bots whose posts and comments are boilerplate
+// rather than answers, left out unless a request lists its own authors
+var defaultExcludedAuthors = []string{"AutoModerator"}
+
+// excludedAuthors returns the users whose content the search leaves out
+func (o SearchOptions) excludedAuthors() []string {
+    if o.ExcludeAuthors == nil {
+        return defaultExcludedAuthors
+    }
+    return o.ExcludeAuthors
+}
+
+// cacheKey returns a stable representation of the options for cache keys
+func (o SearchOptions) cacheKey() string {
+    key := strings.ToLower(strings.Join(o.Subreddits, ","))
+    if o.RecencyBias != "" {
+        key += ":recency=" + o.RecencyBias
+    }
+    if o.MinScore > 0 || o.MinComments > 0 {
+        key += fmt.Sprintf(":min=%d/%d", o.MinScore, o.MinComments)
+    }
+    if len(o.ExcludeSubreddits) > 0 {
+        key += ":exclude=" + strings.ToLower(strings.Join(o.ExcludeSubreddits, ","))
+    }
+    if o.ExcludeAuthors != nil {
+        key += ":authors=" + strings.ToLower(strings.Join(o.ExcludeAuthors, ","))
+    }
+    if o.Region != "" {
+        key += ":region=" + strings.ToLower(o.Region)
+    }
+    return key
+}
+
+// filter drops the results from excluded communities, and those below the
+// options' score and comment thresholds. Communities and wiki pages have
+// neither score nor comments, so only exclusions apply to them.
+func (o SearchOptions) filter(results []models.SearchResult) []models.SearchResult {
+    if o.MinScore <= 0 && o.MinComments <= 0 && len(o.ExcludeSubreddits) == 0 {
+        return results
+    }
+    excluded := make(map[string]bool, len(o.ExcludeSubreddits))
+    for _, sr := range o.ExcludeSubreddits {
+        excluded[config.NormalizeSubreddit(sr)] = true
+    }
+    kept := make([]models.SearchResult, 0, len(results))
+    for _, result := range results {
+        if excluded[config.NormalizeSubreddit(result.Subreddit)] {
+            continue
+        }
+        switch result.Type {
+        case "post":
+            if result.Score < o.MinScore || result.CommentCount < o.MinComments {
+                continue
+            }
+        case "comment":
+            if result.Score < o.MinScore {
+                continue
+            }
+        }
+        kept = append(kept, result)
+    }
+    if dropped := len(results) - len(kept); dropped > 0 {
+        log.Printf("Dropped %d results from excluded communities or below minScore %d or minComments %d", dropped, o.MinScore, o.MinComments)
+    }
+    return kept
+}
+
 func (s *RedditService) SearchReddit(ctx context.Context, query string, searchMode string, limit int) ([]models.SearchResult, error) {
+    return s.SearchRedditWithOptions(ctx, query, searchMode, limit, SearchOptions{})
+}
+
+// SearchRedditWithOptions searches Reddit like SearchReddit, honoring the given options
+func (s *RedditService) SearchRedditWithOptions(ctx context.Context, query string, searchMode string, limit int, opts SearchOptions) ([]models.SearchResult, error) {
     // Validate and normalize parameters
     if query == "" {
         return nil, errors.New("search query cannot be empty")
@@ -116,24 +337,38 @@ func (s *RedditService) SearchReddit(ctx context.Context, query string, searchMo
     // Parse query to extract intent and parameters
     params := utils.ParseQuery(query)
 
+    // Scope the search to the requested communities, ahead of any mentioned in the query
+    if len(opts.Subreddits) > 0 {
+        params.Subreddits = getUniqueItems(append(append([]string{}, opts.Subreddits...), params.Subreddits...))
+    }
+    params.RecencyBias = opts.RecencyBias
+    params.ExcludeSubreddits = opts.ExcludeSubreddits
+    params.ExcludeAuthors = opts.excludedAuthors()
+    if opts.Region != "" {
+        params.RegionSubreddits, _ = s.RegionSubreddits(opts.Region)
+    }
+
     // Log the search request
     log.Printf("Starting Reddit search for query: '%s', mode: '%s', limit: %d", query, searchMode, limit)
 
     // Check cache with time sensitivity awareness
-    cacheKey := fmt.Sprintf("search:%s:%s:%d", query, searchMode, limit)
+    cacheKey := fmt.Sprintf("search:%s:%s:%d:%s", query, searchMode, limit, opts.cacheKey())
     if !params.IsTimeSensitive {
         // Use normal cache for non-time-sensitive queries
         if cachedResults, found := s.resultCache.Get(cacheKey); found {
             log.Printf("Cache hit for query: '%s'", query)
-            return cachedResults.([]models.SearchResult), nil
+            trackCacheLookup(ctx, true)
+            return cachedResults, nil
         }
     } else {
         // Use short TTL cache for time-sensitive queries
         if cachedResults, found := s.resultCache.GetWithTTL(cacheKey, 5*time.Minute); found {
             log.Printf("Short TTL cache hit for time-sensitive query: '%s'", query)
-            return cachedResults.([]models.SearchResult), nil
+            trackCacheLookup(ctx, true)
+            return cachedResults, nil
         }
     }
+    trackCacheLookup(ctx, false)
     
     // Convert searchMode to search type if specified
     searchType := ""
@@ -202,8 +437,19 @@ func (s *RedditService) SearchReddit(ctx context.Context, query string, searchMo
         return nil, fmt.Errorf("search failed: %w", err)
     }
 
-    // Process and score results
-    processedResults := s.processSearchResults(params, results, limit)
+    // Searches that aren't scoped look in the region's communities too
+    if len(params.RegionSubreddits) > 0 && len(params.Subreddits) == 0 && searchType != "sr" {
+        results = withRegionalResults(results, s.searchRegion(ctx, params, searchType, limit))
+    }
+
+    // Process and score results, leaving out excluded communities and
+    // low-engagement noise first
+    processedResults := s.processSearchResults(params, opts.filter(results), limit)
+
+    // For breaking news, the top comments of megathreads are primary sources
+    if params.IsTimeSensitive && (searchType == "" || searchType == "comment") {
+        processedResults = withPrimarySources(withoutAuthors(opts.filter(s.megathreadSources(ctx, params)), params.ExcludeAuthors), processedResults, limit)
+    }
 
     // Cache the processed results with appropriate TTL
     if len(processedResults) > 0 {
@@ -214,6 +460,9 @@ func (s *RedditService) SearchReddit(ctx context.Context, query string, searchMo
             // Use default TTL for normal queries
             s.resultCache.Set(cacheKey, processedResults)
         }
+    } else {
+        // Remember the miss briefly; store an empty slice so a hit returns no results
+        s.resultCache.SetWithTTL(cacheKey, []models.SearchResult{}, negativeCacheTTL)
     }
 
     log.Printf("Search completed, returning %d results", len(processedResults))
@@ -294,6 +543,9 @@ func (s *RedditService) timeAwareSearch(ctx context.Context, params utils.QueryP
             }
         }
         
+        // Local communities come first for regional requests
+        relevantSubreddits = getUniqueItems(append(append([]string{}, params.RegionSubreddits...), relevantSubreddits...))
+
         // Limit to a reasonable number of subreddits
         if len(relevantSubreddits) > 3 {
             relevantSubreddits = relevantSubreddits[:3]
@@ -434,6 +686,7 @@ func (s *RedditService) rankingFocusedSearch(ctx context.Context, params utils.Q
                 
                 // Create params for this specific query
                 queryParams := utils.ParseQuery(query)
+                queryParams.ExcludeSubreddits = params.ExcludeSubreddits
                 queryParams.SortBy = "top"
                 queryParams.TimeFrame = "all" // Start with all-time for rankings
                 
@@ -621,8 +874,9 @@ func (s *RedditService) enhanceSearchForRankingQueries(ctx context.Context, para
         }
     }
     
-    // Keep only unique subreddits and limit to a reasonable number
-    relevantSubreddits = getUniqueItems(relevantSubreddits)
+    // Keep only unique subreddits, local ones first for regional requests,
+    // and limit to a reasonable number
+    relevantSubreddits = getUniqueItems(append(append([]string{}, params.RegionSubreddits...), relevantSubreddits...))
     if len(relevantSubreddits) > 5 {
         relevantSubreddits = relevantSubreddits[:5]
     }
@@ -703,7 +957,7 @@ func (s *RedditService) enhanceSearchForRankingQueries(ctx context.Context, para
         
         // Build query parameters
         queryParams := url.Values{}
-        queryParams.Set("q", enhancedQuery)
+        queryParams.Set("q", excludingSubreddits(enhancedQuery, params.ExcludeSubreddits))
         queryParams.Set("limit", fmt.Sprintf("%d", limit))
         queryParams.Set("sort", "relevance")
         
@@ -756,7 +1010,7 @@ func (s *RedditService) enhanceSearchForRankingQueries(ctx context.Context, para
         
         // Build query parameters for recent content
         queryParams := url.Values{}
-        queryParams.Set("q", combinedQuery)
+        queryParams.Set("q", excludingSubreddits(combinedQuery, params.ExcludeSubreddits))
         queryParams.Set("limit", fmt.Sprintf("%d", limit))
         queryParams.Set("sort", "new")
         queryParams.Set("t", "week") // Focus on very recent content
@@ -1012,8 +1266,8 @@ func (s *RedditService) parallelSearch(ctx context.Context, params utils.QueryPa
 	defer cancel()
 
 	// Create channels for results and errors
-	resultChan := make(chan []models.SearchResult, 3)
-	errorChan := make(chan error, 3)
+	resultChan := make(chan []models.SearchResult, 4)
+	errorChan := make(chan error, 4)
 	searchCount := 0
 
 	// Launch search functions based on query intent
@@ -1054,6 +1308,20 @@ func (s *RedditService) parallelSearch(ctx context.Context, params utils.QueryPa
 		}()
 	}
 
+	// Add community wikis for evergreen questions
+	if searchType == "" && !params.IsTimeSensitive {
+		searchCount++
+		go func() {
+			vector := &WikiSearchVector{Keywords: params.FilteredKeywords, Subreddits: params.Subreddits, Limit: limit/4 + 1}
+			results, err := vector.Execute(ctx, s)
+			if err != nil {
+				errorChan <- err
+			} else {
+				resultChan <- results
+			}
+		}()
+	}
+
 	// Collect results from all searches
 	var allResults []models.SearchResult
 	var lastErr error
@@ -1102,8 +1370,9 @@ func (s *RedditService) searchPosts(ctx context.Context, params utils.QueryParam
 	q := params.OriginalQuery
 	if len(params.Subreddits) > 0 {
 		// Add subreddit restriction
-		q = fmt.Sprintf("%s subreddit:%s", q, params.Subreddits[0])
+		q = fmt.Sprintf("%s %s", q, subredditRestriction(params.Subreddits))
 	}
+	q = excludingSubreddits(q, params.ExcludeSubreddits)
 
 	// Build query parameters
 	queryParams := url.Values{}
@@ -1124,8 +1393,9 @@ func (s *RedditService) searchComments(ctx context.Context, params utils.QueryPa
 	q := params.OriginalQuery
 	if len(params.Subreddits) > 0 {
 		// Add subreddit restriction
-		q = fmt.Sprintf("%s subreddit:%s", q, params.Subreddits[0])
+		q = fmt.Sprintf("%s %s", q, subredditRestriction(params.Subreddits))
 	}
+	q = excludingSubreddits(q, params.ExcludeSubreddits)
 
 	// Build query parameters
 	queryParams := url.Values{}
@@ -1263,22 +1533,82 @@ func (s *RedditService) searchTrending(ctx context.Context, params utils.QueryPa
 	return allResults, nil
 }
 
-// executeSearchRequest performs the actual HTTP request to the Reddit API
+// FetchListing returns posts from a subreddit listing such as "new", "hot"
+// or "top". Top listings cover the past day.
+func (s *RedditService) FetchListing(ctx context.Context, subreddit, listing string, limit int) ([]models.SearchResult, error) {
+	if limit <= 0 || limit > maxRequestLimit {
+		limit = maxRequestLimit
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("limit", fmt.Sprintf("%d", limit))
+	if listing == "top" {
+		queryParams.Set("t", "day")
+	}
+
+	endpoint := fmt.Sprintf("/r/%s/%s.json?%s", url.PathEscape(subreddit), listing, queryParams.Encode())
+	return s.executeSearchRequest(ctx, endpoint)
+}
+
+// executeSearchRequest fetches a Reddit listing and parses it into search results
 func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint string) ([]models.SearchResult, error) {
-	// Acquire rate limiter slot
+	body, err := s.executeRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse response into search results
+	results, err := parseRedditResponse(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return results, nil
+}
+
+// executeRequest fetches endpoint from the Reddit API and returns the raw
+// response body. Search strategies often request the same endpoint at once,
+// so identical in-flight requests share one call.
+func (s *RedditService) executeRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	// The endpoint includes its encoded query parameters, so it identifies the call
+	result := s.inflight.DoChan(endpoint, func() (interface{}, error) {
+		// Detached from the first caller so its cancellation doesn't fail
+		// the others, but bounded in case every caller gives up
+		sharedCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedRequestTimeout)
+		defer cancel()
+		return s.doRequest(sharedCtx, endpoint)
+	})
+
 	select {
-	case s.rateLimiter <- struct{}{}:
-		// Got the slot, continue
-		defer func() { <-s.rateLimiter }()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
 	case <-ctx.Done():
-		// Context cancelled while waiting for slot
 		return nil, ctx.Err()
 	}
+}
 
-	// Get access token (authenticated requests are preferred)
+// doRequest performs the actual HTTP request to the Reddit API and returns
+// the raw response body
+func (s *RedditService) doRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	// Acquire a concurrency slot, reporting how Reddit responded when done
+	if err := s.limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	var latency time.Duration
+	throttled := false
+	defer func() { s.limiter.Release(latency, throttled) }()
+
+	// Get access token (authenticated requests are preferred). Requests made
+	// on a user's behalf use the user's token; see userRequest.
+	userToken, _ := ctx.Value(userTokenKey{}).(string)
 	var token string
-	token, err := s.auth.GetAccessToken(ctx)
-	if err != nil {
+	var err error
+	if userToken != "" {
+		token = userToken
+	} else if token, err = s.auth.GetAccessToken(ctx); err != nil {
 		log.Printf("Warning: Failed to get access token, proceeding without authentication: %v", err)
 		// Continue without token - Reddit allows anonymous access with rate limits
 	}
@@ -1350,7 +1680,12 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 
 		// Make the request
 		var reqErr error
+		requestStart := time.Now()
 		resp, reqErr = s.httpClient.Do(reqClone)
+		if reqErr == nil {
+			latency = time.Since(requestStart)
+			s.rateLimit.observe(resp.Header)
+		}
 		
 		// Check for context cancellation
 		if reqErr != nil {
@@ -1359,7 +1694,7 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 			}
 			
 			if attempt == maxRetries {
-				return nil, fmt.Errorf("request failed after %d attempts: %w", maxRetries, reqErr)
+				return nil, fmt.Errorf("%w: request failed after %d attempts: %w", ErrRedditUnavailable, maxRetries, reqErr)
 			}
 			
 			// Exponential backoff for next retry
@@ -1384,6 +1719,11 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 			
 			resp.Body.Close()
 			
+			// The user revoked access, or their token expired; only they can fix it
+			if userToken != "" && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+				return nil, fmt.Errorf("%w: Reddit refused the user's token (%d)", ErrRedditLoginRequired, resp.StatusCode)
+			}
+
 			if resp.StatusCode == http.StatusUnauthorized {
 				// Clear invalid token
 				s.auth.Clear()
@@ -1428,8 +1768,9 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 			
 			// Handle rate limiting
 			if resp.StatusCode == http.StatusTooManyRequests {
+				throttled = true
 				if attempt == maxRetries {
-					return nil, errors.New("rate limited by Reddit API")
+					return nil, ErrRedditRateLimited
 				}
 				
 				// Use rate limit headers if available
@@ -1445,6 +1786,9 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 			}
 			
 			if attempt == maxRetries {
+				if resp.StatusCode >= http.StatusInternalServerError {
+					return nil, fmt.Errorf("%w: HTTP error: %d - %s", ErrRedditUnavailable, resp.StatusCode, errorDetails)
+				}
 				return nil, fmt.Errorf("HTTP error: %d - %s", resp.StatusCode, errorDetails)
 			}
 			
@@ -1470,13 +1814,7 @@ func (s *RedditService) executeSearchRequest(ctx context.Context, endpoint strin
 		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	// Parse response into search results
-	results, err := parseRedditResponse(body)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
-	}
-
-	return results, nil
+	return body, nil
 }
 
 // Helper method to check if a result matches query keywords
@@ -1496,4 +1834,59 @@ func (s *RedditService) resultMatchesKeywords(result models.SearchResult, keywor
 	}
 
 	return false
-}
\ No newline at end of file
+}
+// subredditRestriction builds a Reddit search operator limiting results to the given subreddits
+func subredditRestriction(subreddits []string) string {
+	if len(subreddits) == 1 {
+		return "subreddit:" + subreddits[0]
+	}
+
+	terms := make([]string, len(subreddits))
+	for i, sr := range subreddits {
+		terms[i] = "subreddit:" + sr
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+// excludingSubreddits appends Reddit search operators leaving out the given
+// subreddits to q
+func excludingSubreddits(q string, subreddits []string) string {
+	for _, sr := range subreddits {
+		q += " -subreddit:" + sr
+	}
+	return q
+}
+
+// searchRegion searches the communities of the request's region, so local
+// discussions compete with the rest even when Reddit ranks them lower
+func (s *RedditService) searchRegion(ctx context.Context, params utils.QueryParams, searchType string, limit int) []models.SearchResult {
+	regionParams := params
+	regionParams.Subreddits = params.RegionSubreddits
+	var results []models.SearchResult
+	var err error
+	if searchType == "comment" {
+		results, err = s.searchComments(ctx, regionParams, limit)
+	} else {
+		results, err = s.searchPosts(ctx, regionParams, searchType, limit)
+	}
+	if err != nil {
+		log.Printf("Regional search in %v failed: %v", params.RegionSubreddits, err)
+		return nil
+	}
+	return results
+}
+
+// withRegionalResults adds the regional results not already among results
+func withRegionalResults(results, regional []models.SearchResult) []models.SearchResult {
+	seen := make(map[string]bool, len(results))
+	for _, result := range results {
+		seen[result.ID] = true
+	}
+	for _, result := range regional {
+		if !seen[result.ID] {
+			seen[result.ID] = true
+			results = append(results, result)
+		}
+	}
+	return results
+}
@@ -162,6 +162,11 @@ func (r *RedditAuth) GetAuthStatus() map[string]interface{} {
 	}
 }
 
+// HasCredentials reports whether an OAuth client ID and secret are configured
+func (r *RedditAuth) HasCredentials() bool {
+	return r.clientID != "" && r.clientSecret != ""
+}
+
 // Clear invalidates the current token
 func (r *RedditAuth) Clear() {
 	r.tokenLock.Lock()
@@ -0,0 +1,295 @@
+// File: backend/internal/services/reddit_comments.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// commentsCacheTTL is short because comment scores and replies change quickly
+const commentsCacheTTL = 2 * time.Minute
+
+// CommentOptions controls which comments GetComments returns
+type CommentOptions struct {
+	Sort  string // confidence, top, new, controversial, old or qa
+	Limit int    // Maximum number of comments in the tree
+	Depth int    // Maximum reply depth
+	// Expand follows "more" stubs to load comments Reddit left out, within
+	// the bounds of expandMoreComments
+	Expand bool
+}
+
+// redditThing is the kind/data envelope Reddit wraps every object in
+type redditThing struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+// redditListing is a listing of things
+type redditListing struct {
+	Data struct {
+		Children []redditThing `json:"children"`
+	} `json:"data"`
+}
+
+// GetComments returns a post and its comment tree
+func (s *RedditService) GetComments(ctx context.Context, postID string, opts CommentOptions) (*models.CommentThread, error) {
+	cacheKey := fmt.Sprintf("comments:%s:%s:%d:%d:%t", postID, opts.Sort, opts.Limit, opts.Depth, opts.Expand)
+	if cached, found := s.commentCache.Get(cacheKey); found {
+		return cached, nil
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("sort", opts.Sort)
+	queryParams.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	queryParams.Set("depth", fmt.Sprintf("%d", opts.Depth))
+	queryParams.Set("raw_json", "1")
+
+	endpoint := fmt.Sprintf("/comments/%s.json?%s", url.PathEscape(postID), queryParams.Encode())
+	body, err := s.executeRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := parseCommentThread(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing comments: %w", err)
+	}
+
+	// A partly expanded thread is still worth returning
+	if opts.Expand {
+		if err := s.expandMoreComments(ctx, thread, opts.Sort); err != nil {
+			log.Printf("Error expanding comments of %s: %v", postID, err)
+		}
+	}
+
+	s.commentCache.Set(cacheKey, thread)
+	return thread, nil
+}
+
+// GetCommentContext returns a post with a single comment and up to levels of
+// the comments above it. Thread.Comments holds the outermost of those, each
+// with the next as its only reply.
+func (s *RedditService) GetCommentContext(ctx context.Context, postID, commentID string, levels int) (*models.CommentThread, error) {
+	cacheKey := fmt.Sprintf("context:%s:%s:%d", postID, commentID, levels)
+	if cached, found := s.commentCache.Get(cacheKey); found {
+		return cached, nil
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("context", fmt.Sprintf("%d", levels))
+	queryParams.Set("raw_json", "1")
+
+	endpoint := fmt.Sprintf("/comments/%s/_/%s.json?%s", url.PathEscape(postID), url.PathEscape(commentID), queryParams.Encode())
+	body, err := s.executeRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	thread, err := parseCommentThread(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing comment context: %w", err)
+	}
+
+	s.commentCache.Set(cacheKey, thread)
+	return thread, nil
+}
+
+// commentAncestors returns the comments above commentID in a context
+// thread, outermost first and without their replies
+func commentAncestors(thread *models.CommentThread, commentID string) []models.Comment {
+	var ancestors []models.Comment
+	level := thread.Comments
+	for len(level) > 0 {
+		comment := level[0]
+		if comment.ID == commentID {
+			return ancestors
+		}
+		level = comment.Replies
+		comment.Replies, comment.More = nil, nil
+		ancestors = append(ancestors, comment)
+	}
+	// The comment wasn't in the thread, so the chain isn't its context
+	return nil
+}
+
+// ThreadResults converts a post and its top-level comments into results for
+// answering questions about the thread: the post first, then up to
+// maxComments comments by score. Removed comments are skipped.
+func ThreadResults(thread *models.CommentThread, maxComments int) []models.SearchResult {
+	var comments []models.Comment
+	for _, comment := range thread.Comments {
+		if !isRemovedText(comment.Body) && comment.Author != "AutoModerator" {
+			comments = append(comments, comment)
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Score > comments[j].Score
+	})
+	if len(comments) > maxComments {
+		comments = comments[:maxComments]
+	}
+
+	results := make([]models.SearchResult, 0, len(comments)+1)
+	results = append(results, thread.Post)
+	for _, comment := range comments {
+		results = append(results, models.SearchResult{
+			ID:            comment.ID,
+			Type:          "comment",
+			Title:         "Comment on: " + thread.Post.Title,
+			Subreddit:     thread.Post.Subreddit,
+			Author:        comment.Author,
+			Content:       comment.Body,
+			URL:           comment.URL,
+			Permalink:     comment.URL,
+			CreatedUTC:    comment.CreatedUTC,
+			Score:         comment.Score,
+			Distinguished: comment.Distinguished != "",
+			Stickied:      comment.Stickied,
+			ParentID:      comment.ParentID,
+			LinkID:        "t3_" + thread.Post.ID,
+			Depth:         comment.Depth,
+		})
+	}
+	return results
+}
+
+// parseCommentThread parses the two-listing response of the comments
+// endpoint: the post, then its top-level comments
+func parseCommentThread(rawResponse []byte) (*models.CommentThread, error) {
+	var listings []json.RawMessage
+	if err := json.Unmarshal(rawResponse, &listings); err != nil {
+		return nil, fmt.Errorf("error parsing comments JSON: %w", err)
+	}
+	if len(listings) != 2 {
+		return nil, fmt.Errorf("expected post and comment listings, got %d listings", len(listings))
+	}
+
+	posts, err := parseRedditResponse(listings[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("comments response contains no post")
+	}
+
+	var comments redditListing
+	if err := json.Unmarshal(listings[1], &comments); err != nil {
+		return nil, fmt.Errorf("error parsing comment listing: %w", err)
+	}
+
+	thread := &models.CommentThread{Post: posts[0]}
+	thread.Comments, thread.More = parseCommentChildren(comments.Data.Children)
+	if thread.Comments == nil {
+		thread.Comments = []models.Comment{}
+	}
+	return thread, nil
+}
+
+// parseCommentChildren parses one level of a comment tree, returning its
+// comments and any "more" stub. Malformed comments are skipped.
+func parseCommentChildren(children []redditThing) ([]models.Comment, *models.MoreComments) {
+	var comments []models.Comment
+	var more *models.MoreComments
+
+	for _, child := range children {
+		switch child.Kind {
+		case "t1":
+			comment, err := parseComment(child.Data)
+			if err != nil {
+				log.Printf("Error parsing comment: %v", err)
+				continue
+			}
+			comments = append(comments, comment)
+		case "more":
+			stub, err := parseMoreComments(child.Data)
+			if err != nil {
+				log.Printf("Error parsing more stub: %v", err)
+				continue
+			}
+			// Reddit sends at most one stub per level; merge defensively
+			if more == nil {
+				more = stub
+			} else {
+				more.Count += stub.Count
+				more.Children = append(more.Children, stub.Children...)
+			}
+		}
+	}
+
+	return comments, more
+}
+
+// parseComment parses a comment (t1) and, recursively, its replies
+func parseComment(data []byte) (models.Comment, error) {
+	var raw struct {
+		ID            string          `json:"id"`
+		ParentID      string          `json:"parent_id"`
+		Author        string          `json:"author"`
+		Body          string          `json:"body"`
+		Score         int             `json:"score"`
+		CreatedUTC    float64         `json:"created_utc"`
+		Permalink     string          `json:"permalink"`
+		Depth         int             `json:"depth"`
+		IsSubmitter   bool            `json:"is_submitter"`
+		Distinguished string          `json:"distinguished"`
+		Stickied      bool            `json:"stickied"`
+		Replies       json.RawMessage `json:"replies"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return models.Comment{}, fmt.Errorf("error parsing comment JSON: %w", err)
+	}
+
+	comment := models.Comment{
+		ID:            raw.ID,
+		ParentID:      raw.ParentID,
+		Author:        raw.Author,
+		Body:          raw.Body,
+		Score:         raw.Score,
+		CreatedUTC:    int64(raw.CreatedUTC),
+		Depth:         raw.Depth,
+		IsSubmitter:   raw.IsSubmitter,
+		Distinguished: raw.Distinguished,
+		Stickied:      raw.Stickied,
+	}
+	if raw.Permalink != "" {
+		comment.URL = "https://www.reddit.com" + raw.Permalink
+	}
+
+	// Replies is an empty string when there are none, otherwise a listing
+	if len(raw.Replies) > 0 && raw.Replies[0] == '{' {
+		var replies redditListing
+		if err := json.Unmarshal(raw.Replies, &replies); err != nil {
+			return models.Comment{}, fmt.Errorf("error parsing replies of %s: %w", raw.ID, err)
+		}
+		comment.Replies, comment.More = parseCommentChildren(replies.Data.Children)
+	}
+
+	return comment, nil
+}
+
+// parseMoreComments parses a "more" stub
+func parseMoreComments(data []byte) (*models.MoreComments, error) {
+	var raw struct {
+		Count    int      `json:"count"`
+		ParentID string   `json:"parent_id"`
+		Children []string `json:"children"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing more JSON: %w", err)
+	}
+
+	return &models.MoreComments{
+		Count:    raw.Count,
+		ParentID: raw.ParentID,
+		Children: raw.Children,
+	}, nil
+}
@@ -0,0 +1,140 @@
+// File: backend/internal/services/reddit_comments_test.go
+
+package services
+
+import (
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestParseCommentThread(t *testing.T) {
+	response := `[
+	  {"kind": "Listing", "data": {"children": [
+	    {"kind": "t3", "data": {"id": "abc", "title": "A post", "subreddit": "golang", "permalink": "/r/golang/comments/abc/a_post/"}}
+	  ]}},
+	  {"kind": "Listing", "data": {"children": [
+	    {"kind": "t1", "data": {"id": "c1", "parent_id": "t3_abc", "body": "Top level", "score": 10, "depth": 0,
+	      "replies": {"kind": "Listing", "data": {"children": [
+	        {"kind": "t1", "data": {"id": "c2", "parent_id": "t1_c1", "body": "Nested", "depth": 1, "is_submitter": true, "replies": ""}},
+	        {"kind": "more", "data": {"count": 0, "parent_id": "t1_c1", "children": []}}
+	      ]}}}},
+	    {"kind": "t1", "data": {"id": "c3", "parent_id": "t3_abc", "body": "Second", "replies": ""}},
+	    {"kind": "more", "data": {"count": 42, "parent_id": "t3_abc", "children": ["c4", "c5"]}}
+	  ]}}
+	]`
+
+	thread, err := parseCommentThread([]byte(response))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if thread.Post.ID != "abc" || len(thread.Comments) != 2 {
+		t.Fatalf("Expected post abc with 2 top-level comments, got %q with %d", thread.Post.ID, len(thread.Comments))
+	}
+	if thread.More == nil || thread.More.Count != 42 || len(thread.More.Children) != 2 {
+		t.Errorf("Expected top-level more stub with 42 comments, got %+v", thread.More)
+	}
+
+	first := thread.Comments[0]
+	if len(first.Replies) != 1 || first.Replies[0].Body != "Nested" || !first.Replies[0].IsSubmitter {
+		t.Errorf("Expected one nested reply from the submitter, got %+v", first.Replies)
+	}
+	if first.More == nil || len(first.More.Children) != 0 {
+		t.Errorf("Expected a continue-thread stub on the first comment, got %+v", first.More)
+	}
+	if thread.Comments[1].Replies != nil {
+		t.Errorf("Expected no replies when Reddit sends an empty string")
+	}
+}
+
+func TestCommentAncestors(t *testing.T) {
+	thread := &models.CommentThread{Comments: []models.Comment{{
+		ID: "a", Body: "Question",
+		Replies: []models.Comment{{
+			ID: "b", ParentID: "t1_a", Body: "Follow-up",
+			Replies: []models.Comment{{ID: "c", ParentID: "t1_b", Body: "Answer"}},
+		}},
+	}}}
+
+	ancestors := commentAncestors(thread, "c")
+	if len(ancestors) != 2 || ancestors[0].ID != "a" || ancestors[1].ID != "b" || ancestors[0].Replies != nil {
+		t.Errorf("Expected ancestors a and b without replies, got %+v", ancestors)
+	}
+	if ancestors := commentAncestors(thread, "missing"); ancestors != nil {
+		t.Errorf("Expected no ancestors for a comment outside the thread, got %+v", ancestors)
+	}
+}
+
+func TestThreadResults(t *testing.T) {
+	thread := &models.CommentThread{
+		Post: models.SearchResult{ID: "abc", Type: "post", Title: "A post", Subreddit: "golang"},
+		Comments: []models.Comment{
+			{ID: "c1", Body: "Low", Score: 1},
+			{ID: "c2", Body: "[removed]", Score: 50},
+			{ID: "c3", Body: "High", Score: 20},
+			{ID: "c4", Body: "Rules reminder", Author: "AutoModerator", Score: 30},
+			{ID: "c5", Body: "Middle", Score: 5},
+		},
+	}
+
+	results := ThreadResults(thread, 2)
+	if len(results) != 3 || results[0].ID != "abc" {
+		t.Fatalf("Expected the post and 2 comments, got %+v", results)
+	}
+	if results[1].ID != "c3" || results[2].ID != "c5" {
+		t.Errorf("Expected comments by score, got %s, %s", results[1].ID, results[2].ID)
+	}
+	if results[1].Type != "comment" || results[1].LinkID != "t3_abc" || results[1].Title != "Comment on: A post" {
+		t.Errorf("Unexpected comment result: %+v", results[1])
+	}
+}
+
+func TestHeavilyCitedReply(t *testing.T) {
+	results := []models.SearchResult{
+		{ID: "p", Type: "post"},
+		{ID: "top", Type: "comment", ParentID: "t3_p", LinkID: "t3_p"},
+		{ID: "reply", Type: "comment", ParentID: "t1_top", LinkID: "t3_p"},
+	}
+
+	if got := heavilyCitedReply("A [1][1][1][1]. B [2][2][2]. C [3][3][3].", results); got != 2 {
+		t.Errorf("Expected the heavily cited reply at index 2, got %d", got)
+	}
+	if got := heavilyCitedReply("C [3][3]. D [9][9][9].", results); got != -1 {
+		t.Errorf("Expected no reply below the threshold, got %d", got)
+	}
+}
+
+func TestAttachMoreChildren(t *testing.T) {
+	thread := &models.CommentThread{
+		Post:     models.SearchResult{ID: "p"},
+		Comments: []models.Comment{{ID: "a", More: &models.MoreComments{Count: 1, ParentID: "t1_a", Children: []string{"c"}}}},
+		More:     &models.MoreComments{Count: 1, ParentID: "t3_p", Children: []string{"b"}},
+	}
+	if stub := nextMoreStub(thread); stub != thread.More {
+		t.Fatalf("Expected the top-level stub first, got %+v", stub)
+	}
+	thread.More.Children, thread.More.Count = nil, 0
+
+	things := []redditThing{
+		{Kind: "t1", Data: []byte(`{"id": "b", "parent_id": "t3_p", "body": "Buried answer", "replies": ""}`)},
+		{Kind: "t1", Data: []byte(`{"id": "d", "parent_id": "t1_b", "body": "Reply to the buried answer", "replies": ""}`)},
+		{Kind: "more", Data: []byte(`{"count": 4, "parent_id": "t1_d", "children": ["e", "f"]}`)},
+		{Kind: "t1", Data: []byte(`{"id": "x", "parent_id": "t1_unknown", "body": "Orphan", "replies": ""}`)},
+	}
+	attachMoreChildren(thread, things)
+
+	if thread.More != nil {
+		t.Errorf("Expected the emptied top-level stub to be removed, got %+v", thread.More)
+	}
+	if len(thread.Comments) != 2 || thread.Comments[1].ID != "b" {
+		t.Fatalf("Expected the buried answer at the top level, got %+v", thread.Comments)
+	}
+	buried := thread.Comments[1]
+	if len(buried.Replies) != 1 || buried.Replies[0].ID != "d" || buried.Replies[0].More == nil || buried.Replies[0].More.Count != 4 {
+		t.Errorf("Expected the new reply nested with its stub, got %+v", buried.Replies)
+	}
+	if stub := nextMoreStub(thread); stub == nil || stub.ParentID != "t1_a" {
+		t.Errorf("Expected the shallowest remaining stub next, got %+v", stub)
+	}
+}
@@ -0,0 +1,142 @@
+// File: backend/internal/services/reddit_limiter.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// Adjustments made by the adaptive limiter
+const (
+	throttledBackoff = 0.5 // Limit multiplier after Reddit answers 429
+	slowBackoff      = 0.9 // Limit multiplier after a response slower than the target
+)
+
+// adaptiveLimiter bounds concurrent Reddit requests with a limit that adapts
+// to how Reddit responds (AIMD): each fast response raises it by 1/limit, so
+// roughly by one per limit's worth of requests, while 429s halve it and slow
+// responses trim it.
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	settings  models.ConcurrencySettings
+	limit     float64
+	inFlight  int
+	throttled uint64
+	changed   chan struct{} // Closed and replaced whenever a slot may have opened
+}
+
+// newAdaptiveLimiter creates a limiter starting at the configured initial limit
+func newAdaptiveLimiter(cfg config.RedditConcurrencyConfig) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		settings: models.ConcurrencySettings{
+			Min:             cfg.Min,
+			Max:             cfg.Max,
+			TargetLatencyMs: cfg.TargetLatency.Milliseconds(),
+		},
+		limit:   float64(cfg.Initial),
+		changed: make(chan struct{}),
+	}
+}
+
+// Acquire waits for a free slot
+func (l *adaptiveLimiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees a slot and adjusts the limit from the request's outcome.
+// latency is zero when no response was received, which leaves the limit alone.
+func (l *adaptiveLimiter) Release(latency time.Duration, throttled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	target := time.Duration(l.settings.TargetLatencyMs) * time.Millisecond
+	switch {
+	case throttled:
+		l.throttled++
+		l.limit *= throttledBackoff
+	case latency <= 0:
+	case latency > target:
+		l.limit *= slowBackoff
+	default:
+		l.limit += 1 / l.limit
+	}
+	l.clamp()
+	l.notify()
+}
+
+// Status reports the current limit and usage
+func (l *adaptiveLimiter) Status() models.ConcurrencyStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return models.ConcurrencyStatus{
+		ConcurrencySettings: l.settings,
+		Limit:               int(l.limit),
+		InFlight:            l.inFlight,
+		Throttled:           l.throttled,
+	}
+}
+
+// Update replaces the limiter's bounds, moving the current limit inside them
+func (l *adaptiveLimiter) Update(settings models.ConcurrencySettings) error {
+	if err := validateConcurrency(settings); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.settings = settings
+	l.clamp()
+	l.notify()
+	return nil
+}
+
+// clamp keeps the limit within the configured bounds. Callers hold l.mu.
+func (l *adaptiveLimiter) clamp() {
+	l.limit = math.Max(float64(l.settings.Min), math.Min(float64(l.settings.Max), l.limit))
+}
+
+// notify wakes goroutines waiting in Acquire. Callers hold l.mu.
+func (l *adaptiveLimiter) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// validateConcurrency checks limiter bounds set at runtime
+func validateConcurrency(settings models.ConcurrencySettings) error {
+	if settings.Min < 1 {
+		return errors.New("min must be at least 1")
+	}
+	if settings.Max < settings.Min || settings.Max > config.MaxRedditConcurrency {
+		return fmt.Errorf("max must be between min and %d", config.MaxRedditConcurrency)
+	}
+	if settings.TargetLatencyMs <= 0 {
+		return errors.New("targetLatencyMs must be positive")
+	}
+	return nil
+}
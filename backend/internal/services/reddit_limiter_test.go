@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	limiter := newAdaptiveLimiter(config.RedditConcurrencyConfig{
+		Initial:       4,
+		Min:           1,
+		Max:           8,
+		TargetLatency: time.Second,
+	})
+
+	// Fast responses raise the limit by about one per limit's worth of requests
+	for i := 0; i < 4; i++ {
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire: %v", err)
+		}
+		limiter.Release(100*time.Millisecond, false)
+	}
+	if got := limiter.Status().Limit; got != 4 && got != 5 {
+		t.Errorf("limit after fast responses = %d, want about 5", got)
+	}
+
+	// A 429 halves it
+	limiter.Acquire(context.Background())
+	limiter.Release(100*time.Millisecond, true)
+	status := limiter.Status()
+	if status.Limit != 2 || status.Throttled != 1 {
+		t.Errorf("after 429: limit %d, throttled %d; want 2, 1", status.Limit, status.Throttled)
+	}
+
+	// A full limiter blocks until the context ends
+	limiter.Acquire(context.Background())
+	limiter.Acquire(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); err == nil {
+		t.Error("Acquire succeeded beyond the limit")
+	}
+
+	// Raising the floor at runtime frees a slot
+	if err := limiter.Update(models.ConcurrencySettings{Min: 3, Max: 8, TargetLatencyMs: 1000}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire after raising min: %v", err)
+	}
+	if err := limiter.Update(models.ConcurrencySettings{Min: 0, Max: 8, TargetLatencyMs: 1000}); err == nil {
+		t.Error("Update accepted min 0")
+	}
+}
@@ -0,0 +1,174 @@
+// File: backend/internal/services/reddit_media.go
+
+package services
+
+import (
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// redditImageSource is an image rendition in a post's preview
+type redditImageSource struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// redditPostMedia holds the media fields of a post (t3)
+type redditPostMedia struct {
+	Thumbnail string `json:"thumbnail"`
+	Preview   *struct {
+		Images []struct {
+			Source   redditImageSource `json:"source"`
+			Variants struct {
+				MP4 *struct {
+					Source redditImageSource `json:"source"`
+				} `json:"mp4"`
+			} `json:"variants"`
+		} `json:"images"`
+	} `json:"preview"`
+	GalleryData *struct {
+		Items []struct {
+			MediaID string `json:"media_id"`
+			Caption string `json:"caption"`
+		} `json:"items"`
+	} `json:"gallery_data"`
+	MediaMetadata map[string]struct {
+		Status string `json:"status"`
+		Kind   string `json:"e"` // "Image" or "AnimatedImage"
+		Source struct {
+			URL    string `json:"u"`
+			GIF    string `json:"gif"`
+			MP4    string `json:"mp4"`
+			Width  int    `json:"x"`
+			Height int    `json:"y"`
+		} `json:"s"`
+	} `json:"media_metadata"`
+	SecureMedia *struct {
+		RedditVideo *struct {
+			FallbackURL string `json:"fallback_url"`
+			HLSURL      string `json:"hls_url"`
+			Width       int    `json:"width"`
+			Height      int    `json:"height"`
+			Duration    int    `json:"duration"`
+		} `json:"reddit_video"`
+		OEmbed *struct {
+			ProviderName string `json:"provider_name"`
+			ThumbnailURL string `json:"thumbnail_url"`
+			Width        int    `json:"width"`
+			Height       int    `json:"height"`
+		} `json:"oembed"`
+	} `json:"secure_media"`
+	URL string `json:"url"`
+}
+
+// parsePostMedia extracts thumbnails, images and video from a post's JSON.
+// It returns nil for posts without media.
+func parsePostMedia(data []byte) *models.Media {
+	var post redditPostMedia
+	if err := json.Unmarshal(data, &post); err != nil {
+		return nil
+	}
+
+	media := &models.Media{}
+
+	// Reddit uses placeholder words ("self", "default", "nsfw", ...) when a
+	// post has no thumbnail of its own
+	if strings.HasPrefix(post.Thumbnail, "http") {
+		media.Thumbnail = mediaURL(post.Thumbnail)
+	}
+
+	// Galleries list their items in order in gallery_data, with the image
+	// details in media_metadata
+	if post.GalleryData != nil {
+		for _, item := range post.GalleryData.Items {
+			meta, ok := post.MediaMetadata[item.MediaID]
+			if !ok || meta.Status != "valid" {
+				continue
+			}
+			image := models.MediaImage{
+				URL:      mediaURL(meta.Source.URL),
+				Width:    meta.Source.Width,
+				Height:   meta.Source.Height,
+				Caption:  item.Caption,
+				Animated: meta.Kind == "AnimatedImage",
+			}
+			if image.Animated {
+				if meta.Source.MP4 != "" {
+					image.URL = mediaURL(meta.Source.MP4)
+				} else {
+					image.URL = mediaURL(meta.Source.GIF)
+				}
+			}
+			if image.URL != "" {
+				media.Images = append(media.Images, image)
+			}
+		}
+	} else if post.Preview != nil && len(post.Preview.Images) > 0 {
+		preview := post.Preview.Images[0]
+		image := models.MediaImage{
+			URL:    mediaURL(preview.Source.URL),
+			Width:  preview.Source.Width,
+			Height: preview.Source.Height,
+		}
+		if mp4 := preview.Variants.MP4; mp4 != nil && mp4.Source.URL != "" {
+			image.URL = mediaURL(mp4.Source.URL)
+			image.Animated = true
+		}
+		if image.URL != "" {
+			media.Images = append(media.Images, image)
+		}
+	}
+
+	if post.SecureMedia != nil {
+		if video := post.SecureMedia.RedditVideo; video != nil && video.FallbackURL != "" {
+			media.Video = &models.MediaVideo{
+				URL:      mediaURL(video.FallbackURL),
+				Provider: "reddit",
+				Width:    video.Width,
+				Height:   video.Height,
+				Duration: video.Duration,
+				HLSURL:   mediaURL(video.HLSURL),
+			}
+		} else if embed := post.SecureMedia.OEmbed; embed != nil && post.URL != "" {
+			media.Video = &models.MediaVideo{
+				URL:      post.URL,
+				Provider: embed.ProviderName,
+				Width:    embed.Width,
+				Height:   embed.Height,
+			}
+			if media.Thumbnail == "" {
+				media.Thumbnail = mediaURL(embed.ThumbnailURL)
+			}
+		}
+	}
+
+	if media.Thumbnail == "" && len(media.Images) == 0 && media.Video == nil {
+		return nil
+	}
+	return media
+}
+
+// mediaURL undoes the HTML escaping Reddit applies to URLs in JSON unless
+// raw_json=1 is requested
+func mediaURL(raw string) string {
+	return html.UnescapeString(raw)
+}
+
+// previewURLs returns a post's thumbnail and its first still image, skipping
+// animated images whose URLs point at videos
+func previewURLs(media *models.Media) (thumbnail, preview string) {
+	if media == nil {
+		return "", ""
+	}
+	for _, image := range media.Images {
+		if !image.Animated {
+			preview = image.URL
+			break
+		}
+	}
+	return media.Thumbnail, preview
+}
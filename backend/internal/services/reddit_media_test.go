@@ -0,0 +1,57 @@
+// File: backend/internal/services/reddit_media_test.go
+
+package services
+
+import "testing"
+
+func TestParsePostMedia(t *testing.T) {
+	gallery := `{
+	  "thumbnail": "https://b.thumbs.redditmedia.com/t.jpg",
+	  "is_gallery": true,
+	  "gallery_data": {"items": [{"media_id": "b", "caption": "Back"}, {"media_id": "a"}, {"media_id": "gone"}]},
+	  "media_metadata": {
+	    "a": {"status": "valid", "e": "Image", "s": {"u": "https://preview.redd.it/a.jpg?width=640&amp;s=1", "x": 640, "y": 480}},
+	    "b": {"status": "valid", "e": "AnimatedImage", "s": {"gif": "https://i.redd.it/b.gif", "mp4": "https://preview.redd.it/b.gif?format=mp4", "x": 320, "y": 240}},
+	    "gone": {"status": "failed"}
+	  }
+	}`
+	media := parsePostMedia([]byte(gallery))
+	if media == nil || len(media.Images) != 2 {
+		t.Fatalf("Expected 2 gallery images, got %+v", media)
+	}
+	if img := media.Images[0]; !img.Animated || img.URL != "https://preview.redd.it/b.gif?format=mp4" || img.Caption != "Back" {
+		t.Errorf("Expected the animated item first, in gallery order, got %+v", img)
+	}
+	if img := media.Images[1]; img.URL != "https://preview.redd.it/a.jpg?width=640&s=1" || img.Width != 640 {
+		t.Errorf("Expected an unescaped image URL with its size, got %+v", img)
+	}
+	if thumbnail, preview := previewURLs(media); thumbnail != "https://b.thumbs.redditmedia.com/t.jpg" || preview != "https://preview.redd.it/a.jpg?width=640&s=1" {
+		t.Errorf("Expected the thumbnail and the first still image, got %q and %q", thumbnail, preview)
+	}
+
+	video := `{
+	  "thumbnail": "default",
+	  "preview": {"images": [{"source": {"url": "https://external-preview.redd.it/v.jpg", "width": 1280, "height": 720}}]},
+	  "secure_media": {"reddit_video": {"fallback_url": "https://v.redd.it/x/DASH_720.mp4", "hls_url": "https://v.redd.it/x/HLSPlaylist.m3u8", "width": 1280, "height": 720, "duration": 42}}
+	}`
+	media = parsePostMedia([]byte(video))
+	if media == nil || media.Thumbnail != "" || len(media.Images) != 1 {
+		t.Fatalf("Expected a preview image and no placeholder thumbnail, got %+v", media)
+	}
+	if media.Video == nil || media.Video.Provider != "reddit" || media.Video.Duration != 42 {
+		t.Errorf("Expected a Reddit video, got %+v", media.Video)
+	}
+
+	embed := `{
+	  "url": "https://www.youtube.com/watch?v=123",
+	  "secure_media": {"type": "youtube.com", "oembed": {"provider_name": "YouTube", "thumbnail_url": "https://i.ytimg.com/vi/123/hqdefault.jpg"}}
+	}`
+	media = parsePostMedia([]byte(embed))
+	if media == nil || media.Video == nil || media.Video.Provider != "YouTube" || media.Thumbnail == "" {
+		t.Errorf("Expected an embedded YouTube video with its thumbnail, got %+v", media)
+	}
+
+	if media := parsePostMedia([]byte(`{"thumbnail": "self", "selftext": "text"}`)); media != nil {
+		t.Errorf("Expected no media for a text post, got %+v", media)
+	}
+}
@@ -0,0 +1,231 @@
+// File: backend/internal/services/reddit_megathreads.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// Limits on the megathread comments used as sources
+const (
+	maxMegathreads            = 2                  // Threads whose comments are used
+	megathreadCommentsPerPost = 6                  // Top comments taken from each thread
+	megathreadMaxAge          = 7 * 24 * time.Hour // Older threads are about a past event
+	megathreadMinCommentChars = 40                 // Shorter comments are rarely informative
+)
+
+// megathreadTitlePattern matches the titles communities give the threads
+// they consolidate breaking news into
+var megathreadTitlePattern = regexp.MustCompile(`(?i)\b(mega\s?thread|live\s+(thread|updates?|discussion)|discussion\s+thread)\b`)
+
+// megathreadSearchTerms restricts a Reddit search to megathread titles
+const megathreadSearchTerms = `(title:megathread OR title:"live thread" OR title:"live updates" OR title:"discussion thread")`
+
+// temporalKeywords describe when rather than what, and megathread titles
+// rarely contain them
+var temporalKeywords = map[string]bool{
+	"latest": true, "today": true, "now": true, "current": true, "currently": true,
+	"recent": true, "recently": true, "news": true, "update": true, "updates": true,
+	"breaking": true, "happening": true, "tonight": true, "yesterday": true,
+}
+
+// isMegathread reports whether a post is a megathread or live thread
+func isMegathread(result models.SearchResult) bool {
+	return result.Type == "post" && megathreadTitlePattern.MatchString(result.Title)
+}
+
+// megathreadSources finds recent megathreads about a time-sensitive query
+// and returns their most-upvoted comments, which during breaking news are
+// where most first-hand information ends up. Failures are logged and yield
+// no sources.
+func (s *RedditService) megathreadSources(ctx context.Context, params utils.QueryParams) []models.SearchResult {
+	threads := s.findMegathreads(ctx, params)
+	if len(threads) == 0 {
+		return nil
+	}
+
+	perThread := make([][]models.SearchResult, len(threads))
+	var wg sync.WaitGroup
+	for i, thread := range threads {
+		wg.Add(1)
+		go func(i int, thread models.SearchResult) {
+			defer wg.Done()
+			comments, err := s.GetComments(ctx, thread.ID, CommentOptions{Sort: "top", Limit: 50, Depth: 1, Expand: true})
+			if err != nil {
+				log.Printf("Error fetching megathread %s: %v", thread.ID, err)
+				return
+			}
+			perThread[i] = megathreadComments(comments, params.FilteredKeywords)
+		}(i, thread)
+	}
+	wg.Wait()
+
+	var sources []models.SearchResult
+	for _, comments := range perThread {
+		sources = append(sources, comments...)
+	}
+	log.Printf("Found %d megathread comments in %d threads for '%s'", len(sources), len(threads), params.OriginalQuery)
+	return sources
+}
+
+// findMegathreads returns the most active recent megathreads matching the
+// query, from Reddit search and, for scoped searches, the communities'
+// pinned posts
+func (s *RedditService) findMegathreads(ctx context.Context, params utils.QueryParams) []models.SearchResult {
+	keywords := subjectKeywords(params.FilteredKeywords)
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	q := strings.Join(keywords, " ") + " " + megathreadSearchTerms
+	if len(params.Subreddits) > 0 {
+		q += " " + subredditRestriction(params.Subreddits)
+	}
+	q = excludingSubreddits(q, params.ExcludeSubreddits)
+	queryParams := url.Values{}
+	queryParams.Set("q", q)
+	queryParams.Set("type", "link")
+	queryParams.Set("sort", "relevance")
+	queryParams.Set("t", "week")
+	queryParams.Set("limit", "10")
+
+	candidates, err := s.executeSearchRequest(ctx, "/search.json?"+queryParams.Encode())
+	if err != nil {
+		log.Printf("Megathread search failed: %v", err)
+	}
+
+	// Pinned posts come first in a community's hot listing
+	for _, subreddit := range params.Subreddits {
+		pinned, err := s.executeSearchRequest(ctx, fmt.Sprintf("/r/%s/hot.json?limit=5", url.PathEscape(subreddit)))
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, pinned...)
+	}
+
+	return selectMegathreads(candidates, keywords, time.Now())
+}
+
+// selectMegathreads keeps recent megathreads whose titles mention the query,
+// most commented first
+func selectMegathreads(candidates []models.SearchResult, keywords []string, now time.Time) []models.SearchResult {
+	seen := make(map[string]bool)
+	var threads []models.SearchResult
+	for _, candidate := range candidates {
+		if seen[candidate.ID] || !isMegathread(candidate) ||
+			now.Sub(time.Unix(candidate.CreatedUTC, 0)) > megathreadMaxAge ||
+			!containsAny(candidate.Title, keywords) {
+			continue
+		}
+		seen[candidate.ID] = true
+		threads = append(threads, candidate)
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].CommentCount > threads[j].CommentCount
+	})
+	if len(threads) > maxMegathreads {
+		threads = threads[:maxMegathreads]
+	}
+	return threads
+}
+
+// megathreadComments converts a megathread's most-upvoted top-level
+// comments into results, skipping bots, pinned moderator notices and
+// removed or very short comments
+func megathreadComments(thread *models.CommentThread, keywords []string) []models.SearchResult {
+	var comments []models.Comment
+	for _, comment := range thread.Comments {
+		body := strings.TrimSpace(comment.Body)
+		if comment.Stickied || comment.Distinguished != "" || comment.Author == "AutoModerator" ||
+			body == "[removed]" || body == "[deleted]" || len(body) < megathreadMinCommentChars {
+			continue
+		}
+		comments = append(comments, comment)
+	}
+
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Score > comments[j].Score
+	})
+	if len(comments) > megathreadCommentsPerPost {
+		comments = comments[:megathreadCommentsPerPost]
+	}
+
+	results := make([]models.SearchResult, 0, len(comments))
+	for _, comment := range comments {
+		results = append(results, models.SearchResult{
+			ID:         comment.ID,
+			Title:      "Comment in megathread: " + thread.Post.Title,
+			Subreddit:  thread.Post.Subreddit,
+			Author:     comment.Author,
+			Content:    comment.Body,
+			URL:        comment.URL,
+			Permalink:  comment.URL,
+			CreatedUTC: comment.CreatedUTC,
+			Score:      comment.Score,
+			Type:       "comment",
+			Highlights: extractHighlights(comment.Body, keywords),
+			ParentID:   comment.ParentID,
+			LinkID:     "t3_" + thread.Post.ID,
+			Depth:      comment.Depth,
+			Megathread: true,
+		})
+	}
+	return results
+}
+
+// withPrimarySources puts sources ahead of ranked results, dropping
+// duplicates and the lowest-ranked results beyond limit. Sources take at
+// most half of the results so regular search still contributes, and rank as
+// relevant as the best result.
+func withPrimarySources(sources, ranked []models.SearchResult, limit int) []models.SearchResult {
+	if len(sources) == 0 {
+		return ranked
+	}
+	if maxSources := (limit + 1) / 2; len(sources) > maxSources {
+		sources = sources[:maxSources]
+	}
+
+	merged := make([]models.SearchResult, 0, limit)
+	seen := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		seen[source.ID] = true
+		source.Relevance = 1
+		merged = append(merged, source)
+	}
+	for _, result := range ranked {
+		if len(merged) >= limit {
+			break
+		}
+		if !seen[result.ID] {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+// subjectKeywords lowercases keywords, drops temporal words and keeps the
+// first few
+func subjectKeywords(keywords []string) []string {
+	var subject []string
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(keyword); !temporalKeywords[keyword] {
+			subject = append(subject, keyword)
+		}
+		if len(subject) == 3 {
+			break
+		}
+	}
+	return subject
+}
@@ -0,0 +1,72 @@
+// File: backend/internal/services/reddit_megathreads_test.go
+
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestSelectMegathreads(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	recent := now.Add(-time.Hour).Unix()
+	candidates := []models.SearchResult{
+		{ID: "a", Type: "post", Title: "Hurricane Milton Megathread", CommentCount: 900, CreatedUTC: recent},
+		{ID: "b", Type: "post", Title: "LIVE THREAD: Hurricane Milton makes landfall", CommentCount: 4000, CreatedUTC: recent},
+		{ID: "a", Type: "post", Title: "Hurricane Milton Megathread", CommentCount: 900, CreatedUTC: recent},
+		{ID: "c", Type: "post", Title: "Hurricane Milton damage photos", CommentCount: 5000, CreatedUTC: recent},
+		{ID: "d", Type: "post", Title: "Election megathread", CommentCount: 9000, CreatedUTC: recent},
+		{ID: "e", Type: "post", Title: "Hurricane Ian megathread", CommentCount: 9000, CreatedUTC: now.Add(-30 * 24 * time.Hour).Unix()},
+		{ID: "f", Type: "post", Title: "Hurricane discussion thread", CommentCount: 10, CreatedUTC: recent},
+	}
+
+	threads := selectMegathreads(candidates, subjectKeywords([]string{"latest", "Hurricane", "Milton"}), now)
+	if len(threads) != 2 || threads[0].ID != "b" || threads[1].ID != "a" {
+		t.Errorf("Expected threads b and a, most commented first, got %+v", threads)
+	}
+}
+
+func TestMegathreadComments(t *testing.T) {
+	long := "The storm surge reached twelve feet in Siesta Key according to the county."
+	thread := &models.CommentThread{
+		Post: models.SearchResult{ID: "b", Title: "Live thread: Hurricane Milton", Subreddit: "news"},
+		Comments: []models.Comment{
+			{ID: "mod", Author: "news_mod", Body: long, Score: 9000, Distinguished: "moderator", Stickied: true},
+			{ID: "bot", Author: "AutoModerator", Body: long, Score: 5000},
+			{ID: "low", Author: "u1", Body: long, Score: 10},
+			{ID: "top", Author: "u2", Body: long, Score: 800, URL: "https://www.reddit.com/r/news/comments/b/_/top"},
+			{ID: "short", Author: "u3", Body: "Stay safe!", Score: 3000},
+			{ID: "removed", Author: "[deleted]", Body: "[removed]", Score: 2000},
+		},
+	}
+
+	results := megathreadComments(thread, []string{"storm"})
+	if len(results) != 2 || results[0].ID != "top" || results[1].ID != "low" {
+		t.Fatalf("Expected comments top and low, got %+v", results)
+	}
+	if top := results[0]; top.Type != "comment" || !top.Megathread || top.Subreddit != "news" ||
+		top.Title != "Comment in megathread: Live thread: Hurricane Milton" || len(top.Highlights) == 0 {
+		t.Errorf("Unexpected megathread result: %+v", top)
+	}
+}
+
+func TestWithPrimarySources(t *testing.T) {
+	sources := []models.SearchResult{{ID: "s1"}, {ID: "s2"}, {ID: "s3"}, {ID: "r1"}}
+	ranked := []models.SearchResult{{ID: "r1"}, {ID: "r2"}, {ID: "r3"}, {ID: "r4"}}
+
+	merged := withPrimarySources(sources, ranked, 5)
+	var ids []string
+	for _, result := range merged {
+		ids = append(ids, result.ID)
+	}
+	if got := strings.Join(ids, ","); got != "s1,s2,s3,r1,r2" {
+		t.Errorf("Expected sources first, capped at half, got %s", got)
+	}
+
+	if merged := withPrimarySources(nil, ranked, 5); len(merged) != len(ranked) {
+		t.Errorf("Expected ranked results unchanged without sources, got %d", len(merged))
+	}
+}
@@ -0,0 +1,183 @@
+// File: backend/internal/services/reddit_morechildren.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// Bounds on following "more" stubs, which on long threads can hide
+// thousands of comments
+const (
+	maxMoreChildrenCalls = 3   // morechildren requests per thread
+	maxMoreChildrenIDs   = 100 // Comment IDs per request, Reddit's limit
+)
+
+// expandMoreComments loads comments left out of a thread by following its
+// "more" stubs with api/morechildren, top-level stubs first. It stops after
+// maxMoreChildrenCalls requests; stubs it didn't reach are left in place.
+func (s *RedditService) expandMoreComments(ctx context.Context, thread *models.CommentThread, sort string) error {
+	linkID := "t3_" + thread.Post.ID
+	for calls := 0; calls < maxMoreChildrenCalls; calls++ {
+		stub := nextMoreStub(thread)
+		if stub == nil {
+			return nil
+		}
+
+		ids := stub.Children
+		if len(ids) > maxMoreChildrenIDs {
+			ids = ids[:maxMoreChildrenIDs]
+		}
+		things, err := s.moreChildren(ctx, linkID, ids, sort)
+		if err != nil {
+			return err
+		}
+
+		// Drop the loaded IDs from the stub before attaching, which may add
+		// to the same stub
+		stub.Children = stub.Children[len(ids):]
+		stub.Count -= len(ids)
+		if len(stub.Children) == 0 || stub.Count < len(stub.Children) {
+			stub.Count = len(stub.Children)
+		}
+		attachMoreChildren(thread, things)
+	}
+	return nil
+}
+
+// moreChildren fetches comments by ID. Reddit returns them flat, each with
+// its parent_id, in tree order.
+func (s *RedditService) moreChildren(ctx context.Context, linkID string, ids []string, sort string) ([]redditThing, error) {
+	queryParams := url.Values{}
+	queryParams.Set("api_type", "json")
+	queryParams.Set("link_id", linkID)
+	queryParams.Set("children", strings.Join(ids, ","))
+	queryParams.Set("limit_children", "false")
+	queryParams.Set("raw_json", "1")
+	if sort != "" {
+		queryParams.Set("sort", sort)
+	}
+
+	body, err := s.executeRequest(ctx, "/api/morechildren.json?"+queryParams.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		JSON struct {
+			Errors [][]interface{} `json:"errors"`
+			Data   struct {
+				Things []redditThing `json:"things"`
+			} `json:"data"`
+		} `json:"json"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error parsing morechildren response: %w", err)
+	}
+	if len(response.JSON.Errors) > 0 {
+		return nil, fmt.Errorf("morechildren failed: %v", response.JSON.Errors[0])
+	}
+	return response.JSON.Data.Things, nil
+}
+
+// nextMoreStub returns the shallowest stub that lists comments to load.
+// Stubs without children continue past the depth limit and can't be loaded
+// this way.
+func nextMoreStub(thread *models.CommentThread) *models.MoreComments {
+	if thread.More != nil && len(thread.More.Children) > 0 {
+		return thread.More
+	}
+
+	level := make([]*models.Comment, 0, len(thread.Comments))
+	for i := range thread.Comments {
+		level = append(level, &thread.Comments[i])
+	}
+	for len(level) > 0 {
+		var next []*models.Comment
+		for _, comment := range level {
+			if comment.More != nil && len(comment.More.Children) > 0 {
+				return comment.More
+			}
+			for i := range comment.Replies {
+				next = append(next, &comment.Replies[i])
+			}
+		}
+		level = next
+	}
+	return nil
+}
+
+// attachMoreChildren adds morechildren results to a thread under their
+// parents. Comments whose parent isn't in the thread are dropped.
+func attachMoreChildren(thread *models.CommentThread, things []redditThing) {
+	replies := make(map[string][]models.Comment)
+	stubs := make(map[string]*models.MoreComments)
+	for _, thing := range things {
+		switch thing.Kind {
+		case "t1":
+			comment, err := parseComment(thing.Data)
+			if err != nil {
+				log.Printf("Error parsing comment: %v", err)
+				continue
+			}
+			replies[comment.ParentID] = append(replies[comment.ParentID], comment)
+		case "more":
+			stub, err := parseMoreComments(thing.Data)
+			if err != nil || stub.Count == 0 {
+				continue
+			}
+			stubs[stub.ParentID] = mergeMoreStubs(stubs[stub.ParentID], stub)
+		}
+	}
+
+	postID := "t3_" + thread.Post.ID
+	thread.Comments = append(thread.Comments, replies[postID]...)
+	thread.More = mergeMoreStubs(thread.More, stubs[postID])
+	for i := range thread.Comments {
+		nestReplies(&thread.Comments[i], replies, stubs)
+	}
+	if thread.More != nil && thread.More.Count == 0 && len(thread.More.Children) == 0 {
+		thread.More = nil
+	}
+}
+
+// nestReplies attaches the new replies and stubs for a comment, then does
+// the same for its replies, so new comments nest under each other too. Each
+// parent's replies are attached once.
+func nestReplies(comment *models.Comment, replies map[string][]models.Comment, stubs map[string]*models.MoreComments) {
+	fullname := "t1_" + comment.ID
+	if children, ok := replies[fullname]; ok {
+		comment.Replies = append(comment.Replies, children...)
+		delete(replies, fullname)
+	}
+	if stub, ok := stubs[fullname]; ok {
+		comment.More = mergeMoreStubs(comment.More, stub)
+		delete(stubs, fullname)
+	}
+	if comment.More != nil && comment.More.Count == 0 && len(comment.More.Children) == 0 {
+		comment.More = nil
+	}
+	for i := range comment.Replies {
+		nestReplies(&comment.Replies[i], replies, stubs)
+	}
+}
+
+// mergeMoreStubs combines two stubs for the same parent
+func mergeMoreStubs(existing, stub *models.MoreComments) *models.MoreComments {
+	if existing == nil {
+		return stub
+	}
+	if stub == nil {
+		return existing
+	}
+	existing.Count += stub.Count
+	existing.Children = append(existing.Children, stub.Children...)
+	return existing
+}
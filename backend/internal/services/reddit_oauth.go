@@ -0,0 +1,123 @@
+// File: backend/internal/services/reddit_oauth.go
+
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redditAuthorizeURL = "https://www.reddit.com/api/v1/authorize"
+
+// RedditUserScopes are the permissions signing in with Reddit asks for:
+// the username, subscriptions and multireddits, saved posts, and reading
+// listings on the user's behalf
+var RedditUserScopes = []string{"identity", "mysubreddits", "history", "read"}
+
+// RedditToken is a Reddit user's OAuth token
+type RedditToken struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// NeedsRefresh reports whether the access token expires within the buffer
+// RedditAuth keeps for its own token
+func (t *RedditToken) NeedsRefresh(now time.Time) bool {
+	return !now.Add(tokenExpiryBuffer).Before(t.Expiry)
+}
+
+// AuthorizeURL is the Reddit page that asks the user to let this app act on
+// their behalf, then redirects to redirectURI with a code and state. Tokens
+// are permanent so they can be refreshed without asking again.
+func (r *RedditAuth) AuthorizeURL(state, redirectURI string) string {
+	query := url.Values{}
+	query.Set("client_id", r.clientID)
+	query.Set("response_type", "code")
+	query.Set("state", state)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("duration", "permanent")
+	query.Set("scope", strings.Join(RedditUserScopes, " "))
+	return redditAuthorizeURL + "?" + query.Encode()
+}
+
+// ExchangeCode trades the code Reddit redirected back with for the user's
+// token. redirectURI must match the one passed to AuthorizeURL.
+func (r *RedditAuth) ExchangeCode(ctx context.Context, code, redirectURI string) (*RedditToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	return r.userTokenRequest(ctx, form, "")
+}
+
+// RefreshUserToken gets a new access token for a user. Reddit keeps the
+// refresh token, so the returned token carries the old one.
+func (r *RedditAuth) RefreshUserToken(ctx context.Context, token *RedditToken) (*RedditToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", token.RefreshToken)
+	return r.userTokenRequest(ctx, form, token.RefreshToken)
+}
+
+// userTokenRequest posts a user grant to the token endpoint. A refused
+// grant, e.g. because the user revoked access, wraps ErrRedditLoginRequired.
+func (r *RedditAuth) userTokenRequest(ctx context.Context, form url.Values, refreshToken string) (*RedditToken, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", redditTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("User-Agent", r.userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(r.clientID+":"+r.clientSecret)))
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error making token request: %w", ErrRedditUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return nil, fmt.Errorf("error reading token response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, ErrRedditRateLimited
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: token request failed with status %d", ErrRedditUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: token request failed with status %d: %s", ErrRedditLoginRequired, resp.StatusCode, body)
+	}
+
+	// Reddit reports refused grants with a 200 and an error field
+	var tokenResponse struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Error        string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return nil, fmt.Errorf("error parsing token response: %w", err)
+	}
+	if tokenResponse.Error != "" || tokenResponse.AccessToken == "" {
+		return nil, fmt.Errorf("%w: reddit auth error: %s", ErrRedditLoginRequired, tokenResponse.Error)
+	}
+
+	token := &RedditToken{
+		AccessToken:  tokenResponse.AccessToken,
+		RefreshToken: tokenResponse.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(tokenResponse.ExpiresIn) * time.Second),
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
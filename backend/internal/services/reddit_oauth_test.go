@@ -0,0 +1,83 @@
+// File: backend/internal/services/reddit_oauth_test.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roundTripFunc answers HTTP requests without a network
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRedditUserTokens(t *testing.T) {
+	var form url.Values
+	response := `{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`
+	client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if user, pass, ok := req.BasicAuth(); !ok || user != "id" || pass != "secret" {
+			t.Errorf("Expected the app's credentials, got %q:%q", user, pass)
+		}
+		body, _ := io.ReadAll(req.Body)
+		form, _ = url.ParseQuery(string(body))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(response))}, nil
+	})}
+	redditAuth := NewRedditAuth("id", "secret", "test-agent", client)
+
+	authorize, _ := url.Parse(redditAuth.AuthorizeURL("state-1", "https://example.com/callback"))
+	query := authorize.Query()
+	if query.Get("state") != "state-1" || query.Get("duration") != "permanent" || query.Get("scope") != "identity mysubreddits history read" {
+		t.Errorf("Unexpected authorize URL %s", authorize)
+	}
+
+	token, err := redditAuth.ExchangeCode(context.Background(), "code-1", "https://example.com/callback")
+	if err != nil {
+		t.Fatalf("ExchangeCode failed: %v", err)
+	}
+	if form.Get("grant_type") != "authorization_code" || form.Get("code") != "code-1" {
+		t.Errorf("Unexpected exchange form %v", form)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.NeedsRefresh(time.Now()) {
+		t.Errorf("Unexpected token %+v", token)
+	}
+	if !token.NeedsRefresh(time.Now().Add(58 * time.Minute)) {
+		t.Error("Expected the token to need refreshing shortly before it expires")
+	}
+
+	// Refreshes keep the refresh token Reddit leaves out
+	response = `{"access_token":"access-2","expires_in":3600}`
+	refreshed, err := redditAuth.RefreshUserToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("RefreshUserToken failed: %v", err)
+	}
+	if !reflect.DeepEqual([]string{form.Get("grant_type"), form.Get("refresh_token")}, []string{"refresh_token", "refresh-1"}) {
+		t.Errorf("Unexpected refresh form %v", form)
+	}
+	if refreshed.AccessToken != "access-2" || refreshed.RefreshToken != "refresh-1" {
+		t.Errorf("Unexpected refreshed token %+v", refreshed)
+	}
+
+	// Revoked grants need the user to sign in again
+	response = `{"error":"invalid_grant"}`
+	if _, err := redditAuth.RefreshUserToken(context.Background(), token); !errors.Is(err, ErrRedditLoginRequired) {
+		t.Errorf("Expected ErrRedditLoginRequired, got %v", err)
+	}
+}
+
+func TestRankSubreddits(t *testing.T) {
+	got := rankSubreddits([]string{"pics", "golang", "AskReddit", "GoLangJobs"}, []string{"golang"}, 3)
+	want := []string{"golang", "GoLangJobs", "pics"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankSubreddits() = %v, want %v", got, want)
+	}
+}
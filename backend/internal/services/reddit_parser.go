@@ -3,9 +3,11 @@
 package services
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/pranesh-j/subplexity/internal/models"
@@ -64,6 +66,7 @@ func parseRedditResponse(rawResponse []byte) ([]models.SearchResult, error) {
 			// Skip unknown types
 			continue
 		}
+		result.Raw = capRawJSON(child.Data)
 
 		// Only add valid results that have at least an ID and title
 		if result.ID != "" && result.Title != "" {
@@ -74,6 +77,55 @@ func parseRedditResponse(rawResponse []byte) ([]models.SearchResult, error) {
 	return results, nil
 }
 
+// maxRawResultBytes caps the Reddit JSON kept with each result. Posts with
+// large media metadata or HTML bodies can run to hundreds of kilobytes.
+const maxRawResultBytes = 16 << 10
+
+// capRawJSON compacts a result's Reddit JSON and, when it is larger than
+// maxRawResultBytes, drops its largest fields until it fits. Dropped fields
+// are listed under "_omitted" so the JSON stays valid and the cut visible.
+func capRawJSON(data json.RawMessage) json.RawMessage {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil
+	}
+	if compact.Len() <= maxRawResultBytes {
+		return compact.Bytes()
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(compact.Bytes(), &fields); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	size := 2 // The enclosing braces
+	for key, value := range fields {
+		keys = append(keys, key)
+		size += len(key) + len(value) + 4 // Quotes, colon and comma
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(fields[keys[i]]) > len(fields[keys[j]]) })
+
+	// Leave room for the list of omitted fields
+	var omitted []string
+	for _, key := range keys {
+		if size <= maxRawResultBytes-1024 {
+			break
+		}
+		size -= len(key) + len(fields[key]) + 4
+		omitted = append(omitted, key)
+		delete(fields, key)
+	}
+	sort.Strings(omitted)
+	list, _ := json.Marshal(omitted)
+	fields["_omitted"] = list
+
+	capped, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return capped
+}
+
 // parsePostData parses a post (t3) item
 func parsePostData(data []byte, result *models.SearchResult) error {
 	var post struct {
@@ -84,11 +136,22 @@ func parsePostData(data []byte, result *models.SearchResult) error {
 		Subreddit    string  `json:"subreddit"`
 		Score        int     `json:"score"`
 		NumComments  int     `json:"num_comments"`
+		UpvoteRatio  float64 `json:"upvote_ratio"`
 		CreatedUTC   float64 `json:"created_utc"`
+		Edited       json.RawMessage `json:"edited"`
 		Permalink    string  `json:"permalink"`
 		URL          string  `json:"url"`
 		Distinguished string  `json:"distinguished"`
 		Stickied     bool    `json:"stickied"`
+		Archived     bool    `json:"archived"`
+		Locked       bool    `json:"locked"`
+		RemovedByCategory string `json:"removed_by_category"`
+		LinkFlairText   string `json:"link_flair_text"`
+		AuthorFlairText string `json:"author_flair_text"`
+		Over18       bool    `json:"over_18"`
+		TotalAwards  int     `json:"total_awards_received"`
+		Gilded       int     `json:"gilded"`
+		IsSelf       bool    `json:"is_self"`
 	}
 
 	if err := json.Unmarshal(data, &post); err != nil {
@@ -102,35 +165,35 @@ func parsePostData(data []byte, result *models.SearchResult) error {
 	result.Subreddit = post.Subreddit
 	result.Score = post.Score
 	result.CommentCount = post.NumComments
+	result.UpvoteRatio = post.UpvoteRatio
 	result.CreatedUTC = int64(post.CreatedUTC)
+	result.EditedUTC = parseEdited(post.Edited)
+	result.NSFW = post.Over18
+	result.Awards = post.TotalAwards
+	result.Gilded = post.Gilded
+	result.Distinguished = post.Distinguished != ""
+	result.Stickied = post.Stickied
+	result.Archived = post.Archived
+	result.Locked = post.Locked
+	result.Flair = strings.TrimSpace(post.LinkFlairText)
+	result.AuthorFlair = strings.TrimSpace(post.AuthorFlairText)
+	if post.RemovedByCategory != "" || isRemovedText(post.Selftext) {
+		result.Removed = true
+		result.Content = ""
+	}
+	result.Media = parsePostMedia(data)
+	result.ThumbnailURL, result.PreviewURL = previewURLs(result.Media)
 
-	// Set URL (use permalink if available)
+	// Citations always link to the discussion; the linked article or media
+	// is kept separately
 	if post.Permalink != "" {
-		result.URL = "https://www.reddit.com" + post.Permalink
-	} else if post.URL != "" {
-		result.URL = post.URL
+		result.Permalink = "https://www.reddit.com" + post.Permalink
 	} else {
-		result.URL = fmt.Sprintf("https://www.reddit.com/r/%s/comments/%s", post.Subreddit, post.ID)
+		result.Permalink = fmt.Sprintf("https://www.reddit.com/r/%s/comments/%s", post.Subreddit, post.ID)
 	}
-
-	// Add additional context for special posts
-	if post.Distinguished != "" || post.Stickied {
-		// Add some metadata to help with relevance ranking
-		tags := []string{}
-		if post.Stickied {
-			tags = append(tags, "stickied")
-		}
-		if post.Distinguished != "" {
-			tags = append(tags, post.Distinguished)
-		}
-		
-		if len(tags) > 0 {
-			if result.Content != "" {
-				result.Content = fmt.Sprintf("[%s] %s", strings.Join(tags, ", "), result.Content)
-			} else {
-				result.Content = fmt.Sprintf("[%s]", strings.Join(tags, ", "))
-			}
-		}
+	result.URL = result.Permalink
+	if !post.IsSelf && strings.HasPrefix(post.URL, "http") && post.URL != result.Permalink {
+		result.ExternalURL = post.URL
 	}
 
 	return nil
@@ -145,10 +208,20 @@ func parseCommentData(data []byte, result *models.SearchResult) error {
 		Subreddit    string  `json:"subreddit"`
 		Score        int     `json:"score"`
 		CreatedUTC   float64 `json:"created_utc"`
+		Edited       json.RawMessage `json:"edited"`
 		Permalink    string  `json:"permalink"`
 		LinkID       string  `json:"link_id"`
+		ParentID     string  `json:"parent_id"`
+		Depth        int     `json:"depth"`
 		LinkTitle    string  `json:"link_title"`
 		Distinguished string  `json:"distinguished"`
+		Stickied     bool    `json:"stickied"`
+		Archived     bool    `json:"archived"`
+		Locked       bool    `json:"locked"`
+		TotalAwards  int     `json:"total_awards_received"`
+		AuthorFlairText string `json:"author_flair_text"`
+		Gilded       int     `json:"gilded"`
+		Over18       bool    `json:"over_18"` // Whether the post it's on is NSFW
 	}
 
 	if err := json.Unmarshal(data, &comment); err != nil {
@@ -161,6 +234,23 @@ func parseCommentData(data []byte, result *models.SearchResult) error {
 	result.Subreddit = comment.Subreddit
 	result.Score = comment.Score
 	result.CreatedUTC = int64(comment.CreatedUTC)
+	result.EditedUTC = parseEdited(comment.Edited)
+	result.Awards = comment.TotalAwards
+	result.Gilded = comment.Gilded
+	result.Distinguished = comment.Distinguished != ""
+	result.Stickied = comment.Stickied
+	result.Archived = comment.Archived
+	result.Locked = comment.Locked
+	result.NSFW = comment.Over18
+	result.AuthorFlair = strings.TrimSpace(comment.AuthorFlairText)
+	result.Removed = isRemovedText(comment.Body)
+	result.LinkID = comment.LinkID
+	result.ParentID = comment.ParentID
+	result.Depth = comment.Depth
+	// Search results and listings omit depth
+	if result.Depth == 0 && strings.HasPrefix(comment.ParentID, "t1_") {
+		result.Depth = 1
+	}
 
 	// Set title and URL
 	if comment.LinkTitle != "" {
@@ -170,13 +260,14 @@ func parseCommentData(data []byte, result *models.SearchResult) error {
 	}
 
 	if comment.Permalink != "" {
-		result.URL = "https://www.reddit.com" + comment.Permalink
+		result.Permalink = "https://www.reddit.com" + comment.Permalink
 	} else {
 		commentID := strings.TrimPrefix(comment.ID, "t1_")
 		parentID := strings.TrimPrefix(comment.LinkID, "t3_")
-		result.URL = fmt.Sprintf("https://www.reddit.com/r/%s/comments/%s/_/%s", 
+		result.Permalink = fmt.Sprintf("https://www.reddit.com/r/%s/comments/%s/_/%s", 
 			comment.Subreddit, parentID, commentID)
 	}
+	result.URL = result.Permalink
 
 	return nil
 }
@@ -213,9 +304,11 @@ func parseSubredditData(data []byte, result *models.SearchResult) error {
 	
 	result.Score = subreddit.Subscribers
 	result.CreatedUTC = int64(subreddit.CreatedUTC)
-	result.URL = fmt.Sprintf("https://www.reddit.com/r/%s", subreddit.DisplayName)
+	result.Permalink = fmt.Sprintf("https://www.reddit.com/r/%s", subreddit.DisplayName)
+	result.URL = result.Permalink
 
 	// Add NSFW tag to content if applicable
+	result.NSFW = subreddit.NSFW
 	if subreddit.NSFW {
 		result.Content = fmt.Sprintf("[NSFW] %s", result.Content)
 	}
@@ -235,4 +328,21 @@ func getTypeFromKind(kind string) string {
 	default:
 		return "unknown"
 	}
-}
\ No newline at end of file
+}
+
+// parseEdited parses Reddit's "edited" field, which is false for content
+// that was never edited and the Unix time of the last edit otherwise
+func parseEdited(raw json.RawMessage) int64 {
+	var editedUTC float64
+	if err := json.Unmarshal(raw, &editedUTC); err != nil {
+		return 0
+	}
+	return int64(editedUTC)
+}
+
+// isRemovedText reports whether text is the placeholder Reddit leaves in
+// place of removed or deleted content
+func isRemovedText(text string) bool {
+	text = strings.TrimSpace(text)
+	return text == "[removed]" || text == "[deleted]"
+}
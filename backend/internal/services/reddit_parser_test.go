@@ -0,0 +1,83 @@
+// File: backend/internal/services/reddit_parser_test.go
+
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseRedditResponseStatus(t *testing.T) {
+	raw := []byte(`{"kind": "Listing", "data": {"children": [
+		{"kind": "t3", "data": {"id": "a", "title": "Rules", "selftext": "Be nice", "edited": false, "distinguished": "moderator", "stickied": true}},
+		{"kind": "t3", "data": {"id": "b", "title": "Old thread", "selftext": "Still useful", "archived": true, "locked": true, "upvote_ratio": 0.55, "created_utc": 1700000000.0, "edited": 1700003600.0, "link_flair_text": "Guide ", "author_flair_text": "Verified Engineer"}},
+		{"kind": "t3", "data": {"id": "c", "title": "Gone", "selftext": "[removed]", "removed_by_category": "moderator"}},
+		{"kind": "t1", "data": {"id": "d", "body": "[deleted]", "subreddit": "golang", "archived": true, "over_18": true}},
+		{"kind": "t3", "data": {"id": "e", "title": "Awarded", "selftext": "Thanks!", "total_awards_received": 7, "gilded": 2}},
+		{"kind": "t1", "data": {"id": "f", "body": "Gilded before awards existed", "subreddit": "golang", "gilded": 3}}
+	]}}`)
+
+	results, err := parseRedditResponse(raw)
+	if err != nil {
+		t.Fatalf("parseRedditResponse: %v", err)
+	}
+	if len(results) != 6 {
+		t.Fatalf("Expected 6 results, got %d", len(results))
+	}
+
+	if rules := results[0]; !rules.Distinguished || !rules.Stickied || rules.Content != "Be nice" || rules.EditedUTC != 0 {
+		t.Errorf("Expected a distinguished, stickied post with untagged content, got %+v", rules)
+	}
+	if old := results[1]; !old.Archived || !old.Locked || old.Removed || old.Flair != "Guide" || old.AuthorFlair != "Verified Engineer" || old.UpvoteRatio != 0.55 || old.EditedUTC != 1700003600 {
+		t.Errorf("Expected an archived, locked post with flair, got %+v", old)
+	}
+	if gone := results[2]; !gone.Removed || gone.Content != "" {
+		t.Errorf("Expected a removed post without content, got %+v", gone)
+	}
+	if deleted := results[3]; !deleted.Removed || !deleted.Archived || !deleted.NSFW {
+		t.Errorf("Expected a deleted, archived comment on an NSFW post, got %+v", deleted)
+	}
+
+	if awarded := results[4]; awarded.Awards != 7 || awarded.Gilded != 2 || awardCount(awarded) != 7 {
+		t.Errorf("Expected a post with 7 awards, 2 of them gildings, got %+v", awarded)
+	}
+	if gilded := results[5]; gilded.Awards != 0 || gilded.Gilded != 3 || awardCount(gilded) != 3 {
+		t.Errorf("Expected a comment counting its 3 gildings as awards, got %+v", gilded)
+	}
+
+	if kept := withoutRemoved(results); len(kept) != 4 {
+		t.Errorf("Expected withoutRemoved to keep 4 results, got %d", len(kept))
+	}
+}
+
+func TestParseRedditResponseRaw(t *testing.T) {
+	big := strings.Repeat("x", maxRawResultBytes)
+	raw := []byte(`{"kind": "Listing", "data": {"children": [
+		{"kind": "t3", "data": {"id": "a", "title": "Small", "score": 12}},
+		{"kind": "t3", "data": {"id": "b", "title": "Large", "selftext_html": "` + big + `", "media_metadata": {"m": "` + big + `"}}}
+	]}}`)
+
+	results, err := parseRedditResponse(raw)
+	if err != nil {
+		t.Fatalf("parseRedditResponse: %v", err)
+	}
+	if string(results[0].Raw) != `{"id":"a","title":"Small","score":12}` {
+		t.Errorf("Expected the compacted JSON, got %s", results[0].Raw)
+	}
+
+	var capped struct {
+		ID      string   `json:"id"`
+		Omitted []string `json:"_omitted"`
+	}
+	if err := json.Unmarshal(results[1].Raw, &capped); err != nil {
+		t.Fatalf("Expected capped JSON to stay valid: %v", err)
+	}
+	if len(results[1].Raw) > maxRawResultBytes || capped.ID != "b" || strings.Join(capped.Omitted, ",") != "media_metadata,selftext_html" {
+		t.Errorf("Expected the large fields dropped, got %d bytes omitting %v", len(results[1].Raw), capped.Omitted)
+	}
+
+	if stripped := withoutRaw(results); stripped[0].Raw != nil || results[0].Raw == nil {
+		t.Error("Expected withoutRaw to strip a copy of the results")
+	}
+}
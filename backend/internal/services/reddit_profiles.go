@@ -0,0 +1,88 @@
+// File: backend/internal/services/reddit_profiles.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// GetSubreddit returns a community's details as a subreddit result
+func (s *RedditService) GetSubreddit(ctx context.Context, name string) (*models.SearchResult, error) {
+	body, err := s.executeRequest(ctx, fmt.Sprintf("/r/%s/about.json?raw_json=1", url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Reddit answers unknown names with a search listing rather than a t5
+	var thing redditThing
+	if err := json.Unmarshal(body, &thing); err != nil {
+		return nil, fmt.Errorf("error parsing subreddit: %w", err)
+	}
+	if thing.Kind != "t5" {
+		return nil, fmt.Errorf("subreddit r/%s not found", name)
+	}
+
+	result := models.SearchResult{Type: "subreddit"}
+	if err := parseSubredditData(thing.Data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetUser returns a user's public profile
+func (s *RedditService) GetUser(ctx context.Context, name string) (*models.RedditUser, error) {
+	body, err := s.executeRequest(ctx, fmt.Sprintf("/user/%s/about.json?raw_json=1", url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	var about struct {
+		Kind string `json:"kind"`
+		Data struct {
+			Name             string  `json:"name"`
+			CreatedUTC       float64 `json:"created_utc"`
+			LinkKarma        int     `json:"link_karma"`
+			CommentKarma     int     `json:"comment_karma"`
+			HasVerifiedEmail bool    `json:"has_verified_email"`
+			IsMod            bool    `json:"is_mod"`
+			IsSuspended      bool    `json:"is_suspended"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &about); err != nil {
+		return nil, fmt.Errorf("error parsing user: %w", err)
+	}
+	if about.Kind != "t2" {
+		return nil, fmt.Errorf("user u/%s not found", name)
+	}
+
+	return &models.RedditUser{
+		Name:         about.Data.Name,
+		CreatedUTC:   int64(about.Data.CreatedUTC),
+		LinkKarma:    about.Data.LinkKarma,
+		CommentKarma: about.Data.CommentKarma,
+		Verified:     about.Data.HasVerifiedEmail,
+		IsModerator:  about.Data.IsMod,
+		Suspended:    about.Data.IsSuspended,
+	}, nil
+}
+
+// FetchUserListing returns a user's newest posts ("submitted") or comments
+// ("comments")
+func (s *RedditService) FetchUserListing(ctx context.Context, name, listing string, limit int) ([]models.SearchResult, error) {
+	if limit <= 0 || limit > maxRequestLimit {
+		limit = maxRequestLimit
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("limit", fmt.Sprintf("%d", limit))
+	queryParams.Set("sort", "new")
+	queryParams.Set("raw_json", "1")
+
+	endpoint := fmt.Sprintf("/user/%s/%s.json?%s", url.PathEscape(name), listing, queryParams.Encode())
+	return s.executeSearchRequest(ctx, endpoint)
+}
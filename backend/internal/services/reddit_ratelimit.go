@@ -0,0 +1,46 @@
+// File: backend/internal/services/reddit_ratelimit.go
+
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// rateLimitBudget remembers the request budget Reddit last reported in its
+// X-Ratelimit-* response headers. The zero value is ready to use.
+type rateLimitBudget struct {
+	mu     sync.Mutex
+	budget models.RateLimitBudget
+}
+
+// observe records the budget reported by a response's headers, if any
+func (b *rateLimitBudget) observe(header http.Header) {
+	remaining, err := strconv.ParseFloat(header.Get("X-Ratelimit-Remaining"), 64)
+	if err != nil {
+		return
+	}
+	used, _ := strconv.Atoi(header.Get("X-Ratelimit-Used"))
+	reset, _ := strconv.ParseFloat(header.Get("X-Ratelimit-Reset"), 64)
+
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.budget = models.RateLimitBudget{
+		Remaining:  remaining,
+		Used:       used,
+		ResetAt:    now.Add(time.Duration(reset * float64(time.Second))).Unix(),
+		ObservedAt: now.Unix(),
+	}
+}
+
+// status returns the last reported budget
+func (b *rateLimitBudget) status() models.RateLimitBudget {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.budget
+}
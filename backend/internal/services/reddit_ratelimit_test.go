@@ -0,0 +1,31 @@
+package services
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimitBudget(t *testing.T) {
+	var budget rateLimitBudget
+
+	// Responses without the headers leave the budget unknown
+	budget.observe(http.Header{})
+	if got := budget.status(); got.ObservedAt != 0 {
+		t.Errorf("budget without headers = %+v, want zero", got)
+	}
+
+	header := http.Header{}
+	header.Set("X-Ratelimit-Remaining", "595.0")
+	header.Set("X-Ratelimit-Used", "5")
+	header.Set("X-Ratelimit-Reset", "120")
+	budget.observe(header)
+
+	got := budget.status()
+	if got.Remaining != 595 || got.Used != 5 {
+		t.Errorf("budget = %+v, want 595 remaining and 5 used", got)
+	}
+	if reset := got.ResetAt - time.Now().Unix(); reset < 118 || reset > 120 {
+		t.Errorf("budget resets in %ds, want about 120", reset)
+	}
+}
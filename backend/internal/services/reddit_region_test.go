@@ -0,0 +1,57 @@
+// File: backend/internal/services/reddit_region_test.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+func TestSearchRegion(t *testing.T) {
+	reddit, mock := newMockRedditService(t)
+	reddit.SetRegions(config.RegionsConfig{Subreddits: map[string][]string{"uk": {"unitedkingdom"}}})
+	mock.AddPosts(
+		redditmock.Post{ID: "isp1", Subreddit: "technology", Title: "ISP recommendations for fiber", Author: "techie", Score: 800, NumComments: 120},
+		redditmock.Post{ID: "isp2", Subreddit: "unitedkingdom", Title: "ISP recommendations? Virgin vs BT", Author: "brit", Score: 150, NumComments: 60},
+	)
+	pipeline := NewSearchPipeline(reddit, nil)
+
+	req := models.SearchRequest{Query: "isp recommendations", SearchMode: "Posts", SkipAI: true}
+	response, err := pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 2 || response.Results[0].ID != "isp1" {
+		t.Fatalf("Expected the more popular post first without a region, got %+v", response.Results)
+	}
+
+	req.Region = "UK"
+	response, err = pipeline.Run(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Results) != 2 || response.Results[0].ID != "isp2" {
+		t.Errorf("Expected the r/unitedkingdom post first for the uk region, got %+v", response.Results)
+	}
+	var searched bool
+	for _, r := range mock.Requests() {
+		if r.Path == "/search.json" && strings.Contains(r.Query.Get("q"), "subreddit:unitedkingdom") {
+			searched = true
+		}
+	}
+	if !searched {
+		t.Errorf("Expected a search of the region's communities, got %+v", mock.Requests())
+	}
+
+	req.Region = "atlantis"
+	var invalid *ValidationError
+	if err := pipeline.Validate(&req); !errors.As(err, &invalid) || invalid.Fields[0].Field != "region" || !strings.Contains(err.Error(), "(expected uk)") {
+		t.Errorf("Expected an unknown region error, got %v", err)
+	}
+}
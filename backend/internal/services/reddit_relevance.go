@@ -7,18 +7,37 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/pranesh-j/subplexity/internal/config"
 	"github.com/pranesh-j/subplexity/internal/models"
 	"github.com/pranesh-j/subplexity/internal/utils"
 )
 
+// controversialUpvoteRatio is the upvote ratio below which a post counts as
+// controversial
+const controversialUpvoteRatio = 0.6
+
+// lowRecencyWeight and highRecencyWeight scale the preference for newer
+// results under the "low" and "high" recency biases
+const (
+	lowRecencyWeight  = 0.5
+	highRecencyWeight = 2.0
+)
+
+// regionalRelevanceBoost favors results from the communities of the
+// requested region, whose members answer local questions best
+const regionalRelevanceBoost = 80.0
+
 // Define scoredResult type for use in relevance ranking
 type scoredResult struct {
 	result models.SearchResult
 	score  float64
 }
 
-// extractHighlights extracts key excerpts from content that match keywords
+// extractHighlights extracts key excerpts from content that match keywords,
+// best first
 func extractHighlights(content string, keywords []string) []string {
 	if content == "" || len(keywords) == 0 {
 		return nil
@@ -39,7 +58,7 @@ func extractHighlights(content string, keywords []string) []string {
 	var scoredSentences []scoredSentence
 	
 	for _, sentence := range sentences {
-		if len(strings.TrimSpace(sentence)) < 10 {
+		if utf8.RuneCountInString(strings.TrimSpace(sentence)) < 10 {
 			continue // Skip very short sentences
 		}
 		
@@ -71,8 +90,9 @@ func extractHighlights(content string, keywords []string) []string {
 		return scoredSentences[i].score > scoredSentences[j].score
 	})
 	
-	// Take up to 3 highest scoring sentences as highlights
-	maxHighlights := 3
+	// Take the highest scoring sentences as highlights, as many as a request
+	// may ask for; the pipeline keeps the number requested
+	maxHighlights := config.MaxResultHighlights
 	if len(scoredSentences) < maxHighlights {
 		maxHighlights = len(scoredSentences)
 	}
@@ -84,17 +104,7 @@ func extractHighlights(content string, keywords []string) []string {
 		
 		// Truncate if too long
 		const maxHighlightLength = 200
-		if len(highlight) > maxHighlightLength {
-			// Find a good breakpoint
-			breakPoint := maxHighlightLength
-			for j := maxHighlightLength - 1; j >= maxHighlightLength-30; j-- {
-				if j < len(highlight) && (highlight[j] == ' ' || highlight[j] == ',' || highlight[j] == '.') {
-					breakPoint = j
-					break
-				}
-			}
-			highlight = highlight[:breakPoint] + "..."
-		}
+		highlight = utils.TruncateWithEllipsis(highlight, maxHighlightLength)
 		
 		highlights = append(highlights, highlight)
 	}
@@ -118,30 +128,40 @@ func splitIntoSentences(text string) []string {
 		text = strings.ReplaceAll(text, abbr, strings.ReplaceAll(abbr, ".", "##PD##"))
 	}
 	
-	// Use regex-free approach for better performance
+	// Use regex-free approach for better performance. Work on runes so
+	// capitals and punctuation outside ASCII are recognized.
 	var sentences []string
 	var currentSentence strings.Builder
+	runes := []rune(text)
 	
-	for i := 0; i < len(text); i++ {
-		currentSentence.WriteByte(text[i])
+	for i, r := range runes {
+		currentSentence.WriteRune(r)
+		
+		// Full-width CJK punctuation always ends a sentence; no space follows it
+		if r == '。' || r == '！' || r == '？' {
+			sentences = append(sentences, currentSentence.String())
+			currentSentence.Reset()
+			continue
+		}
 		
 		// Check for sentence-ending punctuation
-		if text[i] == '.' || text[i] == '!' || text[i] == '?' {
+		if r == '.' || r == '!' || r == '?' {
 			// Look ahead to see if this is really the end of a sentence
 			isEndOfSentence := false
 			
 			// Check if we're at the end of text
-			if i == len(text)-1 {
+			if i == len(runes)-1 {
 				isEndOfSentence = true
 			} else {
 				// Check if followed by space and capital letter
-				for j := i + 1; j < len(text); j++ {
-					if text[j] == ' ' || text[j] == '\t' || text[j] == '\n' {
+				for j := i + 1; j < len(runes); j++ {
+					if unicode.IsSpace(runes[j]) {
 						continue
 					}
 					
-					// If next non-whitespace char is capital, it's a new sentence
-					if j < len(text) && text[j] >= 'A' && text[j] <= 'Z' {
+					// If next non-whitespace char is capital, or a letter from a
+					// script without case such as CJK, it's a new sentence
+					if unicode.IsUpper(runes[j]) || (unicode.IsLetter(runes[j]) && !unicode.IsLower(runes[j])) {
 						isEndOfSentence = true
 					}
 					
@@ -149,7 +169,7 @@ func splitIntoSentences(text string) []string {
 				}
 				
 				// Also end sentence if followed by multiple newlines
-				if i+2 < len(text) && text[i+1] == '\n' && text[i+2] == '\n' {
+				if i+2 < len(runes) && runes[i+1] == '\n' && runes[i+2] == '\n' {
 					isEndOfSentence = true
 				}
 			}
@@ -176,6 +196,7 @@ func splitIntoSentences(text string) []string {
 
 // processSearchResults processes and ranks search results
 func (s *RedditService) processSearchResults(params utils.QueryParams, results []models.SearchResult, limit int) []models.SearchResult {
+	results = withoutAuthors(results, params.ExcludeAuthors)
 	if len(results) == 0 {
 		return results
 	}
@@ -209,10 +230,12 @@ func (s *RedditService) processSearchResults(params utils.QueryParams, results [
 	}
 	
 	// Extract highlights for final results
+	bestScore := scoredResults[0].score
 	finalResults := make([]models.SearchResult, len(diversifiedResults))
 	for i, sr := range diversifiedResults {
 		// Extract highlights for each result
 		sr.result.Highlights = extractHighlights(sr.result.Content, params.Keywords)
+		sr.result.Relevance = normalizeRelevance(sr.score, bestScore)
 		
 		// Add to final results
 		finalResults[i] = sr.result
@@ -221,6 +244,27 @@ func (s *RedditService) processSearchResults(params utils.QueryParams, results [
 	return finalResults
 }
 
+// withoutAuthors drops the results by the given users, ignoring case
+func withoutAuthors(results []models.SearchResult, authors []string) []models.SearchResult {
+	if len(authors) == 0 {
+		return results
+	}
+	kept := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		excluded := false
+		for _, author := range authors {
+			if strings.EqualFold(result.Author, author) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
 // Completely revamp the calculateRelevanceScore function to be domain-agnostic
 func calculateRelevanceScore(result models.SearchResult, params utils.QueryParams) float64 {
     // Base score starts at 100
@@ -240,6 +284,12 @@ func calculateRelevanceScore(result models.SearchResult, params utils.QueryParam
         if params.Intent == utils.SubredditIntent {
             score += 100
         }
+    case "wiki":
+        // Wikis have no votes to rank by, but are curated by the community
+        // and usually the best source for evergreen questions
+        if !params.IsTimeSensitive {
+            score += 80
+        }
     }
     
     // 2. Keyword matching - completely query-dependent
@@ -270,41 +320,57 @@ func calculateRelevanceScore(result models.SearchResult, params utils.QueryParam
         score += keywordCoverage * 100 // Up to 100 points for complete coverage
     }
     
-    // 3. Temporal relevance - based on query time sensitivity
+    // 3. Temporal relevance - based on query time sensitivity, scaled by
+    // the requested recency bias
+    recency := recencyWeight(params.RecencyBias)
     if params.IsTimeSensitive {
         // Calculate age of the content
-        ageInSeconds := time.Now().Unix() - result.CreatedUTC
+        // Authors often update posts as news develops
+        ageInSeconds := time.Now().Unix() - lastUpdatedUTC(result)
         ageInDays := ageInSeconds / (60 * 60 * 24)
         
         // Apply temporal scoring based on timeframe
+        bonus := 0.0
         switch params.TimeFrame {
         case "day":
             if ageInDays < 1 {
-                score += 300 // Very recent
+                bonus = 300 // Very recent
             } else if ageInDays < 3 {
-                score += 150 // Recent
+                bonus = 150 // Recent
             } else if ageInDays < 7 {
-                score += 50 // Somewhat recent
+                bonus = 50 // Somewhat recent
             }
         case "week":
             if ageInDays < 7 {
-                score += 200 // Within a week
+                bonus = 200 // Within a week
             } else if ageInDays < 14 {
-                score += 100 // Within two weeks
+                bonus = 100 // Within two weeks
             } else if ageInDays < 30 {
-                score += 50 // Within a month
+                bonus = 50 // Within a month
             }
         case "month":
             if ageInDays < 30 {
-                score += 150 // Within a month
+                bonus = 150 // Within a month
             } else if ageInDays < 60 {
-                score += 75 // Within two months
+                bonus = 75 // Within two months
             }
         case "year":
             if ageInDays < 365 {
-                score += 100 // Within a year
+                bonus = 100 // Within a year
             }
         }
+        score += bonus * recency
+    } else if params.RecencyBias == models.RecencyBiasHigh {
+        // Evergreen queries only favor newer content when asked to
+        score += calculateAgeScore(result.CreatedUTC) * recency
+    }
+    
+    // Local communities know local prices, providers and rules
+    for _, sr := range params.RegionSubreddits {
+        if strings.EqualFold(result.Subreddit, sr) {
+            score += regionalRelevanceBoost
+            break
+        }
     }
     
     // 4. Engagement metrics - universal signals of content quality
@@ -316,6 +382,29 @@ func calculateRelevanceScore(result models.SearchResult, params utils.QueryParam
     if result.CommentCount > 0 {
         score += math.Log10(float64(result.CommentCount)+10) * 15
     }
+
+    // Controversial posts draw comments from arguments rather than agreement,
+    // so dampen them the more evenly votes are split
+    if result.UpvoteRatio > 0 && result.UpvoteRatio < controversialUpvoteRatio {
+        score -= (controversialUpvoteRatio - result.UpvoteRatio) * 250
+    }
+
+    // Awards are rarer than upvotes and often mark the best answer in a thread
+    if awards := awardCount(result); awards > 0 {
+        score += math.Log10(float64(awards)+1) * 25
+    }
+
+    // Pinned posts are mostly rules and recurring threads, and distinguished
+    // comments mostly removal notices, rather than answers. Moderator
+    // announcements are what time-sensitive queries about a community want.
+    if result.Stickied && !params.IsTimeSensitive {
+        score -= 60
+    }
+    if result.Distinguished && result.Type == "comment" {
+        score -= 60
+    } else if result.Distinguished && params.IsTimeSensitive {
+        score += 40
+    }
     
     // 5. Apply custom relevance factors from query analysis
     for factor, weight := range params.RelevanceFactors {
@@ -323,9 +412,12 @@ func calculateRelevanceScore(result models.SearchResult, params utils.QueryParam
         case "recency":
             // Already handled above, but could apply multiplier here
             ageScore := calculateAgeScore(result.CreatedUTC)
-            score += ageScore * weight
+            if params.IsTimeSensitive {
+                ageScore = calculateAgeScore(lastUpdatedUTC(result))
+            }
+            score += ageScore * weight * recency
         case "engagement":
-            engagementScore := calculateEngagementScore(result.Score, result.CommentCount)
+            engagementScore := calculateEngagementScore(result.Score, result.CommentCount, awardCount(result))
             score += engagementScore * weight
         }
     }
@@ -333,6 +425,38 @@ func calculateRelevanceScore(result models.SearchResult, params utils.QueryParam
     return score
 }
 
+// recencyWeight scales how much newer results are favored for a
+// models.SearchRequest recency bias
+func recencyWeight(bias string) float64 {
+    switch bias {
+    case models.RecencyBiasNone:
+        return 0
+    case models.RecencyBiasLow:
+        return lowRecencyWeight
+    case models.RecencyBiasHigh:
+        return highRecencyWeight
+    }
+    return 1
+}
+
+// normalizeRelevance scales a relevance score to 0-1 relative to the best
+// score, rounded so responses stay compact
+func normalizeRelevance(score, best float64) float64 {
+    if best <= 0 || score <= 0 {
+        return 0
+    }
+    return math.Round(math.Min(score/best, 1)*1000) / 1000
+}
+
+// lastUpdatedUTC returns when a result was last edited, or posted if it was
+// never edited
+func lastUpdatedUTC(result models.SearchResult) int64 {
+    if result.EditedUTC > result.CreatedUTC {
+        return result.EditedUTC
+    }
+    return result.CreatedUTC
+}
+
 // Helper functions
 func calculateAgeScore(createdUTC int64) float64 {
     ageInSeconds := time.Now().Unix() - createdUTC
@@ -345,9 +469,19 @@ func calculateAgeScore(createdUTC int64) float64 {
     return 100 / (1 + math.Log10(float64(ageInDays)))
 }
 
-func calculateEngagementScore(score int, commentCount int) float64 {
+func calculateEngagementScore(score int, commentCount int, awards int) float64 {
     // Combined engagement metric
-    return (math.Log10(float64(score)+10) * 2) + (math.Log10(float64(commentCount)+10) * 3)
+    return (math.Log10(float64(score)+10) * 2) + (math.Log10(float64(commentCount)+10) * 3) +
+        (math.Log10(float64(awards)+1) * 4)
+}
+
+// awardCount counts a result's awards. Older posts may only report gildings,
+// which newer ones also include in their award total.
+func awardCount(result models.SearchResult) int {
+    if result.Awards > result.Gilded {
+        return result.Awards
+    }
+    return result.Gilded
 }
 // Extract main identifying keywords from the query
 func extractMainKeywords(query string) []string {
@@ -421,7 +555,7 @@ func diversifyResults(scoredResults []scoredResult) []scoredResult {
 	}
 	
 	// Add one of each type first (if available and in top half)
-	for _, typ := range []string{"post", "comment", "subreddit"} {
+	for _, typ := range []string{"post", "comment", "subreddit", "wiki"} {
 		if typeCounts[typ] > 0 {
 			continue // Already have this type
 		}
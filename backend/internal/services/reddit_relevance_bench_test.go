@@ -0,0 +1,152 @@
+// File: backend/internal/services/reddit_relevance_bench_test.go
+
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// relevanceDir holds the golden set and its fixture Reddit responses
+const relevanceDir = "testdata/relevance"
+
+// relevanceGolden is the golden set of queries with judged results, see
+// testdata/relevance/golden.json
+type relevanceGolden struct {
+	// K is the rank cutoff NDCG is computed at
+	K int `json:"k"`
+	// FixtureTime is when the fixtures were captured. Their timestamps are
+	// moved forward to now, so recency scoring sees the same ages every run.
+	FixtureTime int64 `json:"fixtureTime"`
+	// MinMeanNDCG is the mean score ranking changes must not fall below.
+	// Raise it when a change improves ranking.
+	MinMeanNDCG float64 `json:"minMeanNdcg"`
+	Cases       []struct {
+		Name    string `json:"name"`
+		Query   string `json:"query"`
+		Fixture string `json:"fixture"`
+		// Judgments grade results by ID: 3 is a perfect answer, 2 relevant,
+		// 1 marginal; unjudged results count as 0
+		Judgments map[string]int `json:"judgments"`
+	} `json:"cases"`
+}
+
+// TestRelevanceBenchmark scores the ranking of the golden set with NDCG and
+// fails when the mean falls below the recorded minimum. Run it with -v to
+// see each query's score when tuning calculateRelevanceScore.
+func TestRelevanceBenchmark(t *testing.T) {
+	mean := evaluateRelevance(t)
+	t.Logf("mean NDCG: %.4f", mean)
+}
+
+// BenchmarkRelevance reports the golden set's mean NDCG alongside the time
+// ranking takes, for comparing changes with benchstat
+func BenchmarkRelevance(b *testing.B) {
+	var mean float64
+	for i := 0; i < b.N; i++ {
+		mean = evaluateRelevance(b)
+	}
+	b.ReportMetric(mean, "ndcg")
+}
+
+// evaluateRelevance ranks every case in the golden set and returns their
+// mean NDCG, failing tb if it is below the minimum
+func evaluateRelevance(tb testing.TB) float64 {
+	tb.Helper()
+
+	data, err := os.ReadFile(filepath.Join(relevanceDir, "golden.json"))
+	if err != nil {
+		tb.Fatalf("Failed to read golden set: %v", err)
+	}
+	var golden relevanceGolden
+	if err := json.Unmarshal(data, &golden); err != nil {
+		tb.Fatalf("Failed to parse golden set: %v", err)
+	}
+
+	shift := time.Now().Unix() - golden.FixtureTime
+	service := &RedditService{}
+	var total float64
+	for _, tc := range golden.Cases {
+		raw, err := os.ReadFile(filepath.Join(relevanceDir, tc.Fixture))
+		if err != nil {
+			tb.Fatalf("%s: failed to read fixture: %v", tc.Name, err)
+		}
+		results, err := parseRedditResponse(raw)
+		if err != nil {
+			tb.Fatalf("%s: failed to parse fixture: %v", tc.Name, err)
+		}
+		for i := range results {
+			results[i].CreatedUTC += shift
+			if results[i].EditedUTC != 0 {
+				results[i].EditedUTC += shift
+			}
+		}
+
+		ranked := service.processSearchResults(utils.ParseQuery(tc.Query), results, len(results))
+		score := ndcg(ranked, tc.Judgments, golden.K)
+		if testing.Verbose() {
+			tb.Logf("%-28s NDCG@%d %.4f  %s", tc.Name, golden.K, score, rankedIDs(ranked, golden.K))
+		}
+		total += score
+	}
+
+	mean := total / float64(len(golden.Cases))
+	if mean < golden.MinMeanNDCG {
+		tb.Errorf("Mean NDCG@%d is %.4f, below the golden set's minimum of %.4f", golden.K, mean, golden.MinMeanNDCG)
+	}
+	return mean
+}
+
+// ndcg scores the top k of a ranking against graded judgments, from 0 to 1.
+// Gains are 2^grade - 1 and discounted by log2 of the rank plus one.
+func ndcg(ranked []models.SearchResult, judgments map[string]int, k int) float64 {
+	var dcg float64
+	for i, result := range ranked {
+		if i == k {
+			break
+		}
+		dcg += gain(judgments[result.ID]) / math.Log2(float64(i+2))
+	}
+
+	grades := make([]int, 0, len(judgments))
+	for _, grade := range judgments {
+		grades = append(grades, grade)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(grades)))
+	var ideal float64
+	for i, grade := range grades {
+		if i == k {
+			break
+		}
+		ideal += gain(grade) / math.Log2(float64(i+2))
+	}
+
+	if ideal == 0 {
+		return 0
+	}
+	return dcg / ideal
+}
+
+func gain(grade int) float64 {
+	return math.Pow(2, float64(grade)) - 1
+}
+
+// rankedIDs lists the IDs of the top k results, for logs
+func rankedIDs(ranked []models.SearchResult, k int) []string {
+	var ids []string
+	for i, result := range ranked {
+		if i == k {
+			break
+		}
+		ids = append(ids, result.ID)
+	}
+	return ids
+}
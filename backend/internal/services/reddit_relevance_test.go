@@ -0,0 +1,134 @@
+// File: backend/internal/services/reddit_relevance_test.go
+
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+func TestSplitIntoSentencesUnicode(t *testing.T) {
+	sentences := splitIntoSentences("Das ist gut. Über alles! 東京は大きい。大阪も大きい。")
+	expected := []string{"Das ist gut.", " Über alles!", " 東京は大きい。", "大阪も大きい。"}
+	if len(sentences) != len(expected) {
+		t.Fatalf("Expected %d sentences, got %d: %q", len(expected), len(sentences), sentences)
+	}
+	for i := range expected {
+		if sentences[i] != expected[i] {
+			t.Errorf("Sentence %d: expected %q, got %q", i, expected[i], sentences[i])
+		}
+	}
+}
+
+func TestExtractHighlightsUnicode(t *testing.T) {
+	long := strings.Repeat("日本語のラーメンはとても美味しいです", 20) + "。"
+	highlights := extractHighlights("短い。"+long, []string{"ラーメン"})
+	if len(highlights) != 1 {
+		t.Fatalf("Expected 1 highlight, got %d: %q", len(highlights), highlights)
+	}
+	if !utf8.ValidString(highlights[0]) {
+		t.Errorf("Highlight is not valid UTF-8: %q", highlights[0])
+	}
+	if n := utf8.RuneCountInString(highlights[0]); n > 200 {
+		t.Errorf("Expected at most 200 characters, got %d", n)
+	}
+}
+
+func TestRelevanceRecencyBias(t *testing.T) {
+	now := time.Now().Unix()
+	authoritative := models.SearchResult{Type: "post", Title: "Learning Rust: the guide", Score: 5000, CommentCount: 800, CreatedUTC: now - 3*365*24*3600}
+	fresh := models.SearchResult{Type: "post", Title: "Learning Rust: my guide", Score: 40, CommentCount: 12, CreatedUTC: now - 3600}
+
+	testCases := []struct {
+		query      string
+		bias       string
+		freshFirst bool
+	}{
+		{"learning rust guide right now", "", true},
+		{"learning rust guide right now", models.RecencyBiasLow, true},
+		{"learning rust guide right now", models.RecencyBiasNone, false},
+		{"learning rust guide", "", false},
+		{"learning rust guide", models.RecencyBiasHigh, true},
+	}
+	for _, tc := range testCases {
+		params := utils.ParseQuery(tc.query)
+		params.RecencyBias = tc.bias
+		freshScore, oldScore := calculateRelevanceScore(fresh, params), calculateRelevanceScore(authoritative, params)
+		if (freshScore > oldScore) != tc.freshFirst {
+			t.Errorf("%q with bias %q: fresh scored %.1f, authoritative %.1f", tc.query, tc.bias, freshScore, oldScore)
+		}
+	}
+
+	params := utils.ParseQuery("learning rust guide right now")
+	defaultBonus := calculateRelevanceScore(fresh, params) - calculateRelevanceScore(authoritative, params)
+	params.RecencyBias = models.RecencyBiasLow
+	if lowBonus := calculateRelevanceScore(fresh, params) - calculateRelevanceScore(authoritative, params); lowBonus >= defaultBonus {
+		t.Errorf("Expected a low bias to narrow the fresh result's lead, got %.1f vs %.1f", lowBonus, defaultBonus)
+	}
+}
+
+func TestRelevanceAwards(t *testing.T) {
+	params := utils.ParseQuery("best mechanical keyboard")
+	plain := models.SearchResult{Type: "post", Title: "Best mechanical keyboard for coding", Score: 300, CommentCount: 40, CreatedUTC: time.Now().Unix() - 30*24*3600}
+	awarded := plain
+	awarded.Awards = 5
+	gilded := plain
+	gilded.Gilded = 5
+
+	plainScore := calculateRelevanceScore(plain, params)
+	if awardedScore := calculateRelevanceScore(awarded, params); awardedScore <= plainScore {
+		t.Errorf("Expected an awarded result to outrank an identical one, got %.1f vs %.1f", awardedScore, plainScore)
+	}
+	// Gildings count as awards on results that predate awards
+	if gildedScore, awardedScore := calculateRelevanceScore(gilded, params), calculateRelevanceScore(awarded, params); gildedScore != awardedScore {
+		t.Errorf("Expected 5 gildings to score as 5 awards, got %.1f vs %.1f", gildedScore, awardedScore)
+	}
+}
+
+func TestRelevanceModeratorContent(t *testing.T) {
+	created := time.Now().Unix() - 2*24*3600
+	post := models.SearchResult{Type: "post", Title: "Go error handling release notes", Score: 200, CommentCount: 30, CreatedUTC: created}
+	comment := models.SearchResult{Type: "comment", Title: "Comment on: Go error handling", Content: "Go error handling release notes", Score: 50, CreatedUTC: created}
+	evergreen := utils.ParseQuery("go error handling release notes")
+	timely := utils.ParseQuery("latest go error handling release notes")
+	if evergreen.IsTimeSensitive || !timely.IsTimeSensitive {
+		t.Fatalf("Expected only %q to be time-sensitive", timely.OriginalQuery)
+	}
+
+	pinned := post
+	pinned.Stickied = true
+	pinned.Score, pinned.CommentCount = 400, 60
+	announcement := post
+	announcement.Distinguished = true
+	modComment := comment
+	modComment.Distinguished = true
+
+	testCases := []struct {
+		name          string
+		params        utils.QueryParams
+		higher, lower models.SearchResult
+	}{
+		// Pinned rules posts drop below answers despite more engagement
+		{"pinned post, evergreen query", evergreen, post, pinned},
+		// Distinguished comments are mostly removal notices
+		{"distinguished comment, evergreen query", evergreen, comment, modComment},
+		{"distinguished comment, time-sensitive query", timely, comment, modComment},
+		// Moderator announcements are news
+		{"announcement, time-sensitive query", timely, announcement, post},
+	}
+	for _, tc := range testCases {
+		if higher, lower := calculateRelevanceScore(tc.higher, tc.params), calculateRelevanceScore(tc.lower, tc.params); higher <= lower {
+			t.Errorf("%s: expected %.1f above %.1f", tc.name, higher, lower)
+		}
+	}
+
+	// Without a time-sensitive query, announcements are ranked like any post
+	if announced, plain := calculateRelevanceScore(announcement, evergreen), calculateRelevanceScore(post, evergreen); announced != plain {
+		t.Errorf("Expected no announcement boost for an evergreen query, got %.1f vs %.1f", announced, plain)
+	}
+}
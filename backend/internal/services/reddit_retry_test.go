@@ -0,0 +1,236 @@
+// File: backend/internal/services/reddit_retry_test.go
+
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+// newMockRedditService returns a Reddit service talking to a mock Reddit
+// API with a post in r/golang, retrying without the usual delays
+func newMockRedditService(t *testing.T) (*RedditService, *redditmock.Server) {
+	t.Helper()
+
+	delay := initialRetryDelay
+	initialRetryDelay = time.Millisecond
+	t.Cleanup(func() { initialRetryDelay = delay })
+
+	mock := redditmock.New()
+	t.Cleanup(mock.Close)
+	mock.AddPosts(redditmock.Post{ID: "gp1", Subreddit: "golang", Title: "Go 1.22 released", Author: "gopher", Score: 420})
+
+	service := NewRedditService("id", "secret")
+	service.httpClient = mock.Client()
+	service.auth = NewRedditAuth("id", "secret", redditUserAgent, service.httpClient)
+	return service, mock
+}
+
+// listingRequests returns the requests the mock received for r/golang
+func listingRequests(mock *redditmock.Server) []redditmock.Request {
+	var requests []redditmock.Request
+	for _, req := range mock.Requests() {
+		if req.Path == "/r/golang/new.json" {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+func TestRedditRetriesRateLimitedRequests(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Status: http.StatusTooManyRequests, Times: 2})
+
+	results, err := service.FetchListing(context.Background(), "golang", "new", 10)
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after retries, got %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "gp1" {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if requests := listingRequests(mock); len(requests) != 3 {
+		t.Errorf("Expected 2 rate-limited attempts and a success, got %d requests", len(requests))
+	}
+	if budget := service.RateLimitBudget(); budget.ObservedAt == 0 || budget.Remaining != 0 {
+		t.Errorf("Expected the exhausted rate limit to be observed, got %+v", budget)
+	}
+}
+
+func TestRedditGivesUpWhenRateLimited(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Status: http.StatusTooManyRequests})
+
+	_, err := service.FetchListing(context.Background(), "golang", "new", 10)
+	if !errors.Is(err, ErrRedditRateLimited) {
+		t.Errorf("Expected ErrRedditRateLimited, got %v", err)
+	}
+	if requests := listingRequests(mock); len(requests) != maxRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", maxRetries+1, len(requests))
+	}
+}
+
+func TestRedditFallsBackToPublicAPIWhenForbidden(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Status: http.StatusForbidden, Times: 2})
+
+	if _, err := service.FetchListing(context.Background(), "golang", "new", 10); err != nil {
+		t.Fatalf("Expected the public API to answer, got %v", err)
+	}
+	requests := listingRequests(mock)
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", len(requests))
+	}
+	if !requests[0].Authorized || requests[2].Authorized {
+		t.Errorf("Expected authorized attempts then an anonymous one, got %+v", requests)
+	}
+}
+
+func TestRedditUnavailableAfterServerErrors(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Status: http.StatusServiceUnavailable})
+
+	_, err := service.FetchListing(context.Background(), "golang", "new", 10)
+	if !errors.Is(err, ErrRedditUnavailable) {
+		t.Errorf("Expected ErrRedditUnavailable, got %v", err)
+	}
+}
+
+func TestRedditSlowResponseHonorsDeadline(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Delay: 300 * time.Millisecond, Times: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := service.FetchListing(ctx, "golang", "new", 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("Expected to give up at the deadline, took %v", elapsed)
+	}
+}
+
+// searchRequests counts the searches the mock received
+func searchRequests(mock *redditmock.Server) int {
+	count := 0
+	for _, req := range mock.Requests() {
+		if req.Path == "/search.json" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRedditCachesEmptySearchesBriefly(t *testing.T) {
+	ttl := negativeCacheTTL
+	negativeCacheTTL = 50 * time.Millisecond
+	t.Cleanup(func() { negativeCacheTTL = ttl })
+
+	service, mock := newMockRedditService(t)
+	search := func() []models.SearchResult {
+		t.Helper()
+		results, err := service.SearchRedditWithOptions(context.Background(), "nothing matches this", "Posts", 10, SearchOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return results
+	}
+
+	if results := search(); len(results) != 0 {
+		t.Fatalf("Expected no results, got %+v", results)
+	}
+	misses := searchRequests(mock)
+	if misses == 0 {
+		t.Fatal("Expected the first search to reach Reddit")
+	}
+
+	// The miss is remembered, then forgotten after negativeCacheTTL
+	if results := search(); len(results) != 0 || searchRequests(mock) != misses {
+		t.Errorf("Expected the empty result to be served from cache, got %+v after %d requests", results, searchRequests(mock))
+	}
+	time.Sleep(2 * negativeCacheTTL)
+	search()
+	if searchRequests(mock) == misses {
+		t.Error("Expected the empty result to expire")
+	}
+}
+
+func TestRedditDoesNotCacheFailedSearches(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	// Fail the first search and each of its retries
+	mock.Fail(redditmock.Failure{Path: "/search.json", Status: http.StatusServiceUnavailable, Times: maxRetries + 1})
+
+	if _, err := service.SearchRedditWithOptions(context.Background(), "go released", "Posts", 10, SearchOptions{}); err == nil {
+		t.Fatal("Expected the search to fail")
+	}
+
+	// Once Reddit recovers the search goes through instead of hitting a
+	// cached miss
+	results, err := service.SearchRedditWithOptions(context.Background(), "go released", "Posts", 10, SearchOptions{})
+	if err != nil || len(results) == 0 {
+		t.Errorf("Expected results once Reddit recovered, got %+v (%v)", results, err)
+	}
+	if requests := searchRequests(mock); requests != maxRetries+2 {
+		t.Errorf("Expected the second search to reach Reddit, got %d requests", requests)
+	}
+}
+
+func TestRedditCoalescesIdenticalRequests(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Delay: 200 * time.Millisecond})
+	const endpoint = "/r/golang/new.json?limit=10"
+
+	// Start the shared call and wait for it to reach Reddit
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := service.executeRequest(firstCtx, endpoint)
+		firstErr <- err
+	}()
+	for deadline := time.Now().Add(time.Second); len(listingRequests(mock)) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("The first request never reached Reddit")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	const waiters = 3
+	errs := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			body, err := service.executeRequest(context.Background(), endpoint)
+			if err == nil && len(body) == 0 {
+				err = errors.New("empty body")
+			}
+			errs <- err
+		}()
+	}
+	// Let the waiters join before the first caller gives up
+	time.Sleep(20 * time.Millisecond)
+
+	// A cancelled caller returns at once while the shared call goes on
+	cancelled := time.Now()
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancelled caller to get context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(cancelled); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected the cancelled caller to return right away, took %v", elapsed)
+	}
+
+	for i := 0; i < waiters; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected the other callers to get the shared response, got %v", err)
+		}
+	}
+	if requests := listingRequests(mock); len(requests) != 1 {
+		t.Errorf("Expected identical requests to reach Reddit once, got %d", len(requests))
+	}
+}
@@ -0,0 +1,75 @@
+// File: backend/internal/services/reddit_suggest.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// subredditSuggestCacheTTL keeps community suggestions long enough that
+// users typing the same prefixes or queries don't each reach Reddit
+const subredditSuggestCacheTTL = time.Hour
+
+// SuggestSubreddits returns communities whose names start with prefix, from
+// Reddit's typeahead autocomplete. NSFW communities are left out. Results are
+// cached.
+func (s *RedditService) SuggestSubreddits(ctx context.Context, prefix string, limit int) ([]models.SearchResult, error) {
+	prefix = strings.TrimPrefix(strings.TrimSpace(prefix), "r/")
+	if prefix == "" {
+		return []models.SearchResult{}, nil
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("query", prefix)
+	queryParams.Set("limit", fmt.Sprintf("%d", limit))
+	queryParams.Set("include_over_18", "false")
+	queryParams.Set("include_profiles", "false")
+	suggestions, err := s.cachedSubreddits(ctx, "/api/subreddit_autocomplete_v2.json?"+queryParams.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to autocomplete subreddits: %w", err)
+	}
+	return suggestions, nil
+}
+
+// SubredditsForQuery returns communities whose name or description matches
+// query, leaving out NSFW ones. Results are cached.
+func (s *RedditService) SubredditsForQuery(ctx context.Context, query string, limit int) ([]models.SearchResult, error) {
+	queryParams := url.Values{}
+	queryParams.Set("q", strings.TrimSpace(query))
+	queryParams.Set("limit", fmt.Sprintf("%d", limit))
+	subreddits, err := s.cachedSubreddits(ctx, "/subreddits/search.json?"+queryParams.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to search subreddits: %w", err)
+	}
+	return subreddits, nil
+}
+
+// cachedSubreddits fetches the SFW communities listed at endpoint, caching
+// them by endpoint
+func (s *RedditService) cachedSubreddits(ctx context.Context, endpoint string) ([]models.SearchResult, error) {
+	cacheKey := "subreddits:" + strings.ToLower(endpoint)
+	if cached, found := s.resultCache.Get(cacheKey); found {
+		return cached, nil
+	}
+
+	results, err := s.executeSearchRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	subreddits := []models.SearchResult{}
+	for _, result := range results {
+		if result.Type == "subreddit" && !result.NSFW {
+			result.Raw = nil
+			subreddits = append(subreddits, result)
+		}
+	}
+	s.resultCache.SetWithTTL(cacheKey, subreddits, subredditSuggestCacheTTL)
+	return subreddits, nil
+}
@@ -0,0 +1,44 @@
+// File: backend/internal/services/reddit_suggest_test.go
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+func TestSuggestSubreddits(t *testing.T) {
+	service, mock := newMockRedditService(t)
+	mock.AddSubreddits(
+		redditmock.Subreddit{Name: "golang", Description: "The Go programming language", Subscribers: 250000},
+		redditmock.Subreddit{Name: "GoPro", Subscribers: 300000},
+		redditmock.Subreddit{Name: "rust"},
+	)
+
+	suggestions, err := service.SuggestSubreddits(context.Background(), "r/Go", 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(suggestions) != 2 || suggestions[0].Subreddit != "golang" || suggestions[1].Subreddit != "GoPro" {
+		t.Fatalf("Expected the communities starting with go, got %+v", suggestions)
+	}
+	if suggestions[0].Raw != nil {
+		t.Error("Expected suggestions without raw JSON")
+	}
+
+	// Repeated prefixes are served from the cache
+	if _, err := service.SuggestSubreddits(context.Background(), "go", 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	calls := 0
+	for _, req := range mock.Requests() {
+		if req.Path == "/api/subreddit_autocomplete_v2.json" {
+			calls++
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected one autocomplete request, got %d", calls)
+	}
+}
@@ -0,0 +1,101 @@
+// File: backend/internal/services/reddit_trending.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// trendingCacheTTL keeps trending lists fresh without refetching them for
+// every page load
+const trendingCacheTTL = 5 * time.Minute
+
+// TrendingOptions selects what GetTrending returns
+type TrendingOptions struct {
+	// Subreddit scopes posts to one community instead of r/popular
+	Subreddit string
+	// Query searches communities by name and description instead of listing
+	// the popular ones
+	Query string
+	Limit int
+}
+
+// GetTrending returns hot posts from r/popular (or a single subreddit) and
+// popular or matching communities. Results are cached briefly.
+func (s *RedditService) GetTrending(ctx context.Context, opts TrendingOptions) (*models.TrendingResponse, error) {
+	// Subreddit names are case-insensitive, so r/Golang shares r/golang's entry
+	cacheKey := fmt.Sprintf("trending:%s:%s:%d", strings.ToLower(opts.Subreddit), opts.Query, opts.Limit)
+	if cached, found := s.trendingCache.Get(cacheKey); found {
+		return cached, nil
+	}
+
+	subreddit := opts.Subreddit
+	if subreddit == "" {
+		subreddit = "popular"
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	subredditsEndpoint := "/subreddits/popular.json?" + queryParams.Encode()
+	if opts.Query != "" {
+		queryParams.Set("q", opts.Query)
+		subredditsEndpoint = "/subreddits/search.json?" + queryParams.Encode()
+	}
+
+	// Posts and communities are independent, so fetch them concurrently
+	var (
+		wg                     sync.WaitGroup
+		posts, subreddits      []models.SearchResult
+		postsErr, subredditErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		posts, postsErr = s.FetchListing(ctx, subreddit, "hot", opts.Limit)
+	}()
+	go func() {
+		defer wg.Done()
+		subreddits, subredditErr = s.executeSearchRequest(ctx, subredditsEndpoint)
+	}()
+	wg.Wait()
+
+	// Either half is useful on its own; fail only when both are missing
+	if postsErr != nil && subredditErr != nil {
+		return nil, fmt.Errorf("failed to fetch trending content: %w", postsErr)
+	}
+	if postsErr != nil {
+		log.Printf("Error fetching trending posts from r/%s: %v", subreddit, postsErr)
+	}
+	if subredditErr != nil {
+		log.Printf("Error fetching trending subreddits: %v", subredditErr)
+	}
+
+	response := &models.TrendingResponse{
+		Posts:       nonNilResults(posts),
+		Subreddits:  nonNilResults(subreddits),
+		LastUpdated: time.Now().Unix(),
+	}
+
+	// Don't cache partial responses, so the missing half is retried soon
+	if postsErr == nil && subredditErr == nil {
+		s.trendingCache.Set(cacheKey, response)
+	}
+
+	return response, nil
+}
+
+// nonNilResults returns an empty slice instead of nil so JSON clients get []
+func nonNilResults(results []models.SearchResult) []models.SearchResult {
+	if results == nil {
+		return []models.SearchResult{}
+	}
+	return results
+}
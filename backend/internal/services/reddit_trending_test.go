@@ -0,0 +1,98 @@
+// File: backend/internal/services/reddit_trending_test.go
+
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/testing/redditmock"
+)
+
+// popularSubreddits is the mock's response for /subreddits/popular.json
+const popularSubreddits = `{"kind": "Listing", "data": {"children": [
+	{"kind": "t5", "data": {"id": "2qh1i", "display_name": "AskReddit", "title": "Ask Reddit...", "public_description": "Open-ended questions", "subscribers": 45000000, "url": "/r/AskReddit/"}}
+]}}`
+
+// newMockTrendingService returns a Reddit service whose mock lists a post
+// in r/golang and r/AskReddit as a popular community
+func newMockTrendingService(t *testing.T) (*RedditService, *redditmock.Server) {
+	t.Helper()
+	service, mock := newMockRedditService(t)
+	mock.SetResponse("/subreddits/popular.json", popularSubreddits)
+	return service, mock
+}
+
+func TestGetTrending(t *testing.T) {
+	service, mock := newMockTrendingService(t)
+
+	trending, err := service.GetTrending(context.Background(), TrendingOptions{Subreddit: "golang", Limit: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(trending.Posts) != 1 || trending.Posts[0].ID != "gp1" {
+		t.Errorf("Expected r/golang's hot post, got %+v", trending.Posts)
+	}
+	if len(trending.Subreddits) != 1 || trending.Subreddits[0].Subreddit != "AskReddit" {
+		t.Errorf("Expected the popular communities, got %+v", trending.Subreddits)
+	}
+
+	// Complete responses are cached, whatever the subreddit's case
+	requests := len(mock.Requests())
+	for _, subreddit := range []string{"golang", "Golang"} {
+		if _, err := service.GetTrending(context.Background(), TrendingOptions{Subreddit: subreddit, Limit: 10}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(mock.Requests()) != requests {
+		t.Errorf("Expected cache hits without requests, got %d more requests", len(mock.Requests())-requests)
+	}
+}
+
+func TestGetTrendingPartialFailure(t *testing.T) {
+	testCases := []struct {
+		name           string
+		failPath       string
+		wantPosts      int
+		wantSubreddits int
+	}{
+		{"posts fail", "/r/golang", 0, 1},
+		{"communities fail", "/subreddits", 1, 0},
+	}
+	for _, tc := range testCases {
+		service, mock := newMockTrendingService(t)
+		mock.Fail(redditmock.Failure{Path: tc.failPath, Status: http.StatusInternalServerError})
+
+		opts := TrendingOptions{Subreddit: "golang", Limit: 10}
+		trending, err := service.GetTrending(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("%s: expected the other half, got %v", tc.name, err)
+		}
+		if len(trending.Posts) != tc.wantPosts || len(trending.Subreddits) != tc.wantSubreddits {
+			t.Errorf("%s: expected %d posts and %d communities, got %+v", tc.name, tc.wantPosts, tc.wantSubreddits, trending)
+		}
+		if trending.Posts == nil || trending.Subreddits == nil {
+			t.Errorf("%s: expected empty lists rather than null, got %+v", tc.name, trending)
+		}
+
+		// Partial responses aren't cached, so the missing half is retried
+		requests := len(mock.Requests())
+		if _, err := service.GetTrending(context.Background(), opts); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if len(mock.Requests()) == requests {
+			t.Errorf("%s: expected a partial response not to be cached", tc.name)
+		}
+	}
+}
+
+func TestGetTrendingFailure(t *testing.T) {
+	service, mock := newMockTrendingService(t)
+	mock.Fail(redditmock.Failure{Path: "/r/golang", Status: http.StatusInternalServerError})
+	mock.Fail(redditmock.Failure{Path: "/subreddits", Status: http.StatusInternalServerError})
+
+	if trending, err := service.GetTrending(context.Background(), TrendingOptions{Subreddit: "golang", Limit: 10}); err == nil {
+		t.Errorf("Expected an error when both halves fail, got %+v", trending)
+	}
+}
@@ -0,0 +1,231 @@
+// File: backend/internal/services/reddit_user.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+// maxSubscriptionPages bounds how many pages of 100 subscriptions are read
+const maxSubscriptionPages = 5
+
+// RedditUser is a user signed in with Reddit whom a search runs on behalf of
+type RedditUser struct {
+	Name        string
+	AccessToken string
+}
+
+// redditUserKey carries a RedditUser in a context
+type redditUserKey struct{}
+
+// userTokenKey carries the access token doRequest should use instead of the
+// app's own
+type userTokenKey struct{}
+
+// WithRedditUser returns a context for searches scoped to user's own content
+// (SearchRequest.RedditScope)
+func WithRedditUser(ctx context.Context, user RedditUser) context.Context {
+	return context.WithValue(ctx, redditUserKey{}, user)
+}
+
+// redditUserFrom returns the user set with WithRedditUser
+func redditUserFrom(ctx context.Context) (RedditUser, bool) {
+	user, ok := ctx.Value(redditUserKey{}).(RedditUser)
+	return user, ok && user.AccessToken != ""
+}
+
+// Auth returns the service's Reddit authentication, which also handles
+// users signing in with Reddit
+func (s *RedditService) Auth() *RedditAuth {
+	return s.auth
+}
+
+// userRequest fetches endpoint with a user's access token. Responses depend
+// on the user, so they are neither shared with other callers nor cached.
+func (s *RedditService) userRequest(ctx context.Context, accessToken, endpoint string) ([]byte, error) {
+	return s.doRequest(context.WithValue(ctx, userTokenKey{}, accessToken), endpoint)
+}
+
+// Identity returns the username of the user an access token belongs to
+func (s *RedditService) Identity(ctx context.Context, accessToken string) (string, error) {
+	body, err := s.userRequest(ctx, accessToken, "/api/v1/me?raw_json=1")
+	if err != nil {
+		return "", err
+	}
+
+	var me struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &me); err != nil {
+		return "", fmt.Errorf("error parsing identity: %w", err)
+	}
+	if me.Name == "" {
+		return "", fmt.Errorf("reddit returned no username")
+	}
+	return me.Name, nil
+}
+
+// SearchUserScope searches the user's own content: the communities they
+// subscribe to, one of their multireddits, or the posts and comments they
+// saved. Scopes are SearchRequest.RedditScope values.
+func (s *RedditService) SearchUserScope(ctx context.Context, user RedditUser, query, searchMode string, limit int, scope string) ([]models.SearchResult, error) {
+	if scope == models.RedditScopeSaved {
+		return s.searchSaved(ctx, user, query, limit)
+	}
+
+	var subreddits []string
+	var err error
+	if name, ok := strings.CutPrefix(scope, models.RedditScopeMultiPrefix); ok {
+		subreddits, err = s.multiredditSubreddits(ctx, user, name)
+	} else {
+		subreddits, err = s.subscriptions(ctx, user)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(subreddits) == 0 {
+		return nil, nil
+	}
+
+	// Reddit caps query length, so only the communities most likely to
+	// matter are searched
+	subreddits = rankSubreddits(subreddits, utils.ParseQuery(query).FilteredKeywords, maxRequestSubreddits)
+	log.Printf("Searching u/%s's %s in %d communities", user.Name, scope, len(subreddits))
+
+	// Community searches are public, so they go through the shared cache
+	return s.SearchRedditWithOptions(ctx, query, searchMode, limit, SearchOptions{Subreddits: subreddits})
+}
+
+// searchSaved ranks the user's most recently saved posts and comments
+// against the query
+func (s *RedditService) searchSaved(ctx context.Context, user RedditUser, query string, limit int) ([]models.SearchResult, error) {
+	queryParams := url.Values{}
+	queryParams.Set("limit", fmt.Sprintf("%d", maxRequestLimit))
+	queryParams.Set("raw_json", "1")
+
+	endpoint := fmt.Sprintf("/user/%s/saved?%s", url.PathEscape(user.Name), queryParams.Encode())
+	body, err := s.userRequest(ctx, user.AccessToken, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := parseRedditResponse(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing saved items: %w", err)
+	}
+
+	if limit <= 0 || limit > maxRequestLimit {
+		limit = defaultRequestLimit
+	}
+	return s.processSearchResults(utils.ParseQuery(query), saved, limit), nil
+}
+
+// subscriptions lists the communities the user subscribes to
+func (s *RedditService) subscriptions(ctx context.Context, user RedditUser) ([]string, error) {
+	var names []string
+	after := ""
+	for page := 0; page < maxSubscriptionPages; page++ {
+		queryParams := url.Values{}
+		queryParams.Set("limit", fmt.Sprintf("%d", maxRequestLimit))
+		if after != "" {
+			queryParams.Set("after", after)
+		}
+
+		body, err := s.userRequest(ctx, user.AccessToken, "/subreddits/mine/subscriber?"+queryParams.Encode())
+		if err != nil {
+			return nil, err
+		}
+		var listing struct {
+			Data struct {
+				After    string `json:"after"`
+				Children []struct {
+					Data struct {
+						DisplayName string `json:"display_name"`
+					} `json:"data"`
+				} `json:"children"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(body, &listing); err != nil {
+			return nil, fmt.Errorf("error parsing subscriptions: %w", err)
+		}
+
+		for _, child := range listing.Data.Children {
+			// Profiles the user follows are listed as "u_name"
+			if name := child.Data.DisplayName; name != "" && !strings.HasPrefix(name, "u_") {
+				names = append(names, name)
+			}
+		}
+		if after = listing.Data.After; after == "" {
+			break
+		}
+	}
+	return names, nil
+}
+
+// multiredditSubreddits lists the communities in one of the user's
+// multireddits, matching its name regardless of case
+func (s *RedditService) multiredditSubreddits(ctx context.Context, user RedditUser, name string) ([]string, error) {
+	body, err := s.userRequest(ctx, user.AccessToken, "/api/multi/mine")
+	if err != nil {
+		return nil, err
+	}
+
+	var multis []struct {
+		Data struct {
+			Name       string `json:"name"`
+			Subreddits []struct {
+				Name string `json:"name"`
+			} `json:"subreddits"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &multis); err != nil {
+		return nil, fmt.Errorf("error parsing multireddits: %w", err)
+	}
+
+	for _, multi := range multis {
+		if !strings.EqualFold(multi.Data.Name, name) {
+			continue
+		}
+		names := make([]string, 0, len(multi.Data.Subreddits))
+		for _, sr := range multi.Data.Subreddits {
+			names = append(names, sr.Name)
+		}
+		return names, nil
+	}
+	return nil, &ValidationError{Fields: []models.FieldError{{
+		Field:   "redditScope",
+		Message: fmt.Sprintf("u/%s has no multireddit named '%s'", user.Name, name),
+	}}}
+}
+
+// rankSubreddits keeps up to max communities, preferring those whose names
+// mention the query's keywords and otherwise keeping Reddit's order
+func rankSubreddits(names []string, keywords []string, max int) []string {
+	score := func(name string) int {
+		lower := strings.ToLower(name)
+		matches := 0
+		for _, keyword := range keywords {
+			if strings.Contains(lower, strings.ToLower(keyword)) {
+				matches++
+			}
+		}
+		return matches
+	}
+
+	ranked := append([]string{}, names...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return score(ranked[i]) > score(ranked[j])
+	})
+	if len(ranked) > max {
+		ranked = ranked[:max]
+	}
+	return ranked
+}
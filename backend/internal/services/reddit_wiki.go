@@ -0,0 +1,220 @@
+// File: backend/internal/services/reddit_wiki.go
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// wikiCacheTTL is long because wikis are edited far less often than posts
+const wikiCacheTTL = 6 * time.Hour
+
+// maxWikiSectionChars caps the text kept from a wiki section
+const maxWikiSectionChars = 3000
+
+// wikiHeadingPattern matches markdown headings
+var wikiHeadingPattern = regexp.MustCompile(`^#{1,6}\s+(.+?)\s*#*$`)
+
+// wikiPage is a subreddit wiki page
+type wikiPage struct {
+	Subreddit string
+	Name      string // Path within the wiki, e.g. "faq" or "guides/budget"
+	Content   string // Markdown
+	Revised   int64  // Unix time of the last revision
+}
+
+// wikiSection is a page section under a single heading
+type wikiSection struct {
+	Heading string
+	Text    string
+}
+
+// wikiPageNames lists a subreddit's wiki pages. Wikis that are disabled or
+// private return an error.
+func (s *RedditService) wikiPageNames(ctx context.Context, subreddit string) ([]string, error) {
+	body, err := s.wikiRequest(ctx, fmt.Sprintf("/r/%s/wiki/pages.json", url.PathEscape(subreddit)))
+	if err != nil {
+		return nil, err
+	}
+
+	var listing struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, fmt.Errorf("error parsing wiki page list: %w", err)
+	}
+	return listing.Data, nil
+}
+
+// wikiPage fetches a wiki page
+func (s *RedditService) wikiPage(ctx context.Context, subreddit, name string) (*wikiPage, error) {
+	body, err := s.wikiRequest(ctx, fmt.Sprintf("/r/%s/wiki/%s.json?raw_json=1", url.PathEscape(subreddit), escapeWikiPath(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	var page struct {
+		Data struct {
+			ContentMD    string  `json:"content_md"`
+			RevisionDate float64 `json:"revision_date"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("error parsing wiki page: %w", err)
+	}
+	return &wikiPage{
+		Subreddit: subreddit,
+		Name:      name,
+		Content:   page.Data.ContentMD,
+		Revised:   int64(page.Data.RevisionDate),
+	}, nil
+}
+
+// wikiRequest makes a wiki API request through the wiki cache
+func (s *RedditService) wikiRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	if body, found := s.wikiCache.Get(endpoint); found {
+		return body, nil
+	}
+	body, err := s.executeRequest(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s.wikiCache.Set(endpoint, body)
+	return body, nil
+}
+
+// escapeWikiPath escapes each segment of a wiki page path
+func escapeWikiPath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// rankWikiPages picks the pages most likely to answer a query: those whose
+// names mention its keywords, then the index and FAQ, which cover the
+// community's common questions. Moderator configuration pages are skipped.
+func rankWikiPages(names []string, keywords []string, max int) []string {
+	type rankedPage struct {
+		name  string
+		score int
+	}
+
+	var ranked []rankedPage
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "config/") || lower == "usernotes" || lower == "automoderator" {
+			continue
+		}
+
+		score := 0
+		for _, keyword := range keywords {
+			if strings.Contains(lower, strings.ToLower(keyword)) {
+				score += 2
+			}
+		}
+		if lower == "index" || lower == "faq" || strings.HasSuffix(lower, "/faq") {
+			score++
+		}
+		if score > 0 {
+			ranked = append(ranked, rankedPage{name: name, score: score})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	if len(ranked) > max {
+		ranked = ranked[:max]
+	}
+
+	pages := make([]string, len(ranked))
+	for i, page := range ranked {
+		pages[i] = page.name
+	}
+	return pages
+}
+
+// splitWikiSections splits markdown into sections at each heading. Text
+// before the first heading forms a section without one.
+func splitWikiSections(content string) []wikiSection {
+	var sections []wikiSection
+	current := wikiSection{}
+	var text strings.Builder
+
+	flush := func() {
+		current.Text = strings.TrimSpace(text.String())
+		if current.Text != "" {
+			sections = append(sections, current)
+		}
+		text.Reset()
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if match := wikiHeadingPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			flush()
+			current = wikiSection{Heading: match[1]}
+			continue
+		}
+		text.WriteString(line)
+		text.WriteString("\n")
+	}
+	flush()
+
+	return sections
+}
+
+// wikiSectionScore counts the keywords a section mentions, weighting its
+// heading; zero means the section is unrelated to the query
+func wikiSectionScore(section wikiSection, keywords []string) int {
+	heading := strings.ToLower(section.Heading)
+	text := strings.ToLower(section.Text)
+
+	score := 0
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		if strings.Contains(heading, keyword) {
+			score += 3
+		}
+		if strings.Contains(text, keyword) {
+			score += 2
+		}
+	}
+	return score
+}
+
+// wikiResult converts a wiki section into a search result
+func wikiResult(page *wikiPage, index int, section wikiSection) models.SearchResult {
+	title := fmt.Sprintf("r/%s wiki: %s", page.Subreddit, page.Name)
+	if section.Heading != "" {
+		title += " - " + section.Heading
+	}
+
+	content := section.Text
+	if utf8.RuneCountInString(content) > maxWikiSectionChars {
+		content = string([]rune(content)[:maxWikiSectionChars]) + "..."
+	}
+
+	pageURL := fmt.Sprintf("https://www.reddit.com/r/%s/wiki/%s", page.Subreddit, page.Name)
+	return models.SearchResult{
+		ID:         fmt.Sprintf("wiki:%s/%s#%d", strings.ToLower(page.Subreddit), page.Name, index),
+		Title:      title,
+		Subreddit:  page.Subreddit,
+		Content:    content,
+		URL:        pageURL,
+		Permalink:  pageURL,
+		CreatedUTC: page.Revised,
+		Type:       "wiki",
+	}
+}
@@ -0,0 +1,49 @@
+// File: backend/internal/services/reddit_wiki_test.go
+
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankWikiPages(t *testing.T) {
+	names := []string{"config/sidebar", "index", "faq", "guides/budget_builds", "usernotes", "rules", "guides/monitors"}
+
+	got := rankWikiPages(names, []string{"budget", "build"}, 3)
+	want := []string{"guides/budget_builds", "index", "faq"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankWikiPages = %v, want %v", got, want)
+	}
+	if got := rankWikiPages([]string{"rules", "config/automoderator"}, []string{"gpu"}, 3); len(got) != 0 {
+		t.Errorf("Expected no pages, got %v", got)
+	}
+}
+
+func TestWikiSections(t *testing.T) {
+	content := "Welcome to the wiki.\n\n# Budget builds\n\nA $600 build with a used GPU is the sweet spot.\n\n## Monitors ##\n\nPick 1440p for most budgets.\n"
+	sections := splitWikiSections(content)
+	if len(sections) != 3 {
+		t.Fatalf("Expected 3 sections, got %+v", sections)
+	}
+	if sections[0].Heading != "" || sections[1].Heading != "Budget builds" || sections[2].Heading != "Monitors" {
+		t.Errorf("Unexpected headings: %+v", sections)
+	}
+	if sections[1].Text != "A $600 build with a used GPU is the sweet spot." {
+		t.Errorf("Unexpected section text: %q", sections[1].Text)
+	}
+
+	keywords := []string{"budget", "gpu"}
+	if heading, body := wikiSectionScore(sections[1], keywords), wikiSectionScore(sections[2], keywords); heading <= body {
+		t.Errorf("Expected the matching section to score higher: %d vs %d", heading, body)
+	}
+	if score := wikiSectionScore(sections[0], keywords); score != 0 {
+		t.Errorf("Expected an unrelated section to score 0, got %d", score)
+	}
+
+	result := wikiResult(&wikiPage{Subreddit: "buildapc", Name: "guides/budget", Revised: 1700000000}, 1, sections[1])
+	if result.Type != "wiki" || result.Title != "r/buildapc wiki: guides/budget - Budget builds" ||
+		result.URL != "https://www.reddit.com/r/buildapc/wiki/guides/budget" || result.ID != "wiki:buildapc/guides/budget#1" {
+		t.Errorf("Unexpected wiki result: %+v", result)
+	}
+}
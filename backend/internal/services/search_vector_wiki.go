@@ -0,0 +1,117 @@
+// File: backend/internal/services/search_vector_wiki.go
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// Limits on how much of each wiki is read
+const (
+	wikiSubreddits     = 2 // Communities searched when none are given
+	wikiPagesPerWiki   = 3 // Pages read from each community's wiki
+	wikiSectionsPerHit = 2 // Best sections kept from each page
+)
+
+// WikiSearchVector searches the wikis of the communities a query is about.
+// Wikis hold curated FAQs and guides, often the best sources for evergreen
+// questions, and Reddit's post search never returns them.
+type WikiSearchVector struct {
+	Keywords   []string
+	Subreddits []string // Communities to search; found from the keywords when empty
+	Limit      int
+}
+
+func (v *WikiSearchVector) Execute(ctx context.Context, service *RedditService) ([]models.SearchResult, error) {
+	if len(v.Keywords) == 0 {
+		return nil, nil
+	}
+
+	subreddits := v.Subreddits
+	if len(subreddits) == 0 {
+		subQuery := url.Values{}
+		subQuery.Set("q", strings.Join(v.Keywords, " "))
+		subQuery.Set("limit", fmt.Sprintf("%d", wikiSubreddits))
+
+		found, err := service.executeSearchRequest(ctx, "/subreddits/search.json?"+subQuery.Encode())
+		if err != nil {
+			return nil, err
+		}
+		for _, sr := range found {
+			subreddits = append(subreddits, sr.Subreddit)
+		}
+	}
+
+	type scoredSection struct {
+		result models.SearchResult
+		score  int
+	}
+	var sections []scoredSection
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, subreddit := range subreddits {
+		wg.Add(1)
+		go func(subreddit string) {
+			defer wg.Done()
+
+			// Many communities have no wiki or keep it private
+			names, err := service.wikiPageNames(ctx, subreddit)
+			if err != nil {
+				log.Printf("No wiki for r/%s: %v", subreddit, err)
+				return
+			}
+
+			for _, name := range rankWikiPages(names, v.Keywords, wikiPagesPerWiki) {
+				page, err := service.wikiPage(ctx, subreddit, name)
+				if err != nil {
+					log.Printf("Error fetching r/%s wiki page %s: %v", subreddit, name, err)
+					continue
+				}
+
+				var pageSections []scoredSection
+				for i, section := range splitWikiSections(page.Content) {
+					if score := wikiSectionScore(section, v.Keywords); score > 0 {
+						pageSections = append(pageSections, scoredSection{result: wikiResult(page, i, section), score: score})
+					}
+				}
+				sort.SliceStable(pageSections, func(i, j int) bool {
+					return pageSections[i].score > pageSections[j].score
+				})
+				if len(pageSections) > wikiSectionsPerHit {
+					pageSections = pageSections[:wikiSectionsPerHit]
+				}
+
+				mu.Lock()
+				sections = append(sections, pageSections...)
+				mu.Unlock()
+			}
+		}(subreddit)
+	}
+	wg.Wait()
+
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].score > sections[j].score
+	})
+	if len(sections) > v.Limit {
+		sections = sections[:v.Limit]
+	}
+
+	results := make([]models.SearchResult, len(sections))
+	for i, section := range sections {
+		results[i] = section.result
+	}
+	return results, nil
+}
+
+func (v *WikiSearchVector) GetDescription() string {
+	return fmt.Sprintf("Wiki search for '%s'", strings.Join(v.Keywords, " "))
+}
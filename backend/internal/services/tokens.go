@@ -0,0 +1,48 @@
+// File: backend/internal/services/tokens.go
+
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// charsPerToken is the rough number of characters in a token for the
+// supported models' tokenizers, used to estimate usage
+const charsPerToken = 4
+
+// TokenCounter adds up the estimated tokens of the AI calls made with a
+// context, e.g. to charge them to a client's quota
+type TokenCounter struct {
+	tokens atomic.Int64
+}
+
+type tokenCounterKey struct{}
+
+// WithTokenCounter returns a context whose AI calls are counted by the
+// returned counter
+func WithTokenCounter(ctx context.Context) (context.Context, *TokenCounter) {
+	counter := &TokenCounter{}
+	return context.WithValue(ctx, tokenCounterKey{}, counter), counter
+}
+
+// Tokens returns the estimated tokens counted so far
+func (c *TokenCounter) Tokens() int64 {
+	return c.tokens.Load()
+}
+
+// countTokens charges a model call's prompt and response to the context's
+// counter, if it has one. Attached images aren't counted.
+func countTokens(ctx context.Context, prompt, response string) {
+	counter, ok := ctx.Value(tokenCounterKey{}).(*TokenCounter)
+	if !ok {
+		return
+	}
+	counter.tokens.Add(estimateTokens(prompt) + estimateTokens(response))
+}
+
+// estimateTokens approximates the number of tokens in text
+func estimateTokens(text string) int64 {
+	return int64((utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken)
+}
@@ -0,0 +1,244 @@
+// File: backend/internal/services/validation.go
+
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// answerLanguagePattern accepts language names and codes ("Spanish", "pt-BR")
+// while keeping arbitrary instructions out of the prompt
+var answerLanguagePattern = regexp.MustCompile(`^\p{L}[\p{L} ()-]{0,39}$`)
+
+// localePattern accepts BCP 47 style language tags ("de", "pt-BR", "en_US")
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{1,8})*$`)
+
+// subredditPattern matches subreddit names, optionally written as "r/name"
+var subredditPattern = regexp.MustCompile(`^(r/)?[A-Za-z0-9_]{2,21}$`)
+
+// multiredditPattern matches multireddit names in "multi:<name>" scopes
+var multiredditPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+
+// maxRequestSubreddits caps how many communities a search may be scoped to
+const maxRequestSubreddits = 20
+
+// authorPattern matches Reddit usernames, with or without the "u/" prefix
+var authorPattern = regexp.MustCompile(`^(u/)?[A-Za-z0-9_-]{3,20}$`)
+
+// maxRequestAuthors caps how many users a search may leave out
+const maxRequestAuthors = 50
+
+// maxMinSubreddits caps SearchRequest.MinSubreddits, since each community
+// short of it costs a broadening search
+const maxMinSubreddits = 5
+
+// ValidationError lists the fields of a request that failed validation
+type ValidationError struct {
+	Fields []models.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns e, or nil when no field failed
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateRequest rejects search requests that can't be run, whatever the
+// server's configuration. Failures are reported as a *ValidationError.
+func ValidateRequest(req *models.SearchRequest) error {
+	invalid := &ValidationError{}
+	validateRequest(req, invalid)
+	return invalid.err()
+}
+
+func validateRequest(req *models.SearchRequest, invalid *ValidationError) {
+	if strings.TrimSpace(req.Query) == "" {
+		invalid.add("query", "search query cannot be empty")
+	}
+	if req.Limit < 0 {
+		invalid.add("limit", "must not be negative, got %d", req.Limit)
+	}
+	if req.MaxContentLength < 0 || req.MaxContentLength > config.MaxResultContentLength {
+		invalid.add("maxContentLength", "must be between 0 and %d, got %d", config.MaxResultContentLength, req.MaxContentLength)
+	}
+	if req.Highlights < 0 || req.Highlights > config.MaxResultHighlights {
+		invalid.add("highlights", "must be between 0 and %d, got %d", config.MaxResultHighlights, req.Highlights)
+	}
+	if req.MinSubreddits < 0 || req.MinSubreddits > maxMinSubreddits {
+		invalid.add("minSubreddits", "must be between 0 and %d, got %d", maxMinSubreddits, req.MinSubreddits)
+	}
+	if req.MinScore < 0 {
+		invalid.add("minScore", "must not be negative, got %d", req.MinScore)
+	}
+	if req.MinComments < 0 {
+		invalid.add("minComments", "must not be negative, got %d", req.MinComments)
+	}
+	if len(req.Subreddits) > maxRequestSubreddits {
+		invalid.add("subreddits", "at most %d communities, got %d", maxRequestSubreddits, len(req.Subreddits))
+	}
+	for _, sr := range req.Subreddits {
+		if sr = strings.TrimSpace(sr); !subredditPattern.MatchString(sr) {
+			invalid.add("subreddits", "invalid subreddit name '%s'", sr)
+			break
+		}
+	}
+	if len(req.ExcludeSubreddits) > maxRequestSubreddits {
+		invalid.add("excludeSubreddits", "at most %d communities, got %d", maxRequestSubreddits, len(req.ExcludeSubreddits))
+	}
+	for _, sr := range req.ExcludeSubreddits {
+		if sr = strings.TrimSpace(sr); !subredditPattern.MatchString(sr) {
+			invalid.add("excludeSubreddits", "invalid subreddit name '%s'", sr)
+			break
+		}
+	}
+	if len(req.ExcludeAuthors) > maxRequestAuthors {
+		invalid.add("excludeAuthors", "at most %d users, got %d", maxRequestAuthors, len(req.ExcludeAuthors))
+	}
+	for _, author := range req.ExcludeAuthors {
+		if author = strings.TrimSpace(author); !authorPattern.MatchString(author) {
+			invalid.add("excludeAuthors", "invalid username '%s'", author)
+			break
+		}
+	}
+	if scope := strings.TrimSpace(req.RedditScope); scope != "" {
+		if !validRedditScope(scope) {
+			invalid.add("redditScope", "unsupported redditScope '%s' (expected %s, %s or %s<name>)",
+				req.RedditScope, models.RedditScopeSubscriptions, models.RedditScopeSaved, models.RedditScopeMultiPrefix)
+		} else if len(req.Subreddits) > 0 {
+			invalid.add("redditScope", "can't be combined with subreddits")
+		}
+	}
+	if lang := strings.TrimSpace(req.AnswerLanguage); lang != "" && !answerLanguagePattern.MatchString(lang) {
+		invalid.add("answerLanguage", "unsupported answerLanguage '%s'", req.AnswerLanguage)
+	}
+	if locale := strings.TrimSpace(req.Locale); locale != "" && !localePattern.MatchString(locale) {
+		invalid.add("locale", "invalid locale '%s' (expected a language tag such as \"de\" or \"pt-BR\")", req.Locale)
+	}
+	if _, err := requestLocation(req.Timezone); err != nil {
+		invalid.add("timezone", "unknown timezone '%s' (expected an IANA name such as \"Europe/Berlin\")", req.Timezone)
+	}
+	switch strings.ToLower(strings.TrimSpace(req.AnswerFormat)) {
+	case "", models.AnswerFormatBullets, models.AnswerFormatTable, models.AnswerFormatEssay:
+	default:
+		invalid.add("answerFormat", "unsupported answerFormat '%s' (expected bullets, table or essay)", req.AnswerFormat)
+	}
+	switch strings.ToLower(strings.TrimSpace(req.RecencyBias)) {
+	case "", models.RecencyBiasNone, models.RecencyBiasLow, models.RecencyBiasHigh:
+	default:
+		invalid.add("recencyBias", "unsupported recencyBias '%s' (expected none, low or high)", req.RecencyBias)
+	}
+}
+
+// validRedditScope reports whether scope is one of the RedditScope values,
+// ignoring the case of its keyword
+func validRedditScope(scope string) bool {
+	if strings.EqualFold(scope, models.RedditScopeSubscriptions) || strings.EqualFold(scope, models.RedditScopeSaved) {
+		return true
+	}
+	prefix := models.RedditScopeMultiPrefix
+	return len(scope) > len(prefix) && strings.EqualFold(scope[:len(prefix)], prefix) && multiredditPattern.MatchString(scope[len(prefix):])
+}
+
+// requestRules are the configured bounds on search requests
+type requestRules struct {
+	maxQueryLength int
+	searchModes    []string
+	// models are the selectable model names; nil allows every model the AI
+	// service knows
+	models []string
+	// highlights is the number of highlights kept per result by default
+	highlights int
+}
+
+// SetRequestRules replaces the bounds on query length, search modes and
+// models that Validate enforces, and the default number of highlights
+func (p *SearchPipeline) SetRequestRules(cfg config.RequestsConfig) {
+	p.rules = requestRules{
+		maxQueryLength: cfg.MaxQueryLength,
+		searchModes:    cfg.SearchModes,
+		models:         cfg.Models,
+		highlights:     cfg.Highlights,
+	}
+}
+
+// Validate is ValidateRequest plus the configured bounds on query length,
+// search modes and models. It canonicalizes the case of the search mode and
+// model name. Failures are reported as a *ValidationError listing every
+// invalid field.
+func (p *SearchPipeline) Validate(req *models.SearchRequest) error {
+	invalid := &ValidationError{}
+	validateRequest(req, invalid)
+
+	if length := utf8.RuneCountInString(strings.TrimSpace(req.Query)); length > p.rules.maxQueryLength {
+		invalid.add("query", "must be at most %d characters, got %d", p.rules.maxQueryLength, length)
+	}
+
+	if req.SearchMode != "" {
+		if mode, ok := matchName(req.SearchMode, p.rules.searchModes); ok {
+			req.SearchMode = mode
+		} else {
+			invalid.add("searchMode", "unsupported searchMode '%s' (expected %s)", req.SearchMode, strings.Join(p.rules.searchModes, ", "))
+		}
+	}
+
+	if req.ModelName != "" {
+		allowed := p.selectableModels()
+		if model, ok := matchName(req.ModelName, allowed); ok {
+			req.ModelName = model
+		} else {
+			invalid.add("modelName", "unsupported modelName '%s' (expected %s)", req.ModelName, strings.Join(allowed, ", "))
+		}
+	}
+
+	if region := strings.TrimSpace(req.Region); region != "" && p.reddit != nil {
+		if _, ok := p.reddit.RegionSubreddits(region); !ok {
+			invalid.add("region", "unknown region '%s' (expected %s)", req.Region, strings.Join(p.reddit.Regions(), ", "))
+		}
+	}
+
+	return invalid.err()
+}
+
+// selectableModels lists the model names clients may request
+func (p *SearchPipeline) selectableModels() []string {
+	if p.rules.models != nil {
+		return p.rules.models
+	}
+	catalog := p.ai.Catalog()
+	names := make([]string, len(catalog.Models))
+	for i, model := range catalog.Models {
+		names[i] = model.Name
+	}
+	return names
+}
+
+// matchName finds name in names, ignoring case
+func matchName(name string, names []string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, candidate := range names {
+		if strings.EqualFold(name, candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
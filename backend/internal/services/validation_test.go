@@ -0,0 +1,112 @@
+// File: backend/internal/services/validation_test.go
+
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestPipelineValidate(t *testing.T) {
+	pipeline := NewSearchPipeline(nil, nil)
+	pipeline.SetRequestRules(config.RequestsConfig{
+		MaxQueryLength: 10,
+		SearchModes:    []string{"Posts", "Comments"},
+		Models:         []string{"Claude", "GPT-4o"},
+	})
+
+	req := models.SearchRequest{Query: "golang", SearchMode: "posts", ModelName: "gpt-4O"}
+	if err := pipeline.Validate(&req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if req.SearchMode != "Posts" || req.ModelName != "GPT-4o" {
+		t.Errorf("Expected canonical mode and model, got %q and %q", req.SearchMode, req.ModelName)
+	}
+
+	req = models.SearchRequest{
+		Query:             "a query that is far too long",
+		SearchMode:        "All",
+		ModelName:         "Llama",
+		Subreddits:        []string{"golang", "not a subreddit"},
+		AnswerFormat:      "poem",
+		RecencyBias:       "extreme",
+		Highlights:        config.MaxResultHighlights + 1,
+		MinSubreddits:     -1,
+		MinComments:       -1,
+		ExcludeSubreddits: []string{"r/"},
+		ExcludeAuthors:    []string{"a b"},
+	}
+	err := pipeline.Validate(&req)
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a *ValidationError, got %v", err)
+	}
+	var fields []string
+	for _, field := range invalid.Fields {
+		fields = append(fields, field.Field)
+	}
+	if got := strings.Join(fields, ","); got != "highlights,minSubreddits,minComments,subreddits,excludeSubreddits,excludeAuthors,answerFormat,recencyBias,query,searchMode,modelName" {
+		t.Errorf("Unexpected invalid fields %s", got)
+	}
+	if !strings.Contains(err.Error(), "searchMode: unsupported searchMode 'All' (expected Posts, Comments)") {
+		t.Errorf("Expected the allowed modes in the error, got %q", err.Error())
+	}
+}
+
+func TestValidateRequestEmptyQuery(t *testing.T) {
+	err := ValidateRequest(&models.SearchRequest{Query: "  "})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || len(invalid.Fields) != 1 || invalid.Fields[0].Field != "query" {
+		t.Errorf("Expected a query field error, got %v", err)
+	}
+}
+
+func TestValidateRedditScope(t *testing.T) {
+	for _, scope := range []string{"subscriptions", "Saved", "multi:Tech_News", "MULTI:gaming"} {
+		if err := ValidateRequest(&models.SearchRequest{Query: "q", RedditScope: scope}); err != nil {
+			t.Errorf("Expected redditScope %q to be valid, got %v", scope, err)
+		}
+	}
+	for _, req := range []models.SearchRequest{
+		{Query: "q", RedditScope: "frontpage"},
+		{Query: "q", RedditScope: "multi:"},
+		{Query: "q", RedditScope: "multi:no spaces"},
+		{Query: "q", RedditScope: "saved", Subreddits: []string{"golang"}},
+	} {
+		err := ValidateRequest(&req)
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) || invalid.Fields[0].Field != "redditScope" {
+			t.Errorf("Expected a redditScope error for %+v, got %v", req, err)
+		}
+	}
+
+	req := models.SearchRequest{Query: "q", RedditScope: " Multi:Tech_News "}
+	NormalizeRequest(&req)
+	if req.RedditScope != "multi:Tech_News" {
+		t.Errorf("Expected the scope keyword lowercased and the name kept, got %q", req.RedditScope)
+	}
+}
+
+func TestPipelineHighlights(t *testing.T) {
+	pipeline := NewSearchPipeline(nil, nil)
+	results := []models.SearchResult{
+		{ID: "a", Highlights: []string{"one", "two", "three", "four", "five"}},
+		{ID: "b", Highlights: []string{"one"}},
+	}
+
+	if n := pipeline.highlightCount(models.SearchRequest{}); n != config.Default().Requests.Highlights {
+		t.Errorf("Expected the configured default, got %d", n)
+	}
+	n := pipeline.highlightCount(models.SearchRequest{Highlights: 4})
+	limited := limitHighlights(results, n)
+	if len(limited[0].Highlights) != 4 || len(limited[1].Highlights) != 1 {
+		t.Errorf("Expected at most 4 highlights per result, got %+v", limited)
+	}
+	if len(results[0].Highlights) != 5 {
+		t.Error("Expected the caller's results to be left unchanged")
+	}
+}
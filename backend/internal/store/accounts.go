@@ -0,0 +1,89 @@
+// File: backend/internal/store/accounts.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// CreateAccount stores a new account with a hashed password and assigns its
+// ID and creation time. It returns ErrExists when the email is taken.
+func (s *Store) CreateAccount(ctx context.Context, account *models.Account, passwordHash string) error {
+	return createAccount(ctx, s.db, account, passwordHash)
+}
+
+// execer is a database or transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// createAccount inserts an account; accounts without an email store NULL,
+// which doesn't conflict with other accounts
+func createAccount(ctx context.Context, db execer, account *models.Account, passwordHash string) error {
+	account.ID = newID()
+	account.CreatedAt = time.Now().Unix()
+
+	var email sql.NullString
+	if account.Email != "" {
+		email = sql.NullString{String: account.Email, Valid: true}
+	}
+	result, err := db.ExecContext(ctx,
+		`INSERT INTO accounts (id, email, password_hash, created_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT (email) DO NOTHING`,
+		account.ID, email, passwordHash, account.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating account: %w", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error creating account: %w", err)
+	}
+	if created == 0 {
+		return ErrExists
+	}
+
+	return nil
+}
+
+// AccountByEmail returns the account registered with email, ignoring case,
+// and its password hash
+func (s *Store) AccountByEmail(ctx context.Context, email string) (*models.Account, string, error) {
+	var account models.Account
+	var passwordHash string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, email, password_hash, created_at FROM accounts WHERE email = ?`, email).
+		Scan(&account.ID, &account.Email, &passwordHash, &account.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error loading account: %w", err)
+	}
+	return &account, passwordHash, nil
+}
+
+// Account returns the account with the given ID, and the Reddit account
+// linked to it if any
+func (s *Store) Account(ctx context.Context, id string) (*models.Account, error) {
+	var account models.Account
+	var email, redditUsername sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT a.id, a.email, a.created_at, r.username FROM accounts a
+		LEFT JOIN reddit_links r ON r.account_id = a.id WHERE a.id = ?`, id).
+		Scan(&account.ID, &email, &account.CreatedAt, &redditUsername)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading account: %w", err)
+	}
+	account.Email = email.String
+	account.RedditUsername = redditUsername.String
+	return &account, nil
+}
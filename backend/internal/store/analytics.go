@@ -0,0 +1,174 @@
+// File: backend/internal/store/analytics.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// analyticsDateFormat names days in analytics
+const analyticsDateFormat = "2006-01-02"
+
+// latencyStats gathers the searches of a period, to summarize them
+type latencyStats struct {
+	latencies []float64
+	hits      int
+	tracked   int // searches that recorded whether they hit the cache
+}
+
+func (l *latencyStats) add(elapsed float64, cacheHit sql.NullBool) {
+	l.latencies = append(l.latencies, elapsed)
+	if cacheHit.Valid {
+		l.tracked++
+		if cacheHit.Bool {
+			l.hits++
+		}
+	}
+}
+
+func (l *latencyStats) summary() (models.LatencySummary, *float64) {
+	sort.Float64s(l.latencies)
+	latency := models.LatencySummary{
+		P50: percentile(l.latencies, 50),
+		P90: percentile(l.latencies, 90),
+		P99: percentile(l.latencies, 99),
+	}
+	if l.tracked == 0 {
+		return latency, nil
+	}
+	ratio := float64(l.hits) / float64(l.tracked)
+	return latency, &ratio
+}
+
+// percentile returns the nearest-rank percentile p of sorted values, or 0
+// when there are none
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Analytics summarizes the searches run by all owners from since until
+// until, by UTC day. Days start at since's UTC midnight; top caps the lists
+// of most used queries and subreddits.
+func (s *Store) Analytics(ctx context.Context, since, until time.Time, top int) (*models.Analytics, error) {
+	since = since.UTC().Truncate(24 * time.Hour)
+	analytics := &models.Analytics{
+		Since:         since.Unix(),
+		Until:         until.Unix(),
+		Days:          []models.DailyAnalytics{},
+		Models:        []models.UsageCount{},
+		TopQueries:    []models.UsageCount{},
+		TopSubreddits: []models.UsageCount{},
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT created_at, elapsed_time, cache_hit, COALESCE(json_extract(params, '$.modelName'), '')
+		FROM search_history
+		WHERE created_at >= ? AND created_at < ?`,
+		since.Unix(), until.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("error loading analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var total latencyStats
+	days := map[string]*latencyStats{}
+	modelCounts := map[string]int{}
+	for rows.Next() {
+		var (
+			createdAt int64
+			elapsed   float64
+			cacheHit  sql.NullBool
+			model     string
+		)
+		if err := rows.Scan(&createdAt, &elapsed, &cacheHit, &model); err != nil {
+			return nil, fmt.Errorf("error loading analytics: %w", err)
+		}
+		date := time.Unix(createdAt, 0).UTC().Format(analyticsDateFormat)
+		if days[date] == nil {
+			days[date] = &latencyStats{}
+		}
+		days[date].add(elapsed, cacheHit)
+		total.add(elapsed, cacheHit)
+		modelCounts[model]++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error loading analytics: %w", err)
+	}
+
+	analytics.Searches = len(total.latencies)
+	analytics.Latency, analytics.CacheHitRatio = total.summary()
+	for day := since; day.Before(until); day = day.Add(24 * time.Hour) {
+		daily := models.DailyAnalytics{Date: day.Format(analyticsDateFormat)}
+		if stats := days[daily.Date]; stats != nil {
+			daily.Searches = len(stats.latencies)
+			daily.Latency, daily.CacheHitRatio = stats.summary()
+		}
+		analytics.Days = append(analytics.Days, daily)
+	}
+	for model, count := range modelCounts {
+		analytics.Models = append(analytics.Models, models.UsageCount{Name: model, Count: count})
+	}
+	sort.Slice(analytics.Models, func(i, j int) bool {
+		a, b := analytics.Models[i], analytics.Models[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Name < b.Name
+	})
+
+	if analytics.TopQueries, err = s.usageCounts(ctx,
+		`SELECT lower(trim(query)) AS name, COUNT(*) AS uses
+		FROM search_history
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY name
+		ORDER BY uses DESC, name
+		LIMIT ?`,
+		since.Unix(), until.Unix(), top); err != nil {
+		return nil, err
+	}
+	if analytics.TopSubreddits, err = s.usageCounts(ctx,
+		`SELECT lower(subreddit.value) AS name, COUNT(*) AS uses
+		FROM search_history, json_each(search_history.params, '$.subreddits') AS subreddit
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY name
+		ORDER BY uses DESC, name
+		LIMIT ?`,
+		since.Unix(), until.Unix(), top); err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// usageCounts runs a query for name and count pairs
+func (s *Store) usageCounts(ctx context.Context, query string, args ...any) ([]models.UsageCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting usage: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.UsageCount{}
+	for rows.Next() {
+		var count models.UsageCount
+		if err := rows.Scan(&count.Name, &count.Count); err != nil {
+			return nil, fmt.Errorf("error loading usage count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error counting usage: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,69 @@
+// File: backend/internal/store/annotations.go
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// SetAnnotation creates or replaces owner's note on a result, setting the
+// annotation's timestamps
+func (s *Store) SetAnnotation(ctx context.Context, owner string, annotation *models.Annotation) error {
+	now := time.Now().Unix()
+	annotation.UpdatedAt = now
+
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO annotations (owner, snapshot_id, result_id, note, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (owner, snapshot_id, result_id) DO UPDATE SET note = excluded.note, updated_at = excluded.updated_at
+		RETURNING created_at`,
+		owner, annotation.SnapshotID, annotation.ResultID, annotation.Note, now, now).
+		Scan(&annotation.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving annotation: %w", err)
+	}
+	return nil
+}
+
+// ListAnnotations returns owner's notes on the results of a search, oldest
+// first
+func (s *Store) ListAnnotations(ctx context.Context, owner, snapshotID string) ([]models.Annotation, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT snapshot_id, result_id, note, created_at, updated_at FROM annotations
+		WHERE owner = ? AND snapshot_id = ?
+		ORDER BY created_at, result_id`,
+		owner, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing annotations: %w", err)
+	}
+	defer rows.Close()
+
+	annotations := []models.Annotation{}
+	for rows.Next() {
+		var annotation models.Annotation
+		if err := rows.Scan(&annotation.SnapshotID, &annotation.ResultID, &annotation.Note,
+			&annotation.CreatedAt, &annotation.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("error loading annotation: %w", err)
+		}
+		annotations = append(annotations, annotation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing annotations: %w", err)
+	}
+	return annotations, nil
+}
+
+// DeleteAnnotation removes owner's note on a result
+func (s *Store) DeleteAnnotation(ctx context.Context, owner, snapshotID, resultID string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM annotations WHERE owner = ? AND snapshot_id = ? AND result_id = ?`,
+		owner, snapshotID, resultID)
+	if err != nil {
+		return fmt.Errorf("error deleting annotation: %w", err)
+	}
+	return requireAffected(result)
+}
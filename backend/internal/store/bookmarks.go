@@ -0,0 +1,299 @@
+// File: backend/internal/store/bookmarks.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// bookmarkColumns selects a bookmark from bookmarks b, with its tags as a
+// sorted JSON array
+const bookmarkColumns = `b.id, b.kind, b.snapshot_id, b.query, b.content, b.note, b.created_at, b.updated_at,
+	(SELECT json_group_array(tag) FROM (SELECT tag FROM bookmark_tags WHERE bookmark_id = b.id ORDER BY tag))`
+
+// bookmarkContent is what a bookmark keeps of its search, stored as JSON
+type bookmarkContent struct {
+	Result    *models.SearchResult `json:"result,omitempty"`
+	Answer    string               `json:"answer,omitempty"`
+	Citations []models.Citation    `json:"citations,omitempty"`
+}
+
+// CreateBookmark stores a new bookmark for owner and assigns its ID and
+// timestamps. It returns ErrExists when owner already bookmarked the same
+// result or answer.
+func (s *Store) CreateBookmark(ctx context.Context, owner string, bookmark *models.Bookmark) error {
+	content, err := json.Marshal(bookmarkContent{
+		Result:    bookmark.Result,
+		Answer:    bookmark.Answer,
+		Citations: bookmark.Citations,
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding bookmark: %w", err)
+	}
+	resultID := ""
+	if bookmark.Result != nil {
+		resultID = bookmark.Result.ID
+	}
+
+	now := time.Now().Unix()
+	bookmark.ID = newID()
+	bookmark.CreatedAt = now
+	bookmark.UpdatedAt = now
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO bookmarks (id, owner, kind, snapshot_id, result_id, query, content, note, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (owner, snapshot_id, result_id) DO NOTHING`,
+		bookmark.ID, owner, bookmark.Kind, bookmark.SnapshotID, resultID, bookmark.Query,
+		string(content), bookmark.Note, bookmark.CreatedAt, bookmark.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving bookmark: %w", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error saving bookmark: %w", err)
+	}
+	if created == 0 {
+		return ErrExists
+	}
+
+	if err := indexBookmark(ctx, tx, bookmark); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing bookmark: %w", err)
+	}
+	return nil
+}
+
+// ListBookmarks returns one page of owner's bookmarks matching filter,
+// newest first, along with the total number that match
+func (s *Store) ListBookmarks(ctx context.Context, owner string, filter models.BookmarkFilter, limit, offset int) ([]models.Bookmark, int, error) {
+	where := []string{"b.owner = ?"}
+	args := []interface{}{owner}
+	if filter.Kind != "" {
+		where = append(where, "b.kind = ?")
+		args = append(args, filter.Kind)
+	}
+	for _, tag := range filter.Tags {
+		where = append(where, "EXISTS (SELECT 1 FROM bookmark_tags t WHERE t.bookmark_id = b.id AND t.tag = ?)")
+		args = append(args, tag)
+	}
+	if match := ftsQuery(filter.Text); match != "" {
+		where = append(where, "b.id IN (SELECT bookmark_id FROM bookmarks_fts WHERE bookmarks_fts MATCH ?)")
+		args = append(args, match)
+	}
+	conditions := strings.Join(where, " AND ")
+
+	var total int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bookmarks b WHERE `+conditions, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting bookmarks: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+bookmarkColumns+` FROM bookmarks b WHERE `+conditions+`
+		ORDER BY b.created_at DESC, b.id
+		LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []models.Bookmark{}
+	for rows.Next() {
+		bookmark, err := scanBookmark(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		bookmarks = append(bookmarks, *bookmark)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error listing bookmarks: %w", err)
+	}
+
+	return bookmarks, total, nil
+}
+
+// GetBookmark loads a single bookmark belonging to owner
+func (s *Store) GetBookmark(ctx context.Context, owner, id string) (*models.Bookmark, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+bookmarkColumns+` FROM bookmarks b WHERE b.owner = ? AND b.id = ?`, owner, id)
+
+	bookmark, err := scanBookmark(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return bookmark, err
+}
+
+// UpdateBookmark replaces the note and tags of an existing bookmark
+func (s *Store) UpdateBookmark(ctx context.Context, owner string, bookmark *models.Bookmark) error {
+	bookmark.UpdatedAt = time.Now().Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE bookmarks SET note = ?, updated_at = ? WHERE owner = ? AND id = ?`,
+		bookmark.Note, bookmark.UpdatedAt, owner, bookmark.ID)
+	if err != nil {
+		return fmt.Errorf("error updating bookmark: %w", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := indexBookmark(ctx, tx, bookmark); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing bookmark: %w", err)
+	}
+	return nil
+}
+
+// DeleteBookmark removes a bookmark belonging to owner
+func (s *Store) DeleteBookmark(ctx context.Context, owner, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Tags go with the bookmark; the full-text index has no foreign keys
+	result, err := tx.ExecContext(ctx, `DELETE FROM bookmarks WHERE owner = ? AND id = ?`, owner, id)
+	if err != nil {
+		return fmt.Errorf("error deleting bookmark: %w", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bookmarks_fts WHERE bookmark_id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting bookmark: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing bookmark deletion: %w", err)
+	}
+	return nil
+}
+
+// BookmarkTags lists the tags on owner's bookmarks, most used first
+func (s *Store) BookmarkTags(ctx context.Context, owner string) ([]models.TagCount, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.tag, COUNT(*) FROM bookmark_tags t JOIN bookmarks b ON b.id = t.bookmark_id
+		WHERE b.owner = ?
+		GROUP BY t.tag
+		ORDER BY COUNT(*) DESC, t.tag`,
+		owner)
+	if err != nil {
+		return nil, fmt.Errorf("error listing bookmark tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("error loading bookmark tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing bookmark tags: %w", err)
+	}
+	return tags, nil
+}
+
+// indexBookmark replaces a bookmark's tags and full-text entry
+func indexBookmark(ctx context.Context, tx *sql.Tx, bookmark *models.Bookmark) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bookmark_tags WHERE bookmark_id = ?`, bookmark.ID); err != nil {
+		return fmt.Errorf("error saving bookmark tags: %w", err)
+	}
+	for _, tag := range bookmark.Tags {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO bookmark_tags (bookmark_id, tag) VALUES (?, ?) ON CONFLICT DO NOTHING`,
+			bookmark.ID, tag); err != nil {
+			return fmt.Errorf("error saving bookmark tags: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bookmarks_fts WHERE bookmark_id = ?`, bookmark.ID); err != nil {
+		return fmt.Errorf("error indexing bookmark: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO bookmarks_fts (bookmark_id, text) VALUES (?, ?)`,
+		bookmark.ID, bookmarkText(bookmark)); err != nil {
+		return fmt.Errorf("error indexing bookmark: %w", err)
+	}
+	return nil
+}
+
+// bookmarkText is what full-text filtering matches a bookmark against
+func bookmarkText(bookmark *models.Bookmark) string {
+	parts := []string{bookmark.Query, bookmark.Answer, bookmark.Note, strings.Join(bookmark.Tags, " ")}
+	if result := bookmark.Result; result != nil {
+		parts = append(parts, result.Title, result.Content, result.Subreddit, result.Author)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ftsQuery turns free text into an FTS5 query matching every word as a
+// prefix, so user input can't break the query syntax
+func ftsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// scanBookmark reads a bookmark selected with bookmarkColumns
+func scanBookmark(row rowScanner) (*models.Bookmark, error) {
+	var bookmark models.Bookmark
+	var content, tags string
+	err := row.Scan(&bookmark.ID, &bookmark.Kind, &bookmark.SnapshotID, &bookmark.Query, &content,
+		&bookmark.Note, &bookmark.CreatedAt, &bookmark.UpdatedAt, &tags)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading bookmark: %w", err)
+	}
+
+	var decoded bookmarkContent
+	if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+		return nil, fmt.Errorf("error decoding bookmark: %w", err)
+	}
+	bookmark.Result = decoded.Result
+	bookmark.Answer = decoded.Answer
+	bookmark.Citations = decoded.Citations
+
+	if err := json.Unmarshal([]byte(tags), &bookmark.Tags); err != nil {
+		return nil, fmt.Errorf("error decoding bookmark tags: %w", err)
+	}
+	return &bookmark, nil
+}
@@ -0,0 +1,246 @@
+// File: backend/internal/store/collections.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// CreateCollection stores a new, empty collection for owner and assigns its
+// ID and timestamps
+func (s *Store) CreateCollection(ctx context.Context, owner string, collection *models.Collection) error {
+	now := time.Now().Unix()
+	collection.ID = newID()
+	collection.CreatedAt = now
+	collection.UpdatedAt = now
+	collection.Searches = []models.CollectionSearch{}
+	collection.Results = []models.CollectionResult{}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO collections (id, owner, name, description, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		collection.ID, owner, collection.Name, collection.Description, collection.CreatedAt, collection.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving collection: %w", err)
+	}
+	return nil
+}
+
+// ListCollections summarizes owner's collections, most recently changed
+// first
+func (s *Store) ListCollections(ctx context.Context, owner string) ([]models.CollectionSummary, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT c.id, c.name, c.description, c.created_at, c.updated_at,
+			COALESCE(json_extract(c.report, '$.generatedAt'), 0),
+			(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id AND i.result_id = ''),
+			(SELECT COUNT(*) FROM collection_items i WHERE i.collection_id = c.id AND i.result_id != '')
+		FROM collections c WHERE c.owner = ?
+		ORDER BY c.updated_at DESC, c.id`,
+		owner)
+	if err != nil {
+		return nil, fmt.Errorf("error listing collections: %w", err)
+	}
+	defer rows.Close()
+
+	collections := []models.CollectionSummary{}
+	for rows.Next() {
+		var summary models.CollectionSummary
+		if err := rows.Scan(&summary.ID, &summary.Name, &summary.Description, &summary.CreatedAt,
+			&summary.UpdatedAt, &summary.ReportedAt, &summary.Searches, &summary.Results); err != nil {
+			return nil, fmt.Errorf("error loading collection: %w", err)
+		}
+		collections = append(collections, summary)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing collections: %w", err)
+	}
+	return collections, nil
+}
+
+// GetCollection loads one of owner's collections with its items, oldest
+// first, and latest report
+func (s *Store) GetCollection(ctx context.Context, owner, id string) (*models.Collection, error) {
+	var collection models.Collection
+	var report sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, name, description, report, created_at, updated_at FROM collections WHERE owner = ? AND id = ?`,
+		owner, id).
+		Scan(&collection.ID, &collection.Name, &collection.Description, &report, &collection.CreatedAt, &collection.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading collection: %w", err)
+	}
+	if report.Valid {
+		collection.Report = &models.CollectionReport{}
+		if err := json.Unmarshal([]byte(report.String), collection.Report); err != nil {
+			return nil, fmt.Errorf("error decoding collection report: %w", err)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT snapshot_id, result_id, query, result, added_at FROM collection_items
+		WHERE collection_id = ? ORDER BY added_at, rowid`,
+		id)
+	if err != nil {
+		return nil, fmt.Errorf("error loading collection items: %w", err)
+	}
+	defer rows.Close()
+
+	collection.Searches = []models.CollectionSearch{}
+	collection.Results = []models.CollectionResult{}
+	for rows.Next() {
+		var snapshotID, resultID, query string
+		var result sql.NullString
+		var addedAt int64
+		if err := rows.Scan(&snapshotID, &resultID, &query, &result, &addedAt); err != nil {
+			return nil, fmt.Errorf("error loading collection item: %w", err)
+		}
+		if resultID == "" {
+			collection.Searches = append(collection.Searches, models.CollectionSearch{
+				SnapshotID: snapshotID,
+				Query:      query,
+				AddedAt:    addedAt,
+			})
+			continue
+		}
+
+		item := models.CollectionResult{SnapshotID: snapshotID, Query: query, AddedAt: addedAt}
+		if err := json.Unmarshal([]byte(result.String), &item.Result); err != nil {
+			return nil, fmt.Errorf("error decoding collection result: %w", err)
+		}
+		collection.Results = append(collection.Results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error loading collection items: %w", err)
+	}
+
+	return &collection, nil
+}
+
+// UpdateCollection replaces the name and description of an existing
+// collection
+func (s *Store) UpdateCollection(ctx context.Context, owner string, collection *models.Collection) error {
+	collection.UpdatedAt = time.Now().Unix()
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE collections SET name = ?, description = ?, updated_at = ? WHERE owner = ? AND id = ?`,
+		collection.Name, collection.Description, collection.UpdatedAt, owner, collection.ID)
+	if err != nil {
+		return fmt.Errorf("error updating collection: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// DeleteCollection removes one of owner's collections and its items
+func (s *Store) DeleteCollection(ctx context.Context, owner, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM collections WHERE owner = ? AND id = ?`, owner, id)
+	if err != nil {
+		return fmt.Errorf("error deleting collection: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// AddCollectionItems adds searches and results to one of owner's
+// collections, setting their AddedAt. Items already in the collection are
+// left as they are.
+func (s *Store) AddCollectionItems(ctx context.Context, owner, id string, searches []models.CollectionSearch, results []models.CollectionResult) error {
+	now := time.Now().Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := touchCollection(ctx, tx, owner, id, now); err != nil {
+		return err
+	}
+
+	const insert = `INSERT INTO collection_items (collection_id, snapshot_id, result_id, query, result, added_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`
+	for i := range searches {
+		searches[i].AddedAt = now
+		if _, err := tx.ExecContext(ctx, insert, id, searches[i].SnapshotID, "", searches[i].Query, nil, now); err != nil {
+			return fmt.Errorf("error adding search to collection: %w", err)
+		}
+	}
+	for i := range results {
+		results[i].AddedAt = now
+		payload, err := json.Marshal(results[i].Result)
+		if err != nil {
+			return fmt.Errorf("error encoding collection result: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, insert, id, results[i].SnapshotID, results[i].Result.ID, results[i].Query, string(payload), now); err != nil {
+			return fmt.Errorf("error adding result to collection: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing collection items: %w", err)
+	}
+	return nil
+}
+
+// RemoveCollectionItem removes a search, or with a resultID one result, from
+// one of owner's collections
+func (s *Store) RemoveCollectionItem(ctx context.Context, owner, id, snapshotID, resultID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := touchCollection(ctx, tx, owner, id, time.Now().Unix()); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx,
+		`DELETE FROM collection_items WHERE collection_id = ? AND snapshot_id = ? AND result_id = ?`,
+		id, snapshotID, resultID)
+	if err != nil {
+		return fmt.Errorf("error removing collection item: %w", err)
+	}
+	if err := requireAffected(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing collection item removal: %w", err)
+	}
+	return nil
+}
+
+// SaveCollectionReport replaces the latest report of one of owner's
+// collections
+func (s *Store) SaveCollectionReport(ctx context.Context, owner, id string, report *models.CollectionReport) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("error encoding collection report: %w", err)
+	}
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE collections SET report = ? WHERE owner = ? AND id = ?`, string(payload), owner, id)
+	if err != nil {
+		return fmt.Errorf("error saving collection report: %w", err)
+	}
+	return requireAffected(result)
+}
+
+// touchCollection marks one of owner's collections as changed, returning
+// ErrNotFound when owner has no such collection
+func touchCollection(ctx context.Context, tx *sql.Tx, owner, id string, now int64) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE collections SET updated_at = ? WHERE owner = ? AND id = ?`, now, owner, id)
+	if err != nil {
+		return fmt.Errorf("error updating collection: %w", err)
+	}
+	return requireAffected(result)
+}
@@ -0,0 +1,50 @@
+// File: backend/internal/store/digests.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// SaveDigest stores a digest, replacing the previous one for its topic
+func (s *Store) SaveDigest(ctx context.Context, digest *models.Digest) error {
+	data, err := json.Marshal(digest)
+	if err != nil {
+		return fmt.Errorf("error encoding digest: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO digests (topic, data, generated_at) VALUES (?, ?, ?)
+		ON CONFLICT (topic) DO UPDATE SET data = excluded.data, generated_at = excluded.generated_at`,
+		digest.Topic, string(data), digest.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("error saving digest: %w", err)
+	}
+
+	return nil
+}
+
+// GetDigest returns the latest digest for a topic, or ErrNotFound if none
+// has been generated yet
+func (s *Store) GetDigest(ctx context.Context, topic string) (*models.Digest, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM digests WHERE topic = ?`, topic).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading digest: %w", err)
+	}
+
+	var digest models.Digest
+	if err := json.Unmarshal([]byte(data), &digest); err != nil {
+		return nil, fmt.Errorf("error decoding digest: %w", err)
+	}
+	return &digest, nil
+}
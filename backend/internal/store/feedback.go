@@ -0,0 +1,37 @@
+// File: backend/internal/store/feedback.go
+
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// SaveFeedback stores a judgment from owner and assigns it an ID. The search
+// it refers to must already exist as a snapshot.
+func (s *Store) SaveFeedback(ctx context.Context, owner string, feedback *models.Feedback) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM snapshots WHERE id = ?)`, feedback.SearchID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("error checking snapshot: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	feedback.ID = newID()
+	feedback.CreatedAt = time.Now().Unix()
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO feedback (id, search_id, owner, rating, comment, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		feedback.ID, feedback.SearchID, owner, feedback.Rating, feedback.Comment, feedback.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving feedback: %w", err)
+	}
+
+	return nil
+}
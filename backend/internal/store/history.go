@@ -0,0 +1,104 @@
+// File: backend/internal/store/history.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// AddHistoryEntry records a search run by owner and assigns the entry an ID
+func (s *Store) AddHistoryEntry(ctx context.Context, owner string, entry *models.HistoryEntry) error {
+	params, err := json.Marshal(entry.Params)
+	if err != nil {
+		return fmt.Errorf("error encoding history params: %w", err)
+	}
+
+	entry.ID = newID()
+	if entry.CreatedAt == 0 {
+		entry.CreatedAt = time.Now().Unix()
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO search_history (id, owner, query, params, elapsed_time, result_count, snapshot_id, cache_hit, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.ID, owner, entry.Query, string(params), entry.ElapsedTime, entry.ResultCount,
+		entry.SnapshotID, entry.CacheHit, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving history entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListHistory returns one page of owner's search history, newest first, along
+// with the total number of entries
+func (s *Store) ListHistory(ctx context.Context, owner string, limit, offset int) ([]models.HistoryEntry, int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM search_history WHERE owner = ?`, owner).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error counting history: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, query, params, elapsed_time, result_count, snapshot_id, cache_hit, created_at
+		FROM search_history WHERE owner = ?
+		ORDER BY created_at DESC, id
+		LIMIT ? OFFSET ?`,
+		owner, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing history: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.HistoryEntry{}
+	for rows.Next() {
+		var entry models.HistoryEntry
+		var params string
+		var cacheHit sql.NullBool
+		if err := rows.Scan(&entry.ID, &entry.Query, &params, &entry.ElapsedTime,
+			&entry.ResultCount, &entry.SnapshotID, &cacheHit, &entry.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("error loading history entry: %w", err)
+		}
+		if cacheHit.Valid {
+			entry.CacheHit = &cacheHit.Bool
+		}
+		if err := json.Unmarshal([]byte(params), &entry.Params); err != nil {
+			return nil, 0, fmt.Errorf("error decoding history params: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error listing history: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+// DeleteHistoryEntry removes a single history entry belonging to owner
+func (s *Store) DeleteHistoryEntry(ctx context.Context, owner, id string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM search_history WHERE owner = ? AND id = ?`, owner, id)
+	if err != nil {
+		return fmt.Errorf("error deleting history entry: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// ClearHistory removes all of owner's history and reports how many entries
+// were deleted
+func (s *Store) ClearHistory(ctx context.Context, owner string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM search_history WHERE owner = ?`, owner)
+	if err != nil {
+		return 0, fmt.Errorf("error clearing history: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,108 @@
+// File: backend/internal/store/indexed_posts.go
+
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// UpsertIndexedPosts stores posts fetched by the indexer, replacing earlier
+// copies so scores and comment counts stay current
+func (s *Store) UpsertIndexedPosts(ctx context.Context, posts []models.SearchResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	for _, post := range posts {
+		data, err := json.Marshal(post)
+		if err != nil {
+			return fmt.Errorf("error encoding post %s: %w", post.ID, err)
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO indexed_posts (id, subreddit, data, created_utc, indexed_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET data = excluded.data, indexed_at = excluded.indexed_at`,
+			post.ID, strings.ToLower(post.Subreddit), string(data), post.CreatedUTC, now)
+		if err != nil {
+			return fmt.Errorf("error storing post %s: %w", post.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetIndexedPosts returns the indexed posts with the given IDs, in the same
+// order. IDs that are no longer indexed are skipped.
+func (s *Store) GetIndexedPosts(ctx context.Context, ids []string) ([]models.SearchResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	rows, err := s.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT id, data FROM indexed_posts WHERE id IN (%s)`, placeholders), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying indexed posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := make(map[string]models.SearchResult, len(ids))
+	for rows.Next() {
+		var id, data string
+		if err := rows.Scan(&id, &data); err != nil {
+			return nil, fmt.Errorf("error scanning indexed post: %w", err)
+		}
+
+		var post models.SearchResult
+		if err := json.Unmarshal([]byte(data), &post); err != nil {
+			return nil, fmt.Errorf("error decoding indexed post %s: %w", id, err)
+		}
+		posts[id] = post
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading indexed posts: %w", err)
+	}
+
+	ordered := make([]models.SearchResult, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := posts[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+	return ordered, nil
+}
+
+// PruneIndexedPosts deletes posts created before the given Unix time and
+// returns their IDs so related data (embeddings) can be removed too
+func (s *Store) PruneIndexedPosts(ctx context.Context, before int64) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`DELETE FROM indexed_posts WHERE created_utc < ? RETURNING id`, before)
+	if err != nil {
+		return nil, fmt.Errorf("error pruning indexed posts: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning pruned post: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,181 @@
+// File: backend/internal/store/migrations.go
+
+package store
+
+// migrations are applied in order; each entry bumps the schema version by one.
+// Never edit an entry that has shipped - append a new one instead.
+var migrations = []string{
+	// 1: response snapshots
+	`CREATE TABLE snapshots (
+		id         TEXT PRIMARY KEY,
+		query      TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		payload    TEXT NOT NULL
+	);
+	CREATE INDEX idx_snapshots_created_at ON snapshots(created_at);`,
+
+	// 2: saved searches, scoped to the client that created them
+	`CREATE TABLE saved_searches (
+		id           TEXT PRIMARY KEY,
+		owner        TEXT NOT NULL,
+		name         TEXT NOT NULL,
+		query        TEXT NOT NULL,
+		search_mode  TEXT NOT NULL,
+		model_name   TEXT NOT NULL,
+		result_limit INTEGER NOT NULL DEFAULT 0,
+		subreddits   TEXT NOT NULL DEFAULT '[]',
+		created_at   INTEGER NOT NULL,
+		updated_at   INTEGER NOT NULL
+	);
+	CREATE INDEX idx_saved_searches_owner ON saved_searches(owner, created_at);`,
+
+	// 3: search history, scoped to the client that ran the search
+	`CREATE TABLE search_history (
+		id           TEXT PRIMARY KEY,
+		owner        TEXT NOT NULL,
+		query        TEXT NOT NULL,
+		params       TEXT NOT NULL,
+		elapsed_time REAL NOT NULL,
+		result_count INTEGER NOT NULL,
+		snapshot_id  TEXT NOT NULL DEFAULT '',
+		created_at   INTEGER NOT NULL
+	);
+	CREATE INDEX idx_search_history_owner ON search_history(owner, created_at);`,
+
+	// 4: answer feedback, removed along with the snapshot it judges
+	`CREATE TABLE feedback (
+		id         TEXT PRIMARY KEY,
+		search_id  TEXT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+		owner      TEXT NOT NULL,
+		rating     TEXT NOT NULL,
+		comment    TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX idx_feedback_search_id ON feedback(search_id);`,
+
+	// 5: posts fetched by the background subreddit indexer
+	`CREATE TABLE indexed_posts (
+		id          TEXT PRIMARY KEY,
+		subreddit   TEXT NOT NULL,
+		data        TEXT NOT NULL,
+		created_utc INTEGER NOT NULL,
+		indexed_at  INTEGER NOT NULL
+	);
+	CREATE INDEX idx_indexed_posts_created ON indexed_posts(created_utc);`,
+
+	// 6: latest generated digest per topic
+	`CREATE TABLE digests (
+		topic        TEXT PRIMARY KEY,
+		data         TEXT NOT NULL,
+		generated_at INTEGER NOT NULL
+	);`,
+
+	// 7: requests and AI tokens used per client and UTC day, for quotas
+	`CREATE TABLE quota_usage (
+		owner    TEXT NOT NULL,
+		day      TEXT NOT NULL,
+		requests INTEGER NOT NULL DEFAULT 0,
+		tokens   INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (owner, day)
+	);
+	CREATE INDEX idx_quota_usage_day ON quota_usage(day);`,
+
+	// 8: user accounts; emails are unique regardless of case
+	`CREATE TABLE accounts (
+		id            TEXT PRIMARY KEY,
+		email         TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		password_hash TEXT NOT NULL,
+		created_at    INTEGER NOT NULL
+	);`,
+
+	// 9: accounts signed in with Reddit have no email or password, and keep
+	// their encrypted Reddit tokens in reddit_links
+	`CREATE TABLE accounts_new (
+		id            TEXT PRIMARY KEY,
+		email         TEXT UNIQUE COLLATE NOCASE,
+		password_hash TEXT NOT NULL DEFAULT '',
+		created_at    INTEGER NOT NULL
+	);
+	INSERT INTO accounts_new (id, email, password_hash, created_at)
+		SELECT id, email, password_hash, created_at FROM accounts;
+	DROP TABLE accounts;
+	ALTER TABLE accounts_new RENAME TO accounts;
+	CREATE TABLE reddit_links (
+		account_id TEXT PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+		username   TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		tokens     TEXT NOT NULL,
+		linked_at  INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);`,
+
+	// 10: bookmarked results and answers, with their tags and a full-text
+	// index over their content, notes and tags
+	`CREATE TABLE bookmarks (
+		id          TEXT PRIMARY KEY,
+		owner       TEXT NOT NULL,
+		kind        TEXT NOT NULL,
+		snapshot_id TEXT NOT NULL,
+		result_id   TEXT NOT NULL DEFAULT '',
+		query       TEXT NOT NULL,
+		content     TEXT NOT NULL,
+		note        TEXT NOT NULL DEFAULT '',
+		created_at  INTEGER NOT NULL,
+		updated_at  INTEGER NOT NULL,
+		UNIQUE (owner, snapshot_id, result_id)
+	);
+	CREATE INDEX idx_bookmarks_owner_created ON bookmarks(owner, created_at);
+	CREATE TABLE bookmark_tags (
+		bookmark_id TEXT NOT NULL REFERENCES bookmarks(id) ON DELETE CASCADE,
+		tag         TEXT NOT NULL,
+		PRIMARY KEY (bookmark_id, tag)
+	);
+	CREATE INDEX idx_bookmark_tags_tag ON bookmark_tags(tag);
+	CREATE VIRTUAL TABLE bookmarks_fts USING fts5(bookmark_id UNINDEXED, text);`,
+
+	// 11: research collections of whole searches and picked results, with
+	// the latest synthesized report as JSON
+	`CREATE TABLE collections (
+		id          TEXT PRIMARY KEY,
+		owner       TEXT NOT NULL,
+		name        TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		report      TEXT,
+		created_at  INTEGER NOT NULL,
+		updated_at  INTEGER NOT NULL
+	);
+	CREATE INDEX idx_collections_owner_updated ON collections(owner, updated_at);
+	CREATE TABLE collection_items (
+		collection_id TEXT NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+		snapshot_id   TEXT NOT NULL,
+		result_id     TEXT NOT NULL DEFAULT '',
+		query         TEXT NOT NULL,
+		result        TEXT,
+		added_at      INTEGER NOT NULL,
+		PRIMARY KEY (collection_id, snapshot_id, result_id)
+	);`,
+
+	// 12: private notes on results of a search, alongside search history
+	`CREATE TABLE annotations (
+		owner       TEXT NOT NULL,
+		snapshot_id TEXT NOT NULL,
+		result_id   TEXT NOT NULL,
+		note        TEXT NOT NULL,
+		created_at  INTEGER NOT NULL,
+		updated_at  INTEGER NOT NULL,
+		PRIMARY KEY (owner, snapshot_id, result_id)
+	);`,
+
+	// 13: whether each search's Reddit results came from cache, NULL where
+	// unknown, and an index for analytics across owners
+	`ALTER TABLE search_history ADD COLUMN cache_hit INTEGER;
+	CREATE INDEX idx_search_history_created ON search_history(created_at);`,
+
+	// 14: estimated AI spend per provider and UTC day, in millionths of a
+	// dollar
+	`CREATE TABLE ai_spend (
+		provider    TEXT NOT NULL,
+		day         TEXT NOT NULL,
+		cost_micros INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (provider, day)
+	);`,
+}
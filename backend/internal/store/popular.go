@@ -0,0 +1,105 @@
+// File: backend/internal/store/popular.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// PopularSearches returns the searches run most often since the given time,
+// across all owners, most frequent first. Searches count as the same when
+// their query, mode, limit and subreddits match, as those decide which Reddit
+// results they need.
+func (s *Store) PopularSearches(ctx context.Context, since time.Time, limit int) ([]models.SearchRequest, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT query,
+			json_extract(params, '$.searchMode') AS mode,
+			json_extract(params, '$.limit') AS result_limit,
+			json_extract(params, '$.subreddits') AS subreddits,
+			COUNT(*) AS runs
+		FROM search_history
+		WHERE created_at >= ?
+		GROUP BY query, mode, result_limit, subreddits
+		ORDER BY runs DESC, MAX(created_at) DESC
+		LIMIT ?`,
+		since.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing popular searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []models.SearchRequest
+	for rows.Next() {
+		var (
+			req        models.SearchRequest
+			mode       sql.NullString
+			reqLimit   sql.NullInt64
+			subreddits sql.NullString
+			runs       int
+		)
+		if err := rows.Scan(&req.Query, &mode, &reqLimit, &subreddits, &runs); err != nil {
+			return nil, fmt.Errorf("error loading popular search: %w", err)
+		}
+		req.SearchMode = mode.String
+		req.Limit = int(reqLimit.Int64)
+		if subreddits.Valid {
+			if err := json.Unmarshal([]byte(subreddits.String), &req.Subreddits); err != nil {
+				return nil, fmt.Errorf("error decoding popular search subreddits: %w", err)
+			}
+		}
+		searches = append(searches, req)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing popular searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// likeEscaper escapes LIKE wildcards so a prefix matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SuggestQueries returns past queries since the given time that start with
+// prefix, ignoring case, most often run first. So that one client's searches
+// aren't shown to others, a query is only suggested when at least minOwners
+// clients ran it or owner ran it themselves. Each query is returned as it
+// was last written.
+func (s *Store) SuggestQueries(ctx context.Context, owner, prefix string, since time.Time, minOwners, limit int) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT query, MAX(created_at)
+		FROM search_history
+		WHERE created_at >= ? AND lower(query) LIKE ? ESCAPE '\'
+		GROUP BY lower(trim(query))
+		HAVING COUNT(DISTINCT owner) >= ? OR SUM(owner = ?) > 0
+		ORDER BY COUNT(*) DESC, MAX(created_at) DESC
+		LIMIT ?`,
+		since.Unix(), likeEscaper.Replace(strings.ToLower(prefix))+"%", minOwners, owner, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error suggesting queries: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []string{}
+	for rows.Next() {
+		var (
+			query    string
+			lastSeen int64
+		)
+		if err := rows.Scan(&query, &lastSeen); err != nil {
+			return nil, fmt.Errorf("error loading suggested query: %w", err)
+		}
+		queries = append(queries, strings.TrimSpace(query))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error suggesting queries: %w", err)
+	}
+
+	return queries, nil
+}
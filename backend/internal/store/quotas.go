@@ -0,0 +1,55 @@
+// File: backend/internal/store/quotas.go
+
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// QuotaDayLayout formats the UTC day quota usage is recorded under
+const QuotaDayLayout = "2006-01-02"
+
+// AddQuotaUsage adds requests and tokens to owner's usage on day, formatted
+// with QuotaDayLayout
+func (s *Store) AddQuotaUsage(ctx context.Context, owner, day string, requests, tokens int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO quota_usage (owner, day, requests, tokens) VALUES (?, ?, ?, ?)
+		ON CONFLICT (owner, day) DO UPDATE SET
+			requests = requests + excluded.requests,
+			tokens = tokens + excluded.tokens`,
+		owner, day, requests, tokens)
+	if err != nil {
+		return fmt.Errorf("error recording quota usage: %w", err)
+	}
+	return nil
+}
+
+// QuotaUsage returns owner's usage on day and in the month containing it
+func (s *Store) QuotaUsage(ctx context.Context, owner, day string) (daily, monthly models.QuotaUsage, err error) {
+	month := day[:len("2006-01")]
+	err = s.db.QueryRowContext(ctx,
+		`SELECT
+			COALESCE(SUM(CASE WHEN day = ? THEN requests END), 0),
+			COALESCE(SUM(CASE WHEN day = ? THEN tokens END), 0),
+			COALESCE(SUM(requests), 0),
+			COALESCE(SUM(tokens), 0)
+		FROM quota_usage WHERE owner = ? AND day LIKE ? || '-%'`,
+		day, day, owner, month).Scan(&daily.Requests, &daily.Tokens, &monthly.Requests, &monthly.Tokens)
+	if err != nil {
+		return daily, monthly, fmt.Errorf("error reading quota usage: %w", err)
+	}
+	return daily, monthly, nil
+}
+
+// PruneQuotaUsage deletes usage recorded before day and returns how many
+// rows were removed
+func (s *Store) PruneQuotaUsage(ctx context.Context, day string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM quota_usage WHERE day < ?`, day)
+	if err != nil {
+		return 0, fmt.Errorf("error pruning quota usage: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,94 @@
+// File: backend/internal/store/reddit_links.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// CreateRedditAccount creates an account for a Reddit user signing in for
+// the first time and links it. It returns ErrExists when the Reddit user is
+// already linked to an account.
+func (s *Store) CreateRedditAccount(ctx context.Context, link *models.RedditLink) (*models.Account, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	account := &models.Account{RedditUsername: link.Username}
+	if err := createAccount(ctx, tx, account, ""); err != nil {
+		return nil, err
+	}
+
+	link.AccountID = account.ID
+	link.LinkedAt = account.CreatedAt
+	link.UpdatedAt = account.CreatedAt
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO reddit_links (account_id, username, tokens, linked_at, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (username) DO NOTHING`,
+		link.AccountID, link.Username, link.Tokens, link.LinkedAt, link.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("error linking Reddit account: %w", err)
+	}
+	linked, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error linking Reddit account: %w", err)
+	}
+	if linked == 0 {
+		return nil, ErrExists
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing Reddit account: %w", err)
+	}
+	return account, nil
+}
+
+// RedditLink returns the Reddit account linked to an account
+func (s *Store) RedditLink(ctx context.Context, accountID string) (*models.RedditLink, error) {
+	return s.redditLink(ctx, `WHERE account_id = ?`, accountID)
+}
+
+// RedditLinkByUsername returns the link for a Reddit user, ignoring case
+func (s *Store) RedditLinkByUsername(ctx context.Context, username string) (*models.RedditLink, error) {
+	return s.redditLink(ctx, `WHERE username = ?`, username)
+}
+
+func (s *Store) redditLink(ctx context.Context, where string, arg string) (*models.RedditLink, error) {
+	var link models.RedditLink
+	err := s.db.QueryRowContext(ctx,
+		`SELECT account_id, username, tokens, linked_at, updated_at FROM reddit_links `+where, arg).
+		Scan(&link.AccountID, &link.Username, &link.Tokens, &link.LinkedAt, &link.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading Reddit link: %w", err)
+	}
+	return &link, nil
+}
+
+// UpdateRedditTokens replaces the sealed tokens of an account's Reddit link
+func (s *Store) UpdateRedditTokens(ctx context.Context, accountID, tokens string) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE reddit_links SET tokens = ?, updated_at = ? WHERE account_id = ?`,
+		tokens, time.Now().Unix(), accountID)
+	if err != nil {
+		return fmt.Errorf("error updating Reddit tokens: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error updating Reddit tokens: %w", err)
+	}
+	if updated == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+// File: backend/internal/store/saved_searches.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+const savedSearchColumns = `id, name, query, search_mode, model_name, result_limit, subreddits, created_at, updated_at`
+
+// CreateSavedSearch stores a new saved search for owner and assigns its ID
+// and timestamps
+func (s *Store) CreateSavedSearch(ctx context.Context, owner string, search *models.SavedSearch) error {
+	subreddits, err := json.Marshal(search.Subreddits)
+	if err != nil {
+		return fmt.Errorf("error encoding subreddits: %w", err)
+	}
+
+	now := time.Now().Unix()
+	search.ID = newID()
+	search.CreatedAt = now
+	search.UpdatedAt = now
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO saved_searches (id, owner, name, query, search_mode, model_name, result_limit, subreddits, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		search.ID, owner, search.Name, search.Query, search.SearchMode, search.ModelName,
+		search.Limit, string(subreddits), search.CreatedAt, search.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving search: %w", err)
+	}
+
+	return nil
+}
+
+// ListSavedSearches returns all saved searches belonging to owner, newest first
+func (s *Store) ListSavedSearches(ctx context.Context, owner string) ([]models.SavedSearch, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+savedSearchColumns+` FROM saved_searches WHERE owner = ? ORDER BY created_at DESC, id`,
+		owner)
+	if err != nil {
+		return nil, fmt.Errorf("error listing saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []models.SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, *search)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error listing saved searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// GetSavedSearch loads a single saved search belonging to owner
+func (s *Store) GetSavedSearch(ctx context.Context, owner, id string) (*models.SavedSearch, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+savedSearchColumns+` FROM saved_searches WHERE owner = ? AND id = ?`,
+		owner, id)
+
+	search, err := scanSavedSearch(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return search, err
+}
+
+// UpdateSavedSearch replaces the name and parameters of an existing saved search
+func (s *Store) UpdateSavedSearch(ctx context.Context, owner string, search *models.SavedSearch) error {
+	subreddits, err := json.Marshal(search.Subreddits)
+	if err != nil {
+		return fmt.Errorf("error encoding subreddits: %w", err)
+	}
+
+	search.UpdatedAt = time.Now().Unix()
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE saved_searches
+		SET name = ?, query = ?, search_mode = ?, model_name = ?, result_limit = ?, subreddits = ?, updated_at = ?
+		WHERE owner = ? AND id = ?`,
+		search.Name, search.Query, search.SearchMode, search.ModelName, search.Limit,
+		string(subreddits), search.UpdatedAt, owner, search.ID)
+	if err != nil {
+		return fmt.Errorf("error updating saved search: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// DeleteSavedSearch removes a saved search belonging to owner
+func (s *Store) DeleteSavedSearch(ctx context.Context, owner, id string) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM saved_searches WHERE owner = ? AND id = ?`, owner, id)
+	if err != nil {
+		return fmt.Errorf("error deleting saved search: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSavedSearch reads a saved search selected with savedSearchColumns
+func scanSavedSearch(row rowScanner) (*models.SavedSearch, error) {
+	var search models.SavedSearch
+	var subreddits string
+	err := row.Scan(&search.ID, &search.Name, &search.Query, &search.SearchMode, &search.ModelName,
+		&search.Limit, &subreddits, &search.CreatedAt, &search.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading saved search: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(subreddits), &search.Subreddits); err != nil {
+		return nil, fmt.Errorf("error decoding subreddits: %w", err)
+	}
+
+	return &search, nil
+}
+
+// requireAffected reports ErrNotFound when a write matched no rows
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking affected rows: %w", err)
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// File: backend/internal/store/snapshots.go
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// SaveSnapshot persists a complete search response and assigns it an ID
+func (s *Store) SaveSnapshot(ctx context.Context, response *models.SearchResponse) (string, error) {
+	if response.ID == "" {
+		response.ID = newID()
+	}
+
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("error encoding snapshot: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO snapshots (id, query, created_at, payload) VALUES (?, ?, ?, ?)`,
+		response.ID, response.RequestParams.Query, time.Now().Unix(), string(payload))
+	if err != nil {
+		return "", fmt.Errorf("error saving snapshot: %w", err)
+	}
+
+	return response.ID, nil
+}
+
+// GetSnapshot loads a previously saved search response
+func (s *Store) GetSnapshot(ctx context.Context, id string) (*models.SearchResponse, error) {
+	var payload string
+	err := s.db.QueryRowContext(ctx, `SELECT payload FROM snapshots WHERE id = ?`, id).Scan(&payload)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading snapshot: %w", err)
+	}
+
+	var response models.SearchResponse
+	if err := json.Unmarshal([]byte(payload), &response); err != nil {
+		return nil, fmt.Errorf("error decoding snapshot: %w", err)
+	}
+
+	return &response, nil
+}
+
+// prunableSnapshots selects snapshots created before a Unix time, except
+// those of searches in a research collection
+const prunableSnapshots = `SELECT id FROM snapshots WHERE created_at < ?
+	AND id NOT IN (SELECT snapshot_id FROM collection_items WHERE result_id = '')`
+
+// PruneSnapshots deletes snapshots created before the given time and
+// returns how many were removed. Snapshots of searches in a collection are
+// kept. Feedback and notes on a pruned snapshot go with it, and history
+// entries no longer refer to it.
+func (s *Store) PruneSnapshots(ctx context.Context, before time.Time) (int64, error) {
+	cutoff := before.Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM annotations WHERE snapshot_id IN (`+prunableSnapshots+`)`, cutoff); err != nil {
+		return 0, fmt.Errorf("error pruning annotations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE search_history SET snapshot_id = '' WHERE snapshot_id IN (`+prunableSnapshots+`)`, cutoff); err != nil {
+		return 0, fmt.Errorf("error unlinking history: %w", err)
+	}
+	// Feedback is removed by its foreign key
+	result, err := tx.ExecContext(ctx, `DELETE FROM snapshots WHERE id IN (`+prunableSnapshots+`)`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("error pruning snapshots: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error pruning snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error committing snapshot pruning: %w", err)
+	}
+	return removed, nil
+}
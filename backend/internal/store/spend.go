@@ -0,0 +1,37 @@
+// File: backend/internal/store/spend.go
+
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddAISpend adds costMicros, in millionths of a dollar, to provider's spend
+// on day, formatted with QuotaDayLayout
+func (s *Store) AddAISpend(ctx context.Context, provider, day string, costMicros int64) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO ai_spend (provider, day, cost_micros) VALUES (?, ?, ?)
+		ON CONFLICT (provider, day) DO UPDATE SET cost_micros = cost_micros + excluded.cost_micros`,
+		provider, day, costMicros)
+	if err != nil {
+		return fmt.Errorf("error recording AI spend: %w", err)
+	}
+	return nil
+}
+
+// AISpend returns provider's spend on day and in the month containing it, in
+// millionths of a dollar
+func (s *Store) AISpend(ctx context.Context, provider, day string) (daily, monthly int64, err error) {
+	month := day[:len("2006-01")]
+	err = s.db.QueryRowContext(ctx,
+		`SELECT
+			COALESCE(SUM(CASE WHEN day = ? THEN cost_micros END), 0),
+			COALESCE(SUM(cost_micros), 0)
+		FROM ai_spend WHERE provider = ? AND day LIKE ? || '-%'`,
+		day, provider, month).Scan(&daily, &monthly)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading AI spend: %w", err)
+	}
+	return daily, monthly, nil
+}
@@ -0,0 +1,754 @@
+// File: backend/internal/store/store_test.go
+
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// openTestStore opens a fresh database in a temporary directory
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func TestOpenIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// Opening twice must not re-apply migrations
+	for i := 0; i < 2; i++ {
+		s, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open attempt %d failed: %v", i+1, err)
+		}
+		s.Close()
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	response := &models.SearchResponse{
+		Results: []models.SearchResult{
+			{ID: "abc", Title: "First Result", Subreddit: "golang", Score: 42},
+		},
+		TotalCount:    1,
+		Answer:        "An answer [1].",
+		RequestParams: models.RequestParams{Query: "test query"},
+	}
+
+	id, err := s.SaveSnapshot(ctx, response)
+	if err != nil {
+		t.Fatalf("Unexpected error saving snapshot: %v", err)
+	}
+
+	if id == "" || response.ID != id {
+		t.Errorf("Expected response ID to be set to %q, got %q", id, response.ID)
+	}
+
+	loaded, err := s.GetSnapshot(ctx, id)
+	if err != nil {
+		t.Fatalf("Unexpected error loading snapshot: %v", err)
+	}
+
+	if loaded.ID != id {
+		t.Errorf("Expected ID %q, got %q", id, loaded.ID)
+	}
+
+	if loaded.Answer != response.Answer {
+		t.Errorf("Expected answer %q, got %q", response.Answer, loaded.Answer)
+	}
+
+	if len(loaded.Results) != 1 || loaded.Results[0].Score != 42 {
+		t.Errorf("Expected results to round-trip, got %+v", loaded.Results)
+	}
+
+	// Unknown IDs should report ErrNotFound
+	if _, err := s.GetSnapshot(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	save := func(age time.Duration) string {
+		t.Helper()
+		id, err := s.SaveSnapshot(ctx, &models.SearchResponse{Results: []models.SearchResult{{ID: "t3_a"}}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := s.db.Exec(`UPDATE snapshots SET created_at = ? WHERE id = ?`, time.Now().Add(-age).Unix(), id); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return id
+	}
+	expired := save(48 * time.Hour)
+	collected := save(48 * time.Hour)
+	recent := save(time.Hour)
+
+	if err := s.SaveFeedback(ctx, "owner-a", &models.Feedback{SearchID: expired, Rating: models.RatingUp}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.SetAnnotation(ctx, "owner-a", &models.Annotation{SnapshotID: expired, ResultID: "t3_a", Note: "note"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.AddHistoryEntry(ctx, "owner-a", &models.HistoryEntry{Query: "q", SnapshotID: expired}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	collection := &models.Collection{Name: "Kept"}
+	if err := s.CreateCollection(ctx, "owner-a", collection); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.AddCollectionItems(ctx, "owner-a", collection.ID, []models.CollectionSearch{{SnapshotID: collected}}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	removed, err := s.PruneSnapshots(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 snapshot pruned, got %d", removed)
+	}
+	if _, err := s.GetSnapshot(ctx, expired); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the expired snapshot to be pruned, got %v", err)
+	}
+	for _, id := range []string{collected, recent} {
+		if _, err := s.GetSnapshot(ctx, id); err != nil {
+			t.Errorf("Expected snapshot %s to be kept, got %v", id, err)
+		}
+	}
+
+	var feedback int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM feedback`).Scan(&feedback); err != nil || feedback != 0 {
+		t.Errorf("Expected feedback to go with the snapshot, got %d (%v)", feedback, err)
+	}
+	if annotations, _ := s.ListAnnotations(ctx, "owner-a", expired); len(annotations) != 0 {
+		t.Errorf("Expected notes to go with the snapshot, got %+v", annotations)
+	}
+	entries, _, err := s.ListHistory(ctx, "owner-a", 10, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SnapshotID != "" {
+		t.Errorf("Expected the history entry kept without its snapshot, got %+v", entries)
+	}
+}
+
+func TestSavedSearchLifecycle(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	search := &models.SavedSearch{
+		Name:       "Go news",
+		Query:      "golang release",
+		SearchMode: "Posts",
+		ModelName:  "Claude",
+		Limit:      10,
+		Subreddits: []string{"golang", "programming"},
+	}
+
+	if err := s.CreateSavedSearch(ctx, "alice", search); err != nil {
+		t.Fatalf("Unexpected error creating saved search: %v", err)
+	}
+	if search.ID == "" || search.CreatedAt == 0 {
+		t.Fatalf("Expected ID and timestamps to be assigned, got %+v", search)
+	}
+
+	loaded, err := s.GetSavedSearch(ctx, "alice", search.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error loading saved search: %v", err)
+	}
+	if loaded.Query != search.Query || len(loaded.Subreddits) != 2 {
+		t.Errorf("Expected saved search to round-trip, got %+v", loaded)
+	}
+
+	// Other owners can't see or modify it
+	if _, err := s.GetSavedSearch(ctx, "bob", search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for another owner, got %v", err)
+	}
+	if err := s.DeleteSavedSearch(ctx, "bob", search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting as another owner, got %v", err)
+	}
+
+	search.Name = "Go releases"
+	if err := s.UpdateSavedSearch(ctx, "alice", search); err != nil {
+		t.Fatalf("Unexpected error updating saved search: %v", err)
+	}
+
+	list, err := s.ListSavedSearches(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Unexpected error listing saved searches: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "Go releases" {
+		t.Errorf("Expected one renamed saved search, got %+v", list)
+	}
+
+	if err := s.DeleteSavedSearch(ctx, "alice", search.ID); err != nil {
+		t.Fatalf("Unexpected error deleting saved search: %v", err)
+	}
+	if _, err := s.GetSavedSearch(ctx, "alice", search.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestHistoryPagination(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	// Insert five entries with increasing timestamps
+	for i := 0; i < 5; i++ {
+		entry := &models.HistoryEntry{
+			Query:       "query",
+			Params:      models.RequestParams{Query: "query", Limit: i},
+			ResultCount: i,
+			CreatedAt:   int64(1000 + i),
+		}
+		if err := s.AddHistoryEntry(ctx, "alice", entry); err != nil {
+			t.Fatalf("Unexpected error adding history entry: %v", err)
+		}
+	}
+
+	page, total, err := s.ListHistory(ctx, "alice", 2, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error listing history: %v", err)
+	}
+	if total != 5 {
+		t.Errorf("Expected total of 5, got %d", total)
+	}
+	if len(page) != 2 || page[0].ResultCount != 3 || page[1].ResultCount != 2 {
+		t.Errorf("Expected entries 3 and 2 newest first, got %+v", page)
+	}
+
+	// History is private to its owner
+	if _, total, _ := s.ListHistory(ctx, "bob", 10, 0); total != 0 {
+		t.Errorf("Expected no history for another owner, got %d", total)
+	}
+
+	if err := s.DeleteHistoryEntry(ctx, "alice", page[0].ID); err != nil {
+		t.Fatalf("Unexpected error deleting history entry: %v", err)
+	}
+
+	deleted, err := s.ClearHistory(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Unexpected error clearing history: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("Expected 4 remaining entries to be cleared, got %d", deleted)
+	}
+}
+
+func TestPopularSearches(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	add := func(owner, query string, subreddits []string, createdAt int64) {
+		entry := &models.HistoryEntry{
+			Query:     query,
+			Params:    models.RequestParams{Query: query, SearchMode: "All", Limit: 10, Subreddits: subreddits},
+			CreatedAt: createdAt,
+		}
+		if err := s.AddHistoryEntry(ctx, owner, entry); err != nil {
+			t.Fatalf("Unexpected error adding history entry: %v", err)
+		}
+	}
+	add("alice", "rust", nil, now)
+	add("bob", "rust", nil, now)
+	add("alice", "go", []string{"golang"}, now)
+	add("alice", "go", []string{"golang"}, now)
+	add("bob", "go", []string{"golang"}, now)
+	add("alice", "old", nil, now-7200) // Outside the window
+
+	searches, err := s.PopularSearches(ctx, time.Unix(now-3600, 0), 10)
+	if err != nil {
+		t.Fatalf("Unexpected error listing popular searches: %v", err)
+	}
+	if len(searches) != 2 {
+		t.Fatalf("Expected 2 popular searches, got %+v", searches)
+	}
+	if got := searches[0]; got.Query != "go" || got.SearchMode != "All" || got.Limit != 10 ||
+		len(got.Subreddits) != 1 || got.Subreddits[0] != "golang" {
+		t.Errorf("Expected the scoped go search first, got %+v", got)
+	}
+	if searches[1].Query != "rust" || searches[1].Subreddits != nil {
+		t.Errorf("Expected the unscoped rust search second, got %+v", searches[1])
+	}
+}
+
+func TestSuggestQueries(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	add := func(owner, query string, createdAt int64) {
+		entry := &models.HistoryEntry{Query: query, Params: models.RequestParams{Query: query}, CreatedAt: createdAt}
+		if err := s.AddHistoryEntry(ctx, owner, entry); err != nil {
+			t.Fatalf("Unexpected error adding history entry: %v", err)
+		}
+	}
+	add("alice", "best keyboard", now-10)
+	add("bob", "Best Keyboard", now)
+	add("carol", "best keyboard", now-20)
+	add("alice", "best budget monitor", now)
+	add("bob", "best budget monitor", now)
+	add("carol", "best ssn lookup", now) // Only carol ran it
+	add("alice", "best_match", now)
+	add("bob", "bestXmatch", now)
+	add("carol", "bestXmatch", now)
+	add("alice", "best mouse", now-7200) // Outside the window
+	add("bob", "best mouse", now-7200)
+
+	since := time.Unix(now-3600, 0)
+	queries, err := s.SuggestQueries(ctx, "alice", "BEST ", since, 2, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error suggesting queries: %v", err)
+	}
+	if strings.Join(queries, "|") != "Best Keyboard|best budget monitor" {
+		t.Errorf("Expected popular matches as last written, got %q", queries)
+	}
+
+	// Clients see their own searches; LIKE wildcards match literally
+	queries, err = s.SuggestQueries(ctx, "alice", "best_", since, 2, 10)
+	if err != nil {
+		t.Fatalf("Unexpected error suggesting queries: %v", err)
+	}
+	if len(queries) != 1 || queries[0] != "best_match" {
+		t.Errorf("Expected only alice's own literal match, got %q", queries)
+	}
+}
+
+func TestAnalytics(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	hit, miss := true, false
+
+	add := func(query, model string, subreddits []string, elapsed float64, cacheHit *bool, createdAt time.Time) {
+		entry := &models.HistoryEntry{
+			Query:       query,
+			Params:      models.RequestParams{Query: query, ModelName: model, Subreddits: subreddits},
+			ElapsedTime: elapsed,
+			CacheHit:    cacheHit,
+			CreatedAt:   createdAt.Unix(),
+		}
+		if err := s.AddHistoryEntry(ctx, "alice", entry); err != nil {
+			t.Fatalf("Unexpected error adding history entry: %v", err)
+		}
+	}
+	add("Rust", "Claude", []string{"rust"}, 1, &hit, day.Add(time.Hour))
+	add("rust ", "Claude", []string{"Rust", "programming"}, 2, &miss, day.Add(2*time.Hour))
+	add("go", "GPT", nil, 4, nil, day.Add(26*time.Hour))
+	add("old", "GPT", nil, 8, &hit, day.Add(-time.Hour)) // Before the range
+
+	analytics, err := s.Analytics(ctx, day.Add(5*time.Hour), day.Add(48*time.Hour), 1)
+	if err != nil {
+		t.Fatalf("Unexpected error loading analytics: %v", err)
+	}
+	if analytics.Searches != 3 || analytics.Latency.P50 != 2 || analytics.Latency.P99 != 4 {
+		t.Errorf("Expected 3 searches with a median of 2s, got %+v", analytics)
+	}
+	if analytics.CacheHitRatio == nil || *analytics.CacheHitRatio != 0.5 {
+		t.Errorf("Expected a cache hit ratio of 0.5 over tracked searches, got %v", analytics.CacheHitRatio)
+	}
+	if len(analytics.Days) != 2 || analytics.Days[0].Date != "2026-03-10" || analytics.Days[0].Searches != 2 ||
+		analytics.Days[1].Searches != 1 || analytics.Days[1].CacheHitRatio != nil {
+		t.Errorf("Expected two days of 2 and 1 searches, got %+v", analytics.Days)
+	}
+	if len(analytics.Models) != 2 || analytics.Models[0] != (models.UsageCount{Name: "Claude", Count: 2}) {
+		t.Errorf("Expected Claude to lead the model mix, got %+v", analytics.Models)
+	}
+	if len(analytics.TopQueries) != 1 || analytics.TopQueries[0] != (models.UsageCount{Name: "rust", Count: 2}) {
+		t.Errorf("Expected rust as the top query, got %+v", analytics.TopQueries)
+	}
+	if len(analytics.TopSubreddits) != 1 || analytics.TopSubreddits[0] != (models.UsageCount{Name: "rust", Count: 2}) {
+		t.Errorf("Expected r/rust as the top subreddit, got %+v", analytics.TopSubreddits)
+	}
+}
+
+func TestFeedbackRequiresSnapshot(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	missing := &models.Feedback{SearchID: "missing", Rating: models.RatingUp}
+	if err := s.SaveFeedback(ctx, "alice", missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for unknown search, got %v", err)
+	}
+
+	id, err := s.SaveSnapshot(ctx, &models.SearchResponse{RequestParams: models.RequestParams{Query: "q"}})
+	if err != nil {
+		t.Fatalf("Unexpected error saving snapshot: %v", err)
+	}
+
+	feedback := &models.Feedback{SearchID: id, Rating: models.RatingDown, Comment: "Missed the point"}
+	if err := s.SaveFeedback(ctx, "alice", feedback); err != nil {
+		t.Fatalf("Unexpected error saving feedback: %v", err)
+	}
+	if feedback.ID == "" {
+		t.Error("Expected feedback ID to be assigned")
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	for _, usage := range []struct {
+		owner    string
+		day      string
+		requests int64
+		tokens   int64
+	}{
+		{"alice", "2024-02-28", 3, 100},
+		{"alice", "2024-03-01", 1, 0},
+		{"alice", "2024-03-02", 1, 50},
+		{"alice", "2024-03-02", 1, 25},
+		{"bob", "2024-03-02", 7, 700},
+	} {
+		if err := s.AddQuotaUsage(ctx, usage.owner, usage.day, usage.requests, usage.tokens); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	daily, monthly, err := s.QuotaUsage(ctx, "alice", "2024-03-02")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if daily != (models.QuotaUsage{Requests: 2, Tokens: 75}) {
+		t.Errorf("Unexpected daily usage %+v", daily)
+	}
+	if monthly != (models.QuotaUsage{Requests: 3, Tokens: 75}) {
+		t.Errorf("Unexpected monthly usage %+v", monthly)
+	}
+
+	removed, err := s.PruneQuotaUsage(ctx, "2024-03-01")
+	if err != nil || removed != 1 {
+		t.Errorf("Expected 1 record pruned, got %d (%v)", removed, err)
+	}
+}
+
+func TestAISpend(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	for _, spend := range []struct {
+		provider string
+		day      string
+		cost     int64
+	}{
+		{"Anthropic", "2024-02-29", 500},
+		{"Anthropic", "2024-03-01", 100},
+		{"Anthropic", "2024-03-02", 20},
+		{"Anthropic", "2024-03-02", 30},
+		{"OpenAI", "2024-03-02", 700},
+	} {
+		if err := s.AddAISpend(ctx, spend.provider, spend.day, spend.cost); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	daily, monthly, err := s.AISpend(ctx, "Anthropic", "2024-03-02")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if daily != 50 || monthly != 150 {
+		t.Errorf("Expected 50 spent today and 150 this month, got %d and %d", daily, monthly)
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	account := &models.Account{Email: "Alice@example.com"}
+	if err := s.CreateAccount(ctx, account, "hash"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.CreateAccount(ctx, &models.Account{Email: "alice@EXAMPLE.com"}, "hash"); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected ErrExists for the same email in another case, got %v", err)
+	}
+
+	found, hash, err := s.AccountByEmail(ctx, "alice@example.com")
+	if err != nil || found.ID != account.ID || hash != "hash" {
+		t.Errorf("AccountByEmail() = %+v, %q, %v", found, hash, err)
+	}
+	if _, err := s.Account(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRedditLinks(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	account, err := s.CreateRedditAccount(ctx, &models.RedditLink{Username: "Spez", Tokens: "sealed-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.CreateRedditAccount(ctx, &models.RedditLink{Username: "spez", Tokens: "sealed-2"}); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected ErrExists for the same Reddit user in another case, got %v", err)
+	}
+	// Reddit accounts have no email, so they don't collide with each other
+	if _, err := s.CreateRedditAccount(ctx, &models.RedditLink{Username: "kn0thing", Tokens: "sealed-3"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	found, err := s.Account(ctx, account.ID)
+	if err != nil || found.Email != "" || found.RedditUsername != "Spez" {
+		t.Errorf("Account() = %+v, %v", found, err)
+	}
+
+	link, err := s.RedditLinkByUsername(ctx, "SPEZ")
+	if err != nil || link.AccountID != account.ID || link.Tokens != "sealed-1" {
+		t.Errorf("RedditLinkByUsername() = %+v, %v", link, err)
+	}
+	if err := s.UpdateRedditTokens(ctx, account.ID, "sealed-4"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if link, err := s.RedditLink(ctx, account.ID); err != nil || link.Tokens != "sealed-4" {
+		t.Errorf("RedditLink() = %+v, %v", link, err)
+	}
+	if err := s.UpdateRedditTokens(ctx, "missing", "sealed"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBookmarks(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	result := &models.Bookmark{
+		Kind:       models.BookmarkKindResult,
+		SnapshotID: "snap-1",
+		Query:      "best mechanical keyboard",
+		Result:     &models.SearchResult{ID: "t3_a", Title: "Keychron Q1 review", Subreddit: "MechanicalKeyboards"},
+		Tags:       []string{"keyboards", "reviews"},
+	}
+	answer := &models.Bookmark{
+		Kind:       models.BookmarkKindAnswer,
+		SnapshotID: "snap-1",
+		Query:      "best mechanical keyboard",
+		Answer:     "Most recommend tactile switches.",
+		Note:       "for the office",
+		Tags:       []string{"keyboards"},
+	}
+	for _, bookmark := range []*models.Bookmark{result, answer} {
+		if err := s.CreateBookmark(ctx, "owner-a", bookmark); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if err := s.CreateBookmark(ctx, "owner-a", &models.Bookmark{Kind: models.BookmarkKindAnswer, SnapshotID: "snap-1"}); !errors.Is(err, ErrExists) {
+		t.Errorf("Expected ErrExists for the same answer twice, got %v", err)
+	}
+
+	list := func(filter models.BookmarkFilter) []string {
+		t.Helper()
+		bookmarks, total, err := s.ListBookmarks(ctx, "owner-a", filter, 10, 0)
+		if err != nil || total != len(bookmarks) {
+			t.Fatalf("ListBookmarks(%+v) = %d of %d, %v", filter, len(bookmarks), total, err)
+		}
+		ids := make([]string, len(bookmarks))
+		for i, bookmark := range bookmarks {
+			ids[i] = bookmark.ID
+		}
+		return ids
+	}
+	tests := []struct {
+		name   string
+		filter models.BookmarkFilter
+		want   []string
+	}{
+		{"All", models.BookmarkFilter{}, []string{answer.ID, result.ID}},
+		{"Kind", models.BookmarkFilter{Kind: models.BookmarkKindResult}, []string{result.ID}},
+		{"Tags", models.BookmarkFilter{Tags: []string{"keyboards", "reviews"}}, []string{result.ID}},
+		{"Text prefix", models.BookmarkFilter{Text: "keych"}, []string{result.ID}},
+		{"Note", models.BookmarkFilter{Text: "OFFICE"}, []string{answer.ID}},
+		{"Syntax", models.BookmarkFilter{Text: `"tactile (`}, []string{answer.ID}},
+		{"No match", models.BookmarkFilter{Text: "mouse"}, []string{}},
+	}
+	for _, tt := range tests {
+		// Bookmarks made in the same second tie on time, so order is ignored
+		got := list(tt.filter)
+		sort.Strings(got)
+		sort.Strings(tt.want)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	answer.Note = ""
+	answer.Tags = []string{"work"}
+	if err := s.UpdateBookmark(ctx, "owner-a", answer); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := list(models.BookmarkFilter{Text: "office"}); len(got) != 0 {
+		t.Errorf("Expected the old note to be unindexed, got %v", got)
+	}
+	loaded, err := s.GetBookmark(ctx, "owner-a", answer.ID)
+	if err != nil || loaded.Answer != answer.Answer || !reflect.DeepEqual(loaded.Tags, []string{"work"}) {
+		t.Errorf("GetBookmark() = %+v, %v", loaded, err)
+	}
+	if _, err := s.GetBookmark(ctx, "owner-b", answer.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another owner's bookmark to be hidden, got %v", err)
+	}
+
+	tags, err := s.BookmarkTags(ctx, "owner-a")
+	want := []models.TagCount{{Tag: "keyboards", Count: 1}, {Tag: "reviews", Count: 1}, {Tag: "work", Count: 1}}
+	if err != nil || !reflect.DeepEqual(tags, want) {
+		t.Errorf("BookmarkTags() = %v, %v", tags, err)
+	}
+
+	if err := s.DeleteBookmark(ctx, "owner-a", result.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := list(models.BookmarkFilter{Text: "keychron"}); len(got) != 0 {
+		t.Errorf("Expected the deleted bookmark to be unindexed, got %v", got)
+	}
+	if err := s.DeleteBookmark(ctx, "owner-a", result.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	collection := &models.Collection{Name: "Keyboards", Description: "Which one to buy"}
+	if err := s.CreateCollection(ctx, "owner-a", collection); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	searches := []models.CollectionSearch{{SnapshotID: "snap-1", Query: "best mechanical keyboard"}}
+	results := []models.CollectionResult{{
+		SnapshotID: "snap-2",
+		Query:      "quiet switches",
+		Result:     models.SearchResult{ID: "t3_a", Title: "Silent reds"},
+	}}
+	if err := s.AddCollectionItems(ctx, "owner-a", collection.ID, searches, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Adding the same items again leaves them as they are
+	if err := s.AddCollectionItems(ctx, "owner-a", collection.ID, searches, results); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.AddCollectionItems(ctx, "owner-b", collection.ID, searches, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound adding to another owner's collection, got %v", err)
+	}
+
+	got, err := s.GetCollection(ctx, "owner-a", collection.ID)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got.Searches) != 1 || got.Searches[0].Query != "best mechanical keyboard" {
+		t.Errorf("Expected the search once, got %+v", got.Searches)
+	}
+	if len(got.Results) != 1 || got.Results[0].Result.Title != "Silent reds" {
+		t.Errorf("Expected the result once, got %+v", got.Results)
+	}
+	if got.Report != nil {
+		t.Errorf("Expected no report yet, got %+v", got.Report)
+	}
+
+	report := &models.CollectionReport{Prompt: "Which to buy?", Answer: "Silent reds [1]", GeneratedAt: 1700000000}
+	if err := s.SaveCollectionReport(ctx, "owner-a", collection.ID, report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	summaries, err := s.ListCollections(ctx, "owner-a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := models.CollectionSummary{
+		ID:          collection.ID,
+		Name:        "Keyboards",
+		Description: "Which one to buy",
+		Searches:    1,
+		Results:     1,
+		ReportedAt:  1700000000,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   summaries[0].UpdatedAt,
+	}
+	if len(summaries) != 1 || summaries[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, summaries)
+	}
+
+	if err := s.RemoveCollectionItem(ctx, "owner-a", collection.ID, "snap-2", "t3_a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.RemoveCollectionItem(ctx, "owner-a", collection.ID, "snap-2", "t3_a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound removing a missing item, got %v", err)
+	}
+
+	if err := s.DeleteCollection(ctx, "owner-a", collection.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := s.GetCollection(ctx, "owner-a", collection.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+	var items int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM collection_items`).Scan(&items); err != nil || items != 0 {
+		t.Errorf("Expected the items to be deleted with the collection, got %d (%v)", items, err)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	first := &models.Annotation{SnapshotID: "snap-1", ResultID: "t3_a", Note: "Check the follow-up"}
+	if err := s.SetAnnotation(ctx, "owner-a", first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.CreatedAt == 0 || first.UpdatedAt != first.CreatedAt {
+		t.Errorf("Expected matching timestamps on a new annotation, got %+v", first)
+	}
+	createdAt := first.CreatedAt
+
+	// Setting it again replaces the note but keeps the creation time
+	edited := &models.Annotation{SnapshotID: "snap-1", ResultID: "t3_a", Note: "Outdated"}
+	if err := s.SetAnnotation(ctx, "owner-a", edited); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if edited.CreatedAt != createdAt {
+		t.Errorf("Expected creation time %d to be kept, got %d", createdAt, edited.CreatedAt)
+	}
+	if err := s.SetAnnotation(ctx, "owner-b", &models.Annotation{SnapshotID: "snap-1", ResultID: "t3_a", Note: "Mine"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	annotations, err := s.ListAnnotations(ctx, "owner-a", "snap-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(annotations) != 1 || annotations[0].Note != "Outdated" {
+		t.Errorf("Expected only owner-a's edited note, got %+v", annotations)
+	}
+
+	if err := s.DeleteAnnotation(ctx, "owner-a", "snap-1", "t3_a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := s.DeleteAnnotation(ctx, "owner-a", "snap-1", "t3_a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting twice, got %v", err)
+	}
+	if annotations, _ := s.ListAnnotations(ctx, "owner-b", "snap-1"); len(annotations) != 1 {
+		t.Errorf("Expected owner-b's note to remain, got %+v", annotations)
+	}
+}
@@ -0,0 +1,113 @@
+// File: backend/internal/telegram/bot.go
+
+// Package telegram talks to the Telegram Bot API: it decodes webhook
+// updates, sends replies and formats search answers as Telegram messages.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// apiBase is the Bot API endpoint; methods are called at apiBase + token
+const apiBase = "https://api.telegram.org/bot"
+
+// Update is an incoming webhook update. Only the fields the bot uses are
+// decoded.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a message sent to the bot
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+// Chat is the conversation a message belongs to
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // private, group, supergroup or channel
+}
+
+// Bot sends messages through the Bot API
+type Bot struct {
+	token      string
+	httpClient *http.Client
+}
+
+// NewBot creates a client for the bot with the given token
+func NewBot(token string) *Bot {
+	return &Bot{
+		token:      token,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// SendMessage sends an HTML-formatted message to chatID, as a reply to
+// replyTo when it is non-zero
+func (b *Bot) SendMessage(ctx context.Context, chatID int64, html string, replyTo int64) error {
+	params := map[string]interface{}{
+		"chat_id":    chatID,
+		"text":       html,
+		"parse_mode": "HTML",
+		"link_preview_options": map[string]interface{}{
+			"is_disabled": true,
+		},
+	}
+	if replyTo != 0 {
+		params["reply_parameters"] = map[string]interface{}{
+			"message_id":                  replyTo,
+			"allow_sending_without_reply": true,
+		}
+	}
+	return b.call(ctx, "sendMessage", params)
+}
+
+// SendTyping shows the bot as typing in chatID while an answer is prepared
+func (b *Bot) SendTyping(ctx context.Context, chatID int64) error {
+	return b.call(ctx, "sendChatAction", map[string]interface{}{
+		"chat_id": chatID,
+		"action":  "typing",
+	})
+}
+
+// call invokes a Bot API method and checks its result
+func (b *Bot) call(ctx context.Context, method string, params map[string]interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiBase+b.token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		// The URL contains the token, so don't wrap the url.Error
+		return fmt.Errorf("error calling Telegram %s", method)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return fmt.Errorf("error decoding Telegram %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram %s failed (status %d): %s", method, resp.StatusCode, result.Description)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// File: backend/internal/telegram/format.go
+
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// MaxMessageChars is Telegram's limit on the length of a message's text
+const MaxMessageChars = 4096
+
+var (
+	// boldPattern matches **bold** Markdown in answers
+	boldPattern = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	// headingPattern matches Markdown headings, which Telegram can't show
+	headingPattern = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+)
+
+// FormatAnswer renders a search response as a Telegram HTML message: the
+// answer, then its sources in an expandable quote that stays collapsed
+// until tapped. Long answers are cut to fit Telegram's message limit.
+func FormatAnswer(response *models.SearchResponse, maxCitations int) string {
+	answer := strings.TrimSpace(response.Answer)
+	if answer == "" {
+		return "I couldn't find an answer to that on Reddit."
+	}
+
+	citations := formatCitations(response.Citations, maxCitations)
+	for max := min(maxCitations, len(response.Citations)); len([]rune(citations)) > MaxMessageChars/2; {
+		// Leave at least half the message for the answer
+		max--
+		citations = formatCitations(response.Citations, max)
+	}
+	message := formatText(answer) + citations
+	if len([]rune(message)) <= MaxMessageChars {
+		return message
+	}
+
+	// Escaping lengthens the text unevenly, so search for the longest
+	// prefix of the answer that fits. Sizing by the HTML rather than the
+	// text Telegram counts leaves a safe margin.
+	runes := []rune(answer)
+	low, high := 0, len(runes)
+	for low < high {
+		mid := (low + high + 1) / 2
+		if len([]rune(truncated(runes, mid)+citations)) <= MaxMessageChars {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return truncated(runes, low) + citations
+}
+
+// truncated formats the first n runes of an answer, marking the cut
+func truncated(answer []rune, n int) string {
+	return formatText(strings.TrimSpace(string(answer[:n])) + "…")
+}
+
+// formatCitations lists up to max citations as links, or returns "" when
+// there are none
+func formatCitations(citations []models.Citation, max int) string {
+	if len(citations) == 0 || max == 0 {
+		return ""
+	}
+	if len(citations) > max {
+		citations = citations[:max]
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n<b>Sources</b>\n<blockquote expandable>")
+	for i, citation := range citations {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := citation.Title
+		if title == "" {
+			title = citation.URL
+		}
+		if len([]rune(title)) > 80 {
+			title = string([]rune(title)[:80]) + "…"
+		}
+		fmt.Fprintf(&b, "[%d] ", citation.Index)
+		if citation.URL != "" {
+			fmt.Fprintf(&b, `<a href="%s">%s</a>`, html.EscapeString(citation.URL), html.EscapeString(title))
+		} else {
+			b.WriteString(html.EscapeString(title))
+		}
+		if citation.Subreddit != "" {
+			b.WriteString(" · r/" + html.EscapeString(citation.Subreddit))
+		}
+	}
+	b.WriteString("</blockquote>")
+	return b.String()
+}
+
+// formatText escapes Markdown text for Telegram HTML, keeping bold and
+// showing headings in bold
+func formatText(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = headingPattern.ReplaceAllString(escaped, "**$1**")
+	return boldPattern.ReplaceAllString(escaped, "<b>$1</b>")
+}
+
+// Escape escapes plain text for an HTML-formatted message
+func Escape(text string) string {
+	return html.EscapeString(text)
+}
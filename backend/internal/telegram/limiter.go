@@ -0,0 +1,61 @@
+// File: backend/internal/telegram/limiter.go
+
+package telegram
+
+import (
+	"sync"
+	"time"
+)
+
+// ChatLimiter allows each chat a fixed number of messages in any sliding
+// window, so one busy chat can't monopolize searches
+type ChatLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	sent      map[int64][]time.Time // Recent message times by chat, oldest first
+	lastSweep time.Time
+}
+
+// NewChatLimiter allows limit messages per chat in each window
+func NewChatLimiter(limit int, window time.Duration) *ChatLimiter {
+	return &ChatLimiter{
+		limit:  limit,
+		window: window,
+		sent:   make(map[int64][]time.Time),
+	}
+}
+
+// Allow records a message from chatID and reports whether it is within the
+// limit. Rejected messages don't count against the chat.
+func (l *ChatLimiter) Allow(chatID int64) bool {
+	return l.allow(chatID, time.Now())
+}
+
+func (l *ChatLimiter) allow(chatID int64, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cutoff := now.Add(-l.window)
+	if now.Sub(l.lastSweep) > l.window {
+		// Forget chats that have been quiet for a whole window
+		for id, times := range l.sent {
+			if !times[len(times)-1].After(cutoff) {
+				delete(l.sent, id)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	times := l.sent[chatID]
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	if len(times) >= l.limit {
+		l.sent[chatID] = times
+		return false
+	}
+	l.sent[chatID] = append(times, now)
+	return true
+}
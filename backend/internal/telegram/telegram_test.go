@@ -0,0 +1,82 @@
+// File: backend/internal/telegram/telegram_test.go
+
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestChatLimiter(t *testing.T) {
+	limiter := NewChatLimiter(2, time.Minute)
+	start := time.Unix(1700000000, 0)
+
+	if !limiter.allow(1, start) || !limiter.allow(1, start.Add(30*time.Second)) {
+		t.Fatal("messages within the limit were rejected")
+	}
+	if limiter.allow(1, start.Add(31*time.Second)) {
+		t.Error("third message in the window was allowed")
+	}
+	if !limiter.allow(2, start.Add(31*time.Second)) {
+		t.Error("another chat was limited by the first chat's messages")
+	}
+	if !limiter.allow(1, start.Add(time.Minute+time.Second)) {
+		t.Error("message was rejected after the first one left the window")
+	}
+	if limiter.allow(1, start.Add(time.Minute+2*time.Second)) {
+		t.Error("window did not slide: the second message should still count")
+	}
+
+	// Quiet chats are forgotten
+	limiter.allow(3, start.Add(5*time.Minute))
+	if _, ok := limiter.sent[2]; ok {
+		t.Error("idle chat was not swept")
+	}
+}
+
+func TestFormatAnswer(t *testing.T) {
+	response := &models.SearchResponse{
+		Answer: "## Summary\nUse **tabs** <not spaces> [1].",
+		Citations: []models.Citation{
+			{Index: 1, Title: "Tabs vs spaces", URL: "https://reddit.com/r/golang/comments/abc?a=1&b=2", Subreddit: "golang"},
+			{Index: 2, Title: "Second", URL: "https://reddit.com/2"},
+		},
+	}
+
+	message := FormatAnswer(response, 1)
+	for _, want := range []string{
+		"<b>Summary</b>",
+		"Use <b>tabs</b> &lt;not spaces&gt; [1].",
+		"<blockquote expandable>[1] ",
+		`<a href="https://reddit.com/r/golang/comments/abc?a=1&amp;b=2">Tabs vs spaces</a> · r/golang</blockquote>`,
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message missing %q:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "Second") {
+		t.Error("citations beyond the maximum were listed")
+	}
+
+	if message := FormatAnswer(&models.SearchResponse{}, 10); strings.Contains(message, "Sources") {
+		t.Errorf("empty answer listed sources: %s", message)
+	}
+}
+
+func TestFormatAnswerFitsMessageLimit(t *testing.T) {
+	response := &models.SearchResponse{
+		Answer:    strings.Repeat("R&D <b> ", 2000),
+		Citations: []models.Citation{{Index: 1, Title: "Source", URL: "https://reddit.com/1"}},
+	}
+
+	message := FormatAnswer(response, 10)
+	if n := len([]rune(message)); n > MaxMessageChars {
+		t.Errorf("message is %d characters, limit is %d", n, MaxMessageChars)
+	}
+	if !strings.Contains(message, "…") || !strings.HasSuffix(message, "</blockquote>") {
+		t.Errorf("long answer was not cut before the sources: ...%s", message[len(message)-200:])
+	}
+}
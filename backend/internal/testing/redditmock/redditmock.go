@@ -0,0 +1,497 @@
+// File: backend/internal/testing/redditmock/redditmock.go
+
+// Package redditmock is an in-process fake of the Reddit API for integration
+// tests. It serves search, subreddit listings and subreddit autocomplete
+// from fixtures, issues access tokens, and can inject failures such as rate
+// limiting, refusals and slow responses to exercise retry and backoff paths.
+package redditmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is the access token the mock issues
+const Token = "mock-access-token"
+
+// Post is a fixture post. A zero Created means now.
+type Post struct {
+	ID          string
+	Subreddit   string
+	Title       string
+	Body        string
+	Author      string
+	Score       int
+	NumComments int
+	NSFW        bool
+	Created     time.Time
+}
+
+// Comment is a fixture comment on a post. A zero Created means now.
+type Comment struct {
+	ID        string
+	PostID    string
+	PostTitle string
+	Subreddit string
+	Body      string
+	Author    string
+	Score     int
+	Created   time.Time
+}
+
+// Subreddit is a fixture community
+type Subreddit struct {
+	Name        string
+	Description string
+	Subscribers int
+}
+
+// Failure makes matching requests fail or respond slowly
+type Failure struct {
+	// Path is the prefix of the request paths to fail, e.g. "/search.json";
+	// empty fails every request
+	Path string
+	// Status is the error status to respond with, e.g. 429, 403 or 500; zero
+	// responds normally after Delay
+	Status int
+	// Delay is how long to wait before responding
+	Delay time.Duration
+	// Reset is the X-Ratelimit-Reset sent with 429 responses, in seconds
+	Reset int
+	// Times is how many requests fail before the failure is spent; zero
+	// fails every request
+	Times int
+}
+
+// Request is a request the mock received
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	// Authorized is whether the request carried the mock's access token
+	Authorized bool
+}
+
+// Server is a mock Reddit API. Create it with New and close it when done.
+type Server struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	posts      []Post
+	comments   []Comment
+	subreddits []Subreddit
+	responses  map[string]string // Raw fixture bodies by path
+	failures   []*Failure
+	requests   []Request
+}
+
+// New starts a mock Reddit API
+func New() *Server {
+	s := &Server{responses: make(map[string]string)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the server's base URL
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns an HTTP client that sends requests for any host to the
+// mock, so code using Reddit's real URLs can be pointed at it
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.server.URL)
+	return &http.Client{Transport: redirectTransport{target: target, next: s.server.Client().Transport}}
+}
+
+// AddPosts adds posts to search results and subreddit listings
+func (s *Server) AddPosts(posts ...Post) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.posts = append(s.posts, posts...)
+}
+
+// AddComments adds comments to comment search results
+func (s *Server) AddComments(comments ...Comment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.comments = append(s.comments, comments...)
+}
+
+// AddSubreddits adds communities to subreddit search results
+func (s *Server) AddSubreddits(subreddits ...Subreddit) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subreddits = append(s.subreddits, subreddits...)
+}
+
+// SetResponse serves body as JSON for path, e.g. a recorded listing,
+// instead of the fixtures
+func (s *Server) SetResponse(path, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = body
+}
+
+// Fail injects a failure. Failures apply in the order they were added; the
+// first matching one that isn't spent handles a request.
+func (s *Server) Fail(failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure)
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// handle serves a request
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.Query(),
+		Authorized: r.Header.Get("Authorization") == "Bearer "+Token,
+	})
+	failure := s.takeFailure(r.URL.Path)
+	s.mu.Unlock()
+
+	if failure != nil {
+		if failure.Delay > 0 {
+			select {
+			case <-time.After(failure.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if failure.Status != 0 {
+			if failure.Status == http.StatusTooManyRequests {
+				w.Header().Set("X-Ratelimit-Remaining", "0")
+				w.Header().Set("X-Ratelimit-Reset", strconv.Itoa(failure.Reset))
+			}
+			writeJSON(w, failure.Status, map[string]interface{}{"message": http.StatusText(failure.Status), "error": failure.Status})
+			return
+		}
+	}
+
+	if r.URL.Path == "/api/v1/access_token" {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": Token,
+			"token_type":   "bearer",
+			"expires_in":   86400,
+			"scope":        "*",
+		})
+		return
+	}
+
+	s.mu.Lock()
+	body, ok := s.responses[r.URL.Path]
+	s.mu.Unlock()
+	if ok {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		fmt.Fprint(w, body)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 25
+	}
+
+	switch {
+	case r.URL.Path == "/search.json" && query.Get("type") == "comment":
+		writeJSON(w, http.StatusOK, s.searchComments(query.Get("q"), limit))
+	case r.URL.Path == "/search.json":
+		writeJSON(w, http.StatusOK, s.searchPosts(query.Get("q"), query.Get("sort"), limit))
+	case r.URL.Path == "/subreddits/search.json":
+		writeJSON(w, http.StatusOK, s.searchSubreddits(query.Get("q"), limit))
+	case r.URL.Path == "/api/subreddit_autocomplete_v2.json":
+		writeJSON(w, http.StatusOK, s.autocompleteSubreddits(query.Get("query"), limit))
+	case strings.HasPrefix(r.URL.Path, "/r/"):
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/r/"), ".json"), "/")
+		if len(parts) != 2 {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "Not Found", "error": http.StatusNotFound})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.subredditListing(parts[0], parts[1], limit))
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"message": "Not Found", "error": http.StatusNotFound})
+	}
+}
+
+// takeFailure returns the failure for a request to path, if any, spending
+// one of its times. The caller holds s.mu.
+func (s *Server) takeFailure(path string) *Failure {
+	for i, failure := range s.failures {
+		if !strings.HasPrefix(path, failure.Path) {
+			continue
+		}
+		taken := *failure
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+		return &taken
+	}
+	return nil
+}
+
+// searchPosts returns the posts matching q, honoring subreddit: terms
+func (s *Server) searchPosts(q, sortBy string, limit int) map[string]interface{} {
+	terms, subreddits := parseSearch(q)
+
+	s.mu.Lock()
+	var matched []Post
+	for _, post := range s.posts {
+		if inSubreddits(post.Subreddit, subreddits) && matches(post.Title+" "+post.Body, terms) {
+			matched = append(matched, post)
+		}
+	}
+	s.mu.Unlock()
+
+	sortPosts(matched, sortBy)
+	return postListing(matched, limit)
+}
+
+// searchComments returns the comments matching q
+func (s *Server) searchComments(q string, limit int) map[string]interface{} {
+	terms, subreddits := parseSearch(q)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var children []interface{}
+	for _, comment := range s.comments {
+		if len(children) == limit {
+			break
+		}
+		if inSubreddits(comment.Subreddit, subreddits) && matches(comment.PostTitle+" "+comment.Body, terms) {
+			children = append(children, map[string]interface{}{"kind": "t1", "data": map[string]interface{}{
+				"id":          comment.ID,
+				"name":        "t1_" + comment.ID,
+				"body":        comment.Body,
+				"author":      comment.Author,
+				"subreddit":   comment.Subreddit,
+				"score":       comment.Score,
+				"created_utc": createdUTC(comment.Created),
+				"link_id":     "t3_" + comment.PostID,
+				"parent_id":   "t3_" + comment.PostID,
+				"link_title":  comment.PostTitle,
+				"permalink":   fmt.Sprintf("/r/%s/comments/%s/_/%s/", comment.Subreddit, comment.PostID, comment.ID),
+			}})
+		}
+	}
+	return listing(children)
+}
+
+// searchSubreddits returns the communities whose name or description
+// matches q
+func (s *Server) searchSubreddits(q string, limit int) map[string]interface{} {
+	terms, _ := parseSearch(q)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var children []interface{}
+	for _, subreddit := range s.subreddits {
+		if len(children) == limit {
+			break
+		}
+		if matches(subreddit.Name+" "+subreddit.Description, terms) {
+			children = append(children, subredditChild(subreddit))
+		}
+	}
+	return listing(children)
+}
+
+// autocompleteSubreddits returns the communities whose name starts with
+// prefix, like Reddit's typeahead
+func (s *Server) autocompleteSubreddits(prefix string, limit int) map[string]interface{} {
+	prefix = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(prefix), "r/"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var children []interface{}
+	for _, subreddit := range s.subreddits {
+		if len(children) == limit {
+			break
+		}
+		if prefix != "" && strings.HasPrefix(strings.ToLower(subreddit.Name), prefix) {
+			children = append(children, subredditChild(subreddit))
+		}
+	}
+	return listing(children)
+}
+
+// subredditChild returns a community as a listing child
+func subredditChild(subreddit Subreddit) map[string]interface{} {
+	return map[string]interface{}{"kind": "t5", "data": map[string]interface{}{
+		"id":                 strings.ToLower(subreddit.Name),
+		"name":               "t5_" + strings.ToLower(subreddit.Name),
+		"display_name":       subreddit.Name,
+		"title":              subreddit.Name,
+		"public_description": subreddit.Description,
+		"subscribers":        subreddit.Subscribers,
+		"url":                "/r/" + subreddit.Name + "/",
+	}}
+}
+
+// subredditListing returns a subreddit's posts for a listing such as "hot",
+// "new" or "top"
+func (s *Server) subredditListing(subreddit, sortBy string, limit int) map[string]interface{} {
+	s.mu.Lock()
+	var posts []Post
+	for _, post := range s.posts {
+		if strings.EqualFold(subreddit, "all") || strings.EqualFold(subreddit, "popular") || strings.EqualFold(post.Subreddit, subreddit) {
+			posts = append(posts, post)
+		}
+	}
+	s.mu.Unlock()
+
+	sortPosts(posts, sortBy)
+	return postListing(posts, limit)
+}
+
+// postListing renders up to limit posts as a listing
+func postListing(posts []Post, limit int) map[string]interface{} {
+	var children []interface{}
+	for _, post := range posts {
+		if len(children) == limit {
+			break
+		}
+		permalink := fmt.Sprintf("/r/%s/comments/%s/", post.Subreddit, post.ID)
+		children = append(children, map[string]interface{}{"kind": "t3", "data": map[string]interface{}{
+			"id":           post.ID,
+			"name":         "t3_" + post.ID,
+			"title":        post.Title,
+			"selftext":     post.Body,
+			"author":       post.Author,
+			"subreddit":    post.Subreddit,
+			"score":        post.Score,
+			"num_comments": post.NumComments,
+			"upvote_ratio": 0.95,
+			"over_18":      post.NSFW,
+			"created_utc":  createdUTC(post.Created),
+			"is_self":      true,
+			"permalink":    permalink,
+			"url":          "https://www.reddit.com" + permalink,
+		}})
+	}
+	return listing(children)
+}
+
+// listing wraps children in a Reddit listing
+func listing(children []interface{}) map[string]interface{} {
+	if children == nil {
+		children = []interface{}{}
+	}
+	return map[string]interface{}{
+		"kind": "Listing",
+		"data": map[string]interface{}{
+			"children": children,
+			"dist":     len(children),
+			"after":    nil,
+			"before":   nil,
+		},
+	}
+}
+
+// createdUTC returns a fixture's creation time as Reddit reports it
+func createdUTC(created time.Time) float64 {
+	if created.IsZero() {
+		created = time.Now()
+	}
+	return float64(created.Unix())
+}
+
+// sortPosts orders posts like Reddit's sort options: newest first for
+// "new", otherwise highest score first
+func sortPosts(posts []Post, sortBy string) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		if sortBy == "new" {
+			return posts[i].Created.After(posts[j].Created)
+		}
+		return posts[i].Score > posts[j].Score
+	})
+}
+
+// parseSearch splits a search query into lowercase terms and the
+// communities named by subreddit: terms
+func parseSearch(q string) (terms, subreddits []string) {
+	replacer := strings.NewReplacer("(", " ", ")", " ")
+	for _, field := range strings.Fields(strings.ToLower(replacer.Replace(q))) {
+		switch {
+		case strings.HasPrefix(field, "subreddit:"):
+			subreddits = append(subreddits, strings.TrimPrefix(field, "subreddit:"))
+		case field == "or" || field == "and":
+		default:
+			terms = append(terms, field)
+		}
+	}
+	return terms, subreddits
+}
+
+// matches reports whether text contains any of the terms longer than two
+// letters. Reddit matches loosely, so natural-language queries find results.
+func matches(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if len(term) > 2 && strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// inSubreddits reports whether subreddit is one of subreddits, or whether
+// the search isn't restricted
+func inSubreddits(subreddit string, subreddits []string) bool {
+	if len(subreddits) == 0 {
+		return true
+	}
+	for _, name := range subreddits {
+		if strings.EqualFold(name, subreddit) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// redirectTransport sends every request to target
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.next.RoundTrip(req)
+}
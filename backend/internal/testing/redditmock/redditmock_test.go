@@ -0,0 +1,102 @@
+// File: backend/internal/testing/redditmock/redditmock_test.go
+
+package redditmock
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// listingIDs fetches a listing from the mock and returns its children's IDs
+func listingIDs(t *testing.T, client *http.Client, url string) (int, []string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var listing struct {
+		Data struct {
+			Children []struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&listing)
+	var ids []string
+	for _, child := range listing.Data.Children {
+		ids = append(ids, child.Data.ID)
+	}
+	return resp.StatusCode, ids
+}
+
+func TestServerFixtures(t *testing.T) {
+	mock := New()
+	defer mock.Close()
+	mock.AddPosts(
+		Post{ID: "p1", Subreddit: "golang", Title: "Generics in Go", Score: 10},
+		Post{ID: "p2", Subreddit: "rust", Title: "Generics in Rust", Score: 30},
+		Post{ID: "p3", Subreddit: "golang", Title: "Error handling", Score: 20},
+	)
+	mock.AddComments(Comment{ID: "c1", PostID: "p1", PostTitle: "Generics in Go", Subreddit: "golang", Body: "Type parameters help"})
+	mock.AddSubreddits(Subreddit{Name: "golang", Description: "The Go programming language"})
+
+	// Any host reaches the mock
+	client := mock.Client()
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{"https://oauth.reddit.com/search.json?q=generics&sort=relevance", []string{"p2", "p1"}},
+		{"https://www.reddit.com/search.json?q=generics+subreddit:golang", []string{"p1"}},
+		{"https://www.reddit.com/search.json?q=generics&type=comment", []string{"c1"}},
+		{"https://www.reddit.com/subreddits/search.json?q=programming", []string{"golang"}},
+		{"https://oauth.reddit.com/api/subreddit_autocomplete_v2.json?query=GO", []string{"golang"}},
+		{"https://www.reddit.com/r/golang/top.json?limit=1", []string{"p3"}},
+	}
+	for _, tt := range tests {
+		status, ids := listingIDs(t, client, tt.url)
+		if status != http.StatusOK || len(ids) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %d %v", tt.url, tt.want, status, ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.url, tt.want, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestServerFailures(t *testing.T) {
+	mock := New()
+	defer mock.Close()
+	mock.Fail(Failure{Path: "/r/golang", Status: http.StatusTooManyRequests, Reset: 5, Times: 1})
+	mock.Fail(Failure{Path: "/r/", Status: http.StatusForbidden})
+
+	client := mock.Client()
+	resp, err := client.Get("https://oauth.reddit.com/r/golang/hot.json")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("X-Ratelimit-Reset") != "5" {
+		t.Errorf("Expected a 429 with a reset, got %d %v", resp.StatusCode, resp.Header)
+	}
+
+	// The spent 429 gives way to the next matching failure
+	if status, _ := listingIDs(t, client, "https://oauth.reddit.com/r/golang/hot.json"); status != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", status)
+	}
+	if status, _ := listingIDs(t, client, "https://oauth.reddit.com/search.json?q=go"); status != http.StatusOK {
+		t.Errorf("Expected unmatched paths to succeed, got %d", status)
+	}
+	if requests := mock.Requests(); len(requests) != 3 || requests[2].Query.Get("q") != "go" {
+		t.Errorf("Unexpected requests %+v", requests)
+	}
+}
@@ -0,0 +1,169 @@
+// File: backend/internal/topterms/topterms.go
+
+// Package topterms finds the words a set of search results center on, by
+// TF-IDF over the results, so users can see at a glance what a discussion
+// is actually about.
+package topterms
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+	"github.com/pranesh-j/subplexity/internal/utils"
+)
+
+const (
+	// maxTerms caps the terms returned
+	maxTerms = 10
+	// minResults keeps words a single result repeats out of the list
+	minResults = 2
+	// minTermLength drops short words, which are rarely topical
+	minTermLength = 3
+)
+
+// stopwords extend utils.StopWords with words common in Reddit discussions
+// that say nothing about the topic
+var stopwords = toSet(
+	"all", "also", "any", "anyone", "anything", "back", "because", "been", "best", "better",
+	"both", "but", "day", "don't", "doesn't", "didn't", "each", "edit", "even", "ever",
+	"every", "first", "going", "good", "got", "great", "here", "i'm", "i've", "i'd", "i'll",
+	"into", "isn't", "it's", "know", "last", "like", "little", "lot", "made", "make", "many",
+	"more", "most", "much", "need", "never", "new", "now", "off", "one", "only", "other",
+	"our", "out", "over", "people", "pretty", "probably", "really", "right", "same", "say",
+	"see", "since", "some", "something", "still", "such", "sure", "take", "thanks", "that's",
+	"there", "there's", "thing", "things", "think", "though", "time", "too", "two", "under",
+	"use", "used", "using", "very", "want", "way", "well", "what's", "while", "won't",
+	"yeah", "year", "years", "yes", "you're", "amp", "deleted", "removed", "www", "com", "https", "http",
+)
+
+// Extract returns the words the results center on, highest TF-IDF first.
+// Each result is a document: a word's weight sums its sublinear term
+// frequency in each result, scaled by how few results use it, so words
+// running through many results count without drowning out distinctive
+// ones. Words of the query and those only one result uses are left out.
+func Extract(query string, results []models.SearchResult) []models.TopTerm {
+	queryWords := make(map[string]bool)
+	for _, word := range words(query) {
+		queryWords[word] = true
+	}
+
+	type tally struct {
+		frequencies []int // Per result using the word
+		order       int
+	}
+	tallies := make(map[string]*tally)
+	for _, result := range results {
+		// A comment's title is its post's, so it would count the post again
+		text := result.Content
+		if result.Type != "comment" {
+			text = result.Title + "\n" + result.Content
+		}
+
+		counts := make(map[string]int)
+		var seen []string
+		for _, word := range words(text) {
+			if !isTerm(word, queryWords) {
+				continue
+			}
+			if counts[word] == 0 {
+				seen = append(seen, word)
+			}
+			counts[word]++
+		}
+		for _, word := range seen {
+			t, ok := tallies[word]
+			if !ok {
+				t = &tally{order: len(tallies)}
+				tallies[word] = t
+			}
+			t.frequencies = append(t.frequencies, counts[word])
+		}
+	}
+
+	type scored struct {
+		term  models.TopTerm
+		order int
+	}
+	var terms []scored
+	documents := float64(len(results))
+	for word, t := range tallies {
+		if len(t.frequencies) < minResults {
+			continue
+		}
+		// Smoothed so words every result uses still count
+		idf := math.Log((1+documents)/(1+float64(len(t.frequencies)))) + 1
+		weight := 0.0
+		for _, frequency := range t.frequencies {
+			weight += (1 + math.Log(float64(frequency))) * idf
+		}
+		terms = append(terms, scored{
+			term:  models.TopTerm{Term: word, Score: math.Round(weight*100) / 100, Results: len(t.frequencies)},
+			order: t.order,
+		})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].term.Score != terms[j].term.Score {
+			return terms[i].term.Score > terms[j].term.Score
+		}
+		return terms[i].order < terms[j].order
+	})
+
+	if len(terms) > maxTerms {
+		terms = terms[:maxTerms]
+	}
+	top := make([]models.TopTerm, len(terms))
+	for i, t := range terms {
+		top[i] = t.term
+	}
+	return top
+}
+
+// isTerm reports whether word can be a topic term: long enough, not a stop
+// word or number, and not a query word or its plural or singular
+func isTerm(word string, queryWords map[string]bool) bool {
+	if len([]rune(word)) < minTermLength || utils.StopWords[word] || stopwords[word] {
+		return false
+	}
+	if queryWords[word] || queryWords[word+"s"] || queryWords[strings.TrimSuffix(word, "s")] {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// words splits text into lowercase words, keeping hyphens and apostrophes
+// inside words ("wi-fi", "don't") and dropping links and trailing
+// possessives
+func words(text string) []string {
+	var split []string
+	for _, field := range strings.Fields(strings.ToLower(text)) {
+		if strings.Contains(field, "://") || strings.HasPrefix(field, "www.") {
+			continue
+		}
+		field = strings.ReplaceAll(field, "’", "'")
+		for _, word := range strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\''
+		}) {
+			word = strings.TrimSuffix(strings.Trim(word, "-'"), "'s")
+			if word != "" {
+				split = append(split, word)
+			}
+		}
+	}
+	return split
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
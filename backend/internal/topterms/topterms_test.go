@@ -0,0 +1,41 @@
+// File: backend/internal/topterms/topterms_test.go
+
+package topterms
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestExtract(t *testing.T) {
+	results := []models.SearchResult{
+		{Type: "post", Title: "Best mechanical keyboards for coding?",
+			Content: "Thinking about switches. Linear switches or tactile switches? See https://example.com/switches"},
+		{Type: "comment", Title: "Comment on: Best mechanical keyboards for coding?",
+			Content: "Tactile switches all the way. Hot-swap boards let you try both."},
+		{Type: "comment", Content: "I really like my hot-swap board, the keycaps matter too."},
+		{Type: "comment", Content: "Keycaps are overrated; get a good case and tactile switches."},
+		{Type: "comment", Content: "Ergonomics ergonomics ergonomics."},
+	}
+
+	terms := Extract("mechanical keyboard", results)
+	var names []string
+	for _, term := range terms {
+		names = append(names, term.Term)
+	}
+	// Query words and their plurals, stop words, links and words only one
+	// result uses are left out
+	expected := []string{"switches", "tactile", "hot-swap", "keycaps"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+	if terms[0].Results != 3 || terms[0].Score <= terms[1].Score {
+		t.Errorf("Unexpected top term %+v", terms[0])
+	}
+
+	if terms := Extract("anything", nil); len(terms) != 0 {
+		t.Errorf("Expected no terms without results, got %v", terms)
+	}
+}
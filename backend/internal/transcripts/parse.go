@@ -0,0 +1,271 @@
+// File: backend/internal/transcripts/parse.go
+
+package transcripts
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// youtubeIDPattern matches YouTube video IDs
+var youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// redditVideoIDPattern matches v.redd.it video IDs
+var redditVideoIDPattern = regexp.MustCompile(`^[a-z0-9]{5,20}$`)
+
+// vttTagPattern matches WebVTT cue markup such as <c>, <i> and timestamps
+var vttTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// redditVideoID returns the ID of a v.redd.it URL
+// (https://v.redd.it/<id>/DASH_720.mp4), or "" for other URLs
+func redditVideoID(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Hostname(), "v.redd.it") {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if !redditVideoIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// youtubeVideoID returns the ID of a YouTube watch, short, embed or youtu.be
+// URL, or "" for other URLs
+func youtubeVideoID(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	var id string
+	switch host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www."); host {
+	case "youtu.be":
+		id = strings.TrimPrefix(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com":
+		if u.Path == "/watch" {
+			id = u.Query().Get("v")
+		} else if rest, ok := strings.CutPrefix(u.Path, "/shorts/"); ok {
+			id = rest
+		} else if rest, ok := strings.CutPrefix(u.Path, "/embed/"); ok {
+			id = rest
+		} else if rest, ok := strings.CutPrefix(u.Path, "/live/"); ok {
+			id = rest
+		}
+	}
+	id, _, _ = strings.Cut(id, "/")
+
+	if !youtubeIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// captionTrack is a caption track listed on a YouTube watch page
+type captionTrack struct {
+	BaseURL      string `json:"baseUrl"`
+	LanguageCode string `json:"languageCode"`
+	Kind         string `json:"kind"` // "asr" for automatic captions
+}
+
+// parseCaptionTracks extracts the caption tracks from a watch page's
+// embedded player response
+func parseCaptionTracks(page []byte) ([]captionTrack, error) {
+	marker := []byte(`"captionTracks":`)
+	start := bytes.Index(page, marker)
+	if start < 0 {
+		return nil, ErrNoCaptions
+	}
+
+	var tracks []captionTrack
+	decoder := json.NewDecoder(bytes.NewReader(page[start+len(marker):]))
+	if err := decoder.Decode(&tracks); err != nil {
+		return nil, fmt.Errorf("error parsing caption tracks: %w", err)
+	}
+	return tracks, nil
+}
+
+// pickCaptionTrack chooses a track, preferring the language, then
+// human-written captions over automatic ones
+func pickCaptionTrack(tracks []captionTrack, language string) (captionTrack, bool) {
+	best, bestRank := captionTrack{}, -1
+	for _, track := range tracks {
+		if track.BaseURL == "" {
+			continue
+		}
+		rank := 0
+		if languageMatches(track.LanguageCode, language) {
+			rank += 2
+		}
+		if track.Kind != "asr" {
+			rank++
+		}
+		if rank > bestRank {
+			best, bestRank = track, rank
+		}
+	}
+	return best, bestRank >= 0
+}
+
+// dashCaptions is a caption track listed in a DASH manifest
+type dashCaptions struct {
+	URL      string
+	Language string
+}
+
+// dashManifest is the part of a DASH manifest listing caption tracks
+type dashManifest struct {
+	Periods []struct {
+		AdaptationSets []struct {
+			ContentType     string `xml:"contentType,attr"`
+			MimeType        string `xml:"mimeType,attr"`
+			Lang            string `xml:"lang,attr"`
+			Representations []struct {
+				BaseURL string `xml:"BaseURL"`
+			} `xml:"Representation"`
+		} `xml:"AdaptationSet"`
+	} `xml:"Period"`
+}
+
+// pickDASHCaptions finds a WebVTT caption track in a DASH manifest,
+// preferring the language
+func pickDASHCaptions(manifest []byte, language string) (dashCaptions, bool) {
+	var mpd dashManifest
+	if err := xml.Unmarshal(manifest, &mpd); err != nil {
+		return dashCaptions{}, false
+	}
+
+	var best dashCaptions
+	for _, period := range mpd.Periods {
+		for _, set := range period.AdaptationSets {
+			if set.MimeType != "text/vtt" && set.ContentType != "text" {
+				continue
+			}
+			for _, rep := range set.Representations {
+				baseURL := strings.TrimSpace(rep.BaseURL)
+				if baseURL == "" {
+					continue
+				}
+				if languageMatches(set.Lang, language) {
+					return dashCaptions{URL: baseURL, Language: set.Lang}, true
+				}
+				if best.URL == "" {
+					best = dashCaptions{URL: baseURL, Language: set.Lang}
+				}
+			}
+		}
+	}
+	return best, best.URL != ""
+}
+
+// languageMatches reports whether a track's language code ("en", "en-US")
+// is the wanted language
+func languageMatches(code, language string) bool {
+	code = strings.ToLower(code)
+	return code == language || strings.HasPrefix(code, language+"-")
+}
+
+// parseVTT returns the spoken text of a WebVTT file, dropping timings, cue
+// settings, markup and the repeated lines rolling captions produce
+func parseVTT(data []byte) string {
+	var lines []string
+	// The header block (WEBVTT plus metadata such as "Kind: captions") and
+	// NOTE, STYLE and REGION blocks run until the next blank line
+	skipBlock := true
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			skipBlock = false
+			continue
+		case skipBlock, strings.Contains(line, "-->"):
+			continue
+		case strings.HasPrefix(line, "NOTE"), strings.HasPrefix(line, "STYLE"), strings.HasPrefix(line, "REGION"):
+			skipBlock = true
+			continue
+		}
+
+		text := collapse(html.UnescapeString(vttTagPattern.ReplaceAllString(line, "")))
+		if text == "" || isCueID(text) {
+			continue
+		}
+		if len(lines) > 0 && lines[len(lines)-1] == text {
+			continue
+		}
+		lines = append(lines, text)
+	}
+	return strings.Join(lines, " ")
+}
+
+// isCueID reports whether a line is a numeric cue identifier
+func isCueID(line string) bool {
+	for _, r := range line {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTimedText returns the text of a YouTube timed text document, in
+// either the legacy <transcript><text> format or srv3 <timedtext><p>
+func parseTimedText(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "text" || t.Name.Local == "p" {
+				depth++
+			}
+		case xml.EndElement:
+			if (t.Name.Local == "text" || t.Name.Local == "p") && depth > 0 {
+				depth--
+				if depth == 0 {
+					// Captions are often HTML-escaped a second time
+					if text := collapse(html.UnescapeString(current.String())); text != "" {
+						parts = append(parts, text)
+					}
+					current.Reset()
+				}
+			}
+		case xml.CharData:
+			if depth > 0 {
+				current.Write(t)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// collapse trims text and reduces runs of whitespace to single spaces
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to at most maxChars runes, ending at a word boundary
+func truncate(s string, maxChars int) string {
+	if utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+	cut := string([]rune(s)[:maxChars])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "..."
+}
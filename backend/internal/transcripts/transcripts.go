@@ -0,0 +1,278 @@
+// File: backend/internal/transcripts/transcripts.go
+
+// Package transcripts retrieves captions for videos in search results, so
+// video-only posts can contribute to answers. Reddit-hosted videos
+// (v.redd.it) and YouTube videos are supported; retrieval is best-effort and
+// videos without captions are skipped.
+package transcripts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pranesh-j/subplexity/internal/cache"
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+// userAgent identifies the fetcher; YouTube serves caption data to browsers
+const userAgent = "Mozilla/5.0 (compatible; Subplexity/1.0; +https://subplexity.vercel.app)"
+
+// transcriptTTL is how long a transcript, or its absence, is reused
+const transcriptTTL = 6 * time.Hour
+
+// maxResponseBytes bounds every page, manifest and caption file read
+const maxResponseBytes = 4 << 20
+
+// ErrNoCaptions is returned for videos without captions
+var ErrNoCaptions = errors.New("video has no captions")
+
+// Fetcher retrieves and caches transcripts
+type Fetcher struct {
+	cfg    config.TranscriptConfig
+	client *http.Client
+	cache  *cache.Cache[*models.Transcript]
+
+	// Base URLs of the only hosts contacted; replaced in tests
+	youtubeURL     string
+	redditVideoURL string
+}
+
+// New creates a fetcher from configuration
+func New(cfg config.TranscriptConfig) *Fetcher {
+	return &Fetcher{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// Caption URLs are built from known hosts; redirects elsewhere
+			// aren't followed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		cache: cache.NewCache[*models.Transcript](cache.Config{
+			MaxItems:   1000,
+			DefaultTTL: transcriptTTL,
+		}),
+		youtubeURL:     "https://www.youtube.com",
+		redditVideoURL: "https://v.redd.it",
+	}
+}
+
+// video identifies a video whose captions can be looked up
+type video struct {
+	provider string // "reddit" or "youtube"
+	id       string
+}
+
+// key is the video's cache key
+func (v video) key() string {
+	return v.provider + ":" + v.id
+}
+
+// Enrich adds transcripts to the top video posts, looking up to MaxVideos
+// videos concurrently. It returns a copy of results; the input, which may be
+// shared with a cache, is not modified.
+func (f *Fetcher) Enrich(ctx context.Context, results []models.SearchResult) []models.SearchResult {
+	enriched := make([]models.SearchResult, len(results))
+	copy(enriched, results)
+
+	var wg sync.WaitGroup
+	found := 0
+	for i := range enriched {
+		if found >= f.cfg.MaxVideos {
+			break
+		}
+		result := &enriched[i]
+		v, ok := resultVideo(*result)
+		if !ok {
+			continue
+		}
+
+		found++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			transcript, err := f.Fetch(ctx, v)
+			if err != nil {
+				if !errors.Is(err, ErrNoCaptions) {
+					log.Printf("Skipping transcript for %s: %v", v.key(), err)
+				}
+				return
+			}
+			result.Transcript = transcript
+		}()
+	}
+	wg.Wait()
+
+	return enriched
+}
+
+// resultVideo finds a supported video in a post
+func resultVideo(result models.SearchResult) (video, bool) {
+	if result.Type != "post" {
+		return video{}, false
+	}
+	if result.Media != nil && result.Media.Video != nil {
+		if id := redditVideoID(result.Media.Video.URL); id != "" {
+			return video{provider: "reddit", id: id}, true
+		}
+		if id := youtubeVideoID(result.Media.Video.URL); id != "" {
+			return video{provider: "youtube", id: id}, true
+		}
+	}
+	if id := youtubeVideoID(result.ExternalURL); id != "" {
+		return video{provider: "youtube", id: id}, true
+	}
+	return video{}, false
+}
+
+// Fetch returns an excerpt of a video's captions. Results, including videos
+// without captions, are cached.
+func (f *Fetcher) Fetch(ctx context.Context, v video) (*models.Transcript, error) {
+	if transcript, found := f.cache.Get(v.key()); found {
+		if transcript == nil {
+			return nil, ErrNoCaptions
+		}
+		return transcript, nil
+	}
+
+	var transcript *models.Transcript
+	var err error
+	switch v.provider {
+	case "reddit":
+		transcript, err = f.fetchReddit(ctx, v.id)
+	case "youtube":
+		transcript, err = f.fetchYouTube(ctx, v.id)
+	default:
+		return nil, fmt.Errorf("unsupported video provider '%s'", v.provider)
+	}
+
+	// Remember missing captions, but retry transient failures
+	switch {
+	case err == nil:
+		f.cache.Set(v.key(), transcript)
+	case errors.Is(err, ErrNoCaptions):
+		f.cache.Set(v.key(), nil)
+	}
+	return transcript, err
+}
+
+// fetchReddit reads captions listed in a Reddit video's DASH manifest
+func (f *Fetcher) fetchReddit(ctx context.Context, id string) (*models.Transcript, error) {
+	base := f.redditVideoURL + "/" + id + "/"
+	manifest, err := f.get(ctx, base+"DASHPlaylist.mpd")
+	if err != nil {
+		return nil, err
+	}
+
+	track, ok := pickDASHCaptions(manifest, f.cfg.Language)
+	if !ok {
+		return nil, ErrNoCaptions
+	}
+	captionsURL, err := sameHost(base, track.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	vtt, err := f.get(ctx, captionsURL)
+	if err != nil {
+		return nil, err
+	}
+	text := parseVTT(vtt)
+	if text == "" {
+		return nil, ErrNoCaptions
+	}
+	return &models.Transcript{
+		Language: track.Language,
+		Excerpt:  truncate(text, f.cfg.ExcerptChars),
+	}, nil
+}
+
+// fetchYouTube reads captions listed on a YouTube watch page
+func (f *Fetcher) fetchYouTube(ctx context.Context, id string) (*models.Transcript, error) {
+	page, err := f.get(ctx, f.youtubeURL+"/watch?v="+url.QueryEscape(id)+"&hl="+url.QueryEscape(f.cfg.Language))
+	if err != nil {
+		return nil, err
+	}
+
+	tracks, err := parseCaptionTracks(page)
+	if err != nil {
+		return nil, err
+	}
+	track, ok := pickCaptionTrack(tracks, f.cfg.Language)
+	if !ok {
+		return nil, ErrNoCaptions
+	}
+	captionsURL, err := sameHost(f.youtubeURL, track.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	captions, err := f.get(ctx, captionsURL)
+	if err != nil {
+		return nil, err
+	}
+	text := parseTimedText(captions)
+	if text == "" {
+		return nil, ErrNoCaptions
+	}
+	return &models.Transcript{
+		Language:  track.LanguageCode,
+		Generated: track.Kind == "asr",
+		Excerpt:   truncate(text, f.cfg.ExcerptChars),
+	}, nil
+}
+
+// get fetches a URL, treating 403 and 404 as missing captions
+func (f *Fetcher) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Language", f.cfg.Language)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s: %w", req.URL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNoCaptions
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned status %d", req.URL.Redacted(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", req.URL.Redacted(), err)
+	}
+	return body, nil
+}
+
+// sameHost resolves ref against base and requires the result to stay on
+// base's host, so manifests and pages can't point the fetcher elsewhere
+func sameHost(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := baseURL.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid caption URL: %w", err)
+	}
+	if resolved.Scheme != baseURL.Scheme || resolved.Host != baseURL.Host {
+		return "", fmt.Errorf("caption URL on unexpected host '%s'", resolved.Host)
+	}
+	return resolved.String(), nil
+}
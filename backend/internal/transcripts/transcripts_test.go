@@ -0,0 +1,124 @@
+// File: backend/internal/transcripts/transcripts_test.go
+
+package transcripts
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pranesh-j/subplexity/internal/config"
+	"github.com/pranesh-j/subplexity/internal/models"
+)
+
+func TestVideoIDs(t *testing.T) {
+	youtube := map[string]string{
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42":    "dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ?si=abc":                 "dQw4w9WgXcQ",
+		"https://m.youtube.com/shorts/dQw4w9WgXcQ":            "dQw4w9WgXcQ",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ":           "dQw4w9WgXcQ",
+		"https://www.youtube.com/channel/UCabc":               "",
+		"https://youtube.com.example.com/watch?v=dQw4w9WgXcQ": "",
+	}
+	for rawURL, want := range youtube {
+		if got := youtubeVideoID(rawURL); got != want {
+			t.Errorf("youtubeVideoID(%s) = %q, want %q", rawURL, got, want)
+		}
+	}
+
+	if got := redditVideoID("https://v.redd.it/abc123xyz/DASH_720.mp4?source=fallback"); got != "abc123xyz" {
+		t.Errorf("redditVideoID = %q", got)
+	}
+	if got := redditVideoID("https://i.redd.it/abc123xyz.jpg"); got != "" {
+		t.Errorf("redditVideoID of an image = %q", got)
+	}
+}
+
+func TestParseVTT(t *testing.T) {
+	vtt := "WEBVTT\nKind: captions\n\nNOTE\nignored note\n\n1\n00:00:00.000 --> 00:00:02.000 align:start\n<c>Hello</c> &amp; welcome\n\n2\n00:00:02.000 --> 00:00:04.000\nHello &amp; welcome\nto the review\n"
+	if got, want := parseVTT([]byte(vtt)), "Hello & welcome to the review"; got != want {
+		t.Errorf("parseVTT = %q, want %q", got, want)
+	}
+}
+
+func TestParseTimedText(t *testing.T) {
+	legacy := `<?xml version="1.0" encoding="utf-8" ?><transcript><text start="0" dur="1.5">it&amp;#39;s  here</text><text start="1.5" dur="2">the new phone</text></transcript>`
+	if got, want := parseTimedText([]byte(legacy)), "it's here the new phone"; got != want {
+		t.Errorf("legacy format = %q, want %q", got, want)
+	}
+
+	srv3 := `<timedtext format="3"><body><p t="0" d="1500"><s>the</s><s> battery</s></p><p t="1500" d="900">lasts two days</p></body></timedtext>`
+	if got, want := parseTimedText([]byte(srv3)), "the battery lasts two days"; got != want {
+		t.Errorf("srv3 format = %q, want %q", got, want)
+	}
+}
+
+func TestPickCaptionTrack(t *testing.T) {
+	tracks := []captionTrack{
+		{BaseURL: "/de", LanguageCode: "de"},
+		{BaseURL: "/en-asr", LanguageCode: "en", Kind: "asr"},
+		{BaseURL: "/en-us", LanguageCode: "en-US"},
+	}
+	if track, _ := pickCaptionTrack(tracks, "en"); track.BaseURL != "/en-us" {
+		t.Errorf("Expected human-written English captions, got %+v", track)
+	}
+	if track, _ := pickCaptionTrack(tracks[:1], "en"); track.BaseURL != "/de" {
+		t.Errorf("Expected to fall back to another language, got %+v", track)
+	}
+	if _, ok := pickCaptionTrack(nil, "en"); ok {
+		t.Error("Expected no track from an empty list")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/watch", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<script>var ytInitialPlayerResponse = {"captions":{"playerCaptionsTracklistRenderer":{"captionTracks":[{"baseUrl":"/api/timedtext?v=dQw4w9WgXcQ&lang=en","name":{"runs":[{"text":"English"}]},"languageCode":"en","kind":"asr"}]}}};</script>`))
+	})
+	mux.HandleFunc("/api/timedtext", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<transcript><text start="0" dur="1">the camera bump is huge</text></transcript>`))
+	})
+	mux.HandleFunc("/abc123/DASHPlaylist.mpd", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<MPD xmlns="urn:mpeg:dash:schema:mpd:2011"><Period><AdaptationSet contentType="video"><Representation><BaseURL>DASH_720.mp4</BaseURL></Representation></AdaptationSet><AdaptationSet contentType="text" mimeType="text/vtt" lang="en"><Representation><BaseURL>CAPTION_en.vtt</BaseURL></Representation></AdaptationSet></Period></MPD>`))
+	})
+	mux.HandleFunc("/abc123/CAPTION_en.vtt", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("WEBVTT\n\n00:00.000 --> 00:01.000\nunboxing the prototype\n"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := New(config.Default().Transcripts)
+	fetcher.youtubeURL = server.URL
+	fetcher.redditVideoURL = server.URL
+
+	results := []models.SearchResult{
+		{ID: "yt", Type: "post", ExternalURL: "https://youtu.be/dQw4w9WgXcQ"},
+		{ID: "text", Type: "post", Content: "No video here"},
+		{ID: "reddit", Type: "post", Media: &models.Media{Video: &models.MediaVideo{URL: "https://v.redd.it/abc123/DASH_720.mp4", Provider: "reddit"}}},
+		{ID: "missing", Type: "post", Media: &models.Media{Video: &models.MediaVideo{URL: "https://v.redd.it/nocaps/DASH_720.mp4", Provider: "reddit"}}},
+	}
+	enriched := fetcher.Enrich(context.Background(), results)
+
+	if tr := enriched[0].Transcript; tr == nil || tr.Excerpt != "the camera bump is huge" || !tr.Generated || tr.Language != "en" {
+		t.Errorf("Unexpected YouTube transcript: %+v", tr)
+	}
+	if tr := enriched[2].Transcript; tr == nil || tr.Excerpt != "unboxing the prototype" || tr.Generated {
+		t.Errorf("Unexpected Reddit transcript: %+v", tr)
+	}
+	if enriched[1].Transcript != nil || enriched[3].Transcript != nil {
+		t.Error("Expected no transcript for posts without captions")
+	}
+	if results[0].Transcript != nil {
+		t.Error("Enrich modified its input")
+	}
+}
+
+func TestSameHost(t *testing.T) {
+	if got, err := sameHost("https://v.redd.it/abc/", "CAPTION_en.vtt"); err != nil || got != "https://v.redd.it/abc/CAPTION_en.vtt" {
+		t.Errorf("sameHost = %q, %v", got, err)
+	}
+	if _, err := sameHost("https://www.youtube.com", "http://169.254.169.254/latest/meta-data"); err == nil {
+		t.Error("Expected a caption URL on another host to be refused")
+	}
+}
@@ -0,0 +1,77 @@
+// File: backend/internal/utils/comparison.go
+
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxComparisonSides caps the options taken from a query like "A vs B vs C"
+const maxComparisonSides = 4
+
+var (
+	// comparisonBetween matches "difference between X and Y"
+	comparisonBetween = regexp.MustCompile(`(?i)\bdifferences? between (.+?) and (.+)$`)
+	// comparisonSeparator separates the options in "X vs Y", "X compared to
+	// Y" and "X better than Y"
+	comparisonSeparator = regexp.MustCompile(`(?i)\s+(?:vs\.?|versus|compared (?:to|with)|better than)\s+`)
+	// comparisonChoice matches questions asking to choose, whose options may
+	// be separated by a plain "or"
+	comparisonChoice = regexp.MustCompile(`(?i)^(?:(?:which|what)(?: one)?(?:'s| is)? (?:better|best)[,:]?|should i(?: \w+)?|(?:is|are))\s+(.+)$`)
+	// comparisonOr separates the options of a choice
+	comparisonOr = regexp.MustCompile(`(?i)\s+or\s+`)
+	// comparisonQuestion is a trailing "which is better"
+	comparisonQuestion = regexp.MustCompile(`(?i)[\s,:-]+(?:which|what)(?: one)?(?:'s| is)? (?:better|best)$`)
+	// comparisonContext is what follows the last option, as in "X vs Y for
+	// gaming"
+	comparisonContext = regexp.MustCompile(`(?i)\s+(?:for|in|on|when|if)\s+.*$`)
+)
+
+// ComparisonSides returns the options a comparison query weighs against each
+// other, such as ["Python", "Go"] for "Python vs Go for backends", or nil if
+// it doesn't name at least two. A plain "or" only separates options in
+// questions asking to choose ("should I learn Python or Go").
+func ComparisonSides(query string) []string {
+	query = strings.TrimRight(strings.TrimSpace(query), "?!. ")
+	query = comparisonQuestion.ReplaceAllString(query, "")
+
+	var sides []string
+	if match := comparisonBetween.FindStringSubmatch(query); match != nil {
+		sides = []string{match[1], match[2]}
+	} else {
+		choice := false
+		if match := comparisonChoice.FindStringSubmatch(query); match != nil {
+			query, choice = match[1], true
+		}
+		switch {
+		case comparisonSeparator.MatchString(query):
+			sides = comparisonSeparator.Split(query, -1)
+		case choice:
+			sides = comparisonOr.Split(query, -1)
+		}
+	}
+	if len(sides) < 2 {
+		return nil
+	}
+	sides[len(sides)-1] = comparisonContext.ReplaceAllString(sides[len(sides)-1], "")
+
+	var cleaned []string
+	seen := make(map[string]bool)
+	for _, side := range sides {
+		side = strings.Trim(side, " ,;:\"'()")
+		key := strings.ToLower(side)
+		if side == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, side)
+	}
+	if len(cleaned) < 2 {
+		return nil
+	}
+	if len(cleaned) > maxComparisonSides {
+		cleaned = cleaned[:maxComparisonSides]
+	}
+	return cleaned
+}
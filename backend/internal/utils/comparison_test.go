@@ -0,0 +1,34 @@
+// File: backend/internal/utils/comparison_test.go
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComparisonSides(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"Python vs Go", []string{"Python", "Go"}},
+		{"LG C3 vs. Sony A95L for movies?", []string{"LG C3", "Sony A95L"}},
+		{"vim versus emacs versus vscode", []string{"vim", "emacs", "vscode"}},
+		{"difference between TCP and UDP", []string{"TCP", "UDP"}},
+		{"is rust better than c++", []string{"rust", "c++"}},
+		{"Which is better: Kindle or Kobo?", []string{"Kindle", "Kobo"}},
+		{"should I learn Python or Go", []string{"Python", "Go"}},
+		{"react vs vue, which is better", []string{"react", "vue"}},
+		// A plain "or" outside a choice question isn't a comparison
+		{"how to cook rice or pasta", nil},
+		{"best pizza in new york", nil},
+		{"Go vs go", nil},
+	}
+
+	for _, tc := range testCases {
+		if got := ComparisonSides(tc.query); !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("ComparisonSides(%q): expected %v, got %v", tc.query, tc.expected, got)
+		}
+	}
+}
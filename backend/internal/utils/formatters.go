@@ -6,70 +6,153 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
 )
 
-// FormatTimeAgo formats a time as a human-readable "time ago" string
-func FormatTimeAgo(t time.Time) string {
-	now := time.Now()
-	diff := now.Sub(t)
-	
+// FormatTimeAgo formats a time as a human-readable "time ago" string in
+// locale, e.g. "3 days ago" or "vor 3 Tagen". Unsupported locales get
+// English.
+func FormatTimeAgo(t time.Time, locale string) string {
+	words := localeWords(locale)
+
+	diff := time.Since(t)
 	switch {
 	case diff < time.Minute:
-		return "just now"
+		return words.justNow
 	case diff < time.Hour:
-		minutes := int(diff.Minutes())
-		if minutes == 1 {
-			return "1 minute ago"
-		}
-		return fmt.Sprintf("%d minutes ago", minutes)
+		return words.ago(int(diff.Minutes()), unitMinute)
 	case diff < 24*time.Hour:
-		hours := int(diff.Hours())
-		if hours == 1 {
-			return "1 hour ago"
-		}
-		return fmt.Sprintf("%d hours ago", hours)
+		return words.ago(int(diff.Hours()), unitHour)
 	case diff < 48*time.Hour:
-		return "yesterday"
+		return words.yesterday
 	case diff < 7*24*time.Hour:
-		days := int(diff.Hours() / 24)
-		return fmt.Sprintf("%d days ago", days)
+		return words.ago(int(diff.Hours()/24), unitDay)
 	case diff < 30*24*time.Hour:
-		weeks := int(diff.Hours() / 24 / 7)
-		if weeks == 1 {
-			return "1 week ago"
-		}
-		return fmt.Sprintf("%d weeks ago", weeks)
+		return words.ago(int(diff.Hours()/24/7), unitWeek)
 	case diff < 365*24*time.Hour:
-		months := int(diff.Hours() / 24 / 30)
-		if months == 1 {
-			return "1 month ago"
-		}
-		return fmt.Sprintf("%d months ago", months)
+		return words.ago(int(diff.Hours()/24/30), unitMonth)
 	default:
-		years := int(diff.Hours() / 24 / 365)
-		if years == 1 {
-			return "1 year ago"
-		}
-		return fmt.Sprintf("%d years ago", years)
+		return words.ago(int(diff.Hours()/24/365), unitYear)
+	}
+}
+
+// FormatTimeAgoIn is FormatTimeAgo with days counted on the calendar in
+// loc rather than in 24-hour periods, so something posted late last night in
+// the client's timezone is "yesterday", not "9 hours ago"
+func FormatTimeAgoIn(t time.Time, loc *time.Location, locale string) string {
+	return formatTimeAgoAt(t, time.Now(), loc, locale)
+}
+
+func formatTimeAgoAt(t, now time.Time, loc *time.Location, locale string) string {
+	words := localeWords(locale)
+
+	// Within the hour, minutes read better than a day boundary
+	diff := now.Sub(t)
+	switch {
+	case diff < time.Minute:
+		return words.justNow
+	case diff < time.Hour:
+		return words.ago(int(diff.Minutes()), unitMinute)
+	}
+
+	days := calendarDays(t.In(loc), now.In(loc))
+	switch {
+	case days == 0:
+		return words.ago(int(diff.Hours()), unitHour)
+	case days == 1:
+		return words.yesterday
+	case days < 7:
+		return words.ago(days, unitDay)
+	case days < 30:
+		return words.ago(days/7, unitWeek)
+	case days < 365:
+		return words.ago(days/30, unitMonth)
+	default:
+		return words.ago(days/365, unitYear)
 	}
 }
 
-// TruncateWithEllipsis truncates a string to the given length and adds ellipsis if needed
+// calendarDays counts the midnights between from and to, which share a
+// location
+func calendarDays(from, to time.Time) int {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.Date()
+	// Dates in UTC, so DST changes don't make a day 23 or 25 hours long
+	start := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// LocalTimeLayout is how FormatLocalTime writes a time: language-neutral and
+// unambiguous, e.g. "2024-03-09 21:04 PST"
+const LocalTimeLayout = "2006-01-02 15:04 MST"
+
+// FormatLocalTime formats t as a wall-clock time in loc
+func FormatLocalTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(LocalTimeLayout)
+}
+
+// ellipsis marks truncated text
+const ellipsis = "..."
+
+// breakSearchWindow is how many characters TruncateWithEllipsis looks back
+// for a word break
+const breakSearchWindow = 20
+
+// TruncateWithEllipsis truncates text to at most maxLength characters,
+// ellipsis included, breaking before a space or punctuation near the end
+// when there is one. Characters are grapheme clusters, so accented letters,
+// emoji and CJK text are never split.
 func TruncateWithEllipsis(text string, maxLength int) string {
-	if len(text) <= maxLength {
+	if uniseg.GraphemeClusterCount(text) <= maxLength {
 		return text
 	}
-	
+	if maxLength <= len(ellipsis) {
+		return Truncate(text, maxLength)
+	}
+
+	// Byte offset of the start of each kept cluster, and of the cut
+	starts := make([]int, 0, maxLength-len(ellipsis))
+	offset, rest, state := 0, text, -1
+	for len(starts) < maxLength-len(ellipsis) {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		starts = append(starts, offset)
+		offset += len(cluster)
+	}
+	cut := offset
+
 	// Find a good breakpoint to avoid cutting words in the middle
-	breakPoint := maxLength - 3 // Reserve space for ellipsis
-	for i := breakPoint; i > breakPoint-20 && i > 0; i-- {
-		if text[i] == ' ' || text[i] == ',' || text[i] == '.' {
-			breakPoint = i
+	for i := len(starts) - 1; i > 0 && i >= len(starts)-breakSearchWindow; i-- {
+		r, _ := utf8.DecodeRuneInString(text[starts[i]:])
+		if isBreakRune(r) {
+			cut = starts[i]
 			break
 		}
 	}
-	
-	return text[:breakPoint] + "..."
+
+	return strings.TrimRightFunc(text[:cut], unicode.IsSpace) + ellipsis
+}
+
+// Truncate cuts text to at most maxChars characters (grapheme clusters)
+// without adding an ellipsis
+func Truncate(text string, maxChars int) string {
+	offset, rest, state := 0, text, -1
+	for count := 0; count < maxChars && rest != ""; count++ {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		offset += len(cluster)
+	}
+	return text[:offset]
+}
+
+// isBreakRune reports whether text can be cut before r: whitespace and
+// clause punctuation, including the CJK forms
+func isBreakRune(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(",.;:!?、。，；：！？", r)
 }
 
 // SanitizeString removes potentially problematic characters from a string
@@ -0,0 +1,157 @@
+// File: backend/internal/utils/formatters_test.go
+
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatTimeAgo(t *testing.T) {
+	// Test cases
+	now := time.Now()
+	testCases := []struct {
+		name     string
+		time     time.Time
+		locale   string
+		expected string
+	}{
+		{"Just now", now.Add(-30 * time.Second), "en", "just now"},
+		{"Minutes ago", now.Add(-5 * time.Minute), "en", "5 minutes ago"},
+		{"Hours ago", now.Add(-3 * time.Hour), "en", "3 hours ago"},
+		{"Yesterday", now.Add(-30 * time.Hour), "en", "yesterday"},
+		{"Days ago", now.Add(-5 * 24 * time.Hour), "en", "5 days ago"},
+		{"Weeks ago", now.Add(-3 * 7 * 24 * time.Hour), "en", "3 weeks ago"},
+		{"Months ago", now.Add(-2 * 30 * 24 * time.Hour), "en", "2 months ago"},
+		{"Years ago", now.Add(-3 * 365 * 24 * time.Hour), "en", "3 years ago"},
+		{"Singular", now.Add(-90 * time.Minute), "en", "1 hour ago"},
+		{"Spanish", now.Add(-5 * 24 * time.Hour), "es", "hace 5 días"},
+		{"French", now.Add(-3 * time.Hour), "fr", "il y a 3 heures"},
+		{"German", now.Add(-5 * 24 * time.Hour), "de-AT", "vor 5 Tagen"},
+		{"German singular", now.Add(-400 * 24 * time.Hour), "de", "vor 1 Jahr"},
+		{"Portuguese", now.Add(-2 * 30 * 24 * time.Hour), "pt_BR", "há 2 meses"},
+		{"Italian", now.Add(-30 * time.Hour), "it", "ieri"},
+		{"Unsupported locale", now.Add(-5 * time.Minute), "xx", "5 minutes ago"},
+		{"No locale", now.Add(-5 * time.Minute), "", "5 minutes ago"},
+	}
+
+	// Run test cases
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := FormatTimeAgo(tc.time, tc.locale)
+
+			// Some flexibility in the exact wording for time-based tests
+			// to avoid flaky tests when time boundaries are close
+			if !strings.Contains(result, tc.expected) &&
+				!strings.Contains(tc.expected, result) {
+				t.Errorf("Expected time ago containing %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	testCases := map[string]string{
+		"":                          DefaultLocale,
+		"de-DE,de;q=0.9,en;q=0.8":   "de",
+		"ja, fr-CH;q=0.9, es;q=0.5": "fr",
+		"en;q=0.5, pt-BR":           "pt",
+		"es;q=0.8, it;q=0.8":        "es",
+		"fr;q=0, ja":                DefaultLocale,
+		"it;q=bogus, de":            "de",
+	}
+	for header, expected := range testCases {
+		if got := MatchLocale(header); got != expected {
+			t.Errorf("MatchLocale(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
+
+func TestTruncateWithEllipsis(t *testing.T) {
+	testCases := []struct {
+		name      string
+		text      string
+		maxLength int
+		expected  string
+	}{
+		{"Short text unchanged", "héllo wörld", 11, "héllo wörld"},
+		{"Breaks at a space", "the quick brown fox jumps", 15, "the quick..."},
+		{"Counts characters, not bytes", "ééééééééééééé", 8, "ééééé..."},
+		{"Keeps combining marks", "cafécafécafé", 8, "caféc..."},
+		{"Keeps emoji sequences", "👩‍👩‍👧👩‍👩‍👧👩‍👩‍👧👩‍👩‍👧👩‍👩‍👧", 4, "👩‍👩‍👧..."},
+		{"Breaks at CJK punctuation", "東京は日本の首都です。大阪は二番目に大きい都市です", 15, "東京は日本の首都です..."},
+		{"Too short for an ellipsis", "日本語のテキスト", 2, "日本"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := TruncateWithEllipsis(tc.text, tc.maxLength); result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if result := Truncate("🇯🇵🇫🇷🇩🇪", 2); result != "🇯🇵🇫🇷" {
+		t.Errorf("Expected two flags, got %q", result)
+	}
+	if result := Truncate("abc", 10); result != "abc" {
+		t.Errorf("Expected short text unchanged, got %q", result)
+	}
+}
+
+func TestFormatTimeAgoIn(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	now := time.Date(2024, 3, 20, 8, 0, 0, 0, newYork)
+
+	testCases := []struct {
+		name     string
+		time     time.Time
+		locale   string
+		expected string
+	}{
+		{"Just now", now.Add(-30 * time.Second), "en", "just now"},
+		{"Minutes across midnight", time.Date(2024, 3, 20, 7, 15, 0, 0, newYork), "en", "45 minutes ago"},
+		{"Earlier today", time.Date(2024, 3, 20, 1, 0, 0, 0, newYork), "en", "7 hours ago"},
+		// Nine hours ago, but before local midnight
+		{"Late last night", time.Date(2024, 3, 19, 23, 0, 0, 0, newYork), "en", "yesterday"},
+		{"Early yesterday", time.Date(2024, 3, 19, 0, 30, 0, 0, newYork), "de", "gestern"},
+		{"Two days", time.Date(2024, 3, 18, 23, 59, 0, 0, newYork), "en", "2 days ago"},
+		{"Weeks", time.Date(2024, 2, 28, 12, 0, 0, 0, newYork), "es", "hace 3 semanas"},
+		{"Years", time.Date(2022, 3, 20, 12, 0, 0, 0, newYork), "en", "2 years ago"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := formatTimeAgoAt(tc.time, now, newYork, tc.locale); result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+
+	// The same moment is "yesterday" in New York but today in Tokyo
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	posted := time.Date(2024, 3, 19, 23, 0, 0, 0, newYork)
+	if result := formatTimeAgoAt(posted, now, tokyo, "en"); result != "9 hours ago" {
+		t.Errorf("Expected %q in Tokyo, got %q", "9 hours ago", result)
+	}
+}
+
+func TestFormatLocalTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	moment := time.Date(2024, 7, 1, 18, 30, 0, 0, time.UTC)
+	if result := FormatLocalTime(moment, berlin); result != "2024-07-01 20:30 CEST" {
+		t.Errorf("Expected %q, got %q", "2024-07-01 20:30 CEST", result)
+	}
+}
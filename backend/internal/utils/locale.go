@@ -0,0 +1,129 @@
+// File: backend/internal/utils/locale.go
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is used when no supported locale is requested
+const DefaultLocale = "en"
+
+// Units of relative time
+const (
+	unitMinute = iota
+	unitHour
+	unitDay
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+// relativeTime holds a locale's words for FormatTimeAgo
+type relativeTime struct {
+	justNow   string
+	yesterday string
+	// units are the singular and plural forms, indexed by unit
+	units [6][2]string
+	// pattern places the amount, e.g. "%s ago" or "hace %s"
+	pattern string
+}
+
+// ago formats n units in the past
+func (r relativeTime) ago(n, unit int) string {
+	form := r.units[unit][1]
+	if n == 1 {
+		form = r.units[unit][0]
+	}
+	return fmt.Sprintf(r.pattern, fmt.Sprintf("%d %s", n, form))
+}
+
+// relativeTimeLocales are the locales FormatTimeAgo supports
+var relativeTimeLocales = map[string]relativeTime{
+	"en": {
+		justNow: "just now", yesterday: "yesterday", pattern: "%s ago",
+		units: [6][2]string{{"minute", "minutes"}, {"hour", "hours"}, {"day", "days"}, {"week", "weeks"}, {"month", "months"}, {"year", "years"}},
+	},
+	"es": {
+		justNow: "hace un momento", yesterday: "ayer", pattern: "hace %s",
+		units: [6][2]string{{"minuto", "minutos"}, {"hora", "horas"}, {"día", "días"}, {"semana", "semanas"}, {"mes", "meses"}, {"año", "años"}},
+	},
+	"fr": {
+		justNow: "à l'instant", yesterday: "hier", pattern: "il y a %s",
+		units: [6][2]string{{"minute", "minutes"}, {"heure", "heures"}, {"jour", "jours"}, {"semaine", "semaines"}, {"mois", "mois"}, {"an", "ans"}},
+	},
+	"de": {
+		// Dative after "vor"
+		justNow: "gerade eben", yesterday: "gestern", pattern: "vor %s",
+		units: [6][2]string{{"Minute", "Minuten"}, {"Stunde", "Stunden"}, {"Tag", "Tagen"}, {"Woche", "Wochen"}, {"Monat", "Monaten"}, {"Jahr", "Jahren"}},
+	},
+	"pt": {
+		justNow: "agora mesmo", yesterday: "ontem", pattern: "há %s",
+		units: [6][2]string{{"minuto", "minutos"}, {"hora", "horas"}, {"dia", "dias"}, {"semana", "semanas"}, {"mês", "meses"}, {"ano", "anos"}},
+	},
+	"it": {
+		justNow: "poco fa", yesterday: "ieri", pattern: "%s fa",
+		units: [6][2]string{{"minuto", "minuti"}, {"ora", "ore"}, {"giorno", "giorni"}, {"settimana", "settimane"}, {"mese", "mesi"}, {"anno", "anni"}},
+	},
+}
+
+// localeWords returns the relative time words for locale, falling back to
+// DefaultLocale
+func localeWords(locale string) relativeTime {
+	words, ok := relativeTimeLocales[NormalizeLocale(locale)]
+	if !ok {
+		words = relativeTimeLocales[DefaultLocale]
+	}
+	return words
+}
+
+// NormalizeLocale reduces a language tag such as "pt-BR" or "de_AT" to the
+// supported locale for its language, or "" when the language isn't
+// supported
+func NormalizeLocale(tag string) string {
+	language := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	if _, ok := relativeTimeLocales[language]; ok {
+		return language
+	}
+	return ""
+}
+
+// MatchLocale picks the supported locale the client prefers most from an
+// Accept-Language header, e.g. "fr-CH, fr;q=0.9, en;q=0.8", falling back to
+// DefaultLocale
+func MatchLocale(acceptLanguage string) string {
+	type preference struct {
+		locale string
+		q      float64
+	}
+	var preferences []preference
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if locale := NormalizeLocale(tag); locale != "" && q > 0 {
+			preferences = append(preferences, preference{locale, q})
+		}
+	}
+	if len(preferences) == 0 {
+		return DefaultLocale
+	}
+
+	// Stable, so equally weighted languages keep the client's order
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].q > preferences[j].q
+	})
+	return preferences[0].locale
+}
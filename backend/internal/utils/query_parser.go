@@ -58,6 +58,10 @@ type QueryParams struct {
 	HasRankingAspect  bool                 // Queries about "top", "best", etc.
 	QueryCategories   []string             // Detected general categories (e.g., "entertainment", "technology")
 	QuantityRequested int                  // If query requests a specific number of results (e.g., "top 5")
+	RecencyBias       string               // Requested preference for newer content: "none", "low", "high" or "" to follow the query
+	ExcludeSubreddits []string             // Communities whose content is left out
+	ExcludeAuthors    []string             // Users whose content is left out
+	RegionSubreddits  []string             // Communities of the requested region, searched and ranked higher
 }
 
 // Enhanced ParseQuery function to better handle ranking and time-sensitive queries
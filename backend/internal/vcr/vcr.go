@@ -0,0 +1,254 @@
+// File: backend/internal/vcr/vcr.go
+
+// Package vcr records HTTP responses to a cassette file and replays them, so
+// tests can run code against real API responses without the network.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrNotRecorded is returned when replaying a request the cassette doesn't
+// have
+var ErrNotRecorded = errors.New("request not recorded")
+
+// Mode selects whether a Recorder calls the live API or replays a cassette
+type Mode int
+
+const (
+	// Replay answers requests from the cassette, without the network
+	Replay Mode = iota
+	// Record sends requests on and saves their responses to the cassette
+	Record
+)
+
+// recordedHeaders are the response headers kept in cassettes. Others, such
+// as cookies, are left out so cassettes hold nothing sensitive.
+var recordedHeaders = []string{
+	"Content-Type",
+	"X-Ratelimit-Remaining",
+	"X-Ratelimit-Used",
+	"X-Ratelimit-Reset",
+}
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Method string `json:"method"`
+	// URL is the request's path and query. Hosts are ignored, so requests
+	// recorded against one API host replay against another.
+	URL    string            `json:"url"`
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	// JSON bodies are stored as JSON so cassettes stay readable, other
+	// bodies as text
+	JSON json.RawMessage `json:"json,omitempty"`
+	Text string          `json:"text,omitempty"`
+}
+
+// cassette is the file format
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+// Requests are matched by method, path and query; when a request was
+// recorded more than once its responses are replayed in order, repeating
+// the last. Request headers and bodies are never recorded.
+type Recorder struct {
+	path string
+	mode Mode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     map[string]int // Responses served per request key
+	missing      []string
+}
+
+// New creates a recorder for the cassette at path. In Replay mode the
+// cassette is loaded and must exist; in Record mode requests are sent with
+// next, or http.DefaultTransport if nil, and Save writes the cassette.
+func New(path string, mode Mode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		next:     next,
+		replayed: make(map[string]int),
+	}
+	if mode == Record {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("error parsing cassette %s: %w", path, err)
+	}
+	r.interactions = c.Interactions
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == Record {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// record sends req on and keeps its response
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Method: req.Method,
+		URL:    requestURL(req.URL),
+		Status: resp.StatusCode,
+	}
+	for _, name := range recordedHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			if interaction.Header == nil {
+				interaction.Header = make(map[string]string)
+			}
+			interaction.Header[name] = value
+		}
+	}
+	if json.Valid(body) {
+		interaction.JSON = body
+	} else {
+		interaction.Text = string(body)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay answers req from the cassette
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + requestURL(req.URL)
+
+	r.mu.Lock()
+	var matches []Interaction
+	for _, interaction := range r.interactions {
+		if interaction.Method+" "+interaction.URL == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		r.missing = append(r.missing, key)
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+	}
+	served := r.replayed[key]
+	r.replayed[key]++
+	r.mu.Unlock()
+
+	if served >= len(matches) {
+		served = len(matches) - 1
+	}
+	interaction := matches[served]
+
+	body := []byte(interaction.Text)
+	if interaction.JSON != nil {
+		// Cassettes are indented for reading; APIs respond compact
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, interaction.JSON); err != nil {
+			return nil, fmt.Errorf("error replaying %s: %w", key, err)
+		}
+		body = compact.Bytes()
+	}
+	header := make(http.Header, len(interaction.Header))
+	for name, value := range interaction.Header {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+		StatusCode:    interaction.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Missing returns the requests replayed without a recording, as
+// "METHOD /path?query"; a non-empty list means the cassette needs
+// recording again
+func (r *Recorder) Missing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.missing...)
+}
+
+// Save writes the recorded interactions to the cassette, sorted by request
+// so concurrent requests give stable diffs. It does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	interactions := append([]Interaction(nil), r.interactions...)
+	r.mu.Unlock()
+	sort.SliceStable(interactions, func(i, j int) bool {
+		if interactions[i].URL != interactions[j].URL {
+			return interactions[i].URL < interactions[j].URL
+		}
+		return interactions[i].Method < interactions[j].Method
+	})
+
+	// Unescaped so URLs in cassettes read as they were requested
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(cassette{Interactions: interactions}); err != nil {
+		return fmt.Errorf("error encoding cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("error creating cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+	return nil
+}
+
+// requestURL returns the path and query of u, with the query parameters
+// sorted so equivalent requests match
+func requestURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.EscapedPath()
+	}
+	return u.EscapedPath() + "?" + u.Query().Encode()
+}
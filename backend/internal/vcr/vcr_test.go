@@ -0,0 +1,117 @@
+// File: backend/internal/vcr/vcr_test.go
+
+package vcr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Ratelimit-Remaining", "99")
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintf(w, `{"call":%d,"q":%q}`, calls, r.URL.Query().Get("q"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "search.json")
+	recorder, err := New(path, Record, nil)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+	client := &http.Client{Transport: recorder}
+	for _, endpoint := range []string{"/search.json?q=go&limit=5", "/search.json?limit=5&q=go", "/about"} {
+		if _, err := get(client, server.URL+endpoint); err != nil {
+			t.Fatalf("Failed to record %s: %v", endpoint, err)
+		}
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Failed to save cassette: %v", err)
+	}
+
+	// Replay needs no server, and ignores the host and parameter order
+	server.Close()
+	replayer, err := New(path, Replay, nil)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+	client = &http.Client{Transport: replayer}
+	for i, want := range []string{`{"call":1,"q":"go"}`, `{"call":2,"q":"go"}`, `{"call":2,"q":"go"}`} {
+		resp, err := client.Get("https://oauth.example.com/search.json?q=go&limit=5")
+		if err != nil {
+			t.Fatalf("Replay %d failed: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != want {
+			t.Errorf("Replay %d: expected %s, got %s", i, want, body)
+		}
+		if resp.Header.Get("X-Ratelimit-Remaining") != "99" || resp.Header.Get("Set-Cookie") != "" {
+			t.Errorf("Replay %d: unexpected headers %v", i, resp.Header)
+		}
+	}
+
+	if _, err := client.Get("https://oauth.example.com/search.json?q=rust"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded, got %v", err)
+	}
+	if missing := replayer.Missing(); len(missing) != 1 || missing[0] != "GET /search.json?q=rust" {
+		t.Errorf("Unexpected missing requests %v", missing)
+	}
+}
+
+func TestReplayTextBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, "<html>Too Many Requests</html>")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "throttled.json")
+	recorder, _ := New(path, Record, nil)
+	if _, err := get(&http.Client{Transport: recorder}, server.URL+"/r/golang/hot.json"); err != nil {
+		t.Fatalf("Failed to record: %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Failed to save cassette: %v", err)
+	}
+
+	replayer, err := New(path, Replay, nil)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+	resp, err := (&http.Client{Transport: replayer}).Get("https://www.example.com/r/golang/hot.json")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusTooManyRequests || !strings.Contains(string(body), "Too Many Requests") {
+		t.Errorf("Unexpected replay %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), Replay, nil); err == nil {
+		t.Error("Expected an error for a missing cassette")
+	}
+}
+
+func get(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
@@ -0,0 +1,13 @@
+// File: backend/templates/templates.go
+
+// Package templates holds the built-in prompt templates. They are compiled
+// into the binary as defaults; files in the on-disk templates directory
+// override them at runtime.
+package templates
+
+import "embed"
+
+// Prompts contains the default prompt templates under prompts/
+//
+//go:embed prompts/*.tmpl
+var Prompts embed.FS
@@ -73,7 +73,7 @@ export default function AIAnswer({ reasoning, reasoningSteps, answer, citations,
               target="_blank"
               rel="noopener noreferrer"
               className="text-primary hover:underline"
-              title={`${citation.title} (r/${citation.subreddit})`}
+              title={`${citation.title} (r/${citation.subreddit})${citation.archived ? ' - archived' : ''}`}
             >
               {props.children}
             </a>
@@ -4,6 +4,7 @@ export interface SearchRequest {
   searchMode: string;
   modelName: string;
   limit?: number;
+  deferAnswer?: boolean; // Return results first; fetch the answer with fetchAnswer
 }
 
 export interface SearchResult {
@@ -12,12 +13,78 @@ export interface SearchResult {
   subreddit: string;
   author: string;
   content: string;
-  url: string;
+  url: string; // Same as permalink; kept for older responses
+  permalink?: string; // Reddit discussion
   createdUtc: number;
+  editedUtc?: number; // Last edit, if the author edited it
   score: number;
   commentCount?: number;
-  type: string; // "post", "comment", or "subreddit"
+  upvoteRatio?: number; // Share of votes that are upvotes, 0-1; posts only
+  type: string; // "post", "comment", "subreddit" or "wiki"
   highlights?: string[]; // Key excerpts to highlight
+  relevance?: number; // Ranking score, 0-1, relative to the best result
+  media?: Media; // Images and video attached to a post
+  thumbnailUrl?: string; // Small preview image
+  previewUrl?: string; // First full-size still image
+  awards?: number; // Awards received
+  gilded?: number; // Gold awards received
+  distinguished?: boolean; // Made by a moderator or admin in that capacity
+  stickied?: boolean; // Pinned by the moderators
+  archived?: boolean; // Read-only thread
+  locked?: boolean; // Thread accepts no new comments
+  flair?: string; // Post flair
+  authorFlair?: string; // Author's flair in the subreddit
+  externalUrl?: string; // Article, image or video a link post points to
+  linkPreview?: LinkPreview; // Present when link fetching is enabled
+  transcript?: Transcript; // Video captions, when transcripts are enabled
+  parentId?: string; // Comments: parent fullname, t3_ for the post or t1_ for a comment
+  linkId?: string; // Comments: post fullname
+  depth?: number; // Comments: reply depth
+  threadContext?: ThreadComment[]; // Comments a heavily cited reply responds to
+  megathread?: boolean; // Top comment from a megathread about breaking news
+}
+
+export interface ThreadComment {
+  id: string;
+  author: string;
+  body: string;
+  score: number;
+  url: string;
+}
+
+export interface Transcript {
+  language?: string;
+  generated?: boolean; // Automatic captions
+  excerpt: string;
+}
+
+export interface LinkPreview {
+  title?: string;
+  siteName?: string;
+  summary: string;
+}
+
+export interface MediaImage {
+  url: string;
+  width?: number;
+  height?: number;
+  caption?: string;
+  animated?: boolean; // GIF; url may point at an MP4 rendition
+}
+
+export interface MediaVideo {
+  url: string;
+  provider?: string; // "reddit", "YouTube", ...
+  width?: number;
+  height?: number;
+  duration?: number; // Seconds
+  hlsUrl?: string;
+}
+
+export interface Media {
+  thumbnail?: string;
+  images?: MediaImage[]; // Preview image, or every gallery item in order
+  video?: MediaVideo;
 }
 
 export interface Citation {
@@ -27,6 +94,7 @@ export interface Citation {
   title: string;
   type: string;
   subreddit: string;
+  archived?: boolean; // The cited thread is read-only
 }
 
 export interface ReasoningStep {
@@ -51,10 +119,53 @@ export interface SearchResponse {
   elapsedTime: number;
   lastUpdated?: number; // New timestamp field
   requestParams?: RequestParams; // New field for request metadata
+  answerError?: ErrorResponse; // Results were found but the AI answer failed
+  pendingAnswerId?: string; // The answer is still being generated; see fetchAnswer
 }
 
+// Body of every API error response
+export interface ErrorResponse {
+  error: string; // Message for people
+  code: string; // e.g. "reddit_rate_limited", "ai_unavailable", "invalid_request"
+  details?: string;
+  retryable: boolean; // The same request may succeed later
+  requestId?: string; // Quote this when reporting problems
+  fields?: { field: string; message: string }[]; // Invalid fields, for invalid_request
+}
+
+export class ApiError extends Error {
+  readonly status: number;
+  readonly code: string;
+  readonly retryable: boolean;
+  readonly requestId?: string;
+  readonly fields?: { field: string; message: string }[];
+  readonly retryAfter?: number; // Seconds, from the Retry-After header
+
+  constructor(status: number, body: Partial<ErrorResponse>, fallback: string, retryAfter?: number) {
+    super(body.error || fallback);
+    this.name = 'ApiError';
+    this.status = status;
+    this.code = body.code || 'internal';
+    this.retryable = body.retryable ?? false;
+    this.requestId = body.requestId;
+    this.fields = body.fields;
+    this.retryAfter = retryAfter;
+  }
+}
+
+const apiError = async (response: Response, fallback: string): Promise<ApiError> => {
+  let body: Partial<ErrorResponse> = {};
+  try {
+    body = await response.json();
+  } catch {
+    // Not a JSON error body, e.g. from a proxy
+  }
+  const retryAfter = Number(response.headers.get('Retry-After')) || undefined;
+  return new ApiError(response.status, body, fallback, retryAfter);
+};
+
 export const searchReddit = async (request: SearchRequest): Promise<SearchResponse> => {
-  const response = await fetch('http://localhost:8080/api/search', {
+  const response = await fetch('http://localhost:8080/api/v1/search', {
     method: 'POST',
     headers: {
       'Content-Type': 'application/json',
@@ -63,9 +174,55 @@ export const searchReddit = async (request: SearchRequest): Promise<SearchRespon
   });
 
   if (!response.ok) {
-    const errorData = await response.json();
-    throw new Error(errorData.error || 'Failed to search Reddit');
+    throw await apiError(response, 'Failed to search Reddit');
+  }
+
+  return response.json();
+};
+
+// Waits for the answer to a search made with deferAnswer and returns the full
+// response
+export const fetchAnswer = async (pendingAnswerId: string): Promise<SearchResponse> => {
+  const response = await fetch(`http://localhost:8080/api/v1/search/answers/${encodeURIComponent(pendingAnswerId)}`);
+
+  if (!response.ok) {
+    throw await apiError(response, 'Failed to load the answer');
+  }
+
+  return response.json();
+};
+
+export interface ModelInfo {
+  name: string;
+  displayName: string;
+  provider: string;
+  modelId: string;
+  mode: "live" | "mock";
+  default: boolean;
+  maxTokens: number;
+  tokenLimit: number;
+  jsonMode: boolean;
+  vision: boolean; // Can use images from image posts
+  health: {
+    status: "unknown" | "ok" | "degraded" | "down";
+    lastSuccess?: number;
+    lastFailure?: number;
+    lastError?: string;
+    consecutiveFailures: number;
+  };
+}
+
+export interface ModelCatalog {
+  models: ModelInfo[];
+  defaultModel: string;
+}
+
+export const fetchModels = async (): Promise<ModelCatalog> => {
+  const response = await fetch('http://localhost:8080/api/v1/models');
+
+  if (!response.ok) {
+    throw await apiError(response, 'Failed to load models');
   }
 
   return response.json();
-};
\ No newline at end of file
+};
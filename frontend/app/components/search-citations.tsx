@@ -61,6 +61,17 @@ export default function SearchCitations({ citations }: SearchCitationsProps) {
                     {getTypeIcon(citation.type)}
                     <span>{citation.type}</span>
                   </span>
+                  {citation.archived && (
+                    <>
+                      <span className="text-neutral-300 dark:text-neutral-600">•</span>
+                      <span
+                        className="text-xs text-neutral-500 dark:text-neutral-400"
+                        title="This thread is archived and can no longer be commented on or voted on"
+                      >
+                        Archived
+                      </span>
+                    </>
+                  )}
                 </div>
                 
                 {citation.text && (
@@ -2,15 +2,43 @@
 "use client"
 
 import { useState, useEffect } from "react"
-import { Globe, Code, Image, Video } from "lucide-react"
+import { Globe, Code, Image, Video, BookOpen, ExternalLink } from "lucide-react"
 import { Button } from "./ui/button"
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "./ui/select"
 import { TextareaAutosize } from "./ui/textarea-autosize"
-import { searchReddit, SearchResponse } from "./api-client"
+import { ApiError, fetchAnswer, fetchModels, searchReddit, ModelInfo, SearchResponse } from "./api-client"
 import { ResearchProgress, ResearchStep } from "./research-progress"
 import AIAnswer from "./ai-answer"
 import SearchCitations from "./search-citations"
 
+// Message for a failed search, based on the API's error code
+const searchErrorMessage = (err: unknown): string => {
+  if (!(err instanceof ApiError)) {
+    return "Failed to complete search. Please try again."
+  }
+  switch (err.code) {
+    case "invalid_request":
+      return err.fields?.map(f => f.message).join("; ") || err.message
+    case "reddit_rate_limited":
+      return "Reddit is rate limiting searches right now. Please try again in a minute."
+    case "reddit_unavailable":
+      return "Reddit isn't responding right now. Please try again later."
+    case "ai_rate_limited":
+    case "ai_unavailable":
+      return "The AI provider is unavailable right now. Please try again later."
+    case "too_many_requests":
+      return "You already have searches running. Please wait for them to finish."
+    case "quota_exceeded":
+      return "You've used up your search quota. Please try again after it resets."
+    case "overloaded":
+      return "The server is busy. Please try again in a few seconds."
+    case "timeout":
+      return "The search took too long. Please try again."
+    default:
+      return "Failed to complete search. Please try again."
+  }
+}
+
 const searchModes = [
   { icon: Globe, label: "All" },
   { icon: Code, label: "Posts" },
@@ -18,10 +46,15 @@ const searchModes = [
   { icon: Video, label: "Communities" },
 ]
 
+// Posts with a lower upvote ratio get a "Controversial" badge, matching the
+// backend's ranking threshold
+const CONTROVERSIAL_UPVOTE_RATIO = 0.6
+
 export function SearchInterface() {
   const [query, setQuery] = useState("")
   const [searchMode, setSearchMode] = useState("All")
   const [modelName, setModelName] = useState("DeepSeek R1")
+  const [models, setModels] = useState<ModelInfo[]>([])
   const [isSearching, setIsSearching] = useState(false)
   const [searchResults, setSearchResults] = useState<SearchResponse | null>(null)
   const [error, setError] = useState<string | null>(null)
@@ -36,6 +69,18 @@ export function SearchInterface() {
   const [currentStep, setCurrentStep] = useState(0)
   const [streamedAnswer, setStreamedAnswer] = useState("")
 
+  // Load the model picker from the backend catalog
+  useEffect(() => {
+    fetchModels()
+      .then((catalog) => {
+        setModels(catalog.models)
+        if (!catalog.models.some((model) => model.name === modelName)) {
+          setModelName(catalog.defaultModel)
+        }
+      })
+      .catch((err) => console.error("Failed to load models:", err))
+  }, [])
+
   const handleSearch = async () => {
     if (!query.trim()) {
       setError("Please enter a search query")
@@ -70,11 +115,12 @@ export function SearchInterface() {
         setCurrentStep(1)
       }, 1500)
       
-      // Make the actual search request
-      const results = await searchReddit({
+      // Make the actual search request, getting the results before the answer
+      const sources = await searchReddit({
         query,
         searchMode,
         modelName,
+        deferAnswer: true,
       })
       
       // Update to show analyzing results (step 2)
@@ -86,7 +132,9 @@ export function SearchInterface() {
       })
       setCurrentStep(2)
       
-      // Set search results
+      // Show the sources while the answer is generated
+      setSearchResults(sources)
+      const results = sources.pendingAnswerId ? await fetchAnswer(sources.pendingAnswerId) : sources
       setSearchResults(results)
       
       // Begin "streaming" the answer with a delay
@@ -126,7 +174,7 @@ export function SearchInterface() {
       
     } catch (err) {
       console.error("Search error:", err)
-      setError("Failed to complete search. Please try again.")
+      setError(searchErrorMessage(err))
       setIsSearching(false)
       
       // Reset research steps on error
@@ -186,9 +234,20 @@ export function SearchInterface() {
                 <SelectValue placeholder="Select Model" />
               </SelectTrigger>
               <SelectContent>
-                <SelectItem value="Google Gemini">Gemini 2.0 Flash</SelectItem>
-                <SelectItem value="Claude">Claude</SelectItem>
-                <SelectItem value="DeepSeek R1">DeepSeek R1</SelectItem>
+                {models.length > 0 ? (
+                  models.map((model) => (
+                    <SelectItem
+                      key={model.name}
+                      value={model.name}
+                      disabled={model.health.status === "down"}
+                    >
+                      {model.displayName}
+                      {model.mode === "mock" ? " (demo)" : ""}
+                    </SelectItem>
+                  ))
+                ) : (
+                  <SelectItem value={modelName}>{modelName}</SelectItem>
+                )}
               </SelectContent>
             </Select>
           </div>
@@ -246,30 +305,96 @@ export function SearchInterface() {
                       {result.type === "post" && <Code className="h-4 w-4" />}
                       {result.type === "comment" && <Image className="h-4 w-4" />}
                       {result.type === "subreddit" && <Video className="h-4 w-4" />}
+                      {result.type === "wiki" && <BookOpen className="h-4 w-4" />}
                     </div>
                     <div className="flex-1">
                       <h3 className="text-lg font-medium">
                         <a 
-                          href={result.url} 
+                          href={result.permalink ?? result.url} 
                           target="_blank" 
                           rel="noopener noreferrer" 
                           className="hover:text-[#FF4500] transition-colors"
                         >
                           {result.title}
                         </a>
+                        {result.flair && (
+                          <span className="ml-2 align-middle rounded-full border border-zinc-700 px-2 text-xs text-zinc-400">
+                            {result.flair}
+                          </span>
+                        )}
                       </h3>
+                      {result.type === "comment" && result.linkId && (
+                        <a
+                          href={`https://www.reddit.com/comments/${result.linkId.replace(/^t3_/, "")}/_/${result.id}?context=3`}
+                          target="_blank"
+                          rel="noopener noreferrer"
+                          className="mt-1 mr-3 inline-flex items-center gap-1 text-xs text-zinc-400 hover:text-[#FF4500]"
+                        >
+                          View in thread
+                        </a>
+                      )}
+                      {result.externalUrl && (
+                        <a
+                          href={result.externalUrl}
+                          target="_blank"
+                          rel="noopener noreferrer"
+                          className="mt-1 inline-flex items-center gap-1 text-xs text-zinc-400 hover:text-[#FF4500]"
+                        >
+                          {new URL(result.externalUrl).hostname.replace(/^www\./, "")}
+                          <ExternalLink className="h-3 w-3" />
+                        </a>
+                      )}
                       <div className="flex items-center gap-2 text-sm text-zinc-400 mt-1">
                         <span>r/{result.subreddit}</span>
                         <span>•</span>
                         <span>u/{result.author}</span>
+                        {result.authorFlair && (
+                          <span className="rounded bg-zinc-800 px-1.5 text-xs text-zinc-300">{result.authorFlair}</span>
+                        )}
                         <span>•</span>
-                        <span>{new Date(result.createdUtc * 1000).toLocaleDateString()}</span>
+                        <span>
+                          {new Date(result.createdUtc * 1000).toLocaleDateString()}
+                          {result.editedUtc && (
+                            <span title={`Edited ${new Date(result.editedUtc * 1000).toLocaleString()}`}> (edited)</span>
+                          )}
+                        </span>
+                        {result.distinguished && (
+                          <span className="rounded bg-green-900/40 px-1.5 text-xs text-green-400">MOD</span>
+                        )}
+                        {result.stickied && (
+                          <span className="rounded bg-zinc-800 px-1.5 text-xs text-zinc-300">Pinned</span>
+                        )}
+                        {(result.archived || result.locked) && (
+                          <span className="rounded bg-zinc-800 px-1.5 text-xs text-zinc-300">
+                            {result.archived ? "Archived" : "Locked"}
+                          </span>
+                        )}
                       </div>
                       {result.content && (
                         <p className="mt-3 text-zinc-300 line-clamp-3">
                           {result.content}
                         </p>
                       )}
+                      {(result.thumbnailUrl || result.previewUrl) && (
+                        <div className="mt-3 flex items-center gap-2">
+                          <img
+                            src={result.thumbnailUrl || result.previewUrl}
+                            alt=""
+                            loading="lazy"
+                            className="h-20 w-32 rounded object-cover"
+                          />
+                          {(result.media?.images?.length ?? 0) > 1 && (
+                            <span className="text-xs text-zinc-400">
+                              +{(result.media?.images?.length ?? 0) - 1} more
+                            </span>
+                          )}
+                          {result.media?.video && (
+                            <span className="text-xs text-zinc-400">
+                              {result.media.video.provider === "reddit" ? "Video" : result.media.video.provider}
+                            </span>
+                          )}
+                        </div>
+                      )}
                       <div className="flex items-center gap-4 mt-3 text-sm">
                         <span className="text-zinc-400">
                           {result.score} points
@@ -279,6 +404,32 @@ export function SearchInterface() {
                             {result.commentCount} comments
                           </span>
                         )}
+                        {result.relevance !== undefined && (
+                          <span
+                            className="flex items-center gap-1 text-zinc-400"
+                            title={`Relevance ${Math.round(result.relevance * 100)}%`}
+                          >
+                            <span className="h-1.5 w-12 overflow-hidden rounded bg-zinc-800">
+                              <span
+                                className="block h-full bg-[#FF4500]"
+                                style={{ width: `${Math.round(result.relevance * 100)}%` }}
+                              />
+                            </span>
+                          </span>
+                        )}
+                        {result.upvoteRatio !== undefined && result.upvoteRatio < CONTROVERSIAL_UPVOTE_RATIO && (
+                          <span
+                            className="text-amber-400"
+                            title={`${Math.round(result.upvoteRatio * 100)}% upvoted`}
+                          >
+                            Controversial
+                          </span>
+                        )}
+                        {Math.max(result.awards ?? 0, result.gilded ?? 0) > 0 && (
+                          <span className="text-zinc-400">
+                            {Math.max(result.awards ?? 0, result.gilded ?? 0)} awards
+                          </span>
+                        )}
                       </div>
                     </div>
                   </div>